@@ -13,6 +13,9 @@ class Constants {
 
 
         const val KEY_HEARTBEAT_ID = "KEY_HEARTBEAT_ID"
+        const val KEY_RESYNC_ID = "KEY_RESYNC_ID"
+        const val KEY_LOGS_REQUEST_ID = "KEY_LOGS_REQUEST_ID"
+        const val KEY_PHONE_ID = "KEY_PHONE_ID"
 
         const val SIM1 = "SIM1"
         const val SIM2 = "SIM2"
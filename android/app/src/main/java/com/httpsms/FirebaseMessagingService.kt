@@ -8,6 +8,7 @@ import com.google.firebase.messaging.FirebaseMessagingService
 import com.google.firebase.messaging.RemoteMessage
 import com.httpsms.SentReceiver.FailedMessageWorker
 import timber.log.Timber
+import java.io.IOException
 
 class MyFirebaseMessagingService : FirebaseMessagingService() {
     // [START receive_message]
@@ -21,6 +22,18 @@ class MyFirebaseMessagingService : FirebaseMessagingService() {
             return
         }
 
+        if (remoteMessage.data.containsKey(Constants.KEY_RESYNC_ID)) {
+            Timber.w("received resync message with ID [${remoteMessage.data[Constants.KEY_RESYNC_ID]}]")
+            sendHeartbeat()
+            return
+        }
+
+        if (remoteMessage.data.containsKey(Constants.KEY_LOGS_REQUEST_ID)) {
+            Timber.w("received logs request message with ID [${remoteMessage.data[Constants.KEY_LOGS_REQUEST_ID]}]")
+            uploadLogs(remoteMessage.data[Constants.KEY_PHONE_ID])
+            return
+        }
+
         val messageID = remoteMessage.data[Constants.KEY_MESSAGE_ID]
         if (messageID == null)  {
             Timber.e("cannot get message id from notification data with key [${Constants.KEY_MESSAGE_ID}]")
@@ -62,8 +75,20 @@ class MyFirebaseMessagingService : FirebaseMessagingService() {
                     phoneNumbers.add(Settings.getSIM2PhoneNumber(applicationContext))
                 }
 
-                HttpSmsApiService.create(applicationContext).storeHeartbeat(phoneNumbers.toTypedArray(), Settings.isCharging(applicationContext))
+                HttpSmsApiService.create(applicationContext).storeHeartbeat(
+                    phoneNumbers.toTypedArray(),
+                    Settings.isCharging(applicationContext),
+                    Settings.isDozeMode(applicationContext),
+                    Settings.bootTimestamp(),
+                    Settings.getHeartbeatFailedTimestamp(applicationContext),
+                    Settings.signalStrength(applicationContext),
+                    Settings.signalBars(applicationContext),
+                    Settings.networkType(applicationContext)
+                )
                 Settings.setHeartbeatTimestampAsync(applicationContext, System.currentTimeMillis())
+            } catch (exception: IOException) {
+                Settings.setHeartbeatFailedTimestampAsync(applicationContext, System.currentTimeMillis())
+                Timber.e(exception)
             } catch (exception: Exception) {
                 Timber.e(exception)
             }
@@ -71,6 +96,25 @@ class MyFirebaseMessagingService : FirebaseMessagingService() {
         }.start()
     }
 
+    private fun uploadLogs(phoneID: String?) {
+        if (phoneID == null || !Settings.isLoggedIn(applicationContext)) {
+            Timber.w("cannot upload logs for phone [$phoneID] when the user is not logged in")
+            return
+        }
+        Thread {
+            try {
+                val process = Runtime.getRuntime().exec(arrayOf("logcat", "-d", "-v", "time", "--pid=${android.os.Process.myPid()}"))
+                val logs = process.inputStream.readBytes()
+                // keep the most recent logs so the file fits in the upload limit of the server
+                val maxSize = 3 * 1024 * 1024 - 1024
+                HttpSmsApiService.create(applicationContext).uploadLogs(phoneID, logs.copyOfRange(maxOf(0, logs.size - maxSize), logs.size))
+            } catch (exception: Exception) {
+                Timber.e(exception)
+            }
+            Timber.d("finished uploading logs")
+        }.start()
+    }
+
     private fun scheduleJob(messageID: String) {
         // [START dispatch_job]
         val constraints = Constraints.Builder()
@@ -142,6 +186,13 @@ class MyFirebaseMessagingService : FirebaseMessagingService() {
                 return Result.failure()
             }
 
+            if (message.channel != null && message.channel != "sms") {
+                // only SMS is relayed by the app, so messages of other channels are failed instead of being sent as an SMS
+                Timber.w("[${message.sim}] channel [${message.channel}] of message [${message.id}] is not supported")
+                handleFailed(applicationContext, messageID, "The [${message.channel}] channel is not supported by the mobile app")
+                return Result.failure()
+            }
+
             if (message.encrypted && Settings.getEncryptionKey(applicationContext).isNullOrEmpty()) {
                 Timber.w("[${message.sim}] message is encrypted but the encryption key is empty")
                 handleFailed(applicationContext, messageID, "Outgoing message is encrypted but mobile app has no encryption key")
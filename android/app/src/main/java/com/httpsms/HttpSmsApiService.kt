@@ -2,6 +2,7 @@ package com.httpsms
 
 import android.content.Context
 import okhttp3.MediaType.Companion.toMediaType
+import okhttp3.MultipartBody
 import okhttp3.OkHttpClient
 import okhttp3.Request
 import okhttp3.RequestBody.Companion.toRequestBody
@@ -129,10 +130,16 @@ class HttpSmsApiService(private val apiKey: String, private val baseURL: URI) {
         return true
     }
 
-    fun storeHeartbeat(phoneNumbers: Array<String>, charging: Boolean) {
+    fun storeHeartbeat(phoneNumbers: Array<String>, charging: Boolean, dozeMode: Boolean, bootedAt: String, networkFailedAt: String?, signalStrength: Int?, signalBars: Int?, networkType: String?): Long? {
         val body = """
             {
               "charging": $charging,
+              "doze_mode": $dozeMode,
+              "booted_at": "$bootedAt",
+              "network_failed_at": ${if (networkFailedAt == null) "null" else "\"$networkFailedAt\""},
+              "signal_strength": $signalStrength,
+              "signal_bars": $signalBars,
+              "network_type": ${if (networkType == null) "null" else "\"$networkType\""},
               "phone_numbers": ${phoneNumbers.joinToString(prefix = "[", postfix = "]") { "\"$it\"" }}
             }
         """.trimIndent()
@@ -148,11 +155,13 @@ class HttpSmsApiService(private val apiKey: String, private val baseURL: URI) {
         if (!response.isSuccessful) {
             Timber.e("error response [${response.body?.string()}] with code [${response.code}] while sending heartbeat [$body] for phone numbers [${phoneNumbers.joinToString()}]")
             response.close()
-            return
+            return null
         }
 
+        val interval = ResponseHeartbeats.fromJson(response.body!!.string())?.data?.firstNotNullOfOrNull { it }?.intervalSeconds
         response.close()
-        Timber.i( "heartbeat stored successfully for phone numbers [${phoneNumbers.joinToString()}]" )
+        Timber.i( "heartbeat stored successfully for phone numbers [${phoneNumbers.joinToString()}] with interval [$interval] seconds" )
+        return interval
     }
 
 
@@ -226,6 +235,31 @@ class HttpSmsApiService(private val apiKey: String, private val baseURL: URI) {
     }
 
 
+    fun uploadLogs(phoneID: String, logs: ByteArray): Boolean {
+        val body = MultipartBody.Builder()
+            .setType(MultipartBody.FORM)
+            .addFormDataPart("file", "httpsms.log", logs.toRequestBody("text/plain".toMediaType()))
+            .build()
+
+        val request: Request = Request.Builder()
+            .url(resolveURL("/v1/phones/$phoneID/logs"))
+            .post(body)
+            .header(apiKeyHeader, apiKey)
+            .header(clientVersionHeader, BuildConfig.VERSION_NAME)
+            .build()
+
+        val response = client.newCall(request).execute()
+        if (!response.isSuccessful) {
+            Timber.e("error response [${response.body?.string()}] with code [${response.code}] while uploading [${logs.size}] bytes of logs for phone [$phoneID]")
+            response.close()
+            return false
+        }
+
+        response.close()
+        Timber.i("[${logs.size}] bytes of logs uploaded successfully for phone [$phoneID]")
+        return true
+    }
+
     fun validateApiKey(): Pair<String?, String?> {
         val request: Request = Request.Builder()
             .url(resolveURL("/v1/users/me"))
@@ -18,12 +18,8 @@ import android.widget.Toast
 import androidx.activity.result.contract.ActivityResultContracts
 import androidx.appcompat.app.AppCompatActivity
 import androidx.lifecycle.MutableLiveData
-import androidx.work.Constraints
 import androidx.work.ExistingPeriodicWorkPolicy
 import androidx.work.ListenableWorker.Result
-import androidx.work.NetworkType
-import androidx.work.PeriodicWorkRequestBuilder
-import androidx.work.WorkManager
 import com.google.android.material.button.MaterialButton
 import com.google.android.material.card.MaterialCardView
 import com.google.android.material.progressindicator.LinearProgressIndicator
@@ -37,7 +33,6 @@ import java.time.ZoneOffset
 import java.time.ZonedDateTime
 import java.time.format.DateTimeFormatter
 import java.util.*
-import java.util.concurrent.TimeUnit
 import android.provider.Settings as ProviderSettings
 
 
@@ -138,23 +133,7 @@ class MainActivity : AppCompatActivity() {
     }
 
     private fun scheduleHeartbeatWorker(context: Context) {
-        val tag = "TAG_HEARTBEAT_WORKER"
-
-        val constraints = Constraints.Builder()
-            .setRequiredNetworkType(NetworkType.CONNECTED)
-            .build()
-
-        val heartbeatWorker =
-            PeriodicWorkRequestBuilder<HeartbeatWorker>(15, TimeUnit.MINUTES)
-                .setConstraints(constraints)
-                .addTag(tag)
-                .build()
-
-        WorkManager
-            .getInstance(context)
-            .enqueueUniquePeriodicWork(tag, ExistingPeriodicWorkPolicy.KEEP, heartbeatWorker)
-
-        Timber.d("finished scheduling heartbeat worker with ID [${heartbeatWorker.id}]")
+        HeartbeatWorker.schedule(context, ExistingPeriodicWorkPolicy.KEEP)
     }
 
     private fun startStickyNotification(context: Context) {
@@ -337,7 +316,16 @@ class MainActivity : AppCompatActivity() {
                     phoneNumbers.add(Settings.getSIM2PhoneNumber(applicationContext))
                 }
                 Timber.w("numbers = [${phoneNumbers.joinToString()}]")
-                HttpSmsApiService.create(context).storeHeartbeat(phoneNumbers.toTypedArray(), charging)
+                HttpSmsApiService.create(context).storeHeartbeat(
+                    phoneNumbers.toTypedArray(),
+                    charging,
+                    Settings.isDozeMode(applicationContext),
+                    Settings.bootTimestamp(),
+                    Settings.getHeartbeatFailedTimestamp(applicationContext),
+                    Settings.signalStrength(applicationContext),
+                    Settings.signalBars(applicationContext),
+                    Settings.networkType(applicationContext)
+                )
                 Settings.setHeartbeatTimestampAsync(applicationContext, System.currentTimeMillis())
             } catch (exception: Exception) {
                 Timber.e(exception)
@@ -22,6 +22,23 @@ data class ResponsePhone (
     }
 }
 
+data class ResponseHeartbeats (
+    val data: List<Heartbeat?>,
+    val message: String,
+    val status: String,
+) {
+    companion object {
+        fun fromJson(json: String) = Klaxon().parse<ResponseHeartbeats>(json)
+    }
+}
+
+data class Heartbeat (
+    val id: String,
+
+    @Json(name = "interval_seconds")
+    val intervalSeconds: Long = 0,
+)
+
 data class Phone (
     val id: String,
 
@@ -34,6 +51,9 @@ data class Message (
     val content: String,
     val sim: String,
 
+    // channel is null for messages which are fetched from a server without channels
+    val channel: String?,
+
     @Json(name = "created_at")
     val createdAt: String,
 
@@ -1,10 +1,16 @@
 package com.httpsms
 
 import android.content.Context
+import android.net.ConnectivityManager
+import android.net.NetworkCapabilities
 import android.os.BatteryManager
+import android.os.PowerManager
+import android.os.SystemClock
+import android.telephony.TelephonyManager
 import androidx.preference.PreferenceManager
 import timber.log.Timber
 import java.net.URI
+import java.time.Instant
 import java.time.ZoneOffset
 import java.time.ZonedDateTime
 import java.time.format.DateTimeFormatter
@@ -25,6 +31,8 @@ object Settings {
     private const val SETTINGS_USER_ID = "SETTINGS_USER_ID"
     private const val SETTINGS_FCM_TOKEN_UPDATE_TIMESTAMP = "SETTINGS_FCM_TOKEN_UPDATE_TIMESTAMP"
     private const val SETTINGS_HEARTBEAT_TIMESTAMP = "SETTINGS_HEARTBEAT_TIMESTAMP"
+    private const val SETTINGS_HEARTBEAT_FAILED_TIMESTAMP = "SETTINGS_HEARTBEAT_FAILED_TIMESTAMP"
+    private const val SETTINGS_HEARTBEAT_INTERVAL_SECONDS = "SETTINGS_HEARTBEAT_INTERVAL_SECONDS"
     private const val SETTINGS_ENCRYPTION_KEY = "SETTINGS_ENCRYPTION_KEY"
     private const val SETTINGS_ENCRYPT_RECEIVED_MESSAGES = "SETTINGS_ENCRYPT_RECEIVED_MESSAGES"
 
@@ -281,6 +289,52 @@ object Settings {
         return myBatteryManager.isCharging
     }
 
+    fun isDozeMode(context: Context): Boolean {
+        val powerManager = context.getSystemService(Context.POWER_SERVICE) as PowerManager
+        return powerManager.isDeviceIdleMode
+    }
+
+    fun signalStrength(context: Context): Int? {
+        val telephonyManager = context.getSystemService(Context.TELEPHONY_SERVICE) as TelephonyManager
+        return telephonyManager.signalStrength?.cellSignalStrengths?.firstOrNull()?.dbm?.takeIf { it in -150..0 }
+    }
+
+    fun signalBars(context: Context): Int? {
+        val telephonyManager = context.getSystemService(Context.TELEPHONY_SERVICE) as TelephonyManager
+        return telephonyManager.signalStrength?.level
+    }
+
+    fun networkType(context: Context): String? {
+        val connectivityManager = context.getSystemService(Context.CONNECTIVITY_SERVICE) as ConnectivityManager
+        val capabilities = connectivityManager.getNetworkCapabilities(connectivityManager.activeNetwork)
+        if (capabilities?.hasTransport(NetworkCapabilities.TRANSPORT_WIFI) == true) {
+            return "wifi"
+        }
+
+        val telephonyManager = context.getSystemService(Context.TELEPHONY_SERVICE) as TelephonyManager
+        val networkType = try {
+            telephonyManager.dataNetworkType
+        } catch (exception: SecurityException) {
+            Timber.w(exception, "cannot read the data network type")
+            return null
+        }
+
+        return when (networkType) {
+            TelephonyManager.NETWORK_TYPE_GPRS, TelephonyManager.NETWORK_TYPE_EDGE, TelephonyManager.NETWORK_TYPE_CDMA,
+            TelephonyManager.NETWORK_TYPE_1xRTT, TelephonyManager.NETWORK_TYPE_GSM -> "2g"
+            TelephonyManager.NETWORK_TYPE_UMTS, TelephonyManager.NETWORK_TYPE_EVDO_0, TelephonyManager.NETWORK_TYPE_EVDO_A,
+            TelephonyManager.NETWORK_TYPE_EVDO_B, TelephonyManager.NETWORK_TYPE_HSDPA, TelephonyManager.NETWORK_TYPE_HSUPA,
+            TelephonyManager.NETWORK_TYPE_HSPA, TelephonyManager.NETWORK_TYPE_HSPAP, TelephonyManager.NETWORK_TYPE_TD_SCDMA -> "3g"
+            TelephonyManager.NETWORK_TYPE_LTE, TelephonyManager.NETWORK_TYPE_IWLAN -> "4g"
+            TelephonyManager.NETWORK_TYPE_NR -> "5g"
+            else -> null
+        }
+    }
+
+    fun bootTimestamp(): String {
+        return formatTimestamp(System.currentTimeMillis() - SystemClock.elapsedRealtime())
+    }
+
     fun setUserID(context:Context, userID: String?) {
         Timber.d(Settings::setUserID.name)
         PreferenceManager.getDefaultSharedPreferences(context)
@@ -375,6 +429,61 @@ object Settings {
         PreferenceManager.getDefaultSharedPreferences(context)
             .edit()
             .putLong(this.SETTINGS_HEARTBEAT_TIMESTAMP, timestamp)
+            .remove(this.SETTINGS_HEARTBEAT_FAILED_TIMESTAMP)
             .apply()
     }
+
+    fun getHeartbeatIntervalSeconds(context: Context): Long {
+        Timber.d(Settings::getHeartbeatIntervalSeconds.name)
+
+        val interval = PreferenceManager
+            .getDefaultSharedPreferences(context)
+            .getLong(this.SETTINGS_HEARTBEAT_INTERVAL_SECONDS, 15 * 60)
+
+        Timber.d("SETTINGS_HEARTBEAT_INTERVAL_SECONDS: [$interval]")
+        return interval
+    }
+
+    fun setHeartbeatIntervalSecondsAsync(context: Context, interval: Long) {
+        Timber.d(Settings::setHeartbeatIntervalSecondsAsync.name)
+
+        PreferenceManager.getDefaultSharedPreferences(context)
+            .edit()
+            .putLong(this.SETTINGS_HEARTBEAT_INTERVAL_SECONDS, interval)
+            .apply()
+    }
+
+    fun getHeartbeatFailedTimestamp(context: Context): String? {
+        Timber.d(Settings::getHeartbeatFailedTimestamp.name)
+
+        val timestamp = PreferenceManager
+            .getDefaultSharedPreferences(context)
+            .getLong(this.SETTINGS_HEARTBEAT_FAILED_TIMESTAMP,0)
+
+        Timber.d("SETTINGS_HEARTBEAT_FAILED_TIMESTAMP: [$timestamp]")
+        if (timestamp == 0L) {
+            return null
+        }
+        return formatTimestamp(timestamp)
+    }
+
+    fun setHeartbeatFailedTimestampAsync(context: Context, timestamp: Long) {
+        Timber.d(Settings::setHeartbeatFailedTimestampAsync.name)
+
+        val preferences = PreferenceManager.getDefaultSharedPreferences(context)
+        if (preferences.getLong(this.SETTINGS_HEARTBEAT_FAILED_TIMESTAMP, 0) != 0L) {
+            Timber.d("the first failed heartbeat timestamp is already set")
+            return
+        }
+
+        preferences.edit()
+            .putLong(this.SETTINGS_HEARTBEAT_FAILED_TIMESTAMP, timestamp)
+            .apply()
+    }
+
+    private fun formatTimestamp(timestamp: Long): String {
+        return DateTimeFormatter.ofPattern(Constants.TIMESTAMP_PATTERN).format(
+            ZonedDateTime.ofInstant(Instant.ofEpochMilli(timestamp), ZoneOffset.UTC)
+        ).replace("+", "Z")
+    }
 }
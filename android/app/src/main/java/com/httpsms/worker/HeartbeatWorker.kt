@@ -1,12 +1,19 @@
 package com.httpsms.worker
 
 import android.content.Context
+import androidx.work.Constraints
+import androidx.work.ExistingPeriodicWorkPolicy
+import androidx.work.NetworkType
+import androidx.work.PeriodicWorkRequestBuilder
+import androidx.work.WorkManager
 import androidx.work.Worker
 import androidx.work.WorkerParameters
 import com.httpsms.Constants
 import com.httpsms.HttpSmsApiService
 import com.httpsms.Settings
 import timber.log.Timber
+import java.io.IOException
+import java.util.concurrent.TimeUnit
 
 class HeartbeatWorker(appContext: Context, workerParams: WorkerParameters) : Worker(appContext, workerParams) {
     override fun doWork(): Result {
@@ -29,12 +36,54 @@ class HeartbeatWorker(appContext: Context, workerParams: WorkerParameters) : Wor
             return Result.success()
         }
 
-        HttpSmsApiService.create(applicationContext).storeHeartbeat(phoneNumbers.toTypedArray(), Settings.isCharging(applicationContext))
+        val interval = try {
+            HttpSmsApiService.create(applicationContext).storeHeartbeat(
+                phoneNumbers.toTypedArray(),
+                Settings.isCharging(applicationContext),
+                Settings.isDozeMode(applicationContext),
+                Settings.bootTimestamp(),
+                Settings.getHeartbeatFailedTimestamp(applicationContext),
+                Settings.signalStrength(applicationContext),
+                Settings.signalBars(applicationContext),
+                Settings.networkType(applicationContext)
+            )
+        } catch (exception: IOException) {
+            Settings.setHeartbeatFailedTimestampAsync(applicationContext, System.currentTimeMillis())
+            throw exception
+        }
         Timber.d("finished sending heartbeats to server")
 
         Settings.setHeartbeatTimestampAsync(applicationContext, System.currentTimeMillis())
         Timber.d("Set the heartbeat timestamp")
 
+        if (interval != null && interval > 0 && interval != Settings.getHeartbeatIntervalSeconds(applicationContext)) {
+            Timber.i("heartbeat interval changed to [$interval] seconds, rescheduling heartbeat worker")
+            Settings.setHeartbeatIntervalSecondsAsync(applicationContext, interval)
+            schedule(applicationContext, ExistingPeriodicWorkPolicy.UPDATE, interval)
+        }
+
         return Result.success()
     }
+
+    companion object {
+        private const val TAG = "TAG_HEARTBEAT_WORKER"
+
+        fun schedule(context: Context, policy: ExistingPeriodicWorkPolicy, interval: Long = Settings.getHeartbeatIntervalSeconds(context)) {
+            val constraints = Constraints.Builder()
+                .setRequiredNetworkType(NetworkType.CONNECTED)
+                .build()
+
+            val heartbeatWorker =
+                PeriodicWorkRequestBuilder<HeartbeatWorker>(interval, TimeUnit.SECONDS)
+                    .setConstraints(constraints)
+                    .addTag(TAG)
+                    .build()
+
+            WorkManager
+                .getInstance(context)
+                .enqueueUniquePeriodicWork(TAG, policy, heartbeatWorker)
+
+            Timber.d("finished scheduling heartbeat worker with ID [${heartbeatWorker.id}] every [$interval] seconds")
+        }
+    }
 }
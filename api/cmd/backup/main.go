@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/NdoleStudio/httpsms/pkg/di"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/joho/godotenv"
+)
+
+// This command runs backups outside the API server e.g. from a cron job and restores archives which were created by the
+// scheduled backups. Usage:
+//
+//	go run ./cmd/backup create [-user <user-id>]
+//	go run ./cmd/backup list
+//	go run ./cmd/backup restore -name <archive-name>
+func main() {
+	if _, err := os.Stat(".env"); err == nil {
+		if err = godotenv.Load(".env"); err != nil {
+			log.Fatal("Error loading .env file")
+		}
+	}
+
+	if len(os.Args) < 2 {
+		log.Fatal("usage: backup [create|list|restore] [flags]")
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	userID := flags.String("user", "", "ID of the user to backup, the whole database is backed up when it is empty")
+	name := flags.String("name", "", "name of the archive to restore")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+
+	container := di.NewLiteContainer()
+	service := container.BackupService()
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "create":
+		var user *entities.UserID
+		if *userID != "" {
+			id := entities.UserID(*userID)
+			user = &id
+		}
+
+		backup, err := service.Backup(ctx, user)
+		if err != nil {
+			container.Logger().Fatal(err)
+		}
+
+		if err = service.Rotate(ctx); err != nil {
+			container.Logger().Fatal(err)
+		}
+		fmt.Println(backup.Name)
+	case "list":
+		backups, err := service.List(ctx)
+		if err != nil {
+			container.Logger().Fatal(err)
+		}
+
+		for _, backup := range backups {
+			fmt.Printf("%s\t%d\t%s\n", backup.Name, backup.Size, backup.CreatedAt)
+		}
+	case "restore":
+		if *name == "" {
+			log.Fatal("the -name flag is required to restore a backup")
+		}
+
+		count, err := service.Restore(ctx, *name)
+		if err != nil {
+			container.Logger().Fatal(err)
+		}
+		fmt.Printf("restored %d records from %s\n", count, *name)
+	default:
+		log.Fatalf("unknown command [%s]", os.Args[1])
+	}
+}
@@ -45,3 +45,12 @@ func (cache *memoryCache) Set(ctx context.Context, key string, value string, ttl
 	cache.store.Set(key, value, ttl)
 	return nil
 }
+
+// Delete an item from the memory cache
+func (cache *memoryCache) Delete(ctx context.Context, key string) error {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	cache.store.Delete(key)
+	return nil
+}
@@ -51,3 +51,14 @@ func (cache *redisCache) Set(ctx context.Context, key string, value string, ttl
 	}
 	return nil
 }
+
+// Delete an item from the redis cache
+func (cache *redisCache) Delete(ctx context.Context, key string) error {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	if err := cache.client.Del(ctx, key).Err(); err != nil {
+		return cache.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete item in redis with key [%s]", key)))
+	}
+	return nil
+}
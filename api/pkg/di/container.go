@@ -5,8 +5,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/docs"
@@ -37,7 +39,10 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/emails"
 
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
 	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
+	"cloud.google.com/go/storage"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -54,8 +59,10 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
 	"github.com/NdoleStudio/httpsms/pkg/listeners"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
@@ -75,13 +82,25 @@ import (
 
 // Container is used to resolve services at runtime
 type Container struct {
-	projectID       string
-	db              *gorm.DB
-	dedicatedDB     *gorm.DB
-	version         string
-	app             *fiber.App
-	eventDispatcher *services.EventDispatcher
-	logger          telemetry.Logger
+	projectID            string
+	db                   *gorm.DB
+	dedicatedDB          *gorm.DB
+	regionDBs            map[string]*gorm.DB
+	version              string
+	app                  *fiber.App
+	eventDispatcher      *services.EventDispatcher
+	webhookCounter       *services.WebhookDeliveryCounter
+	webhookQueue         *services.WebhookDeliveryQueue
+	socketRegistry       *services.PhoneSocketRegistry
+	redisClient          *redis.Client
+	pruneCounter         *services.MessagePruneCounter
+	apiUsageCounter      *services.APIUsageCounter
+	deprecations         *services.DeprecationCounter
+	phoneSendRateLimiter *services.PhoneSendRateLimiter
+	autocertManager      *autocert.Manager
+	faultInjector        *services.FaultInjector
+	mqttClient           mqtt.Client
+	logger               telemetry.Logger
 }
 
 // NewLiteContainer creates a Container without any routes or listeners
@@ -114,17 +133,32 @@ func NewContainer(projectID string, version string) (container *Container) {
 	container.RegisterMessageListeners()
 	container.RegisterMessageRoutes()
 	container.RegisterBulkMessageRoutes()
+	container.RegisterHomeAssistantRoutes()
+	container.RegisterStatisticsRoutes()
 
 	container.RegisterMessageThreadRoutes()
+	container.RegisterMessageThreadShareRoutes()
+	container.RegisterMessageThreadSummaryRoutes()
 	container.RegisterMessageThreadListeners()
+	container.RegisterMessageThreadClassificationListeners()
 
 	container.RegisterHeartbeatRoutes()
 	container.RegisterHeartbeatListeners()
 
 	container.RegisterUserRoutes()
 	container.RegisterUserListeners()
+	container.RegisterAPIKeyRoutes()
+	container.RegisterAPIKeyListeners()
 
 	container.RegisterPhoneRoutes()
+	container.RegisterPhoneLogRoutes()
+	container.RegisterPhoneLogListeners()
+	container.RegisterPhoneCrashRoutes()
+	container.RegisterPhoneCrashListeners()
+	container.RegisterPhoneMaintenanceWindowRoutes()
+	container.RegisterPhoneMaintenanceWindowListeners()
+	container.RegisterPhoneBalanceRoutes()
+	container.RegisterPhoneBalanceListeners()
 
 	container.RegisterEventRoutes()
 
@@ -133,10 +167,13 @@ func NewContainer(projectID string, version string) (container *Container) {
 
 	container.RegisterBillingRoutes()
 	container.RegisterBillingListeners()
+	container.RegisterAPIUsageRoutes()
 
 	container.RegisterWebhookRoutes()
 	container.RegisterWebhookListeners()
 
+	container.RegisterMetricsRoutes()
+
 	container.RegisterLemonsqueezyRoutes()
 
 	container.RegisterIntegration3CXRoutes()
@@ -145,8 +182,68 @@ func NewContainer(projectID string, version string) (container *Container) {
 	container.RegisterDiscordRoutes()
 	container.RegisterDiscordListeners()
 
+	container.RegisterNotificationChannelRoutes()
+	container.RegisterNotificationChannelListeners()
+	container.RegisterInboxRuleRoutes()
+	container.RegisterInboxRuleListeners()
+	container.RegisterRoutingRuleRoutes()
+	container.RegisterRoutingRuleListeners()
+	container.RegisterPhonePoolRoutes()
+	container.RegisterPhonePoolListeners()
+	container.RegisterComplianceRuleRoutes()
+
+	container.RegisterAttachmentRoutes()
+	container.RegisterAttachmentListeners()
+
+	container.RegisterReportRoutes()
+	container.RegisterReportListeners()
+
+	container.RegisterCustomEventRoutes()
+	container.RegisterCustomEventListeners()
+
+	container.RegisterDebugRequestRoutes()
+	container.RegisterDebugRequestListeners()
+
+	container.RegisterMQTTRoutes()
+	container.RegisterMQTTListeners()
+
 	container.RegisterMarketingListeners()
 
+	container.RegisterSuppressionListeners()
+
+	container.RegisterContactPreferenceRoutes()
+	container.RegisterContactPreferenceListeners()
+
+	container.RegisterCustomDomainRoutes()
+	container.RegisterCustomDomainListeners()
+
+	container.RegisterTranslationListeners()
+
+	// the child processes only serve HTTP requests when APP_PREFORK is enabled so the background jobs are not started once per CPU
+	if !fiber.IsChild() {
+		container.StartBackupScheduler()
+
+		container.StartMessageScheduler()
+
+		container.StartMessageExpirySweeper()
+
+		container.StartMessagePruner()
+
+		container.StartHeartbeatPruner()
+
+		container.StartWebhookEventPruner()
+
+		container.StartReportScheduler()
+
+		container.StartCustomDomainVerifier()
+
+		container.StartHeartbeatPacketListener()
+
+		container.StartPhoneSocketListener()
+
+		container.StartMQTTBridge()
+	}
+
 	// this has to be last since it registers the /* route
 	container.RegisterSwaggerRoutes()
 
@@ -161,7 +258,7 @@ func (container *Container) App() (app *fiber.App) {
 
 	container.logger.Debug(fmt.Sprintf("creating %T", app))
 
-	app = fiber.New()
+	app = fiber.New(container.AppConfig())
 
 	if os.Getenv("USE_HTTP_LOGGER") == "true" {
 		app.Use(fiberLogger.New())
@@ -171,13 +268,151 @@ func (container *Container) App() (app *fiber.App) {
 	app.Use(cors.New())
 	app.Use(middlewares.HTTPRequestLogger(container.Tracer(), container.Logger()))
 
-	app.Use(middlewares.BearerAuth(container.Logger(), container.Tracer(), container.FirebaseAuthClient()))
+	app.Use(middlewares.BearerAuth(
+		container.Logger(),
+		container.Tracer(),
+		container.FirebaseAuthClient(),
+		container.UserRepository(),
+		os.Getenv("FIREBASE_AUTH_CHECK_REVOKED") == "true",
+	))
 	app.Use(middlewares.APIKeyAuth(container.Logger(), container.Tracer(), container.UserRepository()))
+	app.Use(middlewares.DebugRequestRecorder(container.Logger(), container.Tracer(), container.DebugRequestService()))
+	app.Use(middlewares.APIUsageRecorder(container.APIUsageCounter()))
+	app.Use(middlewares.Deprecation(container.DeprecationCounter(), container.DeprecatedRoutes()))
 
 	container.app = app
 	return app
 }
 
+const (
+	// appMinWriteTimeout is the shortest write timeout of the HTTP server which lets a long poll of 60 seconds complete
+	appMinWriteTimeout = 70 * time.Second
+
+	// appDefaultIdleTimeout is how long an idle keep-alive connection stays open when APP_IDLE_TIMEOUT is empty. It is
+	// longer than a long poll so a phone reuses its connection for the next poll.
+	appDefaultIdleTimeout = 2 * time.Minute
+)
+
+// AppConfig creates the fiber.Config of the HTTP server. The read, write and idle timeouts e.g. "30s" are read from
+// APP_READ_TIMEOUT, APP_WRITE_TIMEOUT and APP_IDLE_TIMEOUT, the maximum size of a request body in bytes from APP_BODY_LIMIT
+// and APP_PREFORK=true starts one process per CPU which listens on the same address
+func (container *Container) AppConfig() (config fiber.Config) {
+	container.logger.Debug(fmt.Sprintf("creating %T", config))
+
+	config = fiber.Config{
+		Prefork:   os.Getenv("APP_PREFORK") == "true",
+		BodyLimit: fiber.DefaultBodyLimit,
+	}
+
+	if limit, err := strconv.Atoi(os.Getenv("APP_BODY_LIMIT")); err == nil && limit > 0 {
+		config.BodyLimit = limit
+	}
+
+	if timeout, err := time.ParseDuration(os.Getenv("APP_READ_TIMEOUT")); err == nil && timeout > 0 {
+		config.ReadTimeout = timeout
+	}
+
+	if timeout, err := time.ParseDuration(os.Getenv("APP_WRITE_TIMEOUT")); err == nil && timeout > 0 {
+		config.WriteTimeout = timeout
+	}
+
+	// the write timeout must outlast a long poll of 60 seconds, otherwise the response of the poll cannot be written
+	if config.WriteTimeout > 0 && config.WriteTimeout < appMinWriteTimeout {
+		container.logger.Warn(stacktrace.NewError(fmt.Sprintf("APP_WRITE_TIMEOUT [%s] is shorter than [%s] which is used instead", config.WriteTimeout, appMinWriteTimeout)))
+		config.WriteTimeout = appMinWriteTimeout
+	}
+
+	config.IdleTimeout = appDefaultIdleTimeout
+	if timeout, err := time.ParseDuration(os.Getenv("APP_IDLE_TIMEOUT")); err == nil && timeout > 0 {
+		config.IdleTimeout = timeout
+	}
+
+	return config
+}
+
+// AppAddress is the address of the HTTP server which is read from APP_LISTEN_ADDRESS e.g. ":443" and defaults to APP_HOST:APP_PORT
+func (container *Container) AppAddress() string {
+	if address := strings.TrimSpace(os.Getenv("APP_LISTEN_ADDRESS")); address != "" {
+		return address
+	}
+	return fmt.Sprintf("%s:%s", os.Getenv("APP_HOST"), os.Getenv("APP_PORT"))
+}
+
+// Listen starts the HTTP server on the AppAddress. The server terminates TLS with certificates from Let's Encrypt when
+// APP_TLS_DOMAINS contains a comma separated list of the domains of the server e.g. "api.example.com"
+func (container *Container) Listen() error {
+	address := container.AppAddress()
+
+	domains := container.TLSDomains()
+	if len(domains) == 0 {
+		return container.App().Listen(address)
+	}
+
+	manager := container.AutocertManager()
+	if challengeAddress := strings.TrimSpace(os.Getenv("APP_TLS_HTTP_ADDRESS")); challengeAddress != "" {
+		go func() {
+			if err := http.ListenAndServe(challengeAddress, manager.HTTPHandler(nil)); err != nil {
+				container.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot serve the ACME HTTP challenges on [%s]", challengeAddress)))
+			}
+		}()
+	}
+
+	listener, err := tls.Listen(container.App().Config().Network, address, manager.TLSConfig())
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot listen for TLS connections on [%s]", address))
+	}
+
+	container.logger.Info(fmt.Sprintf("terminating TLS on [%s] for the domains [%s]", address, strings.Join(domains, ",")))
+	return container.App().Listener(listener)
+}
+
+// TLSDomains returns the domains of the server in the comma separated APP_TLS_DOMAINS
+func (container *Container) TLSDomains() (domains []string) {
+	for _, domain := range strings.Split(os.Getenv("APP_TLS_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// AutocertManager creates the autocert.Manager which obtains and renews the certificates of the TLSDomains and the
+// verified entities.CustomDomain of users from Let's Encrypt. The certificates are cached in APP_TLS_CACHE_DIR which
+// defaults to "certs" so they survive restarts. The manager is shared by the HTTP server and the WebSocket listener of
+// phones so that a single ACME order is placed for a host.
+func (container *Container) AutocertManager() (manager *autocert.Manager) {
+	if container.autocertManager != nil {
+		return container.autocertManager
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", manager))
+
+	cacheDir := strings.TrimSpace(os.Getenv("APP_TLS_CACHE_DIR"))
+	if cacheDir == "" {
+		cacheDir = "certs"
+	}
+
+	container.autocertManager = &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: container.AutocertHostPolicy(container.TLSDomains()),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      strings.TrimSpace(os.Getenv("APP_TLS_EMAIL")),
+	}
+	return container.autocertManager
+}
+
+// AutocertHostPolicy allows certificates for the domains of the server and the verified entities.CustomDomain of users
+func (container *Container) AutocertHostPolicy(domains []string) autocert.HostPolicy {
+	whitelist := autocert.HostWhitelist(domains...)
+	customDomains := container.CustomDomainService()
+	return func(ctx context.Context, host string) error {
+		if err := whitelist(ctx, host); err == nil {
+			return nil
+		}
+		return customDomains.HostPolicy(ctx, host)
+	}
+}
+
 // BearerAPIKeyMiddleware creates a new instance of middlewares.BearerAPIKeyAuth
 func (container *Container) BearerAPIKeyMiddleware() fiber.Handler {
 	container.logger.Debug("creating middlewares.BearerAPIKeyAuth")
@@ -187,7 +422,44 @@ func (container *Container) BearerAPIKeyMiddleware() fiber.Handler {
 // AuthenticatedMiddleware creates a new instance of middlewares.Authenticated
 func (container *Container) AuthenticatedMiddleware() fiber.Handler {
 	container.logger.Debug("creating middlewares.Authenticated")
-	return middlewares.Authenticated(container.Tracer())
+	return middlewares.Authenticated(container.Tracer(), container.APIKeyRoutes())
+}
+
+// APIKeyRoutes are the routes which can be requested with an entities.APIKey that has the send or read scope. The other
+// routes can only be requested with an entities.APIKey which has the admin scope.
+func (container *Container) APIKeyRoutes() []entities.APIKeyRoute {
+	send := func(method string, path string) entities.APIKeyRoute {
+		return entities.APIKeyRoute{Method: method, Path: path, Scope: entities.APIKeyScopeSend}
+	}
+	read := func(path string) entities.APIKeyRoute {
+		return entities.APIKeyRoute{Method: fiber.MethodGet, Path: path, Scope: entities.APIKeyScopeRead}
+	}
+
+	return []entities.APIKeyRoute{
+		send(fiber.MethodPost, "/v1/messages/send"),
+		send(fiber.MethodPost, "/v1/messages/simulate"),
+		send(fiber.MethodPost, "/v1/messages/bulk-send"),
+		send(fiber.MethodPost, "/v1/bulk-messages"),
+		send(fiber.MethodPost, "/v1/attachments"),
+
+		read("/v1/messages"),
+		read("/v1/messages/search"),
+		read("/v1/contacts/:contact/timeline"),
+		read("/v1/contacts/:contact/preferences"),
+		read("/v1/message-threads"),
+		read("/v1/message-threads/search"),
+		read("/v1/message-threads/topics"),
+		read("/v1/message-threads/:messageThreadID/context"),
+		read("/v1/message-threads/:messageThreadID/export"),
+		read("/v1/message-threads/:messageThreadID/summary"),
+		read("/v1/attachments/:attachmentID"),
+		read("/v1/phones"),
+		read("/v1/phones/:phoneID"),
+		read("/v1/phones/:phoneID/queue"),
+		read("/v1/phones/:phoneID/queue/stats"),
+		read("/v1/phones/:phoneID/balances"),
+		read("/v1/heartbeats"),
+	}
 }
 
 // AuthRouter creates router for authenticated requests
@@ -245,19 +517,136 @@ func (container *Container) DedicatedDB() (db *gorm.DB) {
 		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tracing plugin"))
 	}
 
-	container.logger.Debug(fmt.Sprintf("Running migrations for dedicated [%T]", db))
-	if err = db.AutoMigrate(&entities.Heartbeat{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Heartbeat{})))
+	if err = db.Use(container.TenantScopePlugin()); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tenant scope plugin"))
 	}
 
-	if err = db.AutoMigrate(&entities.HeartbeatMonitor{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.HeartbeatMonitor{})))
+	container.logger.Debug(fmt.Sprintf("Running migrations for dedicated [%T]", db))
+	for _, model := range container.dedicatedDBModels() {
+		if err = db.AutoMigrate(model); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", model)))
+		}
 	}
 
 	container.dedicatedDB = db
 	return container.dedicatedDB
 }
 
+// RegionDBs creates the regional instances of gorm.DB which are configured in DATA_REGION_DATABASE_URLS e.g. "eu=postgresql://...,us=postgresql://..."
+func (container *Container) RegionDBs() map[string]*gorm.DB {
+	if container.regionDBs != nil {
+		return container.regionDBs
+	}
+
+	container.regionDBs = map[string]*gorm.DB{}
+	for _, item := range strings.Split(os.Getenv("DATA_REGION_DATABASE_URLS"), ",") {
+		region, dsn, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || strings.TrimSpace(region) == "" {
+			continue
+		}
+
+		region = strings.ToLower(strings.TrimSpace(region))
+		container.logger.Debug(fmt.Sprintf("creating %T for data region [%s]", &gorm.DB{}, region))
+
+		config := &gorm.Config{TranslateError: true}
+		if isLocal() {
+			config.Logger = container.GormLogger()
+		}
+
+		db, err := gorm.Open(postgres.Open(strings.TrimSpace(dsn)), config)
+		if err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot open database for data region [%s]", region)))
+		}
+
+		if err = db.Use(tracing.NewPlugin()); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tracing plugin"))
+		}
+
+		if err = db.Use(container.TenantScopePlugin()); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tenant scope plugin"))
+		}
+
+		container.logger.Debug(fmt.Sprintf("Running migrations for data region [%s]", region))
+		if err = db.AutoMigrate(&entities.Message{}); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T in data region [%s]", &entities.Message{}, region)))
+		}
+
+		container.migrateMessageThreadDuplicates(db)
+		if err = db.AutoMigrate(&entities.MessageThread{}); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T in data region [%s]", &entities.MessageThread{}, region)))
+		}
+		container.migrateMessageThreadSearchIndexes(db)
+		container.migrateMessageThreadLastMessage(db)
+
+		container.regionDBs[region] = db
+	}
+
+	return container.regionDBs
+}
+
+// migrateMessageThreadDuplicates removes the duplicate threads of the same owner and contact before the unique index on
+// the user, owner and contact of message threads is created. The thread with the latest message is kept.
+func (container *Container) migrateMessageThreadDuplicates(db *gorm.DB) {
+	if !db.Migrator().HasTable(&entities.MessageThread{}) || db.Migrator().HasIndex(&entities.MessageThread{}, "idx_message_threads__user_id__owner__contact") {
+		return
+	}
+
+	statement := `DELETE FROM message_threads WHERE id IN (
+SELECT id FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, owner, contact ORDER BY order_timestamp DESC, id) AS position FROM message_threads) AS threads
+WHERE threads.position > 1)`
+
+	result := db.Exec(statement)
+	if result.Error != nil {
+		container.logger.Warn(stacktrace.Propagate(result.Error, "cannot remove the duplicate message threads"))
+		return
+	}
+	container.logger.Info(fmt.Sprintf("removed [%d] duplicate message threads", result.RowsAffected))
+}
+
+// migrateMessageThreadSearchIndexes creates the trigram indexes which are used to search message threads with ILIKE and
+// the index which is used to filter the message threads by label
+func (container *Container) migrateMessageThreadSearchIndexes(db *gorm.DB) {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_message_threads__contact_trgm ON message_threads USING GIN (contact gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_message_threads__last_message_content_trgm ON message_threads USING GIN (last_message_content gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_message_threads__labels ON message_threads USING GIN (labels)",
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			container.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot run migration [%s] for message thread search", statement)))
+		}
+	}
+}
+
+// migrateCustomDomainIndex drops the unique index on the domain of all custom domains which is replaced by a unique index
+// on the verified custom domains so that an unverified claim cannot hold the domain of another user
+func (container *Container) migrateCustomDomainIndex(db *gorm.DB) {
+	statement := "DROP INDEX IF EXISTS idx_custom_domains__domain"
+	if err := db.Exec(statement).Error; err != nil {
+		container.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot run migration [%s] for custom domains", statement)))
+	}
+}
+
+// migrateMessageThreadLastMessage backfills the type and the timestamp of the last message on message threads which were
+// created before these columns were stored on the thread
+func (container *Container) migrateMessageThreadLastMessage(db *gorm.DB) {
+	statement := `UPDATE message_threads SET last_message_type = messages.type, last_message_at = messages.order_timestamp
+FROM messages WHERE messages.id = message_threads.last_message_id AND message_threads.last_message_type IS NULL`
+
+	if err := db.Exec(statement).Error; err != nil {
+		container.logger.Warn(stacktrace.Propagate(err, "cannot backfill the last message of message threads"))
+	}
+}
+
+// TenantScopePlugin creates a gorm.Plugin which detects queries that are not scoped by a user.
+// Unscoped queries fail unless TENANT_SCOPE_GUARD is set to "log" which only logs them.
+func (container *Container) TenantScopePlugin() gorm.Plugin {
+	container.logger.Debug("creating repositories.TenantScopePlugin")
+	return repositories.NewTenantScopePlugin(container.Logger(), os.Getenv("TENANT_SCOPE_GUARD") != "log")
+}
+
 // DBWithoutMigration creates an instance of gorm.DB if it has not been created already
 func (container *Container) DBWithoutMigration() (db *gorm.DB) {
 	if container.db != nil {
@@ -280,6 +669,10 @@ func (container *Container) DBWithoutMigration() (db *gorm.DB) {
 	if err = db.Use(tracing.NewPlugin()); err != nil {
 		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tracing plugin"))
 	}
+
+	if err = db.Use(container.TenantScopePlugin()); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tenant scope plugin"))
+	}
 	return container.db
 }
 
@@ -306,6 +699,10 @@ func (container *Container) DB() (db *gorm.DB) {
 		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tracing plugin"))
 	}
 
+	if err = db.Use(container.TenantScopePlugin()); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot use GORM tenant scope plugin"))
+	}
+
 	container.logger.Debug(fmt.Sprintf("Running migrations for %T", db))
 
 	// This prevents a bug in the Gorm AutoMigrate where it tries to delete this no existent constraints
@@ -317,39 +714,61 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Message{})))
 	}
 
+	container.migrateMessageThreadDuplicates(db)
 	if err = db.AutoMigrate(&entities.MessageThread{}); err != nil {
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.MessageThread{})))
 	}
+	container.migrateMessageThreadSearchIndexes(db)
+	container.migrateMessageThreadLastMessage(db)
+	container.migrateCustomDomainIndex(db)
 
-	if err = db.AutoMigrate(&entities.User{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.User{})))
-	}
-
-	if err = db.AutoMigrate(&entities.Phone{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Phone{})))
-	}
-
-	if err = db.AutoMigrate(&entities.PhoneNotification{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.PhoneNotification{})))
-	}
-
-	if err = db.AutoMigrate(&entities.BillingUsage{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.BillingUsage{})))
-	}
-
-	if err = db.AutoMigrate(&entities.Webhook{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Webhook{})))
-	}
-
-	if err = db.AutoMigrate(&entities.Discord{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Discord{})))
+	for _, model := range container.mainDBModels() {
+		if err = db.AutoMigrate(model); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", model)))
+		}
 	}
+	return container.db
+}
 
-	if err = db.AutoMigrate(&entities.Integration3CX{}); err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot migrate %T", &entities.Integration3CX{})))
+// dedicatedDBModels are the entities which are migrated in the dedicated database
+func (container *Container) dedicatedDBModels() []any {
+	return []any{
+		&entities.Heartbeat{},
+		&entities.HeartbeatRollup{},
+		&entities.HeartbeatMonitor{},
+	}
+}
+
+// mainDBModels are the entities which are migrated in the main database after the messages and the message threads
+// which also have a migration in every data region
+func (container *Container) mainDBModels() []any {
+	return []any{
+		&entities.User{},
+		&entities.Phone{},
+		&entities.PhoneNotification{},
+		&entities.BillingUsage{},
+		&entities.Webhook{},
+		&entities.Discord{},
+		&entities.NotificationChannel{},
+		&entities.InboxRule{},
+		&entities.RoutingRule{},
+		&entities.PhonePool{},
+		&entities.Attachment{},
+		&entities.PhoneLog{},
+		&entities.PhoneCrash{},
+		&entities.PhoneMaintenanceWindow{},
+		&entities.PhoneBalance{},
+		&entities.Integration3CX{},
+		&entities.ComplianceRule{},
+		&entities.Suppression{},
+		&entities.APIKey{},
+		&entities.ContactPreference{},
+		&entities.CustomDomain{},
+		&entities.Report{},
+		&entities.CustomEvent{},
+		&entities.WebhookEvent{},
+		&entities.DebugRequest{},
 	}
-
-	return container.db
 }
 
 // FirebaseApp creates a new instance of firebase.App
@@ -373,6 +792,17 @@ func (container *Container) InMemoryCache() cache.Cache {
 // Cache creates a new instance of cache.Cache
 func (container *Container) Cache() cache.Cache {
 	container.logger.Debug("creating cache.Cache")
+	return cache.NewRedisCache(container.Tracer(), container.RedisClient())
+}
+
+// RedisClient creates a new instance of redis.Client which is shared by the cache.Cache, the services.PhoneSocketRegistry,
+// the services.PhoneSendRateLimiter and the counters of the API usage, the webhook deliveries and the deprecated routes
+func (container *Container) RedisClient() (client *redis.Client) {
+	if container.redisClient != nil {
+		return container.redisClient
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", client))
 	opt, err := redis.ParseURL(os.Getenv("REDIS_URL"))
 	if err != nil {
 		container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot parse redis url [%s]", os.Getenv("REDIS_URL"))))
@@ -393,7 +823,8 @@ func (container *Container) Cache() cache.Cache {
 		container.logger.Fatal(stacktrace.Propagate(err, "cannot instrument redis metrics"))
 	}
 
-	return cache.NewRedisCache(container.Tracer(), redisClient)
+	container.redisClient = redisClient
+	return container.redisClient
 }
 
 // FirebaseAuthClient creates a new instance of auth.Client
@@ -419,118 +850,469 @@ func (container *Container) CloudTasksClient() (client *cloudtasks.Client) {
 	return client
 }
 
-// EventsQueueConfiguration creates a new instance of services.PushQueueConfig
-func (container *Container) EventsQueueConfiguration() (config services.PushQueueConfig) {
-	container.logger.Debug(fmt.Sprintf("creating %T", config))
+// CloudStorageClient creates a new instance of storage.Client
+func (container *Container) CloudStorageClient() (client *storage.Client) {
+	container.logger.Debug(fmt.Sprintf("creating %T", client))
 
-	return services.PushQueueConfig{
-		UserAPIKey:       os.Getenv("EVENTS_QUEUE_USER_API_KEY"),
-		Name:             os.Getenv("EVENTS_QUEUE_NAME"),
-		UserID:           entities.UserID(os.Getenv("EVENTS_QUEUE_USER_ID")),
-		ConsumerEndpoint: os.Getenv("EVENTS_QUEUE_ENDPOINT"),
+	client, err := storage.NewClient(context.Background(), option.WithCredentialsJSON(container.FirebaseCredentials()))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot initialize cloud storage client"))
 	}
+
+	return client
 }
 
-// EventsQueue creates a new instance of services.PushQueue
-func (container *Container) EventsQueue() (queue services.PushQueue) {
-	container.logger.Debug("creating events services.PushQueue")
+// BackupStorage creates a new instance of services.BackupStorage
+func (container *Container) BackupStorage() (backupStorage services.BackupStorage) {
+	if bucket := os.Getenv("BACKUP_BUCKET"); bucket != "" {
+		container.logger.Debug(fmt.Sprintf("creating google cloud %T for bucket [%s]", &backupStorage, bucket))
+		return services.NewGoogleCloudBackupStorage(
+			container.Logger(),
+			container.Tracer(),
+			container.CloudStorageClient(),
+			bucket,
+			os.Getenv("BACKUP_PREFIX"),
+		)
+	}
 
-	if os.Getenv("EVENTS_QUEUE_TYPE") == "emulator" {
-		return container.EmulatorEventsQueue()
+	directory := os.Getenv("BACKUP_DIRECTORY")
+	if directory == "" {
+		directory = "backups"
 	}
 
-	return container.CloudTaskEventsQueue()
+	container.logger.Debug(fmt.Sprintf("creating file %T in directory [%s]", &backupStorage, directory))
+	return services.NewFileBackupStorage(container.Logger(), container.Tracer(), directory)
 }
 
-// EmulatorEventsQueue creates an in process instance of events services.PushQueue
-func (container *Container) EmulatorEventsQueue() (queue services.PushQueue) {
-	container.logger.Debug("creating emulator events services.PushQueue")
-	return services.EmulatorPushQueue(
+// BackupService creates a new instance of services.BackupService
+func (container *Container) BackupService() (service *services.BackupService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+
+	retention, err := strconv.Atoi(os.Getenv("BACKUP_RETENTION"))
+	if err != nil {
+		retention = 7
+	}
+
+	return services.NewBackupService(
 		container.Logger(),
 		container.Tracer(),
-		container.HTTPClient("emulator_events_queue"),
-		container.EventsQueueConfiguration(),
+		container.BackupRepository(),
+		container.BackupStorage(),
+		services.BackupServiceConfig{
+			EncryptionKey: os.Getenv("BACKUP_ENCRYPTION_KEY"),
+			Retention:     retention,
+		},
 	)
 }
 
-// CloudTaskEventsQueue creates a Google cloud task instance of events services.PushQueue
-func (container *Container) CloudTaskEventsQueue() (queue services.PushQueue) {
-	container.logger.Debug("creating cloud task events services.PushQueue")
-	return services.NewGooglePushQueue(
+// StartBackupScheduler runs the scheduled backups in the background if BACKUP_INTERVAL is set e.g. "24h"
+func (container *Container) StartBackupScheduler() {
+	interval, err := time.ParseDuration(os.Getenv("BACKUP_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return
+	}
+
+	container.logger.Info(fmt.Sprintf("scheduling backups every [%s]", interval))
+	go container.BackupService().Schedule(context.Background(), interval)
+}
+
+// MessageSchedulerService creates a new instance of services.MessageSchedulerService
+func (container *Container) MessageSchedulerService() (service *services.MessageSchedulerService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageSchedulerService(
 		container.Logger(),
 		container.Tracer(),
-		container.CloudTasksClient(),
-		container.EventsQueueConfiguration(),
+		container.MessageRepository(),
+		container.EventDispatcher(),
 	)
 }
 
-// FirebaseMessagingClient creates a new instance of messaging.Client
-func (container *Container) FirebaseMessagingClient() (client *messaging.Client) {
-	container.logger.Debug(fmt.Sprintf("creating %T", client))
-	messagingClient, err := container.FirebaseApp().Messaging(context.Background())
-	if err != nil {
-		msg := "cannot initialize firebase messaging client"
-		container.logger.Fatal(stacktrace.Propagate(err, msg))
+// StartMessageScheduler dispatches the messages with a send time in the future every MESSAGE_SCHEDULER_INTERVAL which defaults to "15s"
+func (container *Container) StartMessageScheduler() {
+	interval, err := time.ParseDuration(os.Getenv("MESSAGE_SCHEDULER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
 	}
-	return messagingClient
+
+	container.logger.Info(fmt.Sprintf("dispatching scheduled messages every [%s]", interval))
+	go container.MessageSchedulerService().Schedule(context.Background(), interval)
 }
 
-// FirebaseCredentials returns firebase credentials as bytes.
-func (container *Container) FirebaseCredentials() []byte {
-	container.logger.Debug("creating firebase credentials")
-	return []byte(os.Getenv("FIREBASE_CREDENTIALS"))
+// StartMessageExpirySweeper expires the messages which are past their expiry time every MESSAGE_EXPIRY_SWEEPER_INTERVAL which defaults to "1m"
+func (container *Container) StartMessageExpirySweeper() {
+	interval, err := time.ParseDuration(os.Getenv("MESSAGE_EXPIRY_SWEEPER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+
+	container.logger.Info(fmt.Sprintf("expiring messages which are past their expiry time every [%s]", interval))
+	go container.MessageService().SweepExpired(context.Background(), interval)
 }
 
-// Tracer creates a new instance of telemetry.Tracer
-func (container *Container) Tracer() (t telemetry.Tracer) {
-	container.logger.Debug("creating telemetry.Tracer")
-	return telemetry.NewOtelLogger(
-		container.projectID,
-		container.Logger(),
-	)
+// MessagePruneCounter creates a new instance of services.MessagePruneCounter which is shared by the services.MessageRetentionService and the services.MetricsService
+func (container *Container) MessagePruneCounter() (counter *services.MessagePruneCounter) {
+	if container.pruneCounter != nil {
+		return container.pruneCounter
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", counter))
+	container.pruneCounter = services.NewMessagePruneCounter()
+	return container.pruneCounter
 }
 
-// MessageHandlerValidator creates a new instance of validators.MessageHandlerValidator
-func (container *Container) MessageHandlerValidator() (validator *validators.MessageHandlerValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewMessageHandlerValidator(
+// MessageRetentionService creates a new instance of services.MessageRetentionService
+func (container *Container) MessageRetentionService() (service *services.MessageRetentionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageRetentionService(
 		container.Logger(),
 		container.Tracer(),
-		container.PhoneService(),
-		container.TurnstileTokenValidator(),
-	)
+		container.MessageRepository(),
+		container.MessagePruneCounter(),
+		container.MessageRetention(),
+		container.MessageRedaction(),
+	)
+}
+
+// MessageRetention is the retention period of each entities.MessageCategory configured with MESSAGE_RETENTION_TRANSACTIONAL,
+// MESSAGE_RETENTION_MARKETING and MESSAGE_RETENTION_OTP e.g. "24h". Messages of a category without a retention period are kept forever
+func (container *Container) MessageRetention() map[entities.MessageCategory]time.Duration {
+	retention := map[entities.MessageCategory]time.Duration{}
+	for category, key := range map[entities.MessageCategory]string{
+		entities.MessageCategoryTransactional: "MESSAGE_RETENTION_TRANSACTIONAL",
+		entities.MessageCategoryMarketing:     "MESSAGE_RETENTION_MARKETING",
+		entities.MessageCategoryOTP:           "MESSAGE_RETENTION_OTP",
+	} {
+		duration, err := time.ParseDuration(os.Getenv(key))
+		if err != nil || duration <= 0 {
+			continue
+		}
+		retention[category] = duration
+	}
+	return retention
 }
 
-// TurnstileTokenValidator creates a new instance of validators.TurnstileTokenValidator
-func (container *Container) TurnstileTokenValidator() (validator *validators.TurnstileTokenValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewTurnstileTokenValidator(
-		container.Logger(),
-		container.Tracer(),
-		os.Getenv("CLOUDFLARE_TURNSTILE_SECRET_KEY"),
-		container.HTTPClient("turnstile"),
-	)
+// MessageRedaction is the period after which the content of OTP messages is redacted configured with MESSAGE_REDACTION_OTP e.g. "1h".
+// The content of OTP messages is kept when it is empty
+func (container *Container) MessageRedaction() time.Duration {
+	redaction, err := time.ParseDuration(os.Getenv("MESSAGE_REDACTION_OTP"))
+	if err != nil || redaction <= 0 {
+		return 0
+	}
+	return redaction
 }
 
-// BulkMessageHandlerValidator creates a new instance of validators.BulkMessageHandlerValidator
-func (container *Container) BulkMessageHandlerValidator() (validator *validators.BulkMessageHandlerValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewBulkMessageHandlerValidator(
-		container.Logger(),
-		container.Tracer(),
-		container.PhoneService(),
-		container.UserService(),
-	)
+// StartMessagePruner deletes the messages which are older than the retention period of their category and redacts the content of OTP
+// messages every MESSAGE_PRUNER_INTERVAL which defaults to "1h". The pruner is not started if no retention or redaction period is configured
+func (container *Container) StartMessagePruner() {
+	retention := container.MessageRetention()
+	redaction := container.MessageRedaction()
+	if len(retention) == 0 && redaction == 0 {
+		return
+	}
+
+	interval, err := time.ParseDuration(os.Getenv("MESSAGE_PRUNER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	container.logger.Info(fmt.Sprintf("pruning messages with retention [%v] and redacting OTP messages after [%s] every [%s]", retention, redaction, interval))
+	go container.MessageRetentionService().Schedule(context.Background(), interval)
 }
 
-// HeartbeatHandler creates a new instance of handlers.HeartbeatHandler
-func (container *Container) HeartbeatHandler() (h *handlers.HeartbeatHandler) {
-	container.logger.Debug(fmt.Sprintf("creating %T", h))
-	return handlers.NewHeartbeatHandler(
+// HeartbeatRetentionService creates a new instance of services.HeartbeatRetentionService
+func (container *Container) HeartbeatRetentionService() (service *services.HeartbeatRetentionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewHeartbeatRetentionService(
 		container.Logger(),
 		container.Tracer(),
-		container.HeartbeatHandlerValidator(),
-		container.HeartbeatService(),
+		container.HeartbeatRollupRepository(),
+		container.HeartbeatRetention(),
+	)
+}
+
+// HeartbeatRetention is the period after which heartbeats are rolled up and deleted configured with HEARTBEAT_RETENTION e.g. "2160h".
+// Heartbeats are kept forever when it is empty
+func (container *Container) HeartbeatRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("HEARTBEAT_RETENTION"))
+	if err != nil || retention <= 0 {
+		return 0
+	}
+	return retention
+}
+
+// StartHeartbeatPruner rolls the heartbeats which are older than the retention period into hourly and daily aggregates and deletes them
+// every HEARTBEAT_PRUNER_INTERVAL which defaults to "1h". The pruner is not started if no retention period is configured
+func (container *Container) StartHeartbeatPruner() {
+	retention := container.HeartbeatRetention()
+	if retention == 0 {
+		return
+	}
+
+	interval, err := time.ParseDuration(os.Getenv("HEARTBEAT_PRUNER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	container.logger.Info(fmt.Sprintf("pruning heartbeats with retention [%s] every [%s]", retention, interval))
+	go container.HeartbeatRetentionService().Schedule(context.Background(), interval)
+}
+
+// ReportService creates a new instance of services.ReportService
+func (container *Container) ReportService() (service *services.ReportService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewReportService(
+		container.Logger(),
+		container.Tracer(),
+		container.ReportRepository(),
+		container.MessageRepository(),
+		container.UserRepository(),
+		container.AttachmentService(),
+		container.NotificationEmailFactory(),
+		container.Mailer(),
+		container.EventDispatcher(),
+		container.APIURL(),
+		os.Getenv("SHARE_LINK_SIGNING_KEY"),
+	)
+}
+
+// CustomEventService creates a new instance of services.CustomEventService
+func (container *Container) CustomEventService() (service *services.CustomEventService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewCustomEventService(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomEventRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// DebugRequestService creates a new instance of services.DebugRequestService
+func (container *Container) DebugRequestService() (service *services.DebugRequestService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewDebugRequestService(
+		container.Logger(),
+		container.Tracer(),
+		container.Cache(),
+		container.DebugRequestRepository(),
+	)
+}
+
+// APIURL is the public URL of the API server which is configured with API_URL and defaults to the local APP_PORT
+func (container *Container) APIURL() string {
+	if url := strings.TrimSpace(os.Getenv("API_URL")); url != "" {
+		return url
+	}
+	return "http://localhost:" + os.Getenv("APP_PORT")
+}
+
+// StartReportScheduler generates the scheduled reports which are due every REPORT_SCHEDULER_INTERVAL which defaults to "5m"
+func (container *Container) StartReportScheduler() {
+	interval, err := time.ParseDuration(os.Getenv("REPORT_SCHEDULER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	container.logger.Info(fmt.Sprintf("generating scheduled reports every [%s]", interval))
+	go container.ReportService().Schedule(context.Background(), interval)
+}
+
+// StartCustomDomainVerifier checks the TXT records of the unverified entities.CustomDomain every CUSTOM_DOMAIN_VERIFIER_INTERVAL
+func (container *Container) StartCustomDomainVerifier() {
+	interval, err := time.ParseDuration(os.Getenv("CUSTOM_DOMAIN_VERIFIER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	container.logger.Info(fmt.Sprintf("verifying custom domains every [%s]", interval))
+	go container.CustomDomainService().Schedule(context.Background(), interval)
+}
+
+// StartHeartbeatPacketListener receives heartbeats as UDP packets when HEARTBEAT_UDP_ADDRESS is set
+func (container *Container) StartHeartbeatPacketListener() {
+	address := os.Getenv("HEARTBEAT_UDP_ADDRESS")
+	if address == "" {
+		return
+	}
+
+	go func() {
+		if err := container.HeartbeatPacketHandler().ListenAndServe(context.Background(), address); err != nil {
+			container.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot receive heartbeat packets on [%s]", address)))
+		}
+	}()
+}
+
+// StartPhoneSocketListener accepts the WebSocket connections of phones when PHONE_WEBSOCKET_ADDRESS is set. The
+// connections are encrypted with the certificates of the domains in APP_TLS_DOMAINS when it is set.
+func (container *Container) StartPhoneSocketListener() {
+	address := os.Getenv("PHONE_WEBSOCKET_ADDRESS")
+	if address == "" {
+		return
+	}
+
+	var tlsConfig *tls.Config
+	if len(container.TLSDomains()) > 0 {
+		tlsConfig = container.AutocertManager().TLSConfig()
+	}
+
+	go func() {
+		if err := container.PhoneSocketHandler().ListenAndServe(context.Background(), address, tlsConfig); err != nil {
+			container.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot accept websocket connections on [%s]", address)))
+		}
+	}()
+}
+
+// EventsQueueConfiguration creates a new instance of services.PushQueueConfig
+func (container *Container) EventsQueueConfiguration() (config services.PushQueueConfig) {
+	container.logger.Debug(fmt.Sprintf("creating %T", config))
+
+	return services.PushQueueConfig{
+		UserAPIKey:       os.Getenv("EVENTS_QUEUE_USER_API_KEY"),
+		Name:             os.Getenv("EVENTS_QUEUE_NAME"),
+		UserID:           entities.UserID(os.Getenv("EVENTS_QUEUE_USER_ID")),
+		ConsumerEndpoint: os.Getenv("EVENTS_QUEUE_ENDPOINT"),
+	}
+}
+
+// EventsQueue creates a new instance of services.PushQueue
+func (container *Container) EventsQueue() (queue services.PushQueue) {
+	container.logger.Debug("creating events services.PushQueue")
+
+	if os.Getenv("EVENTS_QUEUE_TYPE") == "emulator" {
+		return container.EmulatorEventsQueue()
+	}
+
+	return container.CloudTaskEventsQueue()
+}
+
+// EmulatorEventsQueue creates an in process instance of events services.PushQueue
+func (container *Container) EmulatorEventsQueue() (queue services.PushQueue) {
+	container.logger.Debug("creating emulator events services.PushQueue")
+	return services.EmulatorPushQueue(
+		container.Logger(),
+		container.Tracer(),
+		container.HTTPClient("emulator_events_queue"),
+		container.EventsQueueConfiguration(),
+	)
+}
+
+// CloudTaskEventsQueue creates a Google cloud task instance of events services.PushQueue
+func (container *Container) CloudTaskEventsQueue() (queue services.PushQueue) {
+	container.logger.Debug("creating cloud task events services.PushQueue")
+	return services.NewGooglePushQueue(
+		container.Logger(),
+		container.Tracer(),
+		container.CloudTasksClient(),
+		container.EventsQueueConfiguration(),
+	)
+}
+
+// FirebaseMessagingClient creates a new instance of messaging.Client
+func (container *Container) FirebaseMessagingClient() (client *messaging.Client) {
+	container.logger.Debug(fmt.Sprintf("creating %T", client))
+	messagingClient, err := container.FirebaseApp().Messaging(context.Background())
+	if err != nil {
+		msg := "cannot initialize firebase messaging client"
+		container.logger.Fatal(stacktrace.Propagate(err, msg))
+	}
+	return messagingClient
+}
+
+// FirebaseCredentials returns firebase credentials as bytes.
+func (container *Container) FirebaseCredentials() []byte {
+	container.logger.Debug("creating firebase credentials")
+	return []byte(os.Getenv("FIREBASE_CREDENTIALS"))
+}
+
+// Tracer creates a new instance of telemetry.Tracer
+func (container *Container) Tracer() (t telemetry.Tracer) {
+	container.logger.Debug("creating telemetry.Tracer")
+	return telemetry.NewOtelLogger(
+		container.projectID,
+		container.Logger(),
+	)
+}
+
+// MessageHandlerValidator creates a new instance of validators.MessageHandlerValidator
+func (container *Container) MessageHandlerValidator() (validator *validators.MessageHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+		container.ComplianceService(),
+		container.SuppressionService(),
+		container.ContactPreferenceService(),
+		container.TurnstileTokenValidator(),
+		container.MessageMaxSegments(),
+	)
+}
+
+// MessageMaxSegments is the maximum number of SMS segments of the content of a message which is configured with
+// MESSAGE_MAX_SEGMENTS. The number of segments is not limited when it is not set
+func (container *Container) MessageMaxSegments() uint {
+	segments, err := strconv.ParseUint(os.Getenv("MESSAGE_MAX_SEGMENTS"), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(segments)
+}
+
+// TurnstileTokenValidator creates a new instance of validators.TurnstileTokenValidator
+func (container *Container) TurnstileTokenValidator() (validator *validators.TurnstileTokenValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewTurnstileTokenValidator(
+		container.Logger(),
+		container.Tracer(),
+		os.Getenv("CLOUDFLARE_TURNSTILE_SECRET_KEY"),
+		container.HTTPClient("turnstile"),
+	)
+}
+
+// BulkMessageHandlerValidator creates a new instance of validators.BulkMessageHandlerValidator
+func (container *Container) BulkMessageHandlerValidator() (validator *validators.BulkMessageHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewBulkMessageHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+		container.UserService(),
+		container.ContactPreferenceService(),
+	)
+}
+
+// HeartbeatHandler creates a new instance of handlers.HeartbeatHandler
+func (container *Container) HeartbeatHandler() (h *handlers.HeartbeatHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewHeartbeatHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.HeartbeatHandlerValidator(),
+		container.HeartbeatService(),
+	)
+}
+
+// HeartbeatPacketHandler creates a new instance of handlers.HeartbeatPacketHandler
+func (container *Container) HeartbeatPacketHandler() (h *handlers.HeartbeatPacketHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewHeartbeatPacketHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.HeartbeatHandlerValidator(),
+		container.UserRepository(),
+		container.APIKeyRepository(),
+		container.HeartbeatService(),
+	)
+}
+
+// PhoneSocketHandler creates a new instance of handlers.PhoneSocketHandler
+func (container *Container) PhoneSocketHandler() (h *handlers.PhoneSocketHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhoneSocketHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.UserRepository(),
+		container.PhoneService(),
+		container.PhoneSocketRegistry(),
 	)
 }
 
@@ -565,6 +1347,16 @@ func (container *Container) HeartbeatHandlerValidator() (validator *validators.H
 	)
 }
 
+// APIUsageHandler creates a new instance of handlers.APIUsageHandler
+func (container *Container) APIUsageHandler() (h *handlers.APIUsageHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewAPIUsageHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.APIUsageService(),
+	)
+}
+
 // BillingHandlerValidator creates a new instance of validators.BillingHandlerValidator
 func (container *Container) BillingHandlerValidator() (validator *validators.BillingHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
@@ -591,97 +1383,534 @@ func (container *Container) WebhookHandlerValidator() (validator *validators.Web
 		container.Logger(),
 		container.Tracer(),
 		container.PhoneService(),
+		container.EgressGuard(),
+		container.WebhookEventRetention(),
 	)
 }
 
-// MessageThreadHandler creates a new instance of handlers.MessageThreadHandler
-func (container *Container) MessageThreadHandler() (h *handlers.MessageThreadHandler) {
+// NotificationChannelHandler creates a new instance of handlers.NotificationChannelHandler
+func (container *Container) NotificationChannelHandler() (h *handlers.NotificationChannelHandler) {
 	container.logger.Debug(fmt.Sprintf("creating %T", h))
-	return handlers.NewMessageThreadHandler(
+	return handlers.NewNotificationChannelHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.MessageThreadHandlerValidator(),
-		container.MessageThreadService(),
+		container.NotificationChannelService(),
+		container.NotificationChannelHandlerValidator(),
 	)
 }
 
-// MessageThreadHandlerValidator creates a new instance of validators.MessageThreadHandlerValidator
-func (container *Container) MessageThreadHandlerValidator() (validator *validators.MessageThreadHandlerValidator) {
+// NotificationChannelHandlerValidator creates a new instance of validators.NotificationChannelHandlerValidator
+func (container *Container) NotificationChannelHandlerValidator() (validator *validators.NotificationChannelHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewMessageThreadHandlerValidator(
+	return validators.NewNotificationChannelHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
+		container.NotificationChannelService(),
+		container.EgressGuard(),
 	)
 }
 
-// PhoneHandlerValidator creates a new instance of validators.PhoneHandlerValidator
-func (container *Container) PhoneHandlerValidator() (validator *validators.PhoneHandlerValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewPhoneHandlerValidator(
+// InboxRuleHandler creates a new instance of handlers.InboxRuleHandler
+func (container *Container) InboxRuleHandler() (h *handlers.InboxRuleHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewInboxRuleHandler(
 		container.Logger(),
 		container.Tracer(),
+		container.InboxRuleService(),
+		container.InboxRuleHandlerValidator(),
 	)
 }
 
-// UserHandlerValidator creates a new instance of validators.UserHandlerValidator
-func (container *Container) UserHandlerValidator() (validator *validators.UserHandlerValidator) {
+// InboxRuleHandlerValidator creates a new instance of validators.InboxRuleHandlerValidator
+func (container *Container) InboxRuleHandlerValidator() (validator *validators.InboxRuleHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewUserHandlerValidator(
+	return validators.NewInboxRuleHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
 	)
 }
 
-// EventDispatcher creates a new instance of services.EventDispatcher
-func (container *Container) EventDispatcher() (dispatcher *services.EventDispatcher) {
-	if container.eventDispatcher != nil {
-		return container.eventDispatcher
+// ComplianceRuleHandler creates a new instance of handlers.ComplianceRuleHandler. The users in the comma separated
+// COMPLIANCE_RULE_ADMIN_USER_IDS can change the compliance rules.
+func (container *Container) ComplianceRuleHandler() (h *handlers.ComplianceRuleHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+
+	var admins []entities.UserID
+	for _, userID := range strings.Split(os.Getenv("COMPLIANCE_RULE_ADMIN_USER_IDS"), ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			admins = append(admins, entities.UserID(userID))
+		}
 	}
 
-	container.logger.Debug(fmt.Sprintf("creating %T", dispatcher))
-	dispatcher = services.NewEventDispatcher(
+	return handlers.NewComplianceRuleHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.Float64Histogram("event.publisher.duration", "ms", "measures the duration of processing CloudEvents"),
-		container.EventsQueue(),
-		container.EventsQueueConfiguration(),
+		container.ComplianceService(),
+		container.ComplianceRuleHandlerValidator(),
+		admins,
 	)
-
-	container.eventDispatcher = dispatcher
-	return dispatcher
 }
 
-// Float64Histogram creates a new instance of metric.Float64Histogram
-func (container *Container) Float64Histogram(name, unit, description string) otelMetric.Float64Histogram {
-	container.logger.Debug("creating GORM repositories.MessageRepository")
-	meter := otel.GetMeterProvider().Meter(
-		container.projectID,
-		otelMetric.WithInstrumentationVersion(otel.Version()),
+// ComplianceRuleHandlerValidator creates a new instance of validators.ComplianceRuleHandlerValidator
+func (container *Container) ComplianceRuleHandlerValidator() (validator *validators.ComplianceRuleHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewComplianceRuleHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.ComplianceService(),
 	)
-	histogram, err := meter.Float64Histogram(name, otelMetric.WithUnit(unit), otelMetric.WithDescription(description))
-	if err != nil {
-		container.logger.Fatal(stacktrace.Propagate(err, "cannot create float64 histogram"))
-	}
-	return histogram
 }
 
-// MessageRepository creates a new instance of repositories.MessageRepository
-func (container *Container) MessageRepository() (repository repositories.MessageRepository) {
-	container.logger.Debug("creating GORM repositories.MessageRepository")
-	return repositories.NewGormMessageRepository(
+// RoutingRuleHandler creates a new instance of handlers.RoutingRuleHandler
+func (container *Container) RoutingRuleHandler() (h *handlers.RoutingRuleHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewRoutingRuleHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.RoutingRuleService(),
+		container.RoutingRuleHandlerValidator(),
 	)
 }
 
-// Integration3CXRepository creates a new instance of repositories.Integration3CxRepository
-func (container *Container) Integration3CXRepository() (repository repositories.Integration3CxRepository) {
-	container.logger.Debug("creating GORM repositories.Integration3CxRepository")
-	return repositories.NewGormIntegration3CXRepository(
+// RoutingRuleHandlerValidator creates a new instance of validators.RoutingRuleHandlerValidator
+func (container *Container) RoutingRuleHandlerValidator() (validator *validators.RoutingRuleHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewRoutingRuleHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.PhoneService(),
+	)
+}
+
+// APIKeyHandler creates a new instance of handlers.APIKeyHandler
+func (container *Container) APIKeyHandler() (h *handlers.APIKeyHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewAPIKeyHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.APIKeyService(),
+		container.APIKeyHandlerValidator(),
+	)
+}
+
+// APIKeyHandlerValidator creates a new instance of validators.APIKeyHandlerValidator
+func (container *Container) APIKeyHandlerValidator() (validator *validators.APIKeyHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewAPIKeyHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+	)
+}
+
+// PhonePoolHandler creates a new instance of handlers.PhonePoolHandler
+func (container *Container) PhonePoolHandler() (h *handlers.PhonePoolHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhonePoolHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.PhonePoolService(),
+		container.PhonePoolHandlerValidator(),
+	)
+}
+
+// PhonePoolHandlerValidator creates a new instance of validators.PhonePoolHandlerValidator
+func (container *Container) PhonePoolHandlerValidator() (validator *validators.PhonePoolHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhonePoolHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+	)
+}
+
+// AttachmentHandler creates a new instance of handlers.AttachmentHandler
+func (container *Container) AttachmentHandler() (h *handlers.AttachmentHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewAttachmentHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentService(),
+		container.AttachmentHandlerValidator(),
+	)
+}
+
+// AttachmentHandlerValidator creates a new instance of validators.AttachmentHandlerValidator
+func (container *Container) AttachmentHandlerValidator() (validator *validators.AttachmentHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewAttachmentHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// PhoneLogHandler creates a new instance of handlers.PhoneLogHandler
+func (container *Container) PhoneLogHandler() (h *handlers.PhoneLogHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhoneLogHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneLogService(),
+		container.PhoneLogHandlerValidator(),
+	)
+}
+
+// PhoneLogHandlerValidator creates a new instance of validators.PhoneLogHandlerValidator
+func (container *Container) PhoneLogHandlerValidator() (validator *validators.PhoneLogHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneLogHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentHandlerValidator(),
+	)
+}
+
+// PhoneCrashHandler creates a new instance of handlers.PhoneCrashHandler
+func (container *Container) PhoneCrashHandler() (h *handlers.PhoneCrashHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhoneCrashHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneCrashService(),
+		container.PhoneCrashHandlerValidator(),
+	)
+}
+
+// PhoneCrashHandlerValidator creates a new instance of validators.PhoneCrashHandlerValidator
+func (container *Container) PhoneCrashHandlerValidator() (validator *validators.PhoneCrashHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneCrashHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// PhoneBalanceHandler creates a new instance of handlers.PhoneBalanceHandler
+func (container *Container) PhoneBalanceHandler() (h *handlers.PhoneBalanceHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhoneBalanceHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneBalanceService(),
+		container.PhoneBalanceHandlerValidator(),
+	)
+}
+
+// PhoneBalanceHandlerValidator creates a new instance of validators.PhoneBalanceHandlerValidator
+func (container *Container) PhoneBalanceHandlerValidator() (validator *validators.PhoneBalanceHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneBalanceHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// ContactPreferenceHandler creates a new instance of handlers.ContactPreferenceHandler
+func (container *Container) ContactPreferenceHandler() (h *handlers.ContactPreferenceHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewContactPreferenceHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactPreferenceService(),
+		container.ContactPreferenceHandlerValidator(),
+	)
+}
+
+// ContactPreferenceHandlerValidator creates a new instance of validators.ContactPreferenceHandlerValidator
+func (container *Container) ContactPreferenceHandlerValidator() (validator *validators.ContactPreferenceHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewContactPreferenceHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// CustomDomainHandler creates a new instance of handlers.CustomDomainHandler
+func (container *Container) CustomDomainHandler() (h *handlers.CustomDomainHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewCustomDomainHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomDomainService(),
+		container.CustomDomainHandlerValidator(),
+	)
+}
+
+// CustomDomainHandlerValidator creates a new instance of validators.CustomDomainHandlerValidator
+func (container *Container) CustomDomainHandlerValidator() (validator *validators.CustomDomainHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewCustomDomainHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomDomainService(),
+	)
+}
+
+// PhoneMaintenanceWindowHandler creates a new instance of handlers.PhoneMaintenanceWindowHandler
+func (container *Container) PhoneMaintenanceWindowHandler() (h *handlers.PhoneMaintenanceWindowHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhoneMaintenanceWindowHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneMaintenanceWindowService(),
+		container.PhoneMaintenanceWindowHandlerValidator(),
+	)
+}
+
+// PhoneMaintenanceWindowHandlerValidator creates a new instance of validators.PhoneMaintenanceWindowHandlerValidator
+func (container *Container) PhoneMaintenanceWindowHandlerValidator() (validator *validators.PhoneMaintenanceWindowHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneMaintenanceWindowHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// MessageThreadHandler creates a new instance of handlers.MessageThreadHandler
+func (container *Container) MessageThreadHandler() (h *handlers.MessageThreadHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageThreadHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageThreadHandlerValidator(),
+		container.MessageThreadService(),
+	)
+}
+
+// MessageThreadShareHandler creates a new instance of handlers.MessageThreadShareHandler
+func (container *Container) MessageThreadShareHandler() (h *handlers.MessageThreadShareHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageThreadShareHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageThreadHandlerValidator(),
+		container.MessageThreadShareService(),
+	)
+}
+
+// ReportHandler creates a new instance of handlers.ReportHandler
+func (container *Container) ReportHandler() (h *handlers.ReportHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewReportHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ReportService(),
+		container.ReportHandlerValidator(),
+	)
+}
+
+// ReportHandlerValidator creates a new instance of validators.ReportHandlerValidator
+func (container *Container) ReportHandlerValidator() (validator *validators.ReportHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewReportHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// CustomEventHandler creates a new instance of handlers.CustomEventHandler
+func (container *Container) CustomEventHandler() (h *handlers.CustomEventHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewCustomEventHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomEventService(),
+		container.CustomEventHandlerValidator(),
+	)
+}
+
+// CustomEventHandlerValidator creates a new instance of validators.CustomEventHandlerValidator
+func (container *Container) CustomEventHandlerValidator() (validator *validators.CustomEventHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewCustomEventHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// DebugRequestHandler creates a new instance of handlers.DebugRequestHandler
+func (container *Container) DebugRequestHandler() (h *handlers.DebugRequestHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewDebugRequestHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.DebugRequestService(),
+		container.DebugRequestHandlerValidator(),
+	)
+}
+
+// DebugRequestHandlerValidator creates a new instance of validators.DebugRequestHandlerValidator
+func (container *Container) DebugRequestHandlerValidator() (validator *validators.DebugRequestHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewDebugRequestHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// MessageThreadSummaryHandler creates a new instance of handlers.MessageThreadSummaryHandler
+func (container *Container) MessageThreadSummaryHandler(provider services.LLMProvider) (h *handlers.MessageThreadSummaryHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageThreadSummaryHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageThreadHandlerValidator(),
+		container.MessageThreadSummaryService(provider),
+	)
+}
+
+// MessageThreadHandlerValidator creates a new instance of validators.MessageThreadHandlerValidator
+func (container *Container) MessageThreadHandlerValidator() (validator *validators.MessageThreadHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageThreadHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// PhoneHandlerValidator creates a new instance of validators.PhoneHandlerValidator
+func (container *Container) PhoneHandlerValidator() (validator *validators.PhoneHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+		container.EgressGuard(),
+	)
+}
+
+// UserHandlerValidator creates a new instance of validators.UserHandlerValidator
+func (container *Container) UserHandlerValidator() (validator *validators.UserHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewUserHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// EventDispatcher creates a new instance of services.EventDispatcher
+func (container *Container) EventDispatcher() (dispatcher *services.EventDispatcher) {
+	if container.eventDispatcher != nil {
+		return container.eventDispatcher
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", dispatcher))
+	dispatcher = services.NewEventDispatcher(
+		container.Logger(),
+		container.Tracer(),
+		container.Float64Histogram("event.publisher.duration", "ms", "measures the duration of processing CloudEvents"),
+		container.EventsQueue(),
+		container.EventsQueueConfiguration(),
+		container.DisabledEventListeners(),
+		container.FaultInjector(),
+	)
+
+	container.eventDispatcher = dispatcher
+	return dispatcher
+}
+
+// FaultInjector creates a cached services.FaultInjector for testing integrations. FAULT_INJECTION_FAILURE_RATE e.g. "0.1"
+// fails a share of the calls, FAULT_INJECTION_MAX_DELAY e.g. "5s" delays them and FAULT_INJECTION_TARGETS e.g. "webhooks"
+// selects the listeners and or the webhooks. Faults are never injected when ENV is production.
+func (container *Container) FaultInjector() (injector *services.FaultInjector) {
+	if container.faultInjector != nil {
+		return container.faultInjector
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", injector))
+
+	failureRate, _ := strconv.ParseFloat(os.Getenv("FAULT_INJECTION_FAILURE_RATE"), 64)
+	maxDelay, _ := time.ParseDuration(os.Getenv("FAULT_INJECTION_MAX_DELAY"))
+
+	targets := []services.FaultTarget{services.FaultTargetListeners, services.FaultTargetWebhooks}
+	if value := strings.TrimSpace(os.Getenv("FAULT_INJECTION_TARGETS")); value != "" {
+		targets = nil
+		for _, target := range strings.Split(value, ",") {
+			targets = append(targets, services.FaultTarget(strings.TrimSpace(target)))
+		}
+	}
+
+	if os.Getenv("ENV") == "production" && (failureRate > 0 || maxDelay > 0) {
+		container.logger.Warn(stacktrace.NewError("fault injection is disabled because ENV is production"))
+		failureRate, maxDelay = 0, 0
+	}
+
+	if failureRate > 0 || maxDelay > 0 {
+		container.logger.Warn(stacktrace.NewError(fmt.Sprintf("fault injection is enabled for %v with failure rate [%.2f] and max delay [%s]", targets, failureRate, maxDelay)))
+	}
+
+	container.faultInjector = services.NewFaultInjector(failureRate, maxDelay, targets)
+	return container.faultInjector
+}
+
+// DisabledEventListeners returns the listeners e.g. "MessageThreadListener" or listeners for a single event
+// e.g. "NotificationListener:message.api.sent" which should not be subscribed to the services.EventDispatcher
+func (container *Container) DisabledEventListeners() []string {
+	var disabled []string
+	for _, name := range strings.Split(os.Getenv("EVENT_LISTENERS_DISABLED"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled = append(disabled, name)
+		}
+	}
+	return disabled
+}
+
+// subscribe registers the routes of an event listener on the services.EventDispatcher
+func (container *Container) subscribe(listener any, routes map[string]events.EventListener) {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", listener), "*listeners.")
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(name, event, handler)
+	}
+}
+
+// Float64Histogram creates a new instance of metric.Float64Histogram
+func (container *Container) Float64Histogram(name, unit, description string) otelMetric.Float64Histogram {
+	container.logger.Debug("creating GORM repositories.MessageRepository")
+	meter := otel.GetMeterProvider().Meter(
+		container.projectID,
+		otelMetric.WithInstrumentationVersion(otel.Version()),
+	)
+	histogram, err := meter.Float64Histogram(name, otelMetric.WithUnit(unit), otelMetric.WithDescription(description))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create float64 histogram"))
+	}
+	return histogram
+}
+
+// MessageRepository creates a new instance of repositories.MessageRepository
+func (container *Container) MessageRepository() (repository repositories.MessageRepository) {
+	regionDBs := container.RegionDBs()
+	if len(regionDBs) == 0 {
+		container.logger.Debug("creating GORM repositories.MessageRepository")
+		return repositories.NewGormMessageRepository(
+			container.Logger(),
+			container.Tracer(),
+			container.DB(),
+		)
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating regional repositories.MessageRepository with [%d] regions", len(regionDBs)))
+	shards := make(map[string]repositories.MessageRepository, len(regionDBs))
+	for region, db := range regionDBs {
+		shards[region] = repositories.NewGormMessageRepository(container.Logger(), container.Tracer(), db)
+	}
+
+	return repositories.NewRegionalMessageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DataRegionRouter(),
+		repositories.NewGormMessageRepository(container.Logger(), container.Tracer(), container.DB()),
+		shards,
+	)
+}
+
+// Integration3CXRepository creates a new instance of repositories.Integration3CxRepository
+func (container *Container) Integration3CXRepository() (repository repositories.Integration3CxRepository) {
+	container.logger.Debug("creating GORM repositories.Integration3CxRepository")
+	return repositories.NewGormIntegration3CXRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
 	)
 }
 
@@ -695,6 +1924,56 @@ func (container *Container) PhoneRepository() (repository repositories.PhoneRepo
 	)
 }
 
+// ComplianceRuleRepository creates a new instance of repositories.ComplianceRuleRepository
+func (container *Container) ComplianceRuleRepository() (repository repositories.ComplianceRuleRepository) {
+	container.logger.Debug("creating GORM repositories.ComplianceRuleRepository")
+	return repositories.NewGormComplianceRuleRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// SuppressionRepository creates a new instance of repositories.SuppressionRepository
+func (container *Container) SuppressionRepository() (repository repositories.SuppressionRepository) {
+	container.logger.Debug("creating GORM repositories.SuppressionRepository")
+	return repositories.NewGormSuppressionRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// APIKeyRepository creates a new instance of repositories.APIKeyRepository
+func (container *Container) APIKeyRepository() (repository repositories.APIKeyRepository) {
+	container.logger.Debug("creating GORM repositories.APIKeyRepository")
+	return repositories.NewGormAPIKeyRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ContactPreferenceRepository creates a new instance of repositories.ContactPreferenceRepository
+func (container *Container) ContactPreferenceRepository() (repository repositories.ContactPreferenceRepository) {
+	container.logger.Debug("creating GORM repositories.ContactPreferenceRepository")
+	return repositories.NewGormContactPreferenceRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// CustomDomainRepository creates a new instance of repositories.CustomDomainRepository
+func (container *Container) CustomDomainRepository() (repository repositories.CustomDomainRepository) {
+	container.logger.Debug("creating GORM repositories.CustomDomainRepository")
+	return repositories.NewGormCustomDomainRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
 // BillingUsageRepository creates a new instance of repositories.BillingUsageRepository
 func (container *Container) BillingUsageRepository() (repository repositories.BillingUsageRepository) {
 	container.logger.Debug("creating GORM repositories.BillingUsageRepository")
@@ -715,6 +1994,126 @@ func (container *Container) DiscordRepository() (repository repositories.Discord
 	)
 }
 
+// AttachmentRepository creates a new instance of repositories.AttachmentRepository
+func (container *Container) AttachmentRepository() (repository repositories.AttachmentRepository) {
+	container.logger.Debug("creating GORM repositories.AttachmentRepository")
+	return repositories.NewGormAttachmentRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// NotificationChannelRepository creates a new instance of repositories.NotificationChannelRepository
+func (container *Container) NotificationChannelRepository() (repository repositories.NotificationChannelRepository) {
+	container.logger.Debug("creating GORM repositories.NotificationChannelRepository")
+	return repositories.NewGormNotificationChannelRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// InboxRuleRepository creates a new instance of repositories.InboxRuleRepository
+func (container *Container) InboxRuleRepository() (repository repositories.InboxRuleRepository) {
+	container.logger.Debug("creating GORM repositories.InboxRuleRepository")
+	return repositories.NewGormInboxRuleRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// RoutingRuleRepository creates a new instance of repositories.RoutingRuleRepository
+func (container *Container) RoutingRuleRepository() (repository repositories.RoutingRuleRepository) {
+	container.logger.Debug("creating GORM repositories.RoutingRuleRepository")
+	return repositories.NewGormRoutingRuleRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhonePoolRepository creates a new instance of repositories.PhonePoolRepository
+func (container *Container) PhonePoolRepository() (repository repositories.PhonePoolRepository) {
+	container.logger.Debug("creating GORM repositories.PhonePoolRepository")
+	return repositories.NewGormPhonePoolRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ReportRepository creates a new instance of repositories.ReportRepository
+func (container *Container) ReportRepository() (repository repositories.ReportRepository) {
+	container.logger.Debug("creating GORM repositories.ReportRepository")
+	return repositories.NewGormReportRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// DebugRequestRepository creates a new instance of repositories.DebugRequestRepository
+func (container *Container) DebugRequestRepository() (repository repositories.DebugRequestRepository) {
+	container.logger.Debug("creating GORM repositories.DebugRequestRepository")
+	return repositories.NewGormDebugRequestRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneCrashRepository creates a new instance of repositories.PhoneCrashRepository
+func (container *Container) PhoneCrashRepository() (repository repositories.PhoneCrashRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneCrashRepository")
+	return repositories.NewGormPhoneCrashRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneBalanceRepository creates a new instance of repositories.PhoneBalanceRepository
+func (container *Container) PhoneBalanceRepository() (repository repositories.PhoneBalanceRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneBalanceRepository")
+	return repositories.NewGormPhoneBalanceRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneMaintenanceWindowRepository creates a new instance of repositories.PhoneMaintenanceWindowRepository
+func (container *Container) PhoneMaintenanceWindowRepository() (repository repositories.PhoneMaintenanceWindowRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneMaintenanceWindowRepository")
+	return repositories.NewGormPhoneMaintenanceWindowRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneLogRepository creates a new instance of repositories.PhoneLogRepository
+func (container *Container) PhoneLogRepository() (repository repositories.PhoneLogRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneLogRepository")
+	return repositories.NewGormPhoneLogRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// CustomEventRepository creates a new instance of repositories.CustomEventRepository
+func (container *Container) CustomEventRepository() (repository repositories.CustomEventRepository) {
+	container.logger.Debug("creating GORM repositories.CustomEventRepository")
+	return repositories.NewGormCustomEventRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
 // WebhookRepository creates a new instance of repositories.WebhookRepository
 func (container *Container) WebhookRepository() (repository repositories.WebhookRepository) {
 	container.logger.Debug("creating GORM repositories.WebhookRepository")
@@ -725,6 +2124,16 @@ func (container *Container) WebhookRepository() (repository repositories.Webhook
 	)
 }
 
+// WebhookEventRepository creates a new instance of repositories.WebhookEventRepository
+func (container *Container) WebhookEventRepository() (repository repositories.WebhookEventRepository) {
+	container.logger.Debug("creating GORM repositories.WebhookEventRepository")
+	return repositories.NewGormWebhookEventRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
 // PhoneNotificationRepository creates a new instance of repositories.PhoneNotificationRepository
 func (container *Container) PhoneNotificationRepository() (repository repositories.PhoneNotificationRepository) {
 	container.logger.Debug("creating GORM repositories.PhoneNotificationRepository")
@@ -735,73 +2144,427 @@ func (container *Container) PhoneNotificationRepository() (repository repositori
 	)
 }
 
-// MessageThreadRepository creates a new instance of repositories.MessageThreadRepository
-func (container *Container) MessageThreadRepository() (repository repositories.MessageThreadRepository) {
-	container.logger.Debug("creating GORM repositories.MessageThreadRepository")
-	return repositories.NewGormMessageThreadRepository(
+// BackupRepository creates a new instance of repositories.BackupRepository
+func (container *Container) BackupRepository() (repository repositories.BackupRepository) {
+	container.logger.Debug("creating GORM repositories.BackupRepository")
+
+	databases := []repositories.GormBackupDatabase{
+		{DB: container.DB(), Models: append([]any{&entities.Message{}, &entities.MessageThread{}}, container.mainDBModels()...)},
+		{Name: "dedicated", DB: container.DedicatedDB(), Models: container.dedicatedDBModels()},
+	}
+	for region, db := range container.RegionDBs() {
+		databases = append(databases, repositories.GormBackupDatabase{Name: region, DB: db, Models: []any{&entities.Message{}, &entities.MessageThread{}}})
+	}
+
+	repository, err := repositories.NewGormBackupRepository(container.Logger(), container.Tracer(), databases)
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create GORM repositories.BackupRepository"))
+	}
+	return repository
+}
+
+// MessageThreadRepository creates a new instance of repositories.MessageThreadRepository
+func (container *Container) MessageThreadRepository() (repository repositories.MessageThreadRepository) {
+	regionDBs := container.RegionDBs()
+	if len(regionDBs) == 0 {
+		container.logger.Debug("creating GORM repositories.MessageThreadRepository")
+		return repositories.NewGormMessageThreadRepository(
+			container.Logger(),
+			container.Tracer(),
+			container.DB(),
+		)
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating regional repositories.MessageThreadRepository with [%d] regions", len(regionDBs)))
+	shards := make(map[string]repositories.MessageThreadRepository, len(regionDBs))
+	for region, db := range regionDBs {
+		shards[region] = repositories.NewGormMessageThreadRepository(container.Logger(), container.Tracer(), db)
+	}
+
+	return repositories.NewRegionalMessageThreadRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DataRegionRouter(),
+		repositories.NewGormMessageThreadRepository(container.Logger(), container.Tracer(), container.DB()),
+		shards,
+	)
+}
+
+// DataRegionRouter creates a new instance of repositories.DataRegionRouter
+func (container *Container) DataRegionRouter() (router repositories.DataRegionRouter) {
+	container.logger.Debug("creating GORM repositories.DataRegionRouter")
+	return repositories.NewGormDataRegionRouter(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.DataRegionRistrettoCache(),
+	)
+}
+
+// HeartbeatMonitorRepository creates a new instance of repositories.HeartbeatMonitorRepository
+func (container *Container) HeartbeatMonitorRepository() (repository repositories.HeartbeatMonitorRepository) {
+	container.logger.Debug("creating GORM repositories.HeartbeatMonitorRepository")
+	return repositories.NewGormHeartbeatMonitorRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DedicatedDB(),
+	)
+}
+
+// HeartbeatService creates a new instance of services.HeartbeatService
+func (container *Container) HeartbeatService() (service *services.HeartbeatService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewHeartbeatService(
+		container.Logger(),
+		container.Tracer(),
+		container.HeartbeatRepository(),
+		container.HeartbeatRollupRepository(),
+		container.HeartbeatMonitorRepository(),
+		container.PhoneRepository(),
+		container.UserRepository(),
+		container.PhoneMaintenanceWindowRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// BillingService creates a new instance of services.BillingService
+func (container *Container) BillingService() (service *services.BillingService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewBillingService(
+		container.Logger(),
+		container.Tracer(),
+		container.InMemoryCache(),
+		container.Mailer(),
+		container.UserEmailFactory(),
+		container.BillingUsageRepository(),
+		container.UserRepository(),
+	)
+}
+
+// DiscordService creates a new instance of services.DiscordService
+func (container *Container) DiscordService() (service *services.DiscordService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewDiscordService(
+		container.Logger(),
+		container.Tracer(),
+		container.DiscordClient(),
+		container.DiscordRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// WebhookService creates a new instance of services.WebhookService
+func (container *Container) WebhookService() (service *services.WebhookService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewWebhookService(
+		container.Logger(),
+		container.Tracer(),
+		container.EgressHTTPClient("webhook"),
+		container.WebhookRepository(),
+		container.MessageThreadRepository(),
+		container.WebhookEventRepository(),
+		container.EventDispatcher(),
+		container.WebhookDeliveryCounter(),
+		container.WebhookDeliveryQueue(),
+		container.FaultInjector(),
+		container.WebhookMaxFailures(),
+		container.WebhookEventRetention(),
+	)
+}
+
+// WebhookDeliveryQueue creates a new instance of services.WebhookDeliveryQueue which is shared by every services.WebhookService.
+// WEBHOOK_MAX_CONCURRENCY (default 100) limits the deliveries which run at the same time and WEBHOOK_MAX_CONCURRENCY_PER_DESTINATION
+// (default 10) limits the deliveries to the same host so that a slow receiver cannot take all the slots. A host holds at most
+// WEBHOOK_MAX_PENDING_PER_DESTINATION (default 1000) deliveries which are waiting and the other deliveries are dropped
+func (container *Container) WebhookDeliveryQueue() (queue *services.WebhookDeliveryQueue) {
+	if container.webhookQueue != nil {
+		return container.webhookQueue
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", queue))
+
+	concurrency, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_CONCURRENCY"), 10, 32)
+	if err != nil || concurrency == 0 {
+		concurrency = 100
+	}
+
+	perDestination, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_CONCURRENCY_PER_DESTINATION"), 10, 32)
+	if err != nil || perDestination == 0 {
+		perDestination = 10
+	}
+
+	pending, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_PENDING_PER_DESTINATION"), 10, 32)
+	if err != nil || pending == 0 {
+		pending = 1000
+	}
+
+	container.webhookQueue = services.NewWebhookDeliveryQueue(uint(concurrency), uint(perDestination), uint(pending))
+	return container.webhookQueue
+}
+
+// WebhookMaxFailures is the number of consecutive failures after which a webhook is paused which is configured with
+// WEBHOOK_MAX_CONSECUTIVE_FAILURES and defaults to 20. Webhooks are never paused automatically when it is set to 0
+func (container *Container) WebhookMaxFailures() uint {
+	failures, err := strconv.ParseUint(os.Getenv("WEBHOOK_MAX_CONSECUTIVE_FAILURES"), 10, 32)
+	if err != nil {
+		return 20
+	}
+	return uint(failures)
+}
+
+// WebhookEventRetention is the period for which the events sent to webhooks are stored so that they can be replayed which is
+// configured with WEBHOOK_EVENT_RETENTION e.g. "168h" and defaults to 7 days. The events are not stored when it is set to "0"
+func (container *Container) WebhookEventRetention() time.Duration {
+	value := os.Getenv("WEBHOOK_EVENT_RETENTION")
+	if value == "" {
+		return 7 * 24 * time.Hour
+	}
+
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention <= 0 {
+		return 0
+	}
+	return retention
+}
+
+// StartWebhookEventPruner deletes the webhook events which are older than the retention period every WEBHOOK_EVENT_PRUNER_INTERVAL
+// which defaults to "1h". The pruner is not started when the events are not stored
+func (container *Container) StartWebhookEventPruner() {
+	retention := container.WebhookEventRetention()
+	if retention == 0 {
+		return
+	}
+
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_EVENT_PRUNER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	container.logger.Info(fmt.Sprintf("pruning webhook events with retention [%s] every [%s]", retention, interval))
+	go container.WebhookService().Schedule(context.Background(), interval)
+}
+
+// AttachmentStorage creates a new instance of services.AttachmentStorage
+func (container *Container) AttachmentStorage() (attachmentStorage services.AttachmentStorage) {
+	if bucket := os.Getenv("ATTACHMENT_BUCKET"); bucket != "" {
+		container.logger.Debug(fmt.Sprintf("creating google cloud %T for bucket [%s]", &attachmentStorage, bucket))
+		return services.NewGoogleCloudAttachmentStorage(
+			container.Logger(),
+			container.Tracer(),
+			container.CloudStorageClient(),
+			bucket,
+			os.Getenv("ATTACHMENT_PREFIX"),
+		)
+	}
+
+	directory := os.Getenv("ATTACHMENT_DIRECTORY")
+	if directory == "" {
+		directory = "attachments"
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating file %T in directory [%s]", &attachmentStorage, directory))
+	return services.NewFileAttachmentStorage(container.Logger(), container.Tracer(), directory)
+}
+
+// AttachmentService creates a new instance of services.AttachmentService
+func (container *Container) AttachmentService() (service *services.AttachmentService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewAttachmentService(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentRepository(),
+		container.AttachmentStorage(),
+	)
+}
+
+// PhoneCrashService creates a new instance of services.PhoneCrashService
+func (container *Container) PhoneCrashService() (service *services.PhoneCrashService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewPhoneCrashService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneCrashRepository(),
+		container.PhoneRepository(),
+	)
+}
+
+// PhoneBalanceService creates a new instance of services.PhoneBalanceService
+func (container *Container) PhoneBalanceService() (service *services.PhoneBalanceService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewPhoneBalanceService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneBalanceRepository(),
+		container.PhoneRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// PhoneMaintenanceWindowService creates a new instance of services.PhoneMaintenanceWindowService
+func (container *Container) PhoneMaintenanceWindowService() (service *services.PhoneMaintenanceWindowService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewPhoneMaintenanceWindowService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneMaintenanceWindowRepository(),
+		container.PhoneRepository(),
+	)
+}
+
+// PhoneLogService creates a new instance of services.PhoneLogService
+func (container *Container) PhoneLogService() (service *services.PhoneLogService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewPhoneLogService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneLogRepository(),
+		container.PhoneRepository(),
+		container.AttachmentService(),
+		container.EventDispatcher(),
+	)
+}
+
+// NotificationChannelService creates a new instance of services.NotificationChannelService
+func (container *Container) NotificationChannelService() (service *services.NotificationChannelService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewNotificationChannelService(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.NotificationChannelRepository(),
+		container.Cache(),
+		os.Getenv("APP_URL"),
+		container.NotificationChannelSenders(),
 	)
 }
 
-// HeartbeatMonitorRepository creates a new instance of repositories.HeartbeatMonitorRepository
-func (container *Container) HeartbeatMonitorRepository() (repository repositories.HeartbeatMonitorRepository) {
-	container.logger.Debug("creating GORM repositories.HeartbeatMonitorRepository")
-	return repositories.NewGormHeartbeatMonitorRepository(
+// InboxRuleService creates a new instance of services.InboxRuleService
+func (container *Container) InboxRuleService() (service *services.InboxRuleService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewInboxRuleService(
 		container.Logger(),
 		container.Tracer(),
-		container.DedicatedDB(),
+		container.InboxRuleRepository(),
+		container.MessageThreadRepository(),
 	)
 }
 
-// HeartbeatService creates a new instance of services.HeartbeatService
-func (container *Container) HeartbeatService() (service *services.HeartbeatService) {
+// RoutingRuleService creates a new instance of services.RoutingRuleService
+func (container *Container) RoutingRuleService() (service *services.RoutingRuleService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewHeartbeatService(
+	return services.NewRoutingRuleService(
 		container.Logger(),
 		container.Tracer(),
-		container.HeartbeatRepository(),
-		container.HeartbeatMonitorRepository(),
-		container.EventDispatcher(),
+		container.RoutingRuleRepository(),
 	)
 }
 
-// BillingService creates a new instance of services.BillingService
-func (container *Container) BillingService() (service *services.BillingService) {
+// PhonePoolService creates a new instance of services.PhonePoolService
+func (container *Container) PhonePoolService() (service *services.PhonePoolService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewBillingService(
+	return services.NewPhonePoolService(
 		container.Logger(),
 		container.Tracer(),
-		container.InMemoryCache(),
-		container.Mailer(),
-		container.UserEmailFactory(),
-		container.BillingUsageRepository(),
-		container.UserRepository(),
+		container.PhonePoolRepository(),
 	)
 }
 
-// DiscordService creates a new instance of services.DiscordService
-func (container *Container) DiscordService() (service *services.DiscordService) {
+// NotificationChannelSenders creates the services.NotificationChannelSender for every supported chat platform
+func (container *Container) NotificationChannelSenders() []services.NotificationChannelSender {
+	container.logger.Debug("creating []services.NotificationChannelSender")
+	return []services.NotificationChannelSender{
+		services.NewTeamsNotificationChannelSender(container.Logger(), container.Tracer(), container.EgressHTTPClient("notification_channel")),
+		services.NewMattermostNotificationChannelSender(container.Logger(), container.Tracer(), container.EgressHTTPClient("notification_channel")),
+		services.NewRocketChatNotificationChannelSender(container.Logger(), container.Tracer(), container.EgressHTTPClient("notification_channel")),
+	}
+}
+
+// WebhookDeliveryCounter creates a new instance of services.WebhookDeliveryCounter which is shared by every services.WebhookService
+func (container *Container) WebhookDeliveryCounter() (counter *services.WebhookDeliveryCounter) {
+	if container.webhookCounter != nil {
+		return container.webhookCounter
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", counter))
+	container.webhookCounter = services.NewWebhookDeliveryCounter(container.Logger(), container.RedisClient())
+	return container.webhookCounter
+}
+
+// PhoneSendRateLimiter creates a new instance of services.PhoneSendRateLimiter which is shared by every services.MessageService
+func (container *Container) PhoneSendRateLimiter() (limiter *services.PhoneSendRateLimiter) {
+	if container.phoneSendRateLimiter != nil {
+		return container.phoneSendRateLimiter
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", limiter))
+	container.phoneSendRateLimiter = services.NewPhoneSendRateLimiter(container.RedisClient())
+	return container.phoneSendRateLimiter
+}
+
+// APIUsageCounter creates a new instance of services.APIUsageCounter which is shared by the middlewares.APIUsageRecorder and the services.APIUsageService
+func (container *Container) APIUsageCounter() (counter *services.APIUsageCounter) {
+	if container.apiUsageCounter != nil {
+		return container.apiUsageCounter
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", counter))
+	container.apiUsageCounter = services.NewAPIUsageCounter(container.Logger(), container.RedisClient())
+	return container.apiUsageCounter
+}
+
+// DeprecationCounter creates a new instance of services.DeprecationCounter which is shared by the middlewares.Deprecation and the services.MetricsService
+func (container *Container) DeprecationCounter() (counter *services.DeprecationCounter) {
+	if container.deprecations != nil {
+		return container.deprecations
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", counter))
+	container.deprecations = services.NewDeprecationCounter(container.Logger(), container.RedisClient())
+	return container.deprecations
+}
+
+// DeprecatedRoutes are the routes which are slated for removal. Add a route here with the time when it was deprecated and
+// the time after which it will be removed when a replacement e.g. a /v2 route is released.
+func (container *Container) DeprecatedRoutes() []entities.DeprecatedRoute {
+	return []entities.DeprecatedRoute{}
+}
+
+// APIUsageService creates a new instance of services.APIUsageService
+func (container *Container) APIUsageService() (service *services.APIUsageService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewDiscordService(
+	return services.NewAPIUsageService(
 		container.Logger(),
 		container.Tracer(),
-		container.DiscordClient(),
-		container.DiscordRepository(),
-		container.EventDispatcher(),
+		container.APIUsageCounter(),
+		container.UserRepository(),
+		container.BillingUsageRepository(),
 	)
 }
 
-// WebhookService creates a new instance of services.WebhookService
-func (container *Container) WebhookService() (service *services.WebhookService) {
+// PhoneSocketRegistry creates a new instance of services.PhoneSocketRegistry which is shared by the handlers.PhoneSocketHandler and the services.PhoneNotificationService
+func (container *Container) PhoneSocketRegistry() (registry *services.PhoneSocketRegistry) {
+	if container.socketRegistry != nil {
+		return container.socketRegistry
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", registry))
+	container.socketRegistry = services.NewPhoneSocketRegistry(container.Logger(), container.RedisClient())
+	return container.socketRegistry
+}
+
+// MetricsService creates a new instance of services.MetricsService
+func (container *Container) MetricsService() (service *services.MetricsService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewWebhookService(
+	return services.NewMetricsService(
 		container.Logger(),
 		container.Tracer(),
-		container.HTTPClient("webhook"),
-		container.WebhookRepository(),
-		container.EventDispatcher(),
+		container.MessageRepository(),
+		container.HeartbeatRepository(),
+		container.PhoneNotificationRepository(),
+		container.PhoneCrashRepository(),
+		container.BillingUsageRepository(),
+		container.WebhookDeliveryCounter(),
+		container.MessagePruneCounter(),
+		container.DeprecationCounter(),
 	)
 }
 
@@ -825,6 +2588,41 @@ func (container *Container) HTTPClient(name string) *http.Client {
 	}
 }
 
+// EgressHTTPClient creates a new http.Client for the webhook and callback URLs of users which can only send requests to
+// public addresses unless WEBHOOK_ALLOW_PRIVATE_NETWORKS is "true" and which uses WEBHOOK_EGRESS_PROXY_URL as the proxy when it is set
+func (container *Container) EgressHTTPClient(name string) *http.Client {
+	container.logger.Debug(fmt.Sprintf("creating egress %s %T", name, http.DefaultClient))
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = container.Logger()
+	retryClient.HTTPClient.Transport = container.EgressGuard().Transport()
+
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: otelroundtripper.New(
+			otelroundtripper.WithName(name),
+			otelroundtripper.WithParent(retryClient.StandardClient().Transport),
+			otelroundtripper.WithMeter(otel.GetMeterProvider().Meter(container.projectID)),
+			otelroundtripper.WithAttributes(container.OtelResources(container.version, container.projectID).Attributes()...),
+		),
+	}
+}
+
+// EgressGuard creates a new instance of services.EgressGuard
+func (container *Container) EgressGuard() (guard *services.EgressGuard) {
+	container.logger.Debug(fmt.Sprintf("creating %T", guard))
+
+	var proxy *url.URL
+	if value := strings.TrimSpace(os.Getenv("WEBHOOK_EGRESS_PROXY_URL")); value != "" {
+		parsed, err := url.Parse(value)
+		if err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, fmt.Sprintf("cannot parse egress proxy url [%s]", value)))
+		}
+		proxy = parsed
+	}
+
+	return services.NewEgressGuard(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true", proxy)
+}
+
 // HTTPRoundTripper creates an open telemetry http.RoundTripper
 func (container *Container) HTTPRoundTripper(name string) http.RoundTripper {
 	container.logger.Debug(fmt.Sprintf("Debug: initializing %s %T", name, http.DefaultTransport))
@@ -862,10 +2660,170 @@ func (container *Container) PhoneService() (service *services.PhoneService) {
 		container.Logger(),
 		container.Tracer(),
 		container.PhoneRepository(),
+		container.MessageRepository(),
+		container.WebhookRepository(),
 		container.EventDispatcher(),
 	)
 }
 
+// ComplianceService creates a new instance of services.ComplianceService
+func (container *Container) ComplianceService() (service *services.ComplianceService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewComplianceService(
+		container.Logger(),
+		container.Tracer(),
+		container.ComplianceRuleRepository(),
+	)
+}
+
+// SuppressionService creates a new instance of services.SuppressionService
+func (container *Container) SuppressionService() (service *services.SuppressionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewSuppressionService(
+		container.Logger(),
+		container.Tracer(),
+		container.SuppressionRepository(),
+	)
+}
+
+// APIKeyService creates a new instance of services.APIKeyService
+func (container *Container) APIKeyService() (service *services.APIKeyService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewAPIKeyService(
+		container.Logger(),
+		container.Tracer(),
+		container.APIKeyRepository(),
+	)
+}
+
+// ContactPreferenceService creates a new instance of services.ContactPreferenceService
+func (container *Container) ContactPreferenceService() (service *services.ContactPreferenceService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewContactPreferenceService(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactPreferenceRepository(),
+	)
+}
+
+// CustomDomainService creates a new instance of services.CustomDomainService
+func (container *Container) CustomDomainService() (service *services.CustomDomainService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewCustomDomainService(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomDomainRepository(),
+	)
+}
+
+// Translator creates a new instance of services.Translator for the TRANSLATION_PROVIDER which is either "google" or "deepl"
+func (container *Container) Translator() (translator services.Translator) {
+	switch provider := os.Getenv("TRANSLATION_PROVIDER"); provider {
+	case "google":
+		container.logger.Debug(fmt.Sprintf("creating google %T", &translator))
+		return services.NewGoogleTranslator(
+			container.Logger(),
+			container.Tracer(),
+			container.HTTPClient("translator"),
+			os.Getenv("GOOGLE_TRANSLATE_API_KEY"),
+		)
+	case "deepl":
+		container.logger.Debug(fmt.Sprintf("creating deepl %T", &translator))
+		return services.NewDeepLTranslator(
+			container.Logger(),
+			container.Tracer(),
+			container.HTTPClient("translator"),
+			os.Getenv("DEEPL_API_KEY"),
+		)
+	case "":
+		return nil
+	default:
+		container.logger.Fatal(stacktrace.NewError(fmt.Sprintf("invalid TRANSLATION_PROVIDER [%s]", provider)))
+		return nil
+	}
+}
+
+// LLMProvider creates a new instance of services.LLMProvider for the LLM_PROVIDER which can be "openai"
+func (container *Container) LLMProvider() (provider services.LLMProvider) {
+	switch name := os.Getenv("LLM_PROVIDER"); name {
+	case "openai":
+		container.logger.Debug(fmt.Sprintf("creating openai %T", &provider))
+		return services.NewOpenAILLMProvider(
+			container.Logger(),
+			container.Tracer(),
+			container.HTTPClient("llm"),
+			os.Getenv("OPENAI_API_KEY"),
+			os.Getenv("OPENAI_MODEL"),
+			os.Getenv("OPENAI_BASE_URL"),
+		)
+	case "":
+		return nil
+	default:
+		container.logger.Fatal(stacktrace.NewError(fmt.Sprintf("invalid LLM_PROVIDER [%s]", name)))
+		return nil
+	}
+}
+
+// MessageThreadClassifier creates the services.MessageThreadClassifier which is configured with MESSAGE_THREAD_CLASSIFIER.
+// It returns nil when MESSAGE_THREAD_CLASSIFIER is not set so that threads are not classified.
+func (container *Container) MessageThreadClassifier() (classifier services.MessageThreadClassifier) {
+	switch name := os.Getenv("MESSAGE_THREAD_CLASSIFIER"); name {
+	case "rules":
+		container.logger.Debug(fmt.Sprintf("creating rules %T", &classifier))
+		return services.NewRulesMessageThreadClassifier(container.Logger(), container.Tracer())
+	case "llm":
+		provider := container.LLMProvider()
+		if provider == nil {
+			container.logger.Fatal(stacktrace.NewError("LLM_PROVIDER must be set when MESSAGE_THREAD_CLASSIFIER is [llm]"))
+			return nil
+		}
+		container.logger.Debug(fmt.Sprintf("creating llm %T", &classifier))
+		return services.NewLLMMessageThreadClassifier(container.Logger(), container.Tracer(), provider)
+	case "":
+		return nil
+	default:
+		container.logger.Fatal(stacktrace.NewError(fmt.Sprintf("invalid MESSAGE_THREAD_CLASSIFIER [%s]", name)))
+		return nil
+	}
+}
+
+// MessageThreadClassificationService creates a new instance of services.MessageThreadClassificationService
+func (container *Container) MessageThreadClassificationService(classifier services.MessageThreadClassifier) (service *services.MessageThreadClassificationService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageThreadClassificationService(
+		container.Logger(),
+		container.Tracer(),
+		classifier,
+		container.MessageThreadRepository(),
+		container.MessageRepository(),
+	)
+}
+
+// MessageThreadSummaryService creates a new instance of services.MessageThreadSummaryService
+func (container *Container) MessageThreadSummaryService(provider services.LLMProvider) (service *services.MessageThreadSummaryService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageThreadSummaryService(
+		container.Logger(),
+		container.Tracer(),
+		provider,
+		container.MessageThreadRepository(),
+		container.MessageRepository(),
+		container.Cache(),
+	)
+}
+
+// TranslationService creates a new instance of services.TranslationService
+func (container *Container) TranslationService(translator services.Translator) (service *services.TranslationService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewTranslationService(
+		container.Logger(),
+		container.Tracer(),
+		translator,
+		container.UserRepository(),
+		container.MessageRepository(),
+	)
+}
+
 // MarketingService creates a new instance of services.MarketingService
 func (container *Container) MarketingService() (service *services.MarketingService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -937,10 +2895,25 @@ func (container *Container) MessageThreadService() (service *services.MessageThr
 		container.Logger(),
 		container.Tracer(),
 		container.MessageThreadRepository(),
+		container.MessageRepository(),
+		container.WebhookRepository(),
 		container.EventDispatcher(),
 	)
 }
 
+// MessageThreadShareService creates a new instance of services.MessageThreadShareService
+func (container *Container) MessageThreadShareService() (service *services.MessageThreadShareService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageThreadShareService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageThreadRepository(),
+		container.MessageRepository(),
+		container.CustomDomainService(),
+		os.Getenv("SHARE_LINK_SIGNING_KEY"),
+	)
+}
+
 // EmailNotificationService creates a new instance of services.EmailNotificationService
 func (container *Container) EmailNotificationService() (service *services.EmailNotificationService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -962,10 +2935,77 @@ func (container *Container) MessageHandler() (handler *handlers.MessageHandler)
 		container.Tracer(),
 		container.MessageHandlerValidator(),
 		container.BillingService(),
+		container.MessageSendService(),
 		container.MessageService(),
 	)
 }
 
+// StatisticsHandler creates a new instance of handlers.StatisticsHandler
+func (container *Container) StatisticsHandler() (h *handlers.StatisticsHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewStatisticsHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.StatisticsHandlerValidator(),
+		container.StatisticsService(),
+		container.PhoneService(),
+	)
+}
+
+// StatisticsHandlerValidator creates a new instance of validators.StatisticsHandlerValidator
+func (container *Container) StatisticsHandlerValidator() (validator *validators.StatisticsHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewStatisticsHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// StatisticsService creates a new instance of services.StatisticsService
+func (container *Container) StatisticsService() (service *services.StatisticsService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewStatisticsService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageRepository(),
+		container.HeartbeatRepository(),
+		container.PhoneMaintenanceWindowRepository(),
+	)
+}
+
+// HomeAssistantHandler creates a new instance of handlers.HomeAssistantHandler
+func (container *Container) HomeAssistantHandler() (h *handlers.HomeAssistantHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewHomeAssistantHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.HomeAssistantHandlerValidator(),
+		container.HomeAssistantService(),
+		container.MessageSendService(),
+		container.MessageHandlerValidator(),
+	)
+}
+
+// HomeAssistantHandlerValidator creates a new instance of validators.HomeAssistantHandlerValidator
+func (container *Container) HomeAssistantHandlerValidator() (validator *validators.HomeAssistantHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewHomeAssistantHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// HomeAssistantService creates a new instance of services.HomeAssistantService
+func (container *Container) HomeAssistantService() (service *services.HomeAssistantService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewHomeAssistantService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneRepository(),
+		container.MessageRepository(),
+	)
+}
+
 // BulkMessageHandler creates a new instance of handlers.BulkMessageHandler
 func (container *Container) BulkMessageHandler() (handler *handlers.BulkMessageHandler) {
 	container.logger.Debug(fmt.Sprintf("creating %T", handler))
@@ -1015,15 +3055,13 @@ func (container *Container) EventsHandler() (handler *handlers.EventsHandler) {
 // RegisterMessageListeners registers event listeners for listeners.MessageListener
 func (container *Container) RegisterMessageListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.MessageListener{}))
-	_, routes := listeners.NewMessageListener(
+	listener, routes := listeners.NewMessageListener(
 		container.Logger(),
 		container.Tracer(),
 		container.MessageService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // LemonsqueezyService creates a new instance of services.LemonsqueezyService
@@ -1061,49 +3099,164 @@ func (container *Container) Integration3CXHandler() (handler *handlers.Integrati
 	)
 }
 
-// DiscordHandler creates a new instance of handlers.DiscordHandler
-func (container *Container) DiscordHandler() (handler *handlers.DiscordHandler) {
-	container.logger.Debug(fmt.Sprintf("creating %T", handler))
-
-	return handlers.NewDiscordHandler(
+// DiscordHandler creates a new instance of handlers.DiscordHandler
+func (container *Container) DiscordHandler() (handler *handlers.DiscordHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+
+	return handlers.NewDiscordHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.DiscordHandlerValidator(),
+		container.DiscordService(),
+		container.MessageService(),
+		container.BillingService(),
+		container.MessageHandlerValidator(),
+	)
+}
+
+// LemonsqueezyHandlerValidator creates a new instance of validators.LemonsqueezyHandlerValidator
+func (container *Container) LemonsqueezyHandlerValidator() (validator *validators.LemonsqueezyHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewLemonsqueezyHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.LemonsqueezyClient(),
+	)
+}
+
+// LemonsqueezyClient creates a new instance of lemonsqueezy.Client
+func (container *Container) LemonsqueezyClient() (client *lemonsqueezy.Client) {
+	container.logger.Debug(fmt.Sprintf("creating %T", client))
+	return lemonsqueezy.New(
+		lemonsqueezy.WithHTTPClient(container.HTTPClient("lemonsqueezy")),
+		lemonsqueezy.WithAPIKey(os.Getenv("LEMONSQUEEZY_API_KEY")),
+		lemonsqueezy.WithSigningSecret(os.Getenv("LEMONSQUEEZY_SIGNING_SECRET")),
+	)
+}
+
+// DiscordClient creates a new instance of discord.Client
+func (container *Container) DiscordClient() (client *discord.Client) {
+	container.logger.Debug(fmt.Sprintf("creating %T", client))
+	return discord.New(
+		discord.WithHTTPClient(container.HTTPClient("discord")),
+		discord.WithApplicationID(os.Getenv("DISCORD_APPLICATION_ID")),
+		discord.WithBotToken(os.Getenv("DISCORD_BOT_TOKEN")),
+	)
+}
+
+// MQTTClient creates a new mqtt.Client which connects to the broker in MQTT_BROKER_URL
+func (container *Container) MQTTClient() mqtt.Client {
+	if container.mqttClient != nil {
+		return container.mqttClient
+	}
+
+	clientID := os.Getenv("MQTT_CLIENT_ID")
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = "httpsms-" + hostname
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating mqtt.Client with ID [%s]", clientID))
+	options := mqtt.NewClientOptions().
+		AddBroker(os.Getenv("MQTT_BROKER_URL")).
+		SetClientID(clientID).
+		SetUsername(os.Getenv("MQTT_USERNAME")).
+		SetPassword(os.Getenv("MQTT_PASSWORD")).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(func(_ mqtt.Client) {
+			// subscriptions are lost when the connection drops, so we subscribe every time we are connected
+			if err := container.MQTTBridgeHandler().Subscribe(context.Background()); err != nil {
+				container.logger.Error(stacktrace.Propagate(err, "cannot subscribe to the MQTT broker"))
+			}
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			container.logger.Warn(stacktrace.Propagate(err, "lost connection to the MQTT broker"))
+		})
+
+	container.mqttClient = mqtt.NewClient(options)
+	return container.mqttClient
+}
+
+// MQTTService creates a new instance of services.MQTTService
+func (container *Container) MQTTService() (service *services.MQTTService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+
+	prefix := os.Getenv("MQTT_TOPIC_PREFIX")
+	if prefix == "" {
+		prefix = "httpsms"
+	}
+
+	return services.NewMQTTService(
+		container.Logger(),
+		container.Tracer(),
+		container.MQTTClient(),
+		container.UserRepository(),
+		prefix,
+		os.Getenv("MQTT_SHARED_SUBSCRIPTION_GROUP"),
+		os.Getenv("MQTT_USERNAME"),
+		os.Getenv("MQTT_PASSWORD"),
+	)
+}
+
+// MQTTAuthHandler creates a new instance of handlers.MQTTAuthHandler
+func (container *Container) MQTTAuthHandler() (h *handlers.MQTTAuthHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMQTTAuthHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DiscordHandlerValidator(),
-		container.DiscordService(),
-		container.MessageService(),
-		container.BillingService(),
-		container.MessageHandlerValidator(),
+		container.MQTTService(),
 	)
 }
 
-// LemonsqueezyHandlerValidator creates a new instance of validators.LemonsqueezyHandlerValidator
-func (container *Container) LemonsqueezyHandlerValidator() (validator *validators.LemonsqueezyHandlerValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewLemonsqueezyHandlerValidator(
+// RegisterMQTTRoutes registers routes for the /mqtt prefix which are used by the broker to authenticate the clients
+func (container *Container) RegisterMQTTRoutes() {
+	if os.Getenv("MQTT_BROKER_URL") == "" {
+		return
+	}
+
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MQTTAuthHandler{}))
+	container.MQTTAuthHandler().RegisterRoutes(container.App())
+}
+
+// MQTTBridgeHandler creates a new instance of handlers.MQTTBridgeHandler
+func (container *Container) MQTTBridgeHandler() (h *handlers.MQTTBridgeHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMQTTBridgeHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.LemonsqueezyClient(),
+		container.UserRepository(),
+		container.MQTTService(),
+		container.MessageSendService(),
+		container.MessageHandlerValidator(),
+		container.HeartbeatPacketHandler(),
 	)
 }
 
-// LemonsqueezyClient creates a new instance of lemonsqueezy.Client
-func (container *Container) LemonsqueezyClient() (client *lemonsqueezy.Client) {
-	container.logger.Debug(fmt.Sprintf("creating %T", client))
-	return lemonsqueezy.New(
-		lemonsqueezy.WithHTTPClient(container.HTTPClient("lemonsqueezy")),
-		lemonsqueezy.WithAPIKey(os.Getenv("LEMONSQUEEZY_API_KEY")),
-		lemonsqueezy.WithSigningSecret(os.Getenv("LEMONSQUEEZY_SIGNING_SECRET")),
-	)
+// StartMQTTBridge connects to the MQTT broker when MQTT_BROKER_URL is set
+func (container *Container) StartMQTTBridge() {
+	if os.Getenv("MQTT_BROKER_URL") == "" {
+		return
+	}
+
+	container.logger.Info(fmt.Sprintf("connecting to the MQTT broker [%s]", os.Getenv("MQTT_BROKER_URL")))
+	container.MQTTClient().Connect()
 }
 
-// DiscordClient creates a new instance of discord.Client
-func (container *Container) DiscordClient() (client *discord.Client) {
-	container.logger.Debug(fmt.Sprintf("creating %T", client))
-	return discord.New(
-		discord.WithHTTPClient(container.HTTPClient("discord")),
-		discord.WithApplicationID(os.Getenv("DISCORD_APPLICATION_ID")),
-		discord.WithBotToken(os.Getenv("DISCORD_BOT_TOKEN")),
+// RegisterMQTTListeners registers event listeners for listeners.MQTTListener
+func (container *Container) RegisterMQTTListeners() {
+	if os.Getenv("MQTT_BROKER_URL") == "" {
+		return
+	}
+
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MQTTListener{}))
+	listener, routes := listeners.NewMQTTListener(
+		container.Logger(),
+		container.Tracer(),
+		container.MQTTService(),
 	)
+
+	container.subscribe(listener, routes)
 }
 
 // RegisterLemonsqueezyRoutes registers routes for the /lemonsqueezy prefix
@@ -1127,141 +3280,312 @@ func (container *Container) RegisterDiscordRoutes() {
 // RegisterMessageThreadListeners registers event listeners for listeners.MessageThreadListener
 func (container *Container) RegisterMessageThreadListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.MessageThreadListener{}))
-	_, routes := listeners.NewMessageThreadListener(
+	listener, routes := listeners.NewMessageThreadListener(
 		container.Logger(),
 		container.Tracer(),
 		container.MessageThreadService(),
+		container.InboxRuleService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterEmailNotificationListeners registers event listeners for listeners.EmailNotificationListener
 func (container *Container) RegisterEmailNotificationListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.EmailNotificationListener{}))
-	_, routes := listeners.NewEmailNotificationListener(
+	listener, routes := listeners.NewEmailNotificationListener(
 		container.Logger(),
 		container.Tracer(),
 		container.EmailNotificationService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterNotificationListeners registers event listeners for listeners.PhoneNotificationListener
 func (container *Container) RegisterNotificationListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.PhoneNotificationListener{}))
-	_, routes := listeners.NewNotificationListener(
+	listener, routes := listeners.NewNotificationListener(
 		container.Logger(),
 		container.Tracer(),
 		container.NotificationService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterHeartbeatListeners registers event listeners for listeners.HeartbeatListener
 func (container *Container) RegisterHeartbeatListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.HeartbeatListener{}))
-	_, routes := listeners.NewHeartbeatListener(
+	listener, routes := listeners.NewHeartbeatListener(
 		container.Logger(),
 		container.Tracer(),
 		container.HeartbeatService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterUserListeners registers event listeners for listeners.UserListener
 func (container *Container) RegisterUserListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listners for %T", listeners.UserListener{}))
-	_, routes := listeners.NewUserListener(
+	listener, routes := listeners.NewUserListener(
 		container.Logger(),
 		container.Tracer(),
 		container.UserService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterBillingListeners registers event listeners for listeners.BillingListener
 func (container *Container) RegisterBillingListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.BillingListener{}))
-	_, routes := listeners.NewBillingListener(
+	listener, routes := listeners.NewBillingListener(
 		container.Logger(),
 		container.Tracer(),
 		container.BillingService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterDiscordListeners registers event listeners for listeners.DiscordListener
 func (container *Container) RegisterDiscordListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.DiscordListener{}))
-	_, routes := listeners.NewDiscordListener(
+	listener, routes := listeners.NewDiscordListener(
 		container.Logger(),
 		container.Tracer(),
 		container.DiscordService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterMarketingListeners registers event listeners for listeners.MarketingListener
 func (container *Container) RegisterMarketingListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MarketingListener{}))
-	_, routes := listeners.NewMarketingListener(
+	listener, routes := listeners.NewMarketingListener(
 		container.Logger(),
 		container.Tracer(),
 		container.MarketingService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
+	container.subscribe(listener, routes)
+}
+
+// RegisterSuppressionListeners registers event listeners for listeners.SuppressionListener
+func (container *Container) RegisterSuppressionListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.SuppressionListener{}))
+	listener, routes := listeners.NewSuppressionListener(
+		container.Logger(),
+		container.Tracer(),
+		container.SuppressionService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterAPIKeyListeners registers event listeners for listeners.APIKeyListener
+func (container *Container) RegisterAPIKeyListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.APIKeyListener{}))
+	listener, routes := listeners.NewAPIKeyListener(
+		container.Logger(),
+		container.Tracer(),
+		container.APIKeyService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterMessageThreadClassificationListeners registers event listeners for listeners.MessageThreadClassificationListener if MESSAGE_THREAD_CLASSIFIER is set
+func (container *Container) RegisterMessageThreadClassificationListeners() {
+	classifier := container.MessageThreadClassifier()
+	if classifier == nil {
+		return
 	}
+
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MessageThreadClassificationListener{}))
+	listener, routes := listeners.NewMessageThreadClassificationListener(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageThreadClassificationService(classifier),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterCustomDomainListeners registers event listeners for listeners.CustomDomainListener
+func (container *Container) RegisterCustomDomainListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.CustomDomainListener{}))
+	listener, routes := listeners.NewCustomDomainListener(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomDomainService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterContactPreferenceListeners registers event listeners for listeners.ContactPreferenceListener
+func (container *Container) RegisterContactPreferenceListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.ContactPreferenceListener{}))
+	listener, routes := listeners.NewContactPreferenceListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactPreferenceService(),
+	)
+
+	container.subscribe(listener, routes)
 }
 
 // RegisterIntegration3CXListeners registers event listeners for listeners.Integration3CXListener
 func (container *Container) RegisterIntegration3CXListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.Integration3CXListener{}))
-	_, routes := listeners.NewIntegration3CXListener(
+	listener, routes := listeners.NewIntegration3CXListener(
 		container.Logger(),
 		container.Tracer(),
 		container.Integration3CXService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
-	}
+	container.subscribe(listener, routes)
 }
 
 // RegisterWebhookListeners registers event listeners for listeners.WebhookListener
 func (container *Container) RegisterWebhookListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.WebhookListener{}))
-	_, routes := listeners.NewWebhookListener(
+	listener, routes := listeners.NewWebhookListener(
 		container.Logger(),
 		container.Tracer(),
 		container.WebhookService(),
 	)
 
-	for event, handler := range routes {
-		container.EventDispatcher().Subscribe(event, handler)
+	container.subscribe(listener, routes)
+}
+
+// RegisterTranslationListeners registers event listeners for listeners.TranslationListener if TRANSLATION_PROVIDER is set
+func (container *Container) RegisterTranslationListeners() {
+	translator := container.Translator()
+	if translator == nil {
+		return
 	}
+
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.TranslationListener{}))
+	listener, routes := listeners.NewTranslationListener(
+		container.Logger(),
+		container.Tracer(),
+		container.TranslationService(translator),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterAttachmentListeners registers event listeners for listeners.AttachmentListener
+func (container *Container) RegisterAttachmentListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.AttachmentListener{}))
+	listener, routes := listeners.NewAttachmentListener(
+		container.Logger(),
+		container.Tracer(),
+		container.AttachmentService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterPhoneCrashListeners registers event listeners for listeners.PhoneCrashListener
+func (container *Container) RegisterPhoneCrashListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhoneCrashListener{}))
+	listener, routes := listeners.NewPhoneCrashListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneCrashService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterPhoneBalanceListeners registers event listeners for listeners.PhoneBalanceListener
+func (container *Container) RegisterPhoneBalanceListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhoneBalanceListener{}))
+	listener, routes := listeners.NewPhoneBalanceListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneBalanceService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterPhoneMaintenanceWindowListeners registers event listeners for listeners.PhoneMaintenanceWindowListener
+func (container *Container) RegisterPhoneMaintenanceWindowListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhoneMaintenanceWindowListener{}))
+	listener, routes := listeners.NewPhoneMaintenanceWindowListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneMaintenanceWindowService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterPhoneLogListeners registers event listeners for listeners.PhoneLogListener
+func (container *Container) RegisterPhoneLogListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhoneLogListener{}))
+	listener, routes := listeners.NewPhoneLogListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneLogService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterNotificationChannelListeners registers event listeners for listeners.NotificationChannelListener
+func (container *Container) RegisterNotificationChannelListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.NotificationChannelListener{}))
+	listener, routes := listeners.NewNotificationChannelListener(
+		container.Logger(),
+		container.Tracer(),
+		container.NotificationChannelService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterInboxRuleListeners registers event listeners for listeners.InboxRuleListener
+func (container *Container) RegisterInboxRuleListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.InboxRuleListener{}))
+	listener, routes := listeners.NewInboxRuleListener(
+		container.Logger(),
+		container.Tracer(),
+		container.InboxRuleService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterRoutingRuleListeners registers event listeners for listeners.RoutingRuleListener
+func (container *Container) RegisterRoutingRuleListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.RoutingRuleListener{}))
+	listener, routes := listeners.NewRoutingRuleListener(
+		container.Logger(),
+		container.Tracer(),
+		container.RoutingRuleService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterPhonePoolListeners registers event listeners for listeners.PhonePoolListener
+func (container *Container) RegisterPhonePoolListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhonePoolListener{}))
+	listener, routes := listeners.NewPhonePoolListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhonePoolService(),
+	)
+
+	container.subscribe(listener, routes)
 }
 
 // MessageService creates a new instance of services.MessageService
@@ -1271,11 +3595,40 @@ func (container *Container) MessageService() (service *services.MessageService)
 		container.Logger(),
 		container.Tracer(),
 		container.MessageRepository(),
+		container.MessageThreadRepository(),
+		container.RoutingRuleRepository(),
+		container.PhonePoolRepository(),
+		container.ContactPreferenceRepository(),
 		container.EventDispatcher(),
 		container.PhoneService(),
+		container.Translator(),
+		container.PhoneSendRateLimiter(),
+		container.MessageRetryBackoff(),
+	)
+}
+
+// MessageSendService creates a new instance of services.MessageSendService
+func (container *Container) MessageSendService() (service *services.MessageSendService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageSendService(
+		container.Logger(),
+		container.Tracer(),
+		container.UserService(),
+		container.BillingService(),
+		container.MessageService(),
 	)
 }
 
+// MessageRetryBackoff is the time to wait before retrying the first send attempt of a message which failed because of a
+// temporary error. It is configured with MESSAGE_RETRY_BACKOFF which defaults to "30s"
+func (container *Container) MessageRetryBackoff() time.Duration {
+	backoff, err := time.ParseDuration(os.Getenv("MESSAGE_RETRY_BACKOFF"))
+	if err != nil || backoff <= 0 {
+		return 30 * time.Second
+	}
+	return backoff
+}
+
 // NotificationService creates a new instance of services.PhoneNotificationService
 func (container *Container) NotificationService() (service *services.PhoneNotificationService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -1283,8 +3636,10 @@ func (container *Container) NotificationService() (service *services.PhoneNotifi
 		container.Logger(),
 		container.Tracer(),
 		container.FirebaseMessagingClient(),
+		container.EgressHTTPClient("unified_push"),
 		container.PhoneRepository(),
 		container.PhoneNotificationRepository(),
+		container.PhoneSocketRegistry(),
 		container.EventDispatcher(),
 	)
 }
@@ -1307,6 +3662,111 @@ func (container *Container) RegisterMessageThreadRoutes() {
 	container.MessageThreadHandler().RegisterRoutes(container.AuthRouter())
 }
 
+// RegisterMessageThreadSummaryRoutes registers routes for the summaries of message threads if LLM_PROVIDER is set
+func (container *Container) RegisterMessageThreadSummaryRoutes() {
+	provider := container.LLMProvider()
+	if provider == nil {
+		return
+	}
+
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageThreadSummaryHandler{}))
+	container.MessageThreadSummaryHandler(provider).RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterMessageThreadShareRoutes registers routes for the /shared prefix
+func (container *Container) RegisterMessageThreadShareRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageThreadShareHandler{}))
+	container.MessageThreadShareHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterReportRoutes registers routes for the /reports prefix
+func (container *Container) RegisterReportRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ReportHandler{}))
+	container.ReportHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterReportListeners registers event listeners for listeners.ReportListener
+func (container *Container) RegisterReportListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.ReportListener{}))
+	listener, routes := listeners.NewReportListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ReportService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterCustomEventRoutes registers routes for the /events prefix
+func (container *Container) RegisterCustomEventRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.CustomEventHandler{}))
+	container.CustomEventHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterCustomEventListeners registers event listeners for listeners.CustomEventListener
+func (container *Container) RegisterCustomEventListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.CustomEventListener{}))
+	listener, routes := listeners.NewCustomEventListener(
+		container.Logger(),
+		container.Tracer(),
+		container.CustomEventService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// RegisterDebugRequestRoutes registers routes for the /debug prefix
+func (container *Container) RegisterDebugRequestRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.DebugRequestHandler{}))
+	container.DebugRequestHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterDebugRequestListeners registers event listeners for listeners.DebugRequestListener
+func (container *Container) RegisterDebugRequestListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.DebugRequestListener{}))
+	listener, routes := listeners.NewDebugRequestListener(
+		container.Logger(),
+		container.Tracer(),
+		container.DebugRequestService(),
+	)
+
+	container.subscribe(listener, routes)
+}
+
+// MetricsHandler creates a new instance of handlers.MetricsHandler
+func (container *Container) MetricsHandler() (h *handlers.MetricsHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMetricsHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MetricsService(),
+		os.Getenv("METRICS_BEARER_TOKEN"),
+	)
+}
+
+// RegisterMetricsRoutes registers routes for the /metrics prefix
+func (container *Container) RegisterMetricsRoutes() {
+	if os.Getenv("METRICS_BEARER_TOKEN") == "" {
+		container.logger.Info("skipping metrics routes because [METRICS_BEARER_TOKEN] is not set")
+		return
+	}
+
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MetricsHandler{}))
+	container.MetricsHandler().RegisterRoutes(container.App())
+}
+
+// RegisterStatisticsRoutes registers routes for the /statistics prefix
+func (container *Container) RegisterStatisticsRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.StatisticsHandler{}))
+	container.StatisticsHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterHomeAssistantRoutes registers routes for the /home-assistant prefix
+func (container *Container) RegisterHomeAssistantRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.HomeAssistantHandler{}))
+	container.HomeAssistantHandler().RegisterRoutes(container.AuthRouter())
+}
+
 // RegisterHeartbeatRoutes registers routes for the /heartbeats prefix
 func (container *Container) RegisterHeartbeatRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.HeartbeatHandler{}))
@@ -1319,18 +3779,102 @@ func (container *Container) RegisterBillingRoutes() {
 	container.BillingHandler().RegisterRoutes(container.AuthRouter())
 }
 
+// RegisterAPIUsageRoutes registers routes for the /usage prefix
+func (container *Container) RegisterAPIUsageRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.APIUsageHandler{}))
+	container.APIUsageHandler().RegisterRoutes(container.AuthRouter())
+}
+
 // RegisterWebhookRoutes registers routes for the /webhooks prefix
 func (container *Container) RegisterWebhookRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.WebhookHandler{}))
 	container.WebhookHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
 }
 
+// RegisterNotificationChannelRoutes registers routes for the /notification-channels prefix
+func (container *Container) RegisterNotificationChannelRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.NotificationChannelHandler{}))
+	container.NotificationChannelHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterInboxRuleRoutes registers routes for the /inbox-rules prefix
+func (container *Container) RegisterInboxRuleRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.InboxRuleHandler{}))
+	container.InboxRuleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterRoutingRuleRoutes registers routes for the /routing-rules prefix
+func (container *Container) RegisterRoutingRuleRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.RoutingRuleHandler{}))
+	container.RoutingRuleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterAPIKeyRoutes registers routes for the /api-keys prefix
+func (container *Container) RegisterAPIKeyRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.APIKeyHandler{}))
+	container.APIKeyHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterComplianceRuleRoutes registers routes for the /v1/compliance-rules prefix
+func (container *Container) RegisterComplianceRuleRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ComplianceRuleHandler{}))
+	container.ComplianceRuleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterPhonePoolRoutes registers routes for the /v1/phone-pools prefix
+func (container *Container) RegisterPhonePoolRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhonePoolHandler{}))
+	container.PhonePoolHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
+// RegisterAttachmentRoutes registers routes for the /attachments prefix
+func (container *Container) RegisterAttachmentRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.AttachmentHandler{}))
+	container.AttachmentHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+}
+
 // RegisterPhoneRoutes registers routes for the /phone prefix
 func (container *Container) RegisterPhoneRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneHandler{}))
 	container.PhoneHandler().RegisterRoutes(container.AuthRouter())
 }
 
+// RegisterPhoneCrashRoutes registers routes for the /phones/:phoneID/crashes prefix
+func (container *Container) RegisterPhoneCrashRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneCrashHandler{}))
+	container.PhoneCrashHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterPhoneBalanceRoutes registers routes for the /phones/:phoneID/balances prefix
+func (container *Container) RegisterPhoneBalanceRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneBalanceHandler{}))
+	container.PhoneBalanceHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterContactPreferenceRoutes registers routes for the /contacts/:contact/preferences prefix
+func (container *Container) RegisterContactPreferenceRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ContactPreferenceHandler{}))
+	container.ContactPreferenceHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterCustomDomainRoutes registers routes for the /custom-domain prefix
+func (container *Container) RegisterCustomDomainRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.CustomDomainHandler{}))
+	container.CustomDomainHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterPhoneMaintenanceWindowRoutes registers routes for the /phones/:phoneID/maintenance-windows prefix
+func (container *Container) RegisterPhoneMaintenanceWindowRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneMaintenanceWindowHandler{}))
+	container.PhoneMaintenanceWindowHandler().RegisterRoutes(container.AuthRouter())
+}
+
+// RegisterPhoneLogRoutes registers routes for the /phones/:phoneID/logs prefix
+func (container *Container) RegisterPhoneLogRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneLogHandler{}))
+	container.PhoneLogHandler().RegisterRoutes(container.AuthRouter())
+}
+
 // RegisterUserRoutes registers routes for the /users prefix
 func (container *Container) RegisterUserRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.UserHandler{}))
@@ -1368,6 +3912,16 @@ func (container *Container) HeartbeatRepository() repositories.HeartbeatReposito
 	)
 }
 
+// HeartbeatRollupRepository registers a new instance of repositories.HeartbeatRollupRepository
+func (container *Container) HeartbeatRollupRepository() repositories.HeartbeatRollupRepository {
+	container.logger.Debug("creating GORM repositories.HeartbeatRollupRepository")
+	return repositories.NewGormHeartbeatRollupRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DedicatedDB(),
+	)
+}
+
 // UserRepository registers a new instance of repositories.UserRepository
 func (container *Container) UserRepository() repositories.UserRepository {
 	container.logger.Debug("creating GORM repositories.UserRepository")
@@ -1393,6 +3947,20 @@ func (container *Container) UserRistrettoCache() (cache *ristretto.Cache[string,
 	return ristrettoCache
 }
 
+// DataRegionRistrettoCache creates an in-memory *ristretto.Cache[string, string] for the data regions of users
+func (container *Container) DataRegionRistrettoCache() (cache *ristretto.Cache[string, string]) {
+	container.logger.Debug(fmt.Sprintf("creating %T", cache))
+	ristrettoCache, err := ristretto.NewCache[string, string](&ristretto.Config[string, string]{
+		MaxCost:     5000,
+		NumCounters: 5000 * 10,
+		BufferItems: 64,
+	})
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create data region ristretto cache"))
+	}
+	return ristrettoCache
+}
+
 // InitializeTraceProvider initializes the open telemetry trace provider
 func (container *Container) InitializeTraceProvider() func() {
 	return container.initializeUptraceProvider(container.version, container.projectID)
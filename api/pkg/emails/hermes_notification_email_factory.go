@@ -127,6 +127,54 @@ func (factory *hermesNotificationEmailFactory) WebhookSendFailed(user *entities.
 	}, nil
 }
 
+func (factory *hermesNotificationEmailFactory) WebhookPaused(user *entities.User, payload *events.WebhookPausedPayload) (*Email, error) {
+	email := hermes.Email{
+		Body: hermes.Body{
+			Title: "Hello",
+			Intros: []string{
+				fmt.Sprintf("We paused your webhook at %s because httpSMS could not forward %d events in a row to your webserver.", user.UserTimeString(payload.Timestamp), payload.ConsecutiveFailures),
+			},
+			Dictionary: []hermes.Entry{
+				{"Server URL", payload.WebhookURL},
+				{"Webhook ID", payload.WebhookID.String()},
+				{"Reason", payload.PausedReason},
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: "New events are not sent to a paused webhook. Once your webserver is reachable again, you can resume the webhook on the httpSMS website under the settings page.",
+					Button: hermes.Button{
+						Color:     "#329ef4",
+						TextColor: "#FFFFFF",
+						Text:      "WEBHOOK SETTINGS",
+						Link:      "https://httpsms.com/settings/#webhook-settings",
+					},
+				},
+			},
+			Signature: "Cheers",
+			Outros: []string{
+				"Don't hesitate to contact us by replying to this email. You can disable this email notification on https://httpsms.com/settings/#email-notifications",
+			},
+		},
+	}
+
+	html, err := factory.generator.GenerateHTML(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate html email")
+	}
+
+	text, err := factory.generator.GeneratePlainText(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate text email")
+	}
+
+	return &Email{
+		ToEmail: user.Email,
+		Subject: "⏸️ Your httpSMS webhook has been paused",
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
+
 func (factory *hermesNotificationEmailFactory) MessageExpired(user *entities.User, payload *events.MessageSendExpiredPayload) (*Email, error) {
 	email := hermes.Email{
 		Body: hermes.Body{
@@ -227,3 +275,52 @@ func (factory *hermesNotificationEmailFactory) MessageFailed(user *entities.User
 		Text:    text,
 	}, nil
 }
+
+func (factory *hermesNotificationEmailFactory) ReportGenerated(user *entities.User, payload *events.ReportGeneratedPayload) (*Email, error) {
+	email := hermes.Email{
+		Body: hermes.Body{
+			Title: "Hello",
+			Intros: []string{
+				fmt.Sprintf("Your scheduled %s report is attached to this email as a CSV file.", payload.Type),
+			},
+			Dictionary: []hermes.Entry{
+				{"Report", payload.Type.String()},
+				{"From", user.UserTimeString(payload.From)},
+				{"To", user.UserTimeString(payload.To)},
+				{"File", payload.FileName},
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: "You can change or delete your scheduled reports at any time on the settings page.",
+					Button: hermes.Button{
+						Color:     "#329ef4",
+						TextColor: "#FFFFFF",
+						Text:      "REPORT SETTINGS",
+						Link:      "https://httpsms.com/settings/#reports",
+					},
+				},
+			},
+			Signature: "Cheers",
+			Outros: []string{
+				"Don't hesitate to contact us by replying to this email.",
+			},
+		},
+	}
+
+	html, err := factory.generator.GenerateHTML(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate html email")
+	}
+
+	text, err := factory.generator.GeneratePlainText(email)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate text email")
+	}
+
+	return &Email{
+		ToEmail: user.Email,
+		Subject: fmt.Sprintf("📊 Your %s report on httpSMS", payload.Type),
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
@@ -8,11 +8,19 @@ import (
 
 // Email represents an email message
 type Email struct {
-	ToName  string
-	ToEmail string
-	Subject string
-	HTML    string
-	Text    string
+	ToName      string
+	ToEmail     string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []EmailAttachment
+}
+
+// EmailAttachment is a file which is attached to an Email
+type EmailAttachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
 }
 
 func (mail *Email) toAddress() string {
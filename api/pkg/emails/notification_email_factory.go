@@ -18,4 +18,10 @@ type NotificationEmailFactory interface {
 
 	// WebhookSendFailed sends an email when the user's webhook message is failed
 	WebhookSendFailed(user *entities.User, payload *events.WebhookSendFailedPayload) (*Email, error)
+
+	// WebhookPaused sends an email when the user's webhook is paused after too many consecutive failures
+	WebhookPaused(user *entities.User, payload *events.WebhookPausedPayload) (*Email, error)
+
+	// ReportGenerated sends an email with a scheduled report of the user
+	ReportGenerated(user *entities.User, payload *events.ReportGeneratedPayload) (*Email, error)
 }
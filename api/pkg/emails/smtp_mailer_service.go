@@ -1,6 +1,7 @@
 package emails
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/smtp"
@@ -42,6 +43,12 @@ func (mailer *smtpMailer) Send(ctx context.Context, email *Email) (err error) {
 	ctx, span := mailer.tracer.Start(ctx)
 	defer span.End()
 
+	// the users who sign in with a phone number don't have an email
+	if email.ToEmail == "" {
+		span.AddEvent(fmt.Sprintf("email with subject [%s] is not sent because it has no recipient", email.Subject))
+		return nil
+	}
+
 	e := mail.NewEmail()
 	e.From = mailer.from
 	e.To = []string{email.toAddress()}
@@ -49,6 +56,12 @@ func (mailer *smtpMailer) Send(ctx context.Context, email *Email) (err error) {
 	e.Text = []byte(email.Text)
 	e.HTML = []byte(email.HTML)
 
+	for _, attachment := range email.Attachments {
+		if _, err = e.Attach(bytes.NewReader(attachment.Content), attachment.Name, attachment.ContentType); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot attach file [%s] to email", attachment.Name))
+		}
+	}
+
 	err = e.Send(mailer.address, mailer.auth)
 	if err != nil {
 		return stacktrace.Propagate(err, "cannot send email")
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// APIKeyScope is a permission of an APIKey
+type APIKeyScope string
+
+const (
+	// APIKeyScopeSend allows an APIKey to send messages e.g. from the contact form of a website
+	APIKeyScopeSend = APIKeyScope("send")
+
+	// APIKeyScopeRead allows an APIKey to read the messages, threads and phones of the account
+	APIKeyScopeRead = APIKeyScope("read")
+
+	// APIKeyScopeAdmin allows an APIKey to carry out every request like the API key of the account
+	APIKeyScopeAdmin = APIKeyScope("admin")
+)
+
+// String gets the string representation of the APIKeyScope
+func (scope APIKeyScope) String() string {
+	return string(scope)
+}
+
+// APIKey is an additional API key of a user which can only carry out the requests which are allowed by its scopes
+type APIKey struct {
+	ID     uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name   string         `json:"name" example:"Website contact form"`
+	Key    string         `json:"key" gorm:"uniqueIndex:idx_api_keys_key" example:"DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
+	Scopes pq.StringArray `json:"scopes" example:"[send]" gorm:"type:text[]" swaggertype:"array,string"`
+	// DefaultPhoneID is the phone which sends the messages of the key which do not have a from number
+	DefaultPhoneID *uuid.UUID `json:"default_phone_id" gorm:"type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// APIKeyScopes returns the scopes of the APIKey
+func (key *APIKey) APIKeyScopes() []APIKeyScope {
+	scopes := make([]APIKeyScope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, APIKeyScope(scope))
+	}
+	return scopes
+}
+
+// APIKeyRoute is a route which can be requested with an APIKey that has the Scope. The other routes can only be
+// requested with an APIKey which has the APIKeyScopeAdmin scope.
+type APIKeyRoute struct {
+	Method string
+	// Path is the path of the route with the parameters e.g. "/v1/phones/:phoneID"
+	Path  string
+	Scope APIKeyScope
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// APIUsage is the usage of the API by an account since the counting started and the consumption of the message limit of
+// the subscription in the current billing period
+type APIUsage struct {
+	UserID    UserID              `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Since     time.Time           `json:"since" example:"2022-06-05T14:26:02.302718+03:00"`
+	Endpoints []*APIEndpointUsage `json:"endpoints"`
+	RateLimit APIRateLimitUsage   `json:"rate_limit"`
+}
+
+// APIEndpointUsage is the number of requests, errors and the latency of an endpoint which was called with an API key
+type APIEndpointUsage struct {
+	APIKey                     string    `json:"api_key" example:"****Dj3e"`
+	Method                     string    `json:"method" example:"POST"`
+	Path                       string    `json:"path" example:"/v1/messages/send"`
+	Requests                   uint64    `json:"requests" example:"120"`
+	Errors                     uint64    `json:"errors" example:"3"`
+	ErrorRate                  float64   `json:"error_rate" example:"0.025"`
+	AverageLatencyMilliseconds float64   `json:"average_latency_milliseconds" example:"48.5"`
+	MaxLatencyMilliseconds     float64   `json:"max_latency_milliseconds" example:"212.3"`
+	LastRequestAt              time.Time `json:"last_request_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// APIRateLimitUsage is the number of messages which were sent and received out of the limit of the subscription
+type APIRateLimitUsage struct {
+	Limit          uint      `json:"limit" example:"200"`
+	Used           uint      `json:"used" example:"153"`
+	Remaining      uint      `json:"remaining" example:"47"`
+	StartTimestamp time.Time `json:"start_timestamp" example:"2022-01-01T00:00:00+00:00"`
+	EndTimestamp   time.Time `json:"end_timestamp" example:"2022-01-31T23:59:59+00:00"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a media file e.g. an image which is sent or received in an MMS message
+type Attachment struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID    `json:"user_id" gorm:"index:idx_attachments__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name        string    `json:"name" example:"image.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"102400"`
+	URL         string    `json:"url" example:"https://api.httpsms.com/v1/attachments/32343a19-da5e-4b1b-a767-3298a73703cb"`
+	CreatedAt   time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
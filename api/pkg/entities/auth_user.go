@@ -1,12 +1,28 @@
 package entities
 
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
 // AuthUser is the user gotten from an auth request
 type AuthUser struct {
 	ID    UserID `json:"id"`
 	Email string `json:"email"`
+	// Scopes are the scopes of the entities.APIKey of the request. They are empty for the API key of the account and the
+	// sessions of the web app which can carry out every request.
+	Scopes []APIKeyScope `json:"scopes,omitempty"`
+	// DefaultPhoneID is the phone which sends the messages of the entities.APIKey of the request which do not have a from number
+	DefaultPhoneID *uuid.UUID `json:"default_phone_id,omitempty"`
 }
 
-// IsNoop checks if a user is empty
+// IsNoop checks if a user is empty. The email is not checked because the users who sign in with a phone number don't have one.
 func (user AuthUser) IsNoop() bool {
-	return user.ID == "" || user.Email == ""
+	return user.ID == ""
+}
+
+// HasScope checks if the user can carry out the requests which need the scope
+func (user AuthUser) HasScope(scope APIKeyScope) bool {
+	return len(user.Scopes) == 0 || slices.Contains(user.Scopes, APIKeyScopeAdmin) || slices.Contains(user.Scopes, scope)
 }
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ComplianceRule stores the messaging regulations of a destination country which are enforced before a message is dispatched
+type ComplianceRule struct {
+	ID uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	// CountryCode is the ISO 3166-1 alpha-2 code of the destination country e.g. "US"
+	CountryCode string `json:"country_code" gorm:"uniqueIndex" example:"US"`
+	// OptOutFooterRequired means every message must contain one of the OptOutKeywords e.g. "Reply STOP to unsubscribe"
+	OptOutFooterRequired bool           `json:"opt_out_footer_required" example:"true"`
+	OptOutKeywords       pq.StringArray `json:"opt_out_keywords" example:"[STOP]" gorm:"type:text[]" swaggertype:"array,string"`
+	// ForbiddenHoursStart is the hour of the day (0-23) in the Timezone from which messages cannot be sent
+	ForbiddenHoursStart *uint `json:"forbidden_hours_start" example:"21"`
+	// ForbiddenHoursEnd is the hour of the day (0-23) in the Timezone until which messages cannot be sent
+	ForbiddenHoursEnd *uint  `json:"forbidden_hours_end" example:"8"`
+	Timezone          string `json:"timezone" example:"America/New_York"`
+	// ForbiddenContent are words or phrases describing content categories which cannot be sent to the country e.g. "casino"
+	ForbiddenContent pq.StringArray `json:"forbidden_content" example:"[casino,loan]" gorm:"type:text[]" swaggertype:"array,string"`
+	// ForbiddenCategories are the message categories which cannot be sent to the country e.g. "marketing"
+	ForbiddenCategories pq.StringArray `json:"forbidden_categories" example:"[marketing]" gorm:"type:text[]" swaggertype:"array,string"`
+	CreatedAt           time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt           time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Location fetches the location of the rule's timezone
+func (rule *ComplianceRule) Location() *time.Location {
+	location, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsForbiddenHour checks if a message cannot be sent at the given timestamp
+func (rule *ComplianceRule) IsForbiddenHour(timestamp time.Time) bool {
+	if rule.ForbiddenHoursStart == nil || rule.ForbiddenHoursEnd == nil || *rule.ForbiddenHoursStart == *rule.ForbiddenHoursEnd {
+		return false
+	}
+
+	hour := uint(timestamp.In(rule.Location()).Hour())
+	if *rule.ForbiddenHoursStart < *rule.ForbiddenHoursEnd {
+		return hour >= *rule.ForbiddenHoursStart && hour < *rule.ForbiddenHoursEnd
+	}
+
+	// the forbidden window goes over midnight e.g. from 21:00 to 08:00
+	return hour >= *rule.ForbiddenHoursStart || hour < *rule.ForbiddenHoursEnd
+}
+
+// IsMissingOptOutFooter checks if the content does not contain the mandatory opt-out footer
+func (rule *ComplianceRule) IsMissingOptOutFooter(content string) bool {
+	if !rule.OptOutFooterRequired {
+		return false
+	}
+
+	for _, keyword := range rule.OptOutKeywords {
+		if strings.Contains(strings.ToLower(content), strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForbiddenContentMatches returns the forbidden content which is found in the message
+func (rule *ComplianceRule) ForbiddenContentMatches(content string) []string {
+	var matches []string
+	for _, item := range rule.ForbiddenContent {
+		if strings.Contains(strings.ToLower(content), strings.ToLower(item)) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// IsForbiddenCategory checks if messages of a category cannot be sent to the country
+func (rule *ComplianceRule) IsForbiddenCategory(category MessageCategory) bool {
+	for _, item := range rule.ForbiddenCategories {
+		if strings.EqualFold(item, category.String()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ContactPreference stores the messaging preferences of a contact which are applied to every message sent to the contact
+type ContactPreference struct {
+	ID      uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID  UserID    `json:"user_id" gorm:"uniqueIndex:idx_contact_preferences__user_id__contact" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Contact string    `json:"contact" gorm:"uniqueIndex:idx_contact_preferences__user_id__contact" example:"+18005550100"`
+	// Language is the language e.g. "fr" which the messages sent to the contact are translated into
+	Language *string `json:"language" example:"fr"`
+	// DoNotContactCategories are the message categories which cannot be sent to the contact e.g. "marketing"
+	DoNotContactCategories pq.StringArray `json:"do_not_contact_categories" example:"[marketing]" gorm:"type:text[]" swaggertype:"array,string"`
+	// SendWindowStart is the hour of the day (0-23) in the Timezone from which messages are sent to the contact
+	SendWindowStart *uint `json:"send_window_start" example:"9"`
+	// SendWindowEnd is the hour of the day (0-23) in the Timezone until which messages are sent to the contact
+	SendWindowEnd *uint     `json:"send_window_end" example:"18"`
+	Timezone      string    `json:"timezone" example:"Europe/Paris"`
+	CreatedAt     time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Location fetches the location of the contact's timezone
+func (preference *ContactPreference) Location() *time.Location {
+	location, err := time.LoadLocation(preference.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsDoNotContact checks if messages of the category cannot be sent to the contact
+func (preference *ContactPreference) IsDoNotContact(category MessageCategory) bool {
+	for _, item := range preference.DoNotContactCategories {
+		if MessageCategory(item) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSendWindow checks if the messages to the contact are only sent during some hours of the day
+func (preference *ContactPreference) HasSendWindow() bool {
+	return preference.SendWindowStart != nil && preference.SendWindowEnd != nil && *preference.SendWindowStart != *preference.SendWindowEnd
+}
+
+// IsInSendWindow checks if a message can be sent to the contact at the timestamp
+func (preference *ContactPreference) IsInSendWindow(timestamp time.Time) bool {
+	if !preference.HasSendWindow() {
+		return true
+	}
+
+	hour := uint(timestamp.In(preference.Location()).Hour())
+	if *preference.SendWindowStart < *preference.SendWindowEnd {
+		return hour >= *preference.SendWindowStart && hour < *preference.SendWindowEnd
+	}
+
+	// the send window goes over midnight e.g. from 18:00 to 02:00
+	return hour >= *preference.SendWindowStart || hour < *preference.SendWindowEnd
+}
+
+// NextSendTime returns the timestamp when it is in the send window of the contact, otherwise it returns the start of the next send window
+func (preference *ContactPreference) NextSendTime(timestamp time.Time) time.Time {
+	if preference.IsInSendWindow(timestamp) {
+		return timestamp
+	}
+
+	local := timestamp.In(preference.Location())
+	start := time.Date(local.Year(), local.Month(), local.Day(), int(*preference.SendWindowStart), 0, 0, 0, local.Location())
+	if !start.After(local) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.UTC()
+}
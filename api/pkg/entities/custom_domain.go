@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomDomainVerificationPrefix is the subdomain of the TXT record which proves that a user owns a CustomDomain
+const CustomDomainVerificationPrefix = "_httpsms-verification"
+
+// CustomDomain is a domain of a user which is used in the share links of the user instead of the domain of the API.
+// The domain is only used after the TXT record with the VerificationToken has been found on VerificationRecord. Many
+// users can claim the same domain but only one of them can verify it.
+type CustomDomain struct {
+	ID                 uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID             UserID     `json:"user_id" gorm:"uniqueIndex:idx_custom_domains__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Domain             string     `json:"domain" gorm:"uniqueIndex:idx_custom_domains__verified_domain,where:verified_at IS NOT NULL" example:"sms.example.com"`
+	VerificationRecord string     `json:"verification_record" example:"_httpsms-verification.sms.example.com"`
+	VerificationToken  string     `json:"verification_token" example:"httpsms-verification=mK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
+	VerifiedAt         *time.Time `json:"verified_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CheckedAt          *time.Time `json:"checked_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CreatedAt          time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt          time.Time  `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsVerified checks if the TXT record of the CustomDomain has been found
+func (domain *CustomDomain) IsVerified() bool {
+	return domain.VerifiedAt != nil
+}
+
+// BaseURL is the URL of the links which are created on the CustomDomain
+func (domain *CustomDomain) BaseURL() string {
+	return "https://" + domain.Domain
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomEventTypePrefix namespaces the events which are published by a user so they cannot be confused with the events of httpSMS
+const CustomEventTypePrefix = "custom."
+
+// CustomEventTypeRegex is the format of the type of a CustomEvent e.g. custom.order.shipped
+var CustomEventTypeRegex = regexp.MustCompile(`^custom\.[a-z0-9_-]+(\.[a-z0-9_-]+)*$`)
+
+// CustomEvent is an event which is published by a user with the API, it is stored and forwarded to the webhooks of the user
+type CustomEvent struct {
+	ID        uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID          `json:"user_id" gorm:"index:idx_custom_events__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Type      string          `json:"type" example:"custom.order.shipped"`
+	Source    string          `json:"source" example:"/shop/orders"`
+	Data      json.RawMessage `json:"data" gorm:"type:jsonb" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
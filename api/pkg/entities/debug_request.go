@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DebugRequest is an HTTP request and its response which were recorded while the debug mode of a user was enabled
+type DebugRequest struct {
+	ID                 uuid.UUID       `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID             UserID          `json:"user_id" gorm:"index:idx_debug_requests__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Method             string          `json:"method" example:"POST"`
+	URL                string          `json:"url" example:"/v1/messages/send"`
+	RequestHeaders     json.RawMessage `json:"request_headers" gorm:"type:jsonb" swaggertype:"object"`
+	RequestBody        string          `json:"request_body" example:"{\"from\":\"+18005550199\",\"to\":\"+18005550100\",\"content\":\"\"}"`
+	ResponseStatusCode int             `json:"response_status_code" example:"422"`
+	ResponseBody       string          `json:"response_body" example:"{\"status\":\"error\",\"message\":\"validation errors while sending message\"}"`
+	Duration           time.Duration   `json:"duration" swaggertype:"integer" example:"12000000"`
+	CreatedAt          time.Time       `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// DebugMode is the time window in which the requests of a user are recorded
+type DebugMode struct {
+	UserID    UserID     `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Enabled   bool       `json:"enabled" example:"true"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2022-06-05T15:26:02.302718+03:00"`
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// DeprecatedRoute is an endpoint which is slated for removal
+type DeprecatedRoute struct {
+	Method string `json:"method" example:"POST"`
+
+	// Path is the path of the fiber route e.g. /v1/messages/:messageID
+	Path string `json:"path" example:"/v1/messages/send"`
+
+	// DeprecatedAt is the time when the endpoint was deprecated which is sent in the Deprecation header
+	DeprecatedAt time.Time `json:"deprecated_at" example:"2022-06-05T14:26:02.302718+03:00"`
+
+	// SunsetAt is the time after which the endpoint will be removed which is sent in the Sunset header
+	SunsetAt *time.Time `json:"sunset_at" example:"2022-12-05T14:26:02.302718+03:00"`
+
+	// Link is the URL of the documentation of the endpoint which replaces the deprecated endpoint
+	Link *string `json:"link" example:"https://docs.httpsms.com"`
+}
+
+// Key is the method and the path of the deprecated route
+func (route DeprecatedRoute) Key() string {
+	return route.Method + " " + route.Path
+}
+
+// DeprecatedRouteUsage is the number of requests to a deprecated route with an API key
+type DeprecatedRouteUsage struct {
+	Method        string     `json:"method" example:"POST"`
+	Path          string     `json:"path" example:"/v1/messages/send"`
+	SunsetAt      *time.Time `json:"sunset_at" example:"2022-12-05T14:26:02.302718+03:00"`
+	UserID        UserID     `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	APIKey        string     `json:"api_key" example:"****Dj3e"`
+	Requests      uint64     `json:"requests" example:"120"`
+	LastRequestAt time.Time  `json:"last_request_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
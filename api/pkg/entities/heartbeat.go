@@ -6,6 +6,52 @@ import (
 	"github.com/google/uuid"
 )
 
+// HeartbeatGapCause is the likely cause of a gap in the heartbeats of a phone which was offline
+type HeartbeatGapCause string
+
+const (
+	// HeartbeatGapCauseReboot means the phone was restarted during the gap
+	HeartbeatGapCauseReboot = HeartbeatGapCause("reboot")
+
+	// HeartbeatGapCauseNoNetwork means the app could not reach the API during the gap
+	HeartbeatGapCauseNoNetwork = HeartbeatGapCause("no-network")
+
+	// HeartbeatGapCauseDozeMode means the phone was in doze mode when the gap was closed
+	HeartbeatGapCauseDozeMode = HeartbeatGapCause("doze-mode")
+
+	// HeartbeatGapCauseUnknown means the app did not report anything which explains the gap
+	HeartbeatGapCauseUnknown = HeartbeatGapCause("unknown")
+)
+
+// HeartbeatNetworkType is the type of network the phone was connected to when it sent a heartbeat
+type HeartbeatNetworkType string
+
+const (
+	// HeartbeatNetworkType2G is a GSM, GPRS or EDGE cellular network
+	HeartbeatNetworkType2G = HeartbeatNetworkType("2g")
+
+	// HeartbeatNetworkType3G is a UMTS or HSPA cellular network
+	HeartbeatNetworkType3G = HeartbeatNetworkType("3g")
+
+	// HeartbeatNetworkType4G is an LTE cellular network
+	HeartbeatNetworkType4G = HeartbeatNetworkType("4g")
+
+	// HeartbeatNetworkType5G is an NR cellular network
+	HeartbeatNetworkType5G = HeartbeatNetworkType("5g")
+
+	// HeartbeatNetworkTypeWifi is a WiFi network
+	HeartbeatNetworkTypeWifi = HeartbeatNetworkType("wifi")
+)
+
+// HeartbeatNetworkTypes are all the network types which can be reported in a heartbeat
+var HeartbeatNetworkTypes = []HeartbeatNetworkType{
+	HeartbeatNetworkType2G,
+	HeartbeatNetworkType3G,
+	HeartbeatNetworkType4G,
+	HeartbeatNetworkType5G,
+	HeartbeatNetworkTypeWifi,
+}
+
 // Heartbeat represents is a pulse from an active phone
 type Heartbeat struct {
 	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
@@ -14,4 +60,32 @@ type Heartbeat struct {
 	Charging  bool      `json:"charging" example:"true"`
 	UserID    UserID    `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
 	Timestamp time.Time `json:"timestamp" gorm:"index:idx_heartbeats_owner_timestamp" example:"2022-06-05T14:26:01.520828+03:00"`
+	// GapStartedAt is the timestamp of the previous heartbeat when this heartbeat brought an offline phone back online
+	GapStartedAt *time.Time `json:"gap_started_at" example:"2022-06-05T13:02:11.520828+03:00"`
+	// GapCause is the likely cause of the gap which was closed by this heartbeat
+	GapCause *HeartbeatGapCause `json:"gap_cause" example:"doze-mode"`
+	// SignalStrength is the strength of the cellular signal in dBm
+	SignalStrength *int `json:"signal_strength" example:"-85"`
+	// SignalBars is the level of the cellular signal from 0 to 4 bars which is displayed on the phone
+	SignalBars *uint `json:"signal_bars" example:"3"`
+	// NetworkType is the type of network the phone was connected to
+	NetworkType *HeartbeatNetworkType `json:"network_type" example:"4g"`
+	// IntervalSeconds is the heartbeat interval of the phone which the app uses to schedule the next heartbeat. It is stored on the phone.
+	IntervalSeconds uint `json:"interval_seconds" gorm:"-" example:"900"`
+}
+
+// HeartbeatSignalBucket is the signal of the cellular network of a phone in a time bucket
+type HeartbeatSignalBucket struct {
+	Owner     string    `json:"owner" example:"+18005550199"`
+	Timestamp time.Time `json:"timestamp" example:"2022-06-05T14:00:00Z"`
+	// AverageSignalStrength is the average strength of the cellular signal in dBm
+	AverageSignalStrength *float64 `json:"average_signal_strength" example:"-87.5"`
+	// MinSignalStrength is the weakest cellular signal in dBm
+	MinSignalStrength *int `json:"min_signal_strength" example:"-101"`
+	// AverageSignalBars is the average level of the cellular signal from 0 to 4 bars
+	AverageSignalBars *float64 `json:"average_signal_bars" example:"2.75"`
+	// NetworkType is the network type which was reported the most
+	NetworkType *HeartbeatNetworkType `json:"network_type" example:"4g"`
+	// Count is the number of heartbeats in the bucket
+	Count int64 `json:"count" example:"4"`
 }
@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// HeartbeatReportPeriod is the period ending now which is covered by a HeartbeatReport
+type HeartbeatReportPeriod string
+
+const (
+	// HeartbeatReportPeriodDay is the last 24 hours
+	HeartbeatReportPeriodDay = HeartbeatReportPeriod("day")
+
+	// HeartbeatReportPeriodWeek is the last 7 days
+	HeartbeatReportPeriodWeek = HeartbeatReportPeriod("week")
+
+	// HeartbeatReportPeriodMonth is the last 30 days
+	HeartbeatReportPeriodMonth = HeartbeatReportPeriod("month")
+)
+
+// HeartbeatReportPeriods are all the periods which can be covered by a HeartbeatReport
+var HeartbeatReportPeriods = []HeartbeatReportPeriod{
+	HeartbeatReportPeriodDay,
+	HeartbeatReportPeriodWeek,
+	HeartbeatReportPeriodMonth,
+}
+
+// String converts the HeartbeatReportPeriod to a string
+func (period HeartbeatReportPeriod) String() string {
+	return string(period)
+}
+
+// Duration returns the length of the HeartbeatReportPeriod
+func (period HeartbeatReportPeriod) Duration() time.Duration {
+	switch period {
+	case HeartbeatReportPeriodWeek:
+		return 7 * 24 * time.Hour
+	case HeartbeatReportPeriodMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// HeartbeatReport is the uptime of a phone in a period which is computed from its heartbeats
+type HeartbeatReport struct {
+	Owner  string                `json:"owner" example:"+18005550199"`
+	Period HeartbeatReportPeriod `json:"period" example:"week"`
+	From   time.Time             `json:"from" example:"2022-06-05T14:26:02.302718+03:00"`
+	To     time.Time             `json:"to" example:"2022-06-12T14:26:02.302718+03:00"`
+
+	// UptimePercentage is the percentage of the period in which the phone was online. Maintenance windows are not counted as downtime.
+	UptimePercentage float64 `json:"uptime_percentage" example:"99.52"`
+
+	// DowntimeSeconds is the duration of the outages in seconds excluding the maintenance windows of the phone
+	DowntimeSeconds int64 `json:"downtime_seconds" example:"2880"`
+
+	// HeartbeatCount is the number of heartbeats which the phone sent in the period
+	HeartbeatCount int `json:"heartbeat_count" example:"672"`
+
+	// Outages are the periods in which the phone did not send a heartbeat for longer than its heartbeat timeout
+	Outages []HeartbeatOutage `json:"outages"`
+}
+
+// HeartbeatOutage is a period in which a phone did not send a heartbeat for longer than its heartbeat timeout
+type HeartbeatOutage struct {
+	StartedAt time.Time `json:"started_at" example:"2022-06-07T02:10:00+03:00"`
+
+	// EndedAt is the timestamp of the heartbeat which ended the outage. It is nil when the phone is still offline.
+	EndedAt *time.Time `json:"ended_at" example:"2022-06-07T02:58:00+03:00"`
+
+	DurationSeconds int64 `json:"duration_seconds" example:"2880"`
+}
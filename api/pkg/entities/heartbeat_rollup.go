@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeartbeatRollupResolution is the size of the time bucket of a HeartbeatRollup
+type HeartbeatRollupResolution string
+
+const (
+	// HeartbeatRollupResolutionHour aggregates the heartbeats of a phone in an hour
+	HeartbeatRollupResolutionHour = HeartbeatRollupResolution("hour")
+
+	// HeartbeatRollupResolutionDay aggregates the heartbeats of a phone in a day
+	HeartbeatRollupResolutionDay = HeartbeatRollupResolution("day")
+)
+
+// HeartbeatRollupResolutions are all the resolutions of the heartbeat rollups
+var HeartbeatRollupResolutions = []HeartbeatRollupResolution{
+	HeartbeatRollupResolutionHour,
+	HeartbeatRollupResolutionDay,
+}
+
+// String returns the HeartbeatRollupResolution as a string
+func (resolution HeartbeatRollupResolution) String() string {
+	return string(resolution)
+}
+
+// HeartbeatRollup is the aggregate of the heartbeats of a phone in an hour or a day which is kept after the raw heartbeats are pruned
+type HeartbeatRollup struct {
+	ID         uuid.UUID                 `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID     UserID                    `json:"user_id" gorm:"uniqueIndex:idx_heartbeat_rollups__bucket" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner      string                    `json:"owner" gorm:"uniqueIndex:idx_heartbeat_rollups__bucket" example:"+18005550199"`
+	Resolution HeartbeatRollupResolution `json:"resolution" gorm:"uniqueIndex:idx_heartbeat_rollups__bucket" example:"hour"`
+	// Timestamp is the start of the time bucket in UTC
+	Timestamp time.Time `json:"timestamp" gorm:"uniqueIndex:idx_heartbeat_rollups__bucket" example:"2022-06-05T14:00:00Z"`
+	// Count is the number of heartbeats in the time bucket
+	Count uint `json:"count" example:"4"`
+	// ChargingCount is the number of heartbeats which were sent while the phone was charging
+	ChargingCount uint `json:"charging_count" example:"2"`
+	// FirstTimestamp is the timestamp of the first heartbeat in the time bucket
+	FirstTimestamp time.Time `json:"first_timestamp" example:"2022-06-05T14:01:01.520828Z"`
+	// LastTimestamp is the timestamp of the last heartbeat in the time bucket
+	LastTimestamp time.Time `json:"last_timestamp" example:"2022-06-05T14:46:01.520828Z"`
+	// MinSignalStrength is the weakest cellular signal in dBm which was reported in the time bucket
+	MinSignalStrength *int      `json:"min_signal_strength" example:"-101"`
+	CreatedAt         time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt         time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
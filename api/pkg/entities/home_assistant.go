@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HomeAssistantSensor is the state of the delivery status sensor of a phone in Home Assistant
+type HomeAssistantSensor struct {
+	// State is the status of the last message sent by the phone or "unknown" when the phone has not sent any message
+	State         string     `json:"state" example:"delivered"`
+	Owner         string     `json:"owner" example:"+18005550199"`
+	MessageID     *uuid.UUID `json:"message_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Contact       *string    `json:"contact" example:"+18005550100"`
+	FailureReason *string    `json:"failure_reason" example:"UNKNOWN"`
+	UpdatedAt     *time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// HomeAssistantDiscovery describes the endpoints used to configure Home Assistant
+type HomeAssistantDiscovery struct {
+	NotifyURL string `json:"notify_url" example:"https://api.httpsms.com/v1/home-assistant/notify"`
+	SensorURL string `json:"sensor_url" example:"https://api.httpsms.com/v1/home-assistant/sensor"`
+	// Configuration is a YAML snippet for the configuration.yaml file of Home Assistant with a notify service and a delivery status sensor for every phone
+	Configuration string `json:"configuration"`
+}
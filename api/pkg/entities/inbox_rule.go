@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboxRule labels, archives or marks as read the message thread of a received message which matches the rule
+type InboxRule struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID    `json:"user_id" gorm:"index:idx_inbox_rules__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name      string    `json:"name" example:"Delivery notifications"`
+	Sender    string    `json:"sender" example:"+18005550100"`
+	Content   string    `json:"content" example:"your package"`
+	Label     *string   `json:"label" example:"deliveries"`
+	Archive   bool      `json:"archive" example:"true"`
+	MarkRead  bool      `json:"mark_read" example:"true"`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Matches checks if a received message matches the rule. The sender and content are case-insensitive substrings
+// and an empty value matches every message. The content of an encrypted message is never matched.
+func (rule *InboxRule) Matches(contact string, content string, encrypted bool) bool {
+	if rule.Sender != "" && !strings.Contains(strings.ToLower(contact), strings.ToLower(rule.Sender)) {
+		return false
+	}
+
+	if rule.Content != "" && (encrypted || !strings.Contains(strings.ToLower(content), strings.ToLower(rule.Content))) {
+		return false
+	}
+
+	return true
+}
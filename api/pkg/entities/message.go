@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // MessageType is the type of message if it is incoming or outgoing
@@ -30,6 +31,9 @@ const (
 	// MessageStatusScheduled means the message has been scheduled to be sent
 	MessageStatusScheduled = "scheduled"
 
+	// MessageStatusDeferred means the message is held by the scheduler until its send time before it is queued for the phone
+	MessageStatusDeferred = "deferred"
+
 	// MessageStatusSending means a phone has picked up the message and is currently sending it
 	MessageStatusSending = "sending"
 
@@ -48,6 +52,12 @@ const (
 	// MessageStatusExpired means the message could not be sent by the mobile phone after 5 minutes
 	MessageStatusExpired = "expired"
 
+	// MessageStatusCancelled means the message was cancelled by the user before it was picked up by the mobile phone
+	MessageStatusCancelled = "cancelled"
+
+	// MessageStatusRead means the recipient has read the message. This status is only reported by channels with read receipts e.g. whatsapp
+	MessageStatusRead = "read"
+
 	// MessageStatusDeleted is for deleted messages and threads
 	MessageStatusDeleted = "deleted"
 )
@@ -64,8 +74,52 @@ const (
 
 	// MessageEventNameFailed is emitted when a message is failed by the mobile phone
 	MessageEventNameFailed = MessageEventName("FAILED")
+
+	// MessageEventNameRead is emitted when a read receipt is received for a message sent on a rich channel
+	MessageEventNameRead = MessageEventName("READ")
 )
 
+// MessageDeliveryStatus is the status of a delivery report which the carrier sends to the mobile phone for a message
+type MessageDeliveryStatus string
+
+const (
+	// MessageDeliveryStatusDelivered means the carrier has delivered the message to the recipient
+	MessageDeliveryStatusDelivered = MessageDeliveryStatus("delivered")
+
+	// MessageDeliveryStatusFailed means the carrier could not deliver the message to the recipient
+	MessageDeliveryStatusFailed = MessageDeliveryStatus("failed")
+
+	// MessageDeliveryStatusPending means the carrier accepted the message but has not confirmed that it was delivered to the recipient
+	MessageDeliveryStatusPending = MessageDeliveryStatus("pending")
+)
+
+// MessageDeliveryStatusFromCode parses the TP-Status code of an SMS-STATUS-REPORT as defined in 3GPP TS 23.040 section 9.2.3.15
+func MessageDeliveryStatusFromCode(code uint) MessageDeliveryStatus {
+	switch {
+	case code == 0x01:
+		// the message was forwarded to the recipient but the carrier is unable to confirm the delivery
+		return MessageDeliveryStatusPending
+	case code <= 0x1F:
+		return MessageDeliveryStatusDelivered
+	case code <= 0x3F:
+		// temporary error, the carrier is still trying to deliver the message
+		return MessageDeliveryStatusPending
+	default:
+		// permanent error or temporary error when the carrier is no longer trying to deliver the message
+		return MessageDeliveryStatusFailed
+	}
+}
+
+// MessageContentRedacted replaces the content of a message which has been redacted
+const MessageContentRedacted = "[REDACTED]"
+
+// transientSendFailures are the errors reported by the mobile phone when a message could not be sent because of a temporary problem with the cellular network
+var transientSendFailures = map[string]bool{
+	"GENERIC_FAILURE": true,
+	"NO_SERVICE":      true,
+	"RADIO_OFF":       true,
+}
+
 // SIM is the SIM card to use to send the message
 type SIM string
 
@@ -74,6 +128,8 @@ const (
 	SIM1 = SIM("SIM1")
 	// SIM2 use the SIM card in slot 2 to send the message
 	SIM2 = SIM("SIM2")
+	// SIMDefault use the default communication SIM card of the phone to send the message
+	SIMDefault = SIM("DEFAULT")
 )
 
 // String gets the string representation of the SIM
@@ -85,19 +141,59 @@ func (s SIM) String() string {
 type Message struct {
 	ID        uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
 	RequestID *string       `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4"`
-	Owner     string        `json:"owner" example:"+18005550199"`
-	UserID    UserID        `json:"user_id" gorm:"index:idx_messages__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner     string        `json:"owner" gorm:"index:idx_messages__user_id__owner__status,priority:2" example:"+18005550199"`
+	UserID    UserID        `json:"user_id" gorm:"index:idx_messages__user_id;index:idx_messages__user_id__owner__status,priority:1" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
 	Contact   string        `json:"contact" example:"+18005550100"`
 	Content   string        `json:"content" example:"This is a sample text message"`
 	Encrypted bool          `json:"encrypted" example:"false" gorm:"default:false"`
 	Type      MessageType   `json:"type" example:"mobile-terminated"`
-	Status    MessageStatus `json:"status" example:"pending"`
+	Status    MessageStatus `json:"status" gorm:"index:idx_messages__user_id__owner__status,priority:3" example:"pending"`
 	// SIM is the SIM card to use to send the message
 	// * SMS1: use the SIM card in slot 1
 	// * SMS2: use the SIM card in slot 2
 	// * DEFAULT: used the default communication SIM card
 	SIM SIM `json:"sim" example:"DEFAULT"`
 
+	// Channel is the transport used to deliver the message e.g. sms, rcs
+	Channel MessageChannel `json:"channel" example:"sms" gorm:"default:sms"`
+
+	// Category is the class of the message e.g. transactional, marketing, otp
+	Category MessageCategory `json:"category" example:"transactional" gorm:"default:transactional"`
+
+	// Priority is the urgency of the message e.g. low, normal, high which is used to order the messages queued on the phone
+	Priority MessagePriority `json:"priority" example:"normal" gorm:"default:normal"`
+
+	// SenderName is an optional logical name of the sender e.g. "billing" which is used to group messages independently of the phone
+	SenderName *string `json:"sender_name" example:"billing"`
+
+	// PhonePoolID is the ID of the phone pool which assigned the phone that sends the message
+	PhonePoolID *uuid.UUID `json:"phone_pool_id" gorm:"type:uuid" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+
+	// Encoding is the alphabet used to send the content of an SMS message e.g. GSM-7 or UCS-2. It is empty for encrypted messages
+	Encoding MessageEncoding `json:"encoding" example:"GSM-7"`
+
+	// SegmentCount is the number of SMS segments the carrier charges for the content. It is 0 for encrypted messages
+	SegmentCount uint `json:"segment_count" example:"1"`
+
+	// SuggestedReplies are quick replies shown to the recipient on channels which support rich content
+	SuggestedReplies pq.StringArray `json:"suggested_replies" example:"[Yes,No]" gorm:"type:text[]" swaggertype:"array,string"`
+
+	// MediaURLs are the URLs of media attached to the message on channels which support rich content
+	MediaURLs pq.StringArray `json:"media_urls" example:"[https://example.com/image.png]" gorm:"type:text[]" swaggertype:"array,string"`
+
+	// Language is the language which was detected in the content of a received message or the language which the
+	// content of a sent message was translated into for the contact e.g. "fr"
+	Language *string `json:"language" example:"en"`
+
+	// TranslatedContent is a copy of the content of a received message in the preferred language of the user
+	TranslatedContent *string `json:"translated_content" example:"This is a sample text message"`
+
+	// TranslatedFrom is the language which was detected in the content of a translated message
+	TranslatedFrom *string `json:"translated_from" example:"fr"`
+
+	// FailedOverFrom is the phone number of the phone which owned the message before it went offline and the message was moved to its backup phone
+	FailedOverFrom *string `json:"failed_over_from" example:"+18005550100"`
+
 	// SendDuration is the number of nanoseconds from when the request was received until when the mobile phone send the message
 	SendDuration *int64 `json:"send_time" example:"133414"`
 
@@ -109,14 +205,58 @@ type Message struct {
 	NotificationScheduledAt *time.Time `json:"scheduled_at" example:"2022-06-05T14:26:09.527976+03:00"`
 	SentAt                  *time.Time `json:"sent_at" example:"2022-06-05T14:26:09.527976+03:00"`
 	ScheduledSendTime       *time.Time `json:"scheduled_send_time" example:"2022-06-05T14:26:09.527976+03:00"`
+	ExpiresAt               *time.Time `json:"expires_at" gorm:"index:idx_messages__expires_at" example:"2022-06-05T15:26:09.527976+03:00"`
 	DeliveredAt             *time.Time `json:"delivered_at" example:"2022-06-05T14:26:09.527976+03:00"`
 	ExpiredAt               *time.Time `json:"expired_at" example:"2022-06-05T14:26:09.527976+03:00"`
 	FailedAt                *time.Time `json:"failed_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CancelledAt             *time.Time `json:"cancelled_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	RedactedAt              *time.Time `json:"redacted_at" example:"2022-06-06T14:26:09.527976+03:00"`
 	CanBePolled             bool       `json:"can_be_polled" example:"false"`
 	SendAttemptCount        uint       `json:"send_attempt_count" example:"0"`
 	MaxSendAttempts         uint       `json:"max_send_attempts" example:"1"`
 	ReceivedAt              *time.Time `json:"received_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	ReadAt                  *time.Time `json:"read_at" example:"2022-06-05T14:26:09.527976+03:00"`
 	FailureReason           *string    `json:"failure_reason" example:"UNKNOWN"`
+
+	// DeliveryStatusCode is the raw TP-Status code of the last delivery report which the carrier sent for the message
+	DeliveryStatusCode *uint `json:"delivery_status_code" example:"0"`
+
+	// DeliveryReportPDU is the raw PDU of the last delivery report which the carrier sent for the message encoded in hexadecimal
+	DeliveryReportPDU *string `json:"delivery_report_pdu" example:"07914151551512F2060A0A8101234567892260503141650022605031416500"`
+
+	// DuplicateOfID is the ID of the received message which this message duplicates. Duplicate messages are stored but hidden from the message lists.
+	DuplicateOfID *uuid.UUID `json:"duplicate_of_id" gorm:"type:uuid" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+
+	// IsStarred is set by the user to mark an important message when triaging a thread
+	IsStarred bool `json:"is_starred" example:"false" gorm:"default:false"`
+
+	// NeedsFollowUp is set by the user to mark a message which still has to be followed up
+	NeedsFollowUp bool `json:"needs_follow_up" example:"false" gorm:"default:false"`
+}
+
+// HasRichContent determines if the message has fields which can only be delivered by a rich channel
+func (message *Message) HasRichContent() bool {
+	return len(message.SuggestedReplies) > 0 || len(message.MediaURLs) > 0
+}
+
+// CountSegments sets the Encoding and SegmentCount of an SMS message whose content is not encrypted
+func (message *Message) CountSegments() *Message {
+	if message.Encrypted || MessageChannelSanitized(message.Channel) != MessageChannelSMS {
+		return message
+	}
+	message.Encoding = MessageContentEncoding(message.Content)
+	message.SegmentCount = MessageSegmentCount(message.Content)
+	return message
+}
+
+// Read registers a read receipt for a message
+func (message *Message) Read(timestamp time.Time) *Message {
+	message.ReadAt = &timestamp
+	if MessageChannelSanitized(message.Channel).HasReadReceipts() {
+		message.Status = MessageStatusRead
+		message.updateOrderTimestamp(timestamp)
+	}
+	return message
 }
 
 // IsSending determines if a message is being sent
@@ -139,14 +279,39 @@ func (message *Message) IsScheduled() bool {
 	return message.Status == MessageStatusScheduled
 }
 
+// IsDeferred checks if a message is held by the scheduler until its send time
+func (message *Message) IsDeferred() bool {
+	return message.Status == MessageStatusDeferred
+}
+
 // IsExpired checks if a message is expired
 func (message *Message) IsExpired() bool {
 	return message.Status == MessageStatusExpired
 }
 
+// IsCancelled checks if a message is cancelled
+func (message *Message) IsCancelled() bool {
+	return message.Status == MessageStatusCancelled
+}
+
+// CanBeCancelled checks if a message has not been picked up by the mobile phone so it can be cancelled
+func (message *Message) CanBeCancelled() bool {
+	return message.Type == MessageTypeMobileTerminated && (message.IsPending() || message.IsScheduled() || message.IsDeferred())
+}
+
 // CanBeRescheduled checks if a message can be rescheduled
 func (message *Message) CanBeRescheduled() bool {
-	return message.SendAttemptCount < message.MaxSendAttempts
+	return message.SendAttemptCount < message.MaxSendAttempts && !message.IsPastExpiry(time.Now().UTC())
+}
+
+// CanBeRetried checks if a message which could not be sent with the error message can be sent again
+func (message *Message) CanBeRetried(errorMessage string) bool {
+	return transientSendFailures[errorMessage] && message.CanBeRescheduled()
+}
+
+// IsPastExpiry checks if the validity period of a message has ended at the timestamp
+func (message *Message) IsPastExpiry(timestamp time.Time) bool {
+	return message.ExpiresAt != nil && !timestamp.Before(*message.ExpiresAt)
 }
 
 // IsSent determines if a message has been sent
@@ -174,6 +339,23 @@ func (message *Message) Failed(timestamp time.Time, errorMessage string) *Messag
 	return message
 }
 
+// FailedTransiently registers a message which could not be sent because of a temporary error as pending so it can be sent again
+func (message *Message) FailedTransiently(timestamp time.Time, errorMessage string) *Message {
+	message.Status = MessageStatusPending
+	message.FailureReason = &errorMessage
+	message.updateOrderTimestamp(timestamp)
+	return message
+}
+
+// Requeued registers a message which was picked up by a phone that lost it e.g. after the app was reinstalled as pending so it can be fetched again
+func (message *Message) Requeued(timestamp time.Time) *Message {
+	if message.IsSending() {
+		message.Status = MessageStatusPending
+	}
+	message.updateOrderTimestamp(timestamp)
+	return message
+}
+
 // Delivered registers a message as delivered
 func (message *Message) Delivered(timestamp time.Time) *Message {
 	message.DeliveredAt = &timestamp
@@ -187,6 +369,13 @@ func (message *Message) Delivered(timestamp time.Time) *Message {
 	return message
 }
 
+// DeliveryReported stores the raw status code and PDU of the delivery report which the carrier sent for a message
+func (message *Message) DeliveryReported(statusCode *uint, pdu *string) *Message {
+	message.DeliveryStatusCode = statusCode
+	message.DeliveryReportPDU = pdu
+	return message
+}
+
 // AddSendAttemptCount increments the send attempt count of a message
 func (message *Message) AddSendAttemptCount() *Message {
 	message.SendAttemptCount++
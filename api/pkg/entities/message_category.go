@@ -0,0 +1,54 @@
+package entities
+
+// MessageCategory is the class of a message which drives throttling, quiet hours and suppression checks
+type MessageCategory string
+
+const (
+	// MessageCategoryTransactional is for messages triggered by an action of the recipient e.g. order updates
+	MessageCategoryTransactional = MessageCategory("transactional")
+
+	// MessageCategoryMarketing is for promotional messages which can only be sent to contacts who have not opted out
+	MessageCategoryMarketing = MessageCategory("marketing")
+
+	// MessageCategoryOTP is for time-sensitive one-time passwords which are never throttled or delayed
+	MessageCategoryOTP = MessageCategory("otp")
+)
+
+// String gets the string representation of the MessageCategory
+func (category MessageCategory) String() string {
+	return string(category)
+}
+
+// IsMarketing checks if the category is MessageCategoryMarketing
+func (category MessageCategory) IsMarketing() bool {
+	return category == MessageCategoryMarketing
+}
+
+// IsQuietHoursExempt checks if messages in this category can be sent during the forbidden hours of a country
+func (category MessageCategory) IsQuietHoursExempt() bool {
+	return category == MessageCategoryOTP
+}
+
+// MessagesPerMinute returns the send rate of the category based on the rate configured on the phone.
+// A rate of 0 means the message is not throttled.
+func (category MessageCategory) MessagesPerMinute(phoneMessagesPerMinute uint) uint {
+	switch category {
+	case MessageCategoryOTP:
+		return 0
+	case MessageCategoryMarketing:
+		if phoneMessagesPerMinute > 1 {
+			return phoneMessagesPerMinute / 2
+		}
+		return phoneMessagesPerMinute
+	default:
+		return phoneMessagesPerMinute
+	}
+}
+
+// MessageCategorySanitized returns the category of a message, defaulting to MessageCategoryTransactional
+func MessageCategorySanitized(category MessageCategory) MessageCategory {
+	if category == "" {
+		return MessageCategoryTransactional
+	}
+	return category
+}
@@ -0,0 +1,46 @@
+package entities
+
+// MessageChannel is the transport used by the mobile phone to deliver a message
+type MessageChannel string
+
+const (
+	// MessageChannelSMS sends the message as a plain SMS using the SIM card of the phone
+	MessageChannelSMS = MessageChannel("sms")
+
+	// MessageChannelRCS sends the message using the RCS (Rich Communication Services) transport of the phone
+	MessageChannelRCS = MessageChannel("rcs")
+
+	// MessageChannelWhatsApp relays the message through the WhatsApp application installed on the phone
+	MessageChannelWhatsApp = MessageChannel("whatsapp")
+
+	// MessageChannelMMS sends the message with its media attachments as an MMS using the SIM card of the phone
+	MessageChannelMMS = MessageChannel("mms")
+)
+
+// String gets the string representation of the MessageChannel
+func (channel MessageChannel) String() string {
+	return string(channel)
+}
+
+// SupportsRichContent checks if the channel can deliver suggested replies, media and read receipts
+func (channel MessageChannel) SupportsRichContent() bool {
+	return channel == MessageChannelRCS || channel == MessageChannelWhatsApp
+}
+
+// SupportsMedia checks if the channel can deliver media attachments e.g. images
+func (channel MessageChannel) SupportsMedia() bool {
+	return channel.SupportsRichContent() || channel == MessageChannelMMS
+}
+
+// HasReadReceipts checks if the channel reports when a message is read by the recipient
+func (channel MessageChannel) HasReadReceipts() bool {
+	return channel == MessageChannelRCS || channel == MessageChannelWhatsApp
+}
+
+// MessageChannelSanitized returns the channel of a message, defaulting to MessageChannelSMS for messages stored before channels existed
+func MessageChannelSanitized(channel MessageChannel) MessageChannel {
+	if channel == "" {
+		return MessageChannelSMS
+	}
+	return channel
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "strings"
+
+// MessageDuplicateStrategy determines how a received message is matched with the messages which were previously received from the same contact
+type MessageDuplicateStrategy string
+
+const (
+	// MessageDuplicateStrategyExact matches messages which have exactly the same content
+	MessageDuplicateStrategyExact = MessageDuplicateStrategy("exact")
+
+	// MessageDuplicateStrategyNormalized matches messages which have the same content ignoring the case and the whitespace
+	MessageDuplicateStrategyNormalized = MessageDuplicateStrategy("normalized")
+)
+
+// MessageDuplicateStrategies are all the strategies which can be used to match duplicate messages
+var MessageDuplicateStrategies = []MessageDuplicateStrategy{
+	MessageDuplicateStrategyExact,
+	MessageDuplicateStrategyNormalized,
+}
+
+// String converts the MessageDuplicateStrategy to a string
+func (strategy MessageDuplicateStrategy) String() string {
+	return string(strategy)
+}
+
+// Matches checks if the content of a received message matches the content of a previously received message
+func (strategy MessageDuplicateStrategy) Matches(content string, previous string) bool {
+	if strategy == MessageDuplicateStrategyNormalized {
+		return strings.EqualFold(strings.Join(strings.Fields(content), " "), strings.Join(strings.Fields(previous), " "))
+	}
+	return content == previous
+}
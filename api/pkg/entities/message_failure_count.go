@@ -0,0 +1,8 @@
+package entities
+
+// MessageFailureCount is the number of outgoing messages of a phone which failed with the same reason
+type MessageFailureCount struct {
+	Owner         string `json:"owner" example:"+18005550199"`
+	FailureReason string `json:"failure_reason" example:"GENERIC_FAILURE"`
+	Count         int64  `json:"count" example:"12"`
+}
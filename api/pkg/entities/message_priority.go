@@ -0,0 +1,40 @@
+package entities
+
+// MessagePriority is the urgency of an outgoing message which drives the order in which the messages queued on a phone are sent
+type MessagePriority string
+
+const (
+	// MessagePriorityLow is for messages which can wait for all the other messages queued on the phone e.g. bulk notifications
+	MessagePriorityLow = MessagePriority("low")
+
+	// MessagePriorityNormal is the default priority of a message
+	MessagePriorityNormal = MessagePriority("normal")
+
+	// MessagePriorityHigh is for urgent messages e.g. one-time passwords which jump ahead of the messages queued on the phone
+	MessagePriorityHigh = MessagePriority("high")
+)
+
+// String gets the string representation of the MessagePriority
+func (priority MessagePriority) String() string {
+	return string(priority)
+}
+
+// Rank orders the MessagePriority from the most urgent with a rank of 0 to the least urgent
+func (priority MessagePriority) Rank() int {
+	switch priority {
+	case MessagePriorityHigh:
+		return 0
+	case MessagePriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// MessagePrioritySanitized returns the priority of a message, defaulting to MessagePriorityNormal
+func MessagePrioritySanitized(priority MessagePriority) MessagePriority {
+	if priority == "" {
+		return MessagePriorityNormal
+	}
+	return priority
+}
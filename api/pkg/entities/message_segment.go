@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	gsm7SingleSegmentLength = 160
+	gsm7MultiSegmentLength  = 153
+	ucs2SingleSegmentLength = 70
+	ucs2MultiSegmentLength  = 67
+	gsm7BasicCharacters     = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7ExtensionCharacters = "^{}\\[~]|€\f"
+)
+
+// MessageEncoding is the alphabet used to encode the content of an SMS message
+type MessageEncoding string
+
+const (
+	// MessageEncodingGSM7 is the GSM 03.38 7-bit alphabet which fits 160 characters in a single segment
+	MessageEncodingGSM7 = MessageEncoding("GSM-7")
+
+	// MessageEncodingUCS2 is the 16-bit alphabet used for content with characters e.g. emojis which are not in GSM-7
+	MessageEncodingUCS2 = MessageEncoding("UCS-2")
+)
+
+// String gets the string representation of the MessageEncoding
+func (encoding MessageEncoding) String() string {
+	return string(encoding)
+}
+
+// MessageContentEncoding returns the MessageEncoding which is used to send the content as an SMS
+func MessageContentEncoding(content string) MessageEncoding {
+	if IsGSM7(content) {
+		return MessageEncodingGSM7
+	}
+	return MessageEncodingUCS2
+}
+
+// DefaultOptOutFooter is the footer appended to marketing messages when the user has not configured a custom footer
+const DefaultOptOutFooter = "Reply STOP to unsubscribe"
+
+// MessageSegmentCount returns the number of SMS segments needed to send the content. The escape character and the
+// extension character of GSM-7 and the surrogate pair of a UCS-2 character are never split across two segments.
+func MessageSegmentCount(content string) uint {
+	single, multi := uint(gsm7SingleSegmentLength), uint(gsm7MultiSegmentLength)
+	isGSM7 := IsGSM7(content)
+	if !isGSM7 {
+		single, multi = ucs2SingleSegmentLength, ucs2MultiSegmentLength
+	}
+
+	sizes := make([]uint, 0, len(content))
+	length := uint(0)
+	for _, character := range content {
+		size := uint(1)
+		if isGSM7 && strings.ContainsRune(gsm7ExtensionCharacters, character) {
+			size = 2
+		} else if !isGSM7 {
+			size = uint(utf16.RuneLen(character))
+		}
+		sizes = append(sizes, size)
+		length += size
+	}
+
+	if length <= single {
+		return 1
+	}
+
+	segments, remaining := uint(1), multi
+	for _, size := range sizes {
+		if size > remaining {
+			segments++
+			remaining = multi
+		}
+		remaining -= size
+	}
+	return segments
+}
+
+// IsGSM7 checks if the content can be encoded with the GSM 03.38 7-bit alphabet
+func IsGSM7(content string) bool {
+	for _, character := range content {
+		if !strings.ContainsRune(gsm7BasicCharacters, character) && !strings.ContainsRune(gsm7ExtensionCharacters, character) {
+			return false
+		}
+	}
+	return true
+}
+
+// AppendOptOutFooter appends the opt-out footer to the content if it is not already present
+func AppendOptOutFooter(content string, footer string) string {
+	footer = strings.TrimSpace(footer)
+	if footer == "" || strings.Contains(strings.ToLower(content), strings.ToLower(footer)) {
+		return content
+	}
+	return strings.TrimRight(content, " \n") + "\n" + footer
+}
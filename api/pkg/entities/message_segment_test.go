@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageSegmentCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding MessageEncoding
+		segments uint
+	}{
+		{name: "empty content is a single segment", content: "", encoding: MessageEncodingGSM7, segments: 1},
+		{name: "160 GSM-7 characters fit in a single segment", content: strings.Repeat("a", 160), encoding: MessageEncodingGSM7, segments: 1},
+		{name: "161 GSM-7 characters need 2 segments", content: strings.Repeat("a", 161), encoding: MessageEncodingGSM7, segments: 2},
+		{name: "306 GSM-7 characters fit in 2 segments of 153", content: strings.Repeat("a", 306), encoding: MessageEncodingGSM7, segments: 2},
+		{name: "307 GSM-7 characters need 3 segments", content: strings.Repeat("a", 307), encoding: MessageEncodingGSM7, segments: 3},
+		{name: "an extension character takes 2 septets", content: strings.Repeat("a", 159) + "€", encoding: MessageEncodingGSM7, segments: 2},
+		{name: "80 extension characters fit in a single segment", content: strings.Repeat("{", 80), encoding: MessageEncodingGSM7, segments: 1},
+		{name: "an extension character is not split across segments", content: strings.Repeat("a", 152) + "^" + strings.Repeat("a", 152), encoding: MessageEncodingGSM7, segments: 3},
+		{name: "an extension character which fills a segment is not moved", content: strings.Repeat("a", 151) + "^" + strings.Repeat("a", 153), encoding: MessageEncodingGSM7, segments: 2},
+		{name: "70 UCS-2 characters fit in a single segment", content: strings.Repeat("ж", 70), encoding: MessageEncodingUCS2, segments: 1},
+		{name: "71 UCS-2 characters need 2 segments", content: strings.Repeat("ж", 71), encoding: MessageEncodingUCS2, segments: 2},
+		{name: "134 UCS-2 characters fit in 2 segments of 67", content: strings.Repeat("ж", 134), encoding: MessageEncodingUCS2, segments: 2},
+		{name: "135 UCS-2 characters need 3 segments", content: strings.Repeat("ж", 135), encoding: MessageEncodingUCS2, segments: 3},
+		{name: "a single GSM-7 incompatible character switches the content to UCS-2", content: strings.Repeat("a", 70) + "ж", encoding: MessageEncodingUCS2, segments: 2},
+		{name: "35 emojis fit in a single segment", content: strings.Repeat("😀", 35), encoding: MessageEncodingUCS2, segments: 1},
+		{name: "an emoji is not split across segments", content: strings.Repeat("a", 66) + "😀" + strings.Repeat("a", 66), encoding: MessageEncodingUCS2, segments: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			// Act
+			encoding := MessageContentEncoding(test.content)
+			segments := MessageSegmentCount(test.content)
+
+			// Assert
+			assert.Equal(t, test.encoding, encoding)
+			assert.Equal(t, test.segments, segments)
+		})
+	}
+}
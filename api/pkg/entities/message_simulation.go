@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageSimulation is the outcome of sending a message which is computed without queueing the message
+type MessageSimulation struct {
+	PhoneID     uuid.UUID      `json:"phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	PhonePoolID *uuid.UUID     `json:"phone_pool_id" example:"32343a19-da5e-4b1b-a767-3298a73703cc"`
+	Owner       string         `json:"owner" example:"+18005550199"`
+	Contact     string         `json:"contact" example:"+18005550100"`
+	Content     string         `json:"content" example:"This is a sample text message"`
+	SIM         SIM            `json:"sim" example:"SIM1"`
+	Channel     MessageChannel `json:"channel" example:"sms"`
+
+	// Encoding is the alphabet used to send the content of an SMS message e.g. GSM-7 or UCS-2. It is empty for encrypted messages
+	Encoding MessageEncoding `json:"encoding" example:"GSM-7"`
+
+	// SegmentCount is the number of SMS segments the carrier charges for the content. It is 0 for encrypted messages
+	SegmentCount uint `json:"segment_count" example:"1"`
+
+	// BillableMessages is the number of messages which are counted against the limit of the plan of the user
+	BillableMessages uint `json:"billable_messages" example:"1"`
+
+	// IsEntitled is false when the message would be rejected because the user has reached the limit of their plan
+	IsEntitled bool `json:"is_entitled" example:"true"`
+
+	// Status is the status of the message when it is queued e.g. scheduled when it is held until a send time
+	Status MessageStatus `json:"status" example:"pending"`
+
+	// QueueDepth is the number of outgoing messages of the phone which are waiting to be sent before this message
+	QueueDepth int64 `json:"queue_depth" example:"12"`
+
+	// IsThrottled is true when the message would be delayed by the send rate limits of the phone
+	IsThrottled bool `json:"is_throttled" example:"false"`
+
+	// EstimatedDispatchAt is the estimated time when the message is pushed to the phone
+	EstimatedDispatchAt time.Time `json:"estimated_dispatch_at" example:"2022-06-05T14:26:09.527976+03:00"`
+}
@@ -1,30 +1,54 @@
 package entities
 
 import (
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-// MessageThread represents a message thread between 2 phone numbers
+// MessageThreadLabelRegex is the format of a label of a MessageThread e.g. support
+var MessageThreadLabelRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// MessageThread represents a message thread between 2 phone numbers. A contact has a separate thread with each phone of
+// the user so that the conversations of the phones are not merged.
 type MessageThread struct {
-	ID                 uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
-	Owner              string        `json:"owner" example:"+18005550199"`
-	Contact            string        `json:"contact" example:"+18005550100"`
-	IsArchived         bool          `json:"is_archived" example:"false"`
-	UserID             UserID        `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
-	Color              string        `json:"color" example:"indigo"`
-	Status             MessageStatus `json:"status" example:"PENDING"`
-	LastMessageContent *string       `json:"last_message_content" example:"This is a sample message content"`
-	LastMessageID      *uuid.UUID    `json:"last_message_id" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
-	CreatedAt          time.Time     `json:"created_at" example:"2022-06-05T14:26:09.527976+03:00"`
-	UpdatedAt          time.Time     `json:"updated_at" example:"2022-06-05T14:26:09.527976+03:00"`
-	OrderTimestamp     time.Time     `json:"order_timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+	ID                 uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	Owner              string         `json:"owner" gorm:"uniqueIndex:idx_message_threads__user_id__owner__contact" example:"+18005550199"`
+	Contact            string         `json:"contact" gorm:"uniqueIndex:idx_message_threads__user_id__owner__contact" example:"+18005550100"`
+	IsArchived         bool           `json:"is_archived" example:"false"`
+	IsMuted            bool           `json:"is_muted" example:"false"`
+	IsPinned           bool           `json:"is_pinned" example:"false" gorm:"default:false"`
+	IsUnread           bool           `json:"is_unread" example:"false" gorm:"default:false"`
+	Labels             pq.StringArray `json:"labels" example:"[support]" gorm:"type:text[];default:'{}'" swaggertype:"array,string"`
+	UserID             UserID         `json:"user_id" gorm:"uniqueIndex:idx_message_threads__user_id__owner__contact" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Color              string         `json:"color" example:"indigo"`
+	Topic              *string        `json:"topic" gorm:"index" example:"support"`
+	Language           *string        `json:"language" example:"en"`
+	ClassifiedAt       *time.Time     `json:"classified_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	WebhookID          *uuid.UUID     `json:"webhook_id" gorm:"type:uuid" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Status             MessageStatus  `json:"status" example:"PENDING"`
+	LastMessageContent *string        `json:"last_message_content" example:"This is a sample message content"`
+	LastMessageID      *uuid.UUID     `json:"last_message_id" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	LastMessageType    *MessageType   `json:"last_message_type" example:"mobile-terminated"`
+	LastMessageAt      *time.Time     `json:"last_message_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CreatedAt          time.Time      `json:"created_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	UpdatedAt          time.Time      `json:"updated_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	OrderTimestamp     time.Time      `json:"order_timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
 }
 
 // Update a message thread after a message event
-func (thread *MessageThread) Update(timestamp time.Time, messageID uuid.UUID, content string, status MessageStatus) *MessageThread {
+func (thread *MessageThread) Update(timestamp time.Time, messageID uuid.UUID, messageType MessageType, content string, status MessageStatus) *MessageThread {
+	if !thread.HasLastMessage(messageID) && messageType == MessageTypeMobileOriginated {
+		thread.IsUnread = true
+	}
+	if !thread.HasLastMessage(messageID) || thread.LastMessageAt == nil {
+		thread.LastMessageAt = &timestamp
+	}
 	thread.OrderTimestamp = timestamp
+	thread.LastMessageType = &messageType
 	thread.LastMessageID = &messageID
 	thread.Status = status
 	thread.LastMessageContent = &content
@@ -37,6 +61,52 @@ func (thread *MessageThread) UpdateArchive(isArchived bool) *MessageThread {
 	return thread
 }
 
+// UpdateMuted sets a message thread as muted
+func (thread *MessageThread) UpdateMuted(isMuted bool) *MessageThread {
+	thread.IsMuted = isMuted
+	return thread
+}
+
+// UpdateUnread sets a message thread as unread
+func (thread *MessageThread) UpdateUnread(isUnread bool) *MessageThread {
+	thread.IsUnread = isUnread
+	return thread
+}
+
+// UpdatePinned sets a message thread as pinned
+func (thread *MessageThread) UpdatePinned(isPinned bool) *MessageThread {
+	thread.IsPinned = isPinned
+	return thread
+}
+
+// UpdateWebhook sets the webhook which receives the events of a message thread instead of the webhooks of the account
+func (thread *MessageThread) UpdateWebhook(webhookID *uuid.UUID) *MessageThread {
+	thread.WebhookID = webhookID
+	return thread
+}
+
+// AddLabels adds the labels which are not already on a message thread
+func (thread *MessageThread) AddLabels(labels []string) *MessageThread {
+	for _, label := range labels {
+		if !slices.Contains(thread.Labels, label) {
+			thread.Labels = append(thread.Labels, label)
+		}
+	}
+	return thread
+}
+
+// RemoveLabel removes a label from a message thread
+func (thread *MessageThread) RemoveLabel(label string) *MessageThread {
+	labels := pq.StringArray{}
+	for _, value := range thread.Labels {
+		if value != label {
+			labels = append(labels, value)
+		}
+	}
+	thread.Labels = labels
+	return thread
+}
+
 // HasLastMessage checks the last message in a thread by ID
 func (thread *MessageThread) HasLastMessage(id uuid.UUID) bool {
 	if thread.LastMessageID == nil {
@@ -0,0 +1,12 @@
+package entities
+
+import "github.com/google/uuid"
+
+// MessageThreadBulkDelete is the result of deleting many MessageThread at once
+type MessageThreadBulkDelete struct {
+	// Count is the number of message threads which were deleted
+	Count int `json:"count" example:"2"`
+
+	// MessageThreadIDs are the IDs of the message threads which were deleted
+	MessageThreadIDs []uuid.UUID `json:"message_thread_ids" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadContextRole is the role of the author of a message in a MessageThreadContext
+type MessageThreadContextRole string
+
+const (
+	// MessageThreadContextRoleUser is used for messages which were sent by the contact
+	MessageThreadContextRoleUser = MessageThreadContextRole("user")
+
+	// MessageThreadContextRoleAssistant is used for messages which were sent by the owner of the thread
+	MessageThreadContextRoleAssistant = MessageThreadContextRole("assistant")
+
+	// MessageThreadContextRoleSystem is used for events in the thread which are not text messages e.g. missed calls
+	MessageThreadContextRoleSystem = MessageThreadContextRole("system")
+)
+
+// MessageThreadContextMessage is a compact representation of an entities.Message for bots and LLM integrations
+type MessageThreadContextMessage struct {
+	Role      MessageThreadContextRole `json:"role" example:"user"`
+	Content   string                   `json:"content" example:"This is a sample text message"`
+	Encrypted bool                     `json:"encrypted,omitempty" example:"false"`
+	Timestamp time.Time                `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+}
+
+// MessageThreadContext is the recent exchange in an entities.MessageThread ordered from the oldest to the newest message
+type MessageThreadContext struct {
+	MessageThreadID uuid.UUID                     `json:"message_thread_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner           string                        `json:"owner" example:"+18005550199"`
+	Contact         string                        `json:"contact" example:"+18005550100"`
+	Messages        []MessageThreadContextMessage `json:"messages"`
+}
+
+// NewMessageThreadContext creates a MessageThreadContext from messages which are ordered from the newest to the oldest
+func NewMessageThreadContext(thread *MessageThread, messages []Message) *MessageThreadContext {
+	result := &MessageThreadContext{
+		MessageThreadID: thread.ID,
+		Owner:           thread.Owner,
+		Contact:         thread.Contact,
+		Messages:        make([]MessageThreadContextMessage, 0, len(messages)),
+	}
+
+	for index := len(messages) - 1; index >= 0; index-- {
+		message := messages[index]
+		item := MessageThreadContextMessage{
+			Role:      MessageThreadContextRoleAssistant,
+			Content:   message.Content,
+			Encrypted: message.Encrypted,
+			Timestamp: message.OrderTimestamp,
+		}
+
+		switch message.Type {
+		case MessageTypeMobileOriginated:
+			item.Role = MessageThreadContextRoleUser
+		case MessageTypeCallMissed:
+			item.Role = MessageThreadContextRoleSystem
+			item.Content = "missed phone call from " + message.Contact
+		}
+
+		result.Messages = append(result.Messages, item)
+	}
+
+	return result
+}
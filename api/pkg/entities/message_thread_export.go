@@ -0,0 +1,20 @@
+package entities
+
+// MessageThreadExportFormat is the file format of an exported MessageThread
+type MessageThreadExportFormat string
+
+const (
+	// MessageThreadExportFormatCSV exports a MessageThread as a CSV file with a row for each message
+	MessageThreadExportFormatCSV = MessageThreadExportFormat("csv")
+
+	// MessageThreadExportFormatJSON exports a MessageThread as a JSON document with the full messages
+	MessageThreadExportFormatJSON = MessageThreadExportFormat("json")
+)
+
+// ContentType is the media type of the exported file
+func (format MessageThreadExportFormat) ContentType() string {
+	if format == MessageThreadExportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
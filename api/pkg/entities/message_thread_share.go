@@ -0,0 +1,14 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadShare is a signed public link to a read-only transcript of a MessageThread
+type MessageThreadShare struct {
+	MessageThreadID uuid.UUID `json:"message_thread_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	URL             string    `json:"url" example:"https://api.httpsms.com/shared/message-threads/eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"`
+	ExpiresAt       time.Time `json:"expires_at" example:"2022-06-06T14:26:09.527976+03:00"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadSummary is a short summary of the recent messages in a MessageThread which is generated by an LLM
+type MessageThreadSummary struct {
+	MessageThreadID uuid.UUID  `json:"message_thread_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner           string     `json:"owner" example:"+18005550199"`
+	Contact         string     `json:"contact" example:"+18005550100"`
+	Summary         string     `json:"summary" example:"The contact asked to reschedule the delivery to Friday and the owner confirmed the new date."`
+	MessageCount    int        `json:"message_count" example:"42"`
+	LastMessageID   *uuid.UUID `json:"last_message_id" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	CreatedAt       time.Time  `json:"created_at" example:"2022-06-05T14:26:09.527976+03:00"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+// MessageThreadTopic is the topic of the conversation in a MessageThread which is set by a classifier
+type MessageThreadTopic string
+
+const (
+	// MessageThreadTopicBilling is a conversation about payments, invoices or refunds
+	MessageThreadTopicBilling = MessageThreadTopic("billing")
+
+	// MessageThreadTopicSupport is a conversation where the contact needs help with a problem
+	MessageThreadTopicSupport = MessageThreadTopic("support")
+
+	// MessageThreadTopicSpam is an unsolicited conversation e.g. promotions or phishing
+	MessageThreadTopicSpam = MessageThreadTopic("spam")
+
+	// MessageThreadTopicOther is a conversation which does not match any other topic
+	MessageThreadTopicOther = MessageThreadTopic("other")
+)
+
+// MessageThreadTopics returns all the topics which a MessageThread can be classified with
+func MessageThreadTopics() []MessageThreadTopic {
+	return []MessageThreadTopic{
+		MessageThreadTopicBilling,
+		MessageThreadTopicSupport,
+		MessageThreadTopicSpam,
+		MessageThreadTopicOther,
+	}
+}
+
+// MessageThreadTopicCount is the number of message threads of a phone which are classified with the same topic and language
+type MessageThreadTopicCount struct {
+	Topic    string `json:"topic" example:"support"`
+	Language string `json:"language" example:"en"`
+	Count    int64  `json:"count" example:"12"`
+}
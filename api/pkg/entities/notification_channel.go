@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NotificationChannelProvider is the chat platform which receives the alerts of a NotificationChannel
+type NotificationChannelProvider string
+
+const (
+	// NotificationChannelProviderTeams sends alerts to a Microsoft Teams incoming webhook
+	NotificationChannelProviderTeams = NotificationChannelProvider("teams")
+
+	// NotificationChannelProviderMattermost sends alerts to a Mattermost incoming webhook
+	NotificationChannelProviderMattermost = NotificationChannelProvider("mattermost")
+
+	// NotificationChannelProviderRocketChat sends alerts to a Rocket.Chat incoming webhook
+	NotificationChannelProviderRocketChat = NotificationChannelProvider("rocketchat")
+)
+
+// String converts the NotificationChannelProvider to a string
+func (provider NotificationChannelProvider) String() string {
+	return string(provider)
+}
+
+// NotificationChannel is a chat channel which receives alerts about the phones and messages of a user
+type NotificationChannel struct {
+	ID         uuid.UUID                   `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID     UserID                      `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Provider   NotificationChannelProvider `json:"provider" example:"teams"`
+	Name       string                      `json:"name" example:"Operations"`
+	WebhookURL string                      `json:"webhook_url" example:"https://example.webhook.office.com/webhookb2/7a8a3b2c"`
+	Events     pq.StringArray              `json:"events" example:"[phone.heartbeat.offline,message.send.failed]" gorm:"type:text[]" swaggertype:"array,string"`
+	CreatedAt  time.Time                   `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt  time.Time                   `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Phone represents an android phone which has installed the http sms app
@@ -14,6 +15,28 @@ type Phone struct {
 	PhoneNumber       string    `json:"phone_number" example:"+18005550199"`
 	MessagesPerMinute uint      `json:"messages_per_minute" example:"1"`
 	SIM               SIM       `json:"sim" gorm:"default:SIM1"`
+
+	// SIMOperator is the name of the carrier of the SIM card which was last reported in a heartbeat
+	SIMOperator *string `json:"sim_operator" gorm:"column:sim_operator" example:"T-Mobile"`
+
+	// SIMICCID is the serial number of the SIM card which was last reported in a heartbeat
+	SIMICCID *string `json:"sim_iccid" gorm:"column:sim_iccid" example:"8901260222780116412"`
+
+	// SIMSwappedAt is the time when a heartbeat reported a different SIM card in the SIM slot of the phone
+	SIMSwappedAt *time.Time `json:"sim_swapped_at" gorm:"column:sim_swapped_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
+	// SIMSlots are the SIM slots which had a SIM card in the last heartbeat which reported the SIM cards of the phone
+	SIMSlots pq.StringArray `json:"sim_slots" gorm:"column:sim_slots;type:text[]" example:"[SIM1,SIM2]" swaggertype:"array,string"`
+
+	// MessagesPerHour is the number of messages which the phone sends in an hour before the next messages are delayed to avoid carrier spam blocks. It is not limited when it is 0.
+	MessagesPerHour uint `json:"messages_per_hour" gorm:"default:0" example:"100"`
+
+	// AppVersion is the version of the app which was installed on the phone when it was last registered
+	AppVersion *string `json:"app_version" example:"v1.0.0"`
+
+	// RegisteredAt is the time when the app last registered the phone with its FCM token
+	RegisteredAt *time.Time `json:"registered_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
 	// MaxSendAttempts determines how many times to retry sending an SMS message
 	MaxSendAttempts uint `json:"max_send_attempts" example:"2"`
 
@@ -22,10 +45,65 @@ type Phone struct {
 
 	MissedCallAutoReply *string `json:"missed_call_auto_reply" example:"This phone cannot receive calls. Please send an SMS instead."`
 
+	// PushProvider is the transport which is used to notify the phone of pending messages
+	PushProvider PhonePushProvider `json:"push_provider" gorm:"default:fcm" example:"fcm"`
+
+	// UnifiedPushEndpoint is the URL of the UnifiedPush distributor e.g. an ntfy topic which notifies the phone when the push provider is unified_push
+	UnifiedPushEndpoint *string `json:"unified_push_endpoint" example:"https://ntfy.sh/upYzMtZGZiNDYx?up=1"`
+
+	// SocketConnectedAt is the time when the app opened the WebSocket connection which it currently holds open to receive
+	// commands without push notifications. It is null when the phone is not connected.
+	SocketConnectedAt *time.Time `json:"socket_connected_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
+	// BackupPhoneNumber is the phone number of another phone of the user which sends the pending messages of this phone when it goes offline
+	BackupPhoneNumber *string `json:"backup_phone_number" example:"+18005550100"`
+
+	// HeartbeatTimeoutSeconds is the duration in seconds without a heartbeat after which the phone is considered to be offline.
+	HeartbeatTimeoutSeconds uint `json:"heartbeat_timeout_seconds" example:"3840"`
+
+	// HeartbeatIntervalSeconds is the expected duration in seconds between 2 heartbeats which is sent to the app so that it schedules its heartbeats.
+	HeartbeatIntervalSeconds uint `json:"heartbeat_interval_seconds" example:"900"`
+
+	// LowBalanceThreshold is the credit balance of the SIM below which an alert is sent. The alert is disabled when it is 0.
+	LowBalanceThreshold float64 `json:"low_balance_threshold" example:"5"`
+
+	// DuplicateWindowSeconds is the duration in seconds after receiving a message during which a duplicate message from the same contact is suppressed. It is disabled when it is 0.
+	DuplicateWindowSeconds uint `json:"duplicate_window_seconds" example:"300"`
+
+	// DuplicateStrategy determines how a received message is matched with the messages which were previously received from the same contact
+	DuplicateStrategy MessageDuplicateStrategy `json:"duplicate_strategy" gorm:"default:exact" example:"exact"`
+
+	// DeactivatedAt is the time when the phone was taken out of service. A deactivated phone is not assigned messages and its offline alerts are suppressed.
+	DeactivatedAt *time.Time `json:"deactivated_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
 	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
 
+// HasSIMSlot checks if a SIM slot of the phone has a SIM card. It is true for every SIM slot when the phone has not
+// reported its SIM cards in a heartbeat.
+func (phone *Phone) HasSIMSlot(sim SIM) bool {
+	if len(phone.SIMSlots) == 0 {
+		return true
+	}
+	for _, slot := range phone.SIMSlots {
+		if slot == sim.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeactivated checks if the phone was taken out of service
+func (phone *Phone) IsDeactivated() bool {
+	return phone.DeactivatedAt != nil
+}
+
+// UsesUnifiedPush checks if the phone is notified through its UnifiedPush endpoint instead of Firebase Cloud Messaging
+func (phone *Phone) UsesUnifiedPush() bool {
+	return phone.PushProvider == PhonePushProviderUnifiedPush && phone.UnifiedPushEndpoint != nil
+}
+
 // MessageExpirationDuration returns the message expiration as time.Duration
 func (phone *Phone) MessageExpirationDuration() time.Duration {
 	return time.Duration(int(phone.MessageExpirationSecondsSanitized())) * time.Second
@@ -39,6 +117,37 @@ func (phone *Phone) MessageExpirationSecondsSanitized() uint {
 	return phone.MessageExpirationSeconds
 }
 
+// HeartbeatTimeoutDuration returns the heartbeat timeout as time.Duration
+func (phone *Phone) HeartbeatTimeoutDuration() time.Duration {
+	return time.Duration(int(phone.HeartbeatTimeoutSecondsSanitized())) * time.Second
+}
+
+// HeartbeatTimeoutSecondsSanitized returns the heartbeat timeout seconds with default of 64 minutes
+func (phone *Phone) HeartbeatTimeoutSecondsSanitized() uint {
+	if phone.HeartbeatTimeoutSeconds == 0 {
+		return 64 * 60 // 64 minutes
+	}
+	return phone.HeartbeatTimeoutSeconds
+}
+
+// DuplicateWindowDuration returns the duplicate window as time.Duration
+func (phone *Phone) DuplicateWindowDuration() time.Duration {
+	return time.Duration(int(phone.DuplicateWindowSeconds)) * time.Second
+}
+
+// HeartbeatIntervalDuration returns the heartbeat interval as time.Duration
+func (phone *Phone) HeartbeatIntervalDuration() time.Duration {
+	return time.Duration(int(phone.HeartbeatIntervalSecondsSanitized())) * time.Second
+}
+
+// HeartbeatIntervalSecondsSanitized returns the heartbeat interval seconds with default of 15 minutes
+func (phone *Phone) HeartbeatIntervalSecondsSanitized() uint {
+	if phone.HeartbeatIntervalSeconds == 0 {
+		return 15 * 60 // 15 minutes
+	}
+	return phone.HeartbeatIntervalSeconds
+}
+
 // MaxSendAttemptsSanitized returns the max send attempts replacing 0 with 2
 func (phone *Phone) MaxSendAttemptsSanitized() uint {
 	if phone.MaxSendAttempts == 0 {
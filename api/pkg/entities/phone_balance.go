@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	phoneBalanceAmountRegex = regexp.MustCompile(`(?i:balance|bal|credit|airtime)\D{0,20}?([A-Z]{3}|[$€£₦])?\s?(\d[\d,]*(?:\.\d+)?)\s?([A-Z]{3})?`)
+	phoneBalanceExpiryRegex = regexp.MustCompile(`(?i:valid|expir\w*|until|till)\D{0,20}?(\d{4}-\d{2}-\d{2}|\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4})`)
+
+	// Carriers outside the US write dates with the day first so it is assumed that the day comes before the month
+	phoneBalanceExpiryLayouts = []string{"2006-01-02", "2/1/2006", "2/1/06", "2.1.2006", "2.1.06", "2-1-2006", "2-1-06"}
+)
+
+// PhoneBalance is the credit balance and plan expiry of the SIM of a phone which is reported by the app
+type PhoneBalance struct {
+	ID            uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID        UserID     `json:"user_id" gorm:"index:idx_phone_balances__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneID       uuid.UUID  `json:"phone_id" gorm:"type:uuid;index:idx_phone_balances__phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner         string     `json:"owner" example:"+18005550199"`
+	Amount        float64    `json:"amount" example:"12.5"`
+	Currency      string     `json:"currency" example:"USD"`
+	PlanExpiresAt *time.Time `json:"plan_expires_at" example:"2022-06-30T00:00:00Z"`
+	Content       string     `json:"content" example:"Your balance is USD 12.50, valid until 30/06/2022"`
+	ReportedAt    time.Time  `json:"reported_at" gorm:"index:idx_phone_balances__reported_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	CreatedAt     time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// IsBelow checks if the amount of the balance is below a threshold. A threshold of 0 is never crossed.
+func (balance *PhoneBalance) IsBelow(threshold float64) bool {
+	return threshold > 0 && balance.Amount < threshold
+}
+
+// PhoneBalanceReading is the credit balance and plan expiry which is parsed from the USSD response or the SMS of a carrier
+type PhoneBalanceReading struct {
+	Amount        float64
+	Currency      string
+	PlanExpiresAt *time.Time
+}
+
+// ParsePhoneBalance extracts the credit balance and plan expiry from the USSD response or the SMS of a carrier.
+// It returns nil when the content does not contain a balance.
+func ParsePhoneBalance(content string) *PhoneBalanceReading {
+	match := phoneBalanceAmountRegex.FindStringSubmatch(content)
+	if match == nil {
+		return nil
+	}
+
+	amount, err := strconv.ParseFloat(phoneBalanceAmount(match[2]), 64)
+	if err != nil {
+		return nil
+	}
+
+	reading := &PhoneBalanceReading{Amount: amount, Currency: phoneBalanceCurrency(match[1])}
+	if reading.Currency == "" {
+		reading.Currency = phoneBalanceCurrency(match[3])
+	}
+
+	if match = phoneBalanceExpiryRegex.FindStringSubmatch(content); match != nil {
+		for _, layout := range phoneBalanceExpiryLayouts {
+			if expiresAt, err := time.Parse(layout, match[1]); err == nil {
+				reading.PlanExpiresAt = &expiresAt
+				break
+			}
+		}
+	}
+
+	return reading
+}
+
+// phoneBalanceAmount normalizes the thousands and decimal separators of an amount e.g. "1,200.50" and "12,50"
+func phoneBalanceAmount(value string) string {
+	if strings.Contains(value, ".") {
+		return strings.ReplaceAll(value, ",", "")
+	}
+
+	if index := strings.LastIndex(value, ","); index != -1 && len(value)-index-1 <= 2 {
+		value = value[:index] + "." + value[index+1:]
+	}
+	return strings.ReplaceAll(value, ",", "")
+}
+
+func phoneBalanceCurrency(value string) string {
+	switch value {
+	case "$":
+		return "USD"
+	case "€":
+		return "EUR"
+	case "£":
+		return "GBP"
+	case "₦":
+		return "NGN"
+	default:
+		return value
+	}
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneCrashType is the type of failure which is reported by the app on a phone
+type PhoneCrashType string
+
+const (
+	// PhoneCrashTypeCrash is an uncaught exception which stopped the app
+	PhoneCrashTypeCrash = PhoneCrashType("crash")
+
+	// PhoneCrashTypeANR is when the app was not responding
+	PhoneCrashTypeANR = PhoneCrashType("anr")
+)
+
+// PhoneCrash is a crash or ANR of the app on a phone with the stack trace
+type PhoneCrash struct {
+	ID         uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID     UserID         `json:"user_id" gorm:"index:idx_phone_crashes__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneID    uuid.UUID      `json:"phone_id" gorm:"type:uuid;index:idx_phone_crashes__phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner      string         `json:"owner" example:"+18005550199"`
+	Type       PhoneCrashType `json:"type" example:"crash"`
+	Message    string         `json:"message" example:"java.lang.NullPointerException: Attempt to invoke virtual method on a null object reference"`
+	StackTrace string         `json:"stack_trace" gorm:"type:text" example:"at com.httpsms.SmsManagerService.sendTextMessage(SmsManagerService.kt:42)"`
+	AppVersion string         `json:"app_version" example:"v1.0.0"`
+	OccurredAt time.Time      `json:"occurred_at" gorm:"index:idx_phone_crashes__occurred_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	CreatedAt  time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// PhoneCrashCount is the number of crashes and ANRs of the app on a phone
+type PhoneCrashCount struct {
+	UserID UserID         `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner  string         `json:"owner" example:"+18005550199"`
+	Type   PhoneCrashType `json:"type" example:"crash"`
+	Count  int64          `json:"count" example:"3"`
+}
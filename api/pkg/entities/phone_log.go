@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneLog is a file with the recent logs of the app on a phone which is stored as an Attachment
+type PhoneLog struct {
+	ID           uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID       UserID    `json:"user_id" gorm:"index:idx_phone_logs__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneID      uuid.UUID `json:"phone_id" gorm:"type:uuid;index:idx_phone_logs__phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	AttachmentID uuid.UUID `json:"attachment_id" gorm:"type:uuid" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	Name         string    `json:"name" example:"httpsms.log"`
+	Size         int64     `json:"size" example:"102400"`
+	URL          string    `json:"url" example:"https://api.httpsms.com/v1/attachments/32343a19-da5e-4b1b-a767-3298a73703ca"`
+	CreatedAt    time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// PhoneLogRequest is a request to the app on a phone to upload its recent logs
+type PhoneLogRequest struct {
+	PhoneID     uuid.UUID `json:"phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID    `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner       string    `json:"owner" example:"+18005550199"`
+	RequestedAt time.Time `json:"requested_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
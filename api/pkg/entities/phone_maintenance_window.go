@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneMaintenanceWindow is a period during which a phone is expected to be offline e.g. while it is being updated
+type PhoneMaintenanceWindow struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID    `json:"user_id" gorm:"index:idx_phone_maintenance_windows__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneID   uuid.UUID `json:"phone_id" gorm:"type:uuid;index:idx_phone_maintenance_windows__phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner     string    `json:"owner" example:"+18005550199"`
+	Reason    string    `json:"reason" example:"Android system update"`
+	StartsAt  time.Time `json:"starts_at" example:"2022-06-05T14:00:00+03:00"`
+	EndsAt    time.Time `json:"ends_at" example:"2022-06-05T16:00:00+03:00"`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Contains checks if a timestamp is within the maintenance window
+func (window *PhoneMaintenanceWindow) Contains(timestamp time.Time) bool {
+	return !timestamp.Before(window.StartsAt) && timestamp.Before(window.EndsAt)
+}
+
+// Overlap returns the duration of the maintenance window which is within a time range
+func (window *PhoneMaintenanceWindow) Overlap(from time.Time, to time.Time) time.Duration {
+	if window.StartsAt.After(from) {
+		from = window.StartsAt
+	}
+	if window.EndsAt.Before(to) {
+		to = window.EndsAt
+	}
+	return max(0, to.Sub(from))
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PhonePoolStrategy determines how the messages which are sent from a PhonePool are distributed across its phones
+type PhonePoolStrategy string
+
+const (
+	// PhonePoolStrategyRoundRobin sends each message from the next phone of the pool
+	PhonePoolStrategyRoundRobin = PhonePoolStrategy("round_robin")
+
+	// PhonePoolStrategyLeastLoaded sends each message from the phone of the pool with the fewest outstanding messages
+	PhonePoolStrategyLeastLoaded = PhonePoolStrategy("least_loaded")
+)
+
+// PhonePoolStrategies are all the strategies which can be used to distribute the messages of a PhonePool
+var PhonePoolStrategies = []PhonePoolStrategy{
+	PhonePoolStrategyRoundRobin,
+	PhonePoolStrategyLeastLoaded,
+}
+
+// String converts the PhonePoolStrategy to a string
+func (strategy PhonePoolStrategy) String() string {
+	return string(strategy)
+}
+
+// PhonePool is a group of phones which share the outgoing messages which are addressed from the pool
+type PhonePool struct {
+	ID           uuid.UUID         `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID       UserID            `json:"user_id" gorm:"index:idx_phone_pools__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name         string            `json:"name" example:"Marketing phones"`
+	Strategy     PhonePoolStrategy `json:"strategy" gorm:"default:round_robin" example:"round_robin"`
+	PhoneNumbers pq.StringArray    `json:"phone_numbers" example:"[+18005550199,+18005550100]" gorm:"type:text[]" swaggertype:"array,string"`
+
+	// Assignments is the number of messages which were assigned to a phone with the round robin strategy
+	Assignments uint64 `json:"-" gorm:"default:0"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
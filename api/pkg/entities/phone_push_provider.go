@@ -0,0 +1,23 @@
+package entities
+
+// PhonePushProvider is the transport which is used to notify a phone of pending messages
+type PhonePushProvider string
+
+const (
+	// PhonePushProviderFCM sends the notifications with Firebase Cloud Messaging
+	PhonePushProviderFCM = PhonePushProvider("fcm")
+
+	// PhonePushProviderUnifiedPush sends the notifications to the UnifiedPush endpoint of the phone e.g. an ntfy topic
+	PhonePushProviderUnifiedPush = PhonePushProvider("unified_push")
+)
+
+// PhonePushProviders are all the transports which can be used to notify a phone
+var PhonePushProviders = []PhonePushProvider{
+	PhonePushProviderFCM,
+	PhonePushProviderUnifiedPush,
+}
+
+// String converts the PhonePushProvider to a string
+func (provider PhonePushProvider) String() string {
+	return string(provider)
+}
@@ -0,0 +1,8 @@
+package entities
+
+// PhoneQueueDepth is the number of outgoing messages of a phone which are waiting to be sent
+type PhoneQueueDepth struct {
+	UserID UserID `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner  string `json:"owner" example:"+18005550199"`
+	Count  int64  `json:"count" example:"12"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+// PhoneQueueEntry is an outgoing message in the queue of a phone with its position in the dispatch order
+type PhoneQueueEntry struct {
+	// Position is the 1-based position of the message in the queue, the message at position 1 is sent next
+	Position int `json:"position" example:"1"`
+
+	// AgeSeconds is the number of seconds since the request to send the message was received
+	AgeSeconds int64 `json:"age_seconds" example:"42"`
+
+	Message *Message `json:"message"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneQueueStats is the number of outgoing messages of a phone in each state of its queue which shows when a backlog
+// is building up on the phone
+type PhoneQueueStats struct {
+	PhoneID uuid.UUID `json:"phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner   string    `json:"owner" example:"+18005550199"`
+
+	// Pending is the number of messages which are waiting to be sent by the phone
+	Pending int64 `json:"pending" example:"12"`
+
+	// Scheduled is the number of messages which are held until their send time
+	Scheduled int64 `json:"scheduled" example:"3"`
+
+	// Sending is the number of messages which were fetched by the phone and are being sent
+	Sending int64 `json:"sending" example:"1"`
+
+	// Failed is the number of messages which failed since the Since timestamp
+	Failed int64 `json:"failed" example:"2"`
+
+	// Expired is the number of messages which expired since the Since timestamp
+	Expired int64 `json:"expired" example:"0"`
+
+	// OldestPendingAt is the time when the request to send the oldest pending message was received. It is null when no message is pending
+	OldestPendingAt *time.Time `json:"oldest_pending_at" example:"2022-06-05T14:26:09.527976+03:00"`
+
+	// OldestPendingAgeSeconds is the number of seconds since the request to send the oldest pending message was received
+	OldestPendingAgeSeconds int64 `json:"oldest_pending_age_seconds" example:"320"`
+
+	// Since is the start of the period in which the failed and expired messages are counted
+	Since time.Time `json:"since" example:"2022-06-04T14:26:09.527976+03:00"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneResync is a request to reconcile the outgoing messages of a phone with the server e.g. after the app was reinstalled
+type PhoneResync struct {
+	PhoneID uuid.UUID `json:"phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID  UserID    `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner   string    `json:"owner" example:"+18005550199"`
+
+	// MessageCount is the number of pending messages which were pushed to the phone again
+	MessageCount int       `json:"message_count" example:"3"`
+	RequestedAt  time.Time `json:"requested_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
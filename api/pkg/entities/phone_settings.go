@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneSettings is the configuration of a phone which is managed on the server and fetched by the app when it starts
+type PhoneSettings struct {
+	PhoneID uuid.UUID `json:"phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Owner   string    `json:"owner" example:"+18005550199"`
+
+	// HeartbeatIntervalSeconds is the duration in seconds between 2 heartbeats which the app uses to schedule its heartbeats
+	HeartbeatIntervalSeconds uint `json:"heartbeat_interval_seconds" example:"900"`
+
+	// SIM is the SIM slot which sends the messages of the phone when a message does not choose a SIM slot
+	SIM SIM `json:"sim" example:"SIM1"`
+
+	// MessagesPerMinute is the number of messages which the app sends in a minute
+	MessagesPerMinute uint `json:"messages_per_minute" example:"10"`
+
+	// MessagesPerHour is the number of messages which the app sends in an hour, it is not limited when it is 0
+	MessagesPerHour uint `json:"messages_per_hour" example:"100"`
+
+	// MaxSendAttempts is the number of attempts when sending a message
+	MaxSendAttempts uint `json:"max_send_attempts" example:"2"`
+
+	// PushProvider is the transport which notifies the phone of pending messages
+	PushProvider PhonePushProvider `json:"push_provider" example:"fcm"`
+
+	// WebhooksEnabled is true when a webhook which is not paused receives the events of the phone
+	WebhooksEnabled bool `json:"webhooks_enabled" example:"true"`
+
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
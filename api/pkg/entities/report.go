@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportType is the content of a scheduled Report
+type ReportType string
+
+const (
+	// ReportTypeDailyUsage is a CSV with the number of messages sent, delivered, failed, expired and received by every phone in the last day
+	ReportTypeDailyUsage = ReportType("daily-usage")
+
+	// ReportTypeWeeklyFailures is a CSV with the number of failed messages of every phone grouped by failure reason in the last week
+	ReportTypeWeeklyFailures = ReportType("weekly-failures")
+)
+
+// String converts the ReportType to a string
+func (reportType ReportType) String() string {
+	return string(reportType)
+}
+
+// Period is the duration of time which is covered by a report
+func (reportType ReportType) Period() time.Duration {
+	if reportType == ReportTypeWeeklyFailures {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// NextRunAt is the end of the period which contains the timestamp, days start at midnight UTC and weeks start on Monday
+func (reportType ReportType) NextRunAt(timestamp time.Time) time.Time {
+	timestamp = timestamp.UTC()
+	next := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if reportType == ReportTypeWeeklyFailures {
+		for next.Weekday() != time.Monday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// ReportDelivery is how a generated Report is sent to the user
+type ReportDelivery string
+
+const (
+	// ReportDeliveryEmail sends the report as an attachment to the email address of the user
+	ReportDeliveryEmail = ReportDelivery("email")
+
+	// ReportDeliveryWebhook sends the report.generated event with a signed download URL to the webhooks of the user
+	ReportDeliveryWebhook = ReportDelivery("webhook")
+)
+
+// String converts the ReportDelivery to a string
+func (delivery ReportDelivery) String() string {
+	return string(delivery)
+}
+
+// Report is a CSV report which is generated at the end of every period and delivered to the user
+type Report struct {
+	ID         uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID     UserID         `json:"user_id" gorm:"index:idx_reports__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Type       ReportType     `json:"type" example:"daily-usage"`
+	Delivery   ReportDelivery `json:"delivery" example:"email"`
+	NextRunAt  time.Time      `json:"next_run_at" gorm:"index:idx_reports__next_run_at" example:"2022-06-06T00:00:00Z"`
+	LastSentAt *time.Time     `json:"last_sent_at" example:"2022-06-05T00:00:02.302718Z"`
+	CreatedAt  time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt  time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
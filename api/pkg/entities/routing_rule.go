@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoutingRule chooses the phone which sends an outgoing message when the message does not have a "from" phone number
+type RoutingRule struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID    `json:"user_id" gorm:"index:idx_routing_rules__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name        string    `json:"name" example:"US customers"`
+	Position    uint      `json:"position" example:"1"`
+	Prefix      string    `json:"prefix" example:"+1800"`
+	Country     string    `json:"country" example:"US"`
+	Tag         string    `json:"tag" example:"alerts"`
+	PhoneNumber string    `json:"phone_number" example:"+18005550199"`
+	CreatedAt   time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Matches checks if an outgoing message matches the rule. The prefix is matched against the start of the contact, the
+// country is the region of the contact and the tag is the routing tag of the message. An empty value matches every message.
+func (rule *RoutingRule) Matches(contact string, country string, tag string) bool {
+	if rule.Prefix != "" && !strings.HasPrefix(contact, rule.Prefix) {
+		return false
+	}
+
+	if rule.Country != "" && !strings.EqualFold(rule.Country, country) {
+		return false
+	}
+
+	if rule.Tag != "" && !strings.EqualFold(rule.Tag, tag) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Suppression is a contact who opted out of receiving marketing messages from a phone number
+type Suppression struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID    `json:"user_id" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner     string    `json:"owner" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact" example:"+18005550199"`
+	Contact   string    `json:"contact" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact" example:"+18005550100"`
+	MessageID uuid.UUID `json:"message_id" gorm:"type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+var (
+	suppressionOptOutKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT"}
+	suppressionOptInKeywords  = []string{"START", "UNSTOP", "SUBSCRIBE"}
+)
+
+// IsOptOutKeyword checks if the content of a received message is a request to stop receiving marketing messages
+func IsOptOutKeyword(content string) bool {
+	return suppressionKeywordMatches(content, suppressionOptOutKeywords)
+}
+
+// IsOptInKeyword checks if the content of a received message is a request to receive marketing messages again
+func IsOptInKeyword(content string) bool {
+	return suppressionKeywordMatches(content, suppressionOptInKeywords)
+}
+
+func suppressionKeywordMatches(content string, keywords []string) bool {
+	content = strings.ToUpper(strings.Trim(strings.TrimSpace(content), ".!"))
+	for _, keyword := range keywords {
+		if content == keyword {
+			return true
+		}
+	}
+	return false
+}
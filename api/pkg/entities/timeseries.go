@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// TimeseriesBucket is the number of entities of a phone in a time bucket
+type TimeseriesBucket struct {
+	Owner     string    `json:"owner" example:"+18005550199"`
+	Timestamp time.Time `json:"timestamp" example:"2022-06-05T14:00:00Z"`
+	Count     int64     `json:"count" example:"12"`
+}
+
+// Timeseries is a series of data points in the format of the Grafana JSON datasource
+type Timeseries struct {
+	Target string `json:"target" example:"messages.sent"`
+	// Datapoints are pairs of [value, unix timestamp in milliseconds]
+	Datapoints [][2]float64 `json:"datapoints"`
+}
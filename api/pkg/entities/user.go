@@ -86,8 +86,18 @@ type User struct {
 	NotificationWebhookEnabled       bool             `json:"notification_webhook_enabled" gorm:"default:true" example:"true"`
 	NotificationHeartbeatEnabled     bool             `json:"notification_heartbeat_enabled" gorm:"default:true" example:"true"`
 	NotificationNewsletterEnabled    bool             `json:"notification_newsletter_enabled" gorm:"default:true" example:"true"`
-	CreatedAt                        time.Time        `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
-	UpdatedAt                        time.Time        `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+	OptOutFooter                     *string          `json:"opt_out_footer" example:"Reply STOP to unsubscribe"`
+	PreferredLanguage                *string          `json:"preferred_language" example:"en"`
+	DataRegion                       *string          `json:"data_region" example:"eu"`
+
+	// HeartbeatOfflineThreshold is the number of consecutive missed heartbeats after which a phone is offline. The heartbeat timeout of the phone is used when it is 0.
+	HeartbeatOfflineThreshold uint `json:"heartbeat_offline_threshold" example:"3"`
+
+	// HeartbeatAlertCooldownSeconds is the duration in seconds after a phone goes offline during which it is not reported as offline again. It is disabled when it is 0.
+	HeartbeatAlertCooldownSeconds uint `json:"heartbeat_alert_cooldown_seconds" example:"3600"`
+
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
 
 // IsOnProPlan checks if a user is on the pro plan
@@ -119,6 +129,20 @@ func (user User) UserTimeString(timestamp time.Time) string {
 	return timestamp.In(location).Format(time.RFC1123)
 }
 
+// HeartbeatOfflineTimeout returns the duration without a heartbeat after which a phone with the heartbeat interval and
+// timeout is offline. It is the duration of HeartbeatOfflineThreshold missed heartbeats if the threshold is configured.
+func (user User) HeartbeatOfflineTimeout(interval time.Duration, timeout time.Duration) time.Duration {
+	if user.HeartbeatOfflineThreshold == 0 {
+		return timeout
+	}
+	return time.Duration(user.HeartbeatOfflineThreshold) * interval
+}
+
+// HeartbeatAlertCooldownDuration returns the heartbeat alert cool-down as time.Duration
+func (user User) HeartbeatAlertCooldownDuration() time.Duration {
+	return time.Duration(user.HeartbeatAlertCooldownSeconds) * time.Second
+}
+
 // Location gets the timezone of a user
 func (user User) Location() *time.Location {
 	location, err := time.LoadLocation(user.Timezone)
@@ -127,3 +151,11 @@ func (user User) Location() *time.Location {
 	}
 	return location
 }
+
+// StorageRegion returns the region where the message data of the user is stored or an empty string for the default region
+func (user User) StorageRegion() string {
+	if user.DataRegion == nil {
+		return ""
+	}
+	return *user.DataRegion
+}
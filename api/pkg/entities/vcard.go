@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"strings"
+)
+
+const (
+	vCardBegin   = "BEGIN:VCARD"
+	vCardEnd     = "END:VCARD"
+	vCardVersion = "VERSION:3.0"
+)
+
+// VCard is a contact card which is shared as a vCard attachment in a message
+type VCard struct {
+	Name         string   `json:"name" example:"John Doe"`
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550199"`
+	Emails       []string `json:"emails" example:"john@example.com"`
+	Organization string   `json:"organization" example:"Acme Inc"`
+}
+
+// String serializes the VCard into the vCard 3.0 format
+func (card VCard) String() string {
+	lines := []string{vCardBegin, vCardVersion, "FN:" + vCardEscape(card.Name)}
+	for _, phoneNumber := range card.PhoneNumbers {
+		lines = append(lines, "TEL;TYPE=CELL:"+vCardEscape(phoneNumber))
+	}
+	for _, email := range card.Emails {
+		lines = append(lines, "EMAIL:"+vCardEscape(email))
+	}
+	if card.Organization != "" {
+		lines = append(lines, "ORG:"+vCardEscape(card.Organization))
+	}
+	lines = append(lines, vCardEnd)
+	return strings.Join(lines, "\r\n")
+}
+
+// ContainsVCard checks if the content of a message has a vCard attachment
+func ContainsVCard(content string) bool {
+	return strings.Contains(strings.ToUpper(content), vCardBegin)
+}
+
+// ParseVCards extracts the contacts from the vCard attachments in the content of a message
+func ParseVCards(content string) []VCard {
+	var cards []VCard
+	var card *VCard
+
+	for _, line := range vCardUnfold(content) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		property := strings.ToUpper(strings.TrimSpace(name))
+		if index := strings.Index(property, ";"); index != -1 {
+			property = property[:index]
+		}
+		if index := strings.Index(property, "."); index != -1 {
+			property = property[index+1:]
+		}
+
+		value = vCardUnescape(strings.TrimSpace(value))
+		switch {
+		case property == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			card = &VCard{}
+		case card == nil:
+			continue
+		case property == "END" && strings.EqualFold(value, "VCARD"):
+			if card.Name != "" || len(card.PhoneNumbers) > 0 {
+				cards = append(cards, *card)
+			}
+			card = nil
+		case property == "FN" && value != "":
+			card.Name = value
+		case property == "N" && card.Name == "":
+			card.Name = vCardStructuredName(value)
+		case property == "TEL" && value != "":
+			card.PhoneNumbers = append(card.PhoneNumbers, strings.TrimPrefix(value, "tel:"))
+		case property == "EMAIL" && value != "":
+			card.Emails = append(card.Emails, value)
+		case property == "ORG" && value != "":
+			card.Organization = strings.TrimSpace(strings.ReplaceAll(value, ";", " "))
+		}
+	}
+
+	return cards
+}
+
+// vCardUnfold splits the content into lines and joins folded lines as described in RFC 6350 section 3.2
+func vCardUnfold(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// vCardStructuredName converts the N property (family;given;additional;prefix;suffix) into a display name
+func vCardStructuredName(value string) string {
+	parts := strings.Split(value, ";")
+	if len(parts) < 2 {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(strings.Join(strings.Fields(parts[1]+" "+parts[0]), " "))
+}
+
+func vCardEscape(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n", ",", "\\,", ";", "\\;").Replace(strings.TrimSpace(value))
+}
+
+func vCardUnescape(value string) string {
+	return strings.NewReplacer("\\n", "\n", "\\N", "\n", "\\,", ",", "\\;", ";", "\\\\", "\\").Replace(value)
+}
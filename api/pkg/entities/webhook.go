@@ -7,14 +7,62 @@ import (
 	"github.com/lib/pq"
 )
 
+// WebhookPausedReasonUser is the reason of a webhook which was paused by the user
+const WebhookPausedReasonUser = "paused by the user"
+
 // Webhook stores the webhooks of a user
 type Webhook struct {
-	ID           uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
-	UserID       UserID         `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
-	URL          string         `json:"url" example:"https://example.com"`
-	SigningKey   string         `json:"signing_key" example:"DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
-	PhoneNumbers pq.StringArray `json:"phone_numbers" example:"[+18005550199,+18005550100]" gorm:"type:text[]" swaggertype:"array,string"`
-	Events       pq.StringArray `json:"events" example:"[message.phone.received]" gorm:"type:text[]" swaggertype:"array,string"`
-	CreatedAt    time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
-	UpdatedAt    time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+	ID              uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID          UserID         `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	URL             string         `json:"url" example:"https://example.com"`
+	SigningKey      string         `json:"signing_key" example:"DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY"`
+	PhoneNumbers    pq.StringArray `json:"phone_numbers" example:"[+18005550199,+18005550100]" gorm:"type:text[]" swaggertype:"array,string"`
+	Events          pq.StringArray `json:"events" example:"[message.phone.received]" gorm:"type:text[]" swaggertype:"array,string"`
+	PayloadTemplate string         `json:"payload_template" example:"{\"text\": {{ json .Data.content }}}"`
+	Languages       pq.StringArray `json:"languages" example:"[fr]" gorm:"type:text[]" swaggertype:"array,string"`
+	// TimeoutSeconds is the duration in seconds to wait for the response of the webhook before the delivery fails
+	TimeoutSeconds      uint       `json:"timeout_seconds" gorm:"default:10" example:"10"`
+	ConsecutiveFailures uint       `json:"consecutive_failures" example:"0"`
+	PausedAt            *time.Time `json:"paused_at" example:"2022-06-05T14:26:10.303278+03:00"`
+	PausedReason        *string    `json:"paused_reason" example:"paused by the user"`
+	CreatedAt           time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt           time.Time  `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsPaused checks if events are not sent to the webhook
+func (webhook *Webhook) IsPaused() bool {
+	return webhook.PausedAt != nil
+}
+
+// Timeout returns the duration to wait for the response of the webhook which defaults to 10 seconds
+func (webhook *Webhook) Timeout() time.Duration {
+	if webhook.TimeoutSeconds == 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(webhook.TimeoutSeconds) * time.Second
+}
+
+// IsSubscribed checks if the webhook is subscribed to an event type
+func (webhook *Webhook) IsSubscribed(event string) bool {
+	for _, item := range webhook.Events {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Pause stops sending events to the webhook
+func (webhook *Webhook) Pause(timestamp time.Time, reason string) *Webhook {
+	webhook.PausedAt = &timestamp
+	webhook.PausedReason = &reason
+	return webhook
+}
+
+// Resume sends events to a paused webhook again
+func (webhook *Webhook) Resume() *Webhook {
+	webhook.PausedAt = nil
+	webhook.PausedReason = nil
+	webhook.ConsecutiveFailures = 0
+	return webhook
 }
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is an event which was sent to the webhooks of a user, it is stored so that it can be replayed to a webhook
+// which did not receive it e.g. when the receiver was down.
+type WebhookEvent struct {
+	ID      uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID  UserID    `json:"user_id" gorm:"index:idx_webhook_events__user_id__timestamp,priority:1" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	EventID string    `json:"event_id" example:"0e8a2c8d-8f2b-4b4d-9a2c-6c3c7c1a2b3d"`
+	Type    string    `json:"type" example:"message.phone.received"`
+	// Owner is the phone number of the event, it is empty for the events which are not sent by a phone
+	Owner string `json:"owner" example:"+18005550199"`
+	// Event is the cloud event encoded as JSON
+	Event     json.RawMessage `json:"event" gorm:"type:jsonb" swaggertype:"object"`
+	Timestamp time.Time       `json:"timestamp" gorm:"index:idx_webhook_events__user_id__timestamp,priority:2;index:idx_webhook_events__timestamp" example:"2022-06-05T14:26:02.302718+03:00"`
+	CreatedAt time.Time       `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// WebhookReplay is the result of replaying the stored events of a time range to an entities.Webhook
+type WebhookReplay struct {
+	WebhookID uuid.UUID `json:"webhook_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	From      time.Time `json:"from" example:"2022-06-05T00:00:00Z"`
+	To        time.Time `json:"to" example:"2022-06-06T00:00:00Z"`
+	Events    []string  `json:"events" example:"[message.phone.received]"`
+	// Count is the number of events which are queued to be sent to the webhook
+	Count int `json:"count" example:"120"`
+	// NextFrom is the timestamp of the last replayed event when the time range has more events than can be replayed in one request
+	// or the delivery queue of the webhook is full, it is used as the start of the next replay
+	NextFrom *time.Time `json:"next_from" example:"2022-06-05T18:02:10Z"`
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeCustomEventPublished is emitted when a user publishes an entities.CustomEvent with the API
+const EventTypeCustomEventPublished = "event.custom.published"
+
+// CustomEventPublishedPayload is the payload of the EventTypeCustomEventPublished event
+type CustomEventPublishedPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    entities.UserID `json:"user_id"`
+	Type      string          `json:"type"`
+	Source    string          `json:"source"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
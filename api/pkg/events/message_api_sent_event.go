@@ -13,15 +13,23 @@ const EventTypeMessageAPISent = "message.api.sent"
 
 // MessageAPISentPayload is the payload of the EventTypeMessageSent event
 type MessageAPISentPayload struct {
-	MessageID         uuid.UUID       `json:"message_id"`
-	UserID            entities.UserID `json:"user_id"`
-	Owner             string          `json:"owner"`
-	RequestID         *string         `json:"request_id"`
-	MaxSendAttempts   uint            `json:"max_send_attempts"`
-	Contact           string          `json:"contact"`
-	ScheduledSendTime *time.Time      `json:"scheduled_send_time"`
-	RequestReceivedAt time.Time       `json:"request_received_at"`
-	Content           string          `json:"content"`
-	Encrypted         bool            `json:"encrypted"`
-	SIM               entities.SIM    `json:"sim"`
+	MessageID         uuid.UUID                `json:"message_id"`
+	UserID            entities.UserID          `json:"user_id"`
+	Owner             string                   `json:"owner"`
+	RequestID         *string                  `json:"request_id"`
+	MaxSendAttempts   uint                     `json:"max_send_attempts"`
+	Contact           string                   `json:"contact"`
+	ScheduledSendTime *time.Time               `json:"scheduled_send_time"`
+	ExpiresAt         *time.Time               `json:"expires_at"`
+	RequestReceivedAt time.Time                `json:"request_received_at"`
+	Content           string                   `json:"content"`
+	Encrypted         bool                     `json:"encrypted"`
+	SIM               entities.SIM             `json:"sim"`
+	Channel           entities.MessageChannel  `json:"channel"`
+	MediaURLs         []string                 `json:"media_urls"`
+	Category          entities.MessageCategory `json:"category"`
+	Priority          entities.MessagePriority `json:"priority"`
+	SenderName        *string                  `json:"sender_name"`
+	PhonePoolID       *uuid.UUID               `json:"phone_pool_id"`
+	Language          *string                  `json:"language"`
 }
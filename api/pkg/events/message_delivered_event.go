@@ -0,0 +1,28 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageDelivered is emitted when the carrier reports that a message has been delivered to the recipient
+const EventTypeMessageDelivered = "message.delivered"
+
+// MessageDeliveredPayload is the payload of the EventTypeMessageDelivered event
+type MessageDeliveredPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Owner     string          `json:"owner"`
+	Contact   string          `json:"contact"`
+	RequestID *string         `json:"request_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Encrypted bool            `json:"encrypted"`
+	Timestamp time.Time       `json:"timestamp"`
+	Content   string          `json:"content"`
+	SIM       entities.SIM    `json:"sim"`
+
+	// StatusCode and PDU are the raw TP-Status code and PDU of the delivery report which was sent by the carrier
+	StatusCode *uint   `json:"status_code"`
+	PDU        *string `json:"pdu"`
+}
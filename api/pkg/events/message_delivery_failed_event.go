@@ -0,0 +1,29 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageDeliveryFailed is emitted when the carrier reports that a message which was sent by the phone could not be delivered to the recipient
+const EventTypeMessageDeliveryFailed = "message.delivery.failed"
+
+// MessageDeliveryFailedPayload is the payload of the EventTypeMessageDeliveryFailed event
+type MessageDeliveryFailedPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Owner     string          `json:"owner"`
+	Contact   string          `json:"contact"`
+	RequestID *string         `json:"request_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Encrypted bool            `json:"encrypted"`
+	ErrorCode string          `json:"error_code"`
+	Timestamp time.Time       `json:"timestamp"`
+	Content   string          `json:"content"`
+	SIM       entities.SIM    `json:"sim"`
+
+	// StatusCode and PDU are the raw TP-Status code and PDU of the delivery report which was sent by the carrier
+	StatusCode *uint   `json:"status_code"`
+	PDU        *string `json:"pdu"`
+}
@@ -21,4 +21,12 @@ type MessagePhoneReceivedPayload struct {
 	Timestamp time.Time       `json:"timestamp"`
 	Content   string          `json:"content"`
 	SIM       entities.SIM    `json:"sim"`
+	// Language is the language which was detected in the content of the message e.g. "fr"
+	Language *string `json:"language,omitempty"`
+	// MediaURLs are the URLs of the attachments of an MMS message
+	MediaURLs []string `json:"media_urls,omitempty"`
+	// SuggestedContacts are the contacts parsed from vCard attachments in the message
+	SuggestedContacts []entities.VCard `json:"suggested_contacts,omitempty"`
+	// Muted is true when the message thread with the contact is muted so the message is not forwarded to webhooks or push notifications
+	Muted bool `json:"muted,omitempty"`
 }
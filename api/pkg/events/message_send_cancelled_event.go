@@ -0,0 +1,25 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageSendCancelled is emitted when a message is cancelled before it is picked up by the phone
+const EventTypeMessageSendCancelled = "message.send.cancelled"
+
+// MessageSendCancelledPayload is the payload of the EventTypeMessageSendCancelled event
+type MessageSendCancelledPayload struct {
+	MessageID uuid.UUID       `json:"message_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Owner     string          `json:"owner"`
+	RequestID *string         `json:"request_id"`
+	Contact   string          `json:"contact"`
+	Encrypted bool            `json:"encrypted"`
+	Content   string          `json:"content"`
+	SIM       entities.SIM    `json:"sim"`
+	Timestamp time.Time       `json:"timestamp"`
+}
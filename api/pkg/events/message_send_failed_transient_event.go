@@ -0,0 +1,28 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageSendFailedTransient is emitted when the phone could not send a message because of a temporary error and the message will be retried
+const EventTypeMessageSendFailedTransient = "message.send.failed.transient"
+
+// MessageSendFailedTransientPayload is the payload of the EventTypeMessageSendFailedTransient event
+type MessageSendFailedTransientPayload struct {
+	ID               uuid.UUID       `json:"id"`
+	ErrorMessage     string          `json:"error_message"`
+	UserID           entities.UserID `json:"user_id"`
+	Owner            string          `json:"owner"`
+	RequestID        *string         `json:"request_id"`
+	Contact          string          `json:"contact"`
+	Timestamp        time.Time       `json:"timestamp"`
+	Encrypted        bool            `json:"encrypted"`
+	Content          string          `json:"content"`
+	SIM              entities.SIM    `json:"sim"`
+	SendAttemptCount uint            `json:"send_attempt_count"`
+	MaxSendAttempts  uint            `json:"max_send_attempts"`
+	RetryAt          time.Time       `json:"retry_at"`
+}
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadAPIBulkDeleted is emitted when many message threads are deleted with a single request
+const MessageThreadAPIBulkDeleted = "message-thread.api.bulk-deleted"
+
+// MessageThreadAPIBulkDeletedPayload is the payload of the MessageThreadAPIBulkDeleted event
+type MessageThreadAPIBulkDeletedPayload struct {
+	UserID           entities.UserID `json:"user_id"`
+	MessageThreadIDs []uuid.UUID     `json:"message_thread_ids"`
+	OlderThan        *time.Time      `json:"older_than"`
+	Count            int             `json:"count"`
+	Timestamp        time.Time       `json:"timestamp"`
+}
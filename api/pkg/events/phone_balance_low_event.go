@@ -0,0 +1,25 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypePhoneBalanceLow is emitted when the credit balance of the SIM of a phone drops below the low balance threshold
+const EventTypePhoneBalanceLow = "phone.balance.low"
+
+// PhoneBalanceLowPayload is the payload of the EventTypePhoneBalanceLow event
+type PhoneBalanceLowPayload struct {
+	PhoneID       uuid.UUID       `json:"phone_id"`
+	UserID        entities.UserID `json:"user_id"`
+	Owner         string          `json:"owner"`
+	SIM           entities.SIM    `json:"sim"`
+	BalanceID     uuid.UUID       `json:"balance_id"`
+	Amount        float64         `json:"amount"`
+	Currency      string          `json:"currency"`
+	Threshold     float64         `json:"threshold"`
+	PlanExpiresAt *time.Time      `json:"plan_expires_at"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
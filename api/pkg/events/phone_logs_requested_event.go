@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypePhoneLogsRequested is emitted when the user requests a phone to upload the recent logs of the app
+const EventTypePhoneLogsRequested = "phone.logs.requested"
+
+// PhoneLogsRequestedPayload is the payload of the EventTypePhoneLogsRequested event
+type PhoneLogsRequestedPayload struct {
+	PhoneID   uuid.UUID       `json:"phone_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Owner     string          `json:"owner"`
+}
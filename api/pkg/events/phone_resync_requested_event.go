@@ -0,0 +1,21 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypePhoneResyncRequested is emitted when the user requests a phone to reconcile its state with the server
+const EventTypePhoneResyncRequested = "phone.resync.requested"
+
+// PhoneResyncRequestedPayload is the payload of the EventTypePhoneResyncRequested event
+type PhoneResyncRequestedPayload struct {
+	PhoneID      uuid.UUID       `json:"phone_id"`
+	UserID       entities.UserID `json:"user_id"`
+	Timestamp    time.Time       `json:"timestamp"`
+	Owner        string          `json:"owner"`
+	SIM          entities.SIM    `json:"sim"`
+	MessageCount int             `json:"message_count"`
+}
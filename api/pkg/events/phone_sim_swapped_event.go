@@ -0,0 +1,23 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypePhoneSIMSwapped is emitted when a heartbeat reports a different SIM card in the SIM slot of a phone
+const EventTypePhoneSIMSwapped = "phone.sim.swapped"
+
+// PhoneSIMSwappedPayload is the payload of the EventTypePhoneSIMSwapped event
+type PhoneSIMSwappedPayload struct {
+	PhoneID       uuid.UUID       `json:"phone_id"`
+	UserID        entities.UserID `json:"user_id"`
+	Owner         string          `json:"owner"`
+	SIM           entities.SIM    `json:"sim"`
+	Operator      *string         `json:"operator"`
+	PreviousICCID string          `json:"previous_iccid"`
+	ICCID         string          `json:"iccid"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
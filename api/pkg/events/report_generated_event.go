@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeReportGenerated is emitted when a scheduled report is generated
+const EventTypeReportGenerated = "report.generated"
+
+// ReportGeneratedPayload is the payload of the EventTypeReportGenerated event
+type ReportGeneratedPayload struct {
+	ReportID    uuid.UUID               `json:"report_id"`
+	UserID      entities.UserID         `json:"user_id"`
+	Type        entities.ReportType     `json:"type"`
+	Delivery    entities.ReportDelivery `json:"delivery"`
+	From        time.Time               `json:"from"`
+	To          time.Time               `json:"to"`
+	FileName    string                  `json:"file_name"`
+	DownloadURL string                  `json:"download_url"`
+	ExpiresAt   time.Time               `json:"expires_at"`
+	Timestamp   time.Time               `json:"timestamp"`
+}
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// EventTypeWebhookPaused is emitted when a webhook is paused after too many consecutive failures
+const EventTypeWebhookPaused = "webhook.paused"
+
+// WebhookPausedPayload is the payload of the EventTypeWebhookPaused event
+type WebhookPausedPayload struct {
+	WebhookID           uuid.UUID       `json:"webhook_id"`
+	WebhookURL          string          `json:"webhook_url"`
+	UserID              entities.UserID `json:"user_id"`
+	ConsecutiveFailures uint            `json:"consecutive_failures"`
+	PausedReason        string          `json:"paused_reason"`
+	Timestamp           time.Time       `json:"timestamp"`
+}
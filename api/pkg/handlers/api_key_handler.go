@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// APIKeyHandler handles the additional API keys of a user which only have some scopes
+type APIKeyHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.APIKeyService
+	validator *validators.APIKeyHandlerValidator
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.APIKeyService,
+	validator *validators.APIKeyHandlerValidator,
+) (h *APIKeyHandler) {
+	return &APIKeyHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the APIKeyHandler
+func (h *APIKeyHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/api-keys")
+	router.Get("/", h.computeRoute(middlewares, h.Index)...)
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Delete("/:keyID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Index returns the API keys of a user
+// @Summary      Get the API keys of a user
+// @Description  Get the additional API keys of the account with their scopes
+// @Security	 ApiKeyAuth
+// @Tags         APIKeys
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.APIKeysResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /api-keys 	[get]
+func (h *APIKeyHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	keys, err := h.service.Index(ctx, h.userIDFomContext(c))
+	if err != nil {
+		msg := fmt.Sprintf("cannot get api keys for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(keys), h.pluralize("api key", len(keys))), keys)
+}
+
+// Store an API key
+// @Summary      Create an API key
+// @Description  Create an additional API key with the "send", "read" or "admin" scopes. A key with the "send" scope can only send messages so it can be embedded in the contact form of a website without exposing your inbox.
+// @Security	 ApiKeyAuth
+// @Tags         APIKeys
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.APIKeyStore  		true 	"Payload of the API key"
+// @Success      201 		{object}	responses.APIKeyResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /api-keys 	[post]
+func (h *APIKeyHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.APIKeyStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing api key [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing api key")
+	}
+
+	key, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store api key with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "api key created successfully", key)
+}
+
+// Delete an API key
+// @Summary      Delete an API key
+// @Description  Delete an additional API key of the account. The key stops working within a minute.
+// @Security	 ApiKeyAuth
+// @Tags         APIKeys
+// @Accept       json
+// @Produce      json
+// @Param 		 keyID 		path		string 		true 	"ID of the API key"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /api-keys/{keyID} [delete]
+func (h *APIKeyHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	keyID := c.Params("keyID")
+	if errors := h.validator.ValidateUUID(ctx, keyID, "keyID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting api key with ID [%s]", spew.Sdump(errors), keyID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting api key")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(keyID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find api key with ID [%s]", keyID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete api key with ID [%s]", keyID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "api key deleted successfully")
+}
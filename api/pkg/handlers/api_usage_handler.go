@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// APIUsageHandler handles the API usage analytics of an account
+type APIUsageHandler struct {
+	handler
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.APIUsageService
+}
+
+// NewAPIUsageHandler creates a new APIUsageHandler
+func NewAPIUsageHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.APIUsageService,
+) (h *APIUsageHandler) {
+	return &APIUsageHandler{
+		logger:  logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// RegisterRoutes registers the routes for the APIUsageHandler
+func (h *APIUsageHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/usage/api", h.Show)
+}
+
+// Show returns the API usage of the account
+// @Summary      Get API usage
+// @Description  Get the number of requests, the error rate and the latency of every endpoint which was called with an API key since the counting started, and the number of messages which were consumed out of the limit of your subscription in the current billing period.
+// @Security	 ApiKeyAuth
+// @Tags         Billing
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.APIUsageResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /usage/api [get]
+func (h *APIUsageHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	usage, err := h.service.Get(ctx, h.userIDFomContext(c))
+	if err != nil {
+		msg := fmt.Sprintf("cannot get the API usage of user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched the API usage of %d %s", len(usage.Endpoints), h.pluralize("endpoint", len(usage.Endpoints))), usage)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// AttachmentHandler handles the media files of MMS messages
+type AttachmentHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.AttachmentService
+	validator *validators.AttachmentHandlerValidator
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler
+func NewAttachmentHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.AttachmentService,
+	validator *validators.AttachmentHandlerValidator,
+) (h *AttachmentHandler) {
+	return &AttachmentHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the AttachmentHandler
+func (h *AttachmentHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/attachments")
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Get("/:attachmentID", h.computeRoute(middlewares, h.Show)...)
+	router.Delete("/:attachmentID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Store an attachment
+// @Summary      Upload an attachment
+// @Description  Upload an image, video, audio, vCard or PDF file with a maximum size of 3 MB. Use the URL of the attachment in the `media_urls` of an MMS message.
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file		formData	file	true	"The file to upload"
+// @Success      201 		{object}	responses.AttachmentResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments [post]
+func (h *AttachmentHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch file with name [%s] from request", "file")
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, header); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing attachment [%s] for [%s]", spew.Sdump(errors), header.Filename, h.userIDFomContext(c))
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing attachment")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open uploaded file [%s]", header.Filename)))
+		return h.responseInternalServerError(c)
+	}
+	defer func() { _ = file.Close() }()
+
+	attachment, err := h.service.Store(ctx, &services.AttachmentStoreParams{
+		UserID:      h.userIDFomContext(c),
+		Name:        filepath.Base(header.Filename),
+		ContentType: h.validator.ContentType(header),
+		Size:        header.Size,
+		Reader:      file,
+		BaseURL:     c.BaseURL(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot store attachment [%s] for user [%s]", header.Filename, h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "attachment uploaded successfully", attachment)
+}
+
+// Show downloads an attachment
+// @Summary      Download an attachment
+// @Description  Download the content of an attachment which was uploaded by the user or received by the phone in an MMS message
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Produce      octet-stream
+// @Param 		 attachmentID 	path		string 							true 	"ID of the attachment"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{file}		file
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments/{attachmentID} [get]
+func (h *AttachmentHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachmentID := c.Params("attachmentID")
+	if errors := h.validator.ValidateUUID(ctx, attachmentID, "attachmentID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while downloading attachment with ID [%s]", spew.Sdump(errors), attachmentID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while downloading attachment")
+	}
+
+	attachment, reader, err := h.service.Download(ctx, h.userIDFomContext(c), uuid.MustParse(attachmentID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find attachment with ID [%s]", attachmentID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot download attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": attachment.Name}))
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return c.SendStream(reader, int(attachment.Size))
+}
+
+// Delete an attachment
+// @Summary      Delete an attachment
+// @Description  Delete an attachment and its content
+// @Security	 ApiKeyAuth
+// @Tags         Attachments
+// @Accept       json
+// @Produce      json
+// @Param 		 attachmentID 	path		string 							true 	"ID of the attachment"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachmentID := c.Params("attachmentID")
+	if errors := h.validator.ValidateUUID(ctx, attachmentID, "attachmentID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting attachment with ID [%s]", spew.Sdump(errors), attachmentID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting attachment")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(attachmentID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find attachment with ID [%s]", attachmentID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete attachment with ID [%s]", attachmentID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "attachment deleted successfully")
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ComplianceRuleHandler handles compliance rule requests. The rules apply to the messages of every user so they can only
+// be changed by the admins of the server.
+type ComplianceRuleHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.ComplianceService
+	validator *validators.ComplianceRuleHandlerValidator
+	admins    []entities.UserID
+}
+
+// NewComplianceRuleHandler creates a new ComplianceRuleHandler
+func NewComplianceRuleHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ComplianceService,
+	validator *validators.ComplianceRuleHandlerValidator,
+	admins []entities.UserID,
+) (h *ComplianceRuleHandler) {
+	return &ComplianceRuleHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+		admins:    admins,
+	}
+}
+
+// RegisterRoutes registers the routes for the ComplianceRuleHandler
+func (h *ComplianceRuleHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/compliance-rules")
+	router.Get("/", h.computeRoute(middlewares, h.Index)...)
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Put("/:ruleID", h.computeRoute(middlewares, h.Update)...)
+	router.Delete("/:ruleID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Index returns the compliance rules
+// @Summary      Get compliance rules
+// @Description  Get the messaging regulations of the destination countries which are enforced before a message is sent
+// @Security	 ApiKeyAuth
+// @Tags         ComplianceRules
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.ComplianceRulesResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /compliance-rules 	[get]
+func (h *ComplianceRuleHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	rules, err := h.service.Index(ctx)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot get compliance rules"))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(rules), h.pluralize("compliance rule", len(rules))), rules)
+}
+
+// Store a compliance rule
+// @Summary      Store a compliance rule
+// @Description  Store the messaging regulations of a destination country. Only the admins of the server can store compliance rules.
+// @Security	 ApiKeyAuth
+// @Tags         ComplianceRules
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ComplianceRuleStore  		true "Payload of the compliance rule request"
+// @Success      200 		{object}	responses.ComplianceRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /compliance-rules [post]
+func (h *ComplianceRuleHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAdmin(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user [%s] is not an admin and cannot store a compliance rule", h.userIDFomContext(c))))
+		return h.responseForbidden(c)
+	}
+
+	var request requests.ComplianceRuleStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing compliance rule [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing compliance rule")
+	}
+
+	rule, err := h.service.Store(ctx, request.ToStoreParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot store compliance rule with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "compliance rule created successfully", rule)
+}
+
+// Update an entities.ComplianceRule
+// @Summary      Update a compliance rule
+// @Description  Update the messaging regulations of a destination country. Only the admins of the server can update compliance rules.
+// @Security	 ApiKeyAuth
+// @Tags         ComplianceRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID		path		string 							true 	"ID of the compliance rule" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ComplianceRuleUpdate  	true 	"Payload of compliance rule details to update"
+// @Success      200 		{object}	responses.ComplianceRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /compliance-rules/{ruleID} 	[put]
+func (h *ComplianceRuleHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAdmin(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user [%s] is not an admin and cannot update a compliance rule", h.userIDFomContext(c))))
+		return h.responseForbidden(c)
+	}
+
+	var request requests.ComplianceRuleUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.RuleID = c.Params("ruleID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating compliance rule [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating compliance rule")
+	}
+
+	rule, err := h.service.Update(ctx, request.ToUpdateParams())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find compliance rule with ID [%s]", request.RuleID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update compliance rule with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "compliance rule updated successfully", rule)
+}
+
+// Delete a compliance rule
+// @Summary      Delete compliance rule
+// @Description  Delete the messaging regulations of a destination country. Only the admins of the server can delete compliance rules.
+// @Security	 ApiKeyAuth
+// @Tags         ComplianceRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID 	path		string 							true 	"ID of the compliance rule"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Forbidden
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /compliance-rules/{ruleID} [delete]
+func (h *ComplianceRuleHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAdmin(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user [%s] is not an admin and cannot delete a compliance rule", h.userIDFomContext(c))))
+		return h.responseForbidden(c)
+	}
+
+	ruleID := c.Params("ruleID")
+	if errors := h.validator.ValidateUUID(ctx, ruleID, "ruleID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting compliance rule with ID [%s]", spew.Sdump(errors), ruleID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting compliance rule")
+	}
+
+	err := h.service.Delete(ctx, uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find compliance rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete compliance rule with ID [%+#v]", ruleID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "compliance rule deleted successfully", nil)
+}
+
+// isAdmin checks if the authenticated user can change the compliance rules
+func (h *ComplianceRuleHandler) isAdmin(c *fiber.Ctx) bool {
+	return slices.Contains(h.admins, h.userIDFomContext(c))
+}
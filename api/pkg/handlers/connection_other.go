@@ -0,0 +1,10 @@
+//go:build !unix
+
+package handlers
+
+import "net"
+
+// isConnectionClosed cannot detect a closed connection on this platform so the request runs until it completes
+func isConnectionClosed(_ net.Conn) bool {
+	return false
+}
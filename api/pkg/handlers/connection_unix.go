@@ -0,0 +1,37 @@
+//go:build unix
+
+package handlers
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// isConnectionClosed peeks at the socket of a connection without consuming any bytes and returns true when the client
+// has closed the connection. fasthttp does not cancel the context of a request when the client goes away.
+func isConnectionClosed(conn net.Conn) bool {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	sysConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+
+	rawConn, err := sysConn.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	closed := false
+	buffer := make([]byte, 1)
+	err = rawConn.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buffer, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		closed = (n == 0 && err == nil) || errors.Is(err, syscall.ECONNRESET)
+		return true
+	})
+	return err == nil && closed
+}
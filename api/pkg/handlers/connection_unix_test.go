@@ -0,0 +1,37 @@
+//go:build unix
+
+package handlers
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsConnectionClosed(t *testing.T) {
+	// Setup
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	// Arrange
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	server, err := listener.Accept()
+	require.NoError(t, err)
+	defer func() { _ = server.Close() }()
+
+	// Act
+	open := isConnectionClosed(server)
+	_ = client.Close()
+	time.Sleep(50 * time.Millisecond)
+	closed := isConnectionClosed(server)
+
+	// Assert
+	assert.False(t, open)
+	assert.True(t, closed)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ContactPreferenceHandler handles the messaging preferences of the contacts of a user
+type ContactPreferenceHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.ContactPreferenceService
+	validator *validators.ContactPreferenceHandlerValidator
+}
+
+// NewContactPreferenceHandler creates a new ContactPreferenceHandler
+func NewContactPreferenceHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ContactPreferenceService,
+	validator *validators.ContactPreferenceHandlerValidator,
+) (h *ContactPreferenceHandler) {
+	return &ContactPreferenceHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the ContactPreferenceHandler
+func (h *ContactPreferenceHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/contacts/:contact/preferences", h.Show)
+	router.Put("/contacts/:contact/preferences", h.Update)
+	router.Delete("/contacts/:contact/preferences", h.Delete)
+}
+
+// Show returns the messaging preferences of a contact
+// @Summary      Get the preferences of a contact
+// @Description  Get the language, do-not-contact categories and send window which are applied to the messages sent to a contact
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        contact	path   		string  	true 	"the contact's phone number" 		default(+18005550100)
+// @Success      200 		{object}	responses.ContactPreferenceResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contact}/preferences [get]
+func (h *ContactPreferenceHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contact, err := url.PathUnescape(c.Params("contact"))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unescape contact [%s]", c.Params("contact"))))
+		return h.responseBadRequest(c, err)
+	}
+
+	request := requests.ContactPreference{Contact: contact}
+	if errors := h.validator.ValidateShow(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contact preference [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact preference")
+	}
+
+	preference, err := h.service.Load(ctx, h.userIDFomContext(c), request.Contact)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find preferences for contact [%s]", request.Contact))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact preference with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact preference fetched successfully", preference)
+}
+
+// Update the messaging preferences of a contact
+// @Summary      Update the preferences of a contact
+// @Description  Set the language which the messages sent to a contact are translated into, the message categories which cannot be sent to the contact and the hours of the day in the timezone of the contact when messages are sent. Messages which are sent outside the send window are scheduled for the start of the next window.
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        contact	path   		string  							true 	"the contact's phone number" 		default(+18005550100)
+// @Param        payload   	body 		requests.ContactPreferenceUpdate  	true 	"Payload of the preferences"
+// @Success      200 		{object}	responses.ContactPreferenceResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contact}/preferences [put]
+func (h *ContactPreferenceHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactPreferenceUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	contact, err := url.PathUnescape(c.Params("contact"))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unescape contact [%s]", c.Params("contact"))))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.Contact = contact
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating contact preference [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact preference")
+	}
+
+	preference, err := h.service.Update(ctx, request.ToUpdateParams(h.userIDFomContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot update contact preference with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact preference updated successfully", preference)
+}
+
+// Delete the messaging preferences of a contact
+// @Summary      Delete the preferences of a contact
+// @Description  Delete the preferences of a contact so that the messages sent to the contact are no longer translated, blocked or delayed
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        contact	path   		string  	true 	"the contact's phone number" 		default(+18005550100)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contact}/preferences [delete]
+func (h *ContactPreferenceHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contact, err := url.PathUnescape(c.Params("contact"))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unescape contact [%s]", c.Params("contact"))))
+		return h.responseBadRequest(c, err)
+	}
+
+	request := requests.ContactPreference{Contact: contact}
+	if errors := h.validator.ValidateShow(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting contact preference [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting contact preference")
+	}
+
+	err = h.service.Delete(ctx, h.userIDFomContext(c), request.Contact)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find preferences for contact [%s]", request.Contact))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact preference with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "contact preference deleted successfully")
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// CustomDomainHandler handles the domain which a user can use for the share links of the account
+type CustomDomainHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.CustomDomainService
+	validator *validators.CustomDomainHandlerValidator
+}
+
+// NewCustomDomainHandler creates a new CustomDomainHandler
+func NewCustomDomainHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.CustomDomainService,
+	validator *validators.CustomDomainHandlerValidator,
+) (h *CustomDomainHandler) {
+	return &CustomDomainHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the CustomDomainHandler
+func (h *CustomDomainHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/custom-domain", h.Show)
+	router.Put("/custom-domain", h.Store)
+	router.Post("/custom-domain/verify", h.Verify)
+	router.Delete("/custom-domain", h.Delete)
+}
+
+// Show returns the custom domain of a user
+// @Summary      Get the custom domain
+// @Description  Get the domain which is used for the share links of the account with the TXT record which verifies it
+// @Security	 ApiKeyAuth
+// @Tags         CustomDomains
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.CustomDomainResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /custom-domain [get]
+func (h *CustomDomainHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	domain, err := h.service.Load(ctx, h.userIDFomContext(c))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, "cannot find a custom domain for your account")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "custom domain fetched successfully", domain)
+}
+
+// Store sets the custom domain of a user
+// @Summary      Set the custom domain
+// @Description  Set the domain which is used for the share links of the account. Create a TXT record on the verification_record with the verification_token and a CNAME record which points the domain to the API. The domain is used after the TXT record has been found by the background job or the verify endpoint.
+// @Security	 ApiKeyAuth
+// @Tags         CustomDomains
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.CustomDomainStore  	true 	"Payload of the custom domain"
+// @Success      200 		{object}	responses.CustomDomainResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /custom-domain [put]
+func (h *CustomDomainHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.CustomDomainStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize(), h.userIDFomContext(c)); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing custom domain [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing custom domain")
+	}
+
+	domain, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store custom domain with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "custom domain stored successfully", domain)
+}
+
+// Verify checks the TXT record of the custom domain of a user
+// @Summary      Verify the custom domain
+// @Description  Check the TXT record of the custom domain now instead of waiting for the background job which checks the unverified domains every 15 minutes
+// @Security	 ApiKeyAuth
+// @Tags         CustomDomains
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.CustomDomainResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /custom-domain/verify [post]
+func (h *CustomDomainHandler) Verify(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	domain, err := h.service.Verify(ctx, h.userIDFomContext(c))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, "cannot find a custom domain for your account")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot verify custom domain for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if !domain.IsVerified() {
+		return h.responseOK(c, fmt.Sprintf("cannot find the TXT record [%s] of domain [%s]", domain.VerificationRecord, domain.Domain), domain)
+	}
+
+	return h.responseOK(c, "custom domain verified successfully", domain)
+}
+
+// Delete the custom domain of a user
+// @Summary      Delete the custom domain
+// @Description  Delete the custom domain of the account. New share links are created on the domain of the API.
+// @Security	 ApiKeyAuth
+// @Tags         CustomDomains
+// @Accept       json
+// @Produce      json
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /custom-domain [delete]
+func (h *CustomDomainHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, "cannot find a custom domain for your account")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete custom domain for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "custom domain deleted successfully")
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// CustomEventHandler handles custom event requests
+type CustomEventHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.CustomEventService
+	validator *validators.CustomEventHandlerValidator
+}
+
+// NewCustomEventHandler creates a new CustomEventHandler
+func NewCustomEventHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.CustomEventService,
+	validator *validators.CustomEventHandlerValidator,
+) (h *CustomEventHandler) {
+	return &CustomEventHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the CustomEventHandler
+func (h *CustomEventHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler, middlewares ...fiber.Handler) {
+	router := app.Group("v1/events")
+	router.Get("/", h.computeRoute(append(middlewares, authMiddleware), h.Index)...)
+	router.Post("/", h.computeRoute(append(middlewares, authMiddleware), h.Publish)...)
+}
+
+// Index returns the custom events of a user
+// @Summary      Get custom events of a user
+// @Description  Get the custom events which were published by the user ordered by the time they were published
+// @Security	 ApiKeyAuth
+// @Tags         Events
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of events to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter events with a type or source containing query"
+// @Param        limit		query  int  	false	"number of events to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.CustomEventsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /events 	[get]
+func (h *CustomEventHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.CustomEventIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching custom events [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching custom events")
+	}
+
+	customEvents, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get custom events with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(customEvents), h.pluralize("event", len(customEvents))), customEvents)
+}
+
+// Publish a custom event
+// @Summary      Publish a custom event
+// @Description  Publish a custom event which is stored and sent to the webhooks of the user which are subscribed to its type. The type is namespaced with the "custom." prefix.
+// @Security	 ApiKeyAuth
+// @Tags         Events
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.CustomEventPublish  		true "Payload of the custom event"
+// @Success      201 		{object}	responses.CustomEventResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /events [post]
+func (h *CustomEventHandler) Publish(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.CustomEventPublish
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidatePublish(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while publishing custom event [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while publishing custom event")
+	}
+
+	customEvent, err := h.service.Publish(ctx, request.ToPublishParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot publish custom event with type [%s]", request.Type)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "custom event published successfully", customEvent)
+}
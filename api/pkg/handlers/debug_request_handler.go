@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// DebugRequestHandler handles debug mode requests
+type DebugRequestHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.DebugRequestService
+	validator *validators.DebugRequestHandlerValidator
+}
+
+// NewDebugRequestHandler creates a new DebugRequestHandler
+func NewDebugRequestHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.DebugRequestService,
+	validator *validators.DebugRequestHandlerValidator,
+) (h *DebugRequestHandler) {
+	return &DebugRequestHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the DebugRequestHandler
+func (h *DebugRequestHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/debug")
+	router.Post("/enable", h.computeRoute(middlewares, h.Enable)...)
+	router.Post("/disable", h.computeRoute(middlewares, h.Disable)...)
+	router.Get("/requests", h.computeRoute(middlewares, h.Index)...)
+	router.Delete("/requests", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Enable the debug mode of a user
+// @Summary      Enable the debug mode
+// @Description  Record the requests which are made with your API key and the responses for a limited time so you can troubleshoot your integration. The credentials in the requests and responses are redacted.
+// @Security	 ApiKeyAuth
+// @Tags         Debug
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.DebugModeEnable  	true "Payload of the debug mode request"
+// @Success      200 		{object}	responses.DebugModeResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /debug/enable [post]
+func (h *DebugRequestHandler) Enable(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.DebugModeEnable
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateEnable(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while enabling debug mode [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while enabling debug mode")
+	}
+
+	mode, err := h.service.Enable(ctx, h.userIDFomContext(c), request.Duration())
+	if err != nil {
+		msg := fmt.Sprintf("cannot enable debug mode for user [%s] with params [%+#v]", h.userIDFomContext(c), request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "debug mode enabled successfully", mode)
+}
+
+// Disable the debug mode of a user
+// @Summary      Disable the debug mode
+// @Description  Stop recording the requests which are made with your API key before the debug mode expires
+// @Security	 ApiKeyAuth
+// @Tags         Debug
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.DebugModeResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /debug/disable [post]
+func (h *DebugRequestHandler) Disable(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	mode, err := h.service.Disable(ctx, h.userIDFomContext(c))
+	if err != nil {
+		msg := fmt.Sprintf("cannot disable debug mode for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "debug mode disabled successfully", mode)
+}
+
+// Index returns the debug requests of a user
+// @Summary      Get the recorded requests of a user
+// @Description  Get the requests and responses which were recorded while the debug mode was enabled ordered from the newest to the oldest
+// @Security	 ApiKeyAuth
+// @Tags         Debug
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of requests to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter requests with a URL or method containing query"
+// @Param        limit		query  int  	false	"number of requests to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.DebugRequestsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /debug/requests 	[get]
+func (h *DebugRequestHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.DebugRequestIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching debug requests [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching debug requests")
+	}
+
+	debugRequests, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get debug requests with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d debug %s", len(debugRequests), h.pluralize("request", len(debugRequests))), debugRequests)
+}
+
+// Delete the debug requests of a user
+// @Summary      Delete the recorded requests of a user
+// @Description  Delete all the requests and responses which were recorded while the debug mode was enabled
+// @Security	 ApiKeyAuth
+// @Tags         Debug
+// @Accept       json
+// @Produce      json
+// @Success      204		{object}    responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /debug/requests [delete]
+func (h *DebugRequestHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if err := h.service.DeleteAllForUser(ctx, h.userIDFomContext(c)); err != nil {
+		msg := fmt.Sprintf("cannot delete debug requests for user [%s]", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "debug requests deleted successfully")
+}
@@ -348,7 +348,7 @@ func (h *DiscordHandler) sendSMS(ctx context.Context, c *fiber.Ctx, payload map[
 		var embeds []fiber.Map
 		for _, value := range errors {
 			embeds = append(embeds, fiber.Map{
-				"title": value[0],
+				"title": value.Message,
 				"color": 14681092,
 			})
 		}
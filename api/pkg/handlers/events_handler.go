@@ -5,6 +5,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/davecgh/go-spew/spew"
@@ -39,6 +40,7 @@ func NewEventsHandler(
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *EventsHandler) RegisterRoutes(router fiber.Router) {
 	router.Post("/events", h.Dispatch)
+	router.Get("/events/subscriptions", h.Subscriptions)
 }
 
 // Dispatch a cloud event
@@ -59,7 +61,7 @@ func (h *EventsHandler) Dispatch(c *fiber.Ctx) error {
 	if err := request.Validate(); err != nil {
 		msg := fmt.Sprintf("validation errors [%s], while dispatching event [%+#v]", spew.Sdump(err.Error()), request)
 		ctxLogger.Warn(stacktrace.NewError(msg))
-		return h.responseUnprocessableEntity(c, map[string][]string{"event": {err.Error()}}, "validation errors while dispatching event")
+		return h.responseUnprocessableEntity(c, responses.ValidationErrors{{Field: "event", Rule: "cloudevent", Message: err.Error()}}, "validation errors while dispatching event")
 	}
 
 	if h.userIDFomContext(c) != h.queueConfig.UserID {
@@ -78,3 +80,21 @@ func (h *EventsHandler) Dispatch(c *fiber.Ctx) error {
 
 	return h.responseNoContent(c, "event dispatched successfully")
 }
+
+// Subscriptions returns the listeners which are subscribed to each event type
+// This is an internal API so no documentation provided
+func (h *EventsHandler) Subscriptions(c *fiber.Ctx) error {
+	_, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	if h.userIDFomContext(c) != h.queueConfig.UserID {
+		msg := fmt.Sprintf("user with ID [%s], cannot fetch the event subscriptions", h.userIDFomContext(c))
+		ctxLogger.Error(stacktrace.NewError(msg))
+		return h.responseForbidden(c)
+	}
+
+	subscriptions := h.service.Subscriptions()
+	return h.responseOK(c, fmt.Sprintf("fetched subscriptions for %d event %s", len(subscriptions), h.pluralize("type", len(subscriptions))), subscriptions)
+}
@@ -1,10 +1,11 @@
 package handlers
 
 import (
-	"net/url"
-
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/i18n"
 	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,7 +16,7 @@ type handler struct{}
 func (h *handler) responseBadRequest(c *fiber.Ctx, err error) error {
 	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 		"status":  "error",
-		"message": "The request isn't properly formed",
+		"message": i18n.Translate(h.language(c), "The request isn't properly formed"),
 		"data":    err,
 	})
 }
@@ -23,30 +24,35 @@ func (h *handler) responseBadRequest(c *fiber.Ctx, err error) error {
 func (h *handler) responseInternalServerError(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 		"status":  "error",
-		"message": "We ran into an internal error while handling the request.",
+		"message": i18n.Translate(h.language(c), "We ran into an internal error while handling the request."),
 	})
 }
 
 func (h *handler) responseUnauthorized(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 		"status":  "error",
-		"message": "You are not authorized to carry out this request.",
-		"data":    "Make sure your API key is set in the [X-API-Key] header in the request",
+		"message": i18n.Translate(h.language(c), "You are not authorized to carry out this request."),
+		"data":    i18n.Translate(h.language(c), "Make sure your API key is set in the [X-API-Key] header in the request"),
 	})
 }
 
 func (h *handler) responseForbidden(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 		"status":  "error",
-		"message": fiber.ErrForbidden.Message,
+		"message": i18n.Translate(h.language(c), fiber.ErrForbidden.Message),
 	})
 }
 
-func (h *handler) responseUnprocessableEntity(c *fiber.Ctx, errors url.Values, message string) error {
+func (h *handler) responseUnprocessableEntity(c *fiber.Ctx, errors responses.ValidationErrors, message string) error {
+	language := h.language(c)
+	if language != i18n.English {
+		message = i18n.Translate(language, "The request contains validation errors")
+	}
+
 	return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 		"status":  "error",
 		"message": message,
-		"data":    errors,
+		"data":    i18n.TranslateValidationErrors(language, errors),
 	})
 }
 
@@ -86,6 +92,19 @@ func (h *handler) responseOK(c *fiber.Ctx, message string, data interface{}) err
 	})
 }
 
+// responseOKWithCursor adds the next_cursor to the response when there is a next page
+func (h *handler) responseOKWithCursor(c *fiber.Ctx, message string, data interface{}, cursor *repositories.Cursor) error {
+	response := fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    data,
+	}
+	if cursor != nil {
+		response["next_cursor"] = cursor.Encode()
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 func (h *handler) responseCreated(c *fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
@@ -94,6 +113,14 @@ func (h *handler) responseCreated(c *fiber.Ctx, message string, data interface{}
 	})
 }
 
+// language returns the i18n.Language of the error messages from the Accept-Language header of the request
+func (h *handler) language(c *fiber.Ctx) i18n.Language {
+	language := i18n.FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	c.Set(fiber.HeaderContentLanguage, language.String())
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return language
+}
+
 func (h *handler) pluralize(value string, count int) string {
 	if count == 1 {
 		return value
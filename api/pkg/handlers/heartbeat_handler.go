@@ -6,6 +6,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -42,6 +43,9 @@ func NewHeartbeatHandler(
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *HeartbeatHandler) RegisterRoutes(router fiber.Router) {
 	router.Get("/heartbeats", h.Index)
+	router.Get("/heartbeats/signal", h.Signal)
+	router.Get("/heartbeats/report", h.Report)
+	router.Get("/heartbeats/rollups", h.Rollups)
 	router.Post("/heartbeats", h.Store)
 }
 
@@ -91,6 +95,141 @@ func (h *HeartbeatHandler) Index(c *fiber.Ctx) error {
 	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*heartbeats), h.pluralize("heartbeat", len(*heartbeats))), heartbeats)
 }
 
+// Signal returns the signal of the heartbeats of a phone number in time buckets
+// @Summary      Get the signal strength and network type of a phone
+// @Description  Get the average signal strength, signal bars and most common network type which were reported in the heartbeats of a phone in time buckets so you can correlate failed messages with poor coverage.
+// @Security	 ApiKeyAuth
+// @Tags         Heartbeats
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	true 	"the owner's phone number" 							default(+18005550199)
+// @Param        from		query  string  	false 	"start of the time range in RFC3339 format"		default(2022-06-05T00:00:00Z)
+// @Param        to			query  string  	false 	"end of the time range in RFC3339 format"		default(2022-06-06T00:00:00Z)
+// @Param        interval	query  int  	false	"duration of a time bucket in seconds"			minimum(60)	maximum(86400)
+// @Success      200 		{object}	responses.HeartbeatSignalResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /heartbeats/signal [get]
+func (h *HeartbeatHandler) Signal(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.HeartbeatSignal
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateSignal(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching heartbeat signal [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching heartbeat signal")
+	}
+
+	buckets, err := h.service.Signal(ctx, h.userIDFomContext(c), request.ToTimeseriesParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get heartbeat signal with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d heartbeat signal %s", len(buckets), h.pluralize("bucket", len(buckets))), buckets)
+}
+
+// Rollups returns the hourly or daily aggregates of the heartbeats of a phone number
+// @Summary      Get the heartbeat rollups of a phone
+// @Description  Get the number of heartbeats of a phone in hourly or daily time buckets. The raw heartbeats are aggregated into rollups once they are older than the heartbeat retention period. It will be sorted by timestamp in descending order.
+// @Security	 ApiKeyAuth
+// @Tags         Heartbeats
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	true 	"the owner's phone number" 					default(+18005550199)
+// @Param        resolution	query  string  	false 	"the size of a time bucket e.g. hour or day"	default(hour)
+// @Param        skip		query  int  	false	"number of rollups to skip"					minimum(0)
+// @Param        limit		query  int  	false	"number of rollups to return"				minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.HeartbeatRollupsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /heartbeats/rollups [get]
+func (h *HeartbeatHandler) Rollups(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.HeartbeatRollupIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateRollups(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching heartbeat rollups [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching heartbeat rollups")
+	}
+
+	rollups, err := h.service.Rollups(ctx, h.userIDFomContext(c), request.Owner, request.ResolutionValue(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get heartbeat rollups with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d heartbeat %s", len(rollups), h.pluralize("rollup", len(rollups))), rollups)
+}
+
+// Report returns the uptime of a phone number which is computed from its heartbeats
+// @Summary      Get the uptime report of a phone
+// @Description  Get the uptime percentage and the outages of a phone in the last day, week or month. An outage is a period in which the phone did not send a heartbeat for longer than its heartbeat timeout.
+// @Security	 ApiKeyAuth
+// @Tags         Heartbeats
+// @Accept       json
+// @Produce      json
+// @Param        phone		query  string  	true 	"the phone number"								default(+18005550199)
+// @Param        period		query  string  	false 	"the period ending now e.g. day, week or month"	default(day)
+// @Success      200 		{object}	responses.HeartbeatReportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /heartbeats/report [get]
+func (h *HeartbeatHandler) Report(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.HeartbeatReport
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateReport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching heartbeat report [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching heartbeat report")
+	}
+
+	report, err := h.service.Report(ctx, request.ToReportParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with number [%s]", request.Phone))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot get heartbeat report with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched heartbeat report with %d %s", len(report.Outages), h.pluralize("outage", len(report.Outages))), report)
+}
+
 // Store the heartbeat of a phone number
 // @Summary      Register heartbeat of an owner phone number
 // @Description  Store the heartbeat to make notify that a phone number is still active
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// heartbeatPacketMaxSize is the maximum size of a heartbeat packet, larger packets are truncated and rejected
+	heartbeatPacketMaxSize = 1024
+
+	// heartbeatPacketMaxInFlight limits the number of packets which are processed concurrently
+	heartbeatPacketMaxInFlight = 64
+
+	// heartbeatPacketMaxAge is the maximum difference between the timestamp of a packet and the time when it is received
+	heartbeatPacketMaxAge = 5 * time.Minute
+)
+
+// HeartbeatPacketHandler receives heartbeats as UDP packets or MQTT messages for devices where an HTTPS request per
+// heartbeat is too heavy
+type HeartbeatPacketHandler struct {
+	logger           telemetry.Logger
+	tracer           telemetry.Tracer
+	validator        *validators.HeartbeatHandlerValidator
+	userRepository   repositories.UserRepository
+	apiKeyRepository repositories.APIKeyRepository
+	service          *services.HeartbeatService
+}
+
+// NewHeartbeatPacketHandler creates a new HeartbeatPacketHandler
+func NewHeartbeatPacketHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.HeartbeatHandlerValidator,
+	userRepository repositories.UserRepository,
+	apiKeyRepository repositories.APIKeyRepository,
+	service *services.HeartbeatService,
+) (h *HeartbeatPacketHandler) {
+	return &HeartbeatPacketHandler{
+		logger:           logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:           tracer,
+		validator:        validator,
+		userRepository:   userRepository,
+		apiKeyRepository: apiKeyRepository,
+		service:          service,
+	}
+}
+
+// ListenAndServe receives heartbeat packets on the UDP address until the context is cancelled
+func (h *HeartbeatPacketHandler) ListenAndServe(ctx context.Context, address string) error {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot listen for heartbeat packets on [%s]", address))
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	h.logger.Info(fmt.Sprintf("listening for heartbeat packets on [udp://%s]", conn.LocalAddr()))
+
+	inFlight := make(chan struct{}, heartbeatPacketMaxInFlight)
+	for {
+		buffer := make([]byte, heartbeatPacketMaxSize+1)
+		size, sender, err := conn.ReadFrom(buffer)
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			h.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot read heartbeat packet on [%s]", conn.LocalAddr())))
+			continue
+		}
+
+		inFlight <- struct{}{}
+		go func(packet []byte, source string) {
+			defer func() { <-inFlight }()
+			h.Handle(ctx, source, packet)
+		}(buffer[:size], fmt.Sprintf("udp://%s", sender))
+	}
+}
+
+// Handle stores the heartbeat of a signed packet which was received from the source e.g. udp://192.0.2.1:8125
+func (h *HeartbeatPacketHandler) Handle(ctx context.Context, source string, packet []byte) {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	if len(packet) > heartbeatPacketMaxSize {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("heartbeat packet from [%s] is larger than [%d] bytes", source, heartbeatPacketMaxSize)))
+		return
+	}
+
+	index := bytes.IndexByte(packet, '\n')
+	if index < 0 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("heartbeat packet from [%s] does not start with a signature", source)))
+		return
+	}
+
+	signature, err := hex.DecodeString(string(bytes.TrimSpace(packet[:index])))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot decode the signature of heartbeat packet from [%s]", source)))
+		return
+	}
+
+	var request requests.HeartbeatPacket
+	if err = json.Unmarshal(packet[index+1:], &request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal heartbeat packet into [%T]", request)))
+		return
+	}
+
+	request = request.Sanitize()
+	if request.UserID == "" {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("heartbeat packet from [%s] does not contain a [user_id]", source)))
+		return
+	}
+
+	if age := time.Since(time.Unix(request.Timestamp, 0)).Abs(); age > heartbeatPacketMaxAge {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("timestamp [%d] of heartbeat packet for user [%s] is more than [%s] from the current time", request.Timestamp, request.UserID, heartbeatPacketMaxAge)))
+		return
+	}
+
+	authUser, apiKey, err := h.loadSigner(ctx, request)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load the API key of heartbeat packet for user [%s]", request.UserID)))
+		return
+	}
+
+	// the payload is signed with the API key so that the key is never sent over the network in plain text
+	mac := hmac.New(sha256.New, []byte(apiKey))
+	mac.Write(packet[index+1:])
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("invalid signature of heartbeat packet for user [%s] from [%s]", authUser.ID, source)))
+		return
+	}
+
+	if !authUser.HasScope(entities.APIKeyScopeSend) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("api key [%s] of user [%s] does not have the [%s] scope to store heartbeat packets", request.APIKeyID, authUser.ID, entities.APIKeyScopeSend)))
+		return
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.HeartbeatStore); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("validation errors [%s], while storing heartbeat packet for user [%s]", spew.Sdump(errors), authUser.ID)))
+		return
+	}
+
+	for _, params := range request.ToStoreParams(authUser, source, request.Version) {
+		if _, err = h.service.Store(ctx, params); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store heartbeat packet for owner [%s] and user [%s]", params.Owner, authUser.ID)))
+		}
+	}
+}
+
+// loadSigner fetches the entities.AuthUser with the scopes of the API key which signed the packet and the secret of the key
+func (h *HeartbeatPacketHandler) loadSigner(ctx context.Context, request requests.HeartbeatPacket) (entities.AuthUser, string, error) {
+	user, err := h.userRepository.Load(ctx, entities.UserID(request.UserID))
+	if err != nil {
+		return entities.AuthUser{}, "", stacktrace.Propagate(err, fmt.Sprintf("cannot load user [%s]", request.UserID))
+	}
+
+	authUser := entities.AuthUser{ID: user.ID, Email: user.Email}
+	if request.APIKeyID == "" {
+		return authUser, user.APIKey, nil
+	}
+
+	keyID, err := uuid.Parse(request.APIKeyID)
+	if err != nil {
+		return entities.AuthUser{}, "", stacktrace.Propagate(err, fmt.Sprintf("cannot parse api key ID [%s]", request.APIKeyID))
+	}
+
+	key, err := h.apiKeyRepository.Load(ctx, user.ID, keyID)
+	if err != nil {
+		return entities.AuthUser{}, "", stacktrace.Propagate(err, fmt.Sprintf("cannot load api key [%s]", keyID))
+	}
+
+	authUser.Scopes = key.APIKeyScopes()
+	return authUser, key.Key, nil
+}
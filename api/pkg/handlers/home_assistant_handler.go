@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// HomeAssistantHandler handles requests from the REST integrations of Home Assistant
+type HomeAssistantHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.HomeAssistantHandlerValidator
+	service   *services.HomeAssistantService
+
+	// sendService and messageValidator send the notifications so they are validated, billed and get the opt-out footer exactly like POST /v1/messages/send
+	sendService      *services.MessageSendService
+	messageValidator *validators.MessageHandlerValidator
+}
+
+// NewHomeAssistantHandler creates a new HomeAssistantHandler
+func NewHomeAssistantHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.HomeAssistantHandlerValidator,
+	service *services.HomeAssistantService,
+	sendService *services.MessageSendService,
+	messageValidator *validators.MessageHandlerValidator,
+) (h *HomeAssistantHandler) {
+	return &HomeAssistantHandler{
+		logger:           logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:           tracer,
+		validator:        validator,
+		service:          service,
+		sendService:      sendService,
+		messageValidator: messageValidator,
+	}
+}
+
+// RegisterRoutes registers the routes for the HomeAssistantHandler
+func (h *HomeAssistantHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/home-assistant", h.Discovery)
+	router.Post("/home-assistant/notify", h.Notify)
+	router.Get("/home-assistant/sensor", h.Sensor)
+}
+
+// Discovery returns the Home Assistant configuration for the phones of a user
+// @Summary      Get the Home Assistant configuration
+// @Description  Get the notify and sensor URLs and a configuration.yaml snippet for Home Assistant with a notify service and a delivery status sensor for every phone.
+// @Security	 ApiKeyAuth
+// @Tags         HomeAssistant
+// @Produce      json
+// @Success      200 		{object}	responses.HomeAssistantDiscoveryResponse
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /home-assistant [get]
+func (h *HomeAssistantHandler) Discovery(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	discovery, err := h.service.Discovery(ctx, h.userIDFomContext(c), c.BaseURL())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot generate home assistant configuration for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "fetched home assistant configuration successfully", discovery)
+}
+
+// Notify sends a message from the REST notify platform of Home Assistant
+// @Summary      Send a notification from Home Assistant
+// @Description  Send an SMS message to every target using the payload of the REST notify platform of Home Assistant. The message is sent from your most recently added phone when "from" is not set.
+// @Security	 ApiKeyAuth
+// @Tags         HomeAssistant
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.HomeAssistantNotify  	true 	"Payload of the notification"
+// @Success      200 		{object}	responses.MessagesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /home-assistant/notify [post]
+func (h *HomeAssistantHandler) Notify(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.HomeAssistantNotify
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateNotify(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while sending home assistant notification [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sending home assistant notification")
+	}
+
+	// the default phone of the API key is chosen by the send service before the most recently added phone
+	if request.From == "" && h.userFromContext(c).DefaultPhoneID == nil {
+		owner, err := h.service.DefaultOwner(ctx, h.userIDFomContext(c))
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			return h.responseUnprocessableEntity(c, responses.ValidationErrors{{Field: "from", Rule: "exists", Message: "you don't have any phone, install the httpSMS app on your phone first"}}, "validation errors while sending home assistant notification")
+		}
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch default phone for user [%s]", h.userIDFomContext(c))))
+			return h.responseInternalServerError(c)
+		}
+		request.From = owner
+	}
+
+	sends := request.ToMessageSends()
+	params := make([]services.MessageSendParams, 0, len(sends))
+	for index := range sends {
+		sends[index].Sanitize()
+		if _, _, err := h.sendService.Prepare(ctx, sends[index].ToMessageDraft(h.userFromContext(c))); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot prepare home assistant notification [%+#v]", request)))
+			return h.responseInternalServerError(c)
+		}
+
+		if errors := h.messageValidator.ValidateMessageSend(ctx, h.userIDFomContext(c), sends[index]); len(errors) != 0 {
+			msg := fmt.Sprintf("validation errors [%s], while sending home assistant notification [%+#v]", spew.Sdump(errors), sends[index])
+			ctxLogger.Warn(stacktrace.NewError(msg))
+			return h.responseUnprocessableEntity(c, errors, "validation errors while sending home assistant notification")
+		}
+		params = append(params, sends[index].ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	}
+
+	messages, err := h.sendService.SendBulk(ctx, h.userIDFomContext(c), params)
+	if stacktrace.GetCode(err) == services.ErrCodeNotEntitled {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user with ID [%s] can't send [%d] messages", h.userIDFomContext(c), len(sends))))
+		return h.responsePaymentRequired(c, stacktrace.RootCause(err).Error())
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot send home assistant notification [%+#v]", request)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("%d %s added to queue", len(messages), h.pluralize("message", len(messages))), messages)
+}
+
+// Sensor returns the delivery status of the last message sent by a phone
+// @Summary      Get the delivery status sensor of a phone
+// @Description  Get the status of the last message sent by a phone for the REST sensor platform of Home Assistant. The state is "unknown" when the phone has not sent any message.
+// @Security	 ApiKeyAuth
+// @Tags         HomeAssistant
+// @Produce      json
+// @Param        owner		query  		string  						true 	"the owner's phone number" 		default(+18005550199)
+// @Success      200 		{object}	responses.HomeAssistantSensorResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /home-assistant/sensor [get]
+func (h *HomeAssistantHandler) Sensor(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.HomeAssistantSensor
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateSensor(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching home assistant sensor [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching home assistant sensor")
+	}
+
+	sensor, err := h.service.Sensor(ctx, request.ToSearchParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch home assistant sensor with params [%+#v]", request)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "fetched home assistant sensor successfully", sensor)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// InboxRuleHandler handles inbox rule requests
+type InboxRuleHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.InboxRuleService
+	validator *validators.InboxRuleHandlerValidator
+}
+
+// NewInboxRuleHandler creates a new InboxRuleHandler
+func NewInboxRuleHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.InboxRuleService,
+	validator *validators.InboxRuleHandlerValidator,
+) (h *InboxRuleHandler) {
+	return &InboxRuleHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the InboxRuleHandler
+func (h *InboxRuleHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/inbox-rules")
+	router.Get("/", h.computeRoute(middlewares, h.Index)...)
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Put("/:ruleID", h.computeRoute(middlewares, h.Update)...)
+	router.Delete("/:ruleID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Index returns the inbox rules of a user
+// @Summary      Get inbox rules of a user
+// @Description  Get the rules which label, archive or mark as read the message threads of received messages
+// @Security	 ApiKeyAuth
+// @Tags         InboxRules
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of inbox rules to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter inbox rules containing query"
+// @Param        limit		query  int  	false	"number of inbox rules to return"	minimum(1)	maximum(20)
+// @Success      200 		{object}	responses.InboxRulesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /inbox-rules 	[get]
+func (h *InboxRuleHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.InboxRuleIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching inbox rules [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching inbox rules")
+	}
+
+	rules, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get inbox rules with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(rules), h.pluralize("inbox rule", len(rules))), rules)
+}
+
+// Delete an inbox rule
+// @Summary      Delete inbox rule
+// @Description  Delete an inbox rule for a user
+// @Security	 ApiKeyAuth
+// @Tags         InboxRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID 	path		string 							true 	"ID of the inbox rule"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /inbox-rules/{ruleID} [delete]
+func (h *InboxRuleHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	ruleID := c.Params("ruleID")
+	if errors := h.validator.ValidateUUID(ctx, ruleID, "ruleID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting inbox rule with ID [%s]", spew.Sdump(errors), ruleID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting inbox rule")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find inbox rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete inbox rule with ID [%+#v]", ruleID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "inbox rule deleted successfully", nil)
+}
+
+// Store an inbox rule
+// @Summary      Store an inbox rule
+// @Description  Store a rule which labels, archives or marks as read the message threads of received messages which match the sender or content
+// @Security	 ApiKeyAuth
+// @Tags         InboxRules
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.InboxRuleStore  		true "Payload of the inbox rule request"
+// @Success      200 		{object}	responses.InboxRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /inbox-rules [post]
+func (h *InboxRuleHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.InboxRuleStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing inbox rule [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing inbox rule")
+	}
+
+	rules, err := h.service.Index(ctx, h.userIDFomContext(c), repositories.IndexParams{Skip: 0, Limit: 20})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot index inbox rules for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	if len(rules) == 20 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] wants to create more than 20 inbox rules", h.userIDFomContext(c))))
+		return h.responsePaymentRequired(c, "You can't create more than 20 inbox rules contact us to upgrade to our enterprise plan.")
+	}
+
+	rule, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store inbox rule with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "inbox rule created successfully", rule)
+}
+
+// Update an entities.InboxRule
+// @Summary      Update an inbox rule
+// @Description  Update an inbox rule for the currently authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         InboxRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID	path		string 							true 	"ID of the inbox rule" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.InboxRuleUpdate  	true 	"Payload of inbox rule details to update"
+// @Success      200 		{object}	responses.InboxRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /inbox-rules/{ruleID} 	[put]
+func (h *InboxRuleHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.InboxRuleUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.RuleID = c.Params("ruleID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating inbox rule [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating inbox rule")
+	}
+
+	rule, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find inbox rule with ID [%s]", request.RuleID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update inbox rule with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "inbox rule updated successfully", rule)
+}
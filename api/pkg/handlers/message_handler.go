@@ -2,11 +2,12 @@ package handlers
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/google/uuid"
@@ -20,12 +21,21 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
+const (
+	// messageBodyLimit is the maximum size in bytes of the body of a request for a single message
+	messageBodyLimit = 16 * 1024
+
+	// messageBulkSendBodyLimit is the maximum size in bytes of the body of a bulk send request which can have 1000 recipients
+	messageBulkSendBodyLimit = 64 * 1024
+)
+
 // MessageHandler handles message http requests.
 type MessageHandler struct {
 	handler
 	logger         telemetry.Logger
 	tracer         telemetry.Tracer
 	billingService *services.BillingService
+	sendService    *services.MessageSendService
 	validator      *validators.MessageHandlerValidator
 	service        *services.MessageService
 }
@@ -36,6 +46,7 @@ func NewMessageHandler(
 	tracer telemetry.Tracer,
 	validator *validators.MessageHandlerValidator,
 	billingService *services.BillingService,
+	sendService *services.MessageSendService,
 	service *services.MessageService,
 ) (h *MessageHandler) {
 	return &MessageHandler{
@@ -43,21 +54,29 @@ func NewMessageHandler(
 		tracer:         tracer,
 		validator:      validator,
 		billingService: billingService,
+		sendService:    sendService,
 		service:        service,
 	}
 }
 
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *MessageHandler) RegisterRoutes(router fiber.Router) {
-	router.Post("/messages/send", h.PostSend)
-	router.Post("/messages/bulk-send", h.BulkSend)
-	router.Post("/messages/receive", h.PostReceive)
-	router.Post("/messages/calls/missed", h.PostCallMissed)
+	bodyLimit := []fiber.Handler{middlewares.BodyLimit(h.logger, h.tracer, messageBodyLimit)}
+
+	router.Post("/messages/send", h.computeRoute(bodyLimit, h.PostSend)...)
+	router.Post("/messages/simulate", h.computeRoute(bodyLimit, h.Simulate)...)
+	router.Post("/messages/bulk-send", h.computeRoute([]fiber.Handler{middlewares.BodyLimit(h.logger, h.tracer, messageBulkSendBodyLimit)}, h.BulkSend)...)
+	router.Post("/messages/receive", h.computeRoute(bodyLimit, h.PostReceive)...)
+	router.Post("/messages/calls/missed", h.computeRoute(bodyLimit, h.PostCallMissed)...)
 	router.Get("/messages/outstanding", h.GetOutstanding)
 	router.Get("/messages", h.Index)
 	router.Get("/messages/search", h.Search)
-	router.Post("/messages/:messageID/events", h.PostEvent)
+	router.Get("/contacts/:contact/timeline", h.Timeline)
+	router.Post("/messages/:messageID/events", h.computeRoute(bodyLimit, h.PostEvent)...)
+	router.Post("/messages/:messageID/delivery-report", h.computeRoute(bodyLimit, h.PostDeliveryReport)...)
+	router.Patch("/messages/:messageID", h.computeRoute(bodyLimit, h.Update)...)
 	router.Delete("/messages/:messageID", h.Delete)
+	router.Delete("/messages/:messageID/cancel", h.Cancel)
 }
 
 // PostSend a new entities.Message
@@ -71,6 +90,7 @@ func (h *MessageHandler) RegisterRoutes(router fiber.Router) {
 // @Success      200  {object}  responses.MessageResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      413  {object}  responses.RequestEntityTooLarge
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/send [post]
@@ -87,25 +107,87 @@ func (h *MessageHandler) PostSend(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+	request.Sanitize()
+	phonePoolID, warning, err := h.sendService.Prepare(ctx, request.ToMessageDraft(h.userFromContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot prepare message with paylod [%s]", c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body())
 		ctxLogger.Warn(stacktrace.NewError(msg))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while sending message")
 	}
 
-	if msg := h.billingService.IsEntitled(ctx, h.userIDFomContext(c)); msg != nil {
-		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] can't send a message", h.userIDFomContext(c))))
-		return h.responsePaymentRequired(c, *msg)
-	}
+	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	params.PhonePoolID = phonePoolID
 
-	message, err := h.service.SendMessage(ctx, request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	message, err := h.sendService.Send(ctx, params)
+	if stacktrace.GetCode(err) == services.ErrCodeNotEntitled {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user with ID [%s] can't send a message", h.userIDFomContext(c))))
+		return h.responsePaymentRequired(c, stacktrace.RootCause(err).Error())
+	}
 	if err != nil {
 		msg := fmt.Sprintf("cannot send message with paylod [%s]", c.Body())
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, "message added to queue", message)
+	return h.responseOK(c, "message added to queue"+warning, message)
+}
+
+// Simulate an entities.Message
+// @Summary      Simulate sending an SMS message
+// @Description  Run the validation, the routing, the suppression list, the send rate limits and the billing checks of a message and return what would happen e.g. the phone, the segments and the estimated dispatch time without queueing the message.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MessageSend  true  "Simulate message request payload"
+// @Success      200  {object}  responses.MessageSimulationResponse
+// @Failure      400  {object}  responses.BadRequest
+// @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      413  {object}  responses.RequestEntityTooLarge
+// @Failure      422  {object}  responses.UnprocessableEntity
+// @Failure      500  {object}  responses.InternalServerError
+// @Router       /messages/simulate [post]
+func (h *MessageHandler) Simulate(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageSend
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.Sanitize()
+	phonePoolID, warning, err := h.sendService.Prepare(ctx, request.ToMessageDraft(h.userFromContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot prepare simulated message with paylod [%s]", c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while simulating payload [%s]", spew.Sdump(errors), c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while simulating message")
+	}
+
+	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	params.PhonePoolID = phonePoolID
+
+	simulation, err := h.service.Simulate(ctx, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot simulate message with paylod [%s]", c.Body())
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	simulation.IsEntitled = h.billingService.HasCredits(ctx, h.userIDFomContext(c), simulation.BillableMessages)
+	return h.responseOK(c, "message simulated without queueing"+warning, simulation)
 }
 
 // BulkSend a bulk entities.Message
@@ -119,6 +201,7 @@ func (h *MessageHandler) PostSend(c *fiber.Ctx) error {
 // @Success      200  {object}  []responses.MessagesResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      413  {object}  responses.RequestEntityTooLarge
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/bulk-send [post]
@@ -135,36 +218,30 @@ func (h *MessageHandler) BulkSend(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	if errors := h.validator.ValidateMessageBulkSend(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+	request.Sanitize()
+	_, warning, err := h.sendService.Prepare(ctx, request.ToMessageDraft(h.userFromContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot prepare messages with paylod [%s]", c.Body())))
+		return h.responseInternalServerError(c)
+	}
+
+	if errors := h.validator.ValidateMessageBulkSend(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body())
 		ctxLogger.Warn(stacktrace.NewError(msg))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while sending messages")
 	}
 
-	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(request.To))); msg != nil {
-		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(request.To))))
-		return h.responsePaymentRequired(c, *msg)
+	responses, err := h.sendService.SendBulk(ctx, h.userIDFomContext(c), request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	if stacktrace.GetCode(err) == services.ErrCodeNotEntitled {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(request.To))))
+		return h.responsePaymentRequired(c, stacktrace.RootCause(err).Error())
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot send messages with paylod [%s]", c.Body())))
+		return h.responseInternalServerError(c)
 	}
 
-	wg := sync.WaitGroup{}
-	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
-	responses := make([]*entities.Message, len(params))
-
-	for index, message := range params {
-		wg.Add(1)
-		go func(message services.MessageSendParams, index int) {
-			response, err := h.service.SendMessage(ctx, message)
-			if err != nil {
-				msg := fmt.Sprintf("cannot send message with paylod [%s]", c.Body())
-				ctxLogger.Error(stacktrace.Propagate(err, msg))
-			}
-			responses[index] = response
-			wg.Done()
-		}(message, index)
-	}
-
-	wg.Wait()
-	return h.responseOK(c, fmt.Sprintf("[%d] messages processed successfully", len(responses)), responses)
+	return h.responseOK(c, fmt.Sprintf("[%d] messages processed successfully%s", len(responses), warning), responses)
 }
 
 // GetOutstanding returns an entities.Message which is still to be sent by the mobile phone
@@ -226,9 +303,12 @@ func (h *MessageHandler) GetOutstanding(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        owner		query  string  	true 	"the owner's phone number" 			default(+18005550199)
 // @Param        contact	query  string  	true 	"the contact's phone number" 		default(+18005550100)
-// @Param        skip		query  int  	false	"number of messages to skip"		minimum(0)
+// @Param        skip		query  int  	false	"number of messages to skip, it is ignored when the cursor is set"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page"
 // @Param        query		query  string  	false 	"filter messages containing query"
 // @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(20)
+// @Param        is_starred			query  bool  	false	"only return the messages which are starred or not starred"
+// @Param        needs_follow_up	query  bool  	false	"only return the messages which need or do not need a follow up"
 // @Success      200 		{object}	responses.MessagesResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
@@ -254,14 +334,79 @@ func (h *MessageHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching messages")
 	}
 
-	messages, err := h.service.GetMessages(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	params := request.ToGetParams(h.userIDFomContext(c))
+	messages, err := h.service.GetMessages(ctx, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get messgaes with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages)
+	var cursor *repositories.Cursor
+	if len(*messages) > 0 && len(*messages) == params.Limit {
+		last := (*messages)[len(*messages)-1]
+		cursor = repositories.NewCursor(last.OrderTimestamp, last.ID)
+	}
+
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages, cursor)
+}
+
+// Timeline returns the messages exchanged with a contact by all the phone numbers of the user
+// @Summary      Get the timeline of a contact
+// @Description  Get the messages exchanged with a contact by all your phone numbers in one feed. The messages are sorted by timestamp in descending order.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        contact	path   string  	true 	"the contact's phone number" 		default(+18005550100)
+// @Param        skip		query  int  	false	"number of messages to skip, it is ignored when the cursor is set"		minimum(0)
+// @Param        cursor		query  string  	false	"the next_cursor of the previous page"
+// @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(20)
+// @Success      200 		{object}	responses.MessagesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contact}/timeline [get]
+func (h *MessageHandler) Timeline(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactTimeline
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	contact, err := url.PathUnescape(c.Params("contact"))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unescape contact [%s]", c.Params("contact"))))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.Contact = contact
+	if errors := h.validator.ValidateContactTimeline(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching contact timeline [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact timeline")
+	}
+
+	params := request.ToIndexParams()
+	messages, err := h.service.GetTimeline(ctx, h.userIDFomContext(c), request.Contact, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot get timeline with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	var cursor *repositories.Cursor
+	if len(*messages) > 0 && len(*messages) == params.Limit {
+		last := (*messages)[len(*messages)-1]
+		cursor = repositories.NewCursor(last.OrderTimestamp, last.ID)
+	}
+
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages, cursor)
 }
 
 // PostEvent registers an event on a message
@@ -277,6 +422,7 @@ func (h *MessageHandler) Index(c *fiber.Ctx) error {
 // @Failure      400  		{object}  	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
 // @Failure 	 404		{object}	responses.NotFound
+// @Failure      413  		{object} 	responses.RequestEntityTooLarge
 // @Failure      422  		{object} 	responses.UnprocessableEntity
 // @Failure      500  		{object}  	responses.InternalServerError
 // @Router       /messages/{messageID}/events [post]
@@ -325,6 +471,62 @@ func (h *MessageHandler) PostEvent(c *fiber.Ctx) error {
 	return h.responseOK(c, "message event stored successfully", message)
 }
 
+// PostDeliveryReport registers the delivery report of a message
+// @Summary      Store the carrier delivery report of a message
+// @Description  Use this endpoint on the mobile phone to report if the carrier has delivered a message or if the delivery failed with an error code.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.MessageDeliveryReport	true 	"Payload of the delivery report"
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      413  		{object} 	responses.RequestEntityTooLarge
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/delivery-report [post]
+func (h *MessageHandler) PostDeliveryReport(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageDeliveryReport
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageID = c.Params("messageID")
+	if errors := h.validator.ValidateDeliveryReport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing delivery report [%s] for message [%s]", spew.Sdump(errors), c.Body(), request.MessageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing delivery report")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(request.MessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", request.MessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", request.MessageID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	message, err = h.service.StoreDeliveryReport(ctx, message, request.ToDeliveryReportParams(c.OriginalURL()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store delivery report for message [%s] with paylod [%s]", request.MessageID, c.Body())
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "delivery report stored successfully", message)
+}
+
 // PostReceive receives a new entities.Message
 // @Summary      Receive a new SMS message from a mobile phone
 // @Description  Add a new message received from a mobile phone
@@ -335,6 +537,7 @@ func (h *MessageHandler) PostEvent(c *fiber.Ctx) error {
 // @Param        payload   body requests.MessageReceive  true  "Received message request payload"
 // @Success      200  {object}  responses.MessageResponse
 // @Failure      400  {object}  responses.BadRequest
+// @Failure      413  {object}  responses.RequestEntityTooLarge
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/receive [post]
@@ -372,6 +575,55 @@ func (h *MessageHandler) PostReceive(c *fiber.Ctx) error {
 	return h.responseOK(c, "message received successfully", message)
 }
 
+// Update the flags of a message
+// @Summary      Update the flags of a message
+// @Description  Star a message or mark it as needing a follow up so that your team can triage long threads. Only the flags which are set in the payload are changed.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 						true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.MessageUpdate  	true 	"Payload of the flags to update"
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      413  		{object} 	responses.RequestEntityTooLarge
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID} [patch]
+func (h *MessageHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageID = c.Params("messageID")
+	if errors := h.validator.ValidateMessageUpdate(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating message [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message")
+	}
+
+	message, err := h.service.UpdateFlags(ctx, request.ToFlagsParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", request.MessageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update message with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message updated successfully", message)
+}
+
 // Delete a message
 // @Summary      Delete a message from the database.
 // @Description  Delete a message from the database and removes the message content from the list of threads.
@@ -420,6 +672,64 @@ func (h *MessageHandler) Delete(c *fiber.Ctx) error {
 	return h.responseNoContent(c, "message deleted successfully")
 }
 
+// Cancel a message
+// @Summary      Cancel a message which has not been sent
+// @Description  Cancel a message which has not been fetched by the phone yet i.e. it is in the pending, scheduled or deferred status. A push notification which was already sent for the message is ignored because the phone cannot fetch a cancelled message. A message which was fetched by the phone cannot be cancelled.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/cancel [delete]
+func (h *MessageHandler) Cancel(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageID := c.Params("messageID")
+	if errors := h.validator.ValidateUUID(ctx, messageID, "messageID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while cancelling a message with ID [%s]", spew.Sdump(errors), messageID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while cancelling message")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(messageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", messageID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	errors := responses.ValidationErrors{{Field: "messageID", Rule: "cancellable", Message: "the message has already been picked up by the phone and it cannot be cancelled"}}
+	if !message.CanBeCancelled() {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("message with ID [%s] and status [%s] cannot be cancelled", message.ID, message.Status)))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while cancelling message")
+	}
+
+	message, err = h.service.CancelMessage(ctx, c.OriginalURL(), message.UserID, message.ID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("message with ID [%s] was picked up by the phone while it was being cancelled", messageID)))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while cancelling message")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot cancel message with ID [%s] for user with ID [%s]", messageID, h.userIDFomContext(c))
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message cancelled successfully", message)
+}
+
 // PostCallMissed registers a missed phone call
 // @Summary      Register a missed call event on the mobile phone
 // @Description  This endpoint is called by the httpSMS android app to register a missed call event on the mobile phone.
@@ -432,6 +742,7 @@ func (h *MessageHandler) Delete(c *fiber.Ctx) error {
 // @Failure      400  		{object}  	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
 // @Failure 	 404		{object}	responses.NotFound
+// @Failure      413  		{object} 	responses.RequestEntityTooLarge
 // @Failure      422  		{object} 	responses.UnprocessableEntity
 // @Failure      500  		{object}  	responses.InternalServerError
 // @Router       /messages/calls/missed [post]
@@ -473,6 +784,7 @@ func (h *MessageHandler) PostCallMissed(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        token    	header string   true   	"Cloudflare turnstile token https://www.cloudflare.com/en-gb/application-services/products/turnstile/"
 // @Param        owners		query  string  	true 	"the owner's phone numbers" 		default(+18005550199,+18005550100)
+// @Param        sender_names	query  string  	false 	"the sender names of the messages" 	default(billing,alerts)
 // @Param        skip		query  int  	false	"number of messages to skip"		minimum(0)
 // @Param        query		query  string  	false 	"filter messages containing query"
 // @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(200)
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"fmt"
+	"io"
+	"mime"
 
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/google/uuid"
@@ -42,20 +44,41 @@ func NewMessageThreadHandler(
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *MessageThreadHandler) RegisterRoutes(router fiber.Router) {
 	router.Get("/message-threads", h.Index)
+	router.Get("/message-threads/search", h.Search)
+	router.Get("/message-threads/topics", h.Topics)
+	router.Get("/message-threads/:messageThreadID/context", h.Context)
+	router.Get("/message-threads/:messageThreadID/export", h.Export)
 	router.Put("/message-threads/:messageThreadID", h.Update)
+	router.Put("/message-threads/:messageThreadID/archive", h.Archive)
+	router.Put("/message-threads/:messageThreadID/unarchive", h.Unarchive)
+	router.Put("/message-threads/:messageThreadID/mute", h.Mute)
+	router.Put("/message-threads/:messageThreadID/unmute", h.Unmute)
+	router.Put("/message-threads/:messageThreadID/read", h.Read)
+	router.Put("/message-threads/:messageThreadID/unread", h.Unread)
+	router.Put("/message-threads/:messageThreadID/pin", h.Pin)
+	router.Put("/message-threads/:messageThreadID/unpin", h.Unpin)
+	router.Post("/message-threads/:messageThreadID/labels", h.StoreLabels)
+	router.Delete("/message-threads/:messageThreadID/labels/:label", h.DestroyLabel)
+	router.Put("/message-threads/:messageThreadID/webhook", h.UpdateWebhook)
+	router.Delete("/message-threads/:messageThreadID/webhook", h.DestroyWebhook)
+	router.Delete("/message-threads", h.BulkDelete)
 	router.Delete("/message-threads/:messageThreadID", h.Delete)
 }
 
 // Index returns message threads for a phone number
 // @Summary      Get message threads for a phone number
-// @Description  Get list of contacts which a phone number has communicated with (threads). It will be sorted by timestamp in descending order.
+// @Description  Get list of contacts which a phone number has communicated with (threads). The pinned threads are listed first and the threads are sorted by timestamp in descending order.
 // @Security	 ApiKeyAuth
 // @Tags         MessageThreads
 // @Accept       json
 // @Produce      json
 // @Param        owner	query  string  	true 	"owner phone number" 						default(+18005550199)
-// @Param        skip	query  int  	false	"number of messages to skip"				minimum(0)
+// @Param        skip	query  int  	false	"number of messages to skip, it is ignored when the cursor is set"				minimum(0)
+// @Param        cursor	query  string  	false	"the next_cursor of the previous page"
 // @Param        query	query  string  	false 	"filter message threads containing query"
+// @Param        label	query  string  	false 	"filter message threads with the label"		default(support)
+// @Param        topic	query  string  	false 	"filter message threads with the classified topic"	Enums(billing, support, spam, other)
+// @Param        language	query  string  	false 	"filter message threads with the classified language"	default(en)
 // @Param        limit	query  int  	false	"number of messages to return"				minimum(1)	maximum(20)
 // @Success      200 	{object}	responses.MessageThreadsResponse
 // @Failure      400	{object}	responses.BadRequest
@@ -84,14 +107,108 @@ func (h *MessageThreadHandler) Index(c *fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message threads")
 	}
 
-	threads, err := h.service.GetThreads(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	params := request.ToGetParams(h.userIDFomContext(c))
+	threads, err := h.service.GetThreads(ctx, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot get message threads with params [%+#v]", request)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads)
+	var cursor *repositories.Cursor
+	if len(*threads) > 0 && len(*threads) == params.Limit {
+		last := (*threads)[len(*threads)-1]
+		cursor = repositories.NewCursor(last.OrderTimestamp, last.ID)
+		cursor.IsPinned = last.IsPinned
+	}
+
+	return h.responseOKWithCursor(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads, cursor)
+}
+
+// Search the message threads of a user
+// @Summary      Search message threads
+// @Description  Search the message threads of a user with a contact phone number or last message content which contains the query. Phone numbers can be searched in any format e.g. (800) 555-0100
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param        q			query  string  	true 	"the contact or message content to search for"	default(+18005550100)
+// @Param        owners		query  string  	false 	"the owner's phone numbers"						default(+18005550199)
+// @Param        is_archived	query  bool  	false 	"filter archived or unarchived threads, all threads are searched when it is empty"
+// @Param        skip		query  int  	false	"number of threads to skip"						minimum(0)
+// @Param        limit		query  int  	false	"number of threads to return"					minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.MessageThreadsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-threads/search [get]
+func (h *MessageThreadHandler) Search(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadSearch
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateMessageThreadSearch(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while searching message threads [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while searching message threads")
+	}
+
+	threads, err := h.service.SearchThreads(ctx, request.ToSearchParams(h.userIDFomContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot search message threads with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("found %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads)
+}
+
+// Topics counts the classified message threads of a phone number
+// @Summary      Count message threads by topic
+// @Description  Count the message threads of a phone number which have been classified by topic and language. Threads are only classified when the MESSAGE_THREAD_CLASSIFIER is configured.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param        owner	query  string  	true 	"owner phone number" 	default(+18005550199)
+// @Success      200 	{object}	responses.MessageThreadTopicCountsResponse
+// @Failure      400	{object}	responses.BadRequest
+// @Failure 	 401    {object}	responses.Unauthorized
+// @Failure      422	{object}	responses.UnprocessableEntity
+// @Failure      500	{object}	responses.InternalServerError
+// @Router       /message-threads/topics [get]
+func (h *MessageThreadHandler) Topics(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadTopicIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateTopicIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while counting message thread topics [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while counting message thread topics")
+	}
+
+	counts, err := h.service.GetTopicCounts(ctx, h.userIDFomContext(c), request.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count message thread topics with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("counted threads with %d %s", len(counts), h.pluralize("topic", len(counts))), counts)
 }
 
 // Update an entities.MessageThread
@@ -137,6 +254,492 @@ func (h *MessageThreadHandler) Update(c *fiber.Ctx) error {
 	return h.responseOK(c, "message thread updated successfully", thread)
 }
 
+// Archive an entities.MessageThread
+// @Summary      Archive a message thread
+// @Description  Moves a message thread to the archive so that it is listed when fetching message threads with is_archived=true
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/archive [put]
+func (h *MessageThreadHandler) Archive(c *fiber.Ctx) error {
+	return h.updateArchive(c, true)
+}
+
+// Unarchive an entities.MessageThread
+// @Summary      Unarchive a message thread
+// @Description  Moves an archived message thread back to the inbox
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/unarchive [put]
+func (h *MessageThreadHandler) Unarchive(c *fiber.Ctx) error {
+	return h.updateArchive(c, false)
+}
+
+func (h *MessageThreadHandler) updateArchive(c *fiber.Ctx, isArchived bool) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while setting the archive status of thread with ID [%s] to [%t]", spew.Sdump(errors), messageThreadID, isArchived)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message thread")
+	}
+
+	thread, err := h.service.UpdateStatus(ctx, services.MessageThreadStatusParams{
+		IsArchived:      isArchived,
+		UserID:          h.userIDFomContext(c),
+		MessageThreadID: uuid.MustParse(messageThreadID),
+	})
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot set the archive status of thread with ID [%s] to [%t]", messageThreadID, isArchived)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if isArchived {
+		return h.responseOK(c, "message thread archived successfully", thread)
+	}
+	return h.responseOK(c, "message thread unarchived successfully", thread)
+}
+
+// Mute an entities.MessageThread
+// @Summary      Mute a message thread
+// @Description  Mutes a message thread so that messages received from the contact are still stored but are not forwarded to webhooks or push notifications
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/mute [put]
+func (h *MessageThreadHandler) Mute(c *fiber.Ctx) error {
+	return h.updateMuted(c, true)
+}
+
+// Unmute an entities.MessageThread
+// @Summary      Unmute a message thread
+// @Description  Unmutes a message thread so that received messages are forwarded to webhooks and push notifications again
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/unmute [put]
+func (h *MessageThreadHandler) Unmute(c *fiber.Ctx) error {
+	return h.updateMuted(c, false)
+}
+
+func (h *MessageThreadHandler) updateMuted(c *fiber.Ctx, isMuted bool) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while setting the muted status of thread with ID [%s] to [%t]", spew.Sdump(errors), messageThreadID, isMuted)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message thread")
+	}
+
+	thread, err := h.service.UpdateMuted(ctx, h.userIDFomContext(c), uuid.MustParse(messageThreadID), isMuted)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot set the muted status of thread with ID [%s] to [%t]", messageThreadID, isMuted)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if isMuted {
+		return h.responseOK(c, "message thread muted successfully", thread)
+	}
+	return h.responseOK(c, "message thread unmuted successfully", thread)
+}
+
+// Read marks an entities.MessageThread as read
+// @Summary      Mark a message thread as read
+// @Description  Marks a message thread as read. A thread is unread when it receives a new message from the contact.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/read [put]
+func (h *MessageThreadHandler) Read(c *fiber.Ctx) error {
+	return h.updateUnread(c, false)
+}
+
+// Unread marks an entities.MessageThread as unread
+// @Summary      Mark a message thread as unread
+// @Description  Marks a message thread as unread so that you can come back to it later
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/unread [put]
+func (h *MessageThreadHandler) Unread(c *fiber.Ctx) error {
+	return h.updateUnread(c, true)
+}
+
+func (h *MessageThreadHandler) updateUnread(c *fiber.Ctx, isUnread bool) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while setting the unread status of thread with ID [%s] to [%t]", spew.Sdump(errors), messageThreadID, isUnread)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message thread")
+	}
+
+	thread, err := h.service.UpdateUnread(ctx, h.userIDFomContext(c), uuid.MustParse(messageThreadID), isUnread)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot set the unread status of thread with ID [%s] to [%t]", messageThreadID, isUnread)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if isUnread {
+		return h.responseOK(c, "message thread marked as unread successfully", thread)
+	}
+	return h.responseOK(c, "message thread marked as read successfully", thread)
+}
+
+// Pin an entities.MessageThread
+// @Summary      Pin a message thread
+// @Description  Pins a message thread so that it is listed before the other threads of the owner
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/pin [put]
+func (h *MessageThreadHandler) Pin(c *fiber.Ctx) error {
+	return h.updatePinned(c, true)
+}
+
+// Unpin an entities.MessageThread
+// @Summary      Unpin a message thread
+// @Description  Unpins a message thread so that it is sorted by timestamp with the other threads of the owner
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/unpin [put]
+func (h *MessageThreadHandler) Unpin(c *fiber.Ctx) error {
+	return h.updatePinned(c, false)
+}
+
+func (h *MessageThreadHandler) updatePinned(c *fiber.Ctx, isPinned bool) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while setting the pinned status of thread with ID [%s] to [%t]", spew.Sdump(errors), messageThreadID, isPinned)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message thread")
+	}
+
+	thread, err := h.service.UpdatePinned(ctx, h.userIDFomContext(c), uuid.MustParse(messageThreadID), isPinned)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot set the pinned status of thread with ID [%s] to [%t]", messageThreadID, isPinned)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if isPinned {
+		return h.responseOK(c, "message thread pinned successfully", thread)
+	}
+	return h.responseOK(c, "message thread unpinned successfully", thread)
+}
+
+// StoreLabels adds labels to an entities.MessageThread
+// @Summary      Add labels to a message thread
+// @Description  Adds labels e.g. support, sales or spam to a message thread so you can organize your threads and filter them by label. The labels which are already on the thread are ignored.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 								true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   			body 		requests.MessageThreadLabelsStore 	true 	"Payload of the labels to add"
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/labels [post]
+func (h *MessageThreadHandler) StoreLabels(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadLabelsStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageThreadID = c.Params("messageThreadID")
+	if errors := h.validator.ValidateLabelsStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while adding labels to message thread [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while adding labels to message thread")
+	}
+
+	thread, err := h.service.AddLabels(ctx, h.userIDFomContext(c), request.MessageThreadIDUuid(), request.Labels)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot add labels to message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("added %d %s to message thread", len(request.Labels), h.pluralize("label", len(request.Labels))), thread)
+}
+
+// DestroyLabel removes a label from an entities.MessageThread
+// @Summary      Remove a label from a message thread
+// @Description  Removes a label from a message thread. The request succeeds if the thread does not have the label.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 label				path		string 	true 	"the label to remove" 		default(support)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/labels/{label} [delete]
+func (h *MessageThreadHandler) DestroyLabel(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.MessageThreadLabelDestroy{MessageThreadID: c.Params("messageThreadID"), Label: c.Params("label")}
+	if errors := h.validator.ValidateLabelDestroy(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while removing label from message thread [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while removing label from message thread")
+	}
+
+	thread, err := h.service.RemoveLabel(ctx, h.userIDFomContext(c), request.MessageThreadIDUuid(), request.Label)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot remove label from message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("removed label [%s] from message thread", request.Label), thread)
+}
+
+// UpdateWebhook sets the entities.Webhook of an entities.MessageThread
+// @Summary      Set the webhook of a message thread
+// @Description  Sends the events of a message thread e.g. the replies of a customer to a different webhook. The webhook of the thread takes precedence over the webhooks of your account for the events which it is subscribed to.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 								true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   			body 		requests.MessageThreadWebhookUpdate 	true 	"Payload of the webhook of the thread"
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/webhook [put]
+func (h *MessageThreadHandler) UpdateWebhook(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadWebhookUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageThreadID = c.Params("messageThreadID")
+	if errors := h.validator.ValidateWebhookUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while setting the webhook of message thread [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while setting the webhook of message thread")
+	}
+
+	thread, err := h.service.UpdateWebhook(ctx, h.userIDFomContext(c), request.MessageThreadIDUuid(), request.WebhookIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s] or webhook with ID [%s]", request.MessageThreadID, request.WebhookID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot set the webhook of message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook of message thread updated successfully", thread)
+}
+
+// DestroyWebhook removes the entities.Webhook of an entities.MessageThread
+// @Summary      Remove the webhook of a message thread
+// @Description  Removes the webhook of a message thread so that its events are sent to the webhooks of your account again.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/webhook [delete]
+func (h *MessageThreadHandler) DestroyWebhook(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while removing the webhook of message thread [%s]", spew.Sdump(errors), messageThreadID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while removing the webhook of message thread")
+	}
+
+	thread, err := h.service.UpdateWebhook(ctx, h.userIDFomContext(c), uuid.MustParse(messageThreadID), nil)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot remove the webhook of message thread with ID [%s]", messageThreadID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook of message thread removed successfully", thread)
+}
+
+// BulkDelete deletes many message threads
+// @Summary      Delete many message threads
+// @Description  Delete the message threads with the IDs or the threads which have had no messages for older_than_days days together with all the messages in the threads. At most 1000 threads are deleted in a request, repeat the request until the count is 0 to delete more threads.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.MessageThreadBulkDelete  	true 	"Payload of the threads to delete"
+// @Success      200 		{object}	responses.MessageThreadBulkDeleteResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-threads [delete]
+func (h *MessageThreadHandler) BulkDelete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadBulkDelete
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateBulkDelete(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting message threads [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting message threads")
+	}
+
+	result, err := h.service.BulkDelete(ctx, request.ToBulkDeleteParams(h.userIDFomContext(c), c.OriginalURL()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete message threads with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("deleted %d message %s", result.Count, h.pluralize("thread", result.Count)), result)
+}
+
 // Delete a message thread
 // @Summary      Delete a message thread from the database.
 // @Description  Delete a message thread from the database and also deletes all the messages in the thread.
@@ -182,3 +785,111 @@ func (h *MessageThreadHandler) Delete(c *fiber.Ctx) error {
 
 	return h.responseNoContent(c, "thread thread deleted successfully")
 }
+
+// Export streams all the messages in a message thread as a file
+// @Summary      Export a message thread
+// @Description  Download all the messages in a thread ordered from the oldest to the newest as a CSV or JSON file so you can archive the conversation for compliance or legal records.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Produce      text/csv
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        format				query  		string  false	"format of the exported file"	Enums(csv, json)	default(csv)
+// @Success      200 				{file}		file
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/export [get]
+func (h *MessageThreadHandler) Export(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadExport
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageThreadID = c.Params("messageThreadID")
+	if errors := h.validator.ValidateExport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while exporting message thread [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting message thread")
+	}
+
+	thread, err := h.service.GetThread(ctx, h.userIDFomContext(c), request.MessageThreadIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	// The export is written to a pipe which is read while the response is sent so the thread is not buffered in memory
+	reader, writer := io.Pipe()
+	go func() {
+		err := h.service.Export(ctx, thread, request.ExportFormat(), writer)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot export message thread with params [%+#v]", request)))
+		}
+		_ = writer.CloseWithError(err)
+	}()
+
+	c.Set(fiber.HeaderContentType, request.ExportFormat().ContentType())
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": fmt.Sprintf("httpsms-thread-%s.%s", thread.ID, request.Format)}))
+	return c.SendStream(reader)
+}
+
+// Context returns the recent exchange in a message thread
+// @Summary      Get the context of a message thread
+// @Description  Get the recent messages in a thread in a compact role-annotated format which can be fed directly into bot and LLM integrations. The messages are ordered from the oldest to the newest.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        limit				query  		int  	false	"number of messages to return"	minimum(1)	maximum(100)
+// @Success      200 				{object}	responses.MessageThreadContextResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/context [get]
+func (h *MessageThreadHandler) Context(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadContext
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageThreadID = c.Params("messageThreadID")
+	if errors := h.validator.ValidateContext(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching message thread context [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message thread context")
+	}
+
+	threadContext, err := h.service.GetContext(ctx, request.ToContextParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch context of message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s in message thread context", len(threadContext.Messages), h.pluralize("message", len(threadContext.Messages))), threadContext)
+}
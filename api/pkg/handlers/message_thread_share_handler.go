@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+var messageThreadTranscriptTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<meta name="robots" content="noindex, nofollow">
+	<title>Conversation between {{.Owner}} and {{.Contact}}</title>
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #f5f5f5; margin: 0; padding: 24px; }
+		main { max-width: 640px; margin: 0 auto; }
+		h1 { font-size: 18px; color: #333; }
+		.message { max-width: 80%; margin: 8px 0; padding: 8px 12px; border-radius: 12px; white-space: pre-wrap; word-wrap: break-word; }
+		.user { background: #ffffff; }
+		.assistant { background: #d1e7ff; margin-left: auto; }
+		.system { background: transparent; color: #777; font-style: italic; text-align: center; margin: 8px auto; }
+		time { display: block; font-size: 11px; color: #777; margin-top: 4px; }
+	</style>
+</head>
+<body>
+<main>
+	<h1>Conversation between {{.Owner}} and {{.Contact}}</h1>
+	{{range .Messages}}
+	<div class="message {{.Role}}">{{if .Encrypted}}<em>Encrypted message</em>{{else}}{{.Content}}{{end}}<time datetime="{{.Timestamp.Format "2006-01-02T15:04:05Z07:00"}}">{{.Timestamp.Format "Jan 2, 2006 15:04 MST"}}</time></div>
+	{{else}}
+	<p>There are no messages in this conversation.</p>
+	{{end}}
+</main>
+</body>
+</html>
+`))
+
+// MessageThreadShareHandler handles public share links for message threads
+type MessageThreadShareHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.MessageThreadHandlerValidator
+	service   *services.MessageThreadShareService
+}
+
+// NewMessageThreadShareHandler creates a new MessageThreadShareHandler
+func NewMessageThreadShareHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.MessageThreadHandlerValidator,
+	service *services.MessageThreadShareService,
+) (h *MessageThreadShareHandler) {
+	return &MessageThreadShareHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageThreadShareHandler
+func (h *MessageThreadShareHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler, middlewares ...fiber.Handler) {
+	router := app.Group("shared")
+	router.Get("/message-threads/:token", h.computeRoute(middlewares, h.Show)...)
+
+	authRouter := app.Group("v1/message-threads")
+	authRouter.Post("/:messageThreadID/share", h.computeRoute(append(middlewares, authMiddleware), h.Store)...)
+}
+
+// Store creates a share link for a message thread
+// @Summary      Share a message thread
+// @Description  Generates a signed public URL which renders a read-only transcript of the message thread without authentication. The link stops working after it expires.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 						true 	"ID of the message thread" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   			body 		requests.MessageThreadShare true 	"Payload of the share link"
+// @Success      201 				{object}	responses.MessageThreadShareResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/share [post]
+func (h *MessageThreadShareHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageThreadShare
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+			ctxLogger.Warn(stacktrace.Propagate(err, msg))
+			return h.responseBadRequest(c, err)
+		}
+	}
+
+	request.MessageThreadID = c.Params("messageThreadID")
+	if errors := h.validator.ValidateShare(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while sharing message thread [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sharing message thread")
+	}
+
+	share, err := h.service.Share(ctx, request.ToShareParams(h.userIDFomContext(c), c.BaseURL()))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot share message thread with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "message thread share link created successfully", share)
+}
+
+// Show renders the read-only transcript of a shared message thread
+func (h *MessageThreadShareHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	transcript, err := h.service.LoadShared(ctx, c.Params("token"))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagate(err, "cannot load shared message thread"))
+		return c.Status(fiber.StatusNotFound).SendString("This link is invalid or has expired.")
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot load shared message thread"))
+		return c.Status(fiber.StatusInternalServerError).SendString("We could not load this conversation, please try again later.")
+	}
+
+	return h.renderTranscript(c, transcript)
+}
+
+func (h *MessageThreadShareHandler) renderTranscript(c *fiber.Ctx, transcript *entities.MessageThreadContext) error {
+	var buffer bytes.Buffer
+	if err := messageThreadTranscriptTemplate.Execute(&buffer, transcript); err != nil {
+		h.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot render transcript for message thread [%s]", transcript.MessageThreadID)))
+		return c.Status(fiber.StatusInternalServerError).SendString("We could not load this conversation, please try again later.")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Type("html", "utf-8")
+	return c.Send(buffer.Bytes())
+}
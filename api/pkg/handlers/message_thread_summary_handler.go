@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// MessageThreadSummaryHandler handles requests for the summaries of message threads
+type MessageThreadSummaryHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.MessageThreadHandlerValidator
+	service   *services.MessageThreadSummaryService
+}
+
+// NewMessageThreadSummaryHandler creates a new MessageThreadSummaryHandler
+func NewMessageThreadSummaryHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.MessageThreadHandlerValidator,
+	service *services.MessageThreadSummaryService,
+) (h *MessageThreadSummaryHandler) {
+	return &MessageThreadSummaryHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageThreadSummaryHandler
+func (h *MessageThreadSummaryHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/message-threads/:messageThreadID/summary", h.Show)
+}
+
+// Show returns the summary of a message thread
+// @Summary      Get the summary of a message thread
+// @Description  Get an AI-generated summary of the recent messages in a thread. The summary is cached until a new message is added to the thread. This endpoint is only available when the LLM_PROVIDER is configured on the API.
+// @Security	 ApiKeyAuth
+// @Tags         MessageThreads
+// @Accept       json
+// @Produce      json
+// @Param 		 messageThreadID	path		string 	true 	"ID of the message thread" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 				{object}	responses.MessageThreadSummaryResponse
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404				{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /message-threads/{messageThreadID}/summary [get]
+func (h *MessageThreadSummaryHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	messageThreadID := c.Params("messageThreadID")
+	if errors := h.validator.ValidateUUID(ctx, messageThreadID, "messageThreadID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while summarizing message thread with ID [%s]", spew.Sdump(errors), messageThreadID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while summarizing message thread")
+	}
+
+	summary, err := h.service.Summarize(ctx, h.userIDFomContext(c), uuid.MustParse(messageThreadID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", messageThreadID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot summarize message thread with ID [%s]", messageThreadID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("summarized %d %s in message thread", summary.MessageCount, h.pluralize("message", summary.MessageCount)), summary)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// MetricsHandler exports business metrics in the Prometheus text exposition format
+type MetricsHandler struct {
+	handler
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MetricsService
+	token   string
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+func NewMetricsHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MetricsService,
+	token string,
+) (h *MetricsHandler) {
+	return &MetricsHandler{
+		logger:  logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:  tracer,
+		service: service,
+		token:   token,
+	}
+}
+
+// RegisterRoutes registers the routes for the MetricsHandler
+func (h *MetricsHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	app.Get("/metrics", h.computeRoute(middlewares, h.Index)...)
+	app.Get("/metrics/usage", h.computeRoute(middlewares, h.Usage)...)
+	app.Get("/metrics/deprecations", h.computeRoute(middlewares, h.Deprecations)...)
+}
+
+// Index returns the business metrics in the Prometheus text exposition format
+func (h *MetricsHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAuthorized(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("invalid bearer token for metrics request from [%s]", c.IP())))
+		return h.responseUnauthorized(c)
+	}
+
+	metrics, err := h.service.Collect(ctx)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot collect business metrics"))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return c.Send(h.render(metrics))
+}
+
+// Usage returns the sent and received messages of every account for the billing period which contains the date query
+// parameter e.g. 2022-06-05 in the OpenMetrics text format or as CSV when the format query parameter is csv
+func (h *MetricsHandler) Usage(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAuthorized(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("invalid bearer token for usage metrics request from [%s]", c.IP())))
+		return h.responseUnauthorized(c)
+	}
+
+	timestamp := time.Now().UTC()
+	if date := strings.TrimSpace(c.Query("date")); date != "" {
+		value, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			msg := fmt.Sprintf("cannot parse date [%s] of the usage metrics request", date)
+			ctxLogger.Warn(stacktrace.Propagate(err, msg))
+			return h.responseBadRequest(c, err)
+		}
+		timestamp = value
+	}
+
+	usages, err := h.service.CollectUsage(ctx, timestamp)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot collect the usage of every account for [%s]", timestamp)))
+		return h.responseInternalServerError(c)
+	}
+
+	if strings.ToLower(c.Query("format")) == "csv" {
+		content, err := h.renderUsageCSV(usages)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot render the usage of [%d] accounts as CSV", len(usages))))
+			return h.responseInternalServerError(c)
+		}
+
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"httpsms-usage-%s.csv\"", timestamp.Format("2006-01")))
+		return c.Send(content)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	return c.Send(h.renderUsage(usages))
+}
+
+// Deprecations returns the users and API keys which called the deprecated routes so that they can be contacted before the
+// routes are removed
+func (h *MetricsHandler) Deprecations(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.isAuthorized(c) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("invalid bearer token for deprecations request from [%s]", c.IP())))
+		return h.responseUnauthorized(c)
+	}
+
+	usages, err := h.service.DeprecatedRouteUsages(ctx)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot load the usage of the deprecated routes"))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d deprecated route %s", len(usages), h.pluralize("usage", len(usages))), usages)
+}
+
+func (h *MetricsHandler) isAuthorized(c *fiber.Ctx) bool {
+	return subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte("Bearer "+h.token)) == 1
+}
+
+func (h *MetricsHandler) renderUsage(usages []*entities.BillingUsage) []byte {
+	var buffer bytes.Buffer
+
+	h.writeHeader(&buffer, "httpsms_account_sent_messages", "gauge", "Number of messages sent by an account in the billing period.")
+	for _, usage := range usages {
+		buffer.WriteString(fmt.Sprintf("httpsms_account_sent_messages{user_id=\"%s\",period=\"%s\"} %d\n", h.escapeLabel(string(usage.UserID)), usage.StartTimestamp.Format("2006-01"), usage.SentMessages))
+	}
+
+	h.writeHeader(&buffer, "httpsms_account_received_messages", "gauge", "Number of messages received by an account in the billing period.")
+	for _, usage := range usages {
+		buffer.WriteString(fmt.Sprintf("httpsms_account_received_messages{user_id=\"%s\",period=\"%s\"} %d\n", h.escapeLabel(string(usage.UserID)), usage.StartTimestamp.Format("2006-01"), usage.ReceivedMessages))
+	}
+
+	h.writeHeader(&buffer, "httpsms_usage_collected_timestamp_seconds", "gauge", "Unix timestamp when the usage was collected from the database.")
+	buffer.WriteString(fmt.Sprintf("httpsms_usage_collected_timestamp_seconds %d\n", time.Now().UTC().Truncate(time.Second).Unix()))
+
+	buffer.WriteString("# EOF\n")
+	return buffer.Bytes()
+}
+
+func (h *MetricsHandler) renderUsageCSV(usages []*entities.BillingUsage) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write([]string{"user_id", "start_timestamp", "end_timestamp", "sent_messages", "received_messages", "total_messages"}); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot write the header of the usage CSV")
+	}
+
+	for _, usage := range usages {
+		row := []string{
+			string(usage.UserID),
+			usage.StartTimestamp.UTC().Format(time.RFC3339),
+			usage.EndTimestamp.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(usage.SentMessages), 10),
+			strconv.FormatUint(uint64(usage.ReceivedMessages), 10),
+			strconv.FormatUint(uint64(usage.TotalMessages()), 10),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot write the usage of user [%s] to the CSV", usage.UserID))
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot flush the usage CSV")
+	}
+	return buffer.Bytes(), nil
+}
+
+func (h *MetricsHandler) render(metrics *services.BusinessMetrics) []byte {
+	var buffer bytes.Buffer
+
+	h.writeHeader(&buffer, "httpsms_phone_pending_messages", "gauge", "Number of outgoing messages which are pending, scheduled or sending for a phone.")
+	for _, depth := range metrics.QueueDepths {
+		buffer.WriteString(fmt.Sprintf("httpsms_phone_pending_messages{user_id=\"%s\",owner=\"%s\"} %d\n", h.escapeLabel(string(depth.UserID)), h.escapeLabel(depth.Owner), depth.Count))
+	}
+
+	h.writeHeader(&buffer, "httpsms_phone_seconds_since_last_heartbeat", "gauge", "Seconds since the last heartbeat of a phone which sent a heartbeat in the last 7 days.")
+	for _, heartbeat := range metrics.Heartbeats {
+		seconds := metrics.CollectedAt.Sub(heartbeat.Timestamp).Seconds()
+		buffer.WriteString(fmt.Sprintf("httpsms_phone_seconds_since_last_heartbeat{user_id=\"%s\",owner=\"%s\"} %.0f\n", h.escapeLabel(string(heartbeat.UserID)), h.escapeLabel(heartbeat.Owner), seconds))
+	}
+
+	h.writeHeader(&buffer, "httpsms_webhook_requests_total", "counter", "Number of webhook requests handled by this instance since it started.")
+	buffer.WriteString(fmt.Sprintf("httpsms_webhook_requests_total{result=\"delivered\"} %d\n", metrics.WebhooksDelivered))
+	buffer.WriteString(fmt.Sprintf("httpsms_webhook_requests_total{result=\"failed\"} %d\n", metrics.WebhooksFailed))
+
+	h.writeHeader(&buffer, "httpsms_webhook_failure_ratio", "gauge", "Ratio of failed webhook requests handled by this instance since it started.")
+	buffer.WriteString(fmt.Sprintf("httpsms_webhook_failure_ratio %g\n", metrics.WebhookFailureRatio()))
+
+	h.writeHeader(&buffer, "httpsms_messages_pruned_total", "counter", "Number of messages deleted by the retention policy of their category by this instance since it started.")
+	for _, category := range []entities.MessageCategory{entities.MessageCategoryTransactional, entities.MessageCategoryMarketing, entities.MessageCategoryOTP} {
+		buffer.WriteString(fmt.Sprintf("httpsms_messages_pruned_total{category=\"%s\"} %d\n", category, metrics.MessagesPruned[category]))
+	}
+
+	h.writeHeader(&buffer, "httpsms_dead_letter_queue_size", "gauge", "Number of phone notifications which could not be delivered in the last 24 hours.")
+	buffer.WriteString(fmt.Sprintf("httpsms_dead_letter_queue_size %d\n", metrics.DeadLetters))
+
+	h.writeHeader(&buffer, "httpsms_phone_crashes", "gauge", "Number of crashes and ANRs of the app on a phone in the last 24 hours.")
+	for _, crash := range metrics.PhoneCrashes {
+		buffer.WriteString(fmt.Sprintf("httpsms_phone_crashes{user_id=\"%s\",owner=\"%s\",type=\"%s\"} %d\n", h.escapeLabel(string(crash.UserID)), h.escapeLabel(crash.Owner), crash.Type, crash.Count))
+	}
+
+	h.writeHeader(&buffer, "httpsms_metrics_collected_timestamp_seconds", "gauge", "Unix timestamp when the metrics were collected from the database.")
+	buffer.WriteString(fmt.Sprintf("httpsms_metrics_collected_timestamp_seconds %d\n", metrics.CollectedAt.Truncate(time.Second).Unix()))
+
+	return buffer.Bytes()
+}
+
+func (h *MetricsHandler) writeHeader(buffer *bytes.Buffer, name string, metricType string, help string) {
+	buffer.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType))
+}
+
+func (h *MetricsHandler) escapeLabel(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// MQTTAuthHandler authenticates and authorizes the clients of the MQTT broker with the HTTP backend of mosquitto-go-auth.
+// A request is allowed when the response has the 200 status code.
+type MQTTAuthHandler struct {
+	handler
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MQTTService
+}
+
+// NewMQTTAuthHandler creates a new MQTTAuthHandler
+func NewMQTTAuthHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MQTTService,
+) (h *MQTTAuthHandler) {
+	return &MQTTAuthHandler{
+		logger:  logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MQTTAuthHandler
+func (h *MQTTAuthHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("mqtt")
+	router.Post("/auth", h.computeRoute(middlewares, h.Auth)...)
+	router.Post("/superuser", h.computeRoute(middlewares, h.Superuser)...)
+	router.Post("/acl", h.computeRoute(middlewares, h.ACL)...)
+}
+
+// Auth checks the credentials of an MQTT client
+// @Summary      Authenticate an MQTT client
+// @Description  Checks the credentials of a client which connects to the MQTT broker. The username is the ID of the user and the password is an API key with the send and read scopes.
+// @Tags         MQTT
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MQTTAuth  true  "Credentials of the MQTT client"
+// @Success      200 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure      403		{object}	responses.Forbidden
+// @Router       /mqtt/auth [post]
+func (h *MQTTAuthHandler) Auth(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MQTTAuth
+	if err := c.BodyParser(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot marshall request into [%T]", request)))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if !h.service.Authenticate(ctx, request.Username, request.Password) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("invalid credentials for MQTT client [%s] with username [%s]", request.ClientID, request.Username)))
+		return h.responseForbidden(c)
+	}
+
+	return h.responseOK(c, "the MQTT client is authenticated", nil)
+}
+
+// Superuser checks if an MQTT client is the API
+// @Summary      Check if an MQTT client is a superuser
+// @Description  Only the client of the API which connects with the MQTT_USERNAME is a superuser
+// @Tags         MQTT
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MQTTAuth  true  "Username of the MQTT client"
+// @Success      200 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure      403		{object}	responses.Forbidden
+// @Router       /mqtt/superuser [post]
+func (h *MQTTAuthHandler) Superuser(c *fiber.Ctx) error {
+	_, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MQTTAuth
+	if err := c.BodyParser(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot marshall request into [%T]", request)))
+		return h.responseBadRequest(c, err)
+	}
+
+	if request = request.Sanitize(); !h.service.IsSuperuser(request.Username) {
+		return h.responseForbidden(c)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("MQTT client [%s] with username [%s] is a superuser", request.ClientID, request.Username))
+	return h.responseOK(c, "the MQTT client is a superuser", nil)
+}
+
+// ACL checks if an MQTT client can access a topic
+// @Summary      Authorize an MQTT client
+// @Description  A user can only publish to its send topic and the heartbeat topic and receive the messages of its own topics
+// @Tags         MQTT
+// @Accept       json
+// @Produce      json
+// @Param        payload   body requests.MQTTACL  true  "Topic which is accessed by the MQTT client"
+// @Success      200 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure      403		{object}	responses.Forbidden
+// @Router       /mqtt/acl [post]
+func (h *MQTTAuthHandler) ACL(c *fiber.Ctx) error {
+	_, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MQTTACL
+	if err := c.BodyParser(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot marshall request into [%T]", request)))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if !h.service.Authorize(request.Username, request.Topic, request.AccessLevel()) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("MQTT client [%s] with username [%s] cannot access topic [%s] with [%d]", request.ClientID, request.Username, request.Topic, request.Access)))
+		return h.responseForbidden(c)
+	}
+
+	return h.responseOK(c, "the MQTT client can access the topic", nil)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	mqttSendStatusQueued       = "queued"
+	mqttSendStatusInvalid      = "invalid"
+	mqttSendStatusNotEntitled  = "payment-required"
+	mqttSendStatusServiceError = "error"
+)
+
+// MQTTBridgeHandler sends messages from the commands which are published to the MQTT send topic and stores the
+// heartbeats which are published to the MQTT heartbeat topic
+type MQTTBridgeHandler struct {
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	userRepository repositories.UserRepository
+	service        *services.MQTTService
+
+	// sendService and validator send the messages so MQTT commands are validated, billed and get the opt-out footer exactly like POST /v1/messages/send
+	sendService *services.MessageSendService
+	validator   *validators.MessageHandlerValidator
+
+	// heartbeatHandler stores the MQTT heartbeats exactly like the heartbeat packets which are received over UDP
+	heartbeatHandler *HeartbeatPacketHandler
+}
+
+// NewMQTTBridgeHandler creates a new MQTTBridgeHandler
+func NewMQTTBridgeHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userRepository repositories.UserRepository,
+	service *services.MQTTService,
+	sendService *services.MessageSendService,
+	validator *validators.MessageHandlerValidator,
+	heartbeatHandler *HeartbeatPacketHandler,
+) (h *MQTTBridgeHandler) {
+	return &MQTTBridgeHandler{
+		logger:           logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:           tracer,
+		userRepository:   userRepository,
+		service:          service,
+		sendService:      sendService,
+		validator:        validator,
+		heartbeatHandler: heartbeatHandler,
+	}
+}
+
+// Subscribe starts receiving send commands and heartbeats from the MQTT broker
+func (h *MQTTBridgeHandler) Subscribe(ctx context.Context) error {
+	if err := h.service.SubscribeToSendCommands(ctx, h.handle); err != nil {
+		return stacktrace.Propagate(err, "cannot subscribe to MQTT send commands")
+	}
+
+	err := h.service.SubscribeToHeartbeats(ctx, func(ctx context.Context, payload []byte) {
+		h.heartbeatHandler.Handle(ctx, "mqtt", payload)
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot subscribe to MQTT heartbeats")
+	}
+	return nil
+}
+
+// handle sends the command of the user who published it. The broker authenticates the client with an API key which has
+// the send scope and only allows the user to publish to its own send topic, so the command doesn't contain the API key.
+func (h *MQTTBridgeHandler) handle(ctx context.Context, userID entities.UserID, payload []byte) {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	var request requests.MessageSend
+	if err := json.Unmarshal(payload, &request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal MQTT send command into [%T]", request)))
+		return
+	}
+
+	user, err := h.userRepository.LoadAuthUserByID(ctx, userID)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load user [%s] for MQTT send command", userID)))
+		return
+	}
+
+	result := h.send(ctx, user, &request)
+	if err = h.service.PublishSendResult(ctx, user.ID, result); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot publish MQTT send result for user [%s]", user.ID)))
+	}
+}
+
+func (h *MQTTBridgeHandler) send(ctx context.Context, authUser entities.AuthUser, request *requests.MessageSend) *services.MQTTSendResult {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	request.Sanitize()
+	result := &services.MQTTSendResult{RequestID: request.RequestID, Status: mqttSendStatusServiceError}
+
+	phonePoolID, _, err := h.sendService.Prepare(ctx, request.ToMessageDraft(authUser))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot prepare MQTT send command for user [%s]", authUser.ID)))
+		return result
+	}
+
+	if errors := h.validator.ValidateMessageSend(ctx, authUser.ID, *request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("validation errors [%s], while sending MQTT command [%+#v]", spew.Sdump(errors), request)))
+		result.Status, result.Errors = mqttSendStatusInvalid, errors
+		return result
+	}
+
+	params := request.ToMessageSendParams(authUser.ID, "mqtt")
+	params.PhonePoolID = phonePoolID
+
+	message, err := h.sendService.Send(ctx, params)
+	if stacktrace.GetCode(err) == services.ErrCodeNotEntitled {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user with ID [%s] can't send a message", authUser.ID)))
+		result.Status, result.Errors = mqttSendStatusNotEntitled, responses.ValidationErrors{{Field: "billing", Rule: "entitled", Message: stacktrace.RootCause(err).Error()}}
+		return result
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot send MQTT command for user [%s]", authUser.ID)))
+		return result
+	}
+
+	result.Status, result.Message = mqttSendStatusQueued, message
+	return result
+}
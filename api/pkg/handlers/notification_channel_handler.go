@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// NotificationChannelHandler handles notification channel requests
+type NotificationChannelHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.NotificationChannelService
+	validator *validators.NotificationChannelHandlerValidator
+}
+
+// NewNotificationChannelHandler creates a new NotificationChannelHandler
+func NewNotificationChannelHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.NotificationChannelService,
+	validator *validators.NotificationChannelHandlerValidator,
+) (h *NotificationChannelHandler) {
+	return &NotificationChannelHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the NotificationChannelHandler
+func (h *NotificationChannelHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/notification-channels")
+	router.Get("/", h.computeRoute(middlewares, h.Index)...)
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Put("/:channelID", h.computeRoute(middlewares, h.Update)...)
+	router.Delete("/:channelID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Index returns the notification channels of a user
+// @Summary      Get notification channels of a user
+// @Description  Get the chat channels which receive alerts about the phones and messages of a user
+// @Security	 ApiKeyAuth
+// @Tags         NotificationChannels
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of notification channels to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter notification channels containing query"
+// @Param        limit		query  int  	false	"number of notification channels to return"	minimum(1)	maximum(20)
+// @Success      200 		{object}	responses.NotificationChannelsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /notification-channels 	[get]
+func (h *NotificationChannelHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.NotificationChannelIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching notification channels [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching notification channels")
+	}
+
+	channels, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get notification channels with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(channels), h.pluralize("notification channel", len(channels))), channels)
+}
+
+// Delete a notification channel
+// @Summary      Delete notification channel
+// @Description  Delete a notification channel for a user
+// @Security	 ApiKeyAuth
+// @Tags         NotificationChannels
+// @Accept       json
+// @Produce      json
+// @Param 		 channelID 	path		string 							true 	"ID of the notification channel"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /notification-channels/{channelID} [delete]
+func (h *NotificationChannelHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	channelID := c.Params("channelID")
+	if errors := h.validator.ValidateUUID(ctx, channelID, "channelID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting notification channel with ID [%s]", spew.Sdump(errors), channelID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting notification channel")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(channelID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find notification channel with ID [%s]", channelID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete notification channel with ID [%+#v]", channelID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "notification channel deleted successfully", nil)
+}
+
+// Store a notification channel
+// @Summary      Store a notification channel
+// @Description  Store a chat channel e.g. a Microsoft Teams incoming webhook which receives alerts for the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         NotificationChannels
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.NotificationChannelStore  		true "Payload of the notification channel request"
+// @Success      200 		{object}	responses.NotificationChannelResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /notification-channels [post]
+func (h *NotificationChannelHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.NotificationChannelStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing notification channel [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing notification channel")
+	}
+
+	channels, err := h.service.Index(ctx, h.userIDFomContext(c), repositories.IndexParams{Skip: 0, Limit: 10})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot index notification channels for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	if len(channels) == 10 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] wants to create more than 10 notification channels", h.userIDFomContext(c))))
+		return h.responsePaymentRequired(c, "You can't create more than 10 notification channels contact us to upgrade to our enterprise plan.")
+	}
+
+	channel, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store notification channel with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "notification channel created successfully", channel)
+}
+
+// Update an entities.NotificationChannel
+// @Summary      Update a notification channel
+// @Description  Update a notification channel for the currently authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         NotificationChannels
+// @Accept       json
+// @Produce      json
+// @Param 		 channelID	path		string 							true 	"ID of the notification channel" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.NotificationChannelUpdate  	true 	"Payload of notification channel details to update"
+// @Success      200 		{object}	responses.NotificationChannelResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /notification-channels/{channelID} 	[put]
+func (h *NotificationChannelHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.NotificationChannelUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ChannelID = c.Params("channelID")
+	if errors := h.validator.ValidateUpdate(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating notification channel [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating notification channel")
+	}
+
+	channel, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find notification channel with ID [%s]", request.ChannelID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update notification channel with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "notification channel updated successfully", channel)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneBalanceHandler handles the credit balance and plan expiry of the SIM which are reported by the app on a phone
+type PhoneBalanceHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhoneBalanceService
+	validator *validators.PhoneBalanceHandlerValidator
+}
+
+// NewPhoneBalanceHandler creates a new PhoneBalanceHandler
+func NewPhoneBalanceHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhoneBalanceService,
+	validator *validators.PhoneBalanceHandlerValidator,
+) (h *PhoneBalanceHandler) {
+	return &PhoneBalanceHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhoneBalanceHandler
+func (h *PhoneBalanceHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/phones/:phoneID/balances", h.Index)
+	router.Post("/phones/:phoneID/balances", h.Store)
+}
+
+// Store the balance of the SIM of a phone
+// @Summary      Report the balance of the SIM of a phone
+// @Description  Report the credit balance and plan expiry of the SIM with the USSD response or the SMS of the carrier. The balance is parsed from the content when the amount is empty and an alert is sent when it drops below the low balance threshold of the phone.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 						true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.PhoneBalanceStore  	true 	"Payload of the balance"
+// @Success      201 		{object}	responses.PhoneBalanceResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/balances [post]
+func (h *PhoneBalanceHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneBalanceStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing phone balance [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing phone balance")
+	}
+
+	balance, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c), c.OriginalURL()))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot store phone balance with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone balance stored successfully", balance)
+}
+
+// Index returns the balances of a phone
+// @Summary      Get the balances of a phone
+// @Description  Get the credit balances of the SIM which were reported by the Android app ordered from the newest to the oldest
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 	true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of balances to skip"		minimum(0)
+// @Param        query		query  		string  false 	"filter balances with a content or currency containing query"
+// @Param        limit		query  		int  	false	"number of balances to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhoneBalancesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/balances [get]
+func (h *PhoneBalanceHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneBalanceIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone balances [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone balances")
+	}
+
+	balances, err := h.service.Index(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get phone balances with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d phone %s", len(balances), h.pluralize("balance", len(balances))), balances)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneCrashHandler handles the crashes and ANRs which are reported by the app on a phone
+type PhoneCrashHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhoneCrashService
+	validator *validators.PhoneCrashHandlerValidator
+}
+
+// NewPhoneCrashHandler creates a new PhoneCrashHandler
+func NewPhoneCrashHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhoneCrashService,
+	validator *validators.PhoneCrashHandlerValidator,
+) (h *PhoneCrashHandler) {
+	return &PhoneCrashHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhoneCrashHandler
+func (h *PhoneCrashHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/phones/:phoneID/crashes", h.Index)
+	router.Post("/phones/:phoneID/crashes", h.Store)
+}
+
+// Store a crash of the app on a phone
+// @Summary      Report a crash of the app on a phone
+// @Description  Report a crash or an ANR of the Android app with the stack trace so it can be correlated with the messages which were not sent by the phone.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 						true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.PhoneCrashStore  	true 	"Payload of the crash"
+// @Success      201 		{object}	responses.PhoneCrashResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/crashes [post]
+func (h *PhoneCrashHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneCrashStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing phone crash [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing phone crash")
+	}
+
+	crash, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c), c.Get("X-Client-Version")))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot store phone crash with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone crash stored successfully", crash)
+}
+
+// Index returns the crashes of a phone
+// @Summary      Get the crashes of a phone
+// @Description  Get the crashes and ANRs which were reported by the Android app ordered from the newest to the oldest
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 	true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of crashes to skip"		minimum(0)
+// @Param        query		query  		string  false 	"filter crashes with a message or app version containing query"
+// @Param        limit		query  		int  	false	"number of crashes to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhoneCrashesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/crashes [get]
+func (h *PhoneCrashHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneCrashIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone crashes [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone crashes")
+	}
+
+	crashes, err := h.service.Index(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get phone crashes with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d phone crash %s", len(crashes), h.pluralize("report", len(crashes))), crashes)
+}
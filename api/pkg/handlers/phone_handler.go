@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/validators"
 	"github.com/davecgh/go-spew/spew"
@@ -13,6 +17,9 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
+// phonePollDisconnectInterval is the duration between the checks for a closed connection while a phone is long polling
+const phonePollDisconnectInterval = time.Second
+
 // PhoneHandler handles phone http requests.
 type PhoneHandler struct {
 	handler
@@ -41,7 +48,16 @@ func NewPhoneHandler(
 func (h *PhoneHandler) RegisterRoutes(router fiber.Router) {
 	router.Get("/phones", h.Index)
 	router.Put("/phones", h.Upsert)
+	router.Post("/phones/register", h.Register)
+	router.Get("/phones/:phoneID", h.Show)
 	router.Delete("/phones/:phoneID", h.Delete)
+	router.Get("/phones/:phoneID/settings", h.Settings)
+	router.Get("/phones/:phoneID/queue", h.Queue)
+	router.Get("/phones/:phoneID/queue/stats", h.QueueStats)
+	router.Post("/phones/:phoneID/resync", h.Resync)
+	router.Post("/phones/:phoneID/deactivate", h.Deactivate)
+	router.Post("/phones/:phoneID/reactivate", h.Reactivate)
+	router.Get("/phones/:phoneID/poll", h.Poll)
 }
 
 // Index returns the phones of a user
@@ -116,7 +132,7 @@ func (h *PhoneHandler) Upsert(c *fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	if errors := h.validator.ValidateUpsert(ctx, request.Sanitize()); len(errors) != 0 {
+	if errors := h.validator.ValidateUpsert(ctx, request.Sanitize(), h.userIDFomContext(c)); len(errors) != 0 {
 		msg := fmt.Sprintf("validation errors [%s], while updating phones [%+#v]", spew.Sdump(errors), request)
 		ctxLogger.Warn(stacktrace.NewError(msg))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phones")
@@ -132,6 +148,410 @@ func (h *PhoneHandler) Upsert(c *fiber.Ctx) error {
 	return h.responseOK(c, "phone updated successfully", phone)
 }
 
+// Register a phone
+// @Summary      Register a phone
+// @Description  Registers the phone of the app with its FCM token and app version so that it is notified of new messages with push notifications. The phone is created when it does not exist.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.PhoneRegister  		true 	"Payload of the phone"
+// @Success      200 		{object}	responses.PhoneResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/register [post]
+func (h *PhoneHandler) Register(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneRegister
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateRegister(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while registering phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while registering phone")
+	}
+
+	phone, err := h.service.Register(ctx, request.ToRegisterParams(h.userFromContext(c), c.OriginalURL(), c.Get("X-Client-Version")))
+	if err != nil {
+		msg := fmt.Sprintf("cannot register phone with number [%s]", request.PhoneNumber)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone registered successfully", phone)
+}
+
+// Show returns a phone
+// @Summary      Get a phone
+// @Description  Get a phone which a user has registered on the http sms application with its settings
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID} [get]
+func (h *PhoneHandler) Show(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneShow{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateShow(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone")
+	}
+
+	phone, err := h.service.LoadByID(ctx, h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "fetched phone successfully", phone)
+}
+
+// Queue returns the queued messages of a phone
+// @Summary      Get the queued messages of a phone
+// @Description  Get the outgoing messages which are pending, scheduled or being sent by a phone in the order the phone will send them. The message at position 1 is sent next.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        limit		query  		int  							false	"number of messages to return"		minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhoneQueueResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/queue [get]
+func (h *PhoneHandler) Queue(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneQueue
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateQueue(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching the queue of phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching the queue of phone")
+	}
+
+	entries, err := h.service.Queue(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.LimitInt())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the queue of phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d queued %s", len(entries), h.pluralize("message", len(entries))), entries)
+}
+
+// QueueStats returns the queue stats of a phone
+// @Summary      Get the queue stats of a phone
+// @Description  Get the number of outgoing messages of a phone which are pending, scheduled or being sent, the messages which failed or expired in the last 24 hours and the age of the oldest pending message to see when a backlog is building up on the phone.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneQueueStatsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/queue/stats [get]
+func (h *PhoneHandler) QueueStats(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneQueueStats{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateQueueStats(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching the queue stats of phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching the queue stats of phone")
+	}
+
+	stats, err := h.service.QueueStats(ctx, h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the queue stats of phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched queue stats with %d pending %s", stats.Pending, h.pluralize("message", int(stats.Pending))), stats)
+}
+
+// Poll the messages of a phone
+// @Summary      Long poll the messages of a phone
+// @Description  Wait until a phone has messages to send and return them. This is a fallback for phones which cannot receive push notifications e.g. phones without Google Play Services. Each message is fetched with the /messages/outstanding endpoint before it is sent.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 			path		string 		true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        timeout_seconds	query  		int  		false	"maximum number of seconds to wait for a message"		minimum(0)	maximum(60)
+// @Success      200 		{object}	responses.MessagesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/poll [get]
+func (h *PhoneHandler) Poll(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoll
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidatePoll(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while polling the messages of phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while polling the messages of phone")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go h.cancelOnDisconnect(ctx, cancel, c.Context().Conn())
+
+	messages, err := h.service.Poll(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.Timeout())
+	if ctx.Err() != nil {
+		ctxLogger.Info(fmt.Sprintf("phone [%s] disconnected while long polling", request.PhoneID))
+		return nil
+	}
+
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot poll the messages of phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(messages), h.pluralize("message", len(messages))), messages)
+}
+
+// cancelOnDisconnect cancels a long poll when the phone closes the connection so the server stops querying its messages
+func (h *PhoneHandler) cancelOnDisconnect(ctx context.Context, cancel context.CancelFunc, conn net.Conn) {
+	ticker := time.NewTicker(phonePollDisconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if isConnectionClosed(conn) {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// Settings returns the settings of a phone
+// @Summary      Get the settings of a phone
+// @Description  Get the server-managed configuration of a phone e.g. the heartbeat interval, the SIM and the send rate which the Android app fetches when it starts.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneSettingsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/settings [get]
+func (h *PhoneHandler) Settings(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneSettings{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateSettings(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone settings [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone settings")
+	}
+
+	settings, err := h.service.Settings(ctx, h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch settings of phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "fetched phone settings", settings)
+}
+
+// Resync a phone
+// @Summary      Resync a phone
+// @Description  Push the queued messages of a phone again and request the Android app to upload the results of the messages which were not received by the server. Use this to reconcile the server and the phone after the app was reinstalled.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneResyncResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/resync [post]
+func (h *PhoneHandler) Resync(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneResync{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateResync(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while resyncing phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while resyncing phone")
+	}
+
+	resync, err := h.service.Resync(ctx, c.OriginalURL(), h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot resync phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("resynced phone with %d queued %s", resync.MessageCount, h.pluralize("message", resync.MessageCount)), resync)
+}
+
+// Deactivate a phone
+// @Summary      Deactivate a phone
+// @Description  Take a phone out of service e.g. for maintenance. A deactivated phone is not assigned messages by phone pools, routing rules or failover and its offline alerts are suppressed.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/deactivate [post]
+func (h *PhoneHandler) Deactivate(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneDeactivate{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateDeactivate(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deactivating phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deactivating phone")
+	}
+
+	phone, err := h.service.Deactivate(ctx, c.OriginalURL(), h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot deactivate phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone deactivated successfully", phone)
+}
+
+// Reactivate a phone
+// @Summary      Reactivate a phone
+// @Description  Put a phone which was deactivated back into service so that it is assigned messages and its offline alerts are sent again.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/reactivate [post]
+func (h *PhoneHandler) Reactivate(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := requests.PhoneReactivate{PhoneID: c.Params("phoneID")}
+	if errors := h.validator.ValidateReactivate(ctx, request); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while reactivating phone [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while reactivating phone")
+	}
+
+	phone, err := h.service.Reactivate(ctx, c.OriginalURL(), h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot reactivate phone with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone reactivated successfully", phone)
+}
+
 // Delete a phone
 // @Summary      Delete Phone
 // @Description  Delete a phone that has been sored in the database
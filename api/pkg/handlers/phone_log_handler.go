@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneLogHandler handles the logs which are uploaded by the app on a phone
+type PhoneLogHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhoneLogService
+	validator *validators.PhoneLogHandlerValidator
+}
+
+// NewPhoneLogHandler creates a new PhoneLogHandler
+func NewPhoneLogHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhoneLogService,
+	validator *validators.PhoneLogHandlerValidator,
+) (h *PhoneLogHandler) {
+	return &PhoneLogHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhoneLogHandler
+func (h *PhoneLogHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/phones/:phoneID/logs", h.Index)
+	router.Post("/phones/:phoneID/logs", h.Store)
+	router.Post("/phones/:phoneID/logs/request", h.Request)
+}
+
+// Request the logs of a phone
+// @Summary      Request the logs of a phone
+// @Description  Send a push notification to the Android app to upload its recent logs so you can diagnose the messages which fail on the phone. The logs are available in the list of logs of the phone after the upload.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 							true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhoneLogRequestResponse
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/logs/request [post]
+func (h *PhoneLogHandler) Request(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	phoneID := c.Params("phoneID")
+	if errors := h.validator.ValidateUUID(ctx, phoneID, "phoneID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while requesting the logs of phone with ID [%s]", spew.Sdump(errors), phoneID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while requesting the logs of phone")
+	}
+
+	request, err := h.service.Request(ctx, c.OriginalURL(), h.userIDFomContext(c), uuid.MustParse(phoneID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", phoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot request the logs of phone with ID [%s]", phoneID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "logs requested from phone successfully", request)
+}
+
+// Store the logs of a phone
+// @Summary      Upload the logs of a phone
+// @Description  Upload the recent logs of the Android app as a text, gzip or zip file with a maximum size of 3 MB. This is used by the Android app after the logs are requested.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param 		 phoneID 	path		string 		true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        file		formData	file		true	"The log file to upload"
+// @Success      201 		{object}	responses.PhoneLogResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/logs [post]
+func (h *PhoneLogHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch file with name [%s] from request", "file")
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	phoneID := c.Params("phoneID")
+	if errors := h.validator.ValidateStore(ctx, phoneID, header); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing logs [%s] of phone [%s]", spew.Sdump(errors), header.Filename, phoneID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing the logs of phone")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open uploaded file [%s]", header.Filename)))
+		return h.responseInternalServerError(c)
+	}
+	defer func() { _ = file.Close() }()
+
+	log, err := h.service.Store(ctx, &services.PhoneLogStoreParams{
+		UserID:      h.userIDFomContext(c),
+		PhoneID:     uuid.MustParse(phoneID),
+		Name:        filepath.Base(header.Filename),
+		ContentType: h.validator.ContentType(header),
+		Size:        header.Size,
+		Reader:      file,
+		BaseURL:     c.BaseURL(),
+	})
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", phoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot store logs [%s] of phone [%s]", header.Filename, phoneID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone logs uploaded successfully", log)
+}
+
+// Index returns the logs of a phone
+// @Summary      Get the logs of a phone
+// @Description  Get the log files which were uploaded by the Android app ordered from the newest to the oldest. Download a log file using its URL.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 	true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of logs to skip"		minimum(0)
+// @Param        limit		query  		int  	false	"number of logs to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhoneLogsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/logs [get]
+func (h *PhoneLogHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneLogIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone logs [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone logs")
+	}
+
+	logs, err := h.service.Index(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get phone logs with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d phone %s", len(logs), h.pluralize("log", len(logs))), logs)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneMaintenanceWindowHandler handles the maintenance windows during which a phone is expected to be offline
+type PhoneMaintenanceWindowHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhoneMaintenanceWindowService
+	validator *validators.PhoneMaintenanceWindowHandlerValidator
+}
+
+// NewPhoneMaintenanceWindowHandler creates a new PhoneMaintenanceWindowHandler
+func NewPhoneMaintenanceWindowHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhoneMaintenanceWindowService,
+	validator *validators.PhoneMaintenanceWindowHandlerValidator,
+) (h *PhoneMaintenanceWindowHandler) {
+	return &PhoneMaintenanceWindowHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhoneMaintenanceWindowHandler
+func (h *PhoneMaintenanceWindowHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/phones/:phoneID/maintenance-windows", h.Index)
+	router.Post("/phones/:phoneID/maintenance-windows", h.Store)
+	router.Delete("/phones/:phoneID/maintenance-windows/:windowID", h.Delete)
+}
+
+// Store a maintenance window of a phone
+// @Summary      Schedule a maintenance window for a phone
+// @Description  Declare a period during which a phone is expected to be offline. Offline alerts are not sent and the uptime statistics exclude the maintenance window.
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 									true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.PhoneMaintenanceWindowStore  	true 	"Payload of the maintenance window"
+// @Success      201 		{object}	responses.PhoneMaintenanceWindowResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/maintenance-windows [post]
+func (h *PhoneMaintenanceWindowHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneMaintenanceWindowStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing phone maintenance window [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing phone maintenance window")
+	}
+
+	window, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot store phone maintenance window with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone maintenance window created successfully", window)
+}
+
+// Index returns the maintenance windows of a phone
+// @Summary      Get the maintenance windows of a phone
+// @Description  Get the maintenance windows of a phone ordered from the latest to the earliest start time
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 	true 	"ID of the phone"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  		int  	false	"number of maintenance windows to skip"		minimum(0)
+// @Param        query		query  		string  false 	"filter maintenance windows with a reason containing query"
+// @Param        limit		query  		int  	false	"number of maintenance windows to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhoneMaintenanceWindowsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/maintenance-windows [get]
+func (h *PhoneMaintenanceWindowHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhoneMaintenanceWindowIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PhoneID = c.Params("phoneID")
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone maintenance windows [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone maintenance windows")
+	}
+
+	windows, err := h.service.Index(ctx, h.userIDFomContext(c), request.PhoneIDUuid(), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get phone maintenance windows with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d maintenance %s", len(windows), h.pluralize("window", len(windows))), windows)
+}
+
+// Delete a maintenance window of a phone
+// @Summary      Delete a maintenance window of a phone
+// @Description  Delete a maintenance window so that offline alerts are sent again during the period of the window
+// @Security	 ApiKeyAuth
+// @Tags         Phones
+// @Accept       json
+// @Produce      json
+// @Param 		 phoneID 	path		string 	true 	"ID of the phone"				default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 windowID 	path		string 	true 	"ID of the maintenance window"	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phones/{phoneID}/maintenance-windows/{windowID} [delete]
+func (h *PhoneMaintenanceWindowHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	phoneID := c.Params("phoneID")
+	if errors := h.validator.ValidateUUID(ctx, phoneID, "phoneID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting maintenance window of phone with ID [%s]", spew.Sdump(errors), phoneID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting phone maintenance window")
+	}
+
+	windowID := c.Params("windowID")
+	if errors := h.validator.ValidateUUID(ctx, windowID, "windowID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting maintenance window with ID [%s]", spew.Sdump(errors), windowID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting phone maintenance window")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(phoneID), uuid.MustParse(windowID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find maintenance window with ID [%s] for phone [%s]", windowID, phoneID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete maintenance window with ID [%s] of phone [%s]", windowID, phoneID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "phone maintenance window deleted successfully")
+}
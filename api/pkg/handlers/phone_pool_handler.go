@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhonePoolHandler handles phone pool requests
+type PhonePoolHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhonePoolService
+	validator *validators.PhonePoolHandlerValidator
+}
+
+// NewPhonePoolHandler creates a new PhonePoolHandler
+func NewPhonePoolHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhonePoolService,
+	validator *validators.PhonePoolHandlerValidator,
+) (h *PhonePoolHandler) {
+	return &PhonePoolHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhonePoolHandler
+func (h *PhonePoolHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Handler) {
+	router := app.Group("/v1/phone-pools")
+	router.Get("/", h.computeRoute(middlewares, h.Index)...)
+	router.Post("/", h.computeRoute(middlewares, h.Store)...)
+	router.Put("/:poolID", h.computeRoute(middlewares, h.Update)...)
+	router.Delete("/:poolID", h.computeRoute(middlewares, h.Delete)...)
+}
+
+// Index returns the phone pools of a user
+// @Summary      Get phone pools of a user
+// @Description  Get the pools of phones which share the outgoing messages that are sent from the pool
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of phone pools to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter phone pools containing query"
+// @Param        limit		query  int  	false	"number of phone pools to return"	minimum(1)	maximum(20)
+// @Success      200 		{object}	responses.PhonePoolsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools 	[get]
+func (h *PhonePoolHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoolIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching phone pools [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone pools")
+	}
+
+	pools, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get phone pools with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(pools), h.pluralize("phone pool", len(pools))), pools)
+}
+
+// Delete a phone pool
+// @Summary      Delete phone pool
+// @Description  Delete a phone pool for a user
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param 		 poolID 	path		string 							true 	"ID of the phone pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools/{poolID} [delete]
+func (h *PhonePoolHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	poolID := c.Params("poolID")
+	if errors := h.validator.ValidateUUID(ctx, poolID, "poolID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting phone pool with ID [%s]", spew.Sdump(errors), poolID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting phone pool")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(poolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone pool with ID [%s]", poolID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete phone pool with ID [%+#v]", poolID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone pool deleted successfully", nil)
+}
+
+// Store a phone pool
+// @Summary      Store a phone pool
+// @Description  Store a pool of phones which share the outgoing messages that are sent with the ID of the pool, round robin or to the phone with the fewest pending messages
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.PhonePoolStore  		true "Payload of the phone pool request"
+// @Success      200 		{object}	responses.PhonePoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools [post]
+func (h *PhonePoolHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoolStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing phone pool [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing phone pool")
+	}
+
+	pools, err := h.service.Index(ctx, h.userIDFomContext(c), repositories.IndexParams{Skip: 0, Limit: 20})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot index phone pools for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	if len(pools) == 20 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] wants to create more than 20 phone pools", h.userIDFomContext(c))))
+		return h.responsePaymentRequired(c, "You can't create more than 20 phone pools contact us to upgrade to our enterprise plan.")
+	}
+
+	pool, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store phone pool with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone pool created successfully", pool)
+}
+
+// Update an entities.PhonePool
+// @Summary      Update a phone pool
+// @Description  Update a phone pool for the currently authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param 		 poolID	path		string 							true 	"ID of the phone pool" 		default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.PhonePoolUpdate  	true 	"Payload of phone pool details to update"
+// @Success      200 		{object}	responses.PhonePoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools/{poolID} 	[put]
+func (h *PhonePoolHandler) Update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoolUpdate
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.PoolID = c.Params("poolID")
+	if errors := h.validator.ValidateUpdate(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating phone pool [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phone pool")
+	}
+
+	pool, err := h.service.Update(ctx, request.ToUpdateParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone pool with ID [%s]", request.PoolID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update phone pool with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone pool updated successfully", pool)
+}
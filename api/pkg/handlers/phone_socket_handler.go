@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gorilla/websocket"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// phoneSocketPath is the path where the app opens the WebSocket connection
+	phoneSocketPath = "/v1/phones/socket"
+
+	// phoneSocketPingInterval is how often a ping is sent so proxies do not close an idle connection
+	phoneSocketPingInterval = 30 * time.Second
+
+	// phoneSocketPongTimeout is how long the server waits for a pong before the connection is considered broken
+	phoneSocketPongTimeout = 2 * phoneSocketPingInterval
+
+	// phoneSocketWriteTimeout is the maximum time for writing a single frame to the connection
+	phoneSocketWriteTimeout = 10 * time.Second
+
+	// phoneSocketMaxMessageSize is the maximum size of a frame which is read from the app
+	phoneSocketMaxMessageSize = 1024
+
+	// phoneSocketCleanupInterval is how often the socket connections of phones which are not connected to any process are cleared
+	phoneSocketCleanupInterval = 5 * time.Minute
+)
+
+// PhoneSocketHandler holds the WebSocket connections which the app opens to receive commands in real time on devices
+// which don't have Google Play Services for push notifications
+type PhoneSocketHandler struct {
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	userRepository repositories.UserRepository
+	service        *services.PhoneService
+	registry       *services.PhoneSocketRegistry
+	upgrader       websocket.Upgrader
+}
+
+// NewPhoneSocketHandler creates a new PhoneSocketHandler
+func NewPhoneSocketHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userRepository repositories.UserRepository,
+	service *services.PhoneService,
+	registry *services.PhoneSocketRegistry,
+) (h *PhoneSocketHandler) {
+	return &PhoneSocketHandler{
+		logger:         logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:         tracer,
+		userRepository: userRepository,
+		service:        service,
+		registry:       registry,
+		upgrader: websocket.Upgrader{
+			// the app is not a browser so the origin is not checked, the connection is authenticated with the API key
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+// ListenAndServe accepts WebSocket connections on the TCP address until the context is cancelled. The connections are
+// encrypted when tlsConfig is not nil.
+func (h *PhoneSocketHandler) ListenAndServe(ctx context.Context, address string, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot listen for websocket connections on [%s]", address))
+	}
+
+	scheme := "ws"
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+		scheme = "wss"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(phoneSocketPath, func(writer http.ResponseWriter, request *http.Request) {
+		h.serve(ctx, writer, request)
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: phoneSocketWriteTimeout}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	go h.cleanup(ctx)
+
+	h.logger.Info(fmt.Sprintf("listening for websocket connections on [%s://%s%s]", scheme, listener.Addr(), phoneSocketPath))
+
+	if err = server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot serve websocket connections on [%s]", address))
+	}
+	return nil
+}
+
+// cleanup clears the socket connections of the phones which are not connected to any process until the context is
+// cancelled e.g. when the process which held the connection crashed before the phone disconnected
+func (h *PhoneSocketHandler) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(phoneSocketCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.service.ClearStaleSocketConnections(ctx, h.registry); err != nil {
+			h.logger.Error(stacktrace.Propagate(err, "cannot clear the stale socket connections of phones"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *PhoneSocketHandler) serve(ctx context.Context, writer http.ResponseWriter, request *http.Request) {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	apiKey := request.Header.Get("x-api-key")
+	if apiKey == "" {
+		http.Error(writer, "The x-api-key header is required", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userRepository.LoadAuthUser(ctx, apiKey)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, "cannot load user for websocket connection"))
+		http.Error(writer, "The x-api-key header is not valid", http.StatusUnauthorized)
+		return
+	}
+
+	if !user.HasScope(entities.APIKeyScopeAdmin) {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("api key of user [%s] does not have the [%s] scope to open a websocket connection", user.ID, entities.APIKeyScopeAdmin)))
+		http.Error(writer, "Your API key does not have the scope to carry out this request.", http.StatusForbidden)
+		return
+	}
+
+	phone, err := h.service.Load(ctx, user.ID, request.URL.Query().Get("owner"))
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone with owner [%s] for user [%s]", request.URL.Query().Get("owner"), user.ID)))
+		http.Error(writer, fmt.Sprintf("cannot find phone with owner [%s]", request.URL.Query().Get("owner")), http.StatusNotFound)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot upgrade websocket connection for phone [%s]", phone.ID)))
+		return
+	}
+
+	socket := &phoneSocket{conn: conn}
+	h.connect(ctx, phone, socket)
+	defer h.disconnect(ctx, phone, socket)
+
+	done := make(chan struct{})
+	defer close(done)
+	go socket.ping(done)
+
+	socket.read()
+}
+
+func (h *PhoneSocketHandler) connect(ctx context.Context, phone *entities.Phone, socket *phoneSocket) {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	if err := h.registry.Register(ctx, phone.ID, socket); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot register websocket connection of phone [%s]", phone.ID)))
+	}
+
+	connectedAt := time.Now().UTC()
+	if err := h.service.UpdateSocketConnectedAt(ctx, phone.UserID, phone.ID, &connectedAt); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store websocket connection of phone [%s]", phone.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone [%s] for user [%s] connected over websocket, [%d] phones are connected", phone.ID, phone.UserID, h.registry.Count()))
+}
+
+func (h *PhoneSocketHandler) disconnect(ctx context.Context, phone *entities.Phone, socket *phoneSocket) {
+	ctx, span, ctxLogger := h.tracer.StartWithLogger(ctx, h.logger)
+	defer span.End()
+
+	_ = socket.conn.Close()
+
+	// the phone reconnected before this connection was closed so it is still connected
+	unregistered, err := h.registry.Unregister(ctx, phone.ID, socket)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unregister websocket connection of phone [%s]", phone.ID)))
+	}
+	if !unregistered {
+		return
+	}
+
+	if err = h.service.UpdateSocketConnectedAt(ctx, phone.UserID, phone.ID, nil); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot clear websocket connection of phone [%s]", phone.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone [%s] for user [%s] disconnected from websocket, [%d] phones are connected", phone.ID, phone.UserID, h.registry.Count()))
+}
+
+// phoneSocket is a services.PhoneSocket which serializes the writes to a WebSocket connection
+type phoneSocket struct {
+	mutex sync.Mutex
+	conn  *websocket.Conn
+}
+
+// Send a command to the phone as a JSON text frame
+func (socket *phoneSocket) Send(command *services.PhoneSocketCommand) error {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+
+	_ = socket.conn.SetWriteDeadline(time.Now().Add(phoneSocketWriteTimeout))
+	if err := socket.conn.WriteJSON(command); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot write [%s] command to websocket", command.Type))
+	}
+	return nil
+}
+
+// read discards the frames from the app until the connection is closed or a pong is not received in time
+func (socket *phoneSocket) read() {
+	socket.conn.SetReadLimit(phoneSocketMaxMessageSize)
+	_ = socket.conn.SetReadDeadline(time.Now().Add(phoneSocketPongTimeout))
+	socket.conn.SetPongHandler(func(string) error {
+		return socket.conn.SetReadDeadline(time.Now().Add(phoneSocketPongTimeout))
+	})
+
+	for {
+		if _, _, err := socket.conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+// ping the app until done is closed so broken connections are detected
+func (socket *phoneSocket) ping(done chan struct{}) {
+	ticker := time.NewTicker(phoneSocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			socket.mutex.Lock()
+			err := socket.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(phoneSocketWriteTimeout))
+			socket.mutex.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ReportHandler handles scheduled report requests
+type ReportHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.ReportService
+	validator *validators.ReportHandlerValidator
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ReportService,
+	validator *validators.ReportHandlerValidator,
+) (h *ReportHandler) {
+	return &ReportHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the ReportHandler
+func (h *ReportHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler, middlewares ...fiber.Handler) {
+	router := app.Group("shared")
+	router.Get("/reports/:token", h.computeRoute(middlewares, h.Download)...)
+
+	authRouter := app.Group("v1/reports")
+	authRouter.Get("/", h.computeRoute(append(middlewares, authMiddleware), h.Index)...)
+	authRouter.Post("/", h.computeRoute(append(middlewares, authMiddleware), h.Store)...)
+	authRouter.Delete("/:reportID", h.computeRoute(append(middlewares, authMiddleware), h.Delete)...)
+}
+
+// Index returns the scheduled reports of a user
+// @Summary      Get scheduled reports of a user
+// @Description  Get the reports which are generated periodically and delivered by email or webhook to the user
+// @Security	 ApiKeyAuth
+// @Tags         Reports
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of reports to skip"		minimum(0)
+// @Param        limit		query  int  	false	"number of reports to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ReportsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /reports 	[get]
+func (h *ReportHandler) Index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ReportIndex
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall URL [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching reports [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching reports")
+	}
+
+	reports, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot get reports with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(reports), h.pluralize("report", len(reports))), reports)
+}
+
+// Store a scheduled report
+// @Summary      Schedule a report
+// @Description  Schedule a daily usage or weekly failure report in CSV format which is delivered as an email attachment or as a webhook event with a signed download URL
+// @Security	 ApiKeyAuth
+// @Tags         Reports
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ReportStore  		true "Payload of the scheduled report"
+// @Success      201 		{object}	responses.ReportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 402	    {object}	responses.PaymentRequired
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /reports [post]
+func (h *ReportHandler) Store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ReportStore
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing report [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing report")
+	}
+
+	reports, err := h.service.Index(ctx, h.userIDFomContext(c), repositories.IndexParams{Skip: 0, Limit: 10})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot index reports for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	if len(reports) == 10 {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("user with ID [%s] wants to create more than 10 reports", h.userIDFomContext(c))))
+		return h.responsePaymentRequired(c, "You can't schedule more than 10 reports contact us to upgrade to our enterprise plan.")
+	}
+
+	report, err := h.service.Store(ctx, request.ToStoreParams(h.userFromContext(c)))
+	if err != nil {
+		msg := fmt.Sprintf("cannot store report with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "report scheduled successfully", report)
+}
+
+// Delete a scheduled report
+// @Summary      Delete scheduled report
+// @Description  Delete a scheduled report so that it is no longer generated
+// @Security	 ApiKeyAuth
+// @Tags         Reports
+// @Accept       json
+// @Produce      json
+// @Param 		 reportID 	path		string 							true 	"ID of the report"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /reports/{reportID} [delete]
+func (h *ReportHandler) Delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	reportID := c.Params("reportID")
+	if errors := h.validator.ValidateUUID(ctx, reportID, "reportID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting report with ID [%s]", spew.Sdump(errors), reportID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting report")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(reportID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find report with ID [%s]", reportID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete report with ID [%+#v]", reportID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "report deleted successfully", nil)
+}
+
+// Download streams the CSV file of a report using the signed URL from the report.generated webhook event
+func (h *ReportHandler) Download(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	attachment, reader, err := h.service.LoadDownload(ctx, c.Params("token"))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagate(err, "cannot load report download"))
+		return c.Status(fiber.StatusNotFound).SendString("This link is invalid or has expired.")
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot load report download"))
+		return c.Status(fiber.StatusInternalServerError).SendString("We could not load this report, please try again later.")
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendStream(reader, int(attachment.Size))
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// StatisticsHandler handles the requests of the Grafana JSON datasource
+type StatisticsHandler struct {
+	handler
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	validator    *validators.StatisticsHandlerValidator
+	service      *services.StatisticsService
+	phoneService *services.PhoneService
+}
+
+// NewStatisticsHandler creates a new StatisticsHandler
+func NewStatisticsHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.StatisticsHandlerValidator,
+	service *services.StatisticsService,
+	phoneService *services.PhoneService,
+) (h *StatisticsHandler) {
+	return &StatisticsHandler{
+		logger:       logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:       tracer,
+		validator:    validator,
+		service:      service,
+		phoneService: phoneService,
+	}
+}
+
+// RegisterRoutes registers the routes for the StatisticsHandler
+func (h *StatisticsHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/statistics/timeseries", h.Health)
+	router.Post("/statistics/timeseries/metrics", h.Metrics)
+	router.Post("/statistics/timeseries/search", h.Search)
+	router.Post("/statistics/timeseries/metric-payload-options", h.MetricPayloadOptions)
+	router.Post("/statistics/timeseries/query", h.Query)
+}
+
+// Health is called by Grafana when testing the JSON datasource
+// @Summary      Test the Grafana JSON datasource
+// @Description  Returns 200 when the API key is valid so Grafana can test the JSON datasource with the URL /v1/statistics/timeseries
+// @Security	 ApiKeyAuth
+// @Tags         Statistics
+// @Produce      json
+// @Success      200 		{object}	responses.OkString
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Router       /statistics/timeseries [get]
+func (h *StatisticsHandler) Health(c *fiber.Ctx) error {
+	return h.responseOK(c, "the statistics datasource is working", "ok")
+}
+
+// Metrics returns the metrics which can be queried
+// @Summary      List the time series metrics
+// @Description  Returns the metrics which can be selected in the query editor of the Grafana JSON datasource
+// @Security	 ApiKeyAuth
+// @Tags         Statistics
+// @Produce      json
+// @Success      200 		{array}		responses.GrafanaMetric
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Router       /statistics/timeseries/metrics [post]
+func (h *StatisticsHandler) Metrics(c *fiber.Ctx) error {
+	metrics := make([]responses.GrafanaMetric, 0, len(services.StatisticsTargets))
+	for _, target := range services.StatisticsTargets {
+		metrics = append(metrics, responses.GrafanaMetric{
+			Label: target,
+			Value: target,
+			Payloads: []responses.GrafanaMetricPayload{
+				{Label: "Phone", Name: "owner", Type: "select", Placeholder: "All phones"},
+			},
+		})
+	}
+	return c.JSON(metrics)
+}
+
+// Search returns the metrics which can be queried for older versions of the Grafana JSON datasource
+// @Summary      Search the time series metrics
+// @Description  Returns the names of the metrics for older versions of the Grafana JSON datasource
+// @Security	 ApiKeyAuth
+// @Tags         Statistics
+// @Produce      json
+// @Success      200 		{array}		string
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Router       /statistics/timeseries/search [post]
+func (h *StatisticsHandler) Search(c *fiber.Ctx) error {
+	return c.JSON(services.StatisticsTargets)
+}
+
+// MetricPayloadOptions returns the phones which can be used to filter a metric
+// @Summary      List the phones for filtering a metric
+// @Description  Returns the phone numbers of the user for the "owner" payload in the query editor of the Grafana JSON datasource
+// @Security	 ApiKeyAuth
+// @Tags         Statistics
+// @Produce      json
+// @Success      200 		{array}		responses.GrafanaPayloadOption
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /statistics/timeseries/metric-payload-options [post]
+func (h *StatisticsHandler) MetricPayloadOptions(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	phones, err := h.phoneService.Index(ctx, h.userFromContext(c), repositories.IndexParams{Limit: 100})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch phones for user [%s]", h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	options := make([]responses.GrafanaPayloadOption, 0, len(*phones))
+	for _, phone := range *phones {
+		options = append(options, responses.GrafanaPayloadOption{Label: phone.PhoneNumber, Value: phone.PhoneNumber})
+	}
+	return c.JSON(options)
+}
+
+// Query returns the time series of the targets
+// @Summary      Query time series
+// @Description  Returns the number of sent, received, delivered, failed and expired messages, heartbeats and the uptime of your phones in the format of the Grafana JSON datasource
+// @Security	 ApiKeyAuth
+// @Tags         Statistics
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.StatisticsTimeseries  	true 	"Grafana query"
+// @Success      200 		{array}		entities.Timeseries
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /statistics/timeseries/query [post]
+func (h *StatisticsHandler) Query(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.StatisticsTimeseries
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall [%s] into %T", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateTimeseries(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while querying time series [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while querying time series")
+	}
+
+	series, err := h.service.Timeseries(ctx, request.ToTimeseriesParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot query time series with params [%+#v]", request)))
+		return h.responseInternalServerError(c)
+	}
+
+	return c.JSON(series)
+}
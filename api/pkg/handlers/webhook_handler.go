@@ -47,6 +47,9 @@ func (h *WebhookHandler) RegisterRoutes(app *fiber.App, middlewares ...fiber.Han
 	router.Post("/", h.computeRoute(middlewares, h.Store)...)
 	router.Put("/:webhookID", h.computeRoute(middlewares, h.Update)...)
 	router.Delete("/:webhookID", h.computeRoute(middlewares, h.Delete)...)
+	router.Post("/:webhookID/pause", h.computeRoute(middlewares, h.Pause)...)
+	router.Post("/:webhookID/resume", h.computeRoute(middlewares, h.Resume)...)
+	router.Post("/:webhookID/replay", h.computeRoute(middlewares, h.Replay)...)
 }
 
 // Index returns the webhooks of a user
@@ -223,3 +226,131 @@ func (h *WebhookHandler) Update(c *fiber.Ctx) error {
 
 	return h.responseOK(c, "webhook updated successfully", user)
 }
+
+// Pause a webhook
+// @Summary      Pause webhook
+// @Description  Pause a webhook so that no events are forwarded to it until it is resumed
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID 	path		string 							true 	"ID of the webhook"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.WebhookResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/pause [post]
+func (h *WebhookHandler) Pause(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	webhookID := c.Params("webhookID")
+	if errors := h.validator.ValidateUUID(ctx, webhookID, "webhookID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while pausing webhook with ID [%s]", spew.Sdump(errors), webhookID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while pausing webhook")
+	}
+
+	webhook, err := h.service.Pause(ctx, h.userIDFomContext(c), uuid.MustParse(webhookID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find webhook with ID [%s]", webhookID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot pause webhook with ID [%s]", webhookID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook paused successfully", webhook)
+}
+
+// Resume a webhook
+// @Summary      Resume webhook
+// @Description  Resume a paused webhook and reset its consecutive failure count
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID 	path		string 							true 	"ID of the webhook"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.WebhookResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/resume [post]
+func (h *WebhookHandler) Resume(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	webhookID := c.Params("webhookID")
+	if errors := h.validator.ValidateUUID(ctx, webhookID, "webhookID"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while resuming webhook with ID [%s]", spew.Sdump(errors), webhookID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while resuming webhook")
+	}
+
+	webhook, err := h.service.Resume(ctx, h.userIDFomContext(c), uuid.MustParse(webhookID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find webhook with ID [%s]", webhookID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot resume webhook with ID [%s]", webhookID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook resumed successfully", webhook)
+}
+
+// Replay the events of a time range to a webhook
+// @Summary      Replay events to a webhook
+// @Description  Send the stored events which happened in a time range again to a webhook e.g. after the receiver was down. The events are sent in the background in the order in which they happened with their original IDs so the receiver can ignore the events which it already processed. At most 10,000 events are replayed in one request and `next_from` is the start of the next replay when the time range has more events.
+// @Security	 ApiKeyAuth
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param 		 webhookID	path		string 							true 	"ID of the webhook" 					default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.WebhookReplay  		true 	"Time range and event types to replay"
+// @Success      200 		{object}	responses.WebhookReplayResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /webhooks/{webhookID}/replay [post]
+func (h *WebhookHandler) Replay(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.WebhookReplay
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall body [%s] into [%T]", c.Body(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.WebhookID = c.Params("webhookID")
+	if errors := h.validator.ValidateReplay(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while replaying events to webhook [%+#v]", spew.Sdump(errors), request)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while replaying events to webhook")
+	}
+
+	replay, err := h.service.Replay(ctx, request.ToReplayParams(h.userFromContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find webhook with ID [%s]", request.WebhookID))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot replay events to webhook with params [%+#v]", request)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("%d %s queued to be replayed", replay.Count, h.pluralize("event", replay.Count)), replay)
+}
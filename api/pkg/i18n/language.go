@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a language which the API messages are translated to
+type Language string
+
+const (
+	// English is the default language of the API messages
+	English = Language("en")
+
+	// French translates the API messages to French
+	French = Language("fr")
+
+	// Spanish translates the API messages to Spanish
+	Spanish = Language("es")
+)
+
+// String converts the Language to a string
+func (language Language) String() string {
+	return string(language)
+}
+
+// IsSupported checks if the API messages can be translated to the Language
+func (language Language) IsSupported() bool {
+	return language == English || language == French || language == Spanish
+}
+
+// FromAcceptLanguage returns the supported Language with the highest quality in an Accept-Language header
+// e.g. "fr-CA,fr;q=0.9,en;q=0.8" is French. English is returned when no language in the header is supported.
+func FromAcceptLanguage(header string) Language {
+	type preference struct {
+		language Language
+		quality  float64
+	}
+
+	var preferences []preference
+	for _, item := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if language := Language(primary); quality > 0 && language.IsSupported() {
+			preferences = append(preferences, preference{language: language, quality: quality})
+		}
+	}
+
+	if len(preferences) == 0 {
+		return English
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+	return preferences[0].language
+}
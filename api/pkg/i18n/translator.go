@@ -0,0 +1,185 @@
+package i18n
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+)
+
+// messages are the translations of the API messages which are the same in every request
+var messages = map[Language]map[string]string{
+	French: {
+		"The request isn't properly formed":                                      "La requête n'est pas correctement formée",
+		"We ran into an internal error while handling the request.":              "Une erreur interne s'est produite lors du traitement de la requête.",
+		"You are not authorized to carry out this request.":                      "Vous n'êtes pas autorisé à effectuer cette requête.",
+		"Make sure your API key is set in the [X-API-Key] header in the request": "Assurez-vous que votre clé API est définie dans l'en-tête [X-API-Key] de la requête",
+		"Forbidden":                              "Interdit",
+		"The request contains validation errors": "La requête contient des erreurs de validation",
+		"The request body is too large":          "Le corps de la requête est trop volumineux",
+		"Your API key does not have the scope to carry out this request.": "Votre clé API n'a pas la portée nécessaire pour effectuer cette requête.",
+	},
+	Spanish: {
+		"The request isn't properly formed":                                      "La solicitud no está formada correctamente",
+		"We ran into an internal error while handling the request.":              "Se produjo un error interno al procesar la solicitud.",
+		"You are not authorized to carry out this request.":                      "No está autorizado para realizar esta solicitud.",
+		"Make sure your API key is set in the [X-API-Key] header in the request": "Asegúrese de que su clave API esté definida en el encabezado [X-API-Key] de la solicitud",
+		"Forbidden":                              "Prohibido",
+		"The request contains validation errors": "La solicitud contiene errores de validación",
+		"The request body is too large":          "El cuerpo de la solicitud es demasiado grande",
+		"Your API key does not have the scope to carry out this request.": "Su clave API no tiene el alcance necesario para realizar esta solicitud.",
+	},
+}
+
+// rules are the translations of the validation errors of a rule. The :field and :param placeholders are replaced
+// with the field path and the parameter of the rule which failed.
+var rules = map[Language]map[string]string{
+	French: {
+		"required":                   "Le champ :field est obligatoire",
+		"min":                        "Le champ :field doit être au minimum :param",
+		"max":                        "Le champ :field doit être au maximum :param",
+		"numeric":                    "Le champ :field doit être un nombre",
+		"numeric_between":            "Le champ :field doit être un nombre entre :param",
+		"in":                         "Le champ :field doit être l'une des valeurs :param",
+		"uuid":                       "Le champ :field doit être un UUID valide",
+		"url":                        "Le champ :field doit être une URL valide",
+		"alpha_dash":                 "Le champ :field ne peut contenir que des lettres, des chiffres, des tirets et des underscores",
+		"json":                       "Le champ :field doit être une valeur JSON valide",
+		"regex":                      "Le format du champ :field n'est pas valide",
+		"date":                       "Le champ :field doit être une date au format :param",
+		"after":                      "Le champ :field doit être après :param",
+		"before":                     "Le champ :field doit être avant :param",
+		"prohibited":                 "Le champ :field n'est pas autorisé",
+		"prohibited_with":            "Le champ :field ne peut pas être défini en même temps que :param",
+		"required_with":              "Le champ :field est obligatoire lorsque :param est défini",
+		"different":                  "Le champ :field doit être différent de :param",
+		"phoneNumber":                "Le champ :field doit être un numéro de téléphone E.164 valide",
+		"multiplePhoneNumber":        "Le champ :field doit contenir des numéros de téléphone E.164 valides",
+		"contactPhoneNumber":         "Le champ :field doit contenir uniquement des chiffres et moins de 15 caractères",
+		"multipleContactPhoneNumber": "Le champ :field doit contenir des numéros d'au plus 15 chiffres",
+		"multipleIn":                 "Le champ :field contient une valeur non valide",
+		"multipleLanguage":           "Le champ :field doit contenir des codes de langue, par exemple en ou pt-br",
+		"language":                   "Le champ :field doit être un code de langue, par exemple en ou pt-br",
+		"webhookEvents":              "Le champ :field contient un événement non valide",
+		"exists":                     "La valeur du champ :field est introuvable dans votre compte",
+		"unavailable":                "Le champ :field n'a pas pu être validé, veuillez réessayer plus tard",
+		"size":                       "Le fichier :field doit être plus petit que :param octets",
+		"filename_max":               "Le nom du fichier :field doit contenir au maximum :param caractères",
+		"mime":                       "Le type du fichier :field n'est pas pris en charge",
+		"file":                       "Le fichier :field ne peut pas être lu",
+		"min_records":                "Le fichier :field doit contenir au moins :param enregistrement",
+		"max_records":                "Le fichier :field doit contenir moins de :param enregistrements",
+		"max_segments":               "Le champ :field nécessite plus de :param segments SMS",
+		"max_days":                   "La période :field ne peut pas dépasser :param jours",
+		"opted_out":                  "Le contact a refusé de recevoir des messages marketing",
+		"forbidden_category":         "Cette catégorie de message ne peut pas être envoyée dans le pays du contact",
+		"forbidden_hours":            "Les messages ne peuvent pas être envoyés au contact à cette heure",
+		"opt_out_footer":             "Le champ :field doit contenir un pied de page de désinscription",
+		"template":                   "Le modèle :field n'est pas valide",
+		"signature":                  "La signature n'est pas valide",
+		"captcha":                    "Le jeton captcha n'est pas valide",
+		"cloudevent":                 "L'événement n'est pas un CloudEvent valide",
+		"cancellable":                "Le message a déjà été récupéré par le téléphone et ne peut pas être annulé",
+		"utf8":                       "Le champ :field doit être un texte UTF-8 valide",
+		"required_if":                "Le champ :field est obligatoire avec cette configuration",
+		"required_without":           "Le champ :field est obligatoire lorsque les autres champs sont vides",
+		"lt":                         "Le champ :field doit être inférieur à :param",
+		"timezone":                   "Le champ :field doit être un fuseau horaire IANA valide, par exemple Europe/Paris",
+		"domain":                     "Le champ :field doit être un nom de domaine, par exemple sms.example.com",
+		"unique":                     "La valeur du champ :field est déjà utilisée",
+		"public":                     "Le champ :field doit être une adresse http ou https accessible sur internet",
+		"retention":                  "Le champ :field est en dehors de la période de conservation des événements",
+		"cursor":                     "Le champ :field doit être le next_cursor renvoyé par la réponse précédente",
+		"balance":                    "Le solde est introuvable dans le champ :field, définissez le montant à la place",
+		"active":                     "Le téléphone :field est désactivé, réactivez-le pour envoyer des messages",
+		"do_not_contact":             "Le contact ne souhaite pas recevoir cette catégorie de messages",
+		"forbidden_content":          "Le champ :field contient un contenu interdit dans le pays du contact",
+		"entitled":                   "Votre abonnement ne permet pas d'envoyer plus de messages",
+	},
+	Spanish: {
+		"required":                   "El campo :field es obligatorio",
+		"min":                        "El campo :field debe ser como mínimo :param",
+		"max":                        "El campo :field debe ser como máximo :param",
+		"numeric":                    "El campo :field debe ser un número",
+		"numeric_between":            "El campo :field debe ser un número entre :param",
+		"in":                         "El campo :field debe ser uno de los valores :param",
+		"uuid":                       "El campo :field debe ser un UUID válido",
+		"url":                        "El campo :field debe ser una URL válida",
+		"alpha_dash":                 "El campo :field solo puede contener letras, números, guiones y guiones bajos",
+		"json":                       "El campo :field debe ser un valor JSON válido",
+		"regex":                      "El formato del campo :field no es válido",
+		"date":                       "El campo :field debe ser una fecha con el formato :param",
+		"after":                      "El campo :field debe ser posterior a :param",
+		"before":                     "El campo :field debe ser anterior a :param",
+		"prohibited":                 "El campo :field no está permitido",
+		"prohibited_with":            "El campo :field no se puede definir al mismo tiempo que :param",
+		"required_with":              "El campo :field es obligatorio cuando :param está definido",
+		"different":                  "El campo :field debe ser diferente de :param",
+		"phoneNumber":                "El campo :field debe ser un número de teléfono E.164 válido",
+		"multiplePhoneNumber":        "El campo :field debe contener números de teléfono E.164 válidos",
+		"contactPhoneNumber":         "El campo :field debe contener solo dígitos y menos de 15 caracteres",
+		"multipleContactPhoneNumber": "El campo :field debe contener números de como máximo 15 dígitos",
+		"multipleIn":                 "El campo :field contiene un valor no válido",
+		"multipleLanguage":           "El campo :field debe contener códigos de idioma, por ejemplo en o pt-br",
+		"language":                   "El campo :field debe ser un código de idioma, por ejemplo en o pt-br",
+		"webhookEvents":              "El campo :field contiene un evento no válido",
+		"exists":                     "El valor del campo :field no se encuentra en su cuenta",
+		"unavailable":                "No se pudo validar el campo :field, inténtelo de nuevo más tarde",
+		"size":                       "El archivo :field debe ser menor de :param bytes",
+		"filename_max":               "El nombre del archivo :field debe tener como máximo :param caracteres",
+		"mime":                       "El tipo del archivo :field no es compatible",
+		"file":                       "No se puede leer el archivo :field",
+		"min_records":                "El archivo :field debe contener al menos :param registro",
+		"max_records":                "El archivo :field debe contener menos de :param registros",
+		"max_segments":               "El campo :field necesita más de :param segmentos SMS",
+		"max_days":                   "El período :field no puede superar los :param días",
+		"opted_out":                  "El contacto ha rechazado recibir mensajes de marketing",
+		"forbidden_category":         "Esta categoría de mensaje no se puede enviar al país del contacto",
+		"forbidden_hours":            "No se pueden enviar mensajes al contacto a esta hora",
+		"opt_out_footer":             "El campo :field debe contener un pie de página para darse de baja",
+		"template":                   "La plantilla :field no es válida",
+		"signature":                  "La firma no es válida",
+		"captcha":                    "El token captcha no es válido",
+		"cloudevent":                 "El evento no es un CloudEvent válido",
+		"cancellable":                "El mensaje ya fue recogido por el teléfono y no se puede cancelar",
+		"utf8":                       "El campo :field debe ser un texto UTF-8 válido",
+		"required_if":                "El campo :field es obligatorio con esta configuración",
+		"required_without":           "El campo :field es obligatorio cuando los otros campos están vacíos",
+		"lt":                         "El campo :field debe ser menor que :param",
+		"timezone":                   "El campo :field debe ser una zona horaria IANA válida, por ejemplo Europe/Madrid",
+		"domain":                     "El campo :field debe ser un nombre de dominio, por ejemplo sms.example.com",
+		"unique":                     "El valor del campo :field ya está en uso",
+		"public":                     "El campo :field debe ser una dirección http o https accesible en internet",
+		"retention":                  "El campo :field está fuera del período de conservación de los eventos",
+		"cursor":                     "El campo :field debe ser el next_cursor devuelto en la respuesta anterior",
+		"balance":                    "No se encuentra el saldo en el campo :field, defina el importe en su lugar",
+		"active":                     "El teléfono :field está desactivado, reactívelo para enviar mensajes",
+		"do_not_contact":             "El contacto no quiere recibir esta categoría de mensajes",
+		"forbidden_content":          "El campo :field contiene contenido prohibido en el país del contacto",
+		"entitled":                   "Su suscripción no permite enviar más mensajes",
+	},
+}
+
+// Translate a message to the Language. The message is returned as it is when it has no translation.
+func Translate(language Language, message string) string {
+	if translation, ok := messages[language][message]; ok {
+		return translation
+	}
+	return message
+}
+
+// TranslateValidationErrors translates the message of each responses.ValidationError to the Language.
+// Errors of a rule without a translation keep the English message.
+func TranslateValidationErrors(language Language, errors responses.ValidationErrors) responses.ValidationErrors {
+	if language == English || len(errors) == 0 {
+		return errors
+	}
+
+	result := make(responses.ValidationErrors, 0, len(errors))
+	for _, err := range errors {
+		if template, ok := rules[language][err.Rule]; ok {
+			err.Message = strings.NewReplacer(":field", err.Field, ":param", err.Param).Replace(template)
+		}
+		result = append(result, err)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// APIKeyListener handles cloud events which change the entities.APIKey of a user
+type APIKeyListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.APIKeyService
+}
+
+// NewAPIKeyListener creates a new instance of APIKeyListener
+func NewAPIKeyListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.APIKeyService,
+) (l *APIKeyListener, routes map[string]events.EventListener) {
+	l = &APIKeyListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.UserAccountDeleted: l.onUserAccountDeleted,
+	}
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *APIKeyListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		msg := fmt.Sprintf("cannot delete [entities.APIKey] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
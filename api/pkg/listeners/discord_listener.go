@@ -38,7 +38,7 @@ func NewDiscordListener(
 
 // OnMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
 func (listener *DiscordListener) OnMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
-	ctx, span := listener.tracer.Start(ctx)
+	ctx, span, ctxLogger := listener.tracer.StartWithLogger(ctx, listener.logger)
 	defer span.End()
 
 	var payload events.MessagePhoneReceivedPayload
@@ -47,6 +47,11 @@ func (listener *DiscordListener) OnMessagePhoneReceived(ctx context.Context, eve
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	if payload.Muted {
+		ctxLogger.Info(fmt.Sprintf("skipping [%s] event with ID [%s] because the thread with contact [%s] is muted", event.Type(), event.ID(), payload.Contact))
+		return nil
+	}
+
 	if err := listener.service.HandleMessageReceived(ctx, payload.UserID, event); err != nil {
 		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -34,6 +34,7 @@ func NewEmailNotificationListener(
 		events.EventTypeMessageSendExpired: l.OnMessageSendExpired,
 		events.EventTypeMessageSendFailed:  l.OnMessageSendFailed,
 		events.EventTypeWebhookSendFailed:  l.OnWebhookSendFailed,
+		events.EventTypeWebhookPaused:      l.onWebhookPaused,
 		events.EventTypeDiscordSendFailed:  l.OnDiscordSendFailed,
 	}
 }
@@ -95,6 +96,25 @@ func (listener *EmailNotificationListener) OnWebhookSendFailed(ctx context.Conte
 	return nil
 }
 
+// onWebhookPaused handles the events.EventTypeWebhookPaused event
+func (listener *EmailNotificationListener) onWebhookPaused(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.WebhookPausedPayload)
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.NotifyWebhookPaused(ctx, payload); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // OnDiscordSendFailed handles the events.EventTypeDiscordSendFailed event
 func (listener *EmailNotificationListener) OnDiscordSendFailed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
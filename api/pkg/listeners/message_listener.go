@@ -35,12 +35,16 @@ func NewMessageListener(
 		events.EventTypeMessagePhoneSending:          l.OnMessagePhoneSending,
 		events.EventTypeMessagePhoneSent:             l.OnMessagePhoneSent,
 		events.EventTypeMessagePhoneDelivered:        l.OnMessagePhoneDelivered,
+		events.EventTypeMessageDelivered:             l.onMessageDelivered,
+		events.EventTypeMessageDeliveryFailed:        l.onMessageDeliveryFailed,
 		events.EventTypeMessageSendFailed:            l.OnMessagePhoneFailed,
+		events.EventTypeMessageSendFailedTransient:   l.onMessageSendFailedTransient,
 		events.EventTypeMessageNotificationSent:      l.onMessageNotificationSent,
 		events.EventTypeMessageNotificationFailed:    l.onMessageNotificationFailed,
 		events.EventTypeMessageSendExpiredCheck:      l.onMessageSendExpiredCheck,
 		events.EventTypeMessageSendExpired:           l.onMessageSendExpired,
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
+		events.EventTypePhoneHeartbeatOffline:        l.onPhoneHeartbeatOffline,
 		events.MessageThreadAPIDeleted:               l.onMessageThreadAPIDeleted,
 		events.MessageCallMissed:                     l.onMessageCallMissed,
 		events.UserAccountDeleted:                    l.onUserAccountDeleted,
@@ -123,6 +127,57 @@ func (listener *MessageListener) OnMessagePhoneDelivered(ctx context.Context, ev
 	return nil
 }
 
+// onMessageDelivered handles the events.EventTypeMessageDelivered event
+func (listener *MessageListener) onMessageDelivered(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveredPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	handleParams := services.HandleMessageParams{
+		ID:        payload.ID,
+		UserID:    payload.UserID,
+		Timestamp: payload.Timestamp,
+	}
+
+	if err := listener.service.HandleMessageDelivered(ctx, handleParams); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] for message with ID [%s] for event with ID [%s]", event.Type(), handleParams.ID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageDeliveryFailed handles the events.EventTypeMessageDeliveryFailed event
+func (listener *MessageListener) onMessageDeliveryFailed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveryFailedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	handleParams := services.HandleMessageFailedParams{
+		ID:           payload.ID,
+		UserID:       payload.UserID,
+		ErrorMessage: payload.ErrorCode,
+		Timestamp:    payload.Timestamp,
+	}
+
+	if err := listener.service.HandleMessageDeliveryFailed(ctx, handleParams); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] for message with ID [%s] for event with ID [%s]", event.Type(), handleParams.ID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // OnMessagePhoneFailed handles the events.EventTypeMessageSendFailed event
 func (listener *MessageListener) OnMessagePhoneFailed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -149,6 +204,34 @@ func (listener *MessageListener) OnMessagePhoneFailed(ctx context.Context, event
 	return nil
 }
 
+// onMessageSendFailedTransient handles the events.EventTypeMessageSendFailedTransient event
+func (listener *MessageListener) onMessageSendFailedTransient(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendFailedTransientPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	handleParams := services.HandleMessageFailedTransientParams{
+		ID:           payload.ID,
+		Source:       event.Source(),
+		UserID:       payload.UserID,
+		ErrorMessage: payload.ErrorMessage,
+		Timestamp:    payload.Timestamp,
+		RetryAt:      payload.RetryAt,
+	}
+
+	if err := listener.service.HandleMessageFailedTransient(ctx, handleParams); err != nil {
+		msg := fmt.Sprintf("cannot handle [%s] for message with ID [%s] for event with ID [%s]", event.Type(), handleParams.ID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // onMessageNotificationFailed handles the events.EventTypeMessageNotificationFailed event
 func (listener *MessageListener) onMessageNotificationFailed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -293,6 +376,31 @@ func (listener *MessageListener) onMessageNotificationScheduled(ctx context.Cont
 	return nil
 }
 
+// onPhoneHeartbeatOffline handles the events.EventTypePhoneHeartbeatOffline event
+func (listener *MessageListener) onPhoneHeartbeatOffline(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneHeartbeatOfflinePayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	failoverParams := services.MessageFailoverParams{
+		Source:    event.Source(),
+		UserID:    payload.UserID,
+		Owner:     payload.Owner,
+		Timestamp: payload.Timestamp,
+	}
+	if _, err := listener.service.Failover(ctx, failoverParams); err != nil {
+		msg := fmt.Sprintf("cannot fail over the messages of phone [%s] on [%s] event with ID [%s]", payload.PhoneID, event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // onMessageThreadAPIDeleted handles the events.MessageThreadAPIDeleted event
 func (listener *MessageListener) onMessageThreadAPIDeleted(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -0,0 +1,55 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// MessageThreadClassificationListener classifies the topic and language of an entities.MessageThread when a message is received
+type MessageThreadClassificationListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MessageThreadClassificationService
+}
+
+// NewMessageThreadClassificationListener creates a new instance of MessageThreadClassificationListener
+func NewMessageThreadClassificationListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MessageThreadClassificationService,
+) (l *MessageThreadClassificationListener, routes map[string]events.EventListener) {
+	l = &MessageThreadClassificationListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeMessagePhoneReceived: l.onMessagePhoneReceived,
+	}
+}
+
+// onMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
+func (listener *MessageThreadClassificationListener) onMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessagePhoneReceivedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Classify(ctx, payload.UserID, payload.Owner, payload.Contact); err != nil {
+		msg := fmt.Sprintf("cannot classify thread for message [%s] on [%s] event with ID [%s]", payload.MessageID, event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
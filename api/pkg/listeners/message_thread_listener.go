@@ -15,9 +15,10 @@ import (
 
 // MessageThreadListener handles cloud events which need to update entities.MessageThread
 type MessageThreadListener struct {
-	logger  telemetry.Logger
-	tracer  telemetry.Tracer
-	service *services.MessageThreadService
+	logger           telemetry.Logger
+	tracer           telemetry.Tracer
+	service          *services.MessageThreadService
+	inboxRuleService *services.InboxRuleService
 }
 
 // NewMessageThreadListener creates a new instance of MessageThreadListener
@@ -25,11 +26,13 @@ func NewMessageThreadListener(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	service *services.MessageThreadService,
+	inboxRuleService *services.InboxRuleService,
 ) (l *MessageThreadListener, routes map[string]events.EventListener) {
 	l = &MessageThreadListener{
-		logger:  logger.WithService(fmt.Sprintf("%T", l)),
-		tracer:  tracer,
-		service: service,
+		logger:           logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:           tracer,
+		service:          service,
+		inboxRuleService: inboxRuleService,
 	}
 
 	return l, map[string]events.EventListener{
@@ -38,10 +41,13 @@ func NewMessageThreadListener(
 		events.EventTypeMessagePhoneSending:          l.OnMessagePhoneSending,
 		events.EventTypeMessagePhoneSent:             l.OnMessagePhoneSent,
 		events.EventTypeMessagePhoneDelivered:        l.OnMessagePhoneDelivered,
+		events.EventTypeMessageDelivered:             l.onMessageDelivered,
+		events.EventTypeMessageDeliveryFailed:        l.onMessageDeliveryFailed,
 		events.EventTypeMessageSendFailed:            l.OnMessagePhoneFailed,
 		events.EventTypeMessagePhoneReceived:         l.OnMessagePhoneReceived,
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
 		events.EventTypeMessageSendExpired:           l.onMessageExpired,
+		events.EventTypeMessageSendCancelled:         l.onMessageCancelled,
 		events.UserAccountDeleted:                    l.onUserAccountDeleted,
 	}
 }
@@ -65,6 +71,7 @@ func (listener *MessageThreadListener) OnMessageAPISent(ctx context.Context, eve
 		Timestamp: payload.RequestReceivedAt,
 		Content:   payload.Content,
 		MessageID: payload.MessageID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -113,6 +120,7 @@ func (listener *MessageThreadListener) OnMessagePhoneSending(ctx context.Context
 		Timestamp: payload.Timestamp,
 		Content:   payload.Content,
 		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -142,6 +150,7 @@ func (listener *MessageThreadListener) OnMessagePhoneSent(ctx context.Context, e
 		Timestamp: payload.Timestamp,
 		Content:   payload.Content,
 		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -171,6 +180,67 @@ func (listener *MessageThreadListener) OnMessagePhoneDelivered(ctx context.Conte
 		Timestamp: payload.Timestamp,
 		Content:   payload.Content,
 		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
+	}
+
+	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
+		msg := fmt.Sprintf("cannot update thread for message with ID [%s] for event with ID [%s]", updateParams.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageDelivered handles the events.EventTypeMessageDelivered event
+func (listener *MessageThreadListener) onMessageDelivered(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveredPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	updateParams := services.MessageThreadUpdateParams{
+		Owner:     payload.Owner,
+		UserID:    payload.UserID,
+		Contact:   payload.Contact,
+		Status:    entities.MessageStatusDelivered,
+		Timestamp: payload.Timestamp,
+		Content:   payload.Content,
+		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
+	}
+
+	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
+		msg := fmt.Sprintf("cannot update thread for message with ID [%s] for event with ID [%s]", updateParams.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageDeliveryFailed handles the events.EventTypeMessageDeliveryFailed event
+func (listener *MessageThreadListener) onMessageDeliveryFailed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveryFailedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	updateParams := services.MessageThreadUpdateParams{
+		Owner:     payload.Owner,
+		UserID:    payload.UserID,
+		Contact:   payload.Contact,
+		Status:    entities.MessageStatusFailed,
+		Timestamp: payload.Timestamp,
+		Content:   payload.Content,
+		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -200,6 +270,7 @@ func (listener *MessageThreadListener) OnMessagePhoneFailed(ctx context.Context,
 		Timestamp: payload.Timestamp,
 		Content:   payload.Content,
 		MessageID: payload.ID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -229,6 +300,7 @@ func (listener *MessageThreadListener) OnMessagePhoneReceived(ctx context.Contex
 		Status:    entities.MessageStatusReceived,
 		Content:   payload.Content,
 		MessageID: payload.MessageID,
+		Type:      entities.MessageTypeMobileOriginated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -236,6 +308,12 @@ func (listener *MessageThreadListener) OnMessagePhoneReceived(ctx context.Contex
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	// The inbox rules are applied here instead of in their own listener because the listeners of an event run concurrently
+	if err := listener.inboxRuleService.Apply(ctx, &payload); err != nil {
+		msg := fmt.Sprintf("cannot apply inbox rules for message with ID [%s] for event with ID [%s]", payload.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
 	return nil
 }
 
@@ -258,6 +336,7 @@ func (listener *MessageThreadListener) onMessageNotificationScheduled(ctx contex
 		Content:   payload.Content,
 		Status:    entities.MessageStatusScheduled,
 		MessageID: payload.MessageID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -287,6 +366,37 @@ func (listener *MessageThreadListener) onMessageExpired(ctx context.Context, eve
 		Content:   payload.Content,
 		Status:    entities.MessageStatusExpired,
 		MessageID: payload.MessageID,
+		Type:      entities.MessageTypeMobileTerminated,
+	}
+
+	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
+		msg := fmt.Sprintf("cannot update thread for message with ID [%s] for event with ID [%s]", updateParams.MessageID, event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageCancelled handles the events.EventTypeMessageSendCancelled event
+func (listener *MessageThreadListener) onMessageCancelled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendCancelledPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	updateParams := services.MessageThreadUpdateParams{
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		Timestamp: payload.Timestamp,
+		UserID:    payload.UserID,
+		Content:   payload.Content,
+		Status:    entities.MessageStatusCancelled,
+		MessageID: payload.MessageID,
+		Type:      entities.MessageTypeMobileTerminated,
 	}
 
 	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
@@ -0,0 +1,60 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// MQTTListener publishes received messages to the MQTT broker
+type MQTTListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MQTTService
+}
+
+// NewMQTTListener creates a new instance of MQTTListener
+func NewMQTTListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MQTTService,
+) (l *MQTTListener, routes map[string]events.EventListener) {
+	l = &MQTTListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeMessagePhoneReceived: l.OnMessagePhoneReceived,
+	}
+}
+
+// OnMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
+func (listener *MQTTListener) OnMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
+	ctx, span, ctxLogger := listener.tracer.StartWithLogger(ctx, listener.logger)
+	defer span.End()
+
+	var payload events.MessagePhoneReceivedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if payload.Muted {
+		ctxLogger.Info(fmt.Sprintf("skipping [%s] event with ID [%s] because the thread with contact [%s] is muted", event.Type(), event.ID(), payload.Contact))
+		return nil
+	}
+
+	if err := listener.service.PublishReceived(ctx, &payload); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
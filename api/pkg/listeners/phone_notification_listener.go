@@ -37,6 +37,8 @@ func NewNotificationListener(
 		events.EventTypeMessageSendRetry:        l.onMessageSendRetry,
 		events.EventTypeMessageNotificationSend: l.onMessageNotificationSend,
 		events.PhoneHeartbeatMissed:             l.onPhoneHeartbeatMissed,
+		events.EventTypePhoneResyncRequested:    l.onPhoneResyncRequested,
+		events.EventTypePhoneLogsRequested:      l.onPhoneLogsRequested,
 		events.UserAccountDeleted:               l.onUserAccountDeleted,
 	}
 }
@@ -60,6 +62,8 @@ func (listener *PhoneNotificationListener) onMessageAPISent(ctx context.Context,
 		SIM:       payload.SIM,
 		Encrypted: payload.Encrypted,
 		Source:    event.Source(),
+		Category:  payload.Category,
+		Priority:  payload.Priority,
 		MessageID: payload.MessageID,
 	}
 
@@ -90,6 +94,8 @@ func (listener *PhoneNotificationListener) onMessageSendRetry(ctx context.Contex
 		SIM:       payload.SIM,
 		Encrypted: payload.Encrypted,
 		Source:    event.Source(),
+		Category:  payload.Category,
+		Priority:  payload.Priority,
 		MessageID: payload.MessageID,
 	}
 
@@ -120,6 +126,44 @@ func (listener *PhoneNotificationListener) onPhoneHeartbeatMissed(ctx context.Co
 	return nil
 }
 
+// onPhoneResyncRequested handles the events.EventTypePhoneResyncRequested event
+func (listener *PhoneNotificationListener) onPhoneResyncRequested(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.PhoneResyncRequestedPayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.SendResyncFCM(ctx, payload); err != nil {
+		msg := fmt.Sprintf("cannot send resync FCM with params [%s] for event with ID [%s]", spew.Sdump(payload), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onPhoneLogsRequested handles the events.EventTypePhoneLogsRequested event
+func (listener *PhoneNotificationListener) onPhoneLogsRequested(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.PhoneLogsRequestedPayload)
+	if err := event.DataAs(payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.SendLogsRequestFCM(ctx, payload); err != nil {
+		msg := fmt.Sprintf("cannot send logs request FCM with params [%s] for event with ID [%s]", spew.Sdump(payload), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // onMessageNotificationSend handles the events.EventTypeMessageNotificationSend event
 func (listener *PhoneNotificationListener) onMessageNotificationSend(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
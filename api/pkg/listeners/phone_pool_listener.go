@@ -0,0 +1,73 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// PhonePoolListener handles cloud events which need to update entities.PhonePool
+type PhonePoolListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.PhonePoolService
+}
+
+// NewPhonePoolListener creates a new instance of PhonePoolListener
+func NewPhonePoolListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhonePoolService,
+) (l *PhonePoolListener, routes map[string]events.EventListener) {
+	l = &PhonePoolListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.UserAccountDeleted:    l.onUserAccountDeleted,
+		events.EventTypePhoneDeleted: l.onPhoneDeleted,
+	}
+}
+
+func (listener *PhonePoolListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		msg := fmt.Sprintf("cannot delete [entities.PhonePool] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (listener *PhonePoolListener) onPhoneDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.RemovePhoneNumber(ctx, payload.UserID, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot remove phone [%s] from the [entities.PhonePool] of user [%s] on [%s] event with ID [%s]", payload.Owner, payload.UserID, event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -31,21 +31,29 @@ func NewWebhookListener(
 	}
 
 	return l, map[string]events.EventListener{
-		events.EventTypeMessagePhoneReceived:  l.OnMessagePhoneReceived,
-		events.EventTypeMessageSendExpired:    l.OnMessageSendExpired,
-		events.EventTypeMessagePhoneDelivered: l.OnMessagePhoneDelivered,
-		events.EventTypeMessageSendFailed:     l.OnMessageSendFailed,
-		events.EventTypeMessagePhoneSent:      l.OnMessagePhoneSent,
-		events.EventTypePhoneHeartbeatOnline:  l.onPhoneHeartbeatOnline,
-		events.EventTypePhoneHeartbeatOffline: l.onPhoneHeartbeatOffline,
-		events.MessageCallMissed:              l.onMessageCallMissed,
-		events.UserAccountDeleted:             l.onUserAccountDeleted,
+		events.EventTypeMessagePhoneReceived:       l.OnMessagePhoneReceived,
+		events.EventTypeMessageSendExpired:         l.OnMessageSendExpired,
+		events.EventTypeMessageSendCancelled:       l.onMessageSendCancelled,
+		events.EventTypeMessagePhoneDelivered:      l.OnMessagePhoneDelivered,
+		events.EventTypeMessageDelivered:           l.onMessageDelivered,
+		events.EventTypeMessageDeliveryFailed:      l.onMessageDeliveryFailed,
+		events.EventTypeMessageSendFailed:          l.OnMessageSendFailed,
+		events.EventTypeMessageSendFailedTransient: l.onMessageSendFailedTransient,
+		events.EventTypeMessagePhoneSent:           l.OnMessagePhoneSent,
+		events.EventTypePhoneHeartbeatOnline:       l.onPhoneHeartbeatOnline,
+		events.EventTypePhoneHeartbeatOffline:      l.onPhoneHeartbeatOffline,
+		events.EventTypePhoneBalanceLow:            l.onPhoneBalanceLow,
+		events.EventTypePhoneSIMSwapped:            l.onPhoneSIMSwapped,
+		events.MessageCallMissed:                   l.onMessageCallMissed,
+		events.EventTypeReportGenerated:            l.onReportGenerated,
+		events.EventTypeCustomEventPublished:       l.onCustomEventPublished,
+		events.UserAccountDeleted:                  l.onUserAccountDeleted,
 	}
 }
 
 // OnMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
 func (listener *WebhookListener) OnMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
-	ctx, span := listener.tracer.Start(ctx)
+	ctx, span, ctxLogger := listener.tracer.StartWithLogger(ctx, listener.logger)
 	defer span.End()
 
 	var payload events.MessagePhoneReceivedPayload
@@ -54,6 +62,11 @@ func (listener *WebhookListener) OnMessagePhoneReceived(ctx context.Context, eve
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	if payload.Muted {
+		ctxLogger.Info(fmt.Sprintf("skipping [%s] event with ID [%s] because the thread with contact [%s] is muted", event.Type(), event.ID(), payload.Contact))
+		return nil
+	}
+
 	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
 		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -81,6 +94,63 @@ func (listener *WebhookListener) OnMessageSendExpired(ctx context.Context, event
 	return nil
 }
 
+// onMessageSendCancelled handles the events.EventTypeMessageSendCancelled event
+func (listener *WebhookListener) onMessageSendCancelled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendCancelledPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageDelivered handles the events.EventTypeMessageDelivered event
+func (listener *WebhookListener) onMessageDelivered(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveredPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onMessageDeliveryFailed handles the events.EventTypeMessageDeliveryFailed event
+func (listener *WebhookListener) onMessageDeliveryFailed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageDeliveryFailedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // OnMessageSendFailed handles the events.EventTypeMessageSendFailed event
 func (listener *WebhookListener) OnMessageSendFailed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -100,6 +170,25 @@ func (listener *WebhookListener) OnMessageSendFailed(ctx context.Context, event
 	return nil
 }
 
+// onMessageSendFailedTransient handles the events.EventTypeMessageSendFailedTransient event
+func (listener *WebhookListener) onMessageSendFailedTransient(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendFailedTransientPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // OnMessagePhoneSent handles the events.EventTypeMessagePhoneSent event
 func (listener *WebhookListener) OnMessagePhoneSent(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -157,6 +246,42 @@ func (listener *WebhookListener) onPhoneHeartbeatOffline(ctx context.Context, ev
 	return nil
 }
 
+func (listener *WebhookListener) onPhoneBalanceLow(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneBalanceLowPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (listener *WebhookListener) onPhoneSIMSwapped(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneSIMSwappedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.Owner); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // OnMessagePhoneDelivered handles the events.EventTypeMessagePhoneDelivered event
 func (listener *WebhookListener) onPhoneHeartbeatOnline(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -195,6 +320,45 @@ func (listener *WebhookListener) onMessageCallMissed(ctx context.Context, event
 	return nil
 }
 
+// onReportGenerated handles the events.EventTypeReportGenerated event
+func (listener *WebhookListener) onReportGenerated(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ReportGeneratedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// reports cover all the phones of the user so the webhooks are not filtered by phone number
+	if err := listener.service.Send(ctx, payload.UserID, event, ""); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// onCustomEventPublished handles the events.EventTypeCustomEventPublished event
+func (listener *WebhookListener) onCustomEventPublished(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.CustomEventPublishedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.SendCustomEvent(ctx, &payload); err != nil {
+		msg := fmt.Sprintf("cannot process [%s] event with ID [%s]", event.Type(), event.ID())
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 func (listener *WebhookListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
 	defer span.End()
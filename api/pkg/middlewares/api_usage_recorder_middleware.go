@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIUsageRecorder counts the requests, errors and latency of every endpoint which is called with an API key
+func APIUsageRecorder(counter *services.APIUsageCounter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthUser)
+		if !ok || authUser.IsNoop() {
+			return c.Next()
+		}
+
+		apiKey := getAPIKeyFromRequest(c)
+		if apiKey == "" {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		// the status code is set by the error handler when the handler returns an error
+		statusCode := c.Response().StatusCode()
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			statusCode = fiberErr.Code
+		} else if err != nil {
+			statusCode = fiber.StatusInternalServerError
+		}
+
+		// the route path e.g. /v1/messages/:messageID is used so that every message does not have its own endpoint
+		counter.RecordRequest(c.UserContext(), authUser.ID, maskAPIKey(apiKey), c.Method(), c.Route().Path, statusCode, time.Since(start))
+		return err
+	}
+}
+
+// maskAPIKey hides all except the last 4 characters of an API key
+func maskAPIKey(apiKey string) string {
+	if len(apiKey) <= 4 {
+		return "****"
+	}
+	return "****" + apiKey[len(apiKey)-4:]
+}
@@ -1,7 +1,11 @@
 package middlewares
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/i18n"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,20 +21,68 @@ const (
 	ContextKeyAuthUserID = "auth.user.id"
 )
 
-// Authenticated checks if the request is authenticated
-func Authenticated(tracer telemetry.Tracer) fiber.Handler {
+// Authenticated checks if the request is authenticated and that the API key of the request has the scope of the route.
+// The routes which are not in the scoped routes need an API key with the entities.APIKeyScopeAdmin scope.
+func Authenticated(tracer telemetry.Tracer, routes []entities.APIKeyRoute) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		_, span := tracer.StartFromFiberCtx(c, "middlewares.Authenticated")
 		defer span.End()
 
-		if tokenUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthUser); !ok || tokenUser.IsNoop() {
+		tokenUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthUser)
+		if !ok || tokenUser.IsNoop() {
+			language := i18n.FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+			c.Set(fiber.HeaderContentLanguage, language.String())
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "error",
-				"message": "You are not authorized to carry out this request.",
-				"data":    "Make sure your API key is set in the [x-api-key] header in the request",
+				"message": i18n.Translate(language, "You are not authorized to carry out this request."),
+				"data":    i18n.Translate(language, "Make sure your API key is set in the [X-API-Key] header in the request"),
+			})
+		}
+
+		if scope := apiKeyRouteScope(routes, c.Method(), c.Path()); !tokenUser.HasScope(scope) {
+			span.AddEvent(fmt.Sprintf("the api key of user [%s] with scopes [%v] does not have the [%s] scope", tokenUser.ID, tokenUser.Scopes, scope))
+			language := i18n.FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+			c.Set(fiber.HeaderContentLanguage, language.String())
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "error",
+				"message": i18n.Translate(language, "Your API key does not have the scope to carry out this request."),
+				"data":    fmt.Sprintf("The request needs an API key with the [%s] scope", scope),
 			})
 		}
 
 		return c.Next()
 	}
 }
+
+// apiKeyRouteScope returns the scope of the route which matches the method and path of a request
+func apiKeyRouteScope(routes []entities.APIKeyRoute, method string, path string) entities.APIKeyScope {
+	if method == fiber.MethodHead {
+		method = fiber.MethodGet
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for _, route := range routes {
+		if route.Method == method && matchRoutePath(strings.Split(route.Path, "/"), segments) {
+			return route.Scope
+		}
+	}
+
+	return entities.APIKeyScopeAdmin
+}
+
+// matchRoutePath checks if the segments of a path match the segments of a route where the parameters e.g. ":phoneID" match any segment
+func matchRoutePath(route []string, path []string) bool {
+	if len(route) != len(path) {
+		return false
+	}
+
+	for index, segment := range route {
+		if strings.HasPrefix(segment, ":") && path[index] != "" {
+			continue
+		}
+		if segment != path[index] {
+			return false
+		}
+	}
+	return true
+}
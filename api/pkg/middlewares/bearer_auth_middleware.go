@@ -1,22 +1,25 @@
 package middlewares
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
 	"firebase.google.com/go/auth"
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/gofiber/fiber/v2"
 	"github.com/palantir/stacktrace"
 )
 
-// BearerAuth authenticates a user based on the bearer token
-func BearerAuth(logger telemetry.Logger, tracer telemetry.Tracer, authClient *auth.Client) fiber.Handler {
+// BearerAuth authenticates a user with the Firebase ID token in the bearer token so that the web app and the mobile app
+// can use the identity of the user instead of an API key. The Firebase UID is resolved to the local entities.User and a
+// user who is not stored yet is authenticated with the email of the token, which is empty for a phone number sign in, so
+// that the account can be created on the first sign in. When checkRevoked is true, the tokens of users whose sessions were revoked in Firebase are also rejected.
+func BearerAuth(logger telemetry.Logger, tracer telemetry.Tracer, authClient *auth.Client, userRepository repositories.UserRepository, checkRevoked bool) fiber.Handler {
 	logger = logger.WithService("middlewares.BearerAuth")
 	return func(c *fiber.Ctx) error {
-		_, span := tracer.StartFromFiberCtx(c, "middlewares.BearerAuth")
+		ctx, span := tracer.StartFromFiberCtx(c, "middlewares.BearerAuth")
 		defer span.End()
 
 		authToken := c.Get(authHeaderBearer)
@@ -31,18 +34,29 @@ func BearerAuth(logger telemetry.Logger, tracer telemetry.Tracer, authClient *au
 
 		ctxLogger := tracer.CtxLogger(logger, span)
 
-		token, err := authClient.VerifyIDToken(context.Background(), authToken)
+		verify := authClient.VerifyIDToken
+		if checkRevoked {
+			verify = authClient.VerifyIDTokenAndCheckRevoked
+		}
+
+		token, err := verify(ctx, authToken)
 		if err != nil {
-			msg := fmt.Sprintf("invalid firebase id token [%s]", authToken)
-			ctxLogger.Warn(tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+			ctxLogger.Warn(tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "invalid firebase id token")))
 			return c.Next()
 		}
 
 		span.AddEvent(fmt.Sprintf("[%s] token is valid", bearerScheme))
 
-		authUser := entities.AuthUser{
-			Email: token.Claims["email"].(string),
-			ID:    entities.UserID(token.Claims["user_id"].(string)),
+		authUser, err := userRepository.LoadAuthUserByID(ctx, entities.UserID(token.UID))
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			// the email is not in the tokens of users who sign in with a phone number so their account is created without an email
+			email, _ := token.Claims["email"].(string)
+			authUser, err = entities.AuthUser{ID: entities.UserID(token.UID), Email: email}, nil
+		}
+
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load user with firebase UID [%s]", token.UID)))
+			return c.Next()
 		}
 
 		c.Locals(ContextKeyAuthUserID, authUser)
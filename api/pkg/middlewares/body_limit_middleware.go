@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/i18n"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/dustin/go-humanize"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// BodyLimit rejects a request with a 413 response when its body is larger than the limit in bytes
+func BodyLimit(logger telemetry.Logger, tracer telemetry.Tracer, limit int) fiber.Handler {
+	logger = logger.WithService("middlewares.BodyLimit")
+	return func(c *fiber.Ctx) error {
+		_, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.BodyLimit")
+		defer span.End()
+
+		size := len(c.Request().Body())
+		if size <= limit {
+			return c.Next()
+		}
+
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("the body of the request [%s %s] has [%d] bytes which is more than the limit of [%d] bytes", c.Method(), c.Path(), size, limit)))
+
+		language := i18n.FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+		c.Set(fiber.HeaderContentLanguage, language.String())
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"status":  "error",
+			"message": i18n.Translate(language, "The request body is too large"),
+			"data":    fmt.Sprintf("the request body must be less than %s", humanize.IBytes(uint64(limit))),
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// DebugRequestRecorder records the requests which are authenticated with an API key while the debug mode of the user is enabled
+func DebugRequestRecorder(logger telemetry.Logger, tracer telemetry.Tracer, service *services.DebugRequestService) fiber.Handler {
+	logger = logger.WithService("middlewares.DebugRequestRecorder")
+
+	return func(c *fiber.Ctx) error {
+		authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthUser)
+		if !ok || authUser.IsNoop() || strings.HasPrefix(c.Path(), "/v1/debug") || getAPIKeyFromRequest(c) == "" {
+			return c.Next()
+		}
+
+		ctx, span := tracer.StartFromFiberCtx(c, "middlewares.DebugRequestRecorder")
+		if !service.IsEnabled(ctx, authUser.ID) {
+			span.End()
+			return c.Next()
+		}
+
+		start := time.Now().UTC()
+		err := c.Next()
+
+		// the request and response are copied because fiber reuses the buffers after the handler returns
+		_, recordErr := service.Record(ctx, &services.DebugRequestRecordParams{
+			UserID:             authUser.ID,
+			Method:             c.Method(),
+			URL:                c.OriginalURL(),
+			RequestHeaders:     c.GetReqHeaders(),
+			RequestBody:        append([]byte{}, c.Request().Body()...),
+			ResponseStatusCode: c.Response().StatusCode(),
+			ResponseBody:       append([]byte{}, c.Response().Body()...),
+			Duration:           time.Since(start),
+			Timestamp:          start,
+		})
+		if recordErr != nil {
+			tracer.CtxLogger(logger, span).Error(stacktrace.Propagate(recordErr, fmt.Sprintf("cannot record [%s] request to [%s] for user [%s]", c.Method(), c.Path(), authUser.ID)))
+		}
+
+		span.End()
+		return err
+	}
+}
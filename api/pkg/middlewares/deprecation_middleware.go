@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecation attaches the Deprecation and Sunset headers to the responses of the deprecated routes and counts the
+// requests to the deprecated routes per API key
+func Deprecation(counter *services.DeprecationCounter, routes []entities.DeprecatedRoute) fiber.Handler {
+	deprecated := make(map[string]entities.DeprecatedRoute, len(routes))
+	for _, route := range routes {
+		deprecated[route.Key()] = route
+	}
+
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		// the route is only known after it has been matched by the router
+		route, ok := deprecated[c.Method()+" "+c.Route().Path]
+		if !ok {
+			return err
+		}
+
+		c.Set("Deprecation", fmt.Sprintf("@%d", route.DeprecatedAt.Unix()))
+		if route.SunsetAt != nil {
+			c.Set("Sunset", route.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if route.Link != nil {
+			c.Append(fiber.HeaderLink, fmt.Sprintf("<%s>; rel=\"deprecation\"", *route.Link))
+		}
+
+		if authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthUser); ok && !authUser.IsNoop() {
+			apiKey := getAPIKeyFromRequest(c)
+			if apiKey != "" {
+				apiKey = maskAPIKey(apiKey)
+			}
+			counter.RecordRequest(c.UserContext(), route, authUser.ID, apiKey)
+		}
+
+		return err
+	}
+}
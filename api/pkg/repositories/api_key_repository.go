@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository loads and persists an entities.APIKey
+type APIKeyRepository interface {
+	// Store a new entities.APIKey
+	Store(ctx context.Context, key *entities.APIKey) error
+
+	// Load an entities.APIKey of a user by ID
+	Load(ctx context.Context, userID entities.UserID, keyID uuid.UUID) (*entities.APIKey, error)
+
+	// Index fetches the entities.APIKey of a user
+	Index(ctx context.Context, userID entities.UserID) ([]entities.APIKey, error)
+
+	// Delete an entities.APIKey of a user
+	Delete(ctx context.Context, userID entities.UserID, keyID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.APIKey for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// AttachmentRepository loads and persists an entities.Attachment
+type AttachmentRepository interface {
+	// Store a new entities.Attachment
+	Store(ctx context.Context, attachment *entities.Attachment) error
+
+	// Index entities.Attachment by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Attachment, error)
+
+	// Load loads an attachment by ID.
+	Load(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) (*entities.Attachment, error)
+
+	// Delete an entities.Attachment
+	Delete(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) error
+}
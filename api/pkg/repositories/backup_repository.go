@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// BackupRepository exports and imports database rows for backups
+type BackupRepository interface {
+	// Export streams the rows of all the tables to the callback with the name of their database which is empty for the main
+	// database and is the data region for the regional shards. If userID is not nil, only the rows of that user are exported.
+	Export(ctx context.Context, userID *entities.UserID, callback func(database string, table string, row any) error) error
+
+	// Import upserts a row which was exported from a table of a database
+	Import(ctx context.Context, database string, table string, row json.RawMessage) error
+
+	// Tables returns the names of the tables which are backed up in the order in which they are exported. The tables of the
+	// regional shards are prefixed with the data region e.g. "eu/messages".
+	Tables() []string
+}
@@ -21,6 +21,9 @@ type BillingUsageRepository interface {
 	// GetHistory returns past billing usage by entities.UserID
 	GetHistory(ctx context.Context, userID entities.UserID, params IndexParams) (*[]entities.BillingUsage, error)
 
+	// FetchForPeriod fetches the entities.BillingUsage of every user for the billing period which contains the timestamp
+	FetchForPeriod(ctx context.Context, timestamp time.Time) ([]*entities.BillingUsage, error)
+
 	// DeleteForUser deletes all billing usage for an entities.UserID
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
 }
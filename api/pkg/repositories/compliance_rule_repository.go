@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ComplianceRuleRepository loads and persists an entities.ComplianceRule
+type ComplianceRuleRepository interface {
+	// Save an entities.ComplianceRule
+	Save(ctx context.Context, rule *entities.ComplianceRule) error
+
+	// Index all the entities.ComplianceRule ordered by country code
+	Index(ctx context.Context) ([]entities.ComplianceRule, error)
+
+	// LoadByCountry loads the entities.ComplianceRule of a country
+	LoadByCountry(ctx context.Context, countryCode string) (*entities.ComplianceRule, error)
+
+	// Load an entities.ComplianceRule by ID
+	Load(ctx context.Context, ruleID uuid.UUID) (*entities.ComplianceRule, error)
+
+	// Delete an entities.ComplianceRule
+	Delete(ctx context.Context, ruleID uuid.UUID) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// ContactPreferenceRepository loads and persists an entities.ContactPreference
+type ContactPreferenceRepository interface {
+	// Save Upsert an entities.ContactPreference
+	Save(ctx context.Context, preference *entities.ContactPreference) error
+
+	// Load the entities.ContactPreference of a contact
+	Load(ctx context.Context, userID entities.UserID, contact string) (*entities.ContactPreference, error)
+
+	// Delete the entities.ContactPreference of a contact
+	Delete(ctx context.Context, userID entities.UserID, contact string) error
+
+	// DeleteAllForUser deletes all entities.ContactPreference for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
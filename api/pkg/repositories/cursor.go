@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// Cursor is the position of the last row of a page which is ordered by (order_timestamp, id).
+// It is used instead of an offset so that the rows which are added while paging are not skipped or duplicated.
+type Cursor struct {
+	IsPinned       bool      `json:"p,omitempty"`
+	OrderTimestamp time.Time `json:"t"`
+	ID             uuid.UUID `json:"i"`
+}
+
+// NewCursor creates a Cursor after a row with an order timestamp and an ID
+func NewCursor(orderTimestamp time.Time, ID uuid.UUID) *Cursor {
+	return &Cursor{OrderTimestamp: orderTimestamp, ID: ID}
+}
+
+// Encode the cursor into an opaque string which can be sent to the client
+func (cursor *Cursor) Encode() string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor decodes a cursor which was created with Cursor.Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot decode cursor from base64")
+	}
+
+	cursor := new(Cursor)
+	if err = json.Unmarshal(payload, cursor); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot unmarshal cursor from JSON")
+	}
+
+	if cursor.ID == uuid.Nil || cursor.OrderTimestamp.IsZero() {
+		return nil, stacktrace.NewError("cursor does not have an ID and an order timestamp")
+	}
+
+	return cursor, nil
+}
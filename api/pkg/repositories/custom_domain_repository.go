@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// CustomDomainRepository loads and persists an entities.CustomDomain
+type CustomDomainRepository interface {
+	// Save Upsert an entities.CustomDomain
+	Save(ctx context.Context, domain *entities.CustomDomain) error
+
+	// Load the entities.CustomDomain of a user
+	Load(ctx context.Context, userID entities.UserID) (*entities.CustomDomain, error)
+
+	// LoadVerifiedByDomain fetches the verified entities.CustomDomain with a domain name of any user
+	LoadVerifiedByDomain(ctx context.Context, domain string) (*entities.CustomDomain, error)
+
+	// FetchUnverified fetches the entities.CustomDomain of all users which are not verified and were not checked after checkedBefore
+	FetchUnverified(ctx context.Context, checkedBefore time.Time, limit int) ([]*entities.CustomDomain, error)
+
+	// FetchVerified fetches the entities.CustomDomain of all users which are verified and were not checked after checkedBefore
+	FetchVerified(ctx context.Context, checkedBefore time.Time, limit int) ([]*entities.CustomDomain, error)
+
+	// DeleteUnverifiedByDomain deletes the unverified entities.CustomDomain with a domain name of all users except userID
+	DeleteUnverifiedByDomain(ctx context.Context, domain string, userID entities.UserID) (int64, error)
+
+	// Delete the entities.CustomDomain of a user
+	Delete(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// CustomEventRepository loads and persists an entities.CustomEvent
+type CustomEventRepository interface {
+	// Store a new entities.CustomEvent
+	Store(ctx context.Context, event *entities.CustomEvent) error
+
+	// Index entities.CustomEvent by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.CustomEvent, error)
+
+	// DeleteAllForUser deletes all entities.CustomEvent for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// DataRegionRouter resolves the region where the message data of an entities.User is stored
+type DataRegionRouter interface {
+	// Region returns the data region of an entities.User or an empty string if the user is stored in the default region
+	Region(ctx context.Context, userID entities.UserID) (string, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// DebugRequestRepository loads and persists an entities.DebugRequest
+type DebugRequestRepository interface {
+	// Store a new entities.DebugRequest
+	Store(ctx context.Context, request *entities.DebugRequest) error
+
+	// Index entities.DebugRequest by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.DebugRequest, error)
+
+	// DeleteAllForUser deletes all entities.DebugRequest for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
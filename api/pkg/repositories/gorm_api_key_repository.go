@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormAPIKeyRepository is responsible for persisting entities.APIKey
+type gormAPIKeyRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormAPIKeyRepository creates the GORM version of the APIKeyRepository
+func NewGormAPIKeyRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) APIKeyRepository {
+	return &gormAPIKeyRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormAPIKeyRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.APIKey
+func (repository *gormAPIKeyRepository) Store(ctx context.Context, key *entities.APIKey) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(key).Error; err != nil {
+		msg := fmt.Sprintf("cannot save api key with ID [%s]", key.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Load an entities.APIKey of a user by ID
+func (repository *gormAPIKeyRepository) Load(ctx context.Context, userID entities.UserID, keyID uuid.UUID) (*entities.APIKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	key := new(entities.APIKey)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", keyID).
+		First(key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("api key with ID [%s] does not exist for user [%s]", keyID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load api key with ID [%s] for user [%s]", keyID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return key, nil
+}
+
+// Index fetches the entities.APIKey of a user
+func (repository *gormAPIKeyRepository) Index(ctx context.Context, userID entities.UserID) ([]entities.APIKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	keys := make([]entities.APIKey, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch api keys for user [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return keys, nil
+}
+
+// Delete an entities.APIKey of a user
+func (repository *gormAPIKeyRepository) Delete(ctx context.Context, userID entities.UserID, keyID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", keyID).
+		Delete(&entities.APIKey{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete api key with ID [%s] for user [%s]", keyID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.APIKey for a user
+func (repository *gormAPIKeyRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.APIKey{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.APIKey{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
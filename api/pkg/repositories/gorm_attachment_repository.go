@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormAttachmentRepository is responsible for persisting entities.Attachment
+type gormAttachmentRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormAttachmentRepository creates the GORM version of the AttachmentRepository
+func NewGormAttachmentRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) AttachmentRepository {
+	return &gormAttachmentRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormAttachmentRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormAttachmentRepository) Store(ctx context.Context, attachment *entities.Attachment) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		msg := fmt.Sprintf("cannot save attachment with ID [%s]", attachment.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormAttachmentRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Attachment, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("content_type ILIKE ?", queryPattern))
+	}
+
+	attachments := make([]*entities.Attachment, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&attachments).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch attachments for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attachments, nil
+}
+
+func (repository *gormAttachmentRepository) Load(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) (*entities.Attachment, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	attachment := new(entities.Attachment)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", attachmentID).First(&attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("attachment with ID [%s] for user [%s] does not exist", attachmentID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment with ID [%s] for user [%s]", attachmentID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attachment, nil
+}
+
+func (repository *gormAttachmentRepository) Delete(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", attachmentID).
+		Delete(&entities.Attachment{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete attachment with ID [%s] and userID [%s]", attachmentID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const backupBatchSize = 500
+
+// GormBackupDatabase is a database which is included in a backup with the models which are migrated in it
+type GormBackupDatabase struct {
+	// Name identifies the database in the archive. It is empty for the main database and it is the data region for the
+	// regional shards.
+	Name   string
+	DB     *gorm.DB
+	Models []any
+}
+
+// gormBackupTable is a table which is included in a backup
+type gormBackupTable struct {
+	database    string
+	db          *gorm.DB
+	name        string
+	scopeColumn string
+	model       reflect.Type
+}
+
+// gormBackupRepository is responsible for exporting and importing backups with GORM
+type gormBackupRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	tables []gormBackupTable
+}
+
+// NewGormBackupRepository creates the GORM version of the BackupRepository which backs up the tables of all the models
+// which are migrated in the databases
+func NewGormBackupRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	databases []GormBackupDatabase,
+) (BackupRepository, error) {
+	var tables []gormBackupTable
+	for _, database := range databases {
+		for _, model := range database.Models {
+			table, err := newGormBackupTable(database, model)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot back up model [%T] in database [%s]", model, database.Name))
+			}
+			tables = append(tables, table)
+		}
+	}
+
+	return &gormBackupRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormBackupRepository{})),
+		tracer: tracer,
+		tables: tables,
+	}, nil
+}
+
+// newGormBackupTable creates a gormBackupTable from the schema of a model. The rows of a user are selected with the
+// user_id column, the users table is selected with its primary key and the other tables are only in full backups.
+func newGormBackupTable(database GormBackupDatabase, model any) (gormBackupTable, error) {
+	statement := &gorm.Statement{DB: database.DB}
+	if err := statement.Parse(model); err != nil {
+		return gormBackupTable{}, stacktrace.Propagate(err, fmt.Sprintf("cannot parse schema of [%T]", model))
+	}
+
+	scopeColumn := ""
+	if _, ok := model.(*entities.User); ok {
+		scopeColumn = "id"
+	} else if statement.Schema.LookUpField(tenantScopeColumn) != nil {
+		scopeColumn = tenantScopeColumn
+	}
+
+	return gormBackupTable{
+		database:    database.Name,
+		db:          database.DB,
+		name:        statement.Schema.Table,
+		scopeColumn: scopeColumn,
+		model:       statement.Schema.ModelType,
+	}, nil
+}
+
+func (table gormBackupTable) qualifiedName() string {
+	if table.database == "" {
+		return table.name
+	}
+	return table.database + "/" + table.name
+}
+
+func (table gormBackupTable) export(db *gorm.DB, callback func(database string, table string, row any) error) error {
+	rows := reflect.New(reflect.SliceOf(table.model))
+	return db.Model(reflect.New(table.model).Interface()).FindInBatches(rows.Interface(), backupBatchSize, func(_ *gorm.DB, _ int) error {
+		for index := 0; index < rows.Elem().Len(); index++ {
+			if err := callback(table.database, table.name, rows.Elem().Index(index).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (table gormBackupTable) restore(db *gorm.DB, data json.RawMessage) error {
+	row := reflect.New(table.model).Interface()
+	if err := json.Unmarshal(data, row); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal row into [%T]", row))
+	}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error
+}
+
+func (repository *gormBackupRepository) Tables() []string {
+	names := make([]string, 0, len(repository.tables))
+	for _, table := range repository.tables {
+		names = append(names, table.qualifiedName())
+	}
+	return names
+}
+
+func (repository *gormBackupRepository) Export(ctx context.Context, userID *entities.UserID, callback func(database string, table string, row any) error) error {
+	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
+	defer span.End()
+
+	for _, table := range repository.tables {
+		query := skipTenantScope(table.db).WithContext(ctx)
+		if userID != nil {
+			if table.scopeColumn == "" {
+				continue
+			}
+			query = query.Where(fmt.Sprintf("%s = ?", table.scopeColumn), *userID)
+		}
+
+		if err := table.export(query, callback); err != nil {
+			msg := fmt.Sprintf("cannot export rows from table [%s]", table.qualifiedName())
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		ctxLogger.Info(fmt.Sprintf("exported rows from table [%s]", table.qualifiedName()))
+	}
+
+	return nil
+}
+
+func (repository *gormBackupRepository) Import(ctx context.Context, database string, table string, row json.RawMessage) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	for _, item := range repository.tables {
+		if item.database != database || item.name != table {
+			continue
+		}
+
+		if err := item.restore(item.db.WithContext(ctx), row); err != nil {
+			msg := fmt.Sprintf("cannot import row into table [%s]", item.qualifiedName())
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return nil
+	}
+
+	msg := fmt.Sprintf("table [%s] of database [%s] is not included in backups", table, database)
+	return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+}
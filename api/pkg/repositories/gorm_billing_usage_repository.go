@@ -144,6 +144,28 @@ func (repository *gormBillingUsageRepository) GetHistory(ctx context.Context, us
 	return usages, err
 }
 
+// FetchForPeriod fetches the entities.BillingUsage of every user for the billing period which contains the timestamp
+func (repository *gormBillingUsageRepository) FetchForPeriod(ctx context.Context, timestamp time.Time) ([]*entities.BillingUsage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	usages := make([]*entities.BillingUsage, 0)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("start_timestamp = ?", now.New(timestamp).BeginningOfMonth()).
+		Order("user_id ASC").
+		Find(&usages).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the billing usage of every user for the period of [%s]", timestamp)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return usages, nil
+}
+
 func (repository *gormBillingUsageRepository) createBillingUsage(userID entities.UserID, timestamp time.Time, sent uint, received uint) *entities.BillingUsage {
 	return &entities.BillingUsage{
 		ID:               uuid.New(),
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormComplianceRuleRepository is responsible for persisting entities.ComplianceRule
+type gormComplianceRuleRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormComplianceRuleRepository creates the GORM version of the ComplianceRuleRepository
+func NewGormComplianceRuleRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ComplianceRuleRepository {
+	return &gormComplianceRuleRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormComplianceRuleRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Save an entities.ComplianceRule
+func (repository *gormComplianceRuleRepository) Save(ctx context.Context, rule *entities.ComplianceRule) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(rule).Error; err != nil {
+		msg := fmt.Sprintf("cannot save [%T] with ID [%s]", rule, rule.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Index all the entities.ComplianceRule ordered by country code
+func (repository *gormComplianceRuleRepository) Index(ctx context.Context) ([]entities.ComplianceRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var rules []entities.ComplianceRule
+	if err := repository.db.WithContext(ctx).Order("country_code ASC").Find(&rules).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch all [%T]", &entities.ComplianceRule{})
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rules, nil
+}
+
+// LoadByCountry loads the entities.ComplianceRule of a country
+func (repository *gormComplianceRuleRepository) LoadByCountry(ctx context.Context, countryCode string) (*entities.ComplianceRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rule := new(entities.ComplianceRule)
+	err := repository.db.WithContext(ctx).Where("country_code = ?", countryCode).First(rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("compliance rule for country [%s] does not exist", countryCode)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load compliance rule for country [%s]", countryCode)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rule, nil
+}
+
+// Load an entities.ComplianceRule by ID
+func (repository *gormComplianceRuleRepository) Load(ctx context.Context, ruleID uuid.UUID) (*entities.ComplianceRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rule := new(entities.ComplianceRule)
+	err := repository.db.WithContext(ctx).Where("id = ?", ruleID).First(rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("compliance rule with ID [%s] does not exist", ruleID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load compliance rule with ID [%s]", ruleID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rule, nil
+}
+
+// Delete an entities.ComplianceRule
+func (repository *gormComplianceRuleRepository) Delete(ctx context.Context, ruleID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("id = ?", ruleID).Delete(&entities.ComplianceRule{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete compliance rule with ID [%s]", ruleID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
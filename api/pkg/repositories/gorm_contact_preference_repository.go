@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormContactPreferenceRepository is responsible for persisting entities.ContactPreference
+type gormContactPreferenceRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormContactPreferenceRepository creates the GORM version of the ContactPreferenceRepository
+func NewGormContactPreferenceRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ContactPreferenceRepository {
+	return &gormContactPreferenceRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormContactPreferenceRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Save Upsert an entities.ContactPreference
+func (repository *gormContactPreferenceRepository) Save(ctx context.Context, preference *entities.ContactPreference) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", preference.UserID).Save(preference).Error; err != nil {
+		msg := fmt.Sprintf("cannot save contact preference with ID [%s]", preference.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Load the entities.ContactPreference of a contact
+func (repository *gormContactPreferenceRepository) Load(ctx context.Context, userID entities.UserID, contact string) (*entities.ContactPreference, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	preference := new(entities.ContactPreference)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("contact = ?", contact).
+		First(preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("contact preference with contact [%s] does not exist for user [%s]", contact, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load contact preference with contact [%s] for user [%s]", contact, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return preference, nil
+}
+
+// Delete the entities.ContactPreference of a contact
+func (repository *gormContactPreferenceRepository) Delete(ctx context.Context, userID entities.UserID, contact string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("contact = ?", contact).
+		Delete(&entities.ContactPreference{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete contact preference of contact [%s] for user [%s]", contact, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ContactPreference for a user
+func (repository *gormContactPreferenceRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.ContactPreference{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.ContactPreference{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
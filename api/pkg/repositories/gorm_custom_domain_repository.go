@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormCustomDomainRepository is responsible for persisting entities.CustomDomain
+type gormCustomDomainRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormCustomDomainRepository creates the GORM version of the CustomDomainRepository
+func NewGormCustomDomainRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) CustomDomainRepository {
+	return &gormCustomDomainRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormCustomDomainRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Save Upsert an entities.CustomDomain
+func (repository *gormCustomDomainRepository) Save(ctx context.Context, domain *entities.CustomDomain) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", domain.UserID).Save(domain).Error; err != nil {
+		msg := fmt.Sprintf("cannot save custom domain [%s] with ID [%s]", domain.Domain, domain.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Load the entities.CustomDomain of a user
+func (repository *gormCustomDomainRepository) Load(ctx context.Context, userID entities.UserID) (*entities.CustomDomain, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	domain := new(entities.CustomDomain)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).First(domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("custom domain does not exist for user [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domain, nil
+}
+
+// LoadVerifiedByDomain fetches the verified entities.CustomDomain with a domain name of any user e.g. when a TLS certificate is requested for the domain
+func (repository *gormCustomDomainRepository) LoadVerifiedByDomain(ctx context.Context, name string) (*entities.CustomDomain, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	domain := new(entities.CustomDomain)
+	err := skipTenantScope(repository.db).WithContext(ctx).Where("domain = ?", name).Where("verified_at IS NOT NULL").First(domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("verified custom domain [%s] does not exist", name)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain [%s]", name)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domain, nil
+}
+
+// FetchUnverified fetches the entities.CustomDomain of all users which are not verified and were not checked after checkedBefore
+func (repository *gormCustomDomainRepository) FetchUnverified(ctx context.Context, checkedBefore time.Time, limit int) ([]*entities.CustomDomain, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	domains := make([]*entities.CustomDomain, 0, limit)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("verified_at IS NULL").
+		Where(repository.db.Where("checked_at IS NULL").Or("checked_at < ?", checkedBefore)).
+		Order("checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&domains).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch unverified custom domains which were checked before [%s]", checkedBefore)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domains, nil
+}
+
+// FetchVerified fetches the entities.CustomDomain of all users which are verified and were not checked after checkedBefore
+func (repository *gormCustomDomainRepository) FetchVerified(ctx context.Context, checkedBefore time.Time, limit int) ([]*entities.CustomDomain, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	domains := make([]*entities.CustomDomain, 0, limit)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("verified_at IS NOT NULL").
+		Where(repository.db.Where("checked_at IS NULL").Or("checked_at < ?", checkedBefore)).
+		Order("checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&domains).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch verified custom domains which were checked before [%s]", checkedBefore)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domains, nil
+}
+
+// DeleteUnverifiedByDomain deletes the unverified entities.CustomDomain with a domain name of all users except userID
+func (repository *gormCustomDomainRepository) DeleteUnverifiedByDomain(ctx context.Context, name string, userID entities.UserID) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result := skipTenantScope(repository.db).WithContext(ctx).
+		Where("domain = ?", name).
+		Where("user_id <> ?", userID).
+		Where("verified_at IS NULL").
+		Delete(&entities.CustomDomain{})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot delete the unverified custom domains [%s] of users other than [%s]", name, userID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Delete the entities.CustomDomain of a user
+func (repository *gormCustomDomainRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.CustomDomain{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete custom domain for user [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
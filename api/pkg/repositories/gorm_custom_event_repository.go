@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormCustomEventRepository is responsible for persisting entities.CustomEvent
+type gormCustomEventRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormCustomEventRepository creates the GORM version of the CustomEventRepository
+func NewGormCustomEventRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) CustomEventRepository {
+	return &gormCustomEventRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormCustomEventRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormCustomEventRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.CustomEvent{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.CustomEvent{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormCustomEventRepository) Store(ctx context.Context, event *entities.CustomEvent) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(event).Error; err != nil {
+		msg := fmt.Sprintf("cannot save custom event with ID [%s]", event.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormCustomEventRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.CustomEvent, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("type ILIKE ?", queryPattern).Or("source ILIKE ?", queryPattern))
+	}
+
+	customEvents := make([]*entities.CustomEvent, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&customEvents).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch custom events for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return customEvents, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/dgraph-io/ristretto"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormDataRegionRouter resolves the data region of an entities.User from the users table
+type gormDataRegionRouter struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+	cache  *ristretto.Cache[string, string]
+}
+
+// NewGormDataRegionRouter creates the GORM version of the DataRegionRouter
+func NewGormDataRegionRouter(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+	cache *ristretto.Cache[string, string],
+) DataRegionRouter {
+	return &gormDataRegionRouter{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormDataRegionRouter{})),
+		tracer: tracer,
+		db:     db,
+		cache:  cache,
+	}
+}
+
+func (router *gormDataRegionRouter) Region(ctx context.Context, userID entities.UserID) (string, error) {
+	ctx, span, ctxLogger := router.tracer.StartWithLogger(ctx, router.logger)
+	defer span.End()
+
+	if region, found := router.cache.Get(userID.String()); found {
+		return region, nil
+	}
+
+	user := new(entities.User)
+	err := router.db.WithContext(ctx).Select("id", "data_region").Where("id = ?", userID).First(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return "", router.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load data region for user with ID [%s]", userID)
+		return "", router.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if result := router.cache.SetWithTTL(userID.String(), user.StorageRegion(), 1, 15*time.Minute); !result {
+		msg := fmt.Sprintf("cannot cache data region [%s] for user with ID [%s]", user.StorageRegion(), userID)
+		ctxLogger.Error(router.tracer.WrapErrorSpan(span, stacktrace.NewError(msg)))
+	}
+
+	return user.StorageRegion(), nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormDebugRequestRepository is responsible for persisting entities.DebugRequest
+type gormDebugRequestRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormDebugRequestRepository creates the GORM version of the DebugRequestRepository
+func NewGormDebugRequestRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) DebugRequestRepository {
+	return &gormDebugRequestRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormDebugRequestRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormDebugRequestRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.DebugRequest{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.DebugRequest{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormDebugRequestRepository) Store(ctx context.Context, request *entities.DebugRequest) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(request).Error; err != nil {
+		msg := fmt.Sprintf("cannot save debug request with ID [%s]", request.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormDebugRequestRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.DebugRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("url ILIKE ?", queryPattern).Or("method ILIKE ?", queryPattern))
+	}
+
+	requests := make([]*entities.DebugRequest, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&requests).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch debug requests for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
@@ -48,7 +48,7 @@ func (repository *gormDiscordRepository) Save(ctx context.Context, Discord *enti
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(Discord).Error; err != nil {
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", Discord.UserID).Save(Discord).Error; err != nil {
 		msg := fmt.Sprintf("cannot update discord integration with ID [%s]", Discord.ID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -119,7 +119,7 @@ func (repository *gormDiscordRepository) FindByServerID(ctx context.Context, ser
 	defer span.End()
 
 	discord := new(entities.Discord)
-	err := repository.db.WithContext(ctx).Where("server_id = ?", serverID).First(&discord).Error
+	err := skipTenantScope(repository.db).WithContext(ctx).Where("server_id = ?", serverID).First(&discord).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		msg := fmt.Sprintf("discord integration with server ID [%s] does not exist", serverID)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
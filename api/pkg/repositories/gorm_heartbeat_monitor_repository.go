@@ -42,14 +42,19 @@ func (repository *gormHeartbeatMonitorRepository) UpdatePhoneOnline(ctx context.
 	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
 	defer cancel()
 
+	updates := map[string]any{
+		"phone_online": isOnline,
+		"updated_at":   time.Now().UTC(),
+	}
+	if !isOnline {
+		updates["last_offline_at"] = time.Now().UTC()
+	}
+
 	err := repository.db.
 		Model(&entities.HeartbeatMonitor{}).
 		Where("id = ?", monitorID).
 		Where("user_id = ?", userID).
-		Updates(map[string]any{
-			"phone_online": isOnline,
-			"updated_at":   time.Now().UTC(),
-		}).Error
+		Updates(updates).Error
 	if err != nil {
 		msg := fmt.Sprintf("cannot update heartbeat monitor ID [%s] for user [%s]", monitorID, userID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -65,7 +70,7 @@ func (repository *gormHeartbeatMonitorRepository) UpdateQueueID(ctx context.Cont
 	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
 	defer cancel()
 
-	err := repository.db.
+	err := skipTenantScope(repository.db).
 		Model(&entities.HeartbeatMonitor{}).
 		Where("id = ?", monitorID).
 		Updates(map[string]any{
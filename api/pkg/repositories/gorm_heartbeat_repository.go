@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -70,6 +71,131 @@ func (repository *gormHeartbeatRepository) Last(ctx context.Context, userID enti
 	return heartbeat, nil
 }
 
+// Timeseries counts the entities.Heartbeat of a user in time buckets
+func (repository *gormHeartbeatRepository) Timeseries(ctx context.Context, userID entities.UserID, params TimeseriesParams) ([]*entities.TimeseriesBucket, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	query := repository.db.WithContext(ctx).Model(&entities.Heartbeat{}).Where("user_id = ?", userID)
+	buckets, err := gormTimeseries(query, "timestamp", params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count [%T] in time buckets for user [%s] and params [%+#v]", &entities.Heartbeat{}, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return buckets, nil
+}
+
+// SignalTimeseries aggregates the signal strength and network type of the entities.Heartbeat of a user in time buckets
+func (repository *gormHeartbeatRepository) SignalTimeseries(ctx context.Context, userID entities.UserID, params TimeseriesParams) ([]*entities.HeartbeatSignalBucket, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	seconds := int64(params.Interval / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	query := repository.db.WithContext(ctx).
+		Model(&entities.Heartbeat{}).
+		Select(
+			"owner, (FLOOR(EXTRACT(EPOCH FROM timestamp) / ?) * ?)::INT8 AS bucket, "+
+				"AVG(signal_strength) AS average_signal_strength, MIN(signal_strength) AS min_signal_strength, "+
+				"AVG(signal_bars) AS average_signal_bars, MODE() WITHIN GROUP (ORDER BY network_type) AS network_type, COUNT(*) AS count",
+			seconds, seconds,
+		).
+		Where("user_id = ?", userID).
+		Where("timestamp >= ?", params.From).
+		Where("timestamp < ?", params.To)
+	if len(params.Owners) > 0 {
+		query = query.Where("owner IN ?", params.Owners)
+	}
+
+	var rows []*gormHeartbeatSignalRow
+	if err := query.Group("owner, bucket").Order("bucket ASC").Scan(&rows).Error; err != nil {
+		msg := fmt.Sprintf("cannot aggregate the signal of [%T] in time buckets for user [%s] and params [%+#v]", &entities.Heartbeat{}, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	buckets := make([]*entities.HeartbeatSignalBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, &entities.HeartbeatSignalBucket{
+			Owner:                 row.Owner,
+			Timestamp:             time.Unix(row.Bucket, 0).UTC(),
+			AverageSignalStrength: row.AverageSignalStrength,
+			MinSignalStrength:     row.MinSignalStrength,
+			AverageSignalBars:     row.AverageSignalBars,
+			NetworkType:           row.NetworkType,
+			Count:                 row.Count,
+		})
+	}
+
+	return buckets, nil
+}
+
+type gormHeartbeatSignalRow struct {
+	Owner                 string
+	Bucket                int64
+	AverageSignalStrength *float64
+	MinSignalStrength     *int
+	AverageSignalBars     *float64
+	NetworkType           *entities.HeartbeatNetworkType
+	Count                 int64
+}
+
+// LastForEveryOwner returns the last entities.Heartbeat of every phone which sent a heartbeat since a timestamp
+// Timestamps returns the timestamps of the entities.Heartbeat of an owner between from and to ordered from the oldest to the newest
+func (repository *gormHeartbeatRepository) Timestamps(ctx context.Context, userID entities.UserID, owner string, from time.Time, to time.Time) ([]time.Time, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	timestamps := make([]time.Time, 0)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Heartbeat{}).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("timestamp BETWEEN ? AND ?", from, to).
+		Order("timestamp ASC").
+		Pluck("timestamp", &timestamps).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch heartbeat timestamps of owner [%s] for user [%s] between [%s] and [%s]", owner, userID, from, to)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return timestamps, nil
+}
+
+func (repository *gormHeartbeatRepository) LastForEveryOwner(ctx context.Context, since time.Time) ([]*entities.Heartbeat, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var heartbeats []*entities.Heartbeat
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.Heartbeat{}).
+		Select("user_id, owner, MAX(timestamp) AS timestamp").
+		Where("timestamp > ?", since).
+		Group("user_id, owner").
+		Scan(&heartbeats).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch last heartbeat of every owner since [%s]", since)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return heartbeats, nil
+}
+
 // Index entities.Message between 2 parties
 func (repository *gormHeartbeatRepository) Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) (*[]entities.Heartbeat, error) {
 	ctx, span := repository.tracer.Start(ctx)
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbgorm"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormHeartbeatRollupUpsert aggregates the heartbeats in a window into time buckets of a resolution.
+// The buckets of a resolution which already exist are merged since a daily bucket is filled by many windows.
+const gormHeartbeatRollupUpsert = `
+INSERT INTO heartbeat_rollups (id, user_id, owner, resolution, timestamp, count, charging_count, first_timestamp, last_timestamp, min_signal_strength, created_at, updated_at)
+SELECT gen_random_uuid(), user_id, owner, ?, to_timestamp(FLOOR(EXTRACT(EPOCH FROM timestamp) / ?) * ?) AS bucket,
+	COUNT(*), COUNT(*) FILTER (WHERE charging), MIN(timestamp), MAX(timestamp), MIN(signal_strength), ?, ?
+FROM heartbeats
+WHERE timestamp >= ? AND timestamp < ?
+GROUP BY user_id, owner, bucket
+ON CONFLICT (user_id, owner, resolution, timestamp) DO UPDATE SET
+	count = heartbeat_rollups.count + EXCLUDED.count,
+	charging_count = heartbeat_rollups.charging_count + EXCLUDED.charging_count,
+	first_timestamp = LEAST(heartbeat_rollups.first_timestamp, EXCLUDED.first_timestamp),
+	last_timestamp = GREATEST(heartbeat_rollups.last_timestamp, EXCLUDED.last_timestamp),
+	min_signal_strength = LEAST(COALESCE(heartbeat_rollups.min_signal_strength, EXCLUDED.min_signal_strength), COALESCE(EXCLUDED.min_signal_strength, heartbeat_rollups.min_signal_strength)),
+	updated_at = EXCLUDED.updated_at`
+
+// gormHeartbeatRollupRepository is responsible for persisting entities.HeartbeatRollup
+type gormHeartbeatRollupRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormHeartbeatRollupRepository creates the GORM version of the HeartbeatRollupRepository
+func NewGormHeartbeatRollupRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) HeartbeatRollupRepository {
+	return &gormHeartbeatRollupRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormHeartbeatRollupRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// DeleteAllForUser deletes all entities.HeartbeatRollup for a user
+func (repository *gormHeartbeatRollupRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.HeartbeatRollup{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.HeartbeatRollup{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Index fetches the entities.HeartbeatRollup of an owner with a resolution ordered from the newest to the oldest
+func (repository *gormHeartbeatRollupRepository) Index(ctx context.Context, userID entities.UserID, owner string, resolution entities.HeartbeatRollupResolution, params IndexParams) ([]*entities.HeartbeatRollup, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	rollups := make([]*entities.HeartbeatRollup, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("resolution = ?", resolution).
+		Order("timestamp DESC").
+		Limit(params.Limit).
+		Offset(params.Skip).
+		Find(&rollups).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch [%s] heartbeat rollups for user [%s] and owner [%s] with params [%+#v]", resolution, userID, owner, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rollups, nil
+}
+
+// RollUp aggregates the entities.Heartbeat of every user in the oldest window which starts before the timestamp into hourly and daily
+// entities.HeartbeatRollup and deletes the aggregated heartbeats
+func (repository *gormHeartbeatRollupRepository) RollUp(ctx context.Context, timestamp time.Time, window time.Duration) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var oldest *time.Time
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.Heartbeat{}).
+		Select("MIN(timestamp)").
+		Where("timestamp < ?", timestamp).
+		Scan(&oldest).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the oldest heartbeat before [%s]", timestamp)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if oldest == nil {
+		return 0, nil
+	}
+
+	// the window starts at the beginning of an hour so every hourly bucket is aggregated in a single window
+	from := oldest.UTC().Truncate(time.Hour)
+	to := from.Add(window)
+	if to.After(timestamp) {
+		to = timestamp
+	}
+
+	var count int64
+	err = crdbgorm.ExecuteTx(ctx, repository.db, nil, func(tx *gorm.DB) error {
+		for resolution, seconds := range map[entities.HeartbeatRollupResolution]int64{
+			entities.HeartbeatRollupResolutionHour: int64(time.Hour / time.Second),
+			entities.HeartbeatRollupResolutionDay:  int64(24 * time.Hour / time.Second),
+		} {
+			updatedAt := time.Now().UTC()
+			if err := tx.WithContext(ctx).Exec(gormHeartbeatRollupUpsert, resolution, seconds, seconds, updatedAt, updatedAt, from, to).Error; err != nil {
+				return stacktrace.Propagate(err, fmt.Sprintf("cannot aggregate the heartbeats from [%s] to [%s] into [%s] rollups", from, to, resolution))
+			}
+		}
+
+		result := skipTenantScope(tx).WithContext(ctx).
+			Where("timestamp >= ?", from).
+			Where("timestamp < ?", to).
+			Delete(&entities.Heartbeat{})
+		count = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot roll up the heartbeats from [%s] to [%s]", from, to)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormInboxRuleRepository is responsible for persisting entities.InboxRule
+type gormInboxRuleRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormInboxRuleRepository creates the GORM version of the InboxRuleRepository
+func NewGormInboxRuleRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) InboxRuleRepository {
+	return &gormInboxRuleRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormInboxRuleRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormInboxRuleRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.InboxRule{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.InboxRule{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormInboxRuleRepository) Save(ctx context.Context, rule *entities.InboxRule) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", rule.UserID).Save(rule).Error; err != nil {
+		msg := fmt.Sprintf("cannot update inbox rule with ID [%s]", rule.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormInboxRuleRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.InboxRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("sender ILIKE ?", queryPattern).Or("content ILIKE ?", queryPattern))
+	}
+
+	rules := make([]*entities.InboxRule, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&rules).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch inbox rules for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rules, nil
+}
+
+func (repository *gormInboxRuleRepository) LoadAll(ctx context.Context, userID entities.UserID) ([]*entities.InboxRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rules := make([]*entities.InboxRule, 0)
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&rules).Error; err != nil {
+		msg := fmt.Sprintf("cannot load inbox rules for user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rules, nil
+}
+
+func (repository *gormInboxRuleRepository) Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.InboxRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rule := new(entities.InboxRule)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", ruleID).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("inbox rule with ID [%s] for user [%s] does not exist", ruleID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load inbox rule with ID [%s] for user [%s]", ruleID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return rule, nil
+}
+
+func (repository *gormInboxRuleRepository) Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", ruleID).
+		Delete(&entities.InboxRule{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete inbox rule with ID [%s] and userID [%s]", ruleID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
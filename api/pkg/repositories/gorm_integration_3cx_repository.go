@@ -68,7 +68,7 @@ func (repository *gormIntegration3CxRepository) Save(ctx context.Context, integr
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(integration).Error; err != nil {
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", integration.UserID).Save(integration).Error; err != nil {
 		msg := fmt.Sprintf("cannot save [%T] with ID [%s]", integration, integration.ID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
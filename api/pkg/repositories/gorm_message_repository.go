@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm/clause"
 
@@ -15,6 +16,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// messageExpirableStatuses are the statuses of an outgoing entities.Message which has not been sent by the phone
+var messageExpirableStatuses = []entities.MessageStatus{
+	entities.MessageStatusPending,
+	entities.MessageStatusScheduled,
+	entities.MessageStatusDeferred,
+	entities.MessageStatusSending,
+}
+
 // gormMessageRepository is responsible for persisting entities.Message
 type gormMessageRepository struct {
 	logger telemetry.Logger
@@ -81,7 +90,7 @@ func (repository *gormMessageRepository) Delete(ctx context.Context, userID enti
 }
 
 // Index entities.Message between 2 parties
-func (repository *gormMessageRepository) Index(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error) {
+func (repository *gormMessageRepository) Index(ctx context.Context, userID entities.UserID, owner string, contact string, isStarred *bool, needsFollowUp *bool, params IndexParams) (*[]entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
@@ -89,14 +98,29 @@ func (repository *gormMessageRepository) Index(ctx context.Context, userID entit
 		WithContext(ctx).
 		Where("user_id = ?", userID).
 		Where("owner = ?", owner).
-		Where("contact =  ?", contact)
+		Where("contact =  ?", contact).
+		Where("duplicate_of_id IS NULL")
 	if len(params.Query) > 0 {
 		queryPattern := "%" + params.Query + "%"
 		query.Where("content ILIKE ?", queryPattern)
 	}
 
+	if isStarred != nil {
+		query.Where("is_starred = ?", *isStarred)
+	}
+
+	if needsFollowUp != nil {
+		query.Where("needs_follow_up = ?", *needsFollowUp)
+	}
+
+	if params.Cursor != nil {
+		query.Where("(order_timestamp, id) < (?, ?)", params.Cursor.OrderTimestamp, params.Cursor.ID)
+	} else {
+		query.Offset(params.Skip)
+	}
+
 	messages := new([]entities.Message)
-	if err := query.Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&messages).Error; err != nil {
+	if err := query.Order("order_timestamp DESC").Order("id DESC").Limit(params.Limit).Find(&messages).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch messges with owner [%s] and contact [%s] and params [%+#v]", owner, contact, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -104,6 +128,57 @@ func (repository *gormMessageRepository) Index(ctx context.Context, userID entit
 	return messages, nil
 }
 
+func (repository *gormMessageRepository) Timeline(ctx context.Context, userID entities.UserID, contact string, params IndexParams) (*[]entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("contact = ?", contact).
+		Where("duplicate_of_id IS NULL")
+
+	if params.Cursor != nil {
+		query.Where("(order_timestamp, id) < (?, ?)", params.Cursor.OrderTimestamp, params.Cursor.ID)
+	} else {
+		query.Offset(params.Skip)
+	}
+
+	messages := new([]entities.Message)
+	if err := query.Order("order_timestamp DESC").Order("id DESC").Limit(params.Limit).Find(&messages).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch timeline of contact [%s] with params [%+#v]", contact, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+func (repository *gormMessageRepository) History(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact = ?", contact).
+		Where("duplicate_of_id IS NULL")
+
+	if params.Cursor != nil {
+		query.Where("(order_timestamp, id) > (?, ?)", params.Cursor.OrderTimestamp, params.Cursor.ID)
+	} else {
+		query.Offset(params.Skip)
+	}
+
+	messages := new([]entities.Message)
+	if err := query.Order("order_timestamp ASC").Order("id ASC").Limit(params.Limit).Find(&messages).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch message history with owner [%s] and contact [%s] and params [%+#v]", owner, contact, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
 func (repository *gormMessageRepository) LastMessage(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -112,7 +187,8 @@ func (repository *gormMessageRepository) LastMessage(ctx context.Context, userID
 		WithContext(ctx).
 		Where("user_id = ?", userID).
 		Where("owner = ?", owner).
-		Where("contact =  ?", contact)
+		Where("contact =  ?", contact).
+		Where("duplicate_of_id IS NULL")
 
 	message := new(entities.Message)
 
@@ -130,13 +206,14 @@ func (repository *gormMessageRepository) LastMessage(ctx context.Context, userID
 	return message, nil
 }
 
-func (repository *gormMessageRepository) Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, params IndexParams) ([]*entities.Message, error) {
+func (repository *gormMessageRepository) Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, senderNames []string, params IndexParams) ([]*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
 	query := repository.db.
 		WithContext(ctx).
-		Where("user_id = ?", userID)
+		Where("user_id = ?", userID).
+		Where("duplicate_of_id IS NULL")
 
 	if len(owners) > 0 {
 		query = query.Where("owner IN ?", owners)
@@ -147,6 +224,9 @@ func (repository *gormMessageRepository) Search(ctx context.Context, userID enti
 	if len(statuses) > 0 {
 		query = query.Where("status IN ?", statuses)
 	}
+	if len(senderNames) > 0 {
+		query = query.Where("sender_name IN ?", senderNames)
+	}
 
 	if len(params.Query) > 0 {
 		queryPattern := "%" + params.Query + "%"
@@ -214,7 +294,7 @@ func (repository *gormMessageRepository) Update(ctx context.Context, message *en
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(message).Error; err != nil {
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", message.UserID).Save(message).Error; err != nil {
 		msg := fmt.Sprintf("cannot update message with ID [%s]", message.ID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -222,7 +302,8 @@ func (repository *gormMessageRepository) Update(ctx context.Context, message *en
 	return nil
 }
 
-// GetOutstanding fetches messages that still to be sent to the phone
+// GetOutstanding fetches messages that still to be sent to the phone and moves them to the sending status. Every push
+// notification, poll and WebSocket command only carries the ID of the message so a cancelled message is never sent.
 func (repository *gormMessageRepository) GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -235,6 +316,7 @@ func (repository *gormMessageRepository) GetOutstanding(ctx context.Context, use
 				Where("user_id = ?", userID).
 				Where("id = ?", messageID).
 				Where(repository.db.Where("status = ?", entities.MessageStatusScheduled).Or("status = ?", entities.MessageStatusPending).Or("status = ?", entities.MessageStatusExpired)).
+				Where(repository.db.Where("expires_at IS NULL").Or("expires_at > ?", time.Now().UTC())).
 				Update("status", entities.MessageStatusSending).Error
 		},
 	)
@@ -256,6 +338,501 @@ func (repository *gormMessageRepository) GetOutstanding(ctx context.Context, use
 	return message, nil
 }
 
+// Timeseries counts the entities.Message of a user in time buckets
+func (repository *gormMessageRepository) Timeseries(ctx context.Context, userID entities.UserID, types []entities.MessageType, statuses []entities.MessageStatus, params TimeseriesParams) ([]*entities.TimeseriesBucket, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	query := repository.db.WithContext(ctx).Model(&entities.Message{}).Where("user_id = ?", userID).Where("duplicate_of_id IS NULL")
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if len(params.SenderNames) > 0 {
+		query = query.Where("sender_name IN ?", params.SenderNames)
+	}
+	if len(params.Categories) > 0 {
+		query = query.Where("category IN ?", params.Categories)
+	}
+
+	buckets, err := gormTimeseries(query, "created_at", params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count [%T] in time buckets for user [%s] and params [%+#v]", &entities.Message{}, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return buckets, nil
+}
+
+// FetchReceivedSince fetches the entities.Message which were received from a contact after the timestamp and are not duplicates ordered from the newest to the oldest
+func (repository *gormMessageRepository) FetchReceivedSince(ctx context.Context, userID entities.UserID, owner string, contact string, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	messages := make([]*entities.Message, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact = ?", contact).
+		Where("type = ?", entities.MessageTypeMobileOriginated).
+		Where("duplicate_of_id IS NULL").
+		Where("received_at >= ?", timestamp).
+		Order("received_at DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch [%T] received by [%s] from [%s] since [%s] for user [%s]", &entities.Message{}, owner, contact, timestamp, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+// CountFailures counts the outgoing entities.Message of a user which failed between from and to for every phone and failure reason
+func (repository *gormMessageRepository) CountFailures(ctx context.Context, userID entities.UserID, from time.Time, to time.Time) ([]*entities.MessageFailureCount, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	counts := make([]*entities.MessageFailureCount, 0)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Select("owner, COALESCE(failure_reason, '') AS failure_reason, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status = ?", entities.MessageStatusFailed).
+		Where("failed_at >= ?", from).
+		Where("failed_at < ?", to).
+		Group("owner, failure_reason").
+		Order("count DESC").
+		Scan(&counts).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count failed [%T] for user [%s] between [%s] and [%s]", &entities.Message{}, userID, from, to)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counts, nil
+}
+
+// Queue fetches the outgoing entities.Message of a phone which are pending, scheduled or sending in the order they are dispatched to the phone.
+// The messages which are being sent by the phone come first, followed by the pending messages ordered by priority before the
+// time their notification was scheduled like the send slots of the phone.
+// The scheduled messages which are held until their send time are not in the queue yet.
+func (repository *gormMessageRepository) Queue(ctx context.Context, userID entities.UserID, owner string, limit int) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	messages := make([]*entities.Message, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusSending}).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL: "CASE status WHEN ? THEN 0 ELSE 1 END ASC, CASE priority WHEN ? THEN ? WHEN ? THEN ? ELSE ? END ASC, COALESCE(notification_scheduled_at, request_received_at) ASC",
+			Vars: []any{
+				entities.MessageStatusSending,
+				entities.MessagePriorityHigh, entities.MessagePriorityHigh.Rank(),
+				entities.MessagePriorityLow, entities.MessagePriorityLow.Rank(),
+				entities.MessagePriorityNormal.Rank(),
+			},
+		}}).
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the queued [%T] of phone [%s] for user [%s]", &entities.Message{}, owner, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+// QueueStats counts the outgoing entities.Message of a phone in each state of its queue, the failed and expired messages are counted since the timestamp
+func (repository *gormMessageRepository) QueueStats(ctx context.Context, userID entities.UserID, owner string, since time.Time) (*entities.PhoneQueueStats, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	// the counts are aggregated in a single scan of the messages of the phone with the user_id, owner and status index
+	stats := new(entities.PhoneQueueStats)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Select(
+			"COUNT(*) FILTER (WHERE status = ?) AS pending, COUNT(*) FILTER (WHERE status IN ?) AS scheduled, COUNT(*) FILTER (WHERE status = ?) AS sending, "+
+				"COUNT(*) FILTER (WHERE status = ?) AS failed, COUNT(*) FILTER (WHERE status = ?) AS expired, MIN(request_received_at) FILTER (WHERE status = ?) AS oldest_pending_at",
+			entities.MessageStatusPending, []entities.MessageStatus{entities.MessageStatusScheduled, entities.MessageStatusDeferred}, entities.MessageStatusSending,
+			entities.MessageStatusFailed, entities.MessageStatusExpired, entities.MessageStatusPending,
+		).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where(
+			"status IN ? OR (status IN ? AND updated_at >= ?)",
+			[]entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusDeferred, entities.MessageStatusSending},
+			[]entities.MessageStatus{entities.MessageStatusFailed, entities.MessageStatusExpired},
+			since,
+		).
+		Scan(stats).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the queued [%T] of phone [%s] for user [%s] since [%s]", &entities.Message{}, owner, userID, since)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	stats.Owner = owner
+	stats.Since = since
+	return stats, nil
+}
+
+// CountOutstandingByOwner counts the outgoing entities.Message which have not been sent for every phone
+func (repository *gormMessageRepository) CountOutstandingByOwner(ctx context.Context) ([]*entities.PhoneQueueDepth, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var depths []*entities.PhoneQueueDepth
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.Message{}).
+		Select("user_id, owner, COUNT(*) AS count").
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusSending}).
+		Group("user_id, owner").
+		Scan(&depths).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count outstanding [%T] for every owner", &entities.Message{})
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return depths, nil
+}
+
+// CountOutstandingForOwners counts the outgoing entities.Message of a user which have not been sent for each of the owners
+func (repository *gormMessageRepository) CountOutstandingForOwners(ctx context.Context, userID entities.UserID, owners []string) ([]*entities.PhoneQueueDepth, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var depths []*entities.PhoneQueueDepth
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Select("user_id, owner, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Where("owner IN ?", owners).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusSending}).
+		Group("user_id, owner").
+		Scan(&depths).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count outstanding [%T] of user [%s] for [%d] owners", &entities.Message{}, userID, len(owners))
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return depths, nil
+}
+
+// FetchDueScheduled fetches the entities.Message of every user which are held until a scheduled send time before the timestamp
+func (repository *gormMessageRepository) FetchDueScheduled(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	messages := make([]*entities.Message, 0)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status = ?", entities.MessageStatusDeferred).
+		Where("scheduled_send_time <= ?", timestamp).
+		Order("scheduled_send_time ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch scheduled [%T] which are due before [%s]", &entities.Message{}, timestamp)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+// ReleaseScheduled moves an entities.Message which is held until its scheduled send time to the pending status
+func (repository *gormMessageRepository) ReleaseScheduled(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	message := new(entities.Message)
+	result := repository.db.WithContext(ctx).Model(message).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageID).
+		Where("status = ?", entities.MessageStatusDeferred).
+		Updates(map[string]any{"status": entities.MessageStatusPending, "updated_at": time.Now().UTC()})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot release scheduled message with ID [%s] and userID [%s]", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 || message.ID == uuid.Nil {
+		msg := fmt.Sprintf("scheduled message with ID [%s] and userID [%s] does not exist or it has already been released", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return message, nil
+}
+
+// Cancel moves an entities.Message which has not been picked up by the phone to the cancelled status
+func (repository *gormMessageRepository) Cancel(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the status is checked in the same statement so a message which is fetched by the phone at the same time is not
+	// cancelled. A message which was pushed to the phone but not fetched yet is cancelled because GetOutstanding does not
+	// return a cancelled message so the phone drops the push notification.
+	message := new(entities.Message)
+	result := repository.db.WithContext(ctx).Model(message).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageID).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusDeferred}).
+		Updates(map[string]any{
+			"status":          entities.MessageStatusCancelled,
+			"cancelled_at":    timestamp,
+			"order_timestamp": timestamp,
+			"updated_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot cancel message with ID [%s] and userID [%s]", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 || message.ID == uuid.Nil {
+		msg := fmt.Sprintf("message with ID [%s] and userID [%s] does not exist or it has already been picked up by the phone", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return message, nil
+}
+
+// UpdateFlags sets the flags which are used to triage an entities.Message, the flags which are nil are not changed
+func (repository *gormMessageRepository) UpdateFlags(ctx context.Context, userID entities.UserID, messageID uuid.UUID, isStarred *bool, needsFollowUp *bool) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// only the flags are updated so that a status which is changed by the phone at the same time is not overwritten
+	values := map[string]any{"updated_at": time.Now().UTC()}
+	if isStarred != nil {
+		values["is_starred"] = *isStarred
+	}
+	if needsFollowUp != nil {
+		values["needs_follow_up"] = *needsFollowUp
+	}
+
+	message := new(entities.Message)
+	result := repository.db.WithContext(ctx).Model(message).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageID).
+		Updates(values)
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot update the flags of message with ID [%s] and userID [%s]", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 || message.ID == uuid.Nil {
+		msg := fmt.Sprintf("message with ID [%s] and userID [%s] does not exist", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return message, nil
+}
+
+// FetchPastExpiry fetches the outgoing entities.Message of every user which have not been sent before their expiry time
+func (repository *gormMessageRepository) FetchPastExpiry(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	messages := make([]*entities.Message, 0)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", messageExpirableStatuses).
+		Where("expires_at <= ?", timestamp).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch [%T] which are past their expiry time at [%s]", &entities.Message{}, timestamp)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return messages, nil
+}
+
+// Expire moves an outgoing entities.Message which has not been sent before its expiry time to the expired status
+func (repository *gormMessageRepository) Expire(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the status is checked in the same statement so a message which is sent by the phone at the same time is not expired
+	message := new(entities.Message)
+	result := repository.db.WithContext(ctx).Model(message).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageID).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", messageExpirableStatuses).
+		Where("expires_at <= ?", timestamp).
+		Updates(map[string]any{
+			"status":          entities.MessageStatusExpired,
+			"expired_at":      timestamp,
+			"order_timestamp": timestamp,
+			"updated_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot expire message with ID [%s] and userID [%s]", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 || message.ID == uuid.Nil {
+		msg := fmt.Sprintf("message with ID [%s] and userID [%s] does not exist or it is no longer waiting to be sent", messageID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return message, nil
+}
+
+// FailOver moves a pending outgoing entities.Message of a phone to the backup phone of the user
+func (repository *gormMessageRepository) FailOver(ctx context.Context, userID entities.UserID, messageID uuid.UUID, owner string, backup *entities.Phone, timestamp time.Time) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the owner and the status are checked in the same statement so a message which is picked up by the phone at the same time is not moved
+	message := new(entities.Message)
+	result := repository.db.WithContext(ctx).Model(message).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageID).
+		Where("owner = ?", owner).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status = ?", entities.MessageStatusPending).
+		Updates(map[string]any{
+			"owner":            backup.PhoneNumber,
+			"sim":              backup.SIM,
+			"failed_over_from": owner,
+			"order_timestamp":  timestamp,
+			"updated_at":       time.Now().UTC(),
+		})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot fail over message with ID [%s] and userID [%s] to [%s]", messageID, userID, backup.PhoneNumber)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 || message.ID == uuid.Nil {
+		msg := fmt.Sprintf("message with ID [%s] and userID [%s] does not exist or it is no longer pending on phone [%s]", messageID, userID, owner)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return message, nil
+}
+
+// DeleteBefore deletes at most limit entities.Message of every user in the category which were created before the timestamp and are not waiting to be sent
+func (repository *gormMessageRepository) DeleteBefore(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	// postgres does not support DELETE ... LIMIT so the IDs of the batch are selected in a sub query
+	ids := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.Message{}).
+		Select("id").
+		Where("category = ?", category).
+		Where("status NOT IN ?", messageExpirableStatuses).
+		Where("created_at < ?", timestamp).
+		Limit(limit)
+
+	result := skipTenantScope(repository.db).WithContext(ctx).Where("id IN (?)", ids).Delete(&entities.Message{})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot delete [%s] messages which were created before [%s]", category, timestamp)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Redact replaces the content of at most limit entities.Message of every user in the category which were created before the timestamp and are not waiting to be sent
+func (repository *gormMessageRepository) Redact(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var count int64
+	err := crdbgorm.ExecuteTx(ctx, repository.db, nil,
+		func(tx *gorm.DB) error {
+			var ids []uuid.UUID
+			err := skipTenantScope(tx).WithContext(ctx).
+				Model(&entities.Message{}).
+				Where("category = ?", category).
+				Where("status NOT IN ?", messageExpirableStatuses).
+				Where("redacted_at IS NULL").
+				Where("created_at < ?", timestamp).
+				Limit(limit).
+				Pluck("id", &ids).Error
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+
+			result := skipTenantScope(tx).WithContext(ctx).
+				Model(&entities.Message{}).
+				Where("id IN ?", ids).
+				Updates(map[string]any{
+					"content":            entities.MessageContentRedacted,
+					"translated_content": nil,
+					"redacted_at":        time.Now().UTC(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			count = result.RowsAffected
+
+			// the thread keeps a copy of the content of its last message
+			return skipTenantScope(tx).WithContext(ctx).
+				Model(&entities.MessageThread{}).
+				Where("last_message_id IN ?", ids).
+				Update("last_message_content", entities.MessageContentRedacted).Error
+		},
+	)
+	if err != nil {
+		msg := fmt.Sprintf("cannot redact [%s] messages which were created before [%s]", category, timestamp)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
 func (repository *gormMessageRepository) order(params IndexParams, defaultSortBy string) string {
 	sortBy := defaultSortBy
 	if len(params.SortBy) > 0 {
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"gorm.io/gorm/clause"
 
@@ -72,6 +76,8 @@ func (repository *gormMessageThreadRepository) UpdateAfterDeletedMessage(ctx con
 		Updates(map[string]any{
 			"last_message_id":      nil,
 			"last_message_content": nil,
+			"last_message_type":    nil,
+			"last_message_at":      nil,
 			"status":               entities.MessageStatusDeleted,
 		}).Error
 	if err != nil {
@@ -100,7 +106,7 @@ func (repository *gormMessageThreadRepository) Update(ctx context.Context, threa
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(thread).Error; err != nil {
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", thread.UserID).Save(thread).Error; err != nil {
 		msg := fmt.Sprintf("cannot update message thread thread with ID [%s]", thread.ID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -108,6 +114,50 @@ func (repository *gormMessageThreadRepository) Update(ctx context.Context, threa
 	return nil
 }
 
+// UpdateClassification sets the topic and language of a thread without overwriting the changes made by message events
+func (repository *gormMessageThreadRepository) UpdateClassification(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, topic string, language *string, classifiedAt time.Time) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).Model(&entities.MessageThread{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", messageThreadID).
+		Updates(map[string]any{
+			"topic":         topic,
+			"language":      language,
+			"classified_at": classifiedAt,
+		}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot update classification of thread with ID [%s] for user [%s] to topic [%s]", messageThreadID, userID, topic)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// CountTopics counts the classified threads of an owner by topic and language
+func (repository *gormMessageThreadRepository) CountTopics(ctx context.Context, userID entities.UserID, owner string) ([]*entities.MessageThreadTopicCount, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	counts := make([]*entities.MessageThreadTopicCount, 0)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.MessageThread{}).
+		Select("topic, COALESCE(language, '') AS language, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("topic IS NOT NULL").
+		Group("topic, language").
+		Order("count DESC").
+		Scan(&counts).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count topics of [%T] for user [%s] and owner [%s]", &entities.MessageThread{}, userID, owner)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counts, nil
+}
+
 // LoadByOwnerContact a thread between 2 users
 func (repository *gormMessageThreadRepository) LoadByOwnerContact(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.MessageThread, error) {
 	ctx, span := repository.tracer.Start(ctx)
@@ -162,7 +212,7 @@ func (repository *gormMessageThreadRepository) Load(ctx context.Context, userID
 }
 
 // Index message threads for an owner
-func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID entities.UserID, owner string, isArchived bool, params IndexParams) (*[]entities.MessageThread, error) {
+func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID entities.UserID, owner string, isArchived bool, label string, topic string, language string, params IndexParams) (*[]entities.MessageThread, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
@@ -177,6 +227,18 @@ func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID
 		query.Where(repository.db.Where("is_archived = ?", isArchived).Or("is_archived IS NULL"))
 	}
 
+	if label != "" {
+		query.Where("labels @> ?", pq.StringArray{label})
+	}
+
+	if topic != "" {
+		query.Where("topic = ?", topic)
+	}
+
+	if language != "" {
+		query.Where("language = ?", language)
+	}
+
 	if len(params.Query) > 0 {
 		queryPattern := "%" + params.Query + "%"
 		query.Where(
@@ -186,11 +248,96 @@ func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID
 		)
 	}
 
+	if params.Cursor != nil {
+		query.Where("(is_pinned, order_timestamp, id) < (?, ?, ?)", params.Cursor.IsPinned, params.Cursor.OrderTimestamp, params.Cursor.ID)
+	} else {
+		query.Offset(params.Skip)
+	}
+
 	threads := new([]entities.MessageThread)
-	if err := query.Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&threads).Error; err != nil {
+	if err := query.Order("is_pinned DESC").Order("order_timestamp DESC").Order("id DESC").Limit(params.Limit).Find(&threads).Error; err != nil {
 		msg := fmt.Sprintf("cannot fetch message threads with owner [%s] and params [%+#v]", owner, params)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
 	return threads, nil
 }
+
+// LoadMany fetches the threads of a user with the IDs or the threads which have had no messages since olderThan
+func (repository *gormMessageThreadRepository) LoadMany(ctx context.Context, userID entities.UserID, IDs []uuid.UUID, olderThan *time.Time, limit int) (*[]entities.MessageThread, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(IDs) > 0 {
+		query.Where("id IN ?", IDs)
+	}
+
+	if olderThan != nil {
+		query.Where("order_timestamp < ?", *olderThan)
+	}
+
+	threads := new([]entities.MessageThread)
+	if err := query.Order("order_timestamp ASC").Limit(limit).Find(threads).Error; err != nil {
+		msg := fmt.Sprintf("cannot load message threads for user [%s] with [%d] IDs and older than [%v]", userID, len(IDs), olderThan)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return threads, nil
+}
+
+// Search message threads of a user which have a contact or last message content matching the query
+func (repository *gormMessageThreadRepository) Search(ctx context.Context, userID entities.UserID, owners []string, archived *bool, params IndexParams) (*[]entities.MessageThread, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID)
+
+	if len(owners) > 0 {
+		query.Where("owner IN ?", owners)
+	}
+
+	if archived != nil && *archived {
+		query.Where("is_archived = ?", true)
+	} else if archived != nil {
+		query.Where(repository.db.Where("is_archived = ?", false).Or("is_archived IS NULL"))
+	}
+
+	queryPattern := "%" + params.Query + "%"
+	condition := repository.db.Where("contact ILIKE ?", queryPattern).Or("last_message_content ILIKE ?", queryPattern)
+
+	// phone numbers are stored in the E.164 format so a query like "(800) 555-0100" is matched with the digits only
+	if digits := repository.phoneNumberDigits(params.Query); digits != "" {
+		condition.Or("contact LIKE ?", "%"+digits+"%")
+	}
+
+	threads := new([]entities.MessageThread)
+	if err := query.Where(condition).Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&threads).Error; err != nil {
+		msg := fmt.Sprintf("cannot search message threads for user [%s] with owners [%s] and params [%+#v]", userID, strings.Join(owners, ","), params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return threads, nil
+}
+
+// phoneNumberDigits returns the digits of a query which looks like a phone number
+func (repository *gormMessageThreadRepository) phoneNumberDigits(query string) string {
+	digits := strings.Builder{}
+	for _, char := range query {
+		switch {
+		case unicode.IsDigit(char):
+			digits.WriteRune(char)
+		case strings.ContainsRune("+-(). ", char):
+			continue
+		default:
+			return ""
+		}
+	}
+
+	if digits.Len() < 3 || digits.String() == query {
+		return ""
+	}
+	return digits.String()
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormNotificationChannelRepository is responsible for persisting entities.NotificationChannel
+type gormNotificationChannelRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormNotificationChannelRepository creates the GORM version of the NotificationChannelRepository
+func NewGormNotificationChannelRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) NotificationChannelRepository {
+	return &gormNotificationChannelRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormNotificationChannelRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormNotificationChannelRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.NotificationChannel{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.NotificationChannel{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormNotificationChannelRepository) Save(ctx context.Context, channel *entities.NotificationChannel) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", channel.UserID).Save(channel).Error; err != nil {
+		msg := fmt.Sprintf("cannot update notification channel with ID [%s]", channel.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormNotificationChannelRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.NotificationChannel, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("webhook_url ILIKE ?", queryPattern))
+	}
+
+	channels := make([]*entities.NotificationChannel, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&channels).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch notification channels for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return channels, nil
+}
+
+func (repository *gormNotificationChannelRepository) LoadByEvent(ctx context.Context, userID entities.UserID, event string) ([]*entities.NotificationChannel, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	channels := make([]*entities.NotificationChannel, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("CAST(? as TEXT) = ANY(events)", event).
+		Find(&channels).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification channels for user with ID [%s] and event [%s]", userID, event)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return channels, nil
+}
+
+func (repository *gormNotificationChannelRepository) Load(ctx context.Context, userID entities.UserID, channelID uuid.UUID) (*entities.NotificationChannel, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	channel := new(entities.NotificationChannel)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", channelID).First(&channel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("notification channel with ID [%s] for user [%s] does not exist", channelID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification channel with ID [%s] for user [%s]", channelID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return channel, nil
+}
+
+func (repository *gormNotificationChannelRepository) Delete(ctx context.Context, userID entities.UserID, channelID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", channelID).
+		Delete(&entities.NotificationChannel{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete notification channel with ID [%s] and userID [%s]", channelID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormPhoneBalanceRepository is responsible for persisting entities.PhoneBalance
+type gormPhoneBalanceRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhoneBalanceRepository creates the GORM version of the PhoneBalanceRepository
+func NewGormPhoneBalanceRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhoneBalanceRepository {
+	return &gormPhoneBalanceRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhoneBalanceRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormPhoneBalanceRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhoneBalance{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.PhoneBalance{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneBalanceRepository) Store(ctx context.Context, balance *entities.PhoneBalance) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(balance).Error; err != nil {
+		msg := fmt.Sprintf("cannot save phone balance with ID [%s]", balance.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneBalanceRepository) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneBalance, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("phone_id = ?", phoneID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("content ILIKE ?", queryPattern).Or("currency ILIKE ?", queryPattern))
+	}
+
+	balances := make([]*entities.PhoneBalance, 0)
+	if err := query.Order("reported_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&balances).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch balances of phone [%s] for user [%s] and params [%+#v]", phoneID, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return balances, nil
+}
+
+func (repository *gormPhoneBalanceRepository) LoadLatest(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneBalance, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	balance := new(entities.PhoneBalance)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_id = ?", phoneID).
+		Order("reported_at DESC").
+		First(balance).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("phone [%s] of user [%s] does not have a balance", phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the latest balance of phone [%s] for user [%s]", phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return balance, nil
+}
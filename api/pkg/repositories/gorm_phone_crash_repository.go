@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormPhoneCrashRepository is responsible for persisting entities.PhoneCrash
+type gormPhoneCrashRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhoneCrashRepository creates the GORM version of the PhoneCrashRepository
+func NewGormPhoneCrashRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhoneCrashRepository {
+	return &gormPhoneCrashRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhoneCrashRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormPhoneCrashRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhoneCrash{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.PhoneCrash{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneCrashRepository) Store(ctx context.Context, crash *entities.PhoneCrash) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(crash).Error; err != nil {
+		msg := fmt.Sprintf("cannot save phone crash with ID [%s]", crash.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneCrashRepository) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneCrash, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("phone_id = ?", phoneID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("message ILIKE ?", queryPattern).Or("app_version ILIKE ?", queryPattern))
+	}
+
+	crashes := make([]*entities.PhoneCrash, 0)
+	if err := query.Order("occurred_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&crashes).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch crashes of phone [%s] for user [%s] and params [%+#v]", phoneID, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return crashes, nil
+}
+
+// CountByOwner counts the entities.PhoneCrash of every phone which occurred since a timestamp
+func (repository *gormPhoneCrashRepository) CountByOwner(ctx context.Context, since time.Time) ([]*entities.PhoneCrashCount, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var counts []*entities.PhoneCrashCount
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.PhoneCrash{}).
+		Select("user_id, owner, type, COUNT(*) AS count").
+		Where("occurred_at > ?", since).
+		Group("user_id, owner, type").
+		Scan(&counts).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count [%T] for every owner since [%s]", &entities.PhoneCrash{}, since)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counts, nil
+}
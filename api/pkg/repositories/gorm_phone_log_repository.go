@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormPhoneLogRepository is responsible for persisting entities.PhoneLog
+type gormPhoneLogRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhoneLogRepository creates the GORM version of the PhoneLogRepository
+func NewGormPhoneLogRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhoneLogRepository {
+	return &gormPhoneLogRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhoneLogRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormPhoneLogRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhoneLog{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.PhoneLog{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneLogRepository) Store(ctx context.Context, log *entities.PhoneLog) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(log).Error; err != nil {
+		msg := fmt.Sprintf("cannot save phone log with ID [%s]", log.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneLogRepository) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneLog, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	logs := make([]*entities.PhoneLog, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_id = ?", phoneID).
+		Order("created_at DESC").
+		Limit(params.Limit).
+		Offset(params.Skip).
+		Find(&logs).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch logs of phone [%s] for user [%s] and params [%+#v]", phoneID, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return logs, nil
+}
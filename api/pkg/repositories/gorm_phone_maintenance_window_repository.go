@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormPhoneMaintenanceWindowRepository is responsible for persisting entities.PhoneMaintenanceWindow
+type gormPhoneMaintenanceWindowRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhoneMaintenanceWindowRepository creates the GORM version of the PhoneMaintenanceWindowRepository
+func NewGormPhoneMaintenanceWindowRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhoneMaintenanceWindowRepository {
+	return &gormPhoneMaintenanceWindowRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhoneMaintenanceWindowRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhoneMaintenanceWindow{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.PhoneMaintenanceWindow{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) Store(ctx context.Context, window *entities.PhoneMaintenanceWindow) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(window).Error; err != nil {
+		msg := fmt.Sprintf("cannot save phone maintenance window with ID [%s]", window.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneMaintenanceWindow, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("phone_id = ?", phoneID)
+	if len(params.Query) > 0 {
+		query.Where("reason ILIKE ?", "%"+params.Query+"%")
+	}
+
+	windows := make([]*entities.PhoneMaintenanceWindow, 0)
+	if err := query.Order("starts_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&windows).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch maintenance windows of phone [%s] for user [%s] and params [%+#v]", phoneID, userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return windows, nil
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) LoadBetween(ctx context.Context, userID entities.UserID, owners []string, from time.Time, to time.Time) ([]*entities.PhoneMaintenanceWindow, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("starts_at <= ?", to).
+		Where("ends_at > ?", from)
+	if len(owners) > 0 {
+		query.Where("owner IN ?", owners)
+	}
+
+	windows := make([]*entities.PhoneMaintenanceWindow, 0)
+	if err := query.Order("starts_at ASC").Find(&windows).Error; err != nil {
+		msg := fmt.Sprintf("cannot load maintenance windows for user [%s] between [%s] and [%s]", userID, from, to)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return windows, nil
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) Load(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, windowID uuid.UUID) (*entities.PhoneMaintenanceWindow, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	window := new(entities.PhoneMaintenanceWindow)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_id = ?", phoneID).
+		Where("id = ?", windowID).
+		First(window).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("maintenance window with ID [%s] for phone [%s] and user [%s] does not exist", windowID, phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load maintenance window with ID [%s] for phone [%s] and user [%s]", windowID, phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return window, nil
+}
+
+func (repository *gormPhoneMaintenanceWindowRepository) Delete(ctx context.Context, userID entities.UserID, windowID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", windowID).
+		Delete(&entities.PhoneMaintenanceWindow{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete maintenance window with ID [%s] and userID [%s]", windowID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
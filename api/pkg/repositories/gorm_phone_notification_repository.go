@@ -13,6 +13,7 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/palantir/stacktrace"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // gormPhoneNotificationRepository is responsible for persisting entities.PhoneNotification
@@ -47,12 +48,76 @@ func (repository *gormPhoneNotificationRepository) DeleteAllForUser(ctx context.
 	return nil
 }
 
+// CountFailed counts the entities.PhoneNotification which could not be delivered since a timestamp
+func (repository *gormPhoneNotificationRepository) CountFailed(ctx context.Context, since time.Time) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var count int64
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.PhoneNotification{}).
+		Where("status = ?", entities.PhoneNotificationStatusFailed).
+		Where("updated_at > ?", since).
+		Count(&count).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot count failed [%T] since [%s]", &entities.PhoneNotification{}, since)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+// Next claims the pending entities.PhoneNotification of a phone which should be sent next by moving it to the sending status
+func (repository *gormPhoneNotificationRepository) Next(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneNotification, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	notification := new(entities.PhoneNotification)
+	err := crdbgorm.ExecuteTx(ctx, repository.db, nil, func(tx *gorm.DB) error {
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("user_id = ?", userID).
+			Where("phone_id = ?", phoneID).
+			Where("status = ?", entities.PhoneNotificationStatusPending).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL: "CASE priority WHEN ? THEN ? WHEN ? THEN ? ELSE ? END ASC, scheduled_at ASC",
+				Vars: []any{
+					entities.MessagePriorityHigh, entities.MessagePriorityHigh.Rank(),
+					entities.MessagePriorityLow, entities.MessagePriorityLow.Rank(),
+					entities.MessagePriorityNormal.Rank(),
+				},
+			}}).
+			First(notification).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.WithContext(ctx).
+			Model(notification).
+			Where("user_id = ?", userID).
+			Update("status", entities.PhoneNotificationStatusSending).
+			Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("phone with ID [%s] and userID [%s] has no pending notification", phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot claim the next pending notification for phone with ID [%s] and userID [%s]", phoneID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return notification, nil
+}
+
 // UpdateStatus of an entities.PhoneNotification
 func (repository *gormPhoneNotificationRepository) UpdateStatus(ctx context.Context, notificationID uuid.UUID, status entities.PhoneNotificationStatus) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	err := repository.db.
+	err := skipTenantScope(repository.db).
 		WithContext(ctx).
 		Model(&entities.PhoneNotification{ID: notificationID}).
 		Update("status", status).
@@ -76,7 +141,7 @@ func (repository *gormPhoneNotificationRepository) Schedule(ctx context.Context,
 
 	err := crdbgorm.ExecuteTx(ctx, repository.db, nil, func(tx *gorm.DB) error {
 		lastNotification := new(entities.PhoneNotification)
-		err := tx.WithContext(ctx).
+		err := skipTenantScope(tx).WithContext(ctx).
 			Where("phone_id = ?", notification.PhoneID).
 			Order("scheduled_at desc").
 			First(lastNotification).
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormPhonePoolRepository is responsible for persisting entities.PhonePool
+type gormPhonePoolRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhonePoolRepository creates the GORM version of the PhonePoolRepository
+func NewGormPhonePoolRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhonePoolRepository {
+	return &gormPhonePoolRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhonePoolRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormPhonePoolRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhonePool{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.PhonePool{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhonePoolRepository) Save(ctx context.Context, pool *entities.PhonePool) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the assignments are only changed by Advance so that saving a pool does not undo the assignments which were made in the meantime
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", pool.UserID).Omit("assignments").Save(pool).Error; err != nil {
+		msg := fmt.Sprintf("cannot update phone pool with ID [%s]", pool.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormPhonePoolRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.PhonePool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where("name ILIKE ?", queryPattern)
+	}
+
+	pools := make([]*entities.PhonePool, 0)
+	if err := query.Order("created_at ASC").Limit(params.Limit).Offset(params.Skip).Find(&pools).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch phone pools for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return pools, nil
+}
+
+func (repository *gormPhonePoolRepository) LoadAll(ctx context.Context, userID entities.UserID) ([]*entities.PhonePool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	pools := make([]*entities.PhonePool, 0)
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&pools).Error; err != nil {
+		msg := fmt.Sprintf("cannot load phone pools for user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return pools, nil
+}
+
+func (repository *gormPhonePoolRepository) Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.PhonePool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	pool := new(entities.PhonePool)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", poolID).First(&pool).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("phone pool with ID [%s] for user [%s] does not exist", poolID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone pool with ID [%s] for user [%s]", poolID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return pool, nil
+}
+
+func (repository *gormPhonePoolRepository) Advance(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (uint64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the assignments are incremented in a single statement so that concurrent requests are assigned to different phones
+	var assignments []uint64
+	err := repository.db.WithContext(ctx).
+		Raw("UPDATE phone_pools SET assignments = assignments + 1 WHERE user_id = ? AND id = ? RETURNING assignments - 1", userID, poolID).
+		Scan(&assignments).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot advance the assignments of phone pool with ID [%s] for user [%s]", poolID, userID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(assignments) == 0 {
+		msg := fmt.Sprintf("phone pool with ID [%s] for user [%s] does not exist", poolID, userID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return assignments[0], nil
+}
+
+func (repository *gormPhonePoolRepository) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", poolID).
+		Delete(&entities.PhonePool{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete phone pool with ID [%s] and userID [%s]", poolID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -84,12 +85,50 @@ func (repository *gormPhoneRepository) Delete(ctx context.Context, userID entiti
 	return nil
 }
 
+// UpdateSocketConnectedAt updates the time when the phone opened its WebSocket connection or clears it when connectedAt is nil
+func (repository *gormPhoneRepository) UpdateSocketConnectedAt(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, connectedAt *time.Time) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Phone{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", phoneID).
+		Update("socket_connected_at", connectedAt).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot update the socket connection of phone with ID [%s] and userID [%s]", phoneID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// FetchSocketConnected fetches the entities.Phone of all users which have a WebSocket connection that was opened before connectedBefore
+func (repository *gormPhoneRepository) FetchSocketConnected(ctx context.Context, connectedBefore time.Time) ([]*entities.Phone, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	phones := make([]*entities.Phone, 0)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("socket_connected_at < ?", connectedBefore).
+		Find(&phones).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch phones with a websocket connection opened before [%s]", connectedBefore)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return phones, nil
+}
+
 // Save a new entities.Phone
 func (repository *gormPhoneRepository) Save(ctx context.Context, phone *entities.Phone) error {
 	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
 	defer span.End()
 
-	err := repository.db.WithContext(ctx).Save(phone).Error
+	err := repository.db.WithContext(ctx).Where("user_id = ?", phone.UserID).Save(phone).Error
 	if errors.Is(err, gorm.ErrDuplicatedKey) {
 		ctxLogger.Info(fmt.Sprintf("phone with user [%s] and number[%s] already exists", phone.UserID, phone.PhoneNumber))
 		loadedPhone, err := repository.Load(ctx, phone.UserID, phone.PhoneNumber)
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormReportRepository is responsible for persisting entities.Report
+type gormReportRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormReportRepository creates the GORM version of the ReportRepository
+func NewGormReportRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ReportRepository {
+	return &gormReportRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormReportRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormReportRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.Report{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.Report{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormReportRepository) Save(ctx context.Context, report *entities.Report) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", report.UserID).Save(report).Error; err != nil {
+		msg := fmt.Sprintf("cannot save report with ID [%s]", report.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormReportRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Report, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("type ILIKE ?", queryPattern).Or("delivery ILIKE ?", queryPattern))
+	}
+
+	reports := make([]*entities.Report, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&reports).Error; err != nil {
+		msg := fmt.Sprintf("cannot fetch reports for user [%s] and params [%+#v]", userID, params)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reports, nil
+}
+
+func (repository *gormReportRepository) Load(ctx context.Context, userID entities.UserID, reportID uuid.UUID) (*entities.Report, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	report := new(entities.Report)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", reportID).First(&report).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("report with ID [%s] for user [%s] does not exist", reportID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load report with ID [%s] for user [%s]", reportID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return report, nil
+}
+
+func (repository *gormReportRepository) Delete(ctx context.Context, userID entities.UserID, reportID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", reportID).
+		Delete(&entities.Report{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete report with ID [%s] and userID [%s]", reportID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormReportRepository) FetchDue(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Report, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	reports := make([]*entities.Report, 0, limit)
+	err := skipTenantScope(repository.db).WithContext(ctx).
+		Where("next_run_at <= ?", timestamp).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&reports).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch reports which are due before [%s]", timestamp)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reports, nil
+}
+
+func (repository *gormReportRepository) Reschedule(ctx context.Context, report *entities.Report, nextRunAt time.Time) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the current run time is checked in the same statement so that a report is generated only once by multiple instances
+	result := repository.db.WithContext(ctx).Model(&entities.Report{}).
+		Where("user_id = ?", report.UserID).
+		Where("id = ?", report.ID).
+		Where("next_run_at = ?", report.NextRunAt).
+		Updates(map[string]any{"next_run_at": nextRunAt, "updated_at": time.Now().UTC()})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot reschedule report with ID [%s] and userID [%s]", report.ID, report.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 {
+		msg := fmt.Sprintf("report with ID [%s] and userID [%s] does not exist or it has already been rescheduled", report.ID, report.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormSuppressionRepository is responsible for persisting entities.Suppression
+type gormSuppressionRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormSuppressionRepository creates the GORM version of the SuppressionRepository
+func NewGormSuppressionRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) SuppressionRepository {
+	return &gormSuppressionRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormSuppressionRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.Suppression
+func (repository *gormSuppressionRepository) Store(ctx context.Context, suppression *entities.Suppression) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(suppression).Error; err != nil {
+		msg := fmt.Sprintf("cannot save suppression with ID [%s]", suppression.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Exists checks if a contact is suppressed for a phone number
+func (repository *gormSuppressionRepository) Exists(ctx context.Context, userID entities.UserID, owner string, contact string) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var count int64
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Suppression{}).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact = ?", contact).
+		Count(&count).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if contact [%s] is suppressed for owner [%s] and user [%s]", contact, owner, userID)
+		return false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count > 0, nil
+}
+
+// Delete the entities.Suppression of a contact
+func (repository *gormSuppressionRepository) Delete(ctx context.Context, userID entities.UserID, owner string, contact string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact = ?", contact).
+		Delete(&entities.Suppression{}).Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete suppression of contact [%s] for owner [%s] and user [%s]", contact, owner, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Suppression for a user
+func (repository *gormSuppressionRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.Suppression{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.Suppression{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"gorm.io/gorm"
+)
+
+// gormTimeseriesRow is a row of the query which groups a table into time buckets
+type gormTimeseriesRow struct {
+	Owner  string
+	Bucket int64
+	Count  int64
+}
+
+// gormTimeseries counts the rows of the query per owner in buckets of params.Interval on the timestamp column
+func gormTimeseries(query *gorm.DB, column string, params TimeseriesParams) ([]*entities.TimeseriesBucket, error) {
+	seconds := int64(params.Interval / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	column = fmt.Sprintf("%q", column)
+	query = query.
+		Select(fmt.Sprintf("owner, (FLOOR(EXTRACT(EPOCH FROM %s) / ?) * ?)::INT8 AS bucket, COUNT(*) AS count", column), seconds, seconds).
+		Where(column+" >= ?", params.From).
+		Where(column+" < ?", params.To)
+
+	if len(params.Owners) > 0 {
+		query = query.Where("owner IN ?", params.Owners)
+	}
+
+	var rows []*gormTimeseriesRow
+	if err := query.Group("owner, bucket").Order("bucket ASC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*entities.TimeseriesBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, &entities.TimeseriesBucket{
+			Owner:     row.Owner,
+			Timestamp: time.Unix(row.Bucket, 0).UTC(),
+			Count:     row.Count,
+		})
+	}
+	return buckets, nil
+}
@@ -161,8 +161,7 @@ func (repository *gormUserRepository) LoadAuthUser(ctx context.Context, apiKey s
 	user := new(entities.User)
 	err := repository.db.WithContext(ctx).Where("api_key = ?", apiKey).First(user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		msg := fmt.Sprintf("user with api key [%s] does not exist", apiKey)
-		return entities.AuthUser{}, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+		return repository.loadScopedAuthUser(ctx, apiKey)
 	}
 
 	if err != nil {
@@ -183,6 +182,77 @@ func (repository *gormUserRepository) LoadAuthUser(ctx context.Context, apiKey s
 	return authUser, nil
 }
 
+// loadScopedAuthUser fetches the entities.AuthUser of an entities.APIKey with the scopes of the key. It is cached for a
+// short time so that a deleted key stops working quickly.
+func (repository *gormUserRepository) loadScopedAuthUser(ctx context.Context, apiKey string) (entities.AuthUser, error) {
+	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
+	defer span.End()
+
+	// the key is looked up before the user is known so the query cannot be scoped by the user_id
+	key := new(entities.APIKey)
+	err := skipTenantScope(repository.db).WithContext(ctx).Where("key = ?", apiKey).First(key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		msg := fmt.Sprintf("user with api key [%s] does not exist", apiKey)
+		return entities.AuthUser{}, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scoped api key [%s]", apiKey)
+		return entities.AuthUser{}, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	user, err := repository.Load(ctx, key.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user [%s] of api key with ID [%s]", key.UserID, key.ID)
+		return entities.AuthUser{}, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	authUser := entities.AuthUser{
+		ID:             user.ID,
+		Email:          user.Email,
+		Scopes:         key.APIKeyScopes(),
+		DefaultPhoneID: key.DefaultPhoneID,
+	}
+
+	if result := repository.cache.SetWithTTL(apiKey, authUser, 1, time.Minute); !result {
+		msg := fmt.Sprintf("cannot cache [%T] with ID [%s] for api key with ID [%s] and result [%t]", authUser, user.ID, key.ID, result)
+		ctxLogger.Error(repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg)))
+	}
+
+	return authUser, nil
+}
+
+// LoadAuthUserByID fetches the entities.AuthUser of an entities.User by ID. It is cached for a short time so that a
+// deleted user cannot use a valid identity token for long.
+func (repository *gormUserRepository) LoadAuthUserByID(ctx context.Context, userID entities.UserID) (entities.AuthUser, error) {
+	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
+	defer span.End()
+
+	cacheKey := "user-id." + userID.String()
+	if authUser, found := repository.cache.Get(cacheKey); found {
+		ctxLogger.Info(fmt.Sprintf("cache hit for user with ID [%s]", authUser.ID))
+		return authUser, nil
+	}
+
+	user, err := repository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load auth user with ID [%s]", userID)
+		return entities.AuthUser{}, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	authUser := entities.AuthUser{
+		ID:    user.ID,
+		Email: user.Email,
+	}
+
+	if result := repository.cache.SetWithTTL(cacheKey, authUser, 1, time.Minute); !result {
+		msg := fmt.Sprintf("cannot cache [%T] with ID [%s] and result [%t]", authUser, user.ID, result)
+		ctxLogger.Error(repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg)))
+	}
+
+	return authUser, nil
+}
+
 func (repository *gormUserRepository) Load(ctx context.Context, userID entities.UserID) (*entities.User, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+)
+
+// gormWebhookEventRepository is responsible for persisting entities.WebhookEvent
+type gormWebhookEventRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormWebhookEventRepository creates the GORM version of the WebhookEventRepository
+func NewGormWebhookEventRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) WebhookEventRepository {
+	return &gormWebhookEventRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormWebhookEventRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *gormWebhookEventRepository) Store(ctx context.Context, event *entities.WebhookEvent) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(event).Error; err != nil {
+		msg := fmt.Sprintf("cannot save webhook event with ID [%s] for event [%s]", event.ID, event.EventID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormWebhookEventRepository) LoadByTimestamp(ctx context.Context, userID entities.UserID, from time.Time, to time.Time, types []string, owners []string, limit int) ([]*entities.WebhookEvent, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("timestamp >= ?", from).
+		Where("timestamp < ?", to).
+		Where("type IN ?", types)
+
+	if len(owners) > 0 {
+		query = query.Where(repository.db.Where("owner IN ?", owners).Or("owner = ?", ""))
+	}
+
+	webhookEvents := make([]*entities.WebhookEvent, 0, limit)
+	if err := query.Order("timestamp ASC").Order("id ASC").Limit(limit).Find(&webhookEvents).Error; err != nil {
+		msg := fmt.Sprintf("cannot load webhook events for user [%s] between [%s] and [%s]", userID, from, to)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhookEvents, nil
+}
+
+func (repository *gormWebhookEventRepository) DeleteBefore(ctx context.Context, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	// postgres does not support DELETE ... LIMIT so the IDs of the batch are selected in a sub query
+	ids := skipTenantScope(repository.db).WithContext(ctx).
+		Model(&entities.WebhookEvent{}).
+		Select("id").
+		Where("timestamp < ?", timestamp).
+		Limit(limit)
+
+	result := skipTenantScope(repository.db).WithContext(ctx).Where("id IN (?)", ids).Delete(&entities.WebhookEvent{})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot delete webhook events which happened before [%s]", timestamp)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (repository *gormWebhookEventRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.WebhookEvent{}).Error; err != nil {
+		msg := fmt.Sprintf("cannot delete all [%T] for user with ID [%s]", &entities.WebhookEvent{}, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // gormWebhookRepository is responsible for persisting entities.Webhook
@@ -48,7 +50,7 @@ func (repository *gormWebhookRepository) Save(ctx context.Context, webhook *enti
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(webhook).Error; err != nil {
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", webhook.UserID).Save(webhook).Error; err != nil {
 		msg := fmt.Sprintf("cannot update webhook with ID [%s]", webhook.ID)
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -80,11 +82,16 @@ func (repository *gormWebhookRepository) LoadByEvent(ctx context.Context, userID
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
+	query := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("paused_at IS NULL").
+		Where("CAST(? as TEXT) = ANY(events)", event)
+	if phoneNumber != "" {
+		query = query.Where("CAST(? as TEXT) = ANY(phone_numbers)", phoneNumber)
+	}
+
 	webhooks := make([]*entities.Webhook, 0)
-	err := repository.db.
-		Raw("SELECT * FROM webhooks WHERE user_id = ? AND CAST(? as TEXT) = ANY(events) AND CAST(? as TEXT) = ANY(phone_numbers)", userID, event, phoneNumber).
-		Scan(&webhooks).
-		Error
+	err := query.Find(&webhooks).Error
 	if err != nil {
 		msg := fmt.Sprintf("cannot load webhooks for user with ID [%s] and event [%s]", userID, event)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -93,6 +100,72 @@ func (repository *gormWebhookRepository) LoadByEvent(ctx context.Context, userID
 	return webhooks, nil
 }
 
+func (repository *gormWebhookRepository) RecordFailure(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	webhook := new(entities.Webhook)
+	err := repository.db.WithContext(ctx).
+		Model(webhook).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "consecutive_failures"}}}).
+		Where("user_id = ?", userID).
+		Where("id = ?", webhookID).
+		UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot record failure for webhook with ID [%s] and user [%s]", webhookID, userID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhook.ConsecutiveFailures, nil
+}
+
+func (repository *gormWebhookRepository) ResetFailures(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Webhook{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", webhookID).
+		Where("consecutive_failures > ?", 0).
+		UpdateColumn("consecutive_failures", 0).
+		Error
+	if err != nil {
+		msg := fmt.Sprintf("cannot reset failures for webhook with ID [%s] and user [%s]", webhookID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *gormWebhookRepository) Pause(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, timestamp time.Time, reason string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result := repository.db.WithContext(ctx).
+		Model(&entities.Webhook{}).
+		Where("user_id = ?", userID).
+		Where("id = ?", webhookID).
+		Where("paused_at IS NULL").
+		Updates(map[string]any{
+			"paused_at":     timestamp,
+			"paused_reason": reason,
+			"updated_at":    time.Now().UTC(),
+		})
+	if result.Error != nil {
+		msg := fmt.Sprintf("cannot pause webhook with ID [%s] and user [%s]", webhookID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(result.Error, msg))
+	}
+
+	if result.RowsAffected == 0 {
+		msg := fmt.Sprintf("webhook with ID [%s] and user [%s] does not exist or it is already paused", webhookID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
+
 func (repository *gormWebhookRepository) Load(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (*entities.Webhook, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
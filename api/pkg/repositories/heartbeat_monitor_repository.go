@@ -25,7 +25,7 @@ type HeartbeatMonitorRepository interface {
 	// Delete an entities.HeartbeatMonitor
 	Delete(ctx context.Context, userID entities.UserID, phoneNumber string) error
 
-	// UpdatePhoneOnline updates the phone online status of a monitor
+	// UpdatePhoneOnline updates the phone online status of a monitor and the time when the phone was last marked offline
 	UpdatePhoneOnline(ctx context.Context, userID entities.UserID, monitorID uuid.UUID, online bool) error
 
 	// DeleteAllForUser deletes all entities.HeartbeatMonitor for a user
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 )
@@ -17,6 +18,18 @@ type HeartbeatRepository interface {
 	// Last entities.Heartbeat returns the last heartbeat
 	Last(ctx context.Context, userID entities.UserID, owner string) (*entities.Heartbeat, error)
 
+	// Timeseries counts the entities.Heartbeat of a user in time buckets
+	Timeseries(ctx context.Context, userID entities.UserID, params TimeseriesParams) ([]*entities.TimeseriesBucket, error)
+
+	// SignalTimeseries aggregates the signal strength and network type of the entities.Heartbeat of a user in time buckets
+	SignalTimeseries(ctx context.Context, userID entities.UserID, params TimeseriesParams) ([]*entities.HeartbeatSignalBucket, error)
+
+	// Timestamps returns the timestamps of the entities.Heartbeat of an owner between from and to ordered from the oldest to the newest
+	Timestamps(ctx context.Context, userID entities.UserID, owner string, from time.Time, to time.Time) ([]time.Time, error)
+
+	// LastForEveryOwner returns the last entities.Heartbeat of every phone which sent a heartbeat since a timestamp
+	LastForEveryOwner(ctx context.Context, since time.Time) ([]*entities.Heartbeat, error)
+
 	// DeleteAllForUser deletes all entities.Heartbeat for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
 }
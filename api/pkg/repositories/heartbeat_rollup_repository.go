@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// HeartbeatRollupRepository loads and persists an entities.HeartbeatRollup
+type HeartbeatRollupRepository interface {
+	// RollUp aggregates the entities.Heartbeat of every user in the oldest window which starts before the timestamp into hourly and daily
+	// entities.HeartbeatRollup and deletes the aggregated heartbeats. It returns the number of deleted heartbeats which is 0 when there are
+	// no heartbeats before the timestamp
+	RollUp(ctx context.Context, timestamp time.Time, window time.Duration) (int64, error)
+
+	// Index fetches the entities.HeartbeatRollup of an owner with a resolution ordered from the newest to the oldest
+	Index(ctx context.Context, userID entities.UserID, owner string, resolution entities.HeartbeatRollupResolution, params IndexParams) ([]*entities.HeartbeatRollup, error)
+
+	// DeleteAllForUser deletes all entities.HeartbeatRollup for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// InboxRuleRepository loads and persists an entities.InboxRule
+type InboxRuleRepository interface {
+	// Save Upsert a new entities.InboxRule
+	Save(ctx context.Context, rule *entities.InboxRule) error
+
+	// Index entities.InboxRule by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.InboxRule, error)
+
+	// LoadAll loads all the inbox rules of a user
+	LoadAll(ctx context.Context, userID entities.UserID) ([]*entities.InboxRule, error)
+
+	// Load loads an inbox rule by ID.
+	Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.InboxRule, error)
+
+	// Delete an entities.InboxRule
+	Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.InboxRule for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/google/uuid"
@@ -18,14 +19,23 @@ type MessageRepository interface {
 	// Load an entities.Message by ID
 	Load(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error)
 
-	// Index entities.Message between 2 phone numbers
-	Index(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error)
+	// Index entities.Message between 2 phone numbers, only the messages with the flags are fetched when isStarred or needsFollowUp is not nil
+	Index(ctx context.Context, userID entities.UserID, owner string, contact string, isStarred *bool, needsFollowUp *bool, params IndexParams) (*[]entities.Message, error)
+
+	// UpdateFlags sets the flags which are used to triage an entities.Message, the flags which are nil are not changed
+	UpdateFlags(ctx context.Context, userID entities.UserID, messageID uuid.UUID, isStarred *bool, needsFollowUp *bool) (*entities.Message, error)
+
+	// Timeline fetches the entities.Message exchanged with a contact by all the phone numbers of a user ordered from the newest to the oldest
+	Timeline(ctx context.Context, userID entities.UserID, contact string, params IndexParams) (*[]entities.Message, error)
+
+	// History fetches the entities.Message between 2 phone numbers ordered from the oldest to the newest
+	History(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error)
 
 	// LastMessage fetches the last message between an owner and a contact
 	LastMessage(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.Message, error)
 
 	// Search entities.Message for a user
-	Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, params IndexParams) ([]*entities.Message, error)
+	Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, senderNames []string, params IndexParams) ([]*entities.Message, error)
 
 	// GetOutstanding fetches an entities.Message which is outstanding
 	GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error)
@@ -38,4 +48,49 @@ type MessageRepository interface {
 
 	// DeleteAllForUser deletes all entities.Message for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+
+	// Timeseries counts the entities.Message of a user in time buckets
+	Timeseries(ctx context.Context, userID entities.UserID, types []entities.MessageType, statuses []entities.MessageStatus, params TimeseriesParams) ([]*entities.TimeseriesBucket, error)
+
+	// FetchReceivedSince fetches the entities.Message which were received from a contact after the timestamp and are not duplicates ordered from the newest to the oldest
+	FetchReceivedSince(ctx context.Context, userID entities.UserID, owner string, contact string, timestamp time.Time, limit int) ([]*entities.Message, error)
+
+	// CountFailures counts the outgoing entities.Message of a user which failed between from and to for every phone and failure reason
+	CountFailures(ctx context.Context, userID entities.UserID, from time.Time, to time.Time) ([]*entities.MessageFailureCount, error)
+
+	// Queue fetches the outgoing entities.Message of a phone which are pending, scheduled or sending in the order they are dispatched to the phone
+	Queue(ctx context.Context, userID entities.UserID, owner string, limit int) ([]*entities.Message, error)
+
+	// QueueStats counts the outgoing entities.Message of a phone in each state of its queue, the failed and expired messages are counted since the timestamp
+	QueueStats(ctx context.Context, userID entities.UserID, owner string, since time.Time) (*entities.PhoneQueueStats, error)
+
+	// CountOutstandingByOwner counts the outgoing entities.Message which have not been sent for every phone
+	CountOutstandingByOwner(ctx context.Context) ([]*entities.PhoneQueueDepth, error)
+
+	// CountOutstandingForOwners counts the outgoing entities.Message of a user which have not been sent for each of the owners
+	CountOutstandingForOwners(ctx context.Context, userID entities.UserID, owners []string) ([]*entities.PhoneQueueDepth, error)
+
+	// FetchDueScheduled fetches the deferred entities.Message of every user with a scheduled send time before the timestamp
+	FetchDueScheduled(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error)
+
+	// ReleaseScheduled moves a deferred entities.Message to the pending status
+	ReleaseScheduled(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error)
+
+	// Cancel moves an entities.Message which has not been picked up by the phone to the cancelled status
+	Cancel(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error)
+
+	// FetchPastExpiry fetches the outgoing entities.Message of every user which have not been sent before their expiry time
+	FetchPastExpiry(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error)
+
+	// Expire moves an outgoing entities.Message which has not been sent before its expiry time to the expired status
+	Expire(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error)
+
+	// FailOver moves a pending outgoing entities.Message of a phone to the backup phone of the user
+	FailOver(ctx context.Context, userID entities.UserID, messageID uuid.UUID, owner string, backup *entities.Phone, timestamp time.Time) (*entities.Message, error)
+
+	// DeleteBefore deletes at most limit entities.Message of every user in the category which were created before the timestamp and are not waiting to be sent
+	DeleteBefore(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error)
+
+	// Redact replaces the content of at most limit entities.Message of every user in the category which were created before the timestamp and are not waiting to be sent
+	Redact(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error)
 }
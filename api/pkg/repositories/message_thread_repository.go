@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,8 +23,20 @@ type MessageThreadRepository interface {
 	// Load a thread by ID
 	Load(ctx context.Context, userID entities.UserID, ID uuid.UUID) (*entities.MessageThread, error)
 
-	// Index message threads for an owner
-	Index(ctx context.Context, userID entities.UserID, owner string, archived bool, params IndexParams) (*[]entities.MessageThread, error)
+	// Index message threads for an owner with the pinned threads first, the threads are filtered by the label, topic and language which are not empty
+	Index(ctx context.Context, userID entities.UserID, owner string, archived bool, label string, topic string, language string, params IndexParams) (*[]entities.MessageThread, error)
+
+	// UpdateClassification sets the topic and language of a thread which were detected by a classifier
+	UpdateClassification(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, topic string, language *string, classifiedAt time.Time) error
+
+	// CountTopics counts the classified threads of an owner by topic and language
+	CountTopics(ctx context.Context, userID entities.UserID, owner string) ([]*entities.MessageThreadTopicCount, error)
+
+	// Search message threads of a user by the contact or the content of the last message
+	Search(ctx context.Context, userID entities.UserID, owners []string, archived *bool, params IndexParams) (*[]entities.MessageThread, error)
+
+	// LoadMany fetches the threads of a user with the IDs or the threads which have had no messages since olderThan
+	LoadMany(ctx context.Context, userID entities.UserID, IDs []uuid.UUID, olderThan *time.Time, limit int) (*[]entities.MessageThread, error)
 
 	// UpdateAfterDeletedMessage updates a thread after the original message has been deleted
 	UpdateAfterDeletedMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error
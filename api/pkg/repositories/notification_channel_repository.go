@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// NotificationChannelRepository loads and persists an entities.NotificationChannel
+type NotificationChannelRepository interface {
+	// Save Upsert a new entities.NotificationChannel
+	Save(ctx context.Context, channel *entities.NotificationChannel) error
+
+	// Index entities.NotificationChannel by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.NotificationChannel, error)
+
+	// LoadByEvent loads the notification channels of a user which are subscribed to an event.
+	LoadByEvent(ctx context.Context, userID entities.UserID, event string) ([]*entities.NotificationChannel, error)
+
+	// Load loads a notification channel by ID.
+	Load(ctx context.Context, userID entities.UserID, channelID uuid.UUID) (*entities.NotificationChannel, error)
+
+	// Delete an entities.NotificationChannel
+	Delete(ctx context.Context, userID entities.UserID, channelID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.NotificationChannel for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
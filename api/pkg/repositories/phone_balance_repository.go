@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// PhoneBalanceRepository loads and persists an entities.PhoneBalance
+type PhoneBalanceRepository interface {
+	// Store a new entities.PhoneBalance
+	Store(ctx context.Context, balance *entities.PhoneBalance) error
+
+	// Index entities.PhoneBalance of a phone
+	Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneBalance, error)
+
+	// LoadLatest loads the entities.PhoneBalance of a phone which was reported last
+	LoadLatest(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneBalance, error)
+
+	// DeleteAllForUser deletes all entities.PhoneBalance for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// PhoneCrashRepository loads and persists an entities.PhoneCrash
+type PhoneCrashRepository interface {
+	// Store a new entities.PhoneCrash
+	Store(ctx context.Context, crash *entities.PhoneCrash) error
+
+	// Index entities.PhoneCrash of a phone
+	Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneCrash, error)
+
+	// CountByOwner counts the entities.PhoneCrash of every phone which occurred since a timestamp
+	CountByOwner(ctx context.Context, since time.Time) ([]*entities.PhoneCrashCount, error)
+
+	// DeleteAllForUser deletes all entities.PhoneCrash for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// PhoneLogRepository loads and persists an entities.PhoneLog
+type PhoneLogRepository interface {
+	// Store a new entities.PhoneLog
+	Store(ctx context.Context, log *entities.PhoneLog) error
+
+	// Index entities.PhoneLog of a phone
+	Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneLog, error)
+
+	// DeleteAllForUser deletes all entities.PhoneLog for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
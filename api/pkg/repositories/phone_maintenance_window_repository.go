@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// PhoneMaintenanceWindowRepository loads and persists an entities.PhoneMaintenanceWindow
+type PhoneMaintenanceWindowRepository interface {
+	// Store a new entities.PhoneMaintenanceWindow
+	Store(ctx context.Context, window *entities.PhoneMaintenanceWindow) error
+
+	// Index entities.PhoneMaintenanceWindow of a phone
+	Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params IndexParams) ([]*entities.PhoneMaintenanceWindow, error)
+
+	// LoadBetween loads the entities.PhoneMaintenanceWindow of the owners which overlap with a time range. All owners are loaded when owners is empty.
+	LoadBetween(ctx context.Context, userID entities.UserID, owners []string, from time.Time, to time.Time) ([]*entities.PhoneMaintenanceWindow, error)
+
+	// Load an entities.PhoneMaintenanceWindow of a phone by ID
+	Load(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, windowID uuid.UUID) (*entities.PhoneMaintenanceWindow, error)
+
+	// Delete an entities.PhoneMaintenanceWindow
+	Delete(ctx context.Context, userID entities.UserID, windowID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.PhoneMaintenanceWindow for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,9 +14,15 @@ type PhoneNotificationRepository interface {
 	// Schedule a new entities.PhoneNotification
 	Schedule(ctx context.Context, messagesPerMinute uint, notification *entities.PhoneNotification) error
 
+	// Next claims the pending entities.PhoneNotification of a phone which should be sent next, ordered by priority before the time it was scheduled
+	Next(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneNotification, error)
+
 	// UpdateStatus of a notification
 	UpdateStatus(ctx context.Context, notificationID uuid.UUID, status entities.PhoneNotificationStatus) error
 
+	// CountFailed counts the entities.PhoneNotification which could not be delivered since a timestamp
+	CountFailed(ctx context.Context, since time.Time) (int64, error)
+
 	// DeleteAllForUser deletes all entities.PhoneNotification for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
 }
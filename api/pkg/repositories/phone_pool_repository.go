@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// PhonePoolRepository loads and persists an entities.PhonePool
+type PhonePoolRepository interface {
+	// Save Upsert a new entities.PhonePool
+	Save(ctx context.Context, pool *entities.PhonePool) error
+
+	// Index entities.PhonePool by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.PhonePool, error)
+
+	// LoadAll loads all the phone pools of a user
+	LoadAll(ctx context.Context, userID entities.UserID) ([]*entities.PhonePool, error)
+
+	// Load loads a phone pool by ID.
+	Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.PhonePool, error)
+
+	// Advance increments the round robin assignments of a phone pool and returns the number of assignments before the increment
+	Advance(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (uint64, error)
+
+	// Delete an entities.PhonePool
+	Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.PhonePool for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,6 +23,12 @@ type PhoneRepository interface {
 	// LoadByID a phone by ID
 	LoadByID(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.Phone, error)
 
+	// UpdateSocketConnectedAt updates the time when the phone opened its WebSocket connection or clears it when connectedAt is nil
+	UpdateSocketConnectedAt(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, connectedAt *time.Time) error
+
+	// FetchSocketConnected fetches the entities.Phone of all users which have a WebSocket connection that was opened before connectedBefore
+	FetchSocketConnected(ctx context.Context, connectedBefore time.Time) ([]*entities.Phone, error)
+
 	// Delete an entities.Phone
 	Delete(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) error
 
@@ -0,0 +1,325 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// regionalMessageRepository routes queries for entities.Message to the database in the data region of the user
+type regionalMessageRepository struct {
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	router       DataRegionRouter
+	defaultShard MessageRepository
+	shards       map[string]MessageRepository
+}
+
+// NewRegionalMessageRepository creates a MessageRepository which is sharded by the data region of the user
+func NewRegionalMessageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	router DataRegionRouter,
+	defaultShard MessageRepository,
+	shards map[string]MessageRepository,
+) MessageRepository {
+	return &regionalMessageRepository{
+		logger:       logger.WithService(fmt.Sprintf("%T", &regionalMessageRepository{})),
+		tracer:       tracer,
+		router:       router,
+		defaultShard: defaultShard,
+		shards:       shards,
+	}
+}
+
+func (repository *regionalMessageRepository) shard(ctx context.Context, userID entities.UserID) (MessageRepository, error) {
+	region, err := repository.router.Region(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot resolve data region for user with ID [%s]", userID))
+	}
+
+	if region == "" {
+		return repository.defaultShard, nil
+	}
+
+	shard, ok := repository.shards[region]
+	if !ok {
+		return nil, stacktrace.NewError(fmt.Sprintf("data region [%s] of user with ID [%s] is not configured", region, userID))
+	}
+	return shard, nil
+}
+
+func (repository *regionalMessageRepository) Store(ctx context.Context, message *entities.Message) error {
+	shard, err := repository.shard(ctx, message.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot store message with ID [%s]", message.ID))
+	}
+	return shard.Store(ctx, message)
+}
+
+func (repository *regionalMessageRepository) Update(ctx context.Context, message *entities.Message) error {
+	shard, err := repository.shard(ctx, message.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot update message with ID [%s]", message.ID))
+	}
+	return shard.Update(ctx, message)
+}
+
+func (repository *regionalMessageRepository) Load(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load message with ID [%s]", messageID))
+	}
+	return shard.Load(ctx, userID, messageID)
+}
+
+func (repository *regionalMessageRepository) Index(ctx context.Context, userID entities.UserID, owner string, contact string, isStarred *bool, needsFollowUp *bool, params IndexParams) (*[]entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot index messages between [%s] and [%s]", owner, contact))
+	}
+	return shard.Index(ctx, userID, owner, contact, isStarred, needsFollowUp, params)
+}
+
+func (repository *regionalMessageRepository) Timeline(ctx context.Context, userID entities.UserID, contact string, params IndexParams) (*[]entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch timeline of contact [%s]", contact))
+	}
+	return shard.Timeline(ctx, userID, contact, params)
+}
+
+func (repository *regionalMessageRepository) History(ctx context.Context, userID entities.UserID, owner string, contact string, params IndexParams) (*[]entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch message history between [%s] and [%s]", owner, contact))
+	}
+	return shard.History(ctx, userID, owner, contact, params)
+}
+
+func (repository *regionalMessageRepository) LastMessage(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load last message between [%s] and [%s]", owner, contact))
+	}
+	return shard.LastMessage(ctx, userID, owner, contact)
+}
+
+func (repository *regionalMessageRepository) Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, senderNames []string, params IndexParams) ([]*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot search messages for user with ID [%s]", userID))
+	}
+	return shard.Search(ctx, userID, owners, types, statuses, senderNames, params)
+}
+
+func (repository *regionalMessageRepository) GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch outstanding message with ID [%s]", messageID))
+	}
+	return shard.GetOutstanding(ctx, userID, messageID)
+}
+
+func (repository *regionalMessageRepository) Delete(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete message with ID [%s]", messageID))
+	}
+	return shard.Delete(ctx, userID, messageID)
+}
+
+func (repository *regionalMessageRepository) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner string, contact string) error {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete messages between [%s] and [%s]", owner, contact))
+	}
+	return shard.DeleteByOwnerAndContact(ctx, userID, owner, contact)
+}
+
+func (repository *regionalMessageRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	// The user may already be deleted when this is called, so we delete from every region instead of resolving the region of the user
+	if err := repository.defaultShard.DeleteAllForUser(ctx, userID); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete all messages for user with ID [%s] in the default region", userID))
+	}
+
+	for region, shard := range repository.shards {
+		if err := shard.DeleteAllForUser(ctx, userID); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot delete all messages for user with ID [%s] in data region [%s]", userID, region))
+		}
+	}
+	return nil
+}
+
+func (repository *regionalMessageRepository) Timeseries(ctx context.Context, userID entities.UserID, types []entities.MessageType, statuses []entities.MessageStatus, params TimeseriesParams) ([]*entities.TimeseriesBucket, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count messages in time buckets for user with ID [%s]", userID))
+	}
+	return shard.Timeseries(ctx, userID, types, statuses, params)
+}
+
+func (repository *regionalMessageRepository) Queue(ctx context.Context, userID entities.UserID, owner string, limit int) ([]*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch the queued messages of phone [%s]", owner))
+	}
+	return shard.Queue(ctx, userID, owner, limit)
+}
+
+func (repository *regionalMessageRepository) CountOutstandingByOwner(ctx context.Context) ([]*entities.PhoneQueueDepth, error) {
+	depths, err := repository.defaultShard.CountOutstandingByOwner(ctx)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot count outstanding messages in the default region")
+	}
+
+	for region, shard := range repository.shards {
+		regionDepths, err := shard.CountOutstandingByOwner(ctx)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count outstanding messages in data region [%s]", region))
+		}
+		depths = append(depths, regionDepths...)
+	}
+	return depths, nil
+}
+
+func (repository *regionalMessageRepository) QueueStats(ctx context.Context, userID entities.UserID, owner string, since time.Time) (*entities.PhoneQueueStats, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count the queued messages of phone [%s]", owner))
+	}
+	return shard.QueueStats(ctx, userID, owner, since)
+}
+
+func (repository *regionalMessageRepository) CountOutstandingForOwners(ctx context.Context, userID entities.UserID, owners []string) ([]*entities.PhoneQueueDepth, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count the outstanding messages of [%d] owners", len(owners)))
+	}
+	return shard.CountOutstandingForOwners(ctx, userID, owners)
+}
+
+func (repository *regionalMessageRepository) FetchDueScheduled(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	messages, err := repository.defaultShard.FetchDueScheduled(ctx, timestamp, limit)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot fetch scheduled messages in the default region")
+	}
+
+	for region, shard := range repository.shards {
+		regionMessages, err := shard.FetchDueScheduled(ctx, timestamp, limit)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch scheduled messages in data region [%s]", region))
+		}
+		messages = append(messages, regionMessages...)
+	}
+	return messages, nil
+}
+
+func (repository *regionalMessageRepository) ReleaseScheduled(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot release scheduled message with ID [%s]", messageID))
+	}
+	return shard.ReleaseScheduled(ctx, userID, messageID)
+}
+
+func (repository *regionalMessageRepository) Cancel(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot cancel message with ID [%s]", messageID))
+	}
+	return shard.Cancel(ctx, userID, messageID, timestamp)
+}
+
+func (repository *regionalMessageRepository) UpdateFlags(ctx context.Context, userID entities.UserID, messageID uuid.UUID, isStarred *bool, needsFollowUp *bool) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot update the flags of message with ID [%s]", messageID))
+	}
+	return shard.UpdateFlags(ctx, userID, messageID, isStarred, needsFollowUp)
+}
+
+func (repository *regionalMessageRepository) FetchPastExpiry(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	messages, err := repository.defaultShard.FetchPastExpiry(ctx, timestamp, limit)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot fetch expired messages in the default region")
+	}
+
+	for region, shard := range repository.shards {
+		regionMessages, err := shard.FetchPastExpiry(ctx, timestamp, limit)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch expired messages in data region [%s]", region))
+		}
+		messages = append(messages, regionMessages...)
+	}
+	return messages, nil
+}
+
+func (repository *regionalMessageRepository) Expire(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot expire message with ID [%s]", messageID))
+	}
+	return shard.Expire(ctx, userID, messageID, timestamp)
+}
+
+func (repository *regionalMessageRepository) FailOver(ctx context.Context, userID entities.UserID, messageID uuid.UUID, owner string, backup *entities.Phone, timestamp time.Time) (*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fail over message with ID [%s]", messageID))
+	}
+	return shard.FailOver(ctx, userID, messageID, owner, backup, timestamp)
+}
+
+func (repository *regionalMessageRepository) DeleteBefore(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error) {
+	count, err := repository.defaultShard.DeleteBefore(ctx, category, timestamp, limit)
+	if err != nil {
+		return count, stacktrace.Propagate(err, fmt.Sprintf("cannot delete [%s] messages in the default region", category))
+	}
+
+	for region, shard := range repository.shards {
+		regionCount, err := shard.DeleteBefore(ctx, category, timestamp, limit)
+		if err != nil {
+			return count, stacktrace.Propagate(err, fmt.Sprintf("cannot delete [%s] messages in data region [%s]", category, region))
+		}
+		count += regionCount
+	}
+	return count, nil
+}
+
+func (repository *regionalMessageRepository) Redact(ctx context.Context, category entities.MessageCategory, timestamp time.Time, limit int) (int64, error) {
+	count, err := repository.defaultShard.Redact(ctx, category, timestamp, limit)
+	if err != nil {
+		return count, stacktrace.Propagate(err, fmt.Sprintf("cannot redact [%s] messages in the default region", category))
+	}
+
+	for region, shard := range repository.shards {
+		regionCount, err := shard.Redact(ctx, category, timestamp, limit)
+		if err != nil {
+			return count, stacktrace.Propagate(err, fmt.Sprintf("cannot redact [%s] messages in data region [%s]", category, region))
+		}
+		count += regionCount
+	}
+	return count, nil
+}
+
+func (repository *regionalMessageRepository) FetchReceivedSince(ctx context.Context, userID entities.UserID, owner string, contact string, timestamp time.Time, limit int) ([]*entities.Message, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch messages received by [%s] from [%s] for user [%s]", owner, contact, userID))
+	}
+	return shard.FetchReceivedSince(ctx, userID, owner, contact, timestamp, limit)
+}
+
+func (repository *regionalMessageRepository) CountFailures(ctx context.Context, userID entities.UserID, from time.Time, to time.Time) ([]*entities.MessageFailureCount, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count failed messages for user [%s]", userID))
+	}
+	return shard.CountFailures(ctx, userID, from, to)
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// regionalMessageThreadRepository routes queries for entities.MessageThread to the database in the data region of the user
+type regionalMessageThreadRepository struct {
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	router       DataRegionRouter
+	defaultShard MessageThreadRepository
+	shards       map[string]MessageThreadRepository
+}
+
+// NewRegionalMessageThreadRepository creates a MessageThreadRepository which is sharded by the data region of the user
+func NewRegionalMessageThreadRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	router DataRegionRouter,
+	defaultShard MessageThreadRepository,
+	shards map[string]MessageThreadRepository,
+) MessageThreadRepository {
+	return &regionalMessageThreadRepository{
+		logger:       logger.WithService(fmt.Sprintf("%T", &regionalMessageThreadRepository{})),
+		tracer:       tracer,
+		router:       router,
+		defaultShard: defaultShard,
+		shards:       shards,
+	}
+}
+
+func (repository *regionalMessageThreadRepository) shard(ctx context.Context, userID entities.UserID) (MessageThreadRepository, error) {
+	region, err := repository.router.Region(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot resolve data region for user with ID [%s]", userID))
+	}
+
+	if region == "" {
+		return repository.defaultShard, nil
+	}
+
+	shard, ok := repository.shards[region]
+	if !ok {
+		return nil, stacktrace.NewError(fmt.Sprintf("data region [%s] of user with ID [%s] is not configured", region, userID))
+	}
+	return shard, nil
+}
+
+func (repository *regionalMessageThreadRepository) Store(ctx context.Context, thread *entities.MessageThread) error {
+	shard, err := repository.shard(ctx, thread.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot store message thread with ID [%s]", thread.ID))
+	}
+	return shard.Store(ctx, thread)
+}
+
+func (repository *regionalMessageThreadRepository) Update(ctx context.Context, thread *entities.MessageThread) error {
+	shard, err := repository.shard(ctx, thread.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot update message thread with ID [%s]", thread.ID))
+	}
+	return shard.Update(ctx, thread)
+}
+
+func (repository *regionalMessageThreadRepository) LoadByOwnerContact(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.MessageThread, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load message thread between [%s] and [%s]", owner, contact))
+	}
+	return shard.LoadByOwnerContact(ctx, userID, owner, contact)
+}
+
+func (repository *regionalMessageThreadRepository) Load(ctx context.Context, userID entities.UserID, ID uuid.UUID) (*entities.MessageThread, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load message thread with ID [%s]", ID))
+	}
+	return shard.Load(ctx, userID, ID)
+}
+
+func (repository *regionalMessageThreadRepository) Index(ctx context.Context, userID entities.UserID, owner string, archived bool, label string, topic string, language string, params IndexParams) (*[]entities.MessageThread, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot index message threads for owner [%s]", owner))
+	}
+	return shard.Index(ctx, userID, owner, archived, label, topic, language, params)
+}
+
+func (repository *regionalMessageThreadRepository) UpdateClassification(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, topic string, language *string, classifiedAt time.Time) error {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot update the classification of message thread with ID [%s]", messageThreadID))
+	}
+	return shard.UpdateClassification(ctx, userID, messageThreadID, topic, language, classifiedAt)
+}
+
+func (repository *regionalMessageThreadRepository) CountTopics(ctx context.Context, userID entities.UserID, owner string) ([]*entities.MessageThreadTopicCount, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count the topics of message threads for owner [%s]", owner))
+	}
+	return shard.CountTopics(ctx, userID, owner)
+}
+
+func (repository *regionalMessageThreadRepository) LoadMany(ctx context.Context, userID entities.UserID, IDs []uuid.UUID, olderThan *time.Time, limit int) (*[]entities.MessageThread, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load message threads for user with ID [%s]", userID))
+	}
+	return shard.LoadMany(ctx, userID, IDs, olderThan, limit)
+}
+
+func (repository *regionalMessageThreadRepository) Search(ctx context.Context, userID entities.UserID, owners []string, archived *bool, params IndexParams) (*[]entities.MessageThread, error) {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot search message threads for user with ID [%s]", userID))
+	}
+	return shard.Search(ctx, userID, owners, archived, params)
+}
+
+func (repository *regionalMessageThreadRepository) UpdateAfterDeletedMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot update message thread after deleting message with ID [%s]", messageID))
+	}
+	return shard.UpdateAfterDeletedMessage(ctx, userID, messageID)
+}
+
+func (repository *regionalMessageThreadRepository) Delete(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID) error {
+	shard, err := repository.shard(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete message thread with ID [%s]", messageThreadID))
+	}
+	return shard.Delete(ctx, userID, messageThreadID)
+}
+
+func (repository *regionalMessageThreadRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	// The user may already be deleted when this is called, so we delete from every region instead of resolving the region of the user
+	if err := repository.defaultShard.DeleteAllForUser(ctx, userID); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete all message threads for user with ID [%s] in the default region", userID))
+	}
+
+	for region, shard := range repository.shards {
+		if err := shard.DeleteAllForUser(ctx, userID); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot delete all message threads for user with ID [%s] in data region [%s]", userID, region))
+		}
+	}
+	return nil
+}
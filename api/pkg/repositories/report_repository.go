@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// ReportRepository loads and persists an entities.Report
+type ReportRepository interface {
+	// Save Upsert a new entities.Report
+	Save(ctx context.Context, report *entities.Report) error
+
+	// Index entities.Report by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Report, error)
+
+	// Load an entities.Report by ID.
+	Load(ctx context.Context, userID entities.UserID, reportID uuid.UUID) (*entities.Report, error)
+
+	// Delete an entities.Report
+	Delete(ctx context.Context, userID entities.UserID, reportID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.Report for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+
+	// FetchDue fetches the entities.Report of every user which should have been generated before the timestamp
+	FetchDue(ctx context.Context, timestamp time.Time, limit int) ([]*entities.Report, error)
+
+	// Reschedule moves the run time of an entities.Report from its current value to nextRunAt, it returns ErrCodeNotFound when the report was already rescheduled
+	Reschedule(ctx context.Context, report *entities.Report, nextRunAt time.Time) error
+}
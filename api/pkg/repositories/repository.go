@@ -3,6 +3,7 @@ package repositories
 import (
 	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/palantir/stacktrace"
 )
 
@@ -13,6 +14,20 @@ type IndexParams struct {
 	SortDescending bool   `json:"sort_descending"`
 	Query          string `json:"query"`
 	Limit          int    `json:"take"`
+	// Cursor is the position of the last row of the previous page, Skip is ignored when it is set
+	Cursor *Cursor `json:"cursor"`
+}
+
+// TimeseriesParams are parameters for counting the rows of a database table in time buckets
+type TimeseriesParams struct {
+	From     time.Time
+	To       time.Time
+	Interval time.Duration
+	Owners   []string
+	// SenderNames filters the entities.Message by sender name, it is not supported by the other entities
+	SenderNames []string
+	// Categories filters the entities.Message by category, it is not supported by the other entities
+	Categories []entities.MessageCategory
 }
 
 const (
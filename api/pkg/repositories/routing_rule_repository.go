@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// RoutingRuleRepository loads and persists an entities.RoutingRule
+type RoutingRuleRepository interface {
+	// Save Upsert a new entities.RoutingRule
+	Save(ctx context.Context, rule *entities.RoutingRule) error
+
+	// Index entities.RoutingRule by entities.UserID
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.RoutingRule, error)
+
+	// LoadAll loads all the routing rules of a user in the order in which they are matched
+	LoadAll(ctx context.Context, userID entities.UserID) ([]*entities.RoutingRule, error)
+
+	// Load loads a routing rule by ID.
+	Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.RoutingRule, error)
+
+	// Delete an entities.RoutingRule
+	Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.RoutingRule for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// SuppressionRepository loads and persists an entities.Suppression
+type SuppressionRepository interface {
+	// Store a new entities.Suppression, it is ignored if the contact is already suppressed
+	Store(ctx context.Context, suppression *entities.Suppression) error
+
+	// Exists checks if a contact is suppressed for a phone number
+	Exists(ctx context.Context, userID entities.UserID, owner string, contact string) (bool, error)
+
+	// Delete the entities.Suppression of a contact
+	Delete(ctx context.Context, userID entities.UserID, owner string, contact string) error
+
+	// DeleteAllForUser deletes all entities.Suppression for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
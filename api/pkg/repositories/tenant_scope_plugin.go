@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// ErrCodeTenantScope is thrown when a query on a table which belongs to a user is not scoped by the user
+	ErrCodeTenantScope = stacktrace.ErrorCode(1001)
+
+	tenantScopeColumn  = "user_id"
+	tenantScopeSkipKey = "httpsms:tenant_scope:skip"
+)
+
+// tenantScopeConditionRegex matches a condition which only compares the user_id to a single value e.g. "messages.user_id = ?"
+var tenantScopeConditionRegex = regexp.MustCompile(`^\s*("?[a-z_]+"?\.)?"?user_id"?\s*=\s*\?\s*$`)
+
+// tenantScopePlugin is a gorm.Plugin which guards against queries that can read or modify the data of another user
+type tenantScopePlugin struct {
+	logger telemetry.Logger
+	strict bool
+}
+
+// NewTenantScopePlugin creates a gorm.Plugin which checks that every query, update and delete on a table with a
+// user_id column is filtered by the user_id. When strict is true, unscoped queries fail with ErrCodeTenantScope
+// instead of only being logged.
+func NewTenantScopePlugin(logger telemetry.Logger, strict bool) gorm.Plugin {
+	return &tenantScopePlugin{
+		logger: logger.WithService(fmt.Sprintf("%T", &tenantScopePlugin{})),
+		strict: strict,
+	}
+}
+
+// Name returns the name of the plugin
+func (plugin *tenantScopePlugin) Name() string {
+	return "httpsms:tenant_scope"
+}
+
+// Initialize registers the callbacks of the plugin
+func (plugin *tenantScopePlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register(plugin.Name()+":query", plugin.check("query")); err != nil {
+		return stacktrace.Propagate(err, "cannot register tenant scope query callback")
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register(plugin.Name()+":row", plugin.check("row")); err != nil {
+		return stacktrace.Propagate(err, "cannot register tenant scope row callback")
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register(plugin.Name()+":update", plugin.check("update")); err != nil {
+		return stacktrace.Propagate(err, "cannot register tenant scope update callback")
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register(plugin.Name()+":delete", plugin.check("delete")); err != nil {
+		return stacktrace.Propagate(err, "cannot register tenant scope delete callback")
+	}
+	return nil
+}
+
+func (plugin *tenantScopePlugin) check(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil {
+			return
+		}
+
+		field := db.Statement.Schema.LookUpField(tenantScopeColumn)
+		if field == nil || field.DBName != tenantScopeColumn {
+			return
+		}
+
+		if skip, ok := db.Get(tenantScopeSkipKey); ok && skip == true {
+			return
+		}
+
+		if plugin.isScopedWhere(db.Statement) {
+			return
+		}
+
+		msg := fmt.Sprintf("[%s] on table [%s] is not scoped by [%s]", operation, db.Statement.Table, tenantScopeColumn)
+		if plugin.strict {
+			_ = db.AddError(stacktrace.NewErrorWithCode(ErrCodeTenantScope, msg))
+			return
+		}
+		plugin.logger.Error(stacktrace.NewErrorWithCode(ErrCodeTenantScope, msg))
+	}
+}
+
+// isScopedWhere checks if the WHERE clause of the statement compares the user_id to a single value. Raw SQL cannot be
+// checked so it is never scoped and the query must be built with gorm or marked with skipTenantScope.
+func (plugin *tenantScopePlugin) isScopedWhere(statement *gorm.Statement) bool {
+	if statement.SQL.Len() > 0 {
+		return false
+	}
+
+	where, ok := statement.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok {
+		return false
+	}
+	return plugin.containsTenantScope(where.Exprs)
+}
+
+// containsTenantScope checks if one of the AND conditions is a "user_id = ?" equality. OR conditions are not considered
+// as a scope because any of the branches can match rows of another user.
+func (plugin *tenantScopePlugin) containsTenantScope(expressions []clause.Expression) bool {
+	for _, expression := range expressions {
+		switch value := expression.(type) {
+		case clause.Expr:
+			if tenantScopeConditionRegex.MatchString(value.SQL) && len(value.Vars) == 1 && plugin.isTenantValue(value.Vars[0]) {
+				return true
+			}
+		case clause.Eq:
+			if plugin.isTenantColumn(value.Column) && plugin.isTenantValue(value.Value) {
+				return true
+			}
+		case clause.AndConditions:
+			if plugin.containsTenantScope(value.Exprs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (plugin *tenantScopePlugin) isTenantColumn(column any) bool {
+	switch value := column.(type) {
+	case string:
+		return tenantScopeConditionRegex.MatchString(value + " = ?")
+	case clause.Column:
+		return value.Name == tenantScopeColumn && !value.Raw
+	}
+	return false
+}
+
+// isTenantValue checks if the value which the user_id is compared to is a single user e.g. not nil, a list or a subquery
+func (plugin *tenantScopePlugin) isTenantValue(value any) bool {
+	if value == nil {
+		return false
+	}
+
+	switch value.(type) {
+	case clause.Expression, *gorm.DB:
+		return false
+	}
+
+	kind := reflect.Indirect(reflect.ValueOf(value)).Kind()
+	return kind == reflect.String && reflect.Indirect(reflect.ValueOf(value)).String() != ""
+}
+
+// skipTenantScope marks a query which is intentionally not scoped by a user e.g. when looking up a discord server
+func skipTenantScope(db *gorm.DB) *gorm.DB {
+	return db.Set(tenantScopeSkipKey, true)
+}
@@ -0,0 +1,612 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/hirosassa/zerodriver"
+	"github.com/palantir/stacktrace"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestTenantScopePlugin(t *testing.T) {
+	t.Run("queries without the user_id are rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		var messages []entities.Message
+		err := db.Where("owner = ?", "+18005550199").Find(&messages).Error
+
+		// Assert
+		assert.Equal(t, ErrCodeTenantScope, stacktrace.GetCode(err))
+	})
+
+	t.Run("queries with the user_id in an OR condition are rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		var messages []entities.Message
+		err := db.Where("user_id = ? OR owner = ?", "user-id", "+18005550199").Find(&messages).Error
+
+		// Assert
+		assert.Equal(t, ErrCodeTenantScope, stacktrace.GetCode(err))
+	})
+
+	t.Run("queries which only mention the user_id are rejected", func(t *testing.T) {
+		queries := map[string]func(db *gorm.DB) *gorm.DB{
+			"select":   func(db *gorm.DB) *gorm.DB { return db.Select("user_id").Where("owner = ?", "+18005550199") },
+			"not null": func(db *gorm.DB) *gorm.DB { return db.Where("user_id IS NOT NULL") },
+			"join":     func(db *gorm.DB) *gorm.DB { return db.Joins("JOIN phones ON phones.user_id = messages.user_id") },
+			"in":       func(db *gorm.DB) *gorm.DB { return db.Where("user_id IN ?", []string{"user-a", "user-b"}) },
+			"empty":    func(db *gorm.DB) *gorm.DB { return db.Where("user_id = ?", "") },
+			"raw": func(db *gorm.DB) *gorm.DB {
+				return db.Raw("SELECT * FROM messages WHERE user_id = ?", "user-a")
+			},
+		}
+
+		for name, query := range queries {
+			t.Run(name, func(t *testing.T) {
+				// Setup
+				t.Parallel()
+
+				// Arrange
+				db, _, _ := tenantScopeTestDB(t)
+
+				// Act
+				var messages []entities.Message
+				err := query(db).Find(&messages).Error
+
+				// Assert
+				assert.Equal(t, ErrCodeTenantScope, stacktrace.GetCode(err))
+			})
+		}
+	})
+
+	t.Run("queries with a user_id equality are allowed", func(t *testing.T) {
+		queries := map[string]func(db *gorm.DB) *gorm.DB{
+			"expression": func(db *gorm.DB) *gorm.DB { return db.Where("user_id = ?", "user-a") },
+			"table":      func(db *gorm.DB) *gorm.DB { return db.Where("messages.user_id = ?", "user-a") },
+			"struct":     func(db *gorm.DB) *gorm.DB { return db.Where(&entities.Message{UserID: "user-a"}) },
+			"and": func(db *gorm.DB) *gorm.DB {
+				return db.Where("owner = ?", "+18005550199").Where(db.Where("user_id = ?", "user-a"))
+			},
+		}
+
+		for name, query := range queries {
+			t.Run(name, func(t *testing.T) {
+				// Setup
+				t.Parallel()
+
+				// Arrange
+				db, _, _ := tenantScopeTestDB(t)
+
+				// Act
+				var messages []entities.Message
+				tx := query(db).Find(&messages)
+
+				// Assert
+				assert.NoError(t, tx.Error)
+				assert.Contains(t, tx.Statement.SQL.String(), "user_id")
+			})
+		}
+	})
+
+	t.Run("updates of a model with a user_id are rejected when the WHERE has no user_id", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		err := db.Save(&entities.Message{ID: uuid.New(), UserID: "user-a"}).Error
+
+		// Assert
+		assert.Equal(t, ErrCodeTenantScope, stacktrace.GetCode(err))
+	})
+
+	t.Run("updates of a model are allowed when the WHERE has the user_id", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		err := db.Where("user_id = ?", "user-a").Save(&entities.Message{ID: uuid.New(), UserID: "user-a"}).Error
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("tables without a user_id are not checked", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		var rules []entities.ComplianceRule
+		err := db.Find(&rules).Error
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("queries marked with skipTenantScope are allowed", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		db, _, _ := tenantScopeTestDB(t)
+
+		// Act
+		var messages []entities.Message
+		err := skipTenantScope(db).Where("owner = ?", "+18005550199").Find(&messages).Error
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
+
+func TestTenantScopePlugin_Repositories(t *testing.T) {
+	db, logger, tracer := tenantScopeTestDB(t)
+
+	messages := NewGormMessageRepository(logger, tracer, db)
+	threads := NewGormMessageThreadRepository(logger, tracer, db)
+	phones := NewGormPhoneRepository(logger, tracer, db)
+	webhooks := NewGormWebhookRepository(logger, tracer, db)
+	discords := NewGormDiscordRepository(logger, tracer, db)
+	channels := NewGormNotificationChannelRepository(logger, tracer, db)
+	inboxRules := NewGormInboxRuleRepository(logger, tracer, db)
+	routingRules := NewGormRoutingRuleRepository(logger, tracer, db)
+	phonePools := NewGormPhonePoolRepository(logger, tracer, db)
+	attachments := NewGormAttachmentRepository(logger, tracer, db)
+	reports := NewGormReportRepository(logger, tracer, db)
+	customEvents := NewGormCustomEventRepository(logger, tracer, db)
+	webhookEvents := NewGormWebhookEventRepository(logger, tracer, db)
+	contactPreferences := NewGormContactPreferenceRepository(logger, tracer, db)
+	apiKeys := NewGormAPIKeyRepository(logger, tracer, db)
+	customDomains := NewGormCustomDomainRepository(logger, tracer, db)
+	debugRequests := NewGormDebugRequestRepository(logger, tracer, db)
+	phoneLogs := NewGormPhoneLogRepository(logger, tracer, db)
+	phoneCrashes := NewGormPhoneCrashRepository(logger, tracer, db)
+	maintenanceWindows := NewGormPhoneMaintenanceWindowRepository(logger, tracer, db)
+	phoneBalances := NewGormPhoneBalanceRepository(logger, tracer, db)
+	heartbeats := NewGormHeartbeatRepository(logger, tracer, db)
+	heartbeatRollups := NewGormHeartbeatRollupRepository(logger, tracer, db)
+	monitors := NewGormHeartbeatMonitorRepository(logger, tracer, db)
+	notifications := NewGormPhoneNotificationRepository(logger, tracer, db)
+	integrations := NewGormIntegration3CXRepository(logger, tracer, db)
+	suppressions := NewGormSuppressionRepository(logger, tracer, db)
+	billing := NewGormBillingUsageRepository(logger, tracer, db)
+
+	cases := map[string]func(ctx context.Context, userID entities.UserID) error{
+		"MessageRepository.Update": func(ctx context.Context, userID entities.UserID) error {
+			return messages.Update(ctx, &entities.Message{ID: uuid.New(), UserID: userID})
+		},
+		"MessageRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"MessageRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			starred := true
+			_, err := messages.Index(ctx, userID, "+18005550199", "+18005550100", &starred, nil, IndexParams{Limit: 10, Query: "hello"})
+			return err
+		},
+		"MessageRepository.Timeline": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.Timeline(ctx, userID, "+18005550100", IndexParams{Limit: 10})
+			return err
+		},
+		"MessageRepository.History": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.History(ctx, userID, "+18005550199", "+18005550100", IndexParams{Limit: 10})
+			return err
+		},
+		"MessageRepository.LastMessage": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.LastMessage(ctx, userID, "+18005550199", "+18005550100")
+			return err
+		},
+		"MessageRepository.Search": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.Search(ctx, userID, []string{"+18005550199"}, nil, nil, nil, IndexParams{Limit: 10, Query: "hello"})
+			return err
+		},
+		"MessageRepository.Queue": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.Queue(ctx, userID, "+18005550199", 10)
+			return err
+		},
+		"MessageRepository.FetchReceivedSince": func(ctx context.Context, userID entities.UserID) error {
+			_, err := messages.FetchReceivedSince(ctx, userID, "+18005550199", "+18005550100", time.Now().Add(-time.Hour), 10)
+			return err
+		},
+		"MessageRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return messages.Delete(ctx, userID, uuid.New())
+		},
+		"MessageRepository.DeleteByOwnerAndContact": func(ctx context.Context, userID entities.UserID) error {
+			return messages.DeleteByOwnerAndContact(ctx, userID, "+18005550199", "+18005550100")
+		},
+		"MessageRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return messages.DeleteAllForUser(ctx, userID)
+		},
+		"MessageThreadRepository.Update": func(ctx context.Context, userID entities.UserID) error {
+			return threads.Update(ctx, &entities.MessageThread{ID: uuid.New(), UserID: userID})
+		},
+		"MessageThreadRepository.LoadByOwnerContact": func(ctx context.Context, userID entities.UserID) error {
+			_, err := threads.LoadByOwnerContact(ctx, userID, "+18005550199", "+18005550100")
+			return err
+		},
+		"MessageThreadRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := threads.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"MessageThreadRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := threads.Index(ctx, userID, "+18005550199", true, "support", "billing", "en", IndexParams{Limit: 10, Query: "hello"})
+			return err
+		},
+		"MessageThreadRepository.UpdateClassification": func(ctx context.Context, userID entities.UserID) error {
+			language := "en"
+			return threads.UpdateClassification(ctx, userID, uuid.New(), "billing", &language, time.Now().UTC())
+		},
+		"MessageThreadRepository.Search": func(ctx context.Context, userID entities.UserID) error {
+			_, err := threads.Search(ctx, userID, []string{"+18005550199"}, nil, IndexParams{Limit: 10, Query: "(800) 555-0100"})
+			return err
+		},
+		"MessageThreadRepository.LoadMany": func(ctx context.Context, userID entities.UserID) error {
+			olderThan := time.Now().UTC()
+			_, err := threads.LoadMany(ctx, userID, []uuid.UUID{uuid.New()}, &olderThan, 10)
+			return err
+		},
+		"MessageThreadRepository.UpdateAfterDeletedMessage": func(ctx context.Context, userID entities.UserID) error {
+			return threads.UpdateAfterDeletedMessage(ctx, userID, uuid.New())
+		},
+		"MessageThreadRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return threads.Delete(ctx, userID, uuid.New())
+		},
+		"PhoneRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phones.Index(ctx, userID, IndexParams{Limit: 10, Query: "+1800"})
+			return err
+		},
+		"PhoneRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phones.Load(ctx, userID, "+18005550199")
+			return err
+		},
+		"PhoneRepository.LoadByID": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phones.LoadByID(ctx, userID, uuid.New())
+			return err
+		},
+		"PhoneRepository.UpdateSocketConnectedAt": func(ctx context.Context, userID entities.UserID) error {
+			return phones.UpdateSocketConnectedAt(ctx, userID, uuid.New(), nil)
+		},
+		"PhoneRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return phones.Delete(ctx, userID, uuid.New())
+		},
+		"WebhookRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := webhooks.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"WebhookRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := webhooks.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"WebhookRepository.LoadByEvent": func(ctx context.Context, userID entities.UserID) error {
+			_, err := webhooks.LoadByEvent(ctx, userID, "message.phone.received", "+18005550199")
+			return err
+		},
+		"WebhookRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return webhooks.Delete(ctx, userID, uuid.New())
+		},
+		"WebhookRepository.RecordFailure": func(ctx context.Context, userID entities.UserID) error {
+			_, err := webhooks.RecordFailure(ctx, userID, uuid.New())
+			return err
+		},
+		"WebhookRepository.ResetFailures": func(ctx context.Context, userID entities.UserID) error {
+			return webhooks.ResetFailures(ctx, userID, uuid.New())
+		},
+		"WebhookEventRepository.LoadByTimestamp": func(ctx context.Context, userID entities.UserID) error {
+			_, err := webhookEvents.LoadByTimestamp(ctx, userID, time.Now().Add(-time.Hour), time.Now(), []string{"message.phone.received"}, []string{"+18005550199"}, 10)
+			return err
+		},
+		"WebhookEventRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return webhookEvents.DeleteAllForUser(ctx, userID)
+		},
+		"ContactPreferenceRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := contactPreferences.Load(ctx, userID, "+18005550100")
+			return err
+		},
+		"ContactPreferenceRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return contactPreferences.Delete(ctx, userID, "+18005550100")
+		},
+		"ContactPreferenceRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return contactPreferences.DeleteAllForUser(ctx, userID)
+		},
+		"APIKeyRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := apiKeys.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"APIKeyRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := apiKeys.Index(ctx, userID)
+			return err
+		},
+		"APIKeyRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return apiKeys.Delete(ctx, userID, uuid.New())
+		},
+		"APIKeyRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return apiKeys.DeleteAllForUser(ctx, userID)
+		},
+		"CustomDomainRepository.Save": func(ctx context.Context, userID entities.UserID) error {
+			return customDomains.Save(ctx, &entities.CustomDomain{ID: uuid.New(), UserID: userID, Domain: "sms.example.com"})
+		},
+		"CustomDomainRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := customDomains.Load(ctx, userID)
+			return err
+		},
+		"CustomDomainRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return customDomains.Delete(ctx, userID)
+		},
+		"DiscordRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := discords.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"DiscordRepository.FetchHavingIncomingChannel": func(ctx context.Context, userID entities.UserID) error {
+			_, err := discords.FetchHavingIncomingChannel(ctx, userID)
+			return err
+		},
+		"DiscordRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := discords.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"DiscordRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return discords.Delete(ctx, userID, uuid.New())
+		},
+		"NotificationChannelRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := channels.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"NotificationChannelRepository.LoadByEvent": func(ctx context.Context, userID entities.UserID) error {
+			_, err := channels.LoadByEvent(ctx, userID, "phone.heartbeat.offline")
+			return err
+		},
+		"NotificationChannelRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := channels.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"NotificationChannelRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return channels.Delete(ctx, userID, uuid.New())
+		},
+		"InboxRuleRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := inboxRules.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"InboxRuleRepository.LoadAll": func(ctx context.Context, userID entities.UserID) error {
+			_, err := inboxRules.LoadAll(ctx, userID)
+			return err
+		},
+		"InboxRuleRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := inboxRules.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"InboxRuleRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return inboxRules.Delete(ctx, userID, uuid.New())
+		},
+		"RoutingRuleRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := routingRules.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"RoutingRuleRepository.LoadAll": func(ctx context.Context, userID entities.UserID) error {
+			_, err := routingRules.LoadAll(ctx, userID)
+			return err
+		},
+		"RoutingRuleRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := routingRules.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"RoutingRuleRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return routingRules.Delete(ctx, userID, uuid.New())
+		},
+		"RoutingRuleRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return routingRules.DeleteAllForUser(ctx, userID)
+		},
+		"PhonePoolRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phonePools.Index(ctx, userID, IndexParams{Limit: 10, Query: "example"})
+			return err
+		},
+		"PhonePoolRepository.LoadAll": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phonePools.LoadAll(ctx, userID)
+			return err
+		},
+		"PhonePoolRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phonePools.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"PhonePoolRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return phonePools.Delete(ctx, userID, uuid.New())
+		},
+		"PhonePoolRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return phonePools.DeleteAllForUser(ctx, userID)
+		},
+		"AttachmentRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := attachments.Index(ctx, userID, IndexParams{Limit: 10, Query: "image"})
+			return err
+		},
+		"AttachmentRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := attachments.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"AttachmentRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return attachments.Delete(ctx, userID, uuid.New())
+		},
+		"ReportRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := reports.Index(ctx, userID, IndexParams{Limit: 10, Query: "daily"})
+			return err
+		},
+		"ReportRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := reports.Load(ctx, userID, uuid.New())
+			return err
+		},
+		"ReportRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return reports.Delete(ctx, userID, uuid.New())
+		},
+		"ReportRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return reports.DeleteAllForUser(ctx, userID)
+		},
+		"CustomEventRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := customEvents.Index(ctx, userID, IndexParams{Limit: 10, Query: "custom.order"})
+			return err
+		},
+		"CustomEventRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return customEvents.DeleteAllForUser(ctx, userID)
+		},
+		"DebugRequestRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := debugRequests.Index(ctx, userID, IndexParams{Limit: 10, Query: "/v1/messages"})
+			return err
+		},
+		"DebugRequestRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return debugRequests.DeleteAllForUser(ctx, userID)
+		},
+		"PhoneLogRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phoneLogs.Index(ctx, userID, uuid.New(), IndexParams{Limit: 10})
+			return err
+		},
+		"PhoneLogRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return phoneLogs.DeleteAllForUser(ctx, userID)
+		},
+		"PhoneCrashRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phoneCrashes.Index(ctx, userID, uuid.New(), IndexParams{Limit: 10, Query: "NullPointerException"})
+			return err
+		},
+		"PhoneCrashRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return phoneCrashes.DeleteAllForUser(ctx, userID)
+		},
+		"PhoneMaintenanceWindowRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := maintenanceWindows.Index(ctx, userID, uuid.New(), IndexParams{Limit: 10, Query: "update"})
+			return err
+		},
+		"PhoneMaintenanceWindowRepository.LoadBetween": func(ctx context.Context, userID entities.UserID) error {
+			_, err := maintenanceWindows.LoadBetween(ctx, userID, []string{"+18005550199"}, time.Now().Add(-time.Hour), time.Now())
+			return err
+		},
+		"PhoneMaintenanceWindowRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := maintenanceWindows.Load(ctx, userID, uuid.New(), uuid.New())
+			return err
+		},
+		"PhoneMaintenanceWindowRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return maintenanceWindows.Delete(ctx, userID, uuid.New())
+		},
+		"PhoneMaintenanceWindowRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return maintenanceWindows.DeleteAllForUser(ctx, userID)
+		},
+		"PhoneBalanceRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phoneBalances.Index(ctx, userID, uuid.New(), IndexParams{Limit: 10, Query: "USD"})
+			return err
+		},
+		"PhoneBalanceRepository.LoadLatest": func(ctx context.Context, userID entities.UserID) error {
+			_, err := phoneBalances.LoadLatest(ctx, userID, uuid.New())
+			return err
+		},
+		"PhoneBalanceRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return phoneBalances.DeleteAllForUser(ctx, userID)
+		},
+		"HeartbeatRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := heartbeats.Index(ctx, userID, "+18005550199", IndexParams{Limit: 10, Query: "1.0"})
+			return err
+		},
+		"HeartbeatRepository.Last": func(ctx context.Context, userID entities.UserID) error {
+			_, err := heartbeats.Last(ctx, userID, "+18005550199")
+			return err
+		},
+		"HeartbeatRepository.Timestamps": func(ctx context.Context, userID entities.UserID) error {
+			_, err := heartbeats.Timestamps(ctx, userID, "+18005550199", time.Now().Add(-time.Hour), time.Now())
+			return err
+		},
+		"HeartbeatRollupRepository.Index": func(ctx context.Context, userID entities.UserID) error {
+			_, err := heartbeatRollups.Index(ctx, userID, "+18005550199", entities.HeartbeatRollupResolutionHour, IndexParams{Limit: 10})
+			return err
+		},
+		"HeartbeatRollupRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return heartbeatRollups.DeleteAllForUser(ctx, userID)
+		},
+		"HeartbeatMonitorRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := monitors.Load(ctx, userID, "+18005550199")
+			return err
+		},
+		"HeartbeatMonitorRepository.Exists": func(ctx context.Context, userID entities.UserID) error {
+			_, err := monitors.Exists(ctx, userID, uuid.New())
+			return err
+		},
+		"HeartbeatMonitorRepository.UpdatePhoneOnline": func(ctx context.Context, userID entities.UserID) error {
+			return monitors.UpdatePhoneOnline(ctx, userID, uuid.New(), true)
+		},
+		"HeartbeatMonitorRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return monitors.Delete(ctx, userID, "+18005550199")
+		},
+		"PhoneNotificationRepository.DeleteAllForUser": func(ctx context.Context, userID entities.UserID) error {
+			return notifications.DeleteAllForUser(ctx, userID)
+		},
+		"Integration3CXRepository.Load": func(ctx context.Context, userID entities.UserID) error {
+			_, err := integrations.Load(ctx, userID)
+			return err
+		},
+		"SuppressionRepository.Exists": func(ctx context.Context, userID entities.UserID) error {
+			_, err := suppressions.Exists(ctx, userID, "+18005550199", "+18005550100")
+			return err
+		},
+		"SuppressionRepository.Delete": func(ctx context.Context, userID entities.UserID) error {
+			return suppressions.Delete(ctx, userID, "+18005550199", "+18005550100")
+		},
+		"BillingUsageRepository.GetHistory": func(ctx context.Context, userID entities.UserID) error {
+			_, err := billing.GetHistory(ctx, userID, IndexParams{Limit: 10})
+			return err
+		},
+	}
+
+	for name, query := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			t.Parallel()
+
+			for i := 0; i < 10; i++ {
+				// Arrange
+				userID := entities.UserID(uuid.NewString())
+
+				// Act
+				err := query(context.Background(), userID)
+
+				// Assert
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func tenantScopeTestDB(t *testing.T) (*gorm.DB, telemetry.Logger, telemetry.Tracer) {
+	zerologLogger := zerolog.New(os.Stderr).Level(zerolog.Disabled)
+	logger := telemetry.NewZerologLogger("test", map[string]string{}, &zerodriver.Logger{Logger: &zerologLogger}, nil)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		NowFunc:                func() time.Time { return time.Now().UTC() },
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewTenantScopePlugin(logger, true)))
+
+	return db, logger, telemetry.NewOtelLogger("test", logger)
+}
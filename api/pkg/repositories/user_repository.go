@@ -17,6 +17,9 @@ type UserRepository interface {
 	// LoadAuthUser fetches an entities.AuthUser by apiKey
 	LoadAuthUser(ctx context.Context, apiKey string) (entities.AuthUser, error)
 
+	// LoadAuthUserByID fetches the entities.AuthUser of the entities.User with the ID of a verified identity token
+	LoadAuthUserByID(ctx context.Context, userID entities.UserID) (entities.AuthUser, error)
+
 	// Load an entities.User by entities.UserID
 	Load(ctx context.Context, userID entities.UserID) (*entities.User, error)
 
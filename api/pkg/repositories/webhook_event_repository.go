@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// WebhookEventRepository loads and persists an entities.WebhookEvent
+type WebhookEventRepository interface {
+	// Store a new entities.WebhookEvent
+	Store(ctx context.Context, event *entities.WebhookEvent) error
+
+	// LoadByTimestamp loads at most limit entities.WebhookEvent of the types which happened between from and to ordered by timestamp.
+	// The events are not filtered by owner when owners is empty and the events without an owner are always loaded.
+	LoadByTimestamp(ctx context.Context, userID entities.UserID, from time.Time, to time.Time, types []string, owners []string, limit int) ([]*entities.WebhookEvent, error)
+
+	// DeleteBefore deletes at most limit entities.WebhookEvent of every user which happened before the timestamp
+	DeleteBefore(ctx context.Context, timestamp time.Time, limit int) (int64, error)
+
+	// DeleteAllForUser deletes all entities.WebhookEvent for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -16,12 +17,21 @@ type WebhookRepository interface {
 	// Index entities.Webhook by entities.UserID
 	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.Webhook, error)
 
-	// LoadByEvent loads webhooks for a user and event.
+	// LoadByEvent loads webhooks for a user and event, the webhooks are not filtered by phone number when it is empty.
 	LoadByEvent(ctx context.Context, userID entities.UserID, event string, phoneNumber string) ([]*entities.Webhook, error)
 
 	// Load loads a webhook by ID.
 	Load(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (*entities.Webhook, error)
 
+	// RecordFailure increments the number of consecutive failures of an entities.Webhook and returns the new count
+	RecordFailure(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (uint, error)
+
+	// ResetFailures sets the number of consecutive failures of an entities.Webhook to 0
+	ResetFailures(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error
+
+	// Pause an entities.Webhook which is not paused, ErrCodeNotFound is returned when the webhook is already paused
+	Pause(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, timestamp time.Time, reason string) error
+
 	// Delete an entities.Webhook
 	Delete(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error
 
@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// APIKeyStore is the payload for creating a new entities.APIKey
+type APIKeyStore struct {
+	request
+	Name string `json:"name" example:"Website contact form"`
+	// Scopes are the permissions of the key which are "send", "read" or "admin"
+	Scopes []string `json:"scopes" example:"send"`
+	// DefaultPhoneID is the optional ID of the phone which sends the messages of the key that do not have a from number
+	DefaultPhoneID string `json:"default_phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+}
+
+// Sanitize sets defaults to APIKeyStore
+func (input *APIKeyStore) Sanitize() APIKeyStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.DefaultPhoneID = strings.TrimSpace(input.DefaultPhoneID)
+
+	var scopes []string
+	for _, scope := range input.Scopes {
+		if scope = strings.ToLower(strings.TrimSpace(scope)); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	input.Scopes = input.removeStringDuplicates(scopes)
+
+	return *input
+}
+
+// ToStoreParams converts APIKeyStore to services.APIKeyStoreParams
+func (input *APIKeyStore) ToStoreParams(userID entities.UserID) *services.APIKeyStoreParams {
+	var defaultPhoneID *uuid.UUID
+	if input.DefaultPhoneID != "" {
+		val := uuid.MustParse(input.DefaultPhoneID)
+		defaultPhoneID = &val
+	}
+
+	return &services.APIKeyStoreParams{
+		UserID:         userID,
+		Name:           input.Name,
+		Scopes:         input.Scopes,
+		DefaultPhoneID: defaultPhoneID,
+	}
+}
@@ -23,7 +23,7 @@ type BulkMessage struct {
 // Sanitize sets defaults to BulkMessage
 func (input *BulkMessage) Sanitize() *BulkMessage {
 	input.ToPhoneNumber = input.sanitizeAddress(input.ToPhoneNumber)
-	input.Content = strings.TrimSpace(input.Content)
+	input.Content = input.sanitizeContent(strings.TrimSpace(input.Content))
 	input.FromPhoneNumber = input.sanitizeAddress(input.FromPhoneNumber)
 	return input
 }
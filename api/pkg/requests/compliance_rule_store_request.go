@@ -0,0 +1,60 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ComplianceRuleStore is the payload for creating a new entities.ComplianceRule
+type ComplianceRuleStore struct {
+	request
+	CountryCode          string   `json:"country_code" example:"US"`
+	OptOutFooterRequired bool     `json:"opt_out_footer_required" example:"true"`
+	OptOutKeywords       []string `json:"opt_out_keywords" example:"STOP"`
+	ForbiddenHoursStart  *uint    `json:"forbidden_hours_start" example:"21" validate:"optional"`
+	ForbiddenHoursEnd    *uint    `json:"forbidden_hours_end" example:"8" validate:"optional"`
+	Timezone             string   `json:"timezone" example:"America/New_York"`
+	ForbiddenContent     []string `json:"forbidden_content" example:"casino"`
+	ForbiddenCategories  []string `json:"forbidden_categories" example:"marketing"`
+}
+
+// Sanitize sets defaults to ComplianceRuleStore
+func (input *ComplianceRuleStore) Sanitize() ComplianceRuleStore {
+	input.CountryCode = strings.ToUpper(strings.TrimSpace(input.CountryCode))
+	input.OptOutKeywords = input.sanitizeList(input.OptOutKeywords, strings.TrimSpace)
+	input.Timezone = strings.TrimSpace(input.Timezone)
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+	input.ForbiddenContent = input.sanitizeList(input.ForbiddenContent, strings.TrimSpace)
+	input.ForbiddenCategories = input.sanitizeList(input.ForbiddenCategories, func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	})
+	return *input
+}
+
+// ToStoreParams converts ComplianceRuleStore to services.ComplianceRuleStoreParams
+func (input *ComplianceRuleStore) ToStoreParams() *services.ComplianceRuleStoreParams {
+	return &services.ComplianceRuleStoreParams{
+		CountryCode:          input.CountryCode,
+		OptOutFooterRequired: input.OptOutFooterRequired,
+		OptOutKeywords:       input.OptOutKeywords,
+		ForbiddenHoursStart:  input.ForbiddenHoursStart,
+		ForbiddenHoursEnd:    input.ForbiddenHoursEnd,
+		Timezone:             input.Timezone,
+		ForbiddenContent:     input.ForbiddenContent,
+		ForbiddenCategories:  input.ForbiddenCategories,
+	}
+}
+
+// sanitizeList cleans the values of a list and removes the empty values
+func (input *ComplianceRuleStore) sanitizeList(values []string, sanitize func(string) string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = sanitize(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
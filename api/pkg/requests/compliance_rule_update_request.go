@@ -0,0 +1,26 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ComplianceRuleUpdate is the payload for updating an entities.ComplianceRule
+type ComplianceRuleUpdate struct {
+	ComplianceRuleStore
+	RuleID string `json:"ruleID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to ComplianceRuleUpdate
+func (input *ComplianceRuleUpdate) Sanitize() ComplianceRuleUpdate {
+	input.ComplianceRuleStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts ComplianceRuleUpdate to services.ComplianceRuleUpdateParams
+func (input *ComplianceRuleUpdate) ToUpdateParams() *services.ComplianceRuleUpdateParams {
+	return &services.ComplianceRuleUpdateParams{
+		ComplianceRuleStoreParams: *input.ToStoreParams(),
+		RuleID:                    uuid.MustParse(input.RuleID),
+	}
+}
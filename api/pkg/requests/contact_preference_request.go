@@ -0,0 +1,13 @@
+package requests
+
+// ContactPreference is the payload for fetching or deleting the entities.ContactPreference of a contact
+type ContactPreference struct {
+	request
+	Contact string `json:"contact" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to ContactPreference
+func (input *ContactPreference) Sanitize() ContactPreference {
+	input.Contact = input.sanitizeAddress(input.Contact)
+	return *input
+}
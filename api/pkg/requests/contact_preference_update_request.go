@@ -0,0 +1,56 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactPreferenceUpdate is the payload for updating the entities.ContactPreference of a contact
+type ContactPreferenceUpdate struct {
+	request
+	Contact string `json:"contact" swaggerignore:"true"` // used internally for validation
+	// Language is the language e.g. "fr" which the messages sent to the contact are translated into, leave it empty to send the messages as they are
+	Language string `json:"language" example:"fr"`
+	// DoNotContactCategories are the message categories which cannot be sent to the contact
+	DoNotContactCategories []string `json:"do_not_contact_categories" example:"marketing"`
+	// SendWindowStart is the hour of the day (0-23) in the timezone of the contact from which messages are sent to the contact
+	SendWindowStart *uint `json:"send_window_start" example:"9"`
+	// SendWindowEnd is the hour of the day (0-23) in the timezone of the contact until which messages are sent to the contact
+	SendWindowEnd *uint  `json:"send_window_end" example:"18"`
+	Timezone      string `json:"timezone" example:"Europe/Paris"`
+}
+
+// Sanitize sets defaults to ContactPreferenceUpdate
+func (input *ContactPreferenceUpdate) Sanitize() ContactPreferenceUpdate {
+	input.Contact = input.sanitizeAddress(input.Contact)
+	input.Language = strings.ToLower(strings.TrimSpace(input.Language))
+	input.Timezone = strings.TrimSpace(input.Timezone)
+	if input.Timezone == "" {
+		input.Timezone = "UTC"
+	}
+
+	var categories []string
+	for _, category := range input.DoNotContactCategories {
+		if category = strings.ToLower(strings.TrimSpace(category)); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	input.DoNotContactCategories = input.removeStringDuplicates(categories)
+
+	return *input
+}
+
+// ToUpdateParams converts ContactPreferenceUpdate to services.ContactPreferenceUpdateParams
+func (input *ContactPreferenceUpdate) ToUpdateParams(userID entities.UserID) *services.ContactPreferenceUpdateParams {
+	return &services.ContactPreferenceUpdateParams{
+		UserID:                 userID,
+		Contact:                input.Contact,
+		Language:               input.sanitizeStringPointer(input.Language),
+		DoNotContactCategories: input.DoNotContactCategories,
+		SendWindowStart:        input.SendWindowStart,
+		SendWindowEnd:          input.SendWindowEnd,
+		Timezone:               input.Timezone,
+	}
+}
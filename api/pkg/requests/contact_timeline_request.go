@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// ContactTimeline is the payload for fetching the entities.Message exchanged with a contact by all the phone numbers of a user
+type ContactTimeline struct {
+	request
+	Contact string `json:"contact" swaggerignore:"true"` // used internally for validation
+	Skip    string `json:"skip" query:"skip"`
+	Limit   string `json:"limit" query:"limit"`
+	Cursor  string `json:"cursor" query:"cursor"`
+}
+
+// Sanitize sets defaults to ContactTimeline
+func (input *ContactTimeline) Sanitize() ContactTimeline {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+
+	input.Contact = input.sanitizeAddress(input.Contact)
+	input.Cursor = strings.TrimSpace(input.Cursor)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+
+	return *input
+}
+
+// ToIndexParams converts ContactTimeline to repositories.IndexParams
+func (input *ContactTimeline) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:   input.getInt(input.Skip),
+		Limit:  input.getInt(input.Limit),
+		Cursor: input.getCursor(input.Cursor),
+	}
+}
@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// CustomDomainStore is the payload for setting the entities.CustomDomain of a user
+type CustomDomainStore struct {
+	request
+	Domain string `json:"domain" example:"sms.example.com"`
+}
+
+// Sanitize sets defaults to CustomDomainStore
+func (input *CustomDomainStore) Sanitize() CustomDomainStore {
+	domain := strings.ToLower(strings.TrimSpace(input.Domain))
+	domain = strings.TrimPrefix(strings.TrimPrefix(domain, "https://"), "http://")
+	input.Domain = strings.TrimSuffix(strings.TrimSuffix(domain, "/"), ".")
+	return *input
+}
+
+// ToStoreParams converts CustomDomainStore to services.CustomDomainStoreParams
+func (input *CustomDomainStore) ToStoreParams(userID entities.UserID) *services.CustomDomainStoreParams {
+	return &services.CustomDomainStoreParams{
+		UserID: userID,
+		Domain: input.Domain,
+	}
+}
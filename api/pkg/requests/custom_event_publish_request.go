@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// CustomEventPublish is the payload for publishing a new entities.CustomEvent
+type CustomEventPublish struct {
+	request
+	Type   string          `json:"type" example:"order.shipped"`
+	Source string          `json:"source" example:"/shop/orders"`
+	Data   json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// Sanitize sets defaults to CustomEventPublish
+func (input *CustomEventPublish) Sanitize() CustomEventPublish {
+	input.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	if input.Type != "" && !strings.HasPrefix(input.Type, entities.CustomEventTypePrefix) {
+		input.Type = entities.CustomEventTypePrefix + input.Type
+	}
+	input.Source = strings.TrimSpace(input.Source)
+	return *input
+}
+
+// ToPublishParams converts CustomEventPublish to services.CustomEventPublishParams
+func (input *CustomEventPublish) ToPublishParams(user entities.AuthUser) *services.CustomEventPublishParams {
+	return &services.CustomEventPublishParams{
+		UserID: user.ID,
+		Type:   input.Type,
+		Source: input.Source,
+		Data:   input.Data,
+	}
+}
@@ -0,0 +1,24 @@
+package requests
+
+import (
+	"time"
+)
+
+// DebugModeEnable is the payload for recording the requests of a user for a limited time
+type DebugModeEnable struct {
+	request
+	DurationMinutes uint `json:"duration_minutes" example:"60"`
+}
+
+// Sanitize sets defaults to DebugModeEnable
+func (input *DebugModeEnable) Sanitize() DebugModeEnable {
+	if input.DurationMinutes == 0 {
+		input.DurationMinutes = 60
+	}
+	return *input
+}
+
+// Duration is the time window in which the requests are recorded
+func (input *DebugModeEnable) Duration() time.Duration {
+	return time.Duration(input.DurationMinutes) * time.Minute
+}
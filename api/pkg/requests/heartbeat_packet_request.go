@@ -0,0 +1,26 @@
+package requests
+
+import "strings"
+
+// HeartbeatPacket is the payload of a heartbeat which is sent as a single UDP packet or MQTT message. The packet starts
+// with the hex encoded HMAC-SHA256 of the payload which is signed with the API key of the user, followed by a new line
+// and the payload.
+type HeartbeatPacket struct {
+	HeartbeatStore
+	UserID  string `json:"user_id"`
+	Version string `json:"version"`
+
+	// APIKeyID is the ID of the entities.APIKey which signed the packet, the API key of the account is used when it is empty
+	APIKeyID string `json:"api_key_id"`
+
+	// Timestamp is the unix time in seconds when the packet was signed so that a captured packet cannot be replayed later
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Sanitize sets defaults to HeartbeatPacket
+func (input *HeartbeatPacket) Sanitize() HeartbeatPacket {
+	input.HeartbeatStore = input.HeartbeatStore.Sanitize()
+	input.UserID = strings.TrimSpace(input.UserID)
+	input.APIKeyID = strings.TrimSpace(input.APIKeyID)
+	return *input
+}
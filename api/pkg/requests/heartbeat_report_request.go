@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// HeartbeatReport is the payload for fetching the uptime of a phone which is computed from its entities.Heartbeat
+type HeartbeatReport struct {
+	request
+	Phone string `json:"phone" query:"phone"`
+	// Period is the period ending now which is covered by the report e.g. day, week or month
+	Period string `json:"period" query:"period" example:"week"`
+}
+
+// Sanitize sets defaults to HeartbeatReport
+func (input *HeartbeatReport) Sanitize() HeartbeatReport {
+	input.Phone = input.sanitizeAddress(input.Phone)
+	input.Period = strings.ToLower(strings.TrimSpace(input.Period))
+	if input.Period == "" {
+		input.Period = entities.HeartbeatReportPeriodDay.String()
+	}
+	return *input
+}
+
+// ToReportParams converts HeartbeatReport to services.HeartbeatReportParams
+func (input *HeartbeatReport) ToReportParams(userID entities.UserID) *services.HeartbeatReportParams {
+	return &services.HeartbeatReportParams{
+		UserID: userID,
+		Owner:  input.Phone,
+		Period: entities.HeartbeatReportPeriod(input.Period),
+	}
+}
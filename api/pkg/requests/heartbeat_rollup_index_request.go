@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// HeartbeatRollupIndex is the payload for fetching the entities.HeartbeatRollup of a phone number
+type HeartbeatRollupIndex struct {
+	request
+	Owner string `json:"owner" query:"owner"`
+	// Resolution is the size of the time bucket e.g. hour or day
+	Resolution string `json:"resolution" query:"resolution" example:"hour"`
+	Skip       string `json:"skip" query:"skip"`
+	Limit      string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to HeartbeatRollupIndex
+func (input *HeartbeatRollupIndex) Sanitize() HeartbeatRollupIndex {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	input.Resolution = strings.ToLower(strings.TrimSpace(input.Resolution))
+	if input.Resolution == "" {
+		input.Resolution = entities.HeartbeatRollupResolutionHour.String()
+	}
+	input.Limit = strings.TrimSpace(input.Limit)
+	if input.Limit == "" {
+		input.Limit = "24"
+	}
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ResolutionValue returns the resolution as entities.HeartbeatRollupResolution
+func (input *HeartbeatRollupIndex) ResolutionValue() entities.HeartbeatRollupResolution {
+	return entities.HeartbeatRollupResolution(input.Resolution)
+}
+
+// ToIndexParams converts HeartbeatRollupIndex to repositories.IndexParams
+func (input *HeartbeatRollupIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Limit: input.getInt(input.Limit),
+	}
+}
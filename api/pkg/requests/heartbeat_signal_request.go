@@ -0,0 +1,58 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// HeartbeatSignal is the payload for fetching the signal of the entities.Heartbeat of a phone number in time buckets
+type HeartbeatSignal struct {
+	request
+	Owner string `json:"owner" query:"owner"`
+	// From is the start of the time range in RFC3339 format, it is 24 hours before to when it is empty
+	From string `json:"from" query:"from" example:"2022-06-05T00:00:00Z"`
+	// To is the end of the time range in RFC3339 format, it is the current time when it is empty
+	To string `json:"to" query:"to" example:"2022-06-06T00:00:00Z"`
+	// Interval is the duration of a time bucket in seconds
+	Interval string `json:"interval" query:"interval" example:"3600"`
+}
+
+// Sanitize sets defaults to HeartbeatSignal
+func (input *HeartbeatSignal) Sanitize() HeartbeatSignal {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	input.From = strings.TrimSpace(input.From)
+	input.To = strings.TrimSpace(input.To)
+	input.Interval = strings.TrimSpace(input.Interval)
+	if input.Interval == "" {
+		input.Interval = "3600"
+	}
+	return *input
+}
+
+// FromTime returns the start of the time range which defaults to 24 hours before the end of the time range
+func (input *HeartbeatSignal) FromTime() time.Time {
+	if from, err := time.Parse(time.RFC3339, input.From); err == nil {
+		return from.UTC()
+	}
+	return input.ToTime().Add(-24 * time.Hour)
+}
+
+// ToTime returns the end of the time range which defaults to the current time
+func (input *HeartbeatSignal) ToTime() time.Time {
+	if to, err := time.Parse(time.RFC3339, input.To); err == nil {
+		return to.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// ToTimeseriesParams converts HeartbeatSignal to repositories.TimeseriesParams
+func (input *HeartbeatSignal) ToTimeseriesParams() repositories.TimeseriesParams {
+	return repositories.TimeseriesParams{
+		From:     input.FromTime(),
+		To:       input.ToTime(),
+		Interval: time.Duration(input.getInt(input.Interval)) * time.Second,
+		Owners:   []string{input.Owner},
+	}
+}
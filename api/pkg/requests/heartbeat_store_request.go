@@ -1,6 +1,7 @@
 package requests
 
 import (
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
@@ -13,31 +14,96 @@ type HeartbeatStore struct {
 	Owner        string   `json:"owner" swaggerignore:"true"`
 	Charging     bool     `json:"charging"`
 	PhoneNumbers []string `json:"phone_numbers"`
+
+	// BootedAt is the time when the phone was last restarted
+	BootedAt *time.Time `json:"booted_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	// DozeMode is true when the phone is in doze mode while sending the heartbeat
+	DozeMode bool `json:"doze_mode" example:"false"`
+	// NetworkFailedAt is the first time the app could not send a heartbeat since the last heartbeat was sent
+	NetworkFailedAt *time.Time `json:"network_failed_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+
+	// SignalStrength is the strength of the cellular signal in dBm
+	SignalStrength *int `json:"signal_strength" example:"-85" validate:"optional"`
+	// SignalBars is the level of the cellular signal from 0 to 4 bars which is displayed on the phone
+	SignalBars *uint `json:"signal_bars" example:"3" validate:"optional"`
+	// NetworkType is the type of network the phone is connected to e.g. 2g, 3g, 4g, 5g or wifi
+	NetworkType *string `json:"network_type" example:"4g" validate:"optional"`
+
+	// SIMs are the SIM cards in the SIM slots of the phone
+	SIMs []HeartbeatSIM `json:"sims" validate:"optional"`
+}
+
+// HeartbeatSIM is the SIM card in a SIM slot of the phone which is reported in a heartbeat
+type HeartbeatSIM struct {
+	// SIM is the SIM slot of the SIM card e.g. SIM1 or SIM2
+	SIM string `json:"sim" example:"SIM1"`
+	// PhoneNumber is the phone number of the SIM card, it is empty when the carrier does not store the phone number on the SIM card
+	PhoneNumber string `json:"phone_number" example:"+18005550199" validate:"optional"`
+	// Operator is the name of the carrier of the SIM card
+	Operator string `json:"operator" example:"T-Mobile" validate:"optional"`
+	// ICCID is the serial number of the SIM card
+	ICCID string `json:"iccid" example:"8901260222780116412" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
 func (input *HeartbeatStore) Sanitize() HeartbeatStore {
 	input.Owner = input.sanitizeAddress(input.Owner)
 
+	if input.NetworkType != nil {
+		input.NetworkType = input.sanitizeStringPointer(strings.ToLower(*input.NetworkType))
+	}
+
 	input.PhoneNumbers = input.sanitizeAddresses(input.PhoneNumbers)
 	if len(input.PhoneNumbers) == 0 {
 		input.PhoneNumbers = append(input.PhoneNumbers, input.Owner)
 	}
 
+	for index, sim := range input.SIMs {
+		input.SIMs[index].SIM = input.sanitizeSIM(strings.ToUpper(strings.TrimSpace(sim.SIM)))
+		if phoneNumber := strings.TrimSpace(sim.PhoneNumber); phoneNumber != "" {
+			input.SIMs[index].PhoneNumber = input.sanitizeAddress(phoneNumber)
+		}
+		input.SIMs[index].Operator = strings.TrimSpace(sim.Operator)
+		input.SIMs[index].ICCID = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(sim.ICCID), " ", ""))
+	}
+
 	return *input
 }
 
 // ToStoreParams converts HeartbeatIndex to repositories.IndexParams
 func (input *HeartbeatStore) ToStoreParams(user entities.AuthUser, source string, version string) []services.HeartbeatStoreParams {
+	var networkType *entities.HeartbeatNetworkType
+	if input.NetworkType != nil {
+		value := entities.HeartbeatNetworkType(*input.NetworkType)
+		networkType = &value
+	}
+
+	var sims []services.HeartbeatSIMParams
+	for _, sim := range input.SIMs {
+		sims = append(sims, services.HeartbeatSIMParams{
+			SIM:         entities.SIM(sim.SIM),
+			PhoneNumber: sim.PhoneNumber,
+			Operator:    input.sanitizeStringPointer(sim.Operator),
+			ICCID:       input.sanitizeStringPointer(sim.ICCID),
+		})
+	}
+
 	var params []services.HeartbeatStoreParams
 	for _, phoneNumber := range input.PhoneNumbers {
 		params = append(params, services.HeartbeatStoreParams{
-			Owner:     phoneNumber,
-			Charging:  input.Charging,
-			Source:    source,
-			Version:   version,
-			UserID:    user.ID,
-			Timestamp: time.Now(),
+			Owner:           phoneNumber,
+			Charging:        input.Charging,
+			Source:          source,
+			Version:         version,
+			UserID:          user.ID,
+			Timestamp:       time.Now(),
+			BootedAt:        input.BootedAt,
+			DozeMode:        input.DozeMode,
+			NetworkFailedAt: input.NetworkFailedAt,
+			SignalStrength:  input.SignalStrength,
+			SignalBars:      input.SignalBars,
+			NetworkType:     networkType,
+			SIMs:            sims,
 		})
 	}
 	return params
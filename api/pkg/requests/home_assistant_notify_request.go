@@ -0,0 +1,67 @@
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// HomeAssistantNotify is the payload which is sent by the REST notify platform of Home Assistant
+type HomeAssistantNotify struct {
+	request
+	Message string `json:"message" example:"The front door is open"`
+	// Title is prepended to the message when it is set
+	Title string `json:"title" example:"Alarm" validate:"optional"`
+	// Target are the phone numbers which will receive the message. Home Assistant sends a single phone number or a list of phone numbers
+	Target []string `json:"target" example:"+18005550100"`
+	// From is the phone number which sends the message. It defaults to your most recently added phone
+	From string `json:"from" example:"+18005550199" validate:"optional"`
+}
+
+// UnmarshalJSON accepts the target as a string or as a list of strings
+func (input *HomeAssistantNotify) UnmarshalJSON(data []byte) error {
+	type payload HomeAssistantNotify
+	value := struct {
+		*payload
+		Target json.RawMessage `json:"target"`
+	}{payload: (*payload)(input)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	input.Target = nil
+	if len(value.Target) == 0 || string(value.Target) == "null" {
+		return nil
+	}
+
+	var target string
+	if err := json.Unmarshal(value.Target, &target); err == nil {
+		input.Target = strings.Split(target, ",")
+		return nil
+	}
+
+	return json.Unmarshal(value.Target, &input.Target)
+}
+
+// Sanitize sets defaults to HomeAssistantNotify
+func (input *HomeAssistantNotify) Sanitize() HomeAssistantNotify {
+	input.Message = strings.TrimSpace(input.Message)
+	input.Title = strings.TrimSpace(input.Title)
+	input.From = input.sanitizeAddress(input.From)
+	input.Target = input.sanitizeAddresses(input.Target)
+	return *input
+}
+
+// ToMessageSends converts HomeAssistantNotify into a MessageSend for every target
+func (input *HomeAssistantNotify) ToMessageSends() []MessageSend {
+	content := input.Message
+	if input.Title != "" {
+		content = input.Title + "\n" + input.Message
+	}
+
+	var sends []MessageSend
+	for _, target := range input.Target {
+		sends = append(sends, MessageSend{From: input.From, To: target, Content: content})
+	}
+	return sends
+}
@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// HomeAssistantSensor is the payload for fetching the delivery status sensor of a phone
+type HomeAssistantSensor struct {
+	request
+	Owner string `json:"owner" query:"owner"`
+}
+
+// Sanitize sets defaults to HomeAssistantSensor
+func (input *HomeAssistantSensor) Sanitize() HomeAssistantSensor {
+	input.Owner = input.sanitizeAddress(strings.TrimSpace(input.Owner))
+	return *input
+}
+
+// ToSearchParams converts HomeAssistantSensor to services.MessageSearchParams for the last message sent by the phone
+func (input *HomeAssistantSensor) ToSearchParams(userID entities.UserID) *services.MessageSearchParams {
+	return &services.MessageSearchParams{
+		IndexParams: repositories.IndexParams{
+			SortBy:         "created_at",
+			SortDescending: true,
+			Limit:          1,
+		},
+		UserID: userID,
+		Owners: []string{input.Owner},
+		Types:  []entities.MessageType{entities.MessageTypeMobileTerminated},
+	}
+}
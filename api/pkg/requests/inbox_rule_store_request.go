@@ -0,0 +1,48 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// InboxRuleStore is the payload for creating a new entities.InboxRule
+type InboxRuleStore struct {
+	request
+	Name     string `json:"name" example:"Delivery notifications"`
+	Sender   string `json:"sender" example:"+18005550100"`
+	Content  string `json:"content" example:"your package"`
+	Label    string `json:"label" example:"deliveries"`
+	Archive  bool   `json:"archive" example:"true"`
+	MarkRead bool   `json:"mark_read" example:"true"`
+}
+
+// Sanitize sets defaults to InboxRuleStore
+func (input *InboxRuleStore) Sanitize() InboxRuleStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Sender = strings.TrimSpace(input.Sender)
+	input.Content = strings.TrimSpace(input.Content)
+	input.Label = strings.ToLower(strings.TrimSpace(input.Label))
+	return *input
+}
+
+// ToStoreParams converts InboxRuleStore to services.InboxRuleStoreParams
+func (input *InboxRuleStore) ToStoreParams(user entities.AuthUser) *services.InboxRuleStoreParams {
+	return &services.InboxRuleStoreParams{
+		UserID:   user.ID,
+		Name:     input.Name,
+		Sender:   input.Sender,
+		Content:  input.Content,
+		Label:    input.getLabel(),
+		Archive:  input.Archive,
+		MarkRead: input.MarkRead,
+	}
+}
+
+func (input *InboxRuleStore) getLabel() *string {
+	if input.Label == "" {
+		return nil
+	}
+	return &input.Label
+}
@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// InboxRuleUpdate is the payload for updating an entities.InboxRule
+type InboxRuleUpdate struct {
+	InboxRuleStore
+	RuleID string `json:"ruleID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to InboxRuleUpdate
+func (input *InboxRuleUpdate) Sanitize() InboxRuleUpdate {
+	input.InboxRuleStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts InboxRuleUpdate to services.InboxRuleUpdateParams
+func (input *InboxRuleUpdate) ToUpdateParams(user entities.AuthUser) *services.InboxRuleUpdateParams {
+	return &services.InboxRuleUpdateParams{
+		UserID:   user.ID,
+		RuleID:   uuid.MustParse(input.RuleID),
+		Name:     input.Name,
+		Sender:   input.Sender,
+		Content:  input.Content,
+		Label:    input.getLabel(),
+		Archive:  input.Archive,
+		MarkRead: input.MarkRead,
+	}
+}
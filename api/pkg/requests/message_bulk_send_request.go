@@ -22,6 +22,18 @@ type MessageBulkSend struct {
 
 	// RequestID is an optional parameter used to track a request from the client's perspective
 	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
+	// OptOutFooter is used to append the opt-out footer configured on your account e.g. "Reply STOP to unsubscribe". When it is not set, the footer is appended only to marketing messages.
+	OptOutFooter *bool `json:"opt_out_footer" example:"true" validate:"optional"`
+	// Category is the class of the message which can be one of "transactional", "marketing" or "otp". It defaults to "transactional"
+	Category string `json:"category" example:"transactional" validate:"optional"`
+	// Priority is the urgency of the message which can be one of "low", "normal" or "high". High priority messages are sent before the other messages queued on the phone. It defaults to "normal"
+	Priority string `json:"priority" example:"normal" validate:"optional"`
+	// ExpiresAt is an optional time after which the message is marked as expired if it has not been sent by the phone
+	ExpiresAt *time.Time `json:"expires_at" example:"2022-06-05T15:26:09.527976+03:00" validate:"optional"`
+	// ValidityPeriod is an optional number of seconds after the send time during which the message can be sent by the phone. It is ignored when expires_at is set
+	ValidityPeriod uint `json:"validity_period" example:"3600" validate:"optional"`
+	// SenderName is an optional logical name of the sender e.g. "billing" or "alerts" which can be used to filter messages and statistics
+	SenderName string `json:"sender_name" example:"billing" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageReceive
@@ -32,13 +44,32 @@ func (input *MessageBulkSend) Sanitize() MessageBulkSend {
 	}
 	input.To = to
 	input.From = input.sanitizeAddress(input.From)
+	input.Content = input.sanitizeContent(input.Content)
+	input.Category = input.sanitizeCategory(input.Category)
+	input.Priority = input.sanitizePriority(input.Priority)
+	input.SenderName = input.sanitizeSenderName(input.SenderName)
 	return *input
 }
 
+// ToMessageDraft converts MessageBulkSend to services.MessageDraft which updates the from and content of the request in place
+func (input *MessageBulkSend) ToMessageDraft(authUser entities.AuthUser) *services.MessageDraft {
+	return &services.MessageDraft{
+		UserID:         authUser.ID,
+		DefaultPhoneID: authUser.DefaultPhoneID,
+		From:           &input.From,
+		Content:        &input.Content,
+		Encrypted:      input.Encrypted,
+		Marketing:      entities.MessageCategory(input.Category).IsMarketing(),
+		OptOutFooter:   input.OptOutFooter,
+	}
+}
+
 // ToMessageSendParams converts MessageSend to services.MessageSendParams
 func (input *MessageBulkSend) ToMessageSendParams(userID entities.UserID, source string) []services.MessageSendParams {
 	from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
 
+	expiresAt := input.getExpiresAt(input.ExpiresAt, input.ValidityPeriod, nil)
+
 	var result []services.MessageSendParams
 	for _, to := range input.To {
 		result = append(result, services.MessageSendParams{
@@ -47,9 +78,13 @@ func (input *MessageBulkSend) ToMessageSendParams(userID entities.UserID, source
 			Encrypted:         input.Encrypted,
 			RequestID:         input.sanitizeStringPointer(input.RequestID),
 			UserID:            userID,
+			ExpiresAt:         expiresAt,
 			RequestReceivedAt: time.Now().UTC(),
 			Contact:           to,
 			Content:           input.Content,
+			Category:          entities.MessageCategory(input.Category),
+			Priority:          entities.MessagePriority(input.Priority),
+			SenderName:        input.sanitizeStringPointer(input.SenderName),
 		})
 	}
 
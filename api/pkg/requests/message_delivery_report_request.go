@@ -0,0 +1,66 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageDeliveryReport is the payload for the delivery report which the carrier sent to the mobile phone for a message
+type MessageDeliveryReport struct {
+	request
+
+	// Status is the delivery status reported by the carrier, it is parsed from the status_code when it is empty
+	// * delivered: the message has been delivered to the recipient
+	// * failed: the message could not be delivered to the recipient
+	// * pending: the carrier accepted the message but has not confirmed that it was delivered to the recipient
+	Status string `json:"status" example:"delivered"`
+
+	// StatusCode is the raw TP-Status code of the SMS-STATUS-REPORT which was received by the mobile phone
+	StatusCode *uint `json:"status_code" example:"0"`
+
+	// PDU is the raw PDU of the SMS-STATUS-REPORT encoded in hexadecimal
+	PDU *string `json:"pdu" example:"07914151551512F2060A0A8101234567892260503141650022605031416500"`
+
+	// ErrorCode is the error code reported by the carrier when the message could not be delivered
+	ErrorCode *string `json:"error_code" example:"UNKNOWN_SUBSCRIBER"`
+
+	// Timestamp is the time when the delivery report was received by the mobile phone
+	Timestamp time.Time `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+
+	MessageID string `json:"messageID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageDeliveryReport
+func (input *MessageDeliveryReport) Sanitize() MessageDeliveryReport {
+	input.MessageID = input.sanitizeMessageID(input.MessageID)
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+	if input.Status == "" && input.StatusCode != nil {
+		input.Status = string(entities.MessageDeliveryStatusFromCode(*input.StatusCode))
+	}
+	if input.PDU != nil {
+		input.PDU = input.sanitizeStringPointer(strings.ToUpper(*input.PDU))
+	}
+	if input.ErrorCode != nil {
+		errorCode := strings.TrimSpace(*input.ErrorCode)
+		input.ErrorCode = &errorCode
+	}
+	if input.Timestamp.IsZero() {
+		input.Timestamp = time.Now().UTC()
+	}
+	return *input
+}
+
+// ToDeliveryReportParams converts MessageDeliveryReport to services.MessageDeliveryReportParams
+func (input *MessageDeliveryReport) ToDeliveryReportParams(source string) services.MessageDeliveryReportParams {
+	return services.MessageDeliveryReportParams{
+		Status:     entities.MessageDeliveryStatus(input.Status),
+		ErrorCode:  input.ErrorCode,
+		StatusCode: input.StatusCode,
+		PDU:        input.PDU,
+		Timestamp:  input.Timestamp,
+		Source:     source,
+	}
+}
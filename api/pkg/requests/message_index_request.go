@@ -13,11 +13,15 @@ import (
 
 // MessageIndex is the payload fetching entities.Message sent between 2 numbers
 type MessageIndex struct {
-	Skip    string `json:"skip" query:"skip"`
-	Contact string `json:"contact" query:"contact"`
-	Owner   string `json:"owner" query:"owner"`
-	Query   string `json:"query" query:"query"`
-	Limit   string `json:"limit" query:"limit"`
+	request
+	Skip          string `json:"skip" query:"skip"`
+	Contact       string `json:"contact" query:"contact"`
+	Owner         string `json:"owner" query:"owner"`
+	Query         string `json:"query" query:"query"`
+	Limit         string `json:"limit" query:"limit"`
+	Cursor        string `json:"cursor" query:"cursor"`
+	IsStarred     string `json:"is_starred" query:"is_starred" example:"true"`
+	NeedsFollowUp string `json:"needs_follow_up" query:"needs_follow_up" example:"true"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -27,10 +31,13 @@ func (input *MessageIndex) Sanitize() MessageIndex {
 	}
 
 	input.Query = strings.TrimSpace(input.Query)
+	input.IsStarred = input.sanitizeBool(input.IsStarred)
+	input.NeedsFollowUp = input.sanitizeBool(input.NeedsFollowUp)
 
 	input.Owner = input.sanitizeAddress(input.Owner)
 	input.Contact = input.sanitizeAddress(input.Contact)
 
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	input.Skip = strings.TrimSpace(input.Skip)
 	if input.Skip == "" {
 		input.Skip = "0"
@@ -43,13 +50,16 @@ func (input *MessageIndex) Sanitize() MessageIndex {
 func (input *MessageIndex) ToGetParams(userID entities.UserID) services.MessageGetParams {
 	return services.MessageGetParams{
 		IndexParams: repositories.IndexParams{
-			Skip:  input.getInt(input.Skip),
-			Query: input.Query,
-			Limit: input.getInt(input.Limit),
+			Skip:   input.getInt(input.Skip),
+			Query:  input.Query,
+			Limit:  input.getInt(input.Limit),
+			Cursor: input.getCursor(input.Cursor),
 		},
-		UserID:  userID,
-		Owner:   input.Owner,
-		Contact: input.Contact,
+		UserID:        userID,
+		Owner:         input.Owner,
+		Contact:       input.Contact,
+		IsStarred:     input.getBoolPointer(input.IsStarred),
+		NeedsFollowUp: input.getBoolPointer(input.NeedsFollowUp),
 	}
 }
 
@@ -62,6 +72,15 @@ func (input *MessageIndex) sanitizeAddress(value string) string {
 	return value
 }
 
+// getCursor decodes a cursor which has already been validated
+func (input *MessageIndex) getCursor(value string) *repositories.Cursor {
+	if value == "" {
+		return nil
+	}
+	cursor, _ := repositories.DecodeCursor(value)
+	return cursor
+}
+
 // getLimit gets the take as a string
 func (input *MessageIndex) getInt(value string) int {
 	val, _ := strconv.Atoi(value)
@@ -21,6 +21,8 @@ type MessageReceive struct {
 	Encrypted bool `json:"encrypted" example:"false"`
 	// SIM card that received the message
 	SIM entities.SIM `json:"sim" example:"SIM1"`
+	// MediaURLs are the URLs of the attachments of an MMS message which were uploaded with the POST /v1/attachments endpoint
+	MediaURLs []string `json:"media_urls" example:"https://api.httpsms.com/v1/attachments/32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
 	// Timestamp is the time when the event was emitted, Please send the timestamp in UTC with as much precision as possible
 	Timestamp time.Time `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
 }
@@ -29,9 +31,13 @@ type MessageReceive struct {
 func (input *MessageReceive) Sanitize() MessageReceive {
 	input.To = input.sanitizeAddress(input.To)
 	input.From = input.sanitizeContact(input.To, input.From)
+	input.Content = input.sanitizeContent(input.Content)
 	if strings.TrimSpace(string(input.SIM)) == "" || input.SIM == ("DEFAULT") {
 		input.SIM = entities.SIM1
 	}
+	for index, mediaURL := range input.MediaURLs {
+		input.MediaURLs[index] = input.sanitizeURL(mediaURL)
+	}
 	return *input
 }
 
@@ -47,5 +53,6 @@ func (input *MessageReceive) ToMessageReceiveParams(userID entities.UserID, sour
 		Owner:     *phone,
 		Content:   input.Content,
 		SIM:       input.SIM,
+		MediaURLs: input.MediaURLs,
 	}
 }
@@ -17,6 +17,7 @@ type MessageSearch struct {
 	Owners         []string `json:"owners" query:"owners"`
 	Types          []string `json:"types" query:"types"`
 	Statuses       []string `json:"statuses" query:"statuses"`
+	SenderNames    []string `json:"sender_names" query:"sender_names"`
 	Query          string   `json:"query" query:"query"`
 	SortBy         string   `json:"sort_by" query:"sort_by"`
 	SortDescending bool     `json:"sort_descending" query:"sort_descending"`
@@ -34,6 +35,12 @@ func (input *MessageSearch) Sanitize() MessageSearch {
 
 	input.Query = strings.TrimSpace(input.Query)
 
+	var senderNames []string
+	for _, senderName := range input.SenderNames {
+		senderNames = append(senderNames, input.sanitizeSenderName(senderName))
+	}
+	input.SenderNames = senderNames
+
 	input.Skip = strings.TrimSpace(input.Skip)
 	if input.Skip == "" {
 		input.Skip = "0"
@@ -62,9 +69,10 @@ func (input *MessageSearch) ToSearchParams(userID entities.UserID) *services.Mes
 			SortDescending: input.SortDescending,
 			Limit:          input.getInt(input.Limit),
 		},
-		UserID:   userID,
-		Owners:   input.Owners,
-		Types:    types,
-		Statuses: statuses,
+		UserID:      userID,
+		Owners:      input.Owners,
+		Types:       types,
+		Statuses:    statuses,
+		SenderNames: input.SenderNames,
 	}
 }
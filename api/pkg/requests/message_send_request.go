@@ -14,6 +14,7 @@ import (
 // MessageSend is the payload for sending and SMS message
 type MessageSend struct {
 	request
+	// From is the phone number which sends the message. It is chosen with the routing rules or the default phone of your account when it is empty
 	From    string `json:"from" example:"+18005550199"`
 	To      string `json:"to" example:"+18005550100"`
 	Content string `json:"content" example:"This is a sample text message"`
@@ -24,6 +25,30 @@ type MessageSend struct {
 	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
 	// SendAt is an optional parameter used to schedule a message to be sent at a later time
 	SendAt *time.Time `json:"send_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	// VCard is an optional contact which will be sent as a vCard attachment when the content is empty
+	VCard *entities.VCard `json:"vcard" validate:"optional"`
+	// Channel is the transport used by the phone to send the message. It defaults to "sms", use "mms" to send picture messages or "whatsapp" to relay the message through WhatsApp. The Android app only sends the "sms" channel for now
+	Channel string `json:"channel" example:"sms" validate:"optional"`
+	// MediaURLs are optional media attachments which are only supported on the "mms" and "whatsapp" channels. Upload files with the POST /v1/attachments endpoint to get their URL
+	MediaURLs []string `json:"media_urls" example:"https://example.com/image.png" validate:"optional"`
+	// OptOutFooter is used to append the opt-out footer configured on your account e.g. "Reply STOP to unsubscribe". When it is not set, the footer is appended only to marketing messages.
+	OptOutFooter *bool `json:"opt_out_footer" example:"true" validate:"optional"`
+	// Category is the class of the message which can be one of "transactional", "marketing" or "otp". It defaults to "transactional"
+	Category string `json:"category" example:"transactional" validate:"optional"`
+	// Priority is the urgency of the message which can be one of "low", "normal" or "high". High priority messages are sent before the other messages queued on the phone. It defaults to "normal"
+	Priority string `json:"priority" example:"normal" validate:"optional"`
+	// ExpiresAt is an optional time after which the message is marked as expired if it has not been sent by the phone
+	ExpiresAt *time.Time `json:"expires_at" example:"2022-06-05T15:26:09.527976+03:00" validate:"optional"`
+	// ValidityPeriod is an optional number of seconds after the send time during which the message can be sent by the phone. It is ignored when expires_at is set
+	ValidityPeriod uint `json:"validity_period" example:"3600" validate:"optional"`
+	// SenderName is an optional logical name of the sender e.g. "billing" or "alerts" which can be used to filter messages and statistics
+	SenderName string `json:"sender_name" example:"billing" validate:"optional"`
+	// RoutingTag is an optional tag e.g. "alerts" which is matched by the routing rules to choose the phone when from is empty
+	RoutingTag string `json:"routing_tag" example:"alerts" validate:"optional"`
+	// SIM is the SIM slot which sends the message on a dual-SIM phone which can be one of "SIM1", "SIM2" or "DEFAULT" to use the default SMS SIM card of the phone. It defaults to the SIM slot of the "from" phone
+	SIM string `json:"sim" example:"SIM1" validate:"optional"`
+	// PhonePoolID is the optional ID of a phone pool which chooses the phone that sends the message when from is empty
+	PhonePoolID string `json:"phone_pool_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageReceive
@@ -31,9 +56,49 @@ func (input *MessageSend) Sanitize() MessageSend {
 	input.To = input.sanitizeAddress(input.To)
 	input.RequestID = strings.TrimSpace(input.RequestID)
 	input.From = input.sanitizeAddress(input.From)
+	input.Content = input.sanitizeContent(input.Content)
+	input.Category = input.sanitizeCategory(input.Category)
+	input.Priority = input.sanitizePriority(input.Priority)
+	input.SenderName = input.sanitizeSenderName(input.SenderName)
+	input.RoutingTag = strings.ToLower(strings.TrimSpace(input.RoutingTag))
+	input.SIM = strings.ToUpper(strings.TrimSpace(input.SIM))
+	input.PhonePoolID = strings.TrimSpace(input.PhonePoolID)
+	input.Channel = entities.MessageChannelSanitized(entities.MessageChannel(strings.ToLower(strings.TrimSpace(input.Channel)))).String()
+	for index, mediaURL := range input.MediaURLs {
+		input.MediaURLs[index] = input.sanitizeURL(mediaURL)
+	}
+	if input.VCard != nil {
+		input.VCard.Name = strings.TrimSpace(input.VCard.Name)
+		for index, phoneNumber := range input.VCard.PhoneNumbers {
+			input.VCard.PhoneNumbers[index] = input.sanitizeAddress(phoneNumber)
+		}
+		for index, email := range input.VCard.Emails {
+			input.VCard.Emails[index] = strings.TrimSpace(email)
+		}
+		input.VCard.Organization = strings.TrimSpace(input.VCard.Organization)
+		if strings.TrimSpace(input.Content) == "" {
+			input.Content = input.VCard.String()
+		}
+	}
 	return *input
 }
 
+// ToMessageDraft converts MessageSend to services.MessageDraft which updates the from and content of the request in place
+func (input *MessageSend) ToMessageDraft(authUser entities.AuthUser) *services.MessageDraft {
+	return &services.MessageDraft{
+		UserID:         authUser.ID,
+		DefaultPhoneID: authUser.DefaultPhoneID,
+		From:           &input.From,
+		Content:        &input.Content,
+		To:             input.To,
+		Encrypted:      input.Encrypted,
+		Marketing:      entities.MessageCategory(input.Category).IsMarketing(),
+		OptOutFooter:   input.OptOutFooter,
+		PhonePoolID:    input.PhonePoolID,
+		RoutingTag:     input.RoutingTag,
+	}
+}
+
 // ToMessageSendParams converts MessageSend to services.MessageSendParams
 func (input *MessageSend) ToMessageSendParams(userID entities.UserID, source string) services.MessageSendParams {
 	from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
@@ -44,8 +109,15 @@ func (input *MessageSend) ToMessageSendParams(userID entities.UserID, source str
 		RequestID:         input.sanitizeStringPointer(input.RequestID),
 		UserID:            userID,
 		SendAt:            input.SendAt,
+		ExpiresAt:         input.getExpiresAt(input.ExpiresAt, input.ValidityPeriod, input.SendAt),
 		RequestReceivedAt: time.Now().UTC(),
 		Contact:           input.sanitizeAddress(input.To),
 		Content:           input.Content,
+		Channel:           entities.MessageChannel(input.Channel),
+		MediaURLs:         input.MediaURLs,
+		Category:          entities.MessageCategory(input.Category),
+		Priority:          entities.MessagePriority(input.Priority),
+		SenderName:        input.sanitizeStringPointer(input.SenderName),
+		SIM:               entities.SIM(input.SIM),
 	}
 }
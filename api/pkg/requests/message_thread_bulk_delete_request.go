@@ -0,0 +1,52 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageThreadBulkDelete is the payload for deleting many entities.MessageThread at once
+type MessageThreadBulkDelete struct {
+	request
+	// IDs are the IDs of the message threads to delete
+	IDs []string `json:"ids" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+
+	// OlderThanDays deletes the message threads which have had no messages for this number of days when IDs is empty
+	OlderThanDays uint `json:"older_than_days" example:"90"`
+}
+
+// Sanitize sets defaults to MessageThreadBulkDelete
+func (input *MessageThreadBulkDelete) Sanitize() MessageThreadBulkDelete {
+	var ids []string
+	for _, id := range input.IDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	input.IDs = ids
+	return *input
+}
+
+// ToBulkDeleteParams converts MessageThreadBulkDelete into services.MessageThreadBulkDeleteParams
+func (input *MessageThreadBulkDelete) ToBulkDeleteParams(userID entities.UserID, source string) services.MessageThreadBulkDeleteParams {
+	params := services.MessageThreadBulkDeleteParams{
+		UserID: userID,
+		Source: source,
+	}
+
+	for _, id := range input.IDs {
+		params.MessageThreadIDs = append(params.MessageThreadIDs, uuid.MustParse(id))
+	}
+
+	if input.OlderThanDays > 0 {
+		olderThan := time.Now().UTC().Add(-time.Duration(input.OlderThanDays) * 24 * time.Hour)
+		params.OlderThan = &olderThan
+	}
+
+	return params
+}
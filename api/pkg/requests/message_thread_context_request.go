@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageThreadContext is the payload for fetching the recent exchange in an entities.MessageThread
+type MessageThreadContext struct {
+	request
+	Limit string `json:"limit" query:"limit"`
+
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadContext
+func (input *MessageThreadContext) Sanitize() MessageThreadContext {
+	input.Limit = strings.TrimSpace(input.Limit)
+	if input.Limit == "" {
+		input.Limit = "20"
+	}
+	input.MessageThreadID = strings.TrimSpace(input.MessageThreadID)
+	return *input
+}
+
+// ToContextParams converts MessageThreadContext into services.MessageThreadContextParams
+func (input *MessageThreadContext) ToContextParams(userID entities.UserID) services.MessageThreadContextParams {
+	return services.MessageThreadContextParams{
+		UserID:          userID,
+		MessageThreadID: uuid.MustParse(input.MessageThreadID),
+		Limit:           input.getInt(input.Limit),
+	}
+}
@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// MessageThreadExport is the payload for exporting the messages in an entities.MessageThread
+type MessageThreadExport struct {
+	request
+	Format string `json:"format" query:"format"`
+
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadExport
+func (input *MessageThreadExport) Sanitize() MessageThreadExport {
+	input.Format = strings.ToLower(strings.TrimSpace(input.Format))
+	if input.Format == "" {
+		input.Format = string(entities.MessageThreadExportFormatCSV)
+	}
+	input.MessageThreadID = strings.TrimSpace(input.MessageThreadID)
+	return *input
+}
+
+// MessageThreadIDUuid returns the MessageThreadID as uuid.UUID
+func (input *MessageThreadExport) MessageThreadIDUuid() uuid.UUID {
+	return uuid.MustParse(input.MessageThreadID)
+}
+
+// ExportFormat returns the Format as entities.MessageThreadExportFormat
+func (input *MessageThreadExport) ExportFormat() entities.MessageThreadExportFormat {
+	return entities.MessageThreadExportFormat(input.Format)
+}
@@ -18,6 +18,10 @@ type MessageThreadIndex struct {
 	Query      string `json:"query" query:"query"`
 	Limit      string `json:"limit" query:"limit"`
 	Owner      string `json:"owner" query:"owner"`
+	Label      string `json:"label" query:"label" example:"support"`
+	Topic      string `json:"topic" query:"topic" example:"billing"`
+	Language   string `json:"language" query:"language" example:"en"`
+	Cursor     string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -33,7 +37,11 @@ func (input *MessageThreadIndex) Sanitize() MessageThreadIndex {
 	input.IsArchived = input.sanitizeBool(input.IsArchived)
 	input.Query = strings.TrimSpace(input.Query)
 	input.Owner = input.sanitizeAddress(input.Owner)
+	input.Label = input.sanitizeLabel(input.Label)
+	input.Topic = strings.ToLower(strings.TrimSpace(input.Topic))
+	input.Language = strings.ToLower(strings.TrimSpace(input.Language))
 
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	input.Skip = strings.TrimSpace(input.Skip)
 	if input.Skip == "" {
 		input.Skip = "0"
@@ -46,12 +54,16 @@ func (input *MessageThreadIndex) Sanitize() MessageThreadIndex {
 func (input *MessageThreadIndex) ToGetParams(userID entities.UserID) services.MessageThreadGetParams {
 	return services.MessageThreadGetParams{
 		IndexParams: repositories.IndexParams{
-			Skip:  input.getInt(input.Skip),
-			Query: input.Query,
-			Limit: input.getInt(input.Limit),
+			Skip:   input.getInt(input.Skip),
+			Query:  input.Query,
+			Limit:  input.getInt(input.Limit),
+			Cursor: input.getCursor(input.Cursor),
 		},
 		UserID:     userID,
 		IsArchived: input.getBool(input.IsArchived),
 		Owner:      input.Owner,
+		Label:      input.Label,
+		Topic:      input.Topic,
+		Language:   input.Language,
 	}
 }
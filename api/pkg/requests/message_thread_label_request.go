@@ -0,0 +1,50 @@
+package requests
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadLabelsStore is the payload for adding labels to a message thread
+type MessageThreadLabelsStore struct {
+	request
+	Labels []string `json:"labels" example:"support,sales"`
+
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadLabelsStore
+func (input *MessageThreadLabelsStore) Sanitize() MessageThreadLabelsStore {
+	labels := make([]string, 0, len(input.Labels))
+	for _, label := range input.Labels {
+		if label = input.sanitizeLabel(label); label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	input.Labels = labels
+	return *input
+}
+
+// MessageThreadIDUuid returns the message thread ID as uuid.UUID
+func (input *MessageThreadLabelsStore) MessageThreadIDUuid() uuid.UUID {
+	return uuid.MustParse(input.MessageThreadID)
+}
+
+// MessageThreadLabelDestroy is the payload for removing a label from a message thread
+type MessageThreadLabelDestroy struct {
+	request
+	Label           string `json:"label" swaggerignore:"true"`           // used internally for validation
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadLabelDestroy
+func (input *MessageThreadLabelDestroy) Sanitize() MessageThreadLabelDestroy {
+	input.Label = input.sanitizeLabel(input.Label)
+	return *input
+}
+
+// MessageThreadIDUuid returns the message thread ID as uuid.UUID
+func (input *MessageThreadLabelDestroy) MessageThreadIDUuid() uuid.UUID {
+	return uuid.MustParse(input.MessageThreadID)
+}
@@ -0,0 +1,60 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageThreadSearch is the payload for searching entities.MessageThread by the contact or the last message content
+type MessageThreadSearch struct {
+	request
+	Q          string   `json:"q" query:"q"`
+	Owners     []string `json:"owners" query:"owners"`
+	IsArchived string   `json:"is_archived" query:"is_archived"`
+	Skip       string   `json:"skip" query:"skip"`
+	Limit      string   `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to MessageThreadSearch
+func (input *MessageThreadSearch) Sanitize() MessageThreadSearch {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+
+	input.Q = strings.TrimSpace(input.Q)
+	if strings.TrimSpace(input.IsArchived) != "" {
+		input.IsArchived = input.sanitizeBool(input.IsArchived)
+	}
+
+	input.Owners = input.sanitizeAddresses(input.Owners)
+
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+
+	return *input
+}
+
+// ToSearchParams converts MessageThreadSearch into services.MessageThreadSearchParams
+func (input *MessageThreadSearch) ToSearchParams(userID entities.UserID) services.MessageThreadSearchParams {
+	var isArchived *bool
+	if input.IsArchived != "" {
+		value := input.getBool(input.IsArchived)
+		isArchived = &value
+	}
+
+	return services.MessageThreadSearchParams{
+		IndexParams: repositories.IndexParams{
+			Skip:  input.getInt(input.Skip),
+			Query: input.Q,
+			Limit: input.getInt(input.Limit),
+		},
+		UserID:     userID,
+		IsArchived: isArchived,
+		Owners:     input.Owners,
+	}
+}
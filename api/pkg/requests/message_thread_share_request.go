@@ -0,0 +1,39 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageThreadShare is the payload for creating a public read-only link to an entities.MessageThread
+type MessageThreadShare struct {
+	request
+	ExpiresIn string `json:"expires_in" example:"86400"`
+
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadShare
+func (input *MessageThreadShare) Sanitize() MessageThreadShare {
+	input.ExpiresIn = strings.TrimSpace(input.ExpiresIn)
+	if input.ExpiresIn == "" {
+		input.ExpiresIn = "86400"
+	}
+	input.MessageThreadID = strings.TrimSpace(input.MessageThreadID)
+	return *input
+}
+
+// ToShareParams converts MessageThreadShare into services.MessageThreadShareParams
+func (input *MessageThreadShare) ToShareParams(userID entities.UserID, baseURL string) services.MessageThreadShareParams {
+	return services.MessageThreadShareParams{
+		UserID:          userID,
+		MessageThreadID: uuid.MustParse(input.MessageThreadID),
+		ExpiresIn:       time.Duration(input.getInt(input.ExpiresIn)) * time.Second,
+		BaseURL:         baseURL,
+	}
+}
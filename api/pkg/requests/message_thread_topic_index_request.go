@@ -0,0 +1,13 @@
+package requests
+
+// MessageThreadTopicIndex is the payload for counting the entities.MessageThread of a phone number by topic and language
+type MessageThreadTopicIndex struct {
+	request
+	Owner string `json:"owner" query:"owner" example:"+18005550199"`
+}
+
+// Sanitize sets defaults to MessageThreadTopicIndex
+func (input *MessageThreadTopicIndex) Sanitize() MessageThreadTopicIndex {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	return *input
+}
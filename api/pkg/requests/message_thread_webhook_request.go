@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MessageThreadWebhookUpdate is the payload for setting the webhook of a message thread
+type MessageThreadWebhookUpdate struct {
+	request
+	WebhookID string `json:"webhook_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+
+	MessageThreadID string `json:"messageThreadID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageThreadWebhookUpdate
+func (input *MessageThreadWebhookUpdate) Sanitize() MessageThreadWebhookUpdate {
+	input.WebhookID = strings.TrimSpace(input.WebhookID)
+	return *input
+}
+
+// MessageThreadIDUuid returns the message thread ID as uuid.UUID
+func (input *MessageThreadWebhookUpdate) MessageThreadIDUuid() uuid.UUID {
+	return uuid.MustParse(input.MessageThreadID)
+}
+
+// WebhookIDUuid returns the webhook ID as uuid.UUID
+func (input *MessageThreadWebhookUpdate) WebhookIDUuid() *uuid.UUID {
+	webhookID := uuid.MustParse(input.WebhookID)
+	return &webhookID
+}
@@ -0,0 +1,26 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// MessageUpdate is the payload for updating the flags of a message
+type MessageUpdate struct {
+	request
+	IsStarred     *bool `json:"is_starred" example:"true"`
+	NeedsFollowUp *bool `json:"needs_follow_up" example:"false"`
+
+	MessageID string `json:"messageID" swaggerignore:"true"` // used internally for validation
+}
+
+// ToFlagsParams converts MessageUpdate to services.MessageFlagsParams
+func (input *MessageUpdate) ToFlagsParams(userID entities.UserID) *services.MessageFlagsParams {
+	return &services.MessageFlagsParams{
+		UserID:        userID,
+		MessageID:     uuid.MustParse(input.MessageID),
+		IsStarred:     input.IsStarred,
+		NeedsFollowUp: input.NeedsFollowUp,
+	}
+}
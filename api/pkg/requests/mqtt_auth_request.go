@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MQTTAuth is the payload which is sent by the HTTP authentication plugin of the MQTT broker when a client connects
+type MQTTAuth struct {
+	request
+	Username string `json:"username" form:"username"`
+	Password string `json:"password" form:"password"`
+	ClientID string `json:"clientid" form:"clientid"`
+}
+
+// Sanitize sets defaults to MQTTAuth
+func (input *MQTTAuth) Sanitize() MQTTAuth {
+	input.Username = strings.TrimSpace(input.Username)
+	return *input
+}
+
+// MQTTACL is the payload which is sent by the HTTP authentication plugin of the MQTT broker when a client accesses a topic
+type MQTTACL struct {
+	request
+	Username string `json:"username" form:"username"`
+	ClientID string `json:"clientid" form:"clientid"`
+	Topic    string `json:"topic" form:"topic"`
+	// Access is 1 to read, 2 to publish and 4 to subscribe to the topic
+	Access int `json:"acc" form:"acc"`
+}
+
+// Sanitize sets defaults to MQTTACL
+func (input *MQTTACL) Sanitize() MQTTACL {
+	input.Username = strings.TrimSpace(input.Username)
+	return *input
+}
+
+// AccessLevel returns the services.MQTTAccess of the request
+func (input *MQTTACL) AccessLevel() services.MQTTAccess {
+	return services.MQTTAccess(input.Access)
+}
@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// NotificationChannelStore is the payload for creating a new entities.NotificationChannel
+type NotificationChannelStore struct {
+	request
+	Provider   string   `json:"provider" example:"teams"`
+	Name       string   `json:"name" example:"Operations"`
+	WebhookURL string   `json:"webhook_url" example:"https://example.webhook.office.com/webhookb2/7a8a3b2c"`
+	Events     []string `json:"events" example:"phone.heartbeat.offline,message.send.failed"`
+}
+
+// Sanitize sets defaults to NotificationChannelStore
+func (input *NotificationChannelStore) Sanitize() NotificationChannelStore {
+	input.Provider = strings.ToLower(strings.TrimSpace(input.Provider))
+	input.Name = strings.TrimSpace(input.Name)
+	input.WebhookURL = input.sanitizeURL(input.WebhookURL)
+	input.Events = input.removeStringDuplicates(input.Events)
+	return *input
+}
+
+// ToStoreParams converts NotificationChannelStore to services.NotificationChannelStoreParams
+func (input *NotificationChannelStore) ToStoreParams(user entities.AuthUser) *services.NotificationChannelStoreParams {
+	return &services.NotificationChannelStoreParams{
+		UserID:     user.ID,
+		Provider:   entities.NotificationChannelProvider(input.Provider),
+		Name:       input.Name,
+		WebhookURL: input.WebhookURL,
+		Events:     input.Events,
+	}
+}
@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// NotificationChannelUpdate is the payload for updating an entities.NotificationChannel
+type NotificationChannelUpdate struct {
+	NotificationChannelStore
+	ChannelID string `json:"channelID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to NotificationChannelUpdate
+func (input *NotificationChannelUpdate) Sanitize() NotificationChannelUpdate {
+	input.NotificationChannelStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts NotificationChannelUpdate to services.NotificationChannelUpdateParams
+func (input *NotificationChannelUpdate) ToUpdateParams(user entities.AuthUser) *services.NotificationChannelUpdateParams {
+	return &services.NotificationChannelUpdateParams{
+		UserID:     user.ID,
+		ChannelID:  uuid.MustParse(input.ChannelID),
+		Provider:   entities.NotificationChannelProvider(input.Provider),
+		Name:       input.Name,
+		WebhookURL: input.WebhookURL,
+		Events:     input.Events,
+	}
+}
@@ -0,0 +1,111 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhoneBalanceStore is the payload for reporting the credit balance and plan expiry of the SIM of a phone
+type PhoneBalanceStore struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+
+	// Content is the USSD response or the SMS of the carrier, the balance is parsed from it when the amount is empty
+	Content string `json:"content" example:"Your balance is USD 12.50, valid until 30/06/2022"`
+
+	// Amount is the credit balance of the SIM
+	Amount *float64 `json:"amount" example:"12.5"`
+
+	// Currency is the currency of the credit balance
+	Currency string `json:"currency" example:"USD"`
+
+	// PlanExpiresAt is the time when the plan of the SIM expires
+	PlanExpiresAt *time.Time `json:"plan_expires_at" example:"2022-06-30T00:00:00Z"`
+
+	// Timestamp is the time when the balance was checked, the current time is used when it is empty
+	Timestamp *time.Time `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+}
+
+// Sanitize sets defaults to PhoneBalanceStore
+func (input *PhoneBalanceStore) Sanitize() PhoneBalanceStore {
+	input.Content = strings.TrimSpace(input.Content)
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	return *input
+}
+
+// ToStoreParams converts PhoneBalanceStore to services.PhoneBalanceStoreParams
+func (input *PhoneBalanceStore) ToStoreParams(userID entities.UserID, source string) *services.PhoneBalanceStoreParams {
+	params := &services.PhoneBalanceStoreParams{
+		Source:        source,
+		UserID:        userID,
+		PhoneID:       uuid.MustParse(input.PhoneID),
+		Currency:      input.Currency,
+		PlanExpiresAt: input.PlanExpiresAt,
+		Content:       input.Content,
+		ReportedAt:    time.Now().UTC(),
+	}
+
+	if input.Timestamp != nil {
+		params.ReportedAt = input.Timestamp.UTC()
+	}
+
+	if input.Amount != nil {
+		params.Amount = *input.Amount
+	}
+
+	if reading := entities.ParsePhoneBalance(input.Content); reading != nil {
+		if input.Amount == nil {
+			params.Amount = reading.Amount
+		}
+		if params.Currency == "" {
+			params.Currency = reading.Currency
+		}
+		if params.PlanExpiresAt == nil {
+			params.PlanExpiresAt = reading.PlanExpiresAt
+		}
+	}
+
+	return params
+}
+
+// PhoneBalanceIndex is the payload for fetching entities.PhoneBalance of a phone
+type PhoneBalanceIndex struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	Skip    string `json:"skip" query:"skip"`
+	Query   string `json:"query" query:"query"`
+	Limit   string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to PhoneBalanceIndex
+func (input *PhoneBalanceIndex) Sanitize() PhoneBalanceIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneBalanceIndex) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// ToIndexParams converts PhoneBalanceIndex to repositories.IndexParams
+func (input *PhoneBalanceIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
@@ -0,0 +1,101 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhoneCrashStore is the payload for reporting a crash or ANR of the app on a phone
+type PhoneCrashStore struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+
+	// Type is the type of failure, either crash or anr
+	Type string `json:"type" example:"crash"`
+
+	// Message is the message of the exception or the reason why the app was not responding
+	Message string `json:"message" example:"java.lang.NullPointerException: Attempt to invoke virtual method on a null object reference"`
+
+	// StackTrace is the stack trace of the exception or the main thread when the app was not responding
+	StackTrace string `json:"stack_trace" example:"at com.httpsms.SmsManagerService.sendTextMessage(SmsManagerService.kt:42)"`
+
+	// AppVersion is the version of the app, the X-Client-Version header is used when it is empty
+	AppVersion string `json:"app_version" example:"v1.0.0"`
+
+	// Timestamp is the time when the crash occurred, the current time is used when it is empty
+	Timestamp *time.Time `json:"timestamp" example:"2022-06-05T14:26:09.527976+03:00"`
+}
+
+// Sanitize sets defaults to PhoneCrashStore
+func (input *PhoneCrashStore) Sanitize() PhoneCrashStore {
+	input.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	input.Message = strings.TrimSpace(input.Message)
+	input.StackTrace = strings.TrimSpace(input.StackTrace)
+	input.AppVersion = strings.TrimSpace(input.AppVersion)
+	return *input
+}
+
+// ToStoreParams converts PhoneCrashStore to services.PhoneCrashStoreParams
+func (input *PhoneCrashStore) ToStoreParams(userID entities.UserID, version string) *services.PhoneCrashStoreParams {
+	appVersion := input.AppVersion
+	if appVersion == "" {
+		appVersion = version
+	}
+
+	occurredAt := time.Now().UTC()
+	if input.Timestamp != nil {
+		occurredAt = input.Timestamp.UTC()
+	}
+
+	return &services.PhoneCrashStoreParams{
+		UserID:     userID,
+		PhoneID:    uuid.MustParse(input.PhoneID),
+		Type:       entities.PhoneCrashType(input.Type),
+		Message:    input.Message,
+		StackTrace: input.StackTrace,
+		AppVersion: appVersion,
+		OccurredAt: occurredAt,
+	}
+}
+
+// PhoneCrashIndex is the payload for fetching entities.PhoneCrash of a phone
+type PhoneCrashIndex struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	Skip    string `json:"skip" query:"skip"`
+	Query   string `json:"query" query:"query"`
+	Limit   string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to PhoneCrashIndex
+func (input *PhoneCrashIndex) Sanitize() PhoneCrashIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneCrashIndex) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// ToIndexParams converts PhoneCrashIndex to repositories.IndexParams
+func (input *PhoneCrashIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneDeactivate is the payload for deactivating a phone
+type PhoneDeactivate struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneDeactivate) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// PhoneLogIndex is the payload for fetching entities.PhoneLog of a phone
+type PhoneLogIndex struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	Skip    string `json:"skip" query:"skip"`
+	Limit   string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to PhoneLogIndex
+func (input *PhoneLogIndex) Sanitize() PhoneLogIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneLogIndex) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// ToIndexParams converts PhoneLogIndex to repositories.IndexParams
+func (input *PhoneLogIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Limit: input.getInt(input.Limit),
+	}
+}
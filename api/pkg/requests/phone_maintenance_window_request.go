@@ -0,0 +1,82 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhoneMaintenanceWindowStore is the payload for declaring a maintenance window of a phone
+type PhoneMaintenanceWindowStore struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+
+	// Reason is why the phone will be offline e.g. Android system update
+	Reason string `json:"reason" example:"Android system update"`
+
+	// StartsAt is the time when the maintenance starts
+	StartsAt time.Time `json:"starts_at" example:"2022-06-05T14:00:00+03:00"`
+
+	// EndsAt is the time when the maintenance ends
+	EndsAt time.Time `json:"ends_at" example:"2022-06-05T16:00:00+03:00"`
+}
+
+// Sanitize sets defaults to PhoneMaintenanceWindowStore
+func (input *PhoneMaintenanceWindowStore) Sanitize() PhoneMaintenanceWindowStore {
+	input.Reason = strings.TrimSpace(input.Reason)
+	input.StartsAt = input.StartsAt.UTC()
+	input.EndsAt = input.EndsAt.UTC()
+	return *input
+}
+
+// ToStoreParams converts PhoneMaintenanceWindowStore to services.PhoneMaintenanceWindowStoreParams
+func (input *PhoneMaintenanceWindowStore) ToStoreParams(userID entities.UserID) *services.PhoneMaintenanceWindowStoreParams {
+	return &services.PhoneMaintenanceWindowStoreParams{
+		UserID:   userID,
+		PhoneID:  uuid.MustParse(input.PhoneID),
+		Reason:   input.Reason,
+		StartsAt: input.StartsAt,
+		EndsAt:   input.EndsAt,
+	}
+}
+
+// PhoneMaintenanceWindowIndex is the payload for fetching entities.PhoneMaintenanceWindow of a phone
+type PhoneMaintenanceWindowIndex struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	Skip    string `json:"skip" query:"skip"`
+	Query   string `json:"query" query:"query"`
+	Limit   string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to PhoneMaintenanceWindowIndex
+func (input *PhoneMaintenanceWindowIndex) Sanitize() PhoneMaintenanceWindowIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneMaintenanceWindowIndex) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// ToIndexParams converts PhoneMaintenanceWindowIndex to repositories.IndexParams
+func (input *PhoneMaintenanceWindowIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhonePoll is the payload for long polling the messages which a phone has to send
+type PhonePoll struct {
+	request
+	PhoneID        string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	TimeoutSeconds string `json:"timeout_seconds" query:"timeout_seconds"`
+}
+
+// Sanitize sets defaults to PhonePoll
+func (input *PhonePoll) Sanitize() PhonePoll {
+	input.TimeoutSeconds = strings.TrimSpace(input.TimeoutSeconds)
+	if input.TimeoutSeconds == "" {
+		input.TimeoutSeconds = "30"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhonePoll) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// Timeout returns the duration to wait for a message as time.Duration
+func (input *PhonePoll) Timeout() time.Duration {
+	return time.Duration(input.getInt(input.TimeoutSeconds)) * time.Second
+}
@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhonePoolStore is the payload for creating a new entities.PhonePool
+type PhonePoolStore struct {
+	request
+	Name string `json:"name" example:"Marketing phones"`
+	// Strategy distributes the messages across the phones of the pool and it can be "round_robin" or "least_loaded". It defaults to "round_robin"
+	Strategy     string   `json:"strategy" example:"round_robin"`
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550199,+18005550100"`
+}
+
+// Sanitize sets defaults to PhonePoolStore
+func (input *PhonePoolStore) Sanitize() PhonePoolStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Strategy = strings.ToLower(strings.TrimSpace(input.Strategy))
+	if input.Strategy == "" {
+		input.Strategy = entities.PhonePoolStrategyRoundRobin.String()
+	}
+
+	// the order of the phone numbers is kept because it is the order in which the phones are chosen
+	phoneNumbers := make([]string, 0, len(input.PhoneNumbers))
+	for _, phoneNumber := range input.sanitizeAddresses(input.PhoneNumbers) {
+		if !slices.Contains(phoneNumbers, phoneNumber) {
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+		}
+	}
+	input.PhoneNumbers = phoneNumbers
+	return *input
+}
+
+// ToStoreParams converts PhonePoolStore to services.PhonePoolStoreParams
+func (input *PhonePoolStore) ToStoreParams(user entities.AuthUser) *services.PhonePoolStoreParams {
+	return &services.PhonePoolStoreParams{
+		UserID:       user.ID,
+		Name:         input.Name,
+		Strategy:     entities.PhonePoolStrategy(input.Strategy),
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
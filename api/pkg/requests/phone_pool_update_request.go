@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// PhonePoolUpdate is the payload for updating an entities.PhonePool
+type PhonePoolUpdate struct {
+	PhonePoolStore
+	PoolID string `json:"poolID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to PhonePoolUpdate
+func (input *PhonePoolUpdate) Sanitize() PhonePoolUpdate {
+	input.PhonePoolStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts PhonePoolUpdate to services.PhonePoolUpdateParams
+func (input *PhonePoolUpdate) ToUpdateParams(user entities.AuthUser) *services.PhonePoolUpdateParams {
+	return &services.PhonePoolUpdateParams{
+		UserID:       user.ID,
+		PoolID:       uuid.MustParse(input.PoolID),
+		Name:         input.Name,
+		Strategy:     entities.PhonePoolStrategy(input.Strategy),
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
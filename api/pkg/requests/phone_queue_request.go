@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PhoneQueue is the payload for fetching the queued messages of a phone
+type PhoneQueue struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+	Limit   string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to PhoneQueue
+func (input *PhoneQueue) Sanitize() PhoneQueue {
+	input.Limit = strings.TrimSpace(input.Limit)
+	if input.Limit == "" {
+		input.Limit = "100"
+	}
+	return *input
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneQueue) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
+
+// LimitInt returns the limit as an int
+func (input *PhoneQueue) LimitInt() int {
+	return input.getInt(input.Limit)
+}
@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneQueueStats is the payload for fetching the queue stats of a phone
+type PhoneQueueStats struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneQueueStats) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
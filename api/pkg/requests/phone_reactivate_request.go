@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneReactivate is the payload for reactivating a phone
+type PhoneReactivate struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneReactivate) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhoneRegister is the payload which the app sends to register a phone with its FCM token
+type PhoneRegister struct {
+	request
+	PhoneNumber string `json:"phone_number" example:"+18005550199"`
+
+	// FcmToken is the Firebase Cloud Messaging token which is used to notify the phone of pending messages
+	FcmToken string `json:"fcm_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzd....."`
+
+	// AppVersion is the version of the app, the X-Client-Version header is used when it is empty
+	AppVersion string `json:"app_version" example:"v1.0.0"`
+
+	// SIM is the SIM slot of the phone in case the phone has more than 1 SIM slot
+	SIM string `json:"sim" example:"SIM1"`
+}
+
+// Sanitize sets defaults to PhoneRegister
+func (input *PhoneRegister) Sanitize() PhoneRegister {
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	input.FcmToken = strings.TrimSpace(input.FcmToken)
+	input.AppVersion = strings.TrimSpace(input.AppVersion)
+	input.SIM = input.sanitizeSIM(input.SIM)
+	return *input
+}
+
+// ToRegisterParams converts PhoneRegister to services.PhoneRegisterParams
+func (input *PhoneRegister) ToRegisterParams(user entities.AuthUser, source string, version string) *services.PhoneRegisterParams {
+	phone, _ := phonenumbers.Parse(input.PhoneNumber, phonenumbers.UNKNOWN_REGION)
+
+	appVersion := input.AppVersion
+	if appVersion == "" {
+		appVersion = version
+	}
+
+	return &services.PhoneRegisterParams{
+		Source:      source,
+		PhoneNumber: phone,
+		FcmToken:    input.FcmToken,
+		AppVersion:  appVersion,
+		UserID:      user.ID,
+		SIM:         entities.SIM(input.SIM),
+	}
+}
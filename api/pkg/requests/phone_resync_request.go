@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneResync is the payload for resyncing a phone
+type PhoneResync struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneResync) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
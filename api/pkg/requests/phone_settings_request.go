@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneSettings is the payload for fetching the settings of a phone
+type PhoneSettings struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneSettings) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
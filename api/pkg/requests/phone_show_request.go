@@ -0,0 +1,16 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+)
+
+// PhoneShow is the payload for fetching a phone
+type PhoneShow struct {
+	request
+	PhoneID string `json:"phoneID" swaggerignore:"true"` // used internally for validation
+}
+
+// PhoneIDUuid returns the phoneID as uuid.UUID
+func (input *PhoneShow) PhoneIDUuid() uuid.UUID {
+	return uuid.MustParse(input.PhoneID)
+}
@@ -16,6 +16,9 @@ type PhoneUpsert struct {
 	MessagesPerMinute uint   `json:"messages_per_minute" example:"1"`
 	PhoneNumber       string `json:"phone_number" example:"+18005550199"`
 
+	// MessagesPerHour is the number of messages which the phone sends in an hour before the next messages are delayed. Set it to 0 to remove the limit.
+	MessagesPerHour *uint `json:"messages_per_hour" example:"100"`
+
 	// MessageExpirationSeconds is the duration in seconds after sending a message when it is considered to be expired.
 	MessageExpirationSeconds uint `json:"message_expiration_seconds" example:"12345"`
 
@@ -26,6 +29,30 @@ type PhoneUpsert struct {
 
 	MissedCallAutoReply *string `json:"missed_call_auto_reply" example:"e.g. This phone cannot receive calls. Please send an SMS instead."`
 
+	// BackupPhoneNumber is the phone number of another phone which sends the pending messages when this phone goes offline. Set it to an empty string to remove the backup phone.
+	BackupPhoneNumber *string `json:"backup_phone_number" example:"+18005550100"`
+
+	// HeartbeatTimeoutSeconds is the duration in seconds without a heartbeat after which the phone is considered to be offline.
+	HeartbeatTimeoutSeconds uint `json:"heartbeat_timeout_seconds" example:"3840"`
+
+	// HeartbeatIntervalSeconds is the expected duration in seconds between 2 heartbeats which the app uses to schedule its heartbeats.
+	HeartbeatIntervalSeconds uint `json:"heartbeat_interval_seconds" example:"900"`
+
+	// LowBalanceThreshold is the credit balance of the SIM below which an alert is sent. Set it to 0 to disable the alert.
+	LowBalanceThreshold *float64 `json:"low_balance_threshold" example:"5"`
+
+	// DuplicateWindowSeconds is the duration in seconds after receiving a message during which a duplicate message from the same contact is suppressed. Set it to 0 to disable the suppression.
+	DuplicateWindowSeconds *uint `json:"duplicate_window_seconds" example:"300"`
+
+	// DuplicateStrategy determines how a received message is matched with the previous messages from the same contact e.g. exact or normalized
+	DuplicateStrategy string `json:"duplicate_strategy" example:"exact"`
+
+	// PushProvider is the transport which is used to notify the phone of pending messages which can be "fcm" or "unified_push"
+	PushProvider string `json:"push_provider" example:"fcm"`
+
+	// UnifiedPushEndpoint is the URL of the UnifiedPush distributor e.g. an ntfy topic which is required when the push provider is "unified_push". Set it to an empty string to remove the endpoint.
+	UnifiedPushEndpoint *string `json:"unified_push_endpoint" example:"https://ntfy.sh/upYzMtZGZiNDYx?up=1"`
+
 	// SIM is the SIM slot of the phone in case the phone has more than 1 SIM slot
 	SIM string `json:"sim" example:"SIM1"`
 }
@@ -35,9 +62,25 @@ func (input *PhoneUpsert) Sanitize() PhoneUpsert {
 	input.FcmToken = strings.TrimSpace(input.FcmToken)
 	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
 	input.SIM = input.sanitizeSIM(input.SIM)
+	input.DuplicateStrategy = strings.ToLower(strings.TrimSpace(input.DuplicateStrategy))
+	input.PushProvider = strings.ToLower(strings.TrimSpace(input.PushProvider))
+	if input.UnifiedPushEndpoint != nil {
+		endpoint := strings.TrimSpace(*input.UnifiedPushEndpoint)
+		if endpoint != "" {
+			endpoint = input.sanitizeURL(endpoint)
+		}
+		input.UnifiedPushEndpoint = &endpoint
+	}
 	if input.MissedCallAutoReply != nil {
 		input.MissedCallAutoReply = input.sanitizeStringPointer(*input.MissedCallAutoReply)
 	}
+	if input.BackupPhoneNumber != nil {
+		backupPhoneNumber := strings.TrimSpace(*input.BackupPhoneNumber)
+		if backupPhoneNumber != "" {
+			backupPhoneNumber = input.sanitizeAddress(backupPhoneNumber)
+		}
+		input.BackupPhoneNumber = &backupPhoneNumber
+	}
 	return *input
 }
 
@@ -64,6 +107,40 @@ func (input *PhoneUpsert) ToUpsertParams(user entities.AuthUser, source string)
 		timeout = &duration
 	}
 
+	// ignore default
+	var heartbeatTimeout *time.Duration
+	if input.HeartbeatTimeoutSeconds != 0 {
+		duration := time.Duration(input.HeartbeatTimeoutSeconds) * time.Second
+		heartbeatTimeout = &duration
+	}
+
+	// ignore default
+	var heartbeatInterval *time.Duration
+	if input.HeartbeatIntervalSeconds != 0 {
+		duration := time.Duration(input.HeartbeatIntervalSeconds) * time.Second
+		heartbeatInterval = &duration
+	}
+
+	var duplicateWindow *time.Duration
+	if input.DuplicateWindowSeconds != nil {
+		duration := time.Duration(*input.DuplicateWindowSeconds) * time.Second
+		duplicateWindow = &duration
+	}
+
+	// ignore default
+	var duplicateStrategy *entities.MessageDuplicateStrategy
+	if input.DuplicateStrategy != "" {
+		strategy := entities.MessageDuplicateStrategy(input.DuplicateStrategy)
+		duplicateStrategy = &strategy
+	}
+
+	// ignore default
+	var pushProvider *entities.PhonePushProvider
+	if input.PushProvider != "" {
+		provider := entities.PhonePushProvider(input.PushProvider)
+		pushProvider = &provider
+	}
+
 	var maxSendAttempts *uint
 	if input.MaxSendAttempts != 0 {
 		maxSendAttempts = &input.MaxSendAttempts
@@ -73,8 +150,17 @@ func (input *PhoneUpsert) ToUpsertParams(user entities.AuthUser, source string)
 		Source:                    source,
 		PhoneNumber:               phone,
 		MessagesPerMinute:         messagesPerMinute,
+		MessagesPerHour:           input.MessagesPerHour,
 		MissedCallAutoReply:       input.MissedCallAutoReply,
+		BackupPhoneNumber:         input.BackupPhoneNumber,
 		MessageExpirationDuration: timeout,
+		HeartbeatTimeout:          heartbeatTimeout,
+		HeartbeatInterval:         heartbeatInterval,
+		LowBalanceThreshold:       input.LowBalanceThreshold,
+		DuplicateWindow:           duplicateWindow,
+		DuplicateStrategy:         duplicateStrategy,
+		PushProvider:              pushProvider,
+		UnifiedPushEndpoint:       input.UnifiedPushEndpoint,
 		MaxSendAttempts:           maxSendAttempts,
 		FcmToken:                  fcmToken,
 		UserID:                    user.ID,
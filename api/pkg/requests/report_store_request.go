@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ReportStore is the payload for scheduling a new entities.Report
+type ReportStore struct {
+	request
+	Type     string `json:"type" example:"daily-usage"`
+	Delivery string `json:"delivery" example:"email"`
+}
+
+// Sanitize sets defaults to ReportStore
+func (input *ReportStore) Sanitize() ReportStore {
+	input.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	input.Delivery = strings.ToLower(strings.TrimSpace(input.Delivery))
+	return *input
+}
+
+// ToStoreParams converts ReportStore to services.ReportStoreParams
+func (input *ReportStore) ToStoreParams(user entities.AuthUser) *services.ReportStoreParams {
+	return &services.ReportStoreParams{
+		UserID:   user.ID,
+		Type:     entities.ReportType(input.Type),
+		Delivery: entities.ReportDelivery(input.Delivery),
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -74,6 +77,18 @@ func (input *request) sanitizeSIM(value string) string {
 	return entities.SIM1.String()
 }
 
+func (input *request) sanitizeCategory(value string) string {
+	return entities.MessageCategorySanitized(entities.MessageCategory(strings.ToLower(strings.TrimSpace(value)))).String()
+}
+
+func (input *request) sanitizePriority(value string) string {
+	return entities.MessagePrioritySanitized(entities.MessagePriority(strings.ToLower(strings.TrimSpace(value)))).String()
+}
+
+func (input *request) sanitizeSenderName(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
 func (input *request) sanitizeURL(value string) string {
 	value = strings.TrimSpace(value)
 	website, err := url.Parse(value)
@@ -86,6 +101,33 @@ func (input *request) sanitizeURL(value string) string {
 	return value
 }
 
+func (input *request) sanitizeLabel(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// sanitizeContent removes the control characters except new lines and tabs and the invisible zero-width characters from the
+// content of a message which break the android sender and the webhook consumers. Content which is not valid UTF-8 is kept
+// as it is so that it can be rejected by the validator.
+func (input *request) sanitizeContent(value string) string {
+	if !utf8.ValidString(value) {
+		return value
+	}
+
+	return strings.Map(func(char rune) rune {
+		switch {
+		case char == '\n' || char == '\r' || char == '\t':
+			return char
+		case unicode.IsControl(char):
+			return -1
+		// the zero-width joiner and non-joiner are kept because they are needed to render emoji sequences and some scripts
+		case char == '\u200B' || char == '\u2060' || char == '\uFEFF' || char == '\u180E':
+			return -1
+		default:
+			return char
+		}
+	}, value)
+}
+
 func (input *request) sanitizeStringPointer(value string) *string {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -94,6 +136,21 @@ func (input *request) sanitizeStringPointer(value string) *string {
 	return &value
 }
 
+// getExpiresAt computes the expiry time of a message from the validity period in seconds when expiresAt is not set
+func (input *request) getExpiresAt(expiresAt *time.Time, validityPeriod uint, sendAt *time.Time) *time.Time {
+	if expiresAt != nil || validityPeriod == 0 {
+		return expiresAt
+	}
+
+	start := time.Now().UTC()
+	if sendAt != nil && sendAt.After(start) {
+		start = *sendAt
+	}
+
+	result := start.Add(time.Duration(validityPeriod) * time.Second)
+	return &result
+}
+
 func (input *request) removeStringDuplicates(values []string) []string {
 	cache := map[string]struct{}{}
 	for _, value := range values {
@@ -127,12 +184,30 @@ func (input *request) getBool(value string) bool {
 	return false
 }
 
+// getBoolPointer returns nil when a boolean string which has already been sanitized is empty
+func (input *request) getBoolPointer(value string) *bool {
+	if value == "" {
+		return nil
+	}
+	result := input.getBool(value)
+	return &result
+}
+
 // getLimit gets the take as a string
 func (input *request) getInt(value string) int {
 	val, _ := strconv.Atoi(value)
 	return val
 }
 
+// getCursor decodes a cursor which has already been validated
+func (input *request) getCursor(value string) *repositories.Cursor {
+	if value == "" {
+		return nil
+	}
+	cursor, _ := repositories.DecodeCursor(value)
+	return cursor
+}
+
 func (input *request) isDigits(value string) bool {
 	for _, c := range value {
 		if !unicode.IsDigit(c) {
@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// RoutingRuleStore is the payload for creating a new entities.RoutingRule
+type RoutingRuleStore struct {
+	request
+	Name        string `json:"name" example:"US customers"`
+	Position    uint   `json:"position" example:"1"`
+	Prefix      string `json:"prefix" example:"+1800"`
+	Country     string `json:"country" example:"US"`
+	Tag         string `json:"tag" example:"alerts"`
+	PhoneNumber string `json:"phone_number" example:"+18005550199"`
+}
+
+// Sanitize sets defaults to RoutingRuleStore
+func (input *RoutingRuleStore) Sanitize() RoutingRuleStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Prefix = strings.TrimSpace(input.Prefix)
+	input.Country = strings.ToUpper(strings.TrimSpace(input.Country))
+	input.Tag = strings.ToLower(strings.TrimSpace(input.Tag))
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	return *input
+}
+
+// ToStoreParams converts RoutingRuleStore to services.RoutingRuleStoreParams
+func (input *RoutingRuleStore) ToStoreParams(user entities.AuthUser) *services.RoutingRuleStoreParams {
+	return &services.RoutingRuleStoreParams{
+		UserID:      user.ID,
+		Name:        input.Name,
+		Position:    input.Position,
+		Prefix:      input.Prefix,
+		Country:     input.Country,
+		Tag:         input.Tag,
+		PhoneNumber: input.PhoneNumber,
+	}
+}
@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// RoutingRuleUpdate is the payload for updating an entities.RoutingRule
+type RoutingRuleUpdate struct {
+	RoutingRuleStore
+	RuleID string `json:"ruleID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to RoutingRuleUpdate
+func (input *RoutingRuleUpdate) Sanitize() RoutingRuleUpdate {
+	input.RoutingRuleStore.Sanitize()
+	return *input
+}
+
+// ToUpdateParams converts RoutingRuleUpdate to services.RoutingRuleUpdateParams
+func (input *RoutingRuleUpdate) ToUpdateParams(user entities.AuthUser) *services.RoutingRuleUpdateParams {
+	return &services.RoutingRuleUpdateParams{
+		UserID:      user.ID,
+		RuleID:      uuid.MustParse(input.RuleID),
+		Name:        input.Name,
+		Position:    input.Position,
+		Prefix:      input.Prefix,
+		Country:     input.Country,
+		Tag:         input.Tag,
+		PhoneNumber: input.PhoneNumber,
+	}
+}
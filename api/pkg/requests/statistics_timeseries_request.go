@@ -0,0 +1,136 @@
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+const (
+	statisticsMinInterval  = time.Minute
+	statisticsMaxDatapoint = 10_000
+)
+
+// StatisticsTimeseries is the query which is sent by the Grafana JSON datasource
+type StatisticsTimeseries struct {
+	request
+	Range         StatisticsTimeseriesRange    `json:"range"`
+	IntervalMs    int64                        `json:"intervalMs" example:"60000"`
+	MaxDataPoints int64                        `json:"maxDataPoints" example:"1000"`
+	Targets       []StatisticsTimeseriesTarget `json:"targets"`
+}
+
+// StatisticsTimeseriesRange is the time range of the Grafana dashboard
+type StatisticsTimeseriesRange struct {
+	From time.Time `json:"from" example:"2022-06-05T00:00:00Z"`
+	To   time.Time `json:"to" example:"2022-06-06T00:00:00Z"`
+}
+
+// StatisticsTimeseriesTarget is a metric in the Grafana query
+type StatisticsTimeseriesTarget struct {
+	Target string `json:"target" example:"messages.sent"`
+	RefID  string `json:"refId" example:"A"`
+	Hide   bool   `json:"hide" example:"false"`
+	// Payload is an optional object e.g. {"owner": "+18005550199", "sender_name": "billing", "category": "marketing"} to filter the metric by phone, sender name and category
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+type statisticsTimeseriesTargetPayload struct {
+	Owner      string `json:"owner,omitempty"`
+	SenderName string `json:"sender_name,omitempty"`
+	Category   string `json:"category,omitempty"`
+}
+
+// Owner returns the phone number in the payload of the target
+func (input *StatisticsTimeseriesTarget) Owner() string {
+	return input.payload().Owner
+}
+
+// SenderName returns the sender name in the payload of the target
+func (input *StatisticsTimeseriesTarget) SenderName() string {
+	return input.payload().SenderName
+}
+
+// Category returns the message category in the payload of the target
+func (input *StatisticsTimeseriesTarget) Category() string {
+	return input.payload().Category
+}
+
+func (input *StatisticsTimeseriesTarget) payload() (payload statisticsTimeseriesTargetPayload) {
+
+	// the payload is an object or a string containing an object depending on the version of the Grafana JSON datasource
+	content := input.Payload
+	var value string
+	if err := json.Unmarshal(input.Payload, &value); err == nil {
+		content = json.RawMessage(value)
+	}
+
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return statisticsTimeseriesTargetPayload{}
+	}
+	return payload
+}
+
+// Sanitize sets defaults to StatisticsTimeseries
+func (input *StatisticsTimeseries) Sanitize() StatisticsTimeseries {
+	var targets []StatisticsTimeseriesTarget
+	for _, target := range input.Targets {
+		target.Target = strings.TrimSpace(target.Target)
+		if target.Hide || target.Target == "" {
+			continue
+		}
+		if payload := target.payload(); payload.Owner != "" || payload.SenderName != "" || payload.Category != "" {
+			if payload.Owner != "" {
+				payload.Owner = input.sanitizeAddress(payload.Owner)
+			}
+			payload.SenderName = input.sanitizeSenderName(payload.SenderName)
+			payload.Category = strings.ToLower(strings.TrimSpace(payload.Category))
+			target.Payload, _ = json.Marshal(payload)
+		}
+		targets = append(targets, target)
+	}
+	input.Targets = targets
+	return *input
+}
+
+// ToTimeseriesParams converts StatisticsTimeseries to services.StatisticsTimeseriesParams
+func (input *StatisticsTimeseries) ToTimeseriesParams(userID entities.UserID) *services.StatisticsTimeseriesParams {
+	params := &services.StatisticsTimeseriesParams{
+		UserID:   userID,
+		From:     input.Range.From.UTC(),
+		To:       input.Range.To.UTC(),
+		Interval: input.interval(),
+	}
+
+	for _, target := range input.Targets {
+		params.Targets = append(params.Targets, services.StatisticsTimeseriesTarget{
+			Name:       target.Target,
+			Owner:      target.Owner(),
+			SenderName: target.SenderName(),
+			Category:   entities.MessageCategory(target.Category()),
+		})
+	}
+	return params
+}
+
+// interval is the duration of a bucket so that the number of data points does not exceed the maxDataPoints of the panel
+func (input *StatisticsTimeseries) interval() time.Duration {
+	interval := time.Duration(input.IntervalMs) * time.Millisecond
+
+	maxDataPoints := input.MaxDataPoints
+	if maxDataPoints <= 0 || maxDataPoints > statisticsMaxDatapoint {
+		maxDataPoints = statisticsMaxDatapoint
+	}
+
+	if minInterval := input.Range.To.Sub(input.Range.From) / time.Duration(maxDataPoints); interval < minInterval {
+		interval = minInterval
+	}
+
+	if interval < statisticsMinInterval {
+		interval = statisticsMinInterval
+	}
+	return interval.Round(time.Second)
+}
@@ -14,12 +14,28 @@ type UserUpdate struct {
 	request
 	Timezone      string `json:"timezone" example:"Europe/Helsinki"`
 	ActivePhoneID string `json:"active_phone_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	// OptOutFooter is appended to marketing messages e.g. "Reply STOP to unsubscribe". Set it to an empty string to disable the footer.
+	OptOutFooter *string `json:"opt_out_footer" example:"Reply STOP to unsubscribe" validate:"optional"`
+	// PreferredLanguage is the language e.g. "en" which received messages are translated to. Set it to an empty string to disable translations.
+	PreferredLanguage *string `json:"preferred_language" example:"en" validate:"optional"`
+	// HeartbeatOfflineThreshold is the number of consecutive missed heartbeats after which a phone is offline. Set it to 0 to use the heartbeat timeout of the phone.
+	HeartbeatOfflineThreshold *uint `json:"heartbeat_offline_threshold" example:"3" validate:"optional"`
+	// HeartbeatAlertCooldownSeconds is the duration in seconds after a phone goes offline during which it is not reported as offline again. Set it to 0 to disable the cool-down.
+	HeartbeatAlertCooldownSeconds *uint `json:"heartbeat_alert_cooldown_seconds" example:"3600" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
 func (input *UserUpdate) Sanitize() UserUpdate {
 	input.ActivePhoneID = strings.TrimSpace(input.ActivePhoneID)
 	input.Timezone = strings.TrimSpace(input.Timezone)
+	if input.OptOutFooter != nil {
+		footer := strings.TrimSpace(*input.OptOutFooter)
+		input.OptOutFooter = &footer
+	}
+	if input.PreferredLanguage != nil {
+		language := strings.ToLower(strings.TrimSpace(*input.PreferredLanguage))
+		input.PreferredLanguage = &language
+	}
 	return *input
 }
 
@@ -36,8 +52,18 @@ func (input *UserUpdate) ToUpdateParams() services.UserUpdateParams {
 		activePhoneID = &val
 	}
 
+	var heartbeatAlertCooldown *time.Duration
+	if input.HeartbeatAlertCooldownSeconds != nil {
+		duration := time.Duration(*input.HeartbeatAlertCooldownSeconds) * time.Second
+		heartbeatAlertCooldown = &duration
+	}
+
 	return services.UserUpdateParams{
-		ActivePhoneID: activePhoneID,
-		Timezone:      location,
+		ActivePhoneID:             activePhoneID,
+		Timezone:                  location,
+		OptOutFooter:              input.OptOutFooter,
+		PreferredLanguage:         input.PreferredLanguage,
+		HeartbeatOfflineThreshold: input.HeartbeatOfflineThreshold,
+		HeartbeatAlertCooldown:    heartbeatAlertCooldown,
 	}
 }
@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
+)
+
+// WebhookReplay is the payload for replaying the stored events of a time range to an entities.Webhook
+type WebhookReplay struct {
+	request
+	WebhookID string `json:"webhookID" swaggerignore:"true"` // used internally for validation
+	// From is the start of the time range in RFC3339 format
+	From string `json:"from" example:"2022-06-05T00:00:00Z"`
+	// To is the end of the time range in RFC3339 format
+	To string `json:"to" example:"2022-06-06T00:00:00Z"`
+	// Events limits the replay to these event types, all the events of the webhook are replayed when it is empty
+	Events []string `json:"events" example:"message.phone.received"`
+}
+
+// Sanitize sets defaults to WebhookReplay
+func (input *WebhookReplay) Sanitize() WebhookReplay {
+	input.From = strings.TrimSpace(input.From)
+	input.To = strings.TrimSpace(input.To)
+	input.Events = input.removeStringDuplicates(input.Events)
+	return *input
+}
+
+// FromTime returns the start of the time range
+func (input *WebhookReplay) FromTime() time.Time {
+	from, _ := time.Parse(time.RFC3339, input.From)
+	return from.UTC()
+}
+
+// ToTime returns the end of the time range
+func (input *WebhookReplay) ToTime() time.Time {
+	to, _ := time.Parse(time.RFC3339, input.To)
+	return to.UTC()
+}
+
+// ToReplayParams converts WebhookReplay to services.WebhookReplayParams
+func (input *WebhookReplay) ToReplayParams(user entities.AuthUser) *services.WebhookReplayParams {
+	return &services.WebhookReplayParams{
+		UserID:    user.ID,
+		WebhookID: uuid.MustParse(input.WebhookID),
+		From:      input.FromTime(),
+		To:        input.ToTime(),
+		Events:    input.Events,
+	}
+}
@@ -10,17 +10,32 @@ import (
 // WebhookStore is the payload for creating a new entities.Webhook
 type WebhookStore struct {
 	request
-	SigningKey   string   `json:"signing_key"`
-	URL          string   `json:"url"`
-	PhoneNumbers []string `json:"phone_numbers" example:"+18005550100,+18005550100"`
-	Events       []string `json:"events"`
+	SigningKey      string   `json:"signing_key"`
+	URL             string   `json:"url"`
+	PhoneNumbers    []string `json:"phone_numbers" example:"+18005550100,+18005550100"`
+	Events          []string `json:"events"`
+	PayloadTemplate string   `json:"payload_template" example:"{\"text\": {{ json .Data.content }}}"`
+	// Languages limits the received messages which are sent to the webhook to the detected languages e.g. ["fr"]
+	Languages []string `json:"languages" example:"fr"`
+	// TimeoutSeconds is the duration in seconds to wait for the response of the webhook which defaults to 10 seconds
+	TimeoutSeconds uint `json:"timeout_seconds" example:"10"`
 }
 
 // Sanitize sets defaults to WebhookStore
 func (input *WebhookStore) Sanitize() WebhookStore {
 	input.URL = input.sanitizeURL(input.URL)
 	input.SigningKey = strings.TrimSpace(input.SigningKey)
+	input.PayloadTemplate = strings.TrimSpace(input.PayloadTemplate)
 	input.Events = input.removeStringDuplicates(input.Events)
+	if input.TimeoutSeconds == 0 {
+		input.TimeoutSeconds = 10
+	}
+
+	var languages []string
+	for _, language := range input.Languages {
+		languages = append(languages, strings.ToLower(strings.TrimSpace(language)))
+	}
+	input.Languages = input.removeStringDuplicates(languages)
 
 	var phoneNumbers []string
 	for _, address := range input.PhoneNumbers {
@@ -33,10 +48,13 @@ func (input *WebhookStore) Sanitize() WebhookStore {
 // ToStoreParams converts WebhookStore to services.WebhookStoreParams
 func (input *WebhookStore) ToStoreParams(user entities.AuthUser) *services.WebhookStoreParams {
 	return &services.WebhookStoreParams{
-		UserID:       user.ID,
-		SigningKey:   input.SigningKey,
-		URL:          input.URL,
-		PhoneNumbers: input.PhoneNumbers,
-		Events:       input.Events,
+		UserID:          user.ID,
+		SigningKey:      input.SigningKey,
+		URL:             input.URL,
+		PhoneNumbers:    input.PhoneNumbers,
+		Events:          input.Events,
+		PayloadTemplate: input.PayloadTemplate,
+		Languages:       input.Languages,
+		TimeoutSeconds:  input.TimeoutSeconds,
 	}
 }
@@ -13,3 +13,9 @@ type BillingUsageResponse struct {
 	response
 	Data entities.BillingUsage `json:"data"`
 }
+
+// APIUsageResponse is the payload containing entities.APIUsage
+type APIUsageResponse struct {
+	response
+	Data entities.APIUsage `json:"data"`
+}
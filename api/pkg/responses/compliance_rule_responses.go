@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ComplianceRuleResponse is the payload containing entities.ComplianceRule
+type ComplianceRuleResponse struct {
+	response
+	Data entities.ComplianceRule `json:"data"`
+}
+
+// ComplianceRulesResponse is the payload containing []entities.ComplianceRule
+type ComplianceRulesResponse struct {
+	response
+	Data []entities.ComplianceRule `json:"data"`
+}
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ContactPreferenceResponse is the payload containing an entities.ContactPreference
+type ContactPreferenceResponse struct {
+	response
+	Data entities.ContactPreference `json:"data"`
+}
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// CustomDomainResponse is the payload containing an entities.CustomDomain
+type CustomDomainResponse struct {
+	response
+	Data entities.CustomDomain `json:"data"`
+}
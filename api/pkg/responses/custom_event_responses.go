@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// CustomEventResponse is the payload containing entities.CustomEvent
+type CustomEventResponse struct {
+	response
+	Data entities.CustomEvent `json:"data"`
+}
+
+// CustomEventsResponse is the payload containing []entities.CustomEvent
+type CustomEventsResponse struct {
+	response
+	Data []entities.CustomEvent `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// DebugRequestsResponse is the payload containing []entities.DebugRequest
+type DebugRequestsResponse struct {
+	response
+	Data []entities.DebugRequest `json:"data"`
+}
+
+// DebugModeResponse is the payload containing entities.DebugMode
+type DebugModeResponse struct {
+	response
+	Data entities.DebugMode `json:"data"`
+}
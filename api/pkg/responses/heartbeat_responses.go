@@ -13,3 +13,21 @@ type HeartbeatResponse struct {
 	response
 	Data entities.Heartbeat `json:"data"`
 }
+
+// HeartbeatSignalResponse is the payload containing []entities.HeartbeatSignalBucket
+type HeartbeatSignalResponse struct {
+	response
+	Data []entities.HeartbeatSignalBucket `json:"data"`
+}
+
+// HeartbeatReportResponse is the payload containing entities.HeartbeatReport
+type HeartbeatReportResponse struct {
+	response
+	Data entities.HeartbeatReport `json:"data"`
+}
+
+// HeartbeatRollupsResponse is the payload containing []entities.HeartbeatRollup
+type HeartbeatRollupsResponse struct {
+	response
+	Data []entities.HeartbeatRollup `json:"data"`
+}
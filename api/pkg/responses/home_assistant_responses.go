@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// HomeAssistantDiscoveryResponse is the payload containing entities.HomeAssistantDiscovery
+type HomeAssistantDiscoveryResponse struct {
+	response
+	Data entities.HomeAssistantDiscovery `json:"data"`
+}
+
+// HomeAssistantSensorResponse is the payload containing entities.HomeAssistantSensor
+type HomeAssistantSensorResponse struct {
+	response
+	Data entities.HomeAssistantSensor `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// InboxRuleResponse is the payload containing entities.InboxRule
+type InboxRuleResponse struct {
+	response
+	Data entities.InboxRule `json:"data"`
+}
+
+// InboxRulesResponse is the payload containing []entities.InboxRule
+type InboxRulesResponse struct {
+	response
+	Data []entities.InboxRule `json:"data"`
+}
@@ -8,8 +8,16 @@ type MessageResponse struct {
 	Data entities.Message `json:"data"`
 }
 
+// MessageSimulationResponse is the payload containing an entities.MessageSimulation
+type MessageSimulationResponse struct {
+	response
+	Data entities.MessageSimulation `json:"data"`
+}
+
 // MessagesResponse is the payload containing []entities.Message
 type MessagesResponse struct {
 	response
 	Data []entities.Message `json:"data"`
+	// NextCursor is used to fetch the next page when there are more messages
+	NextCursor *string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NhIn0"`
 }
@@ -6,4 +6,42 @@ import "github.com/NdoleStudio/httpsms/pkg/entities"
 type MessageThreadsResponse struct {
 	response
 	Data []entities.MessageThread `json:"data"`
+	// NextCursor is used to fetch the next page when there are more message threads
+	NextCursor *string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NhIn0"`
+}
+
+// MessageThreadResponse is the payload containing entities.MessageThread
+type MessageThreadResponse struct {
+	response
+	Data entities.MessageThread `json:"data"`
+}
+
+// MessageThreadBulkDeleteResponse is the payload containing entities.MessageThreadBulkDelete
+type MessageThreadBulkDeleteResponse struct {
+	response
+	Data entities.MessageThreadBulkDelete `json:"data"`
+}
+
+// MessageThreadContextResponse is the payload containing entities.MessageThreadContext
+type MessageThreadContextResponse struct {
+	response
+	Data entities.MessageThreadContext `json:"data"`
+}
+
+// MessageThreadShareResponse is the payload containing entities.MessageThreadShare
+type MessageThreadShareResponse struct {
+	response
+	Data entities.MessageThreadShare `json:"data"`
+}
+
+// MessageThreadSummaryResponse is the payload containing entities.MessageThreadSummary
+type MessageThreadSummaryResponse struct {
+	response
+	Data entities.MessageThreadSummary `json:"data"`
+}
+
+// MessageThreadTopicCountsResponse is the number of message threads of a phone with each topic and language
+type MessageThreadTopicCountsResponse struct {
+	response
+	Data []entities.MessageThreadTopicCount `json:"data"`
 }
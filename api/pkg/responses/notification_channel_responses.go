@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// NotificationChannelResponse is the payload containing entities.NotificationChannel
+type NotificationChannelResponse struct {
+	response
+	Data entities.NotificationChannel `json:"data"`
+}
+
+// NotificationChannelsResponse is the payload containing []entities.NotificationChannel
+type NotificationChannelsResponse struct {
+	response
+	Data []entities.NotificationChannel `json:"data"`
+}
@@ -13,3 +13,81 @@ type PhoneResponse struct {
 	response
 	Data entities.Phone `json:"data"`
 }
+
+// PhoneResyncResponse is the payload containing the entities.PhoneResync of a phone
+type PhoneResyncResponse struct {
+	response
+	Data entities.PhoneResync `json:"data"`
+}
+
+// PhoneSettingsResponse is the payload containing the entities.PhoneSettings of a phone
+type PhoneSettingsResponse struct {
+	response
+	Data entities.PhoneSettings `json:"data"`
+}
+
+// PhoneQueueStatsResponse is the payload containing the entities.PhoneQueueStats of a phone
+type PhoneQueueStatsResponse struct {
+	response
+	Data entities.PhoneQueueStats `json:"data"`
+}
+
+// PhoneQueueResponse is the payload containing the entities.PhoneQueueEntry of a phone
+type PhoneQueueResponse struct {
+	response
+	Data []entities.PhoneQueueEntry `json:"data"`
+}
+
+// PhoneLogRequestResponse is the payload containing the entities.PhoneLogRequest of a phone
+type PhoneLogRequestResponse struct {
+	response
+	Data entities.PhoneLogRequest `json:"data"`
+}
+
+// PhoneLogResponse is the payload containing an entities.PhoneLog
+type PhoneLogResponse struct {
+	response
+	Data entities.PhoneLog `json:"data"`
+}
+
+// PhoneLogsResponse is the payload containing []entities.PhoneLog
+type PhoneLogsResponse struct {
+	response
+	Data []entities.PhoneLog `json:"data"`
+}
+
+// PhoneCrashResponse is the payload containing an entities.PhoneCrash
+type PhoneCrashResponse struct {
+	response
+	Data entities.PhoneCrash `json:"data"`
+}
+
+// PhoneCrashesResponse is the payload containing []entities.PhoneCrash
+type PhoneCrashesResponse struct {
+	response
+	Data []entities.PhoneCrash `json:"data"`
+}
+
+// PhoneMaintenanceWindowResponse is the payload containing an entities.PhoneMaintenanceWindow
+type PhoneMaintenanceWindowResponse struct {
+	response
+	Data entities.PhoneMaintenanceWindow `json:"data"`
+}
+
+// PhoneMaintenanceWindowsResponse is the payload containing []entities.PhoneMaintenanceWindow
+type PhoneMaintenanceWindowsResponse struct {
+	response
+	Data []entities.PhoneMaintenanceWindow `json:"data"`
+}
+
+// PhoneBalanceResponse is the payload containing an entities.PhoneBalance
+type PhoneBalanceResponse struct {
+	response
+	Data entities.PhoneBalance `json:"data"`
+}
+
+// PhoneBalancesResponse is the payload containing []entities.PhoneBalance
+type PhoneBalancesResponse struct {
+	response
+	Data []entities.PhoneBalance `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ReportResponse is the payload containing entities.Report
+type ReportResponse struct {
+	response
+	Data entities.Report `json:"data"`
+}
+
+// ReportsResponse is the payload containing []entities.Report
+type ReportsResponse struct {
+	response
+	Data []entities.Report `json:"data"`
+}
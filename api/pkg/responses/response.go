@@ -24,11 +24,18 @@ type BadRequest struct {
 	Data    string `json:"data" example:"The request body is not a valid JSON string"`
 }
 
+// RequestEntityTooLarge is the response with status code is 413
+type RequestEntityTooLarge struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"The request body is too large"`
+	Data    string `json:"data" example:"the request body must be less than 16 KiB"`
+}
+
 // UnprocessableEntity is the response with status code is 422
 type UnprocessableEntity struct {
-	Status  string              `json:"status" example:"error"`
-	Message string              `json:"message" example:"validation errors while sending message"`
-	Data    map[string][]string `json:"data"`
+	Status  string           `json:"status" example:"error"`
+	Message string           `json:"message" example:"validation errors while sending message"`
+	Data    ValidationErrors `json:"data"`
 }
 
 // Unauthorized is the response with status code is 403
@@ -38,6 +45,13 @@ type Unauthorized struct {
 	Data    string `json:"data" example:"Make sure your API key is set in the [X-API-Key] header in the request"`
 }
 
+// Forbidden is the response with status code is 403 when the API key does not have the scope of the request
+type Forbidden struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"Your API key does not have the scope to carry out this request."`
+	Data    string `json:"data" example:"The request needs an API key with the [admin] scope"`
+}
+
 // NoContent is the response when status code is 204
 type NoContent struct {
 	Status  string `json:"status" example:"success"`
@@ -0,0 +1,22 @@
+package responses
+
+// GrafanaMetric is a metric which can be selected in the query editor of the Grafana JSON datasource
+type GrafanaMetric struct {
+	Label    string                 `json:"label" example:"messages.sent"`
+	Value    string                 `json:"value" example:"messages.sent"`
+	Payloads []GrafanaMetricPayload `json:"payloads"`
+}
+
+// GrafanaMetricPayload is an option of a GrafanaMetric in the query editor of the Grafana JSON datasource
+type GrafanaMetricPayload struct {
+	Label       string `json:"label" example:"Phone"`
+	Name        string `json:"name" example:"owner"`
+	Type        string `json:"type" example:"select"`
+	Placeholder string `json:"placeholder" example:"All phones"`
+}
+
+// GrafanaPayloadOption is a value of a GrafanaMetricPayload with the "select" type
+type GrafanaPayloadOption struct {
+	Label string `json:"label" example:"+18005550199"`
+	Value string `json:"value" example:"+18005550199"`
+}
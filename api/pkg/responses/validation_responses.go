@@ -0,0 +1,27 @@
+package responses
+
+// ValidationError is a machine-readable error for a field of a request which failed validation
+type ValidationError struct {
+	Field   string `json:"field" example:"to"`
+	Rule    string `json:"rule" example:"required"`
+	Message string `json:"message" example:"The to field is required"`
+	Param   string `json:"param,omitempty" example:""`
+}
+
+// ValidationErrors are the errors of all the fields in a request which failed validation
+type ValidationErrors []ValidationError
+
+// Add a ValidationError for a rule without a parameter
+func (errors *ValidationErrors) Add(field string, rule string, message string) {
+	errors.AddWithParam(field, rule, "", message)
+}
+
+// AddWithParam adds a ValidationError for a rule with a parameter e.g. the limit of the "max" rule
+func (errors *ValidationErrors) AddWithParam(field string, rule string, param string, message string) {
+	*errors = append(*errors, ValidationError{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+		Param:   param,
+	})
+}
@@ -13,3 +13,9 @@ type WebhooksResponse struct {
 	response
 	Data []entities.Webhook `json:"data"`
 }
+
+// WebhookReplayResponse is the payload containing entities.WebhookReplay
+type WebhookReplayResponse struct {
+	response
+	Data entities.WebhookReplay `json:"data"`
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// apiKeyLength is the number of characters of a generated entities.APIKey which is the same as the API key of the account
+const apiKeyLength = 64
+
+// APIKeyService manages the additional API keys of a user
+type APIKeyService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.APIKeyRepository,
+) (s *APIKeyService) {
+	return &APIKeyService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.APIKey of a user
+func (service *APIKeyService) Index(ctx context.Context, userID entities.UserID) ([]entities.APIKey, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	keys, err := service.repository.Index(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch api keys for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return keys, nil
+}
+
+// APIKeyStoreParams are parameters for creating a new entities.APIKey
+type APIKeyStoreParams struct {
+	UserID         entities.UserID
+	Name           string
+	Scopes         pq.StringArray
+	DefaultPhoneID *uuid.UUID
+}
+
+// Store creates a new entities.APIKey with a random key
+func (service *APIKeyService) Store(ctx context.Context, params *APIKeyStoreParams) (*entities.APIKey, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	value, err := service.generateKey()
+	if err != nil {
+		msg := fmt.Sprintf("cannot generate api key for user [%s]", params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	key := &entities.APIKey{
+		ID:             uuid.New(),
+		UserID:         params.UserID,
+		Name:           params.Name,
+		Key:            value,
+		Scopes:         params.Scopes,
+		DefaultPhoneID: params.DefaultPhoneID,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, key); err != nil {
+		msg := fmt.Sprintf("cannot store api key with ID [%s] for user [%s]", key.ID, key.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("api key with ID [%s] and scopes [%v] created for user [%s]", key.ID, key.Scopes, key.UserID))
+	return key, nil
+}
+
+// Delete an entities.APIKey of a user
+func (service *APIKeyService) Delete(ctx context.Context, userID entities.UserID, keyID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, keyID); err != nil {
+		msg := fmt.Sprintf("cannot load api key with ID [%s] for user [%s]", keyID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, keyID); err != nil {
+		msg := fmt.Sprintf("cannot delete api key with ID [%s] for user [%s]", keyID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted api key with ID [%s] for user [%s]", keyID, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.APIKey for an entities.UserID
+func (service *APIKeyService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete all [entities.APIKey] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.APIKey] for user with ID [%s]", userID))
+	return nil
+}
+
+// generateKey returns a URL-safe random key with the same length as the API key of the account
+func (service *APIKeyService) generateKey() (string, error) {
+	b := make([]byte, apiKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", stacktrace.Propagate(err, fmt.Sprintf("cannot generate [%d] random bytes", apiKeyLength))
+	}
+	return base64.URLEncoding.EncodeToString(b)[0:apiKeyLength], nil
+}
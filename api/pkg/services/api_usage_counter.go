@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// apiUsageCounterKeyPrefix is the prefix of the redis hash which stores the usage of every endpoint of a user
+	apiUsageCounterKeyPrefix = "api-usage:user:"
+
+	// apiUsageCounterSinceKey stores the time in milliseconds when the first request was counted
+	apiUsageCounterSinceKey = "api-usage:since"
+
+	// redisCounterFieldSeparator separates the parts of the fields of a counter hash e.g. "<api key>\n<method>\n<path>\nrequests"
+	redisCounterFieldSeparator = "\n"
+)
+
+// apiUsageCounterScript adds a request to the hash of a user. ARGV[1] is the endpoint, ARGV[2] is "1" for an error,
+// ARGV[3] is the latency in microseconds and ARGV[4] is the time of the request in milliseconds.
+var apiUsageCounterScript = redis.NewScript(`
+redis.call("SET", KEYS[2], ARGV[4], "NX")
+
+local endpoint = ARGV[1] .. "\n"
+redis.call("HINCRBY", KEYS[1], endpoint .. "requests", 1)
+if ARGV[2] == "1" then
+	redis.call("HINCRBY", KEYS[1], endpoint .. "errors", 1)
+end
+redis.call("HINCRBY", KEYS[1], endpoint .. "total_latency", ARGV[3])
+
+local maxLatency = tonumber(redis.call("HGET", KEYS[1], endpoint .. "max_latency") or "0")
+if tonumber(ARGV[3]) > maxLatency then
+	redis.call("HSET", KEYS[1], endpoint .. "max_latency", ARGV[3])
+end
+
+redis.call("HSET", KEYS[1], endpoint .. "last_request_at", ARGV[4])
+return 1
+`)
+
+type apiUsageRecord struct {
+	requests      uint64
+	errors        uint64
+	totalLatency  time.Duration
+	maxLatency    time.Duration
+	lastRequestAt time.Time
+}
+
+// APIUsageCounter counts the requests, errors and latency of every endpoint per API key. The counts are stored in redis
+// so they are shared by the children of a prefork server and the other instances of the API and they survive restarts.
+type APIUsageCounter struct {
+	logger telemetry.Logger
+	client *redis.Client
+}
+
+// NewAPIUsageCounter creates a new APIUsageCounter
+func NewAPIUsageCounter(logger telemetry.Logger, client *redis.Client) (counter *APIUsageCounter) {
+	return &APIUsageCounter{
+		logger: logger.WithService(fmt.Sprintf("%T", counter)),
+		client: client,
+	}
+}
+
+// RecordRequest adds a request to an endpoint with an API key. The request is an error when the status code is 400 or above.
+func (counter *APIUsageCounter) RecordRequest(ctx context.Context, userID entities.UserID, apiKey string, method string, path string, statusCode int, latency time.Duration) {
+	isError := "0"
+	if statusCode >= 400 {
+		isError = "1"
+	}
+
+	err := apiUsageCounterScript.Run(
+		context.WithoutCancel(ctx),
+		counter.client,
+		[]string{apiUsageCounterKeyPrefix + string(userID), apiUsageCounterSinceKey},
+		strings.Join([]string{apiKey, method, path}, redisCounterFieldSeparator),
+		isError,
+		latency.Microseconds(),
+		time.Now().UTC().UnixMilli(),
+	).Err()
+	if err != nil {
+		counter.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot record the request [%s %s] of user [%s] in redis", method, path, userID)))
+	}
+}
+
+// Since returns the time when the counter started counting requests
+func (counter *APIUsageCounter) Since(ctx context.Context) (time.Time, error) {
+	value, err := counter.client.Get(ctx, apiUsageCounterSinceKey).Int64()
+	if err == redis.Nil {
+		return time.Now().UTC(), nil
+	}
+
+	if err != nil {
+		return time.Time{}, stacktrace.Propagate(err, "cannot fetch the start time of the API usage from redis")
+	}
+	return time.UnixMilli(value).UTC(), nil
+}
+
+// Endpoints returns the usage of every endpoint which was called by a user sorted by the API key, path and method
+func (counter *APIUsageCounter) Endpoints(ctx context.Context, userID entities.UserID) ([]*entities.APIEndpointUsage, error) {
+	fields, err := counter.client.HGetAll(ctx, apiUsageCounterKeyPrefix+string(userID)).Result()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch the API usage of user [%s] from redis", userID))
+	}
+
+	records := map[string]*apiUsageRecord{}
+	for field, value := range fields {
+		index := strings.LastIndex(field, redisCounterFieldSeparator)
+		if index < 0 {
+			continue
+		}
+
+		endpoint := field[:index]
+		if _, ok := records[endpoint]; !ok {
+			records[endpoint] = &apiUsageRecord{}
+		}
+
+		number, _ := strconv.ParseInt(value, 10, 64)
+		switch field[index+1:] {
+		case "requests":
+			records[endpoint].requests = uint64(number)
+		case "errors":
+			records[endpoint].errors = uint64(number)
+		case "total_latency":
+			records[endpoint].totalLatency = time.Duration(number) * time.Microsecond
+		case "max_latency":
+			records[endpoint].maxLatency = time.Duration(number) * time.Microsecond
+		case "last_request_at":
+			records[endpoint].lastRequestAt = time.UnixMilli(number).UTC()
+		}
+	}
+
+	endpoints := make([]*entities.APIEndpointUsage, 0, len(records))
+	for endpoint, record := range records {
+		parts := strings.SplitN(endpoint, redisCounterFieldSeparator, 3)
+		if len(parts) != 3 || record.requests == 0 {
+			continue
+		}
+
+		endpoints = append(endpoints, &entities.APIEndpointUsage{
+			APIKey:                     parts[0],
+			Method:                     parts[1],
+			Path:                       parts[2],
+			Requests:                   record.requests,
+			Errors:                     record.errors,
+			ErrorRate:                  float64(record.errors) / float64(record.requests),
+			AverageLatencyMilliseconds: float64(record.totalLatency.Microseconds()) / float64(record.requests) / 1000,
+			MaxLatencyMilliseconds:     float64(record.maxLatency.Microseconds()) / 1000,
+			LastRequestAt:              record.lastRequestAt,
+		})
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].APIKey != endpoints[j].APIKey {
+			return endpoints[i].APIKey < endpoints[j].APIKey
+		}
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	return endpoints, nil
+}
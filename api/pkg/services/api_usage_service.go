@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// APIUsageService reports how an account uses the API so integrators can debug their own clients
+type APIUsageService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	counter         *APIUsageCounter
+	userRepository  repositories.UserRepository
+	usageRepository repositories.BillingUsageRepository
+}
+
+// NewAPIUsageService creates a new APIUsageService
+func NewAPIUsageService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	counter *APIUsageCounter,
+	userRepository repositories.UserRepository,
+	usageRepository repositories.BillingUsageRepository,
+) (s *APIUsageService) {
+	return &APIUsageService{
+		logger:          logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		counter:         counter,
+		userRepository:  userRepository,
+		usageRepository: usageRepository,
+	}
+}
+
+// Get returns the usage of every endpoint which was called with an API key of the user and the number of messages which
+// were consumed out of the limit of the subscription in the current billing period
+func (service *APIUsageService) Get(ctx context.Context, userID entities.UserID) (*entities.APIUsage, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	usage, err := service.usageRepository.GetCurrent(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the current billing usage of user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	limit := user.SubscriptionName.Limit()
+	remaining := uint(0)
+	if usage.TotalMessages() < limit {
+		remaining = limit - usage.TotalMessages()
+	}
+
+	since, err := service.counter.Since(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the start time of the API usage for user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	endpoints, err := service.counter.Endpoints(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the API usage of the endpoints for user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return &entities.APIUsage{
+		UserID:    userID,
+		Since:     since,
+		Endpoints: endpoints,
+		RateLimit: entities.APIRateLimitUsage{
+			Limit:          limit,
+			Used:           usage.TotalMessages(),
+			Remaining:      remaining,
+			StartTimestamp: usage.StartTimestamp,
+			EndTimestamp:   usage.EndTimestamp,
+		},
+	}, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// AttachmentService manages the media files which are sent and received in MMS messages
+type AttachmentService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.AttachmentRepository
+	storage    AttachmentStorage
+}
+
+// NewAttachmentService creates a new AttachmentService
+func NewAttachmentService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.AttachmentRepository,
+	storage AttachmentStorage,
+) (s *AttachmentService) {
+	return &AttachmentService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		storage:    storage,
+	}
+}
+
+// AttachmentStoreParams are parameters for uploading a new entities.Attachment
+type AttachmentStoreParams struct {
+	UserID      entities.UserID
+	Name        string
+	ContentType string
+	Size        int64
+	Reader      io.Reader
+	BaseURL     string
+}
+
+// Store uploads the content of a new entities.Attachment to the AttachmentStorage
+func (service *AttachmentService) Store(ctx context.Context, params *AttachmentStoreParams) (*entities.Attachment, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attachmentID := uuid.New()
+	attachment := &entities.Attachment{
+		ID:          attachmentID,
+		UserID:      params.UserID,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		URL:         strings.TrimRight(params.BaseURL, "/") + "/v1/attachments/" + attachmentID.String(),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if err := service.storage.Upload(ctx, attachment.ID.String(), attachment.ContentType, params.Reader); err != nil {
+		msg := fmt.Sprintf("cannot upload attachment [%s] with name [%s] for user [%s]", attachment.ID, attachment.Name, attachment.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := service.repository.Store(ctx, attachment); err != nil {
+		msg := fmt.Sprintf("cannot save attachment with id [%s]", attachment.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("attachment saved with id [%s] and size [%d] for user [%s]", attachment.ID, attachment.Size, attachment.UserID))
+	return attachment, nil
+}
+
+// Download returns an entities.Attachment with a reader for its content which must be closed by the caller
+func (service *AttachmentService) Download(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) (*entities.Attachment, io.ReadCloser, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	attachment, err := service.repository.Load(ctx, userID, attachmentID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment [%s] for user [%s]", attachmentID, userID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	reader, err := service.storage.Download(ctx, attachment.ID.String())
+	if err != nil {
+		msg := fmt.Sprintf("cannot download content of attachment [%s] for user [%s]", attachmentID, userID)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attachment, reader, nil
+}
+
+// Delete an entities.Attachment and its content
+func (service *AttachmentService) Delete(ctx context.Context, userID entities.UserID, attachmentID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attachment, err := service.repository.Load(ctx, userID, attachmentID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load attachment [%s] for user [%s]", attachmentID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.delete(ctx, attachment); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete attachment [%s]", attachmentID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted attachment [%s] for user [%s]", attachmentID, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Attachment of a user and their content
+func (service *AttachmentService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	count := 0
+	for {
+		attachments, err := service.repository.Index(ctx, userID, repositories.IndexParams{Skip: 0, Limit: 100})
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch attachments for user [%s]", userID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if len(attachments) == 0 {
+			break
+		}
+
+		for _, attachment := range attachments {
+			if err = service.delete(ctx, attachment); err != nil {
+				return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot delete attachment [%s]", attachment.ID)))
+			}
+			count++
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted [%d] attachments for user [%s]", count, userID))
+	return nil
+}
+
+func (service *AttachmentService) delete(ctx context.Context, attachment *entities.Attachment) error {
+	if err := service.storage.Delete(ctx, attachment.ID.String()); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete content of attachment [%s] for user [%s]", attachment.ID, attachment.UserID))
+	}
+
+	if err := service.repository.Delete(ctx, attachment.UserID, attachment.ID); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete attachment [%s] for user [%s]", attachment.ID, attachment.UserID))
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+	"io"
+)
+
+// AttachmentStorage stores the media files of MMS messages
+type AttachmentStorage interface {
+	// Upload stores the content of the reader as a file with the given name
+	Upload(ctx context.Context, name string, contentType string, reader io.Reader) error
+
+	// Download fetches the content of a file
+	Download(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// Delete removes a file
+	Delete(ctx context.Context, name string) error
+}
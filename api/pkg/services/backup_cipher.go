@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/palantir/stacktrace"
+	"golang.org/x/crypto/argon2"
+)
+
+// Backups are encrypted with AES-256-GCM in chunks so that archives can be streamed without loading them in memory.
+// The archive starts with backupCipherMagic, a random salt and a random 96-bit nonce, followed by chunks of
+// [4 byte length][sealed chunk]. The key of every archive is derived from the passphrase and the salt with argon2id and
+// the counter of the chunk is XORed into the nonce. The high bit of the length marks the final chunk which protects
+// against truncation.
+const (
+	backupCipherChunkSize = 64 * 1024
+	backupCipherFinalFlag = uint32(1 << 31)
+	backupCipherSaltSize  = 16
+	backupCipherNonceSize = 12
+)
+
+var backupCipherMagic = []byte("HSMSBK02")
+
+func backupCipherAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot create AES cipher for backup")
+	}
+	return cipher.NewGCM(block)
+}
+
+// backupCipherKey derives the key of an archive from the passphrase and the salt with the argon2id parameters which are
+// recommended by RFC 9106 for memory constrained environments
+func backupCipherKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, 3, 64*1024, 4, 32)
+}
+
+func backupCipherNonce(base []byte, counter uint64) []byte {
+	nonce := append([]byte{}, base...)
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], binary.BigEndian.Uint64(nonce[len(nonce)-8:])^counter)
+	return nonce
+}
+
+type backupEncryptWriter struct {
+	writer  io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+	buffer  []byte
+	closed  bool
+}
+
+// newBackupEncryptWriter creates an io.WriteCloser which encrypts everything written into it. Close must be called to
+// write the final chunk.
+func newBackupEncryptWriter(writer io.Writer, passphrase string) (io.WriteCloser, error) {
+	salt := make([]byte, backupCipherSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate salt for backup")
+	}
+
+	nonce := make([]byte, backupCipherNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot generate nonce for backup")
+	}
+
+	aead, err := backupCipherAEAD(backupCipherKey(passphrase, salt))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot create backup encryption writer")
+	}
+
+	header := append(append(append([]byte{}, backupCipherMagic...), salt...), nonce...)
+	if _, err = writer.Write(header); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot write backup header")
+	}
+
+	return &backupEncryptWriter{
+		writer: writer,
+		aead:   aead,
+		nonce:  nonce,
+		buffer: make([]byte, 0, backupCipherChunkSize),
+	}, nil
+}
+
+func (encrypter *backupEncryptWriter) Write(data []byte) (int, error) {
+	if encrypter.closed {
+		return 0, stacktrace.NewError("cannot write into a closed backup encryption writer")
+	}
+
+	written := 0
+	for len(data) > 0 {
+		count := copy(encrypter.buffer[len(encrypter.buffer):cap(encrypter.buffer)], data)
+		encrypter.buffer = encrypter.buffer[:len(encrypter.buffer)+count]
+		data = data[count:]
+		written += count
+
+		if len(encrypter.buffer) == cap(encrypter.buffer) {
+			if err := encrypter.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (encrypter *backupEncryptWriter) Close() error {
+	if encrypter.closed {
+		return nil
+	}
+	encrypter.closed = true
+	return encrypter.flush(true)
+}
+
+func (encrypter *backupEncryptWriter) flush(final bool) error {
+	header := uint32(0)
+	if final {
+		header = backupCipherFinalFlag
+	}
+
+	nonce := backupCipherNonce(encrypter.nonce, encrypter.counter)
+	sealed := encrypter.aead.Seal(nil, nonce, encrypter.buffer, binary.BigEndian.AppendUint32(nil, header))
+	encrypter.counter++
+	encrypter.buffer = encrypter.buffer[:0]
+
+	if _, err := encrypter.writer.Write(binary.BigEndian.AppendUint32(nil, header|uint32(len(sealed)))); err != nil {
+		return stacktrace.Propagate(err, "cannot write backup chunk header")
+	}
+
+	if _, err := encrypter.writer.Write(sealed); err != nil {
+		return stacktrace.Propagate(err, "cannot write backup chunk")
+	}
+	return nil
+}
+
+type backupDecryptReader struct {
+	reader  io.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+	buffer  []byte
+	final   bool
+}
+
+// newBackupDecryptReader creates an io.Reader which decrypts an archive that was written by newBackupEncryptWriter
+func newBackupDecryptReader(reader io.Reader, passphrase string) (io.Reader, error) {
+	magic := make([]byte, len(backupCipherMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot read backup header")
+	}
+
+	if !bytes.Equal(magic, backupCipherMagic) {
+		return nil, stacktrace.NewError("the archive is not an encrypted httpSMS backup")
+	}
+
+	header := make([]byte, backupCipherSaltSize+backupCipherNonceSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot read backup salt and nonce")
+	}
+
+	aead, err := backupCipherAEAD(backupCipherKey(passphrase, header[:backupCipherSaltSize]))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot create backup decryption reader")
+	}
+
+	return &backupDecryptReader{
+		reader: reader,
+		aead:   aead,
+		nonce:  header[backupCipherSaltSize:],
+	}, nil
+}
+
+func (decrypter *backupDecryptReader) Read(data []byte) (int, error) {
+	for len(decrypter.buffer) == 0 {
+		if decrypter.final {
+			return 0, io.EOF
+		}
+		if err := decrypter.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	count := copy(data, decrypter.buffer)
+	decrypter.buffer = decrypter.buffer[count:]
+	return count, nil
+}
+
+func (decrypter *backupDecryptReader) next() error {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(decrypter.reader, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return stacktrace.NewError("the backup archive is truncated")
+		}
+		return stacktrace.Propagate(err, "cannot read backup chunk header")
+	}
+
+	length := binary.BigEndian.Uint32(header)
+	flag := length & backupCipherFinalFlag
+	length &^= backupCipherFinalFlag
+	if length > backupCipherChunkSize+uint32(decrypter.aead.Overhead()) {
+		return stacktrace.NewError("the backup chunk is larger than the maximum chunk size")
+	}
+
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(decrypter.reader, sealed); err != nil {
+		return stacktrace.Propagate(err, "cannot read backup chunk")
+	}
+
+	nonce := backupCipherNonce(decrypter.nonce, decrypter.counter)
+	buffer, err := decrypter.aead.Open(nil, nonce, sealed, binary.BigEndian.AppendUint32(nil, flag))
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot decrypt backup chunk, the encryption key may be wrong")
+	}
+
+	decrypter.counter++
+	decrypter.buffer = buffer
+	decrypter.final = flag != 0
+	return nil
+}
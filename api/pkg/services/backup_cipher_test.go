@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encryptBackup(t *testing.T, passphrase string, data []byte) []byte {
+	archive := new(bytes.Buffer)
+	writer, err := newBackupEncryptWriter(archive, passphrase)
+	require.NoError(t, err)
+
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return archive.Bytes()
+}
+
+func decryptBackup(archive []byte, passphrase string) ([]byte, error) {
+	reader, err := newBackupDecryptReader(bytes.NewReader(archive), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
+
+func TestBackupCipher(t *testing.T) {
+	const passphrase = "correct horse battery staple"
+
+	headerSize := len(backupCipherMagic) + backupCipherSaltSize + backupCipherNonceSize
+	chunkSize := 4 + backupCipherChunkSize + 16
+
+	data := make([]byte, 2*backupCipherChunkSize+100)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	t.Run("an archive is decrypted with the passphrase", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+
+		// Act
+		plaintext, err := decryptBackup(archive, passphrase)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, data, plaintext)
+		assert.Equal(t, headerSize+2*chunkSize+4+100+16, len(archive))
+	})
+
+	t.Run("an empty archive is decrypted", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, nil)
+
+		// Act
+		plaintext, err := decryptBackup(archive, passphrase)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, plaintext)
+	})
+
+	t.Run("an archive is not decrypted with a wrong passphrase", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+
+		// Act
+		_, err := decryptBackup(archive, "wrong passphrase")
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("an archive without the final chunk is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+
+		// Act
+		_, err := decryptBackup(archive[:headerSize+2*chunkSize], passphrase)
+
+		// Assert
+		assert.ErrorContains(t, err, "truncated")
+	})
+
+	t.Run("an archive with a truncated final chunk is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+
+		// Act
+		_, err := decryptBackup(archive[:len(archive)-10], passphrase)
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("an archive with reordered chunks is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+		first := archive[headerSize : headerSize+chunkSize]
+		second := archive[headerSize+chunkSize : headerSize+2*chunkSize]
+
+		reordered := append([]byte{}, archive[:headerSize]...)
+		reordered = append(reordered, second...)
+		reordered = append(reordered, first...)
+		reordered = append(reordered, archive[headerSize+2*chunkSize:]...)
+
+		// Act
+		_, err := decryptBackup(reordered, passphrase)
+
+		// Assert
+		assert.ErrorContains(t, err, "cannot decrypt backup chunk")
+	})
+
+	t.Run("an archive in an unknown format is rejected", func(t *testing.T) {
+		// Setup
+		t.Parallel()
+
+		// Arrange
+		archive := encryptBackup(t, passphrase, data)
+		copy(archive, "HSMSBK01")
+
+		// Act
+		_, err := decryptBackup(archive, passphrase)
+
+		// Assert
+		assert.ErrorContains(t, err, "not an encrypted httpSMS backup")
+	})
+}
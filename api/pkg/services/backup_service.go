@@ -0,0 +1,287 @@
+package services
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	backupNamePrefix  = "httpsms-backup-"
+	backupNameSuffix  = ".jsonl.gz.enc"
+	backupScopeAll    = "all"
+	backupVersion     = 1
+	backupMaxLineSize = 16 * 1024 * 1024
+)
+
+// BackupServiceConfig are the configurations of the BackupService
+type BackupServiceConfig struct {
+	// EncryptionKey is the passphrase used to encrypt the archives
+	EncryptionKey string
+	// Retention is the number of archives to keep for each scope. Older archives are deleted after a backup
+	Retention int
+}
+
+// BackupService creates, rotates and restores encrypted backups
+type BackupService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.BackupRepository
+	storage    BackupStorage
+	config     BackupServiceConfig
+}
+
+// NewBackupService creates a new BackupService
+func NewBackupService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.BackupRepository,
+	storage BackupStorage,
+	config BackupServiceConfig,
+) (s *BackupService) {
+	return &BackupService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		storage:    storage,
+		config:     config,
+	}
+}
+
+type backupHeader struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	UserID    *entities.UserID `json:"user_id"`
+	Tables    []string         `json:"tables"`
+}
+
+type backupRecord struct {
+	Database string          `json:"database,omitempty"`
+	Table    string          `json:"table"`
+	Row      json.RawMessage `json:"row"`
+}
+
+// Backup exports the database into an encrypted archive. If userID is nil, the whole database is exported.
+func (service *BackupService) Backup(ctx context.Context, userID *entities.UserID) (*BackupObject, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.config.EncryptionKey == "" {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError("cannot create backup because the encryption key is not configured"))
+	}
+
+	createdAt := time.Now().UTC()
+	name := service.backupName(userID, createdAt)
+
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(service.writeArchive(ctx, writer, userID, createdAt))
+	}()
+
+	if err := service.storage.Upload(ctx, name, reader); err != nil {
+		_ = reader.CloseWithError(err)
+		msg := fmt.Sprintf("cannot upload backup [%s]", name)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created backup [%s] in [%s]", name, time.Since(createdAt)))
+	return &BackupObject{Name: name, CreatedAt: createdAt}, nil
+}
+
+// Rotate deletes the oldest archives of each scope which exceed BackupServiceConfig.Retention
+func (service *BackupService) Rotate(ctx context.Context) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.config.Retention <= 0 {
+		return nil
+	}
+
+	objects, err := service.List(ctx)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot list backups for rotation"))
+	}
+
+	kept := map[string]int{}
+	for _, object := range objects {
+		scope := service.backupScope(object.Name)
+		if kept[scope] < service.config.Retention {
+			kept[scope]++
+			continue
+		}
+
+		if err = service.storage.Delete(ctx, object.Name); err != nil {
+			msg := fmt.Sprintf("cannot delete expired backup [%s]", object.Name)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		ctxLogger.Info(fmt.Sprintf("deleted expired backup [%s] created at [%s]", object.Name, object.CreatedAt))
+	}
+
+	return nil
+}
+
+// List returns the backup archives ordered from the newest to the oldest
+func (service *BackupService) List(ctx context.Context) ([]BackupObject, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	objects, err := service.storage.List(ctx)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot list backups"))
+	}
+
+	backups := make([]BackupObject, 0, len(objects))
+	for _, object := range objects {
+		if strings.HasPrefix(object.Name, backupNamePrefix) && strings.HasSuffix(object.Name, backupNameSuffix) {
+			backups = append(backups, object)
+		}
+	}
+
+	// The names contain the timestamp of the backup so they are sorted by name instead of the storage timestamps
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// Restore imports the rows in a backup archive into the database and returns the number of rows which were restored
+func (service *BackupService) Restore(ctx context.Context, name string) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	archive, err := service.storage.Download(ctx, name)
+	if err != nil {
+		msg := fmt.Sprintf("cannot download backup [%s]", name)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() { _ = archive.Close() }()
+
+	decrypted, err := newBackupDecryptReader(archive, service.config.EncryptionKey)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decrypt backup [%s]", name)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	decompressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decompress backup [%s]", name)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	scanner := bufio.NewScanner(decompressed)
+	scanner.Buffer(make([]byte, 0, 64*1024), backupMaxLineSize)
+
+	if !scanner.Scan() {
+		msg := fmt.Sprintf("backup [%s] does not have a header", name)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(scanner.Err(), msg))
+	}
+
+	header := new(backupHeader)
+	if err = json.Unmarshal(scanner.Bytes(), header); err != nil || header.Version != backupVersion {
+		msg := fmt.Sprintf("backup [%s] has an invalid header [%s]", name, scanner.Text())
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	count := 0
+	for scanner.Scan() {
+		record := new(backupRecord)
+		if err = json.Unmarshal(scanner.Bytes(), record); err != nil {
+			msg := fmt.Sprintf("cannot unmarshal record [%d] in backup [%s]", count+1, name)
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		if err = service.repository.Import(ctx, record.Database, record.Table, record.Row); err != nil {
+			msg := fmt.Sprintf("cannot import record [%d] in backup [%s]", count+1, name)
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		count++
+	}
+
+	if err = scanner.Err(); err != nil {
+		msg := fmt.Sprintf("cannot read backup [%s] after [%d] records", name, count)
+		return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("restored [%d] records from backup [%s] created at [%s]", count, name, header.CreatedAt))
+	return count, nil
+}
+
+// Schedule runs a backup of the whole database followed by a rotation at every interval until the context is cancelled
+func (service *BackupService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.Backup(ctx, nil); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot run scheduled backup"))
+				continue
+			}
+			if err := service.Rotate(ctx); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot rotate backups"))
+			}
+		}
+	}
+}
+
+func (service *BackupService) writeArchive(ctx context.Context, writer io.Writer, userID *entities.UserID, createdAt time.Time) error {
+	encrypted, err := newBackupEncryptWriter(writer, service.config.EncryptionKey)
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot create encrypted backup writer")
+	}
+
+	compressed := gzip.NewWriter(encrypted)
+	encoder := json.NewEncoder(compressed)
+
+	header := backupHeader{Version: backupVersion, CreatedAt: createdAt, UserID: userID, Tables: service.repository.Tables()}
+	if err = encoder.Encode(header); err != nil {
+		return stacktrace.Propagate(err, "cannot write backup header")
+	}
+
+	err = service.repository.Export(ctx, userID, func(database string, table string, row any) error {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal row of table [%s]", table))
+		}
+		return encoder.Encode(backupRecord{Database: database, Table: table, Row: data})
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot export rows into backup")
+	}
+
+	if err = compressed.Close(); err != nil {
+		return stacktrace.Propagate(err, "cannot close compressed backup writer")
+	}
+	return encrypted.Close()
+}
+
+func (service *BackupService) backupName(userID *entities.UserID, timestamp time.Time) string {
+	scope := backupScopeAll
+	if userID != nil {
+		scope = userID.String()
+	}
+	return fmt.Sprintf("%s%s-%s%s", backupNamePrefix, scope, timestamp.Format("20060102T150405Z"), backupNameSuffix)
+}
+
+func (service *BackupService) backupScope(name string) string {
+	scope := strings.TrimSuffix(strings.TrimPrefix(name, backupNamePrefix), backupNameSuffix)
+	if index := strings.LastIndex(scope, "-"); index != -1 {
+		return scope[:index]
+	}
+	return scope
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BackupObject is an archive which is stored in the BackupStorage
+type BackupObject struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// BackupStorage stores encrypted backup archives
+type BackupStorage interface {
+	// Upload stores the content of the reader as an archive with the given name
+	Upload(ctx context.Context, name string, reader io.Reader) error
+
+	// Download fetches the content of an archive
+	Download(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// List returns all the archives which are stored
+	List(ctx context.Context) ([]BackupObject, error)
+
+	// Delete removes an archive
+	Delete(ctx context.Context, name string) error
+}
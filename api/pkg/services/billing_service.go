@@ -74,6 +74,26 @@ func (service *BillingService) IsEntitledWithCount(ctx context.Context, userID e
 	return nil
 }
 
+// HasCredits checks if a user can send `count` messages without notifying the user when the limit is exceeded
+func (service *BillingService) HasCredits(ctx context.Context, userID entities.UserID, count uint) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, userID)
+	if err != nil {
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load user with ID [%s], user has credits", userID))))
+		return true
+	}
+
+	usage, err := service.billingUsageRepository.GetCurrent(ctx, userID)
+	if err != nil {
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load billing usage for user with ID [%s], user has credits", userID))))
+		return true
+	}
+
+	return usage.IsEntitled(count, user.SubscriptionName.Limit())
+}
+
 // IsEntitled checks if a user can send or receive and SMS message
 func (service *BillingService) IsEntitled(ctx context.Context, userID entities.UserID) *string {
 	return service.IsEntitledWithCount(ctx, userID, 1)
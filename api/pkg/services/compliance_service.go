@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+// ComplianceService enforces the entities.ComplianceRule of a destination country before a message is dispatched
+type ComplianceService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ComplianceRuleRepository
+}
+
+// NewComplianceService creates a new ComplianceService
+func NewComplianceService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ComplianceRuleRepository,
+) (s *ComplianceService) {
+	return &ComplianceService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// ComplianceCheckParams are the parameters of a message which is checked against the compliance rules
+type ComplianceCheckParams struct {
+	Contact   string
+	Content   string
+	Encrypted bool
+	Category  entities.MessageCategory
+	SendAt    *time.Time
+}
+
+// ComplianceViolation is a compliance rule which is violated by a message
+type ComplianceViolation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Check returns the compliance violations of a message. No violations are returned when the country has no rule.
+func (service *ComplianceService) Check(ctx context.Context, params ComplianceCheckParams) ([]ComplianceViolation, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	countryCode := service.countryCode(params.Contact)
+	if countryCode == "" {
+		return nil, nil
+	}
+
+	rule, err := service.repository.LoadByCountry(ctx, countryCode)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load compliance rule for country [%s]", countryCode)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	violations := service.violations(rule, params)
+	if len(violations) > 0 {
+		ctxLogger.Info(fmt.Sprintf("message to [%s] has [%d] violations of the compliance rule [%s] for country [%s]", params.Contact, len(violations), rule.ID, countryCode))
+	}
+
+	return violations, nil
+}
+
+// Index returns all the entities.ComplianceRule ordered by country code
+func (service *ComplianceService) Index(ctx context.Context) ([]entities.ComplianceRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.repository.Index(ctx)
+	if err != nil {
+		msg := "cannot fetch the compliance rules"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] compliance rules", len(rules)))
+	return rules, nil
+}
+
+// LoadByCountry loads the entities.ComplianceRule of a country
+func (service *ComplianceService) LoadByCountry(ctx context.Context, countryCode string) (*entities.ComplianceRule, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	return service.repository.LoadByCountry(ctx, countryCode)
+}
+
+// ComplianceRuleStoreParams are parameters for creating an entities.ComplianceRule
+type ComplianceRuleStoreParams struct {
+	CountryCode          string
+	OptOutFooterRequired bool
+	OptOutKeywords       []string
+	ForbiddenHoursStart  *uint
+	ForbiddenHoursEnd    *uint
+	Timezone             string
+	ForbiddenContent     []string
+	ForbiddenCategories  []string
+}
+
+// Store a new entities.ComplianceRule
+func (service *ComplianceService) Store(ctx context.Context, params *ComplianceRuleStoreParams) (*entities.ComplianceRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule := &entities.ComplianceRule{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+	}
+	service.fill(rule, params)
+
+	if err := service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save compliance rule with id [%s] for country [%s]", rule.ID, rule.CountryCode)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("compliance rule saved with id [%s] for country [%s] in the [%T]", rule.ID, rule.CountryCode, service.repository))
+	return rule, nil
+}
+
+// ComplianceRuleUpdateParams are parameters for updating an entities.ComplianceRule
+type ComplianceRuleUpdateParams struct {
+	ComplianceRuleStoreParams
+	RuleID uuid.UUID
+}
+
+// Update an entities.ComplianceRule
+func (service *ComplianceService) Update(ctx context.Context, params *ComplianceRuleUpdateParams) (*entities.ComplianceRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule, err := service.repository.Load(ctx, params.RuleID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load compliance rule with ID [%s]", params.RuleID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	service.fill(rule, &params.ComplianceRuleStoreParams)
+
+	if err = service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save compliance rule with id [%s] after update", rule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("compliance rule updated with id [%s] for country [%s] in the [%T]", rule.ID, rule.CountryCode, service.repository))
+	return rule, nil
+}
+
+// Delete an entities.ComplianceRule
+func (service *ComplianceService) Delete(ctx context.Context, ruleID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot load compliance rule with ID [%s]", ruleID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot delete compliance rule with ID [%s]", ruleID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted compliance rule with ID [%s]", ruleID))
+	return nil
+}
+
+func (service *ComplianceService) fill(rule *entities.ComplianceRule, params *ComplianceRuleStoreParams) {
+	rule.CountryCode = params.CountryCode
+	rule.OptOutFooterRequired = params.OptOutFooterRequired
+	rule.OptOutKeywords = params.OptOutKeywords
+	rule.ForbiddenHoursStart = params.ForbiddenHoursStart
+	rule.ForbiddenHoursEnd = params.ForbiddenHoursEnd
+	rule.Timezone = params.Timezone
+	rule.ForbiddenContent = params.ForbiddenContent
+	rule.ForbiddenCategories = params.ForbiddenCategories
+	rule.UpdatedAt = time.Now().UTC()
+}
+
+func (service *ComplianceService) violations(rule *entities.ComplianceRule, params ComplianceCheckParams) (violations []ComplianceViolation) {
+	timestamp := time.Now().UTC()
+	if params.SendAt != nil {
+		timestamp = *params.SendAt
+	}
+
+	if rule.IsForbiddenCategory(params.Category) {
+		violations = append(violations, ComplianceViolation{
+			Field:   "category",
+			Rule:    "forbidden_category",
+			Message: fmt.Sprintf("[%s] messages cannot be sent to [%s]", params.Category, rule.CountryCode),
+		})
+	}
+
+	if !params.Category.IsQuietHoursExempt() && rule.IsForbiddenHour(timestamp) {
+		violations = append(violations, ComplianceViolation{
+			Field:   "send_at",
+			Rule:    "forbidden_hours",
+			Message: fmt.Sprintf("messages to [%s] cannot be sent between %02d:00 and %02d:00 in the [%s] timezone", rule.CountryCode, *rule.ForbiddenHoursStart, *rule.ForbiddenHoursEnd, rule.Location().String()),
+		})
+	}
+
+	// the content of encrypted messages cannot be inspected
+	if params.Encrypted {
+		return violations
+	}
+
+	if rule.IsMissingOptOutFooter(params.Content) {
+		violations = append(violations, ComplianceViolation{
+			Field:   "content",
+			Rule:    "opt_out_footer",
+			Message: fmt.Sprintf("messages to [%s] must contain an opt-out footer with one of the keywords [%s]", rule.CountryCode, strings.Join(rule.OptOutKeywords, ", ")),
+		})
+	}
+
+	if matches := rule.ForbiddenContentMatches(params.Content); len(matches) > 0 {
+		violations = append(violations, ComplianceViolation{
+			Field:   "content",
+			Rule:    "forbidden_content",
+			Message: fmt.Sprintf("messages to [%s] cannot contain [%s]", rule.CountryCode, strings.Join(matches, ", ")),
+		})
+	}
+
+	return violations
+}
+
+func (service *ComplianceService) countryCode(contact string) string {
+	number, err := phonenumbers.Parse(contact, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		return ""
+	}
+	return phonenumbers.GetRegionCodeForNumber(number)
+}
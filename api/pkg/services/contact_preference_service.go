@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// ContactPreferenceService manages the messaging preferences of the contacts of a user
+type ContactPreferenceService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ContactPreferenceRepository
+}
+
+// NewContactPreferenceService creates a new ContactPreferenceService
+func NewContactPreferenceService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ContactPreferenceRepository,
+) (s *ContactPreferenceService) {
+	return &ContactPreferenceService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Load the entities.ContactPreference of a contact
+func (service *ContactPreferenceService) Load(ctx context.Context, userID entities.UserID, contact string) (*entities.ContactPreference, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	preference, err := service.repository.Load(ctx, userID, contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load preference of contact [%s] for user [%s]", contact, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return preference, nil
+}
+
+// IsDoNotContact checks if the contact does not want to receive messages of the category
+func (service *ContactPreferenceService) IsDoNotContact(ctx context.Context, userID entities.UserID, contact string, category entities.MessageCategory) (bool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	preference, err := service.repository.Load(ctx, userID, contact)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load preference of contact [%s] for user [%s]", contact, userID)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return preference.IsDoNotContact(entities.MessageCategorySanitized(category)), nil
+}
+
+// ContactPreferenceUpdateParams are parameters for updating an entities.ContactPreference
+type ContactPreferenceUpdateParams struct {
+	UserID                 entities.UserID
+	Contact                string
+	Language               *string
+	DoNotContactCategories pq.StringArray
+	SendWindowStart        *uint
+	SendWindowEnd          *uint
+	Timezone               string
+}
+
+// Update the entities.ContactPreference of a contact, the preference is created when the contact does not have one
+func (service *ContactPreferenceService) Update(ctx context.Context, params *ContactPreferenceUpdateParams) (*entities.ContactPreference, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	preference, err := service.repository.Load(ctx, params.UserID, params.Contact)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load preference of contact [%s] for user [%s]", params.Contact, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if preference == nil {
+		preference = &entities.ContactPreference{
+			ID:        uuid.New(),
+			UserID:    params.UserID,
+			Contact:   params.Contact,
+			CreatedAt: time.Now().UTC(),
+		}
+	}
+
+	preference.Language = params.Language
+	preference.DoNotContactCategories = params.DoNotContactCategories
+	preference.SendWindowStart = params.SendWindowStart
+	preference.SendWindowEnd = params.SendWindowEnd
+	preference.Timezone = params.Timezone
+	preference.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, preference); err != nil {
+		msg := fmt.Sprintf("cannot save preference with ID [%s] of contact [%s]", preference.ID, params.Contact)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("preference with ID [%s] of contact [%s] saved for user [%s]", preference.ID, preference.Contact, preference.UserID))
+	return preference, nil
+}
+
+// Delete the entities.ContactPreference of a contact
+func (service *ContactPreferenceService) Delete(ctx context.Context, userID entities.UserID, contact string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, contact); err != nil {
+		msg := fmt.Sprintf("cannot load preference of contact [%s] for user [%s]", contact, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, contact); err != nil {
+		msg := fmt.Sprintf("cannot delete preference of contact [%s] for user [%s]", contact, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted preference of contact [%s] for user [%s]", contact, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ContactPreference for an entities.UserID
+func (service *ContactPreferenceService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete all [entities.ContactPreference] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.ContactPreference] for user with ID [%s]", userID))
+	return nil
+}
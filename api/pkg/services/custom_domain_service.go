@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	customDomainTokenLength      = 32
+	customDomainVerifierBatch    = 100
+	customDomainRecheckInterval  = 15 * time.Minute
+	customDomainReverifyInterval = 24 * time.Hour
+	customDomainVerificationName = "httpsms-verification="
+)
+
+// CustomDomainService manages the domains which users can use for their share links
+type CustomDomainService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.CustomDomainRepository
+	resolver   *net.Resolver
+}
+
+// NewCustomDomainService creates a new CustomDomainService
+func NewCustomDomainService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.CustomDomainRepository,
+) (s *CustomDomainService) {
+	return &CustomDomainService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		resolver:   net.DefaultResolver,
+	}
+}
+
+// Load the entities.CustomDomain of a user
+func (service *CustomDomainService) Load(ctx context.Context, userID entities.UserID) (*entities.CustomDomain, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	domain, err := service.repository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return domain, nil
+}
+
+// IsTaken checks if a domain is already verified by another user. A domain which is only claimed by another user is not
+// taken so that nobody can hold the domain of a customer without proving that they own it.
+func (service *CustomDomainService) IsTaken(ctx context.Context, userID entities.UserID, name string) (bool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	domain, err := service.repository.LoadVerifiedByDomain(ctx, name)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain [%s]", name)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domain.UserID != userID, nil
+}
+
+// CustomDomainStoreParams are parameters for setting the entities.CustomDomain of a user
+type CustomDomainStoreParams struct {
+	UserID entities.UserID
+	Domain string
+}
+
+// Store sets the domain of a user. The domain has to be verified again with a new token when it is changed.
+func (service *CustomDomainService) Store(ctx context.Context, params *CustomDomainStoreParams) (*entities.CustomDomain, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	domain, err := service.repository.Load(ctx, params.UserID)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if domain != nil && domain.Domain == params.Domain {
+		return domain, nil
+	}
+
+	token, err := service.generateToken()
+	if err != nil {
+		msg := fmt.Sprintf("cannot generate verification token for domain [%s]", params.Domain)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if domain == nil {
+		domain = &entities.CustomDomain{ID: uuid.New(), UserID: params.UserID, CreatedAt: time.Now().UTC()}
+	}
+
+	domain.Domain = params.Domain
+	domain.VerificationRecord = entities.CustomDomainVerificationPrefix + "." + params.Domain
+	domain.VerificationToken = customDomainVerificationName + token
+	domain.VerifiedAt = nil
+	domain.CheckedAt = nil
+	domain.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, domain); err != nil {
+		msg := fmt.Sprintf("cannot save custom domain [%s] for user [%s]", domain.Domain, domain.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("custom domain [%s] with ID [%s] stored for user [%s]", domain.Domain, domain.ID, domain.UserID))
+	return domain, nil
+}
+
+// Verify checks the TXT record of the entities.CustomDomain of a user immediately instead of waiting for the background job
+func (service *CustomDomainService) Verify(ctx context.Context, userID entities.UserID) (*entities.CustomDomain, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	domain, err := service.repository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if domain.IsVerified() {
+		return domain, nil
+	}
+
+	if err = service.verify(ctx, domain); err != nil {
+		msg := fmt.Sprintf("cannot verify custom domain [%s] for user [%s]", domain.Domain, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return domain, nil
+}
+
+// Schedule checks the TXT records of the unverified domains at every interval and the TXT records of the verified domains
+// once a day until the context is cancelled
+func (service *CustomDomainService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.VerifyPending(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot verify custom domains"))
+			}
+			if _, err := service.ReverifyVerified(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot re-verify custom domains"))
+			}
+		}
+	}
+}
+
+// VerifyPending checks the TXT records of the unverified domains which were not checked in the last customDomainRecheckInterval
+func (service *CustomDomainService) VerifyPending(ctx context.Context, timestamp time.Time) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	domains, err := service.repository.FetchUnverified(ctx, timestamp.Add(-customDomainRecheckInterval), customDomainVerifierBatch)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch unverified custom domains at [%s]", timestamp)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	count := 0
+	for _, domain := range domains {
+		if err = service.verify(ctx, domain); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot verify custom domain [%s] of user [%s]", domain.Domain, domain.UserID)))
+			continue
+		}
+		if domain.IsVerified() {
+			count++
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("verified [%d] out of [%d] custom domains", count, len(domains)))
+	}
+	return count, nil
+}
+
+// ReverifyVerified checks the TXT records of the verified domains which were not checked in the last customDomainReverifyInterval
+// and un-verifies the domains whose TXT record was removed so that they are no longer used for share links and certificates
+func (service *CustomDomainService) ReverifyVerified(ctx context.Context, timestamp time.Time) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	domains, err := service.repository.FetchVerified(ctx, timestamp.Add(-customDomainReverifyInterval), customDomainVerifierBatch)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch verified custom domains at [%s]", timestamp)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	count := 0
+	for _, domain := range domains {
+		if err = service.verify(ctx, domain); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot re-verify custom domain [%s] of user [%s]", domain.Domain, domain.UserID)))
+			continue
+		}
+		if !domain.IsVerified() {
+			ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("custom domain [%s] of user [%s] is no longer verified because its TXT record [%s] was not found", domain.Domain, domain.UserID, domain.VerificationRecord)))
+			count++
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("un-verified [%d] out of [%d] custom domains", count, len(domains)))
+	}
+	return count, nil
+}
+
+// verify looks up the TXT record of a domain and marks the domain as verified when the record contains the token and the
+// domain is not verified by another user. The unverified claims of other users on the domain are removed when it is
+// verified. A verified domain is un-verified when the record does not contain the token or it does not exist, but not
+// when the DNS lookup fails temporarily.
+func (service *CustomDomainService) verify(ctx context.Context, domain *entities.CustomDomain) error {
+	records, err := service.resolver.LookupTXT(ctx, domain.VerificationRecord)
+	if err != nil {
+		// a missing record is not an error because the user may not have created it yet
+		service.logger.Info(fmt.Sprintf("cannot lookup TXT record [%s] of custom domain [%s]: %s", domain.VerificationRecord, domain.ID, err.Error()))
+	}
+
+	var dnsErr *net.DNSError
+	isMissing := err == nil || (errors.As(err, &dnsErr) && dnsErr.IsNotFound)
+
+	timestamp := time.Now().UTC()
+	domain.CheckedAt = &timestamp
+	if slices.Contains(records, domain.VerificationToken) {
+		if !domain.IsVerified() && service.isVerifiedByOther(ctx, domain) {
+			service.logger.Warn(stacktrace.NewError(fmt.Sprintf("custom domain [%s] of user [%s] is already verified by another user", domain.Domain, domain.UserID)))
+		} else if !domain.IsVerified() {
+			domain.VerifiedAt = &timestamp
+		}
+	} else if isMissing {
+		domain.VerifiedAt = nil
+	}
+
+	if err = service.repository.Save(ctx, domain); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot save custom domain [%s] after checking its TXT record", domain.ID))
+	}
+
+	if !domain.IsVerified() {
+		return nil
+	}
+
+	count, err := service.repository.DeleteUnverifiedByDomain(ctx, domain.Domain, domain.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot remove the unverified claims on custom domain [%s]", domain.Domain))
+	}
+	if count > 0 {
+		service.logger.Info(fmt.Sprintf("removed [%d] unverified claims on custom domain [%s] which is verified by user [%s]", count, domain.Domain, domain.UserID))
+	}
+	return nil
+}
+
+// isVerifiedByOther checks if the domain name of a CustomDomain is verified by another user
+func (service *CustomDomainService) isVerifiedByOther(ctx context.Context, domain *entities.CustomDomain) bool {
+	taken, err := service.IsTaken(ctx, domain.UserID, domain.Domain)
+	if err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot check if custom domain [%s] is verified by another user", domain.Domain)))
+		return true
+	}
+	return taken
+}
+
+// HostPolicy allows TLS certificates to be issued for the verified custom domains. It is used by the autocert.Manager
+// when a certificate is requested for a host which is not a domain of the API server.
+func (service *CustomDomainService) HostPolicy(ctx context.Context, host string) error {
+	if _, err := service.repository.LoadVerifiedByDomain(ctx, strings.ToLower(host)); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("host [%s] is not a verified custom domain", host))
+	}
+	return nil
+}
+
+// BaseURL returns the URL of the verified custom domain of a user and the defaultBaseURL when the user has no verified domain
+func (service *CustomDomainService) BaseURL(ctx context.Context, userID entities.UserID, defaultBaseURL string) string {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	domain, err := service.repository.Load(ctx, userID)
+	if err != nil {
+		if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load custom domain for user [%s]", userID)))
+		}
+		return defaultBaseURL
+	}
+
+	if !domain.IsVerified() {
+		return defaultBaseURL
+	}
+
+	return domain.BaseURL()
+}
+
+// Delete the entities.CustomDomain of a user
+func (service *CustomDomainService) Delete(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot load custom domain for user [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete custom domain for user [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted custom domain for user [%s]", userID))
+	return nil
+}
+
+// DeleteAllForUser deletes the entities.CustomDomain of a user whose account is deleted
+func (service *CustomDomainService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.Delete(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete [entities.CustomDomain] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (service *CustomDomainService) generateToken() (string, error) {
+	b := make([]byte, customDomainTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", stacktrace.Propagate(err, fmt.Sprintf("cannot generate [%d] random bytes", customDomainTokenLength))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)[0:customDomainTokenLength], nil
+}
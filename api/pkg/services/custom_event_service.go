@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const customEventSource = "/v1/events"
+
+// CustomEventService stores the events which are published by a user and dispatches them to the webhooks of the user
+type CustomEventService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.CustomEventRepository
+	dispatcher *EventDispatcher
+}
+
+// NewCustomEventService creates a new CustomEventService
+func NewCustomEventService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.CustomEventRepository,
+	dispatcher *EventDispatcher,
+) (s *CustomEventService) {
+	return &CustomEventService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		dispatcher: dispatcher,
+	}
+}
+
+// Index fetches the entities.CustomEvent for an entities.UserID
+func (service *CustomEventService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.CustomEvent, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	customEvents, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch custom events with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] custom events with prams [%+#v]", len(customEvents), params))
+	return customEvents, nil
+}
+
+// CustomEventPublishParams are parameters for publishing a new entities.CustomEvent
+type CustomEventPublishParams struct {
+	UserID entities.UserID
+	Type   string
+	Source string
+	Data   json.RawMessage
+}
+
+// Publish stores a new entities.CustomEvent and dispatches it to the webhooks of the user which are subscribed to its type
+func (service *CustomEventService) Publish(ctx context.Context, params *CustomEventPublishParams) (*entities.CustomEvent, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	source := params.Source
+	if source == "" {
+		source = customEventSource
+	}
+
+	customEvent := &entities.CustomEvent{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Type:      params.Type,
+		Source:    source,
+		Data:      params.Data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, customEvent); err != nil {
+		msg := fmt.Sprintf("cannot store custom event with ID [%s] and type [%s]", customEvent.ID, customEvent.Type)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeCustomEventPublished, customEventSource, &events.CustomEventPublishedPayload{
+		ID:        customEvent.ID,
+		UserID:    customEvent.UserID,
+		Type:      customEvent.Type,
+		Source:    customEvent.Source,
+		Data:      customEvent.Data,
+		Timestamp: customEvent.CreatedAt,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for custom event [%s]", events.EventTypeCustomEventPublished, customEvent.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for custom event [%s]", event.Type(), customEvent.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("custom event [%s] with type [%s] published for user [%s]", customEvent.ID, customEvent.Type, customEvent.UserID))
+	return customEvent, nil
+}
+
+// DeleteAllForUser deletes all entities.CustomEvent for an entities.UserID.
+func (service *CustomEventService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.CustomEvent] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.CustomEvent] for user with ID [%s]", userID))
+	return nil
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	debugRequestRedacted    = "[REDACTED]"
+	debugRequestMaxBodySize = 16 * 1024
+)
+
+// debugRequestRedactedFields are the headers, query parameters and JSON fields which are never recorded
+var debugRequestRedactedFields = map[string]bool{
+	"x-api-key":     true,
+	"api_key":       true,
+	"key":           true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"password":      true,
+	"secret":        true,
+	"signing_key":   true,
+	"token":         true,
+}
+
+// DebugRequestService records the HTTP requests of a user while the debug mode is enabled
+type DebugRequestService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	cache      cache.Cache
+	repository repositories.DebugRequestRepository
+}
+
+// NewDebugRequestService creates a new DebugRequestService
+func NewDebugRequestService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	cache cache.Cache,
+	repository repositories.DebugRequestRepository,
+) (s *DebugRequestService) {
+	return &DebugRequestService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		cache:      cache,
+		repository: repository,
+	}
+}
+
+// Enable records the requests of a user for the duration and deletes the requests which were recorded previously
+func (service *DebugRequestService) Enable(ctx context.Context, userID entities.UserID, duration time.Duration) (*entities.DebugMode, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete the previous debug requests for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	expiresAt := time.Now().UTC().Add(duration)
+	if err := service.cache.Set(ctx, service.cacheKey(userID), expiresAt.Format(time.RFC3339Nano), duration); err != nil {
+		msg := fmt.Sprintf("cannot enable debug mode for user [%s] until [%s]", userID, expiresAt)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("debug mode enabled for user [%s] until [%s]", userID, expiresAt))
+	return &entities.DebugMode{UserID: userID, Enabled: true, ExpiresAt: &expiresAt}, nil
+}
+
+// Disable stops recording the requests of a user before the debug mode expires
+func (service *DebugRequestService) Disable(ctx context.Context, userID entities.UserID) (*entities.DebugMode, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.cache.Delete(ctx, service.cacheKey(userID)); err != nil {
+		msg := fmt.Sprintf("cannot disable debug mode for user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("debug mode disabled for user [%s]", userID))
+	return &entities.DebugMode{UserID: userID, Enabled: false}, nil
+}
+
+// IsEnabled checks if the requests of a user should be recorded
+func (service *DebugRequestService) IsEnabled(ctx context.Context, userID entities.UserID) bool {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	value, err := service.cache.Get(ctx, service.cacheKey(userID))
+	if err != nil {
+		return false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil && time.Now().UTC().Before(expiresAt)
+}
+
+// DebugRequestRecordParams are parameters for recording an HTTP request
+type DebugRequestRecordParams struct {
+	UserID             entities.UserID
+	Method             string
+	URL                string
+	RequestHeaders     map[string][]string
+	RequestBody        []byte
+	ResponseStatusCode int
+	ResponseBody       []byte
+	Duration           time.Duration
+	Timestamp          time.Time
+}
+
+// Record stores an HTTP request and its response after redacting the credentials
+func (service *DebugRequestService) Record(ctx context.Context, params *DebugRequestRecordParams) (*entities.DebugRequest, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	headers, err := json.Marshal(service.redactHeaders(params.RequestHeaders))
+	if err != nil {
+		msg := fmt.Sprintf("cannot marshal headers of [%s] request to [%s]", params.Method, params.URL)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	request := &entities.DebugRequest{
+		ID:                 uuid.New(),
+		UserID:             params.UserID,
+		Method:             params.Method,
+		URL:                service.redactURL(params.URL),
+		RequestHeaders:     headers,
+		RequestBody:        service.redactBody(params.RequestBody),
+		ResponseStatusCode: params.ResponseStatusCode,
+		ResponseBody:       service.redactBody(params.ResponseBody),
+		Duration:           params.Duration,
+		CreatedAt:          params.Timestamp,
+	}
+
+	if err = service.repository.Store(ctx, request); err != nil {
+		msg := fmt.Sprintf("cannot store debug request for [%s] request to [%s]", params.Method, request.URL)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("recorded debug request [%s] for user [%s]", request.ID, request.UserID))
+	return request, nil
+}
+
+// Index fetches the entities.DebugRequest of a user
+func (service *DebugRequestService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.DebugRequest, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	requests, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch debug requests with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] debug requests with params [%+#v]", len(requests), params))
+	return requests, nil
+}
+
+// DeleteAllForUser deletes all entities.DebugRequest for an entities.UserID.
+func (service *DebugRequestService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete [entities.DebugRequest] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.DebugRequest] for user with ID [%s]", userID))
+	return nil
+}
+
+func (service *DebugRequestService) cacheKey(userID entities.UserID) string {
+	return fmt.Sprintf("debug-mode-%s", userID)
+}
+
+func (service *DebugRequestService) redactHeaders(headers map[string][]string) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if debugRequestRedactedFields[strings.ToLower(name)] {
+			result[name] = debugRequestRedacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+func (service *DebugRequestService) redactURL(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.RawQuery == "" {
+		return value
+	}
+
+	query := parsed.Query()
+	for name := range query {
+		if debugRequestRedactedFields[strings.ToLower(name)] {
+			query.Set(name, debugRequestRedacted)
+		}
+	}
+
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// redactBody replaces the credentials in a JSON or form body, other bodies are stored as they are
+func (service *DebugRequestService) redactBody(body []byte) string {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if redacted, err := json.Marshal(service.redactJSON(payload)); err == nil {
+			body = redacted
+		}
+	} else if form, err := url.ParseQuery(string(body)); err == nil && form.Has("x-api-key") {
+		form.Set("x-api-key", debugRequestRedacted)
+		body = []byte(form.Encode())
+	}
+
+	if len(body) > debugRequestMaxBodySize {
+		return string(body[:debugRequestMaxBodySize])
+	}
+	return string(body)
+}
+
+func (service *DebugRequestService) redactJSON(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if debugRequestRedactedFields[strings.ToLower(key)] {
+				value[key] = debugRequestRedacted
+				continue
+			}
+			value[key] = service.redactJSON(item)
+		}
+	case []any:
+		for index, item := range value {
+			value[index] = service.redactJSON(item)
+		}
+	}
+	return value
+}
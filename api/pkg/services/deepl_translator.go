@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/carlmjohnson/requests"
+	"github.com/palantir/stacktrace"
+)
+
+type deeplTranslator struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	client  *http.Client
+	apiKey  string
+	baseURL string
+}
+
+// NewDeepLTranslator creates a Translator which uses the DeepL API.
+// Keys of the DeepL API free plan end with ":fx" and they are sent to the free API endpoint.
+func NewDeepLTranslator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+	apiKey string,
+) Translator {
+	baseURL := "https://api.deepl.com"
+	if strings.HasSuffix(apiKey, ":fx") {
+		baseURL = "https://api-free.deepl.com"
+	}
+
+	return &deeplTranslator{
+		logger:  logger.WithService(fmt.Sprintf("%T", &deeplTranslator{})),
+		tracer:  tracer,
+		client:  client,
+		apiKey:  apiKey,
+		baseURL: baseURL,
+	}
+}
+
+// Translate the text using the v2 DeepL API
+func (translator *deeplTranslator) Translate(ctx context.Context, text string, targetLanguage string) (*Translation, error) {
+	ctx, span := translator.tracer.Start(ctx)
+	defer span.End()
+
+	var response struct {
+		Translations []struct {
+			Text                   string `json:"text"`
+			DetectedSourceLanguage string `json:"detected_source_language"`
+		} `json:"translations"`
+	}
+
+	err := requests.
+		URL(translator.baseURL+"/v2/translate").
+		Client(translator.client).
+		Header("Authorization", "DeepL-Auth-Key "+translator.apiKey).
+		BodyJSON(map[string]any{
+			"text":        []string{text},
+			"target_lang": strings.ToUpper(targetLanguage),
+		}).
+		ToJSON(&response).
+		Fetch(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot translate text into [%s] with deepl", targetLanguage)
+		return nil, translator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(response.Translations) == 0 {
+		msg := fmt.Sprintf("deepl returned no translations for target language [%s]", targetLanguage)
+		return nil, translator.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	return &Translation{
+		Text:           response.Translations[0].Text,
+		SourceLanguage: strings.ToLower(response.Translations[0].DetectedSourceLanguage),
+	}, nil
+}
+
+// Detect the language of the text using the v2 DeepL API.
+// DeepL has no detection endpoint so the text is translated to english and the detected source language is returned.
+func (translator *deeplTranslator) Detect(ctx context.Context, text string) (string, error) {
+	ctx, span := translator.tracer.Start(ctx)
+	defer span.End()
+
+	translation, err := translator.Translate(ctx, text, "en-us")
+	if err != nil {
+		return "", translator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot detect the language of text with deepl"))
+	}
+
+	return translation.SourceLanguage, nil
+}
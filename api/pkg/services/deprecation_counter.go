@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+// deprecationCounterKey is the redis hash which stores the requests to the deprecated routes
+const deprecationCounterKey = "deprecations"
+
+// DeprecationCounter counts the requests to the deprecated routes per API key. The counts are stored in redis so they are
+// shared by the children of a prefork server and the other instances of the API and they survive restarts.
+type DeprecationCounter struct {
+	logger telemetry.Logger
+	client *redis.Client
+}
+
+// NewDeprecationCounter creates a new DeprecationCounter
+func NewDeprecationCounter(logger telemetry.Logger, client *redis.Client) (counter *DeprecationCounter) {
+	return &DeprecationCounter{
+		logger: logger.WithService(fmt.Sprintf("%T", counter)),
+		client: client,
+	}
+}
+
+// RecordRequest adds a request to a deprecated route by a user with an API key
+func (counter *DeprecationCounter) RecordRequest(ctx context.Context, route entities.DeprecatedRoute, userID entities.UserID, apiKey string) {
+	usage := strings.Join([]string{route.Method, route.Path, string(userID), apiKey}, redisCounterFieldSeparator) + redisCounterFieldSeparator
+
+	sunsetAt := ""
+	if route.SunsetAt != nil {
+		sunsetAt = strconv.FormatInt(route.SunsetAt.UnixMilli(), 10)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	_, err := counter.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, deprecationCounterKey, usage+"requests", 1)
+		pipe.HSet(ctx, deprecationCounterKey, usage+"last_request_at", time.Now().UTC().UnixMilli(), usage+"sunset_at", sunsetAt)
+		return nil
+	})
+	if err != nil {
+		counter.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot record the request to deprecated route [%s] of user [%s] in redis", route.Key(), userID)))
+	}
+}
+
+// Usages returns the usage of the deprecated routes sorted by the sunset time and the number of requests
+func (counter *DeprecationCounter) Usages(ctx context.Context) ([]*entities.DeprecatedRouteUsage, error) {
+	fields, err := counter.client.HGetAll(ctx, deprecationCounterKey).Result()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot fetch the usage of the deprecated routes from redis")
+	}
+
+	records := map[string]*entities.DeprecatedRouteUsage{}
+	for field, value := range fields {
+		index := strings.LastIndex(field, redisCounterFieldSeparator)
+		if index < 0 {
+			continue
+		}
+
+		parts := strings.SplitN(field[:index], redisCounterFieldSeparator, 4)
+		if len(parts) != 4 {
+			continue
+		}
+
+		usage, ok := records[field[:index]]
+		if !ok {
+			usage = &entities.DeprecatedRouteUsage{Method: parts[0], Path: parts[1], UserID: entities.UserID(parts[2]), APIKey: parts[3]}
+			records[field[:index]] = usage
+		}
+
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch field[index+1:] {
+		case "requests":
+			usage.Requests = uint64(number)
+		case "last_request_at":
+			usage.LastRequestAt = time.UnixMilli(number).UTC()
+		case "sunset_at":
+			sunsetAt := time.UnixMilli(number).UTC()
+			usage.SunsetAt = &sunsetAt
+		}
+	}
+
+	usages := make([]*entities.DeprecatedRouteUsage, 0, len(records))
+	for _, usage := range records {
+		usages = append(usages, usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Path != usages[j].Path || usages[i].Method != usages[j].Method {
+			return usages[i].Method+" "+usages[i].Path < usages[j].Method+" "+usages[j].Path
+		}
+		return usages[i].Requests > usages[j].Requests
+	})
+
+	return usages, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// blockedNetworks are the ranges which are not checked by net.IP helpers e.g. carrier-grade NAT and benchmarking
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"2001:db8::/32",
+)
+
+// EgressGuard protects the webhook and callback URLs of users from server-side request forgery by making sure that
+// outgoing requests are only sent to addresses on the public internet.
+type EgressGuard struct {
+	allowPrivate bool
+	proxy        *url.URL
+	resolver     *net.Resolver
+	dialer       *net.Dialer
+}
+
+// NewEgressGuard creates a new EgressGuard. The requests are sent through the proxy when it is not nil and
+// private addresses are allowed when allowPrivate is true e.g. for self-hosted deployments on a local network.
+func NewEgressGuard(allowPrivate bool, proxy *url.URL) *EgressGuard {
+	return &EgressGuard{
+		allowPrivate: allowPrivate,
+		proxy:        proxy,
+		resolver:     net.DefaultResolver,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+}
+
+// ValidateURL checks that a URL uses http or https and that its host resolves only to public addresses
+func (guard *EgressGuard) ValidateURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot parse url [%s]", rawURL))
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return stacktrace.NewError(fmt.Sprintf("the scheme [%s] of url [%s] is not http or https", target.Scheme, rawURL))
+	}
+
+	if guard.allowPrivate {
+		return nil
+	}
+
+	if _, err = guard.resolve(ctx, target.Hostname()); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot use url [%s]", rawURL))
+	}
+	return nil
+}
+
+// Transport creates an http.Transport which re-resolves and checks the host of every request when it is sent so that
+// a DNS record which is changed after the URL was validated cannot point the request to a private address.
+func (guard *EgressGuard) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if guard.proxy == nil {
+		transport.Proxy = nil
+		transport.DialContext = guard.DialContext
+		return transport
+	}
+
+	// the connection is opened to the proxy so the host of the request is checked before it is handed to the proxy
+	transport.Proxy = func(request *http.Request) (*url.URL, error) {
+		if _, err := guard.resolve(request.Context(), request.URL.Hostname()); err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot send request to [%s] through the egress proxy", request.URL.Host))
+		}
+		return guard.proxy, nil
+	}
+	return transport
+}
+
+// DialContext connects to the first allowed address of the host so that the address which is dialed is the one which was checked
+func (guard *EgressGuard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot split host and port of address [%s]", address))
+	}
+
+	addresses, err := guard.resolve(ctx, host)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot dial address [%s]", address))
+	}
+
+	var lastErr error
+	for _, ip := range addresses {
+		conn, err := guard.dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, stacktrace.Propagate(lastErr, fmt.Sprintf("cannot dial any of the [%d] addresses of [%s]", len(addresses), address))
+}
+
+func (guard *EgressGuard) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if host == "" {
+		return nil, stacktrace.NewError("the url does not have a host")
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return guard.check(host, []net.IP{ip})
+	}
+
+	addresses, err := guard.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot resolve host [%s]", host))
+	}
+
+	ips := make([]net.IP, 0, len(addresses))
+	for _, address := range addresses {
+		ips = append(ips, address.IP)
+	}
+	return guard.check(host, ips)
+}
+
+// check rejects the host when any of its addresses is private so that a host cannot mix public and private records
+func (guard *EgressGuard) check(host string, ips []net.IP) ([]net.IP, error) {
+	if len(ips) == 0 {
+		return nil, stacktrace.NewError(fmt.Sprintf("host [%s] has no addresses", host))
+	}
+
+	if guard.allowPrivate {
+		return ips, nil
+	}
+
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return nil, stacktrace.NewError(fmt.Sprintf("host [%s] resolves to address [%s] which is not on the public internet", host, ip))
+		}
+	}
+	return ips, nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(values ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
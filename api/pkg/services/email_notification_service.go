@@ -168,6 +168,37 @@ func (service *EmailNotificationService) NotifyWebhookSendFailed(ctx context.Con
 	return nil
 }
 
+// NotifyWebhookPaused sends an email to the user about a webhook which was paused after too many consecutive failures
+func (service *EmailNotificationService) NotifyWebhookPaused(ctx context.Context, payload *events.WebhookPausedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, payload.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s] for [%s] event with webhook ID [%s]", payload.UserID, events.EventTypeWebhookPaused, payload.WebhookID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if !user.NotificationWebhookEnabled {
+		ctxLogger.Info(fmt.Sprintf("[%s] email notifications disabled for user [%s]", events.EventTypeWebhookPaused, payload.UserID))
+		return nil
+	}
+
+	email, err := service.factory.WebhookPaused(user, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] email for user with ID [%s] and webhook with ID [%s]", events.EventTypeWebhookPaused, payload.UserID, payload.WebhookID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.mailer.Send(ctx, email); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] email for user with ID [%s] and webhook with ID [%s]", events.EventTypeWebhookPaused, payload.UserID, payload.WebhookID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("[%s] email sent to [%s] for webhook with ID [%s]", events.EventTypeWebhookPaused, user.ID, payload.WebhookID))
+	return nil
+}
+
 // NotifyDiscordSendFailed sends an email to the user about a failed discord webhook event
 func (service *EmailNotificationService) NotifyDiscordSendFailed(ctx context.Context, payload *events.DiscordSendFailedPayload) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
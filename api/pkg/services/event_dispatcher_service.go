@@ -20,12 +20,15 @@ import (
 
 // EventDispatcher dispatches a new event
 type EventDispatcher struct {
-	logger      telemetry.Logger
-	tracer      telemetry.Tracer
-	listeners   map[string][]events.EventListener
-	meter       metric.Float64Histogram
-	queue       PushQueue
-	queueConfig PushQueueConfig
+	logger        telemetry.Logger
+	tracer        telemetry.Tracer
+	listeners     map[string][]events.EventListener
+	subscriptions map[string][]string
+	disabled      map[string]bool
+	meter         metric.Float64Histogram
+	queue         PushQueue
+	queueConfig   PushQueueConfig
+	faults        *FaultInjector
 }
 
 // NewEventDispatcher creates a new EventDispatcher
@@ -35,14 +38,24 @@ func NewEventDispatcher(
 	meter metric.Float64Histogram,
 	queue PushQueue,
 	queueConfig PushQueueConfig,
+	disabledListeners []string,
+	faults *FaultInjector,
 ) (dispatcher *EventDispatcher) {
+	disabled := make(map[string]bool, len(disabledListeners))
+	for _, name := range disabledListeners {
+		disabled[name] = true
+	}
+
 	return &EventDispatcher{
-		logger:      logger,
-		tracer:      tracer,
-		meter:       meter,
-		listeners:   make(map[string][]events.EventListener),
-		queue:       queue,
-		queueConfig: queueConfig,
+		logger:        logger,
+		tracer:        tracer,
+		meter:         meter,
+		listeners:     make(map[string][]events.EventListener),
+		subscriptions: make(map[string][]string),
+		disabled:      disabled,
+		queue:         queue,
+		queueConfig:   queueConfig,
+		faults:        faults,
 	}
 }
 
@@ -108,13 +121,28 @@ func (dispatcher *EventDispatcher) Dispatch(ctx context.Context, event cloudeven
 	return err
 }
 
-// Subscribe a listener to an event
-func (dispatcher *EventDispatcher) Subscribe(eventType string, listener events.EventListener) {
+// Subscribe a listener to an event unless the listener with the name is disabled for all events or for this event
+func (dispatcher *EventDispatcher) Subscribe(name string, eventType string, listener events.EventListener) {
+	if dispatcher.disabled[name] || dispatcher.disabled[name+":"+eventType] {
+		dispatcher.logger.Info(fmt.Sprintf("listener [%s] is disabled for event type [%s]", name, eventType))
+		return
+	}
+
 	if _, ok := dispatcher.listeners[eventType]; !ok {
 		dispatcher.listeners[eventType] = []events.EventListener{}
 	}
 
 	dispatcher.listeners[eventType] = append(dispatcher.listeners[eventType], listener)
+	dispatcher.subscriptions[eventType] = append(dispatcher.subscriptions[eventType], name)
+}
+
+// Subscriptions returns the names of the active listeners for each event type
+func (dispatcher *EventDispatcher) Subscriptions() map[string][]string {
+	subscriptions := make(map[string][]string, len(dispatcher.subscriptions))
+	for eventType, names := range dispatcher.subscriptions {
+		subscriptions[eventType] = append([]string{}, names...)
+	}
+	return subscriptions
 }
 
 // Publish an event to subscribers
@@ -132,16 +160,22 @@ func (dispatcher *EventDispatcher) Publish(ctx context.Context, event cloudevent
 		return
 	}
 
+	names := dispatcher.subscriptions[event.Type()]
+
 	var wg sync.WaitGroup
-	for _, sub := range subscribers {
+	for index, sub := range subscribers {
 		wg.Add(1)
-		go func(ctx context.Context, sub events.EventListener) {
+		go func(ctx context.Context, name string, sub events.EventListener) {
+			defer wg.Done()
+			if err := dispatcher.faults.Inject(ctx, FaultTargetListeners, name); err != nil {
+				ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("subscriber [%s] did not handle event [%s] with id [%s]", name, event.Type(), event.ID())))
+				return
+			}
 			if err := sub(ctx, event); err != nil {
 				msg := fmt.Sprintf("subscriber [%T] cannot handle event [%s]", sub, event.Type())
 				ctxLogger.Error(stacktrace.Propagate(err, msg))
 			}
-			wg.Done()
-		}(ctx, sub)
+		}(ctx, names[index], sub)
 	}
 
 	wg.Wait()
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// FaultTarget is the part of the event pipeline where faults are injected
+type FaultTarget string
+
+const (
+	// FaultTargetListeners injects faults in the listeners of the EventDispatcher
+	FaultTargetListeners = FaultTarget("listeners")
+
+	// FaultTargetWebhooks injects faults in the deliveries of the WebhookService
+	FaultTargetWebhooks = FaultTarget("webhooks")
+)
+
+// FaultInjector randomly delays or fails the event listeners and the webhook deliveries so that users can test the
+// retries and the idempotency of their integration before going to production. It does nothing when the failure rate
+// and the max delay are 0.
+type FaultInjector struct {
+	failureRate float64
+	maxDelay    time.Duration
+	targets     map[FaultTarget]bool
+}
+
+// NewFaultInjector creates a new FaultInjector which fails a share of the calls to the targets equal to the failure rate
+// between 0 and 1 and delays every call by a random duration up to the max delay
+func NewFaultInjector(failureRate float64, maxDelay time.Duration, targets []FaultTarget) *FaultInjector {
+	enabled := make(map[FaultTarget]bool, len(targets))
+	for _, target := range targets {
+		enabled[target] = true
+	}
+
+	return &FaultInjector{
+		failureRate: min(max(failureRate, 0), 1),
+		maxDelay:    max(maxDelay, 0),
+		targets:     enabled,
+	}
+}
+
+// IsEnabled checks if faults are injected in the target
+func (injector *FaultInjector) IsEnabled(target FaultTarget) bool {
+	return injector.targets[target] && (injector.failureRate > 0 || injector.maxDelay > 0)
+}
+
+// Inject delays the call of the named listener or webhook and returns an error when the call should fail
+func (injector *FaultInjector) Inject(ctx context.Context, target FaultTarget, name string) error {
+	if !injector.IsEnabled(target) {
+		return nil
+	}
+
+	if injector.maxDelay > 0 {
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(injector.maxDelay)))):
+		case <-ctx.Done():
+			return stacktrace.Propagate(ctx.Err(), fmt.Sprintf("context is done while injecting a delay in [%s] of [%s]", name, target))
+		}
+	}
+
+	if rand.Float64() < injector.failureRate {
+		return stacktrace.NewError(fmt.Sprintf("injected a failure in [%s] of [%s] with a failure rate of [%.2f]", name, target, injector.failureRate))
+	}
+	return nil
+}
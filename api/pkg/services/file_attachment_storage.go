@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+type fileAttachmentStorage struct {
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	directory string
+}
+
+// NewFileAttachmentStorage creates an AttachmentStorage which stores files in a directory on the local file system
+func NewFileAttachmentStorage(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	directory string,
+) AttachmentStorage {
+	return &fileAttachmentStorage{
+		logger:    logger.WithService(fmt.Sprintf("%T", &fileAttachmentStorage{})),
+		tracer:    tracer,
+		directory: directory,
+	}
+}
+
+// Upload writes the content of the reader into a file in the attachment directory
+func (attachmentStorage *fileAttachmentStorage) Upload(ctx context.Context, name string, _ string, reader io.Reader) error {
+	_, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := os.MkdirAll(attachmentStorage.directory, 0o700); err != nil {
+		msg := fmt.Sprintf("cannot create attachment directory [%s]", attachmentStorage.directory)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	path := attachmentStorage.path(name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create attachment file [%s]", path)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if _, err = io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		msg := fmt.Sprintf("cannot write attachment file [%s]", path)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = file.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close attachment file [%s]", path)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Download opens a file in the attachment directory
+func (attachmentStorage *fileAttachmentStorage) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	_, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	file, err := os.Open(attachmentStorage.path(name))
+	if err != nil {
+		msg := fmt.Sprintf("cannot open attachment file [%s]", attachmentStorage.path(name))
+		return nil, attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return file, nil
+}
+
+// Delete removes a file from the attachment directory
+func (attachmentStorage *fileAttachmentStorage) Delete(ctx context.Context, name string) error {
+	_, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := os.Remove(attachmentStorage.path(name)); err != nil && !os.IsNotExist(err) {
+		msg := fmt.Sprintf("cannot delete attachment file [%s]", attachmentStorage.path(name))
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (attachmentStorage *fileAttachmentStorage) path(name string) string {
+	return filepath.Join(attachmentStorage.directory, filepath.Base(name))
+}
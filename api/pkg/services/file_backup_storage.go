@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+type fileBackupStorage struct {
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	directory string
+}
+
+// NewFileBackupStorage creates a BackupStorage which stores archives in a directory on the local file system
+func NewFileBackupStorage(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	directory string,
+) BackupStorage {
+	return &fileBackupStorage{
+		logger:    logger.WithService(fmt.Sprintf("%T", &fileBackupStorage{})),
+		tracer:    tracer,
+		directory: directory,
+	}
+}
+
+// Upload writes the content of the reader into a file in the backup directory
+func (backupStorage *fileBackupStorage) Upload(ctx context.Context, name string, reader io.Reader) error {
+	_, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := os.MkdirAll(backupStorage.directory, 0o700); err != nil {
+		msg := fmt.Sprintf("cannot create backup directory [%s]", backupStorage.directory)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// The archive is written to a temporary file first so that a failed backup does not leave a partial archive
+	path := backupStorage.path(name)
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create backup file [%s]", path)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if _, err = io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path + ".tmp")
+		msg := fmt.Sprintf("cannot write backup file [%s]", path)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = file.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close backup file [%s]", path)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = os.Rename(path+".tmp", path); err != nil {
+		msg := fmt.Sprintf("cannot rename backup file [%s]", path)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Download opens a file in the backup directory
+func (backupStorage *fileBackupStorage) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	_, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	file, err := os.Open(backupStorage.path(name))
+	if err != nil {
+		msg := fmt.Sprintf("cannot open backup file [%s]", backupStorage.path(name))
+		return nil, backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return file, nil
+}
+
+// List returns the files in the backup directory
+func (backupStorage *fileBackupStorage) List(ctx context.Context) ([]BackupObject, error) {
+	_, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	entries, err := os.ReadDir(backupStorage.directory)
+	if os.IsNotExist(err) {
+		return []BackupObject{}, nil
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot read backup directory [%s]", backupStorage.directory)
+		return nil, backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	objects := make([]BackupObject, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			msg := fmt.Sprintf("cannot read info of backup file [%s]", entry.Name())
+			return nil, backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		objects = append(objects, BackupObject{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime().UTC(),
+		})
+	}
+
+	return objects, nil
+}
+
+// Delete removes a file from the backup directory
+func (backupStorage *fileBackupStorage) Delete(ctx context.Context, name string) error {
+	_, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := os.Remove(backupStorage.path(name)); err != nil {
+		msg := fmt.Sprintf("cannot delete backup file [%s]", backupStorage.path(name))
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (backupStorage *fileBackupStorage) path(name string) string {
+	return filepath.Join(backupStorage.directory, filepath.Base(name))
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+type googleCloudAttachmentStorage struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+// NewGoogleCloudAttachmentStorage creates an AttachmentStorage which stores files in a google cloud storage bucket
+func NewGoogleCloudAttachmentStorage(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *storage.Client,
+	bucket string,
+	prefix string,
+) AttachmentStorage {
+	return &googleCloudAttachmentStorage{
+		logger: logger.WithService(fmt.Sprintf("%T", &googleCloudAttachmentStorage{})),
+		tracer: tracer,
+		client: client,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+	}
+}
+
+// Upload stores the content of the reader as an object in the bucket
+func (attachmentStorage *googleCloudAttachmentStorage) Upload(ctx context.Context, name string, contentType string, reader io.Reader) error {
+	ctx, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	writer := attachmentStorage.client.Bucket(attachmentStorage.bucket).Object(attachmentStorage.objectName(name)).NewWriter(ctx)
+	writer.ContentType = contentType
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		_ = writer.Close()
+		msg := fmt.Sprintf("cannot upload attachment [%s] to bucket [%s]", name, attachmentStorage.bucket)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := writer.Close(); err != nil {
+		msg := fmt.Sprintf("cannot finalize upload of attachment [%s] to bucket [%s]", name, attachmentStorage.bucket)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Download fetches an object from the bucket
+func (attachmentStorage *googleCloudAttachmentStorage) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	ctx, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	reader, err := attachmentStorage.client.Bucket(attachmentStorage.bucket).Object(attachmentStorage.objectName(name)).NewReader(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot download attachment [%s] from bucket [%s]", name, attachmentStorage.bucket)
+		return nil, attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reader, nil
+}
+
+// Delete removes an object from the bucket
+func (attachmentStorage *googleCloudAttachmentStorage) Delete(ctx context.Context, name string) error {
+	ctx, span := attachmentStorage.tracer.Start(ctx)
+	defer span.End()
+
+	err := attachmentStorage.client.Bucket(attachmentStorage.bucket).Object(attachmentStorage.objectName(name)).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		msg := fmt.Sprintf("cannot delete attachment [%s] from bucket [%s]", name, attachmentStorage.bucket)
+		return attachmentStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (attachmentStorage *googleCloudAttachmentStorage) objectName(name string) string {
+	if attachmentStorage.prefix == "" {
+		return name
+	}
+	return attachmentStorage.prefix + "/" + name
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"google.golang.org/api/iterator"
+)
+
+type googleCloudBackupStorage struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+// NewGoogleCloudBackupStorage creates a BackupStorage which stores archives in a google cloud storage bucket
+func NewGoogleCloudBackupStorage(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *storage.Client,
+	bucket string,
+	prefix string,
+) BackupStorage {
+	return &googleCloudBackupStorage{
+		logger: logger.WithService(fmt.Sprintf("%T", &googleCloudBackupStorage{})),
+		tracer: tracer,
+		client: client,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+	}
+}
+
+// Upload stores the content of the reader as an object in the bucket
+func (backupStorage *googleCloudBackupStorage) Upload(ctx context.Context, name string, reader io.Reader) error {
+	ctx, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	writer := backupStorage.client.Bucket(backupStorage.bucket).Object(backupStorage.objectName(name)).NewWriter(ctx)
+	writer.ContentType = "application/octet-stream"
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		_ = writer.Close()
+		msg := fmt.Sprintf("cannot upload backup [%s] to bucket [%s]", name, backupStorage.bucket)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := writer.Close(); err != nil {
+		msg := fmt.Sprintf("cannot finalize upload of backup [%s] to bucket [%s]", name, backupStorage.bucket)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Download fetches an object from the bucket
+func (backupStorage *googleCloudBackupStorage) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	ctx, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	reader, err := backupStorage.client.Bucket(backupStorage.bucket).Object(backupStorage.objectName(name)).NewReader(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot download backup [%s] from bucket [%s]", name, backupStorage.bucket)
+		return nil, backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return reader, nil
+}
+
+// List returns the objects in the bucket with the backup prefix
+func (backupStorage *googleCloudBackupStorage) List(ctx context.Context) ([]BackupObject, error) {
+	ctx, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	var objects []BackupObject
+	iter := backupStorage.client.Bucket(backupStorage.bucket).Objects(ctx, &storage.Query{Prefix: backupStorage.objectName("")})
+	for {
+		attrs, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+
+		if err != nil {
+			msg := fmt.Sprintf("cannot list backups in bucket [%s]", backupStorage.bucket)
+			return nil, backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		objects = append(objects, BackupObject{
+			Name:      strings.TrimPrefix(attrs.Name, backupStorage.objectName("")),
+			Size:      attrs.Size,
+			CreatedAt: attrs.Created,
+		})
+	}
+
+	return objects, nil
+}
+
+// Delete removes an object from the bucket
+func (backupStorage *googleCloudBackupStorage) Delete(ctx context.Context, name string) error {
+	ctx, span := backupStorage.tracer.Start(ctx)
+	defer span.End()
+
+	if err := backupStorage.client.Bucket(backupStorage.bucket).Object(backupStorage.objectName(name)).Delete(ctx); err != nil {
+		msg := fmt.Sprintf("cannot delete backup [%s] from bucket [%s]", name, backupStorage.bucket)
+		return backupStorage.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (backupStorage *googleCloudBackupStorage) objectName(name string) string {
+	if backupStorage.prefix == "" {
+		return name
+	}
+	return backupStorage.prefix + "/" + name
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/carlmjohnson/requests"
+	"github.com/palantir/stacktrace"
+)
+
+type googleTranslator struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *http.Client
+	apiKey string
+}
+
+// NewGoogleTranslator creates a Translator which uses the google cloud translation API
+func NewGoogleTranslator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+	apiKey string,
+) Translator {
+	return &googleTranslator{
+		logger: logger.WithService(fmt.Sprintf("%T", &googleTranslator{})),
+		tracer: tracer,
+		client: client,
+		apiKey: apiKey,
+	}
+}
+
+// Translate the text using the v2 google cloud translation API
+func (translator *googleTranslator) Translate(ctx context.Context, text string, targetLanguage string) (*Translation, error) {
+	ctx, span := translator.tracer.Start(ctx)
+	defer span.End()
+
+	var response struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText         string `json:"translatedText"`
+				DetectedSourceLanguage string `json:"detectedSourceLanguage"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+
+	err := requests.
+		URL("https://translation.googleapis.com/language/translate/v2").
+		Client(translator.client).
+		Param("key", translator.apiKey).
+		BodyJSON(map[string]any{
+			"q":      []string{text},
+			"target": targetLanguage,
+			"format": "text",
+		}).
+		ToJSON(&response).
+		Fetch(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot translate text into [%s] with google translate", targetLanguage)
+		return nil, translator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(response.Data.Translations) == 0 {
+		msg := fmt.Sprintf("google translate returned no translations for target language [%s]", targetLanguage)
+		return nil, translator.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	return &Translation{
+		Text:           response.Data.Translations[0].TranslatedText,
+		SourceLanguage: strings.ToLower(response.Data.Translations[0].DetectedSourceLanguage),
+	}, nil
+}
+
+// Detect the language of the text using the v2 google cloud translation API
+func (translator *googleTranslator) Detect(ctx context.Context, text string) (string, error) {
+	ctx, span := translator.tracer.Start(ctx)
+	defer span.End()
+
+	var response struct {
+		Data struct {
+			Detections [][]struct {
+				Language   string  `json:"language"`
+				Confidence float64 `json:"confidence"`
+			} `json:"detections"`
+		} `json:"data"`
+	}
+
+	err := requests.
+		URL("https://translation.googleapis.com/language/translate/v2/detect").
+		Client(translator.client).
+		Param("key", translator.apiKey).
+		BodyJSON(map[string]any{"q": []string{text}}).
+		ToJSON(&response).
+		Fetch(ctx)
+	if err != nil {
+		return "", translator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot detect the language of text with google translate"))
+	}
+
+	if len(response.Data.Detections) == 0 || len(response.Data.Detections[0]) == 0 {
+		return "", translator.tracer.WrapErrorSpan(span, stacktrace.NewError("google translate returned no language detections"))
+	}
+
+	return strings.ToLower(response.Data.Detections[0][0].Language), nil
+}
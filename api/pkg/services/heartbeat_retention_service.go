@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	heartbeatRetentionWindow   = 6 * time.Hour
+	heartbeatRetentionMaxBatch = 20
+)
+
+// HeartbeatRetentionService rolls the heartbeats which are older than the retention period into hourly and daily aggregates and deletes them
+type HeartbeatRetentionService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.HeartbeatRollupRepository
+	retention  time.Duration
+}
+
+// NewHeartbeatRetentionService creates a new HeartbeatRetentionService. Heartbeats are kept forever when the retention period is 0
+func NewHeartbeatRetentionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.HeartbeatRollupRepository,
+	retention time.Duration,
+) (s *HeartbeatRetentionService) {
+	return &HeartbeatRetentionService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		retention:  retention,
+	}
+}
+
+// Schedule prunes the heartbeats at every interval until the context is cancelled
+func (service *HeartbeatRetentionService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.Prune(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot prune heartbeats"))
+			}
+		}
+	}
+}
+
+// Prune rolls up and deletes the entities.Heartbeat which are older than the retention period at the timestamp.
+// The heartbeats are pruned in windows starting from the oldest heartbeat so that a single transaction does not lock the whole table
+func (service *HeartbeatRetentionService) Prune(ctx context.Context, timestamp time.Time) (int64, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.retention <= 0 {
+		return 0, nil
+	}
+
+	// only complete hours are rolled up so that an hourly rollup does not change after it is created
+	before := timestamp.Add(-service.retention).UTC().Truncate(time.Hour)
+
+	var total int64
+	for batch := 0; batch < heartbeatRetentionMaxBatch; batch++ {
+		count, err := service.repository.RollUp(ctx, before, heartbeatRetentionWindow)
+		if err != nil {
+			msg := fmt.Sprintf("cannot roll up heartbeats which were sent before [%s]", before)
+			return total, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		total += count
+		if count == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		ctxLogger.Info(fmt.Sprintf("rolled up and deleted [%d] heartbeats which were sent before [%s]", total, before))
+	}
+	return total, nil
+}
@@ -3,6 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
@@ -19,7 +23,8 @@ import (
 
 const (
 	// select id, a.timestamp, a.owner,  a.timestamp - (SELECT timestamp from heartbeats b where  b.timestamp < a.timestamp and a.owner = b.owner and a.user_id = b.user_id order by b.timestamp desc  limit 1) as diff  from heartbeats a;
-	heartbeatCheckInterval = 16 * time.Minute
+	// heartbeatCheckGracePeriod is added to the heartbeat interval of a phone before the next heartbeat check so that a heartbeat is not counted as late when it is delayed by a few seconds
+	heartbeatCheckGracePeriod = time.Minute
 )
 
 // HeartbeatService is handles heartbeat requests
@@ -28,7 +33,11 @@ type HeartbeatService struct {
 	logger            telemetry.Logger
 	tracer            telemetry.Tracer
 	repository        repositories.HeartbeatRepository
+	rollupRepository  repositories.HeartbeatRollupRepository
 	monitorRepository repositories.HeartbeatMonitorRepository
+	phoneRepository   repositories.PhoneRepository
+	userRepository    repositories.UserRepository
+	windowRepository  repositories.PhoneMaintenanceWindowRepository
 	dispatcher        *EventDispatcher
 }
 
@@ -37,14 +46,22 @@ func NewHeartbeatService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	repository repositories.HeartbeatRepository,
+	rollupRepository repositories.HeartbeatRollupRepository,
 	monitorRepository repositories.HeartbeatMonitorRepository,
+	phoneRepository repositories.PhoneRepository,
+	userRepository repositories.UserRepository,
+	windowRepository repositories.PhoneMaintenanceWindowRepository,
 	dispatcher *EventDispatcher,
 ) (s *HeartbeatService) {
 	return &HeartbeatService{
 		logger:            logger.WithService(fmt.Sprintf("%T", s)),
 		tracer:            tracer,
 		repository:        repository,
+		rollupRepository:  rollupRepository,
 		monitorRepository: monitorRepository,
+		phoneRepository:   phoneRepository,
+		userRepository:    userRepository,
+		windowRepository:  windowRepository,
 		dispatcher:        dispatcher,
 	}
 }
@@ -59,12 +76,17 @@ func (service *HeartbeatService) DeleteAllForUser(ctx context.Context, userID en
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	if err := service.rollupRepository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.HeartbeatRollup] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
 	if err := service.monitorRepository.DeleteAllForUser(ctx, userID); err != nil {
 		msg := fmt.Sprintf("could not delete all [entities.HeartbeatMonitor] for user with ID [%s]", userID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Heartbeat] and [entities.HeartbeatMonitor] for user with ID [%s]", userID))
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Heartbeat], [entities.HeartbeatRollup] and [entities.HeartbeatMonitor] for user with ID [%s]", userID))
 	return nil
 }
 
@@ -85,6 +107,139 @@ func (service *HeartbeatService) Index(ctx context.Context, userID entities.User
 	return heartbeats, nil
 }
 
+// Rollups fetches the hourly or daily aggregates of the heartbeats of a phone number
+func (service *HeartbeatService) Rollups(ctx context.Context, userID entities.UserID, owner string, resolution entities.HeartbeatRollupResolution, params repositories.IndexParams) ([]*entities.HeartbeatRollup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rollups, err := service.rollupRepository.Index(ctx, userID, owner, resolution, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch [%s] heartbeat rollups for owner [%s] with params [%+#v]", resolution, owner, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] [%s] heartbeat rollups for owner [%s] with params [%+#v]", len(rollups), resolution, owner, params))
+	return rollups, nil
+}
+
+// Signal fetches the signal strength and network type of the heartbeats of a phone in time buckets
+func (service *HeartbeatService) Signal(ctx context.Context, userID entities.UserID, params repositories.TimeseriesParams) ([]*entities.HeartbeatSignalBucket, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	buckets, err := service.repository.SignalTimeseries(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch the signal of heartbeats for user [%s] with params [%+#v]", userID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] heartbeat signal buckets for user [%s] with params [%+#v]", len(buckets), userID, params))
+	return buckets, nil
+}
+
+// HeartbeatReportParams are parameters for computing the uptime of a phone
+type HeartbeatReportParams struct {
+	UserID entities.UserID
+	Owner  string
+	Period entities.HeartbeatReportPeriod
+}
+
+// Report computes the uptime percentage and the outages of a phone in a period from its heartbeats.
+// An outage starts at the last heartbeat before a gap which is longer than the heartbeat timeout of the phone.
+func (service *HeartbeatService) Report(ctx context.Context, params *HeartbeatReportParams) (*entities.HeartbeatReport, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.Load(ctx, params.UserID, params.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with owner [%s] for user [%s]", params.Owner, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	to := time.Now().UTC()
+	from := to.Add(-params.Period.Duration())
+	if phone.CreatedAt.After(from) {
+		from = phone.CreatedAt.UTC()
+	}
+
+	timeout := phone.HeartbeatTimeoutDuration()
+	timestamps, err := service.repository.Timestamps(ctx, params.UserID, params.Owner, from.Add(-timeout), to)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch heartbeat timestamps of owner [%s] for user [%s] since [%s]", params.Owner, params.UserID, from)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	windows, err := service.windowRepository.LoadBetween(ctx, params.UserID, []string{params.Owner}, from, to)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load maintenance windows of owner [%s] for user [%s] since [%s]", params.Owner, params.UserID, from)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	report := &entities.HeartbeatReport{
+		Owner:   params.Owner,
+		Period:  params.Period,
+		From:    from,
+		To:      to,
+		Outages: []entities.HeartbeatOutage{},
+	}
+
+	// the phone is online at the start of the report when it sent a heartbeat within the timeout before from
+	var downtime time.Duration
+	previous := from.Add(-timeout)
+	for _, timestamp := range timestamps {
+		if !timestamp.Before(from) {
+			report.HeartbeatCount++
+		}
+		if timestamp.Sub(previous) > timeout {
+			endedAt := timestamp
+			downtime += service.addOutage(report, previous, &endedAt, windows)
+		}
+		previous = timestamp
+	}
+
+	if to.Sub(previous) > timeout {
+		downtime += service.addOutage(report, previous, nil, windows)
+	}
+
+	monitored := to.Sub(from)
+	for _, window := range windows {
+		monitored -= window.Overlap(from, to)
+	}
+
+	report.DowntimeSeconds = int64(downtime.Seconds())
+	report.UptimePercentage = 100
+	if monitored > 0 {
+		report.UptimePercentage = math.Round(max(0, float64(monitored-downtime))*10000/float64(monitored)) / 100
+	}
+
+	ctxLogger.Info(fmt.Sprintf("computed uptime [%.2f%%] with [%d] outages for owner [%s] and user [%s] in the last [%s]", report.UptimePercentage, len(report.Outages), params.Owner, params.UserID, params.Period))
+	return report, nil
+}
+
+// addOutage adds an entities.HeartbeatOutage which is clipped to the start of the report and returns its downtime excluding the maintenance windows
+func (service *HeartbeatService) addOutage(report *entities.HeartbeatReport, startedAt time.Time, endedAt *time.Time, windows []*entities.PhoneMaintenanceWindow) time.Duration {
+	if startedAt.Before(report.From) {
+		startedAt = report.From
+	}
+
+	end := report.To
+	if endedAt != nil {
+		end = *endedAt
+	}
+
+	report.Outages = append(report.Outages, entities.HeartbeatOutage{
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(end.Sub(startedAt).Seconds()),
+	})
+
+	downtime := end.Sub(startedAt)
+	for _, window := range windows {
+		downtime -= window.Overlap(startedAt, end)
+	}
+	return max(0, downtime)
+}
+
 // HeartbeatStoreParams are parameters for creating a new entities.Heartbeat
 type HeartbeatStoreParams struct {
 	Owner     string
@@ -93,6 +248,68 @@ type HeartbeatStoreParams struct {
 	Source    string
 	Timestamp time.Time
 	UserID    entities.UserID
+
+	BootedAt        *time.Time
+	DozeMode        bool
+	NetworkFailedAt *time.Time
+
+	SignalStrength *int
+	SignalBars     *uint
+	NetworkType    *entities.HeartbeatNetworkType
+
+	SIMs []HeartbeatSIMParams
+}
+
+// HeartbeatSIMParams are the details of the SIM card in a SIM slot of the phone which sent a heartbeat
+type HeartbeatSIMParams struct {
+	SIM         entities.SIM
+	PhoneNumber string
+	Operator    *string
+	ICCID       *string
+}
+
+// gapCause infers the likely cause of a gap which started at a timestamp from the flags reported by the app
+// simSlots returns the sorted SIM slots which have a SIM card
+func (params HeartbeatStoreParams) simSlots() []string {
+	slots := make([]string, 0, len(params.SIMs))
+	for _, sim := range params.SIMs {
+		if !slices.Contains(slots, sim.SIM.String()) {
+			slots = append(slots, sim.SIM.String())
+		}
+	}
+	sort.Strings(slots)
+	return slots
+}
+
+// phoneSIM returns the SIM card with the phone number of the phone or the SIM card in the SIM slot of the phone when the phone number
+// of the SIM card is unknown
+func (params HeartbeatStoreParams) phoneSIM(phone *entities.Phone) *HeartbeatSIMParams {
+	for index, sim := range params.SIMs {
+		if sim.PhoneNumber == phone.PhoneNumber {
+			return &params.SIMs[index]
+		}
+	}
+
+	for index, sim := range params.SIMs {
+		if sim.PhoneNumber == "" && sim.SIM == phone.SIM {
+			return &params.SIMs[index]
+		}
+	}
+
+	return nil
+}
+
+func (params HeartbeatStoreParams) gapCause(startedAt time.Time) entities.HeartbeatGapCause {
+	if params.BootedAt != nil && params.BootedAt.After(startedAt) {
+		return entities.HeartbeatGapCauseReboot
+	}
+	if params.NetworkFailedAt != nil && params.NetworkFailedAt.After(startedAt) {
+		return entities.HeartbeatGapCauseNoNetwork
+	}
+	if params.DozeMode {
+		return entities.HeartbeatGapCauseDozeMode
+	}
+	return entities.HeartbeatGapCauseUnknown
 }
 
 // Store a new entities.Heartbeat
@@ -103,12 +320,28 @@ func (service *HeartbeatService) Store(ctx context.Context, params HeartbeatStor
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
 	heartbeat := &entities.Heartbeat{
-		ID:        uuid.New(),
-		Owner:     params.Owner,
-		Charging:  params.Charging,
-		Timestamp: params.Timestamp,
-		Version:   params.Version,
-		UserID:    params.UserID,
+		ID:             uuid.New(),
+		Owner:          params.Owner,
+		Charging:       params.Charging,
+		Timestamp:      params.Timestamp,
+		Version:        params.Version,
+		UserID:         params.UserID,
+		SignalStrength: params.SignalStrength,
+		SignalBars:     params.SignalBars,
+		NetworkType:    params.NetworkType,
+	}
+
+	if phone, err := service.phoneRepository.Load(ctx, params.UserID, params.Owner); err == nil {
+		heartbeat.IntervalSeconds = phone.HeartbeatIntervalSecondsSanitized()
+		service.updatePhoneSIM(ctx, params, phone)
+	} else {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone with owner [%s] for user [%s] using the default heartbeat interval", params.Owner, params.UserID)))
+		heartbeat.IntervalSeconds = new(entities.Phone).HeartbeatIntervalSecondsSanitized()
+	}
+
+	monitor, monitorErr := service.monitorRepository.Load(ctx, params.UserID, params.Owner)
+	if monitorErr == nil && monitor.PhoneIsOffline() {
+		service.annotateGap(ctx, heartbeat, params)
 	}
 
 	if err := service.repository.Store(ctx, heartbeat); err != nil {
@@ -118,14 +351,13 @@ func (service *HeartbeatService) Store(ctx context.Context, params HeartbeatStor
 
 	ctxLogger.Info(fmt.Sprintf("heartbeat saved with id [%s] for user [%s]", heartbeat.ID, heartbeat.UserID))
 
-	monitor, err := service.monitorRepository.Load(ctx, params.UserID, params.Owner)
-	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+	if stacktrace.GetCode(monitorErr) == repositories.ErrCodeNotFound {
 		ctxLogger.Info(fmt.Sprintf("heartbeat monitor does not exist for owner [%s] and user [%s]", params.Owner, params.UserID))
 		return heartbeat, nil
 	}
-	if err != nil {
+	if monitorErr != nil {
 		msg := fmt.Sprintf("cannot load heartbeat monitor for owner [%s] and user [%s]", params.Owner, params.UserID)
-		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		ctxLogger.Error(stacktrace.Propagate(monitorErr, msg))
 		return heartbeat, nil
 	}
 
@@ -137,6 +369,112 @@ func (service *HeartbeatService) Store(ctx context.Context, params HeartbeatStor
 	return heartbeat, nil
 }
 
+// annotateGap sets the start and the likely cause of the gap which is closed by a heartbeat of an offline phone
+// updatePhoneSIM stores the SIM card which is in the SIM slot of the phone and dispatches the events.EventTypePhoneSIMSwapped event
+// when the serial number of the SIM card is different from the one which was previously reported
+func (service *HeartbeatService) updatePhoneSIM(ctx context.Context, params HeartbeatStoreParams, phone *entities.Phone) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	slots := params.simSlots()
+	slotsChanged := len(params.SIMs) != 0 && strings.Join(slots, ",") != strings.Join(phone.SIMSlots, ",")
+	if slotsChanged {
+		phone.SIMSlots = slots
+	}
+
+	// the operator and the serial number are not always readable e.g. when the phone has no signal so only the values which are reported are stored
+	sim := params.phoneSIM(phone)
+	if sim == nil || (service.isUnchanged(sim.Operator, phone.SIMOperator) && service.isUnchanged(sim.ICCID, phone.SIMICCID)) {
+		if slotsChanged {
+			service.savePhoneSIMSlots(ctx, phone)
+		}
+		return
+	}
+
+	previousICCID := phone.SIMICCID
+	swapped := previousICCID != nil && sim.ICCID != nil && *previousICCID != *sim.ICCID
+
+	if sim.Operator != nil {
+		phone.SIMOperator = sim.Operator
+	}
+	if sim.ICCID != nil {
+		phone.SIMICCID = sim.ICCID
+	}
+	if swapped {
+		phone.SIMSwappedAt = &params.Timestamp
+	}
+
+	if err := service.phoneRepository.Save(ctx, phone); err != nil {
+		msg := fmt.Sprintf("cannot save the SIM card of phone [%s] for user [%s]", phone.ID, phone.UserID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	if !swapped {
+		return
+	}
+
+	ctxLogger.Info(fmt.Sprintf("the SIM card of phone [%s] in [%s] was swapped from [%s] to [%s]", phone.ID, phone.SIM, *previousICCID, *sim.ICCID))
+
+	event, err := service.createEvent(events.EventTypePhoneSIMSwapped, params.Source, &events.PhoneSIMSwappedPayload{
+		PhoneID:       phone.ID,
+		UserID:        phone.UserID,
+		Owner:         phone.PhoneNumber,
+		SIM:           phone.SIM,
+		Operator:      sim.Operator,
+		PreviousICCID: *previousICCID,
+		ICCID:         *sim.ICCID,
+		Timestamp:     params.Timestamp,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for phone [%s]", events.EventTypePhoneSIMSwapped, phone.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for phone with id [%s]", event.Type(), phone.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+// savePhoneSIMSlots stores the SIM slots of a phone which have a SIM card
+func (service *HeartbeatService) savePhoneSIMSlots(ctx context.Context, phone *entities.Phone) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.phoneRepository.Save(ctx, phone); err != nil {
+		msg := fmt.Sprintf("cannot save the SIM slots [%s] of phone [%s] for user [%s]", strings.Join(phone.SIMSlots, ","), phone.ID, phone.UserID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	ctxLogger.Info(fmt.Sprintf("saved the SIM slots [%s] of phone [%s] for user [%s]", strings.Join(phone.SIMSlots, ","), phone.ID, phone.UserID))
+}
+
+// isUnchanged checks if a reported value is empty or the same as the stored value
+func (service *HeartbeatService) isUnchanged(reported *string, stored *string) bool {
+	return reported == nil || (stored != nil && *reported == *stored)
+}
+
+func (service *HeartbeatService) annotateGap(ctx context.Context, heartbeat *entities.Heartbeat, params HeartbeatStoreParams) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	previous, err := service.repository.Last(ctx, params.UserID, params.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load last heartbeat for owner [%s] and user [%s] to annotate the gap", params.Owner, params.UserID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	cause := params.gapCause(previous.Timestamp)
+	heartbeat.GapStartedAt = &previous.Timestamp
+	heartbeat.GapCause = &cause
+
+	ctxLogger.Info(fmt.Sprintf("heartbeat [%s] closed gap since [%s] for owner [%s] with cause [%s]", heartbeat.ID, previous.Timestamp, params.Owner, cause))
+}
+
 // HeartbeatMonitorStoreParams are parameters for creating a new entities.Heartbeat
 type HeartbeatMonitorStoreParams struct {
 	Owner   string
@@ -203,7 +541,7 @@ func (service *HeartbeatService) StoreMonitor(ctx context.Context, params *Heart
 		MonitorID: monitor.ID,
 		Source:    params.Source,
 	}
-	if err = service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), monitorParams); err != nil {
+	if err = service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), service.heartbeatPhone(ctx, monitorParams).HeartbeatIntervalDuration(), monitorParams); err != nil {
 		msg := fmt.Sprintf("cannot schedule healthcheck for monitor [%s] with owner [%s] and userID [%s]", monitor.ID, params.Owner, params.UserID)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -301,7 +639,7 @@ func (service *HeartbeatService) Monitor(ctx context.Context, params *HeartbeatM
 	if err != nil {
 		msg := fmt.Sprintf("cannot check if monitor exists with userID [%s] and owner [%s]", params.UserID, params.Owner)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
-		return service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), params)
+		return service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), service.heartbeatPhone(ctx, params).HeartbeatIntervalDuration(), params)
 	}
 
 	// Update params in case of ID duplicate
@@ -315,21 +653,105 @@ func (service *HeartbeatService) Monitor(ctx context.Context, params *HeartbeatM
 		return nil
 	}
 
+	phone := service.heartbeatPhone(ctx, params)
+	interval := phone.HeartbeatIntervalDuration()
+	if phone.IsDeactivated() {
+		ctxLogger.Info(fmt.Sprintf("phone with owner [%s] and monitor ID [%s] is deactivated, offline alerts are suppressed", params.Owner, params.MonitorID))
+		return service.scheduleHeartbeatCheck(ctx, heartbeat.Timestamp, interval, params)
+	}
+
+	user := service.heartbeatUser(ctx, params)
+	checkInterval := interval + heartbeatCheckGracePeriod
+	timeout := user.HeartbeatOfflineTimeout(interval, phone.HeartbeatTimeoutDuration())
+	offlineSince, inMaintenance := service.offlineSince(ctx, heartbeat.Timestamp, params)
+
 	// send urgent FCM message if the last heartbeat is late
-	if time.Now().UTC().Sub(heartbeat.Timestamp) > heartbeatCheckInterval && time.Now().UTC().Sub(heartbeat.Timestamp) < (heartbeatCheckInterval*5) {
+	if time.Now().UTC().Sub(heartbeat.Timestamp) > checkInterval && time.Now().UTC().Sub(offlineSince) < (timeout+checkInterval) {
 		ctxLogger.Info(fmt.Sprintf("sending missed heartbeat notification for userID [%s] and owner [%s] and monitor ID [%s]", params.UserID, params.Owner, params.MonitorID))
-		service.handleMissedMonitor(ctx, heartbeat.Timestamp, params)
+		service.handleMissedMonitor(ctx, heartbeat.Timestamp, checkInterval, params)
+	}
+
+	if inMaintenance {
+		ctxLogger.Info(fmt.Sprintf("phone with owner [%s] and monitor ID [%s] is in a maintenance window, offline alerts are suppressed", params.Owner, params.MonitorID))
+		return service.scheduleHeartbeatCheck(ctx, heartbeat.Timestamp, interval, params)
+	}
+
+	// a phone which went offline during the alert cool-down is reported as offline when the cool-down ends
+	offlineAt := offlineSince.Add(timeout)
+	if cooldown := user.HeartbeatAlertCooldownDuration(); monitor.LastOfflineAt != nil && monitor.LastOfflineAt.Add(cooldown).After(offlineAt) {
+		offlineAt = monitor.LastOfflineAt.Add(cooldown)
+	}
+
+	if time.Now().UTC().After(offlineAt) && time.Now().UTC().Before(offlineAt.Add(checkInterval)) && monitor.PhoneOnline {
+		return service.handleFailedMonitor(ctx, heartbeat.Timestamp, interval, params)
+	}
+
+	if time.Now().UTC().Sub(offlineSince) > timeout && time.Now().UTC().Before(offlineAt) && monitor.PhoneOnline {
+		ctxLogger.Info(fmt.Sprintf("phone with owner [%s] and monitor ID [%s] went offline again before [%s], the offline alert is postponed", params.Owner, params.MonitorID, offlineAt))
+	}
+
+	return service.scheduleHeartbeatCheck(ctx, heartbeat.Timestamp, interval, params)
+}
+
+// heartbeatPhone returns the phone of the monitor which has the heartbeat interval and timeout. A phone with the default
+// heartbeat interval and timeout is returned when the phone cannot be loaded.
+func (service *HeartbeatService) heartbeatPhone(ctx context.Context, params *HeartbeatMonitorParams) *entities.Phone {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with ID [%s] for userID [%s] using the default heartbeat interval and timeout", params.PhoneID, params.UserID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return new(entities.Phone)
 	}
 
-	if time.Now().UTC().Sub(heartbeat.Timestamp) > (heartbeatCheckInterval*4) &&
-		time.Now().UTC().Sub(heartbeat.Timestamp) < (heartbeatCheckInterval*5) && monitor.PhoneOnline {
-		return service.handleFailedMonitor(ctx, heartbeat.Timestamp, params)
+	return phone
+}
+
+// heartbeatUser returns the user of the monitor which has the offline threshold and the alert cool-down. A user without an
+// offline threshold and an alert cool-down is returned when the user cannot be loaded.
+func (service *HeartbeatService) heartbeatUser(ctx context.Context, params *HeartbeatMonitorParams) *entities.User {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, params.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s] using the heartbeat timeout of the phone without an alert cool-down", params.UserID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return new(entities.User)
 	}
 
-	return service.scheduleHeartbeatCheck(ctx, heartbeat.Timestamp, params)
+	return user
 }
 
-func (service *HeartbeatService) handleMissedMonitor(ctx context.Context, lastTimestamp time.Time, params *HeartbeatMonitorParams) {
+// offlineSince returns the time from which the phone of the monitor is counted as offline. The heartbeat timeout starts
+// again at the end of a maintenance window and inMaintenance is true when the phone is currently in a maintenance window.
+func (service *HeartbeatService) offlineSince(ctx context.Context, lastTimestamp time.Time, params *HeartbeatMonitorParams) (offlineSince time.Time, inMaintenance bool) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	windows, err := service.windowRepository.LoadBetween(ctx, params.UserID, []string{params.Owner}, lastTimestamp, time.Now().UTC())
+	if err != nil {
+		msg := fmt.Sprintf("cannot load maintenance windows for owner [%s] and userID [%s] since [%s]", params.Owner, params.UserID, lastTimestamp)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return lastTimestamp, false
+	}
+
+	offlineSince = lastTimestamp
+	for _, window := range windows {
+		if window.Contains(time.Now().UTC()) {
+			return offlineSince, true
+		}
+		if window.EndsAt.After(offlineSince) {
+			offlineSince = window.EndsAt
+		}
+	}
+
+	return offlineSince, false
+}
+
+func (service *HeartbeatService) handleMissedMonitor(ctx context.Context, lastTimestamp time.Time, checkInterval time.Duration, params *HeartbeatMonitorParams) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
@@ -347,17 +769,17 @@ func (service *HeartbeatService) handleMissedMonitor(ctx context.Context, lastTi
 		return
 	}
 
-	if _, err = service.dispatcher.DispatchWithTimeout(ctx, event, heartbeatCheckInterval); err != nil {
+	if _, err = service.dispatcher.DispatchWithTimeout(ctx, event, checkInterval); err != nil {
 		msg := fmt.Sprintf("cannot dispatch event [%s] for heartbeat monitor with phone id [%s]", event.Type(), params.PhoneID)
 		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
 	}
 }
 
-func (service *HeartbeatService) handleFailedMonitor(ctx context.Context, lastTimestamp time.Time, params *HeartbeatMonitorParams) error {
+func (service *HeartbeatService) handleFailedMonitor(ctx context.Context, lastTimestamp time.Time, interval time.Duration, params *HeartbeatMonitorParams) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	err := service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), params)
+	err := service.scheduleHeartbeatCheck(ctx, time.Now().UTC(), interval, params)
 	if err != nil {
 		msg := fmt.Sprintf("cannot schedule healthcheck for monitor with owner [%s] and userID [%s]", params.Owner, params.UserID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -385,15 +807,18 @@ func (service *HeartbeatService) handleFailedMonitor(ctx context.Context, lastTi
 	return nil
 }
 
-func (service *HeartbeatService) scheduleHeartbeatCheck(ctx context.Context, lastTimestamp time.Time, params *HeartbeatMonitorParams) error {
+// scheduleHeartbeatCheck schedules the next heartbeat check after the heartbeat interval of the phone since the last heartbeat
+func (service *HeartbeatService) scheduleHeartbeatCheck(ctx context.Context, lastTimestamp time.Time, interval time.Duration, params *HeartbeatMonitorParams) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	checkInterval := interval + heartbeatCheckGracePeriod
+
 	event, err := service.createPhoneHeartbeatCheckEvent(params.Source, &events.PhoneHeartbeatCheckPayload{
 		PhoneID:     params.PhoneID,
 		UserID:      params.UserID,
 		MonitorID:   params.MonitorID,
-		ScheduledAt: lastTimestamp.Add(heartbeatCheckInterval),
+		ScheduledAt: lastTimestamp.Add(checkInterval),
 		Owner:       params.Owner,
 	})
 	if err != nil {
@@ -401,7 +826,7 @@ func (service *HeartbeatService) scheduleHeartbeatCheck(ctx context.Context, las
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	queueID, err := service.dispatcher.DispatchWithTimeout(ctx, event, heartbeatCheckInterval)
+	queueID, err := service.dispatcher.DispatchWithTimeout(ctx, event, checkInterval)
 	if err != nil {
 		msg := fmt.Sprintf("cannot dispatch event [%s] for heartbeat monitor with phone id [%s]", event.Type(), params.PhoneID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
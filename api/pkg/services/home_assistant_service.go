@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const homeAssistantSensorStateUnknown = "unknown"
+
+var homeAssistantConfigurationTemplate = template.Must(template.New("home-assistant").Parse(`# Add your httpSMS API key to secrets.yaml as "httpsms_api_key"
+notify:
+{{- range .Phones}}
+  - platform: rest
+    name: httpsms_{{.Slug}}
+    resource: {{$.NotifyURL}}
+    method: POST_JSON
+    target_param_name: target
+    headers:
+      x-api-key: !secret httpsms_api_key
+    data:
+      from: "{{.PhoneNumber}}"
+{{- end}}
+
+sensor:
+{{- range .Phones}}
+  - platform: rest
+    name: httpsms_{{.Slug}}_delivery_status
+    resource: {{$.SensorURL}}?owner={{.QueryValue}}
+    scan_interval: 60
+    headers:
+      x-api-key: !secret httpsms_api_key
+    value_template: "{{"{{"}} value_json.data.state {{"}}"}}"
+    json_attributes_path: "$.data"
+    json_attributes:
+      - message_id
+      - contact
+      - failure_reason
+      - updated_at
+{{- end}}
+`))
+
+// HomeAssistantService configures the Home Assistant integration
+type HomeAssistantService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	phoneRepository   repositories.PhoneRepository
+	messageRepository repositories.MessageRepository
+}
+
+// NewHomeAssistantService creates a new HomeAssistantService
+func NewHomeAssistantService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneRepository repositories.PhoneRepository,
+	messageRepository repositories.MessageRepository,
+) (s *HomeAssistantService) {
+	return &HomeAssistantService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		phoneRepository:   phoneRepository,
+		messageRepository: messageRepository,
+	}
+}
+
+// DefaultOwner returns the phone number of the most recently added entities.Phone of a user
+func (service *HomeAssistantService) DefaultOwner(ctx context.Context, userID entities.UserID) (string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	phones, err := service.phoneRepository.Index(ctx, userID, repositories.IndexParams{Limit: 1})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch phones for user with ID [%s]", userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(*phones) == 0 {
+		msg := fmt.Sprintf("user with ID [%s] does not have any phone", userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	return (*phones)[0].PhoneNumber, nil
+}
+
+// Discovery returns the endpoints and the configuration.yaml snippet for the phones of a user
+func (service *HomeAssistantService) Discovery(ctx context.Context, userID entities.UserID, baseURL string) (*entities.HomeAssistantDiscovery, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phones, err := service.phoneRepository.Index(ctx, userID, repositories.IndexParams{Limit: 100})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch phones for user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	discovery := &entities.HomeAssistantDiscovery{
+		NotifyURL: strings.TrimRight(baseURL, "/") + "/v1/home-assistant/notify",
+		SensorURL: strings.TrimRight(baseURL, "/") + "/v1/home-assistant/sensor",
+	}
+
+	type phone struct {
+		PhoneNumber string
+		Slug        string
+		QueryValue  string
+	}
+
+	data := struct {
+		NotifyURL string
+		SensorURL string
+		Phones    []phone
+	}{NotifyURL: discovery.NotifyURL, SensorURL: discovery.SensorURL}
+
+	for _, item := range *phones {
+		data.Phones = append(data.Phones, phone{
+			PhoneNumber: item.PhoneNumber,
+			Slug:        strings.TrimPrefix(item.PhoneNumber, "+"),
+			QueryValue:  url.QueryEscape(item.PhoneNumber),
+		})
+	}
+
+	var buffer bytes.Buffer
+	if err = homeAssistantConfigurationTemplate.Execute(&buffer, data); err != nil {
+		msg := fmt.Sprintf("cannot render home assistant configuration for user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	discovery.Configuration = buffer.String()
+
+	ctxLogger.Info(fmt.Sprintf("generated home assistant configuration for [%d] phones of user with ID [%s]", len(data.Phones), userID))
+	return discovery, nil
+}
+
+// Sensor returns the delivery status of the last message sent by a phone
+func (service *HomeAssistantService) Sensor(ctx context.Context, params *MessageSearchParams) (*entities.HomeAssistantSensor, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	messages, err := service.messageRepository.Search(ctx, params.UserID, params.Owners, params.Types, params.Statuses, params.SenderNames, params.IndexParams)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch last message for user with ID [%s] and owners [%s]", params.UserID, strings.Join(params.Owners, ","))
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	sensor := &entities.HomeAssistantSensor{State: homeAssistantSensorStateUnknown, Owner: strings.Join(params.Owners, ",")}
+	if len(messages) == 0 {
+		return sensor, nil
+	}
+
+	message := messages[0]
+	sensor.State = string(message.Status)
+	sensor.MessageID = &message.ID
+	sensor.Contact = &message.Contact
+	sensor.FailureReason = message.FailureReason
+	sensor.UpdatedAt = &message.UpdatedAt
+	return sensor, nil
+}
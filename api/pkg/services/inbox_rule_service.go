@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// InboxRuleService labels, archives or marks as read the message threads of received messages which match the rules of a user
+type InboxRuleService struct {
+	service
+	logger           telemetry.Logger
+	tracer           telemetry.Tracer
+	repository       repositories.InboxRuleRepository
+	threadRepository repositories.MessageThreadRepository
+}
+
+// NewInboxRuleService creates a new InboxRuleService
+func NewInboxRuleService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.InboxRuleRepository,
+	threadRepository repositories.MessageThreadRepository,
+) (s *InboxRuleService) {
+	return &InboxRuleService{
+		logger:           logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:           tracer,
+		repository:       repository,
+		threadRepository: threadRepository,
+	}
+}
+
+// DeleteAllForUser deletes all entities.InboxRule for an entities.UserID.
+func (service *InboxRuleService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.InboxRule] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.InboxRule] for user with ID [%s]", userID))
+	return nil
+}
+
+// Index fetches the entities.InboxRule for an entities.UserID
+func (service *InboxRuleService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.InboxRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch inbox rules with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] inbox rules with prams [%+#v]", len(rules), params))
+	return rules, nil
+}
+
+// Delete an entities.InboxRule
+func (service *InboxRuleService) Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot load inbox rule with userID [%s] and ruleID [%s]", userID, ruleID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot delete inbox rule with id [%s] and user id [%s]", ruleID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted inbox rule with id [%s] and user id [%s]", ruleID, userID))
+	return nil
+}
+
+// InboxRuleStoreParams are parameters for creating a new entities.InboxRule
+type InboxRuleStoreParams struct {
+	UserID   entities.UserID
+	Name     string
+	Sender   string
+	Content  string
+	Label    *string
+	Archive  bool
+	MarkRead bool
+}
+
+// Store a new entities.InboxRule
+func (service *InboxRuleService) Store(ctx context.Context, params *InboxRuleStoreParams) (*entities.InboxRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule := &entities.InboxRule{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Sender:    params.Sender,
+		Content:   params.Content,
+		Label:     params.Label,
+		Archive:   params.Archive,
+		MarkRead:  params.MarkRead,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save inbox rule with id [%s]", rule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("inbox rule saved with id [%s] for user [%s] in the [%T]", rule.ID, rule.UserID, service.repository))
+	return rule, nil
+}
+
+// InboxRuleUpdateParams are parameters for updating an entities.InboxRule
+type InboxRuleUpdateParams struct {
+	UserID   entities.UserID
+	RuleID   uuid.UUID
+	Name     string
+	Sender   string
+	Content  string
+	Label    *string
+	Archive  bool
+	MarkRead bool
+}
+
+// Update an entities.InboxRule
+func (service *InboxRuleService) Update(ctx context.Context, params *InboxRuleUpdateParams) (*entities.InboxRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule, err := service.repository.Load(ctx, params.UserID, params.RuleID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load inbox rule with userID [%s] and ruleID [%s]", params.UserID, params.RuleID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	rule.Name = params.Name
+	rule.Sender = params.Sender
+	rule.Content = params.Content
+	rule.Label = params.Label
+	rule.Archive = params.Archive
+	rule.MarkRead = params.MarkRead
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save inbox rule with id [%s] after update", rule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("inbox rule updated with id [%s] in the [%T]", rule.ID, service.repository))
+	return rule, nil
+}
+
+// Apply the inbox rules of a user to the message thread of a received message
+func (service *InboxRuleService) Apply(ctx context.Context, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.repository.LoadAll(ctx, payload.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load inbox rules for user with ID [%s]", payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var labels []string
+	archive, markRead, matched := false, false, make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if !rule.Matches(payload.Contact, payload.Content, payload.Encrypted) {
+			continue
+		}
+
+		if rule.Label != nil {
+			labels = append(labels, *rule.Label)
+		}
+		archive = archive || rule.Archive
+		markRead = markRead || rule.MarkRead
+		matched = append(matched, rule.ID.String())
+	}
+
+	if len(matched) == 0 {
+		ctxLogger.Info(fmt.Sprintf("message [%s] does not match any of the [%d] inbox rules of user [%s]", payload.MessageID, len(rules), payload.UserID))
+		return nil
+	}
+
+	thread, err := service.threadRepository.LoadByOwnerContact(ctx, payload.UserID, payload.Owner, payload.Contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load thread between owner [%s] and contact [%s] for user [%s]", payload.Owner, payload.Contact, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	thread.AddLabels(labels)
+	if archive {
+		thread.UpdateArchive(true)
+	}
+	if markRead {
+		thread.UpdateUnread(false)
+	}
+
+	if err = service.threadRepository.Update(ctx, thread); err != nil {
+		msg := fmt.Sprintf("cannot update message thread with id [%s] after applying inbox rules [%s]", thread.ID, strings.Join(matched, ","))
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("applied inbox rules [%s] to thread [%s] for message [%s]", strings.Join(matched, ","), thread.ID, payload.MessageID))
+	return nil
+}
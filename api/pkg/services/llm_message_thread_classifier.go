@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const llmMessageThreadClassifierInstructions = "You classify SMS conversations between the owner of a phone and a contact. " +
+	"Pick the topic of the conversation which is one of [billing, support, spam, other] and detect the language of the contact as an ISO 639-1 code. " +
+	`Reply only with a JSON object e.g. {"topic": "support", "language": "en"}`
+
+var llmMessageThreadLanguageRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,4})?$`)
+
+type llmMessageThreadClassifier struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	provider LLMProvider
+}
+
+// NewLLMMessageThreadClassifier creates a MessageThreadClassifier which asks an LLMProvider for the topic and language of a conversation
+func NewLLMMessageThreadClassifier(logger telemetry.Logger, tracer telemetry.Tracer, provider LLMProvider) MessageThreadClassifier {
+	return &llmMessageThreadClassifier{
+		logger:   logger.WithService(fmt.Sprintf("%T", &llmMessageThreadClassifier{})),
+		tracer:   tracer,
+		provider: provider,
+	}
+}
+
+// Classify sends a transcript of the messages to the LLMProvider and parses the JSON reply
+func (classifier *llmMessageThreadClassifier) Classify(ctx context.Context, messages []entities.Message) (*MessageThreadClassification, error) {
+	ctx, span := classifier.tracer.Start(ctx)
+	defer span.End()
+
+	reply, err := classifier.provider.Complete(ctx, llmMessageThreadClassifierInstructions, []entities.MessageThreadContextMessage{
+		{Role: entities.MessageThreadContextRoleUser, Content: classifier.transcript(messages), Timestamp: time.Now().UTC()},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot classify [%d] messages with the LLM provider", len(messages))
+		return nil, classifier.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var payload struct {
+		Topic    string `json:"topic"`
+		Language string `json:"language"`
+	}
+
+	// models often wrap the JSON in a markdown code block even when they are asked not to
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reply, "```json"), "```"), "```")
+	if err = json.Unmarshal([]byte(reply), &payload); err != nil {
+		msg := fmt.Sprintf("cannot decode the classification [%s] of the LLM provider", reply)
+		return nil, classifier.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	classification := &MessageThreadClassification{
+		Topic:    entities.MessageThreadTopic(strings.ToLower(strings.TrimSpace(payload.Topic))),
+		Language: lastMessageLanguage(messages),
+	}
+
+	if !slices.Contains(entities.MessageThreadTopics(), classification.Topic) {
+		classification.Topic = entities.MessageThreadTopicOther
+	}
+
+	if language := strings.ToLower(strings.TrimSpace(payload.Language)); llmMessageThreadLanguageRegex.MatchString(language) {
+		classification.Language = &language
+	}
+
+	return classification, nil
+}
+
+// transcript renders the messages as one line per message with the oldest message first
+func (classifier *llmMessageThreadClassifier) transcript(messages []entities.Message) string {
+	var builder strings.Builder
+	for index := len(messages) - 1; index >= 0; index-- {
+		author := "Owner"
+		if messages[index].Type == entities.MessageTypeMobileOriginated {
+			author = "Contact"
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s\n", author, messages[index].Content))
+	}
+	return builder.String()
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// LLMProvider generates text with a large language model
+type LLMProvider interface {
+	// Complete generates the next message of a conversation which follows the instructions
+	Complete(ctx context.Context, instructions string, messages []entities.MessageThreadContextMessage) (string, error)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// MattermostNotificationChannelSender sends alerts as message attachments to Mattermost incoming webhooks
+type MattermostNotificationChannelSender struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *http.Client
+}
+
+// NewMattermostNotificationChannelSender creates a new MattermostNotificationChannelSender
+func NewMattermostNotificationChannelSender(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+) (s *MattermostNotificationChannelSender) {
+	return &MattermostNotificationChannelSender{
+		logger: logger.WithService(fmt.Sprintf("%T", s)),
+		tracer: tracer,
+		client: client,
+	}
+}
+
+// Provider returns entities.NotificationChannelProviderMattermost
+func (sender *MattermostNotificationChannelSender) Provider() entities.NotificationChannelProvider {
+	return entities.NotificationChannelProviderMattermost
+}
+
+// Send posts the alert to the Mattermost webhook https://developers.mattermost.com/integrate/reference/message-attachments/
+func (sender *MattermostNotificationChannelSender) Send(ctx context.Context, channel *entities.NotificationChannel, alert *NotificationChannelAlert) error {
+	ctx, span := sender.tracer.Start(ctx)
+	defer span.End()
+
+	payload := fiber.Map{
+		"username":    "httpsms.com",
+		"icon_url":    "https://httpsms.com/avatar.png",
+		"attachments": []fiber.Map{createNotificationChannelAttachment(alert)},
+	}
+
+	if err := postNotificationChannelPayload(ctx, sender.client, channel.WebhookURL, payload); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert to mattermost channel [%s] for user [%s]", alert.Event, channel.ID, channel.UserID)
+		return sender.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
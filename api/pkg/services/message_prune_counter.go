@@ -0,0 +1,39 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// MessagePruneCounter counts the messages of each category which were deleted by the MessageRetentionService since the process started
+type MessagePruneCounter struct {
+	mutex  sync.Mutex
+	pruned map[entities.MessageCategory]uint64
+}
+
+// NewMessagePruneCounter creates a new MessagePruneCounter
+func NewMessagePruneCounter() *MessagePruneCounter {
+	return &MessagePruneCounter{
+		pruned: map[entities.MessageCategory]uint64{},
+	}
+}
+
+// RecordPruned adds the number of deleted messages of a category
+func (counter *MessagePruneCounter) RecordPruned(category entities.MessageCategory, count uint64) {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+	counter.pruned[category] += count
+}
+
+// Totals returns the number of deleted messages of each category
+func (counter *MessagePruneCounter) Totals() map[entities.MessageCategory]uint64 {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	totals := make(map[entities.MessageCategory]uint64, len(counter.pruned))
+	for category, count := range counter.pruned {
+		totals[category] = count
+	}
+	return totals
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	messageRetentionBatchSize = 1000
+	messageRetentionMaxBatch  = 10
+)
+
+// MessageRetentionService deletes the messages which are older than the retention period of their category and redacts the content of OTP messages
+type MessageRetentionService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.MessageRepository
+	counter    *MessagePruneCounter
+	retention  map[entities.MessageCategory]time.Duration
+	redaction  time.Duration
+}
+
+// NewMessageRetentionService creates a new MessageRetentionService. Messages of a category without a retention period are kept forever
+// and the content of OTP messages is not redacted when the redaction period is 0
+func NewMessageRetentionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageRepository,
+	counter *MessagePruneCounter,
+	retention map[entities.MessageCategory]time.Duration,
+	redaction time.Duration,
+) (s *MessageRetentionService) {
+	return &MessageRetentionService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		counter:    counter,
+		retention:  retention,
+		redaction:  redaction,
+	}
+}
+
+// Schedule prunes and redacts the messages at every interval until the context is cancelled
+func (service *MessageRetentionService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.Prune(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot prune messages"))
+			}
+			if _, err := service.Redact(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot redact OTP messages"))
+			}
+		}
+	}
+}
+
+// Prune deletes the entities.Message of every category which are older than the retention period of the category at the timestamp
+func (service *MessageRetentionService) Prune(ctx context.Context, timestamp time.Time) (map[entities.MessageCategory]int64, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	result := make(map[entities.MessageCategory]int64, len(service.retention))
+	for category, retention := range service.retention {
+		if retention <= 0 {
+			continue
+		}
+
+		count, err := service.pruneCategory(ctx, category, timestamp.Add(-retention))
+		result[category] = count
+		if err != nil {
+			msg := fmt.Sprintf("cannot prune [%s] messages with retention [%s]", category, retention)
+			return result, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	return result, nil
+}
+
+// Redact replaces the content of the OTP entities.Message which are older than the redaction period at the timestamp while keeping their delivery records
+func (service *MessageRetentionService) Redact(ctx context.Context, timestamp time.Time) (int64, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.redaction <= 0 {
+		return 0, nil
+	}
+
+	before := timestamp.Add(-service.redaction)
+
+	var total int64
+	for batch := 0; batch < messageRetentionMaxBatch; batch++ {
+		count, err := service.repository.Redact(ctx, entities.MessageCategoryOTP, before, messageRetentionBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot redact [%s] messages which were created before [%s]", entities.MessageCategoryOTP, before)
+			return total, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		total += count
+		if count < messageRetentionBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		ctxLogger.Info(fmt.Sprintf("redacted [%d] [%s] messages which were created before [%s]", total, entities.MessageCategoryOTP, before))
+	}
+	return total, nil
+}
+
+func (service *MessageRetentionService) pruneCategory(ctx context.Context, category entities.MessageCategory, before time.Time) (int64, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	var total int64
+	for batch := 0; batch < messageRetentionMaxBatch; batch++ {
+		count, err := service.repository.DeleteBefore(ctx, category, before, messageRetentionBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot delete [%s] messages which were created before [%s]", category, before)
+			return total, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		total += count
+		service.counter.RecordPruned(category, uint64(count))
+
+		if count < messageRetentionBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		ctxLogger.Info(fmt.Sprintf("deleted [%d] [%s] messages which were created before [%s]", total, category, before))
+	}
+	return total, nil
+}
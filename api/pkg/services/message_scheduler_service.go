@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	messageSchedulerSource    = "/v1/messages/scheduler"
+	messageSchedulerBatchSize = 100
+	messageSchedulerMaxBatch  = 10
+)
+
+// MessageSchedulerService dispatches the messages which are held in the deferred status until their send time
+type MessageSchedulerService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.MessageRepository
+	dispatcher *EventDispatcher
+}
+
+// NewMessageSchedulerService creates a new MessageSchedulerService
+func NewMessageSchedulerService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageRepository,
+	dispatcher *EventDispatcher,
+) (s *MessageSchedulerService) {
+	return &MessageSchedulerService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		dispatcher: dispatcher,
+	}
+}
+
+// Schedule dispatches the messages which are due at every interval until the context is cancelled
+func (service *MessageSchedulerService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.DispatchDue(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot dispatch scheduled messages"))
+			}
+		}
+	}
+}
+
+// DispatchDue releases the deferred entities.Message with a send time before the timestamp and dispatches them to the phone
+func (service *MessageSchedulerService) DispatchDue(ctx context.Context, timestamp time.Time) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	count := 0
+	for batch := 0; batch < messageSchedulerMaxBatch; batch++ {
+		messages, err := service.repository.FetchDueScheduled(ctx, timestamp, messageSchedulerBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch scheduled messages which are due before [%s]", timestamp)
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		for _, message := range messages {
+			if service.dispatchMessage(ctx, message) {
+				count++
+			}
+		}
+
+		if len(messages) < messageSchedulerBatchSize {
+			break
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("dispatched [%d] scheduled messages which are due before [%s]", count, timestamp))
+	}
+	return count, nil
+}
+
+func (service *MessageSchedulerService) dispatchMessage(ctx context.Context, scheduled *entities.Message) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// Another instance of the scheduler has already dispatched the message when it cannot be released
+	message, err := service.repository.ReleaseScheduled(ctx, scheduled.UserID, scheduled.ID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("scheduled message [%s] for user [%s] has already been released", scheduled.ID, scheduled.UserID))
+		return false
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot release scheduled message [%s] for user [%s]", scheduled.ID, scheduled.UserID)))
+		return false
+	}
+
+	payload := events.MessageAPISentPayload{
+		MessageID:         message.ID,
+		UserID:            message.UserID,
+		Owner:             message.Owner,
+		RequestID:         message.RequestID,
+		MaxSendAttempts:   message.MaxSendAttempts,
+		Contact:           message.Contact,
+		ScheduledSendTime: message.ScheduledSendTime,
+		ExpiresAt:         message.ExpiresAt,
+		RequestReceivedAt: message.RequestReceivedAt,
+		Content:           message.Content,
+		Encrypted:         message.Encrypted,
+		SIM:               message.SIM,
+		Channel:           message.Channel,
+		MediaURLs:         message.MediaURLs,
+		Category:          message.Category,
+		Priority:          message.Priority,
+		SenderName:        message.SenderName,
+		PhonePoolID:       message.PhonePoolID,
+		Language:          message.Language,
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageAPISent, messageSchedulerSource, payload)
+	if err == nil {
+		err = service.dispatcher.Dispatch(ctx, event)
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch [%s] event for scheduled message [%s]", events.EventTypeMessageAPISent, message.ID)))
+		message.Status = entities.MessageStatusDeferred
+		if err = service.repository.Update(ctx, message); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot move message [%s] back to the [%s] status", message.ID, entities.MessageStatusDeferred)))
+		}
+		return false
+	}
+
+	ctxLogger.Info(fmt.Sprintf("[%s] event with ID [%s] dispatched for scheduled message [%s] with send time [%s]", event.Type(), event.ID(), message.ID, message.ScheduledSendTime))
+	return true
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+)
+
+// ErrCodeNotEntitled is the code of the error which is returned when the user cannot pay for the messages
+const ErrCodeNotEntitled = stacktrace.ErrorCode(1002)
+
+// MessageSendService runs the steps which are shared by the HTTP API, the MQTT bridge and the Home Assistant integration
+// so that their messages get the same opt-out footer, phone selection and billing checks. The caller validates the
+// message between MessageSendService.Prepare and MessageSendService.Send so that a message which is rejected does not
+// take the turn of a phone pool.
+type MessageSendService struct {
+	service
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	userService    *UserService
+	billingService *BillingService
+	messageService *MessageService
+}
+
+// NewMessageSendService creates a new MessageSendService
+func NewMessageSendService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userService *UserService,
+	billingService *BillingService,
+	messageService *MessageService,
+) (s *MessageSendService) {
+	return &MessageSendService{
+		logger:         logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:         tracer,
+		userService:    userService,
+		billingService: billingService,
+		messageService: messageService,
+	}
+}
+
+// MessageDraft is a message which is prepared before it is validated. The From and Content fields are updated in place.
+type MessageDraft struct {
+	UserID         entities.UserID
+	DefaultPhoneID *uuid.UUID
+	From           *string
+	Content        *string
+	To             string
+	Encrypted      bool
+	Marketing      bool
+	OptOutFooter   *bool
+	PhonePoolID    string
+	RoutingTag     string
+}
+
+// Prepare appends the opt-out footer to the content of a message and chooses the phone which sends it when it has no
+// from number. It returns the ID of the phone pool which chose the phone and a warning when the footer increased the
+// number of segments. The phone of a phone pool is only previewed, the turn of the pool is taken by MessageSendService.Send.
+func (service *MessageSendService) Prepare(ctx context.Context, draft *MessageDraft) (*uuid.UUID, string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	warning, err := service.appendOptOutFooter(ctx, draft)
+	if err != nil {
+		msg := fmt.Sprintf("cannot append opt-out footer to message for user [%s]", draft.UserID)
+		return nil, "", service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	poolID, err := service.resolveFrom(ctx, draft)
+	if err != nil {
+		msg := fmt.Sprintf("cannot choose the phone of message to [%s] for user [%s]", draft.To, draft.UserID)
+		return nil, "", service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return poolID, warning, nil
+}
+
+// Send queues a message which was prepared and validated and takes the turn of its phone pool. It returns an error with
+// the ErrCodeNotEntitled code when the user cannot pay for the message.
+func (service *MessageSendService) Send(ctx context.Context, params MessageSendParams) (*entities.Message, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if msg := service.billingService.IsEntitled(ctx, params.UserID); msg != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotEntitled, *msg))
+	}
+
+	if params.PhonePoolID != nil {
+		if err := service.assignPoolPhone(ctx, &params); err != nil {
+			msg := fmt.Sprintf("cannot assign a phone from pool [%s] to message for user [%s]", params.PhonePoolID, params.UserID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	message, err := service.messageService.SendMessage(ctx, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot send message to [%s] for user [%s]", params.Contact, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return message, nil
+}
+
+// SendBulk queues messages which were prepared and validated concurrently. The message is nil when it cannot be sent.
+// It returns an error with the ErrCodeNotEntitled code when the user cannot pay for all the messages.
+func (service *MessageSendService) SendBulk(ctx context.Context, userID entities.UserID, params []MessageSendParams) ([]*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if msg := service.billingService.IsEntitledWithCount(ctx, userID, uint(len(params))); msg != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotEntitled, *msg))
+	}
+
+	wg := sync.WaitGroup{}
+	messages := make([]*entities.Message, len(params))
+
+	for index, message := range params {
+		wg.Add(1)
+		go func(message MessageSendParams, index int) {
+			defer wg.Done()
+			response, err := service.messageService.SendMessage(ctx, message)
+			if err != nil {
+				ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot send message [%d] to [%s] for user [%s]", index, message.Contact, userID)))
+			}
+			messages[index] = response
+		}(message, index)
+	}
+
+	wg.Wait()
+	return messages, nil
+}
+
+// appendOptOutFooter appends the opt-out footer to the content and returns a warning when the footer increases the number of SMS segments
+func (service *MessageSendService) appendOptOutFooter(ctx context.Context, draft *MessageDraft) (string, error) {
+	// the content of an encrypted message cannot be modified
+	if draft.Encrypted {
+		return "", nil
+	}
+
+	footer, err := service.userService.OptOutFooter(ctx, draft.UserID, draft.OptOutFooter, draft.Marketing)
+	if err != nil || footer == nil {
+		return "", err
+	}
+
+	segments := entities.MessageSegmentCount(*draft.Content)
+	*draft.Content = entities.AppendOptOutFooter(*draft.Content, *footer)
+	if newSegments := entities.MessageSegmentCount(*draft.Content); newSegments > segments {
+		unit := "segments"
+		if newSegments == 1 {
+			unit = "segment"
+		}
+		return fmt.Sprintf(". warning: the opt-out footer increased the message from [%d] to [%d] %s", segments, newSegments, unit), nil
+	}
+
+	return "", nil
+}
+
+// assignPoolPhone takes the turn of the phone pool of a message and sends the message with the phone which was assigned.
+// The phone which was previewed by MessageSendService.Prepare is kept when the pool was deleted in the meantime.
+func (service *MessageSendService) assignPoolPhone(ctx context.Context, params *MessageSendParams) error {
+	from, err := service.messageService.AssignPhone(ctx, params.UserID, *params.PhonePoolID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return nil
+	}
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot assign a phone from pool [%s]", params.PhonePoolID))
+	}
+
+	owner, err := phonenumbers.Parse(from, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot parse phone number [%s] of pool [%s]", from, params.PhonePoolID))
+	}
+
+	params.Owner = owner
+	return nil
+}
+
+// resolveFrom sets the from number of a message which is sent with a phone pool, a routing rule or the default phone of
+// the API key. The from number stays empty and fails the validation when the phone pool does not exist or none of the
+// routing rules matches the message and the API key has no default phone.
+func (service *MessageSendService) resolveFrom(ctx context.Context, draft *MessageDraft) (*uuid.UUID, error) {
+	if poolID, err := uuid.Parse(draft.PhonePoolID); *draft.From == "" && err == nil {
+		*draft.From, err = service.messageService.PreviewPhone(ctx, draft.UserID, poolID)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot assign a phone from pool [%s]", poolID))
+		}
+		if err == nil {
+			return &poolID, nil
+		}
+	}
+
+	if *draft.From == "" && draft.PhonePoolID == "" {
+		from, err := service.messageService.Route(ctx, draft.UserID, draft.To, draft.RoutingTag)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot route message to [%s]", draft.To))
+		}
+		*draft.From = from
+	}
+
+	if *draft.From == "" && draft.PhonePoolID == "" && draft.DefaultPhoneID != nil {
+		from, err := service.messageService.DefaultPhone(ctx, draft.UserID, *draft.DefaultPhoneID)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load the default phone [%s] of user [%s]", *draft.DefaultPhoneID, draft.UserID))
+		}
+		*draft.From = from
+	}
+
+	return nil, nil
+}
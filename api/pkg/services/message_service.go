@@ -20,14 +20,28 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 )
 
+const (
+	messageSweeperSource    = "/v1/messages/sweeper"
+	messageSweeperBatchSize = 100
+	messageSweeperMaxBatch  = 10
+	messageRetryMaxBackoff  = time.Hour
+)
+
 // MessageService is handles message requests
 type MessageService struct {
 	service
-	logger          telemetry.Logger
-	tracer          telemetry.Tracer
-	eventDispatcher *EventDispatcher
-	phoneService    *PhoneService
-	repository      repositories.MessageRepository
+	logger           telemetry.Logger
+	tracer           telemetry.Tracer
+	eventDispatcher  *EventDispatcher
+	phoneService     *PhoneService
+	repository       repositories.MessageRepository
+	threadRepository repositories.MessageThreadRepository
+	ruleRepository   repositories.RoutingRuleRepository
+	poolRepository   repositories.PhonePoolRepository
+	preferences      repositories.ContactPreferenceRepository
+	translator       Translator
+	rateLimiter      *PhoneSendRateLimiter
+	retryBackoff     time.Duration
 }
 
 // NewMessageService creates a new MessageService
@@ -35,15 +49,29 @@ func NewMessageService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	repository repositories.MessageRepository,
+	threadRepository repositories.MessageThreadRepository,
+	ruleRepository repositories.RoutingRuleRepository,
+	poolRepository repositories.PhonePoolRepository,
+	preferences repositories.ContactPreferenceRepository,
 	eventDispatcher *EventDispatcher,
 	phoneService *PhoneService,
+	translator Translator,
+	rateLimiter *PhoneSendRateLimiter,
+	retryBackoff time.Duration,
 ) (s *MessageService) {
 	return &MessageService{
-		logger:          logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:          tracer,
-		repository:      repository,
-		phoneService:    phoneService,
-		eventDispatcher: eventDispatcher,
+		logger:           logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:           tracer,
+		repository:       repository,
+		threadRepository: threadRepository,
+		ruleRepository:   ruleRepository,
+		poolRepository:   poolRepository,
+		preferences:      preferences,
+		phoneService:     phoneService,
+		eventDispatcher:  eventDispatcher,
+		translator:       translator,
+		rateLimiter:      rateLimiter,
+		retryBackoff:     retryBackoff,
 	}
 }
 
@@ -159,6 +187,43 @@ func (service *MessageService) DeleteMessage(ctx context.Context, source string,
 	return nil
 }
 
+// CancelMessage cancels an entities.Message which has not been picked up by the phone
+func (service *MessageService) CancelMessage(ctx context.Context, source string, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	timestamp := time.Now().UTC()
+	message, err := service.repository.Cancel(ctx, userID, messageID, timestamp)
+	if err != nil {
+		msg := fmt.Sprintf("cannot cancel message with ID [%s] for user with ID [%s]", messageID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageSendCancelled, source, &events.MessageSendCancelledPayload{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+		Owner:     message.Owner,
+		RequestID: message.RequestID,
+		Contact:   message.Contact,
+		Encrypted: message.Encrypted,
+		Content:   message.Content,
+		SIM:       message.SIM,
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for message with ID [%s]", events.EventTypeMessageSendCancelled, message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message with ID [%s] for user [%s] has been cancelled", message.ID, message.UserID))
+	return message, nil
+}
+
 // DeleteByOwnerAndContact deletes all the messages between an owner and a contact
 func (service *MessageService) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner, contact string) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -216,9 +281,11 @@ func (service *MessageService) RespondToMissedCall(ctx context.Context, source s
 // MessageGetParams parameters for sending a new message
 type MessageGetParams struct {
 	repositories.IndexParams
-	UserID  entities.UserID
-	Owner   string
-	Contact string
+	UserID        entities.UserID
+	Owner         string
+	Contact       string
+	IsStarred     *bool
+	NeedsFollowUp *bool
 }
 
 // GetMessages fetches sent between 2 phone numbers
@@ -228,7 +295,7 @@ func (service *MessageService) GetMessages(ctx context.Context, params MessageGe
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	messages, err := service.repository.Index(ctx, params.UserID, params.Owner, params.Contact, params.IndexParams)
+	messages, err := service.repository.Index(ctx, params.UserID, params.Owner, params.Contact, params.IsStarred, params.NeedsFollowUp, params.IndexParams)
 	if err != nil {
 		msg := fmt.Sprintf("could not fetch messages with parms [%+#v]", params)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -238,6 +305,46 @@ func (service *MessageService) GetMessages(ctx context.Context, params MessageGe
 	return messages, nil
 }
 
+// GetTimeline fetches the messages exchanged with a contact by all the phone numbers of a user
+func (service *MessageService) GetTimeline(ctx context.Context, userID entities.UserID, contact string, params repositories.IndexParams) (*[]entities.Message, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := service.tracer.CtxLogger(service.logger, span)
+
+	messages, err := service.repository.Timeline(ctx, userID, contact, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch timeline of contact [%s] for user [%s] with params [%+#v]", contact, userID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] messages in timeline of contact [%s] for user [%s]", len(*messages), contact, userID))
+	return messages, nil
+}
+
+// MessageFlagsParams are parameters for updating the flags of an entities.Message
+type MessageFlagsParams struct {
+	UserID        entities.UserID
+	MessageID     uuid.UUID
+	IsStarred     *bool
+	NeedsFollowUp *bool
+}
+
+// UpdateFlags sets the flags which are used to triage an entities.Message, the flags which are nil are not changed
+func (service *MessageService) UpdateFlags(ctx context.Context, params *MessageFlagsParams) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	message, err := service.repository.UpdateFlags(ctx, params.UserID, params.MessageID, params.IsStarred, params.NeedsFollowUp)
+	if err != nil {
+		msg := fmt.Sprintf("cannot update the flags of message with ID [%s] for user [%s]", params.MessageID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message with ID [%s] updated with is_starred [%t] and needs_follow_up [%t]", message.ID, message.IsStarred, message.NeedsFollowUp))
+	return message, nil
+}
+
 // GetMessage fetches a message by the ID
 func (service *MessageService) GetMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -275,6 +382,8 @@ func (service *MessageService) StoreEvent(ctx context.Context, message *entities
 		err = service.handleMessageDeliveredEvent(ctx, params, message)
 	case entities.MessageEventNameFailed:
 		err = service.handleMessageFailedEvent(ctx, params, message)
+	case entities.MessageEventNameRead:
+		err = service.repository.Update(ctx, message.Read(params.Timestamp))
 	default:
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(fmt.Sprintf("cannot handle message event [%s]", params.EventName)))
 	}
@@ -287,6 +396,87 @@ func (service *MessageService) StoreEvent(ctx context.Context, message *entities
 	return service.repository.Load(ctx, message.UserID, params.MessageID)
 }
 
+// MessageDeliveryReportParams are parameters for storing the delivery report which the carrier sent for a message
+type MessageDeliveryReportParams struct {
+	Status     entities.MessageDeliveryStatus
+	ErrorCode  *string
+	StatusCode *uint
+	PDU        *string
+	Timestamp  time.Time
+	Source     string
+}
+
+// StoreDeliveryReport dispatches the events.EventTypeMessageDelivered or events.EventTypeMessageDeliveryFailed event for the delivery report of a message
+func (service *MessageService) StoreDeliveryReport(ctx context.Context, message *entities.Message, params MessageDeliveryReportParams) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if params.StatusCode != nil || params.PDU != nil {
+		if err := service.repository.Update(ctx, message.DeliveryReported(params.StatusCode, params.PDU)); err != nil {
+			msg := fmt.Sprintf("cannot store the raw delivery report of message [%s]", message.ID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	var event cloudevents.Event
+	var err error
+	switch params.Status {
+	case entities.MessageDeliveryStatusDelivered:
+		event, err = service.createEvent(events.EventTypeMessageDelivered, params.Source, events.MessageDeliveredPayload{
+			ID:         message.ID,
+			Owner:      message.Owner,
+			Contact:    message.Contact,
+			RequestID:  message.RequestID,
+			UserID:     message.UserID,
+			Encrypted:  message.Encrypted,
+			Timestamp:  params.Timestamp,
+			Content:    message.Content,
+			SIM:        message.SIM,
+			StatusCode: params.StatusCode,
+			PDU:        params.PDU,
+		})
+	case entities.MessageDeliveryStatusFailed:
+		errorCode := "UNKNOWN"
+		if params.ErrorCode != nil {
+			errorCode = *params.ErrorCode
+		} else if params.StatusCode != nil {
+			errorCode = fmt.Sprintf("TP_STATUS_%02X", *params.StatusCode)
+		}
+		event, err = service.createEvent(events.EventTypeMessageDeliveryFailed, params.Source, events.MessageDeliveryFailedPayload{
+			ID:         message.ID,
+			Owner:      message.Owner,
+			Contact:    message.Contact,
+			RequestID:  message.RequestID,
+			UserID:     message.UserID,
+			Encrypted:  message.Encrypted,
+			ErrorCode:  errorCode,
+			Timestamp:  params.Timestamp,
+			Content:    message.Content,
+			SIM:        message.SIM,
+			StatusCode: params.StatusCode,
+			PDU:        params.PDU,
+		})
+	case entities.MessageDeliveryStatusPending:
+		ctxLogger.Info(fmt.Sprintf("carrier has not confirmed the delivery of message [%s] with status [%s]", message.ID, params.Status))
+		return service.repository.Load(ctx, message.UserID, message.ID)
+	default:
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(fmt.Sprintf("cannot handle delivery report with status [%s]", params.Status)))
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot create event for delivery report with status [%s] for message [%s]", params.Status, message.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event type [%s] and id [%s]", event.Type(), event.ID())
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("event [%s] dispatched for delivery report of message [%s]", event.Type(), message.ID))
+	return service.repository.Load(ctx, message.UserID, message.ID)
+}
+
 // MessageReceiveParams parameters registering a message event
 type MessageReceiveParams struct {
 	Contact   string
@@ -297,6 +487,7 @@ type MessageReceiveParams struct {
 	Timestamp time.Time
 	Encrypted bool
 	Source    string
+	MediaURLs []string
 }
 
 // ReceiveMessage handles message received by a mobile phone
@@ -315,8 +506,25 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 		Timestamp: params.Timestamp,
 		Content:   params.Content,
 		SIM:       params.SIM,
+		MediaURLs: params.MediaURLs,
+	}
+
+	if original := service.findDuplicate(ctx, eventPayload); original != nil {
+		ctxLogger.Info(fmt.Sprintf("suppressing received message with ID [%s] because it is a duplicate of message with ID [%s]", eventPayload.MessageID, original.ID))
+		return service.storeReceivedMessage(ctx, eventPayload, &original.ID)
+	}
+
+	if !params.Encrypted && entities.ContainsVCard(params.Content) {
+		eventPayload.SuggestedContacts = entities.ParseVCards(params.Content)
+		ctxLogger.Info(fmt.Sprintf("parsed [%d] suggested contacts from vCard in message with ID [%s]", len(eventPayload.SuggestedContacts), eventPayload.MessageID))
+	}
+
+	if !params.Encrypted {
+		eventPayload.Language = service.detectLanguage(ctx, eventPayload.MessageID, params.Content)
 	}
 
+	eventPayload.Muted = service.isThreadMuted(ctx, params.UserID, eventPayload.Owner, eventPayload.Contact)
+
 	ctxLogger.Info(fmt.Sprintf("creating cloud event for received with ID [%s]", eventPayload.MessageID))
 
 	event, err := service.createMessagePhoneReceivedEvent(params.Source, eventPayload)
@@ -333,7 +541,7 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 	}
 	ctxLogger.Info(fmt.Sprintf("event [%s] dispatched succesfully", event.ID()))
 
-	return service.storeReceivedMessage(ctx, eventPayload)
+	return service.storeReceivedMessage(ctx, eventPayload, nil)
 }
 
 func (service *MessageService) handleMessageSentEvent(ctx context.Context, params MessageStoreEventParams, message *entities.Message) error {
@@ -399,17 +607,22 @@ func (service *MessageService) handleMessageFailedEvent(ctx context.Context, par
 		errorMessage = *params.ErrorMessage
 	}
 
+	if message.CanBeRetried(errorMessage) {
+		return service.handleMessageFailedTransientEvent(ctx, params, message, errorMessage)
+	}
+
 	event, err := service.createMessageSendFailedEvent(params.Source, events.MessageSendFailedPayload{
-		ID:           message.ID,
-		Owner:        message.Owner,
-		ErrorMessage: errorMessage,
-		Timestamp:    params.Timestamp,
-		Encrypted:    message.Encrypted,
-		Contact:      message.Contact,
-		RequestID:    message.RequestID,
-		UserID:       message.UserID,
-		Content:      message.Content,
-		SIM:          message.SIM,
+		ID:               message.ID,
+		Owner:            message.Owner,
+		ErrorMessage:     errorMessage,
+		Timestamp:        params.Timestamp,
+		Encrypted:        message.Encrypted,
+		Contact:          message.Contact,
+		RequestID:        message.RequestID,
+		UserID:           message.UserID,
+		Content:          message.Content,
+		SIM:              message.SIM,
+		SendAttemptCount: message.SendAttemptCount,
 	})
 	if err != nil {
 		msg := fmt.Sprintf("cannot create event [%s] for message [%s]", events.EventTypeMessageSendFailed, message.ID)
@@ -423,6 +636,46 @@ func (service *MessageService) handleMessageFailedEvent(ctx context.Context, par
 	return nil
 }
 
+func (service *MessageService) handleMessageFailedTransientEvent(ctx context.Context, params MessageStoreEventParams, message *entities.Message, errorMessage string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	event, err := service.createMessageSendFailedTransientEvent(params.Source, events.MessageSendFailedTransientPayload{
+		ID:               message.ID,
+		Owner:            message.Owner,
+		ErrorMessage:     errorMessage,
+		Timestamp:        params.Timestamp,
+		Encrypted:        message.Encrypted,
+		Contact:          message.Contact,
+		RequestID:        message.RequestID,
+		UserID:           message.UserID,
+		Content:          message.Content,
+		SIM:              message.SIM,
+		SendAttemptCount: message.SendAttemptCount,
+		MaxSendAttempts:  message.MaxSendAttempts,
+		RetryAt:          time.Now().UTC().Add(service.backoff(message.SendAttemptCount)),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create event [%s] for message [%s]", events.EventTypeMessageSendFailedTransient, message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event type [%s] and id [%s]", event.Type(), event.ID())
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
+
+// backoff is the time to wait before retrying a message which failed after the send attempt. It doubles after every attempt up to messageRetryMaxBackoff
+func (service *MessageService) backoff(attempt uint) time.Duration {
+	backoff := service.retryBackoff
+	for i := uint(1); i < attempt && backoff < messageRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, messageRetryMaxBackoff)
+}
+
 // MessageSendParams parameters for sending a new message
 type MessageSendParams struct {
 	Owner             *phonenumbers.PhoneNumber
@@ -431,9 +684,171 @@ type MessageSendParams struct {
 	Content           string
 	Source            string
 	SendAt            *time.Time
+	ExpiresAt         *time.Time
 	RequestID         *string
 	UserID            entities.UserID
 	RequestReceivedAt time.Time
+	Channel           entities.MessageChannel
+	MediaURLs         []string
+	Category          entities.MessageCategory
+	Priority          entities.MessagePriority
+	SenderName        *string
+	SIM               entities.SIM
+	PhonePoolID       *uuid.UUID
+}
+
+// AssignPhone returns the phone number of the phone in a phone pool which sends the next message of the pool. The phones
+// are chosen in turn with the round robin strategy, and the phone with the fewest pending messages is chosen with the
+// least loaded strategy. It returns an error with the repositories.ErrCodeNotFound code when the pool does not exist.
+func (service *MessageService) AssignPhone(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (string, error) {
+	return service.assignPhone(ctx, userID, poolID, false)
+}
+
+// PreviewPhone returns the phone number which the phone pool would assign to the next message without advancing the
+// round robin turn of the pool
+func (service *MessageService) PreviewPhone(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (string, error) {
+	return service.assignPhone(ctx, userID, poolID, true)
+}
+
+func (service *MessageService) assignPhone(ctx context.Context, userID entities.UserID, poolID uuid.UUID, preview bool) (string, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool, err := service.poolRepository.Load(ctx, userID, poolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone pool with ID [%s] for user [%s]", poolID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if len(pool.PhoneNumbers) == 0 {
+		msg := fmt.Sprintf("phone pool with ID [%s] for user [%s] has no phones", poolID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	phoneNumbers := service.activePhoneNumbers(ctx, userID, pool.PhoneNumbers)
+	if len(phoneNumbers) == 0 {
+		msg := fmt.Sprintf("all the [%d] phones of phone pool with ID [%s] for user [%s] are deactivated", len(pool.PhoneNumbers), poolID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	if pool.Strategy == entities.PhonePoolStrategyLeastLoaded {
+		return service.assignLeastLoadedPhone(ctx, pool, phoneNumbers)
+	}
+
+	assignments := pool.Assignments
+	if !preview {
+		assignments, err = service.poolRepository.Advance(ctx, userID, poolID)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot advance the assignments of phone pool with ID [%s] for user [%s]", poolID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	owner := phoneNumbers[assignments%uint64(len(phoneNumbers))]
+	ctxLogger.Info(fmt.Sprintf("phone pool [%s] assigned phone [%s] in turn [%d] for user [%s]", pool.ID, owner, assignments, userID))
+	return owner, nil
+}
+
+// activePhoneNumbers removes the phone numbers of the phones which are deactivated. A phone which cannot be loaded is kept
+// so that a pool is not left without phones when the database is unavailable.
+func (service *MessageService) activePhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) []string {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	active := make([]string, 0, len(phoneNumbers))
+	for _, phoneNumber := range phoneNumbers {
+		phone, err := service.phoneService.Load(ctx, userID, phoneNumber)
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone [%s] for user [%s] to check if it is deactivated", phoneNumber, userID)))
+		}
+		if err == nil && phone.IsDeactivated() {
+			continue
+		}
+		active = append(active, phoneNumber)
+	}
+
+	return active
+}
+
+func (service *MessageService) assignLeastLoadedPhone(ctx context.Context, pool *entities.PhonePool, phoneNumbers []string) (string, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	depths, err := service.repository.CountOutstandingForOwners(ctx, pool.UserID, phoneNumbers)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the outstanding messages of the phones in pool [%s] for user [%s]", pool.ID, pool.UserID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	counts := make(map[string]int64, len(depths))
+	for _, depth := range depths {
+		counts[depth.Owner] = depth.Count
+	}
+
+	// the phones which have the same number of outstanding messages are chosen in the order of the pool
+	owner := phoneNumbers[0]
+	for _, phoneNumber := range phoneNumbers[1:] {
+		if counts[phoneNumber] < counts[owner] {
+			owner = phoneNumber
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone pool [%s] assigned phone [%s] with [%d] outstanding messages for user [%s]", pool.ID, owner, counts[owner], pool.UserID))
+	return owner, nil
+}
+
+// Route returns the phone number of the first routing rule of a user which matches the contact and the routing tag of an
+// outgoing message. It returns an error with the repositories.ErrCodeNotFound code when no rule matches.
+func (service *MessageService) Route(ctx context.Context, userID entities.UserID, contact string, tag string) (string, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.ruleRepository.LoadAll(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load routing rules for user with ID [%s]", userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	country := ""
+	if number, err := phonenumbers.Parse(contact, phonenumbers.UNKNOWN_REGION); err == nil {
+		country = phonenumbers.GetRegionCodeForNumber(number)
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(contact, country, tag) {
+			continue
+		}
+		if len(service.activePhoneNumbers(ctx, userID, []string{rule.PhoneNumber})) == 0 {
+			ctxLogger.Info(fmt.Sprintf("skipping routing rule [%s] because phone [%s] is deactivated for user [%s]", rule.ID, rule.PhoneNumber, userID))
+			continue
+		}
+		ctxLogger.Info(fmt.Sprintf("routing rule [%s] routes message to [%s] with tag [%s] through phone [%s] for user [%s]", rule.ID, contact, tag, rule.PhoneNumber, userID))
+		return rule.PhoneNumber, nil
+	}
+
+	msg := fmt.Sprintf("none of the [%d] routing rules of user [%s] matches contact [%s] in country [%s] with tag [%s]", len(rules), userID, contact, country, tag)
+	return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+}
+
+// DefaultPhone returns the phone number of the default phone of an API key which sends the messages without a from
+// number. It returns an error with the repositories.ErrCodeNotFound code when the phone does not exist or it is deactivated.
+func (service *MessageService) DefaultPhone(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (string, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneService.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load default phone [%s] of user [%s]", phoneID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if phone.IsDeactivated() {
+		msg := fmt.Sprintf("default phone [%s] of user [%s] is deactivated", phone.ID, userID)
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(repositories.ErrCodeNotFound, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message without a from number is sent by default phone [%s] of user [%s]", phone.PhoneNumber, userID))
+	return phone.PhoneNumber, nil
 }
 
 // SendMessage a new message
@@ -443,8 +858,12 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	sendAttempts, sim := service.phoneSettings(ctx, params.UserID, phonenumbers.Format(params.Owner, phonenumbers.E164))
+	sendAttempts, sim, phone := service.phoneSettings(ctx, params.UserID, phonenumbers.Format(params.Owner, phonenumbers.E164))
+	if params.SIM != "" {
+		sim = params.SIM
+	}
 
+	language := service.applyContactPreference(ctx, &params)
 	eventPayload := events.MessageAPISentPayload{
 		MessageID:         uuid.New(),
 		UserID:            params.UserID,
@@ -455,10 +874,17 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 		Contact:           params.Contact,
 		RequestReceivedAt: params.RequestReceivedAt,
 		Content:           params.Content,
-		ScheduledSendTime: params.SendAt,
+		ScheduledSendTime: service.rateLimit(ctx, ctxLogger, phone, params),
+		ExpiresAt:         params.ExpiresAt,
 		SIM:               sim,
+		Channel:           entities.MessageChannelSanitized(params.Channel),
+		MediaURLs:         params.MediaURLs,
+		Category:          entities.MessageCategorySanitized(params.Category),
+		Priority:          entities.MessagePrioritySanitized(params.Priority),
+		SenderName:        params.SenderName,
+		PhonePoolID:       params.PhonePoolID,
+		Language:          language,
 	}
-
 	event, err := service.createMessageAPISentEvent(params.Source, eventPayload)
 	if err != nil {
 		msg := fmt.Sprintf("cannot create %T from payload with message id [%s]", event, eventPayload.MessageID)
@@ -472,16 +898,100 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	timeout := service.getSendDelay(ctxLogger, eventPayload, params.SendAt)
-	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+	if message.IsDeferred() {
+		ctxLogger.Info(fmt.Sprintf("message [%s] for user [%s] is held by the scheduler until [%s]", message.ID, message.UserID, message.ScheduledSendTime))
+		return message, nil
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
 		msg := fmt.Sprintf("cannot dispatch event type [%s] and id [%s]", event.Type(), event.ID())
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	ctxLogger.Info(fmt.Sprintf("[%s] event with ID [%s] dispatched succesfully for message [%s] with user [%s] and delay [%s]", event.Type(), event.ID(), eventPayload.MessageID, eventPayload.UserID, timeout))
+	ctxLogger.Info(fmt.Sprintf("[%s] event with ID [%s] dispatched succesfully for message [%s] with user [%s]", event.Type(), event.ID(), eventPayload.MessageID, eventPayload.UserID))
 	return message, err
 }
 
+// Simulate computes what happens when a message is sent e.g. the phone, the segments and the estimated dispatch time
+// without storing the message or taking a token from the send rate limits of the phone
+func (service *MessageService) Simulate(ctx context.Context, params MessageSendParams) (*entities.MessageSimulation, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	owner := phonenumbers.Format(params.Owner, phonenumbers.E164)
+	phone, err := service.phoneService.Load(ctx, params.UserID, owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with owner [%s] for user [%s]", owner, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	depths, err := service.repository.CountOutstandingForOwners(ctx, params.UserID, []string{owner})
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the outstanding messages of phone [%s] for user [%s]", phone.ID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	simulation := &entities.MessageSimulation{
+		PhoneID:             phone.ID,
+		PhonePoolID:         params.PhonePoolID,
+		Owner:               owner,
+		Contact:             params.Contact,
+		Content:             params.Content,
+		SIM:                 phone.SIM,
+		Channel:             entities.MessageChannelSanitized(params.Channel),
+		BillableMessages:    1,
+		IsEntitled:          true,
+		Status:              entities.MessageStatusPending,
+		EstimatedDispatchAt: time.Now().UTC(),
+	}
+	if params.SIM != "" {
+		simulation.SIM = params.SIM
+	}
+	for _, depth := range depths {
+		simulation.QueueDepth += depth.Count
+	}
+
+	message := (&entities.Message{Content: params.Content, Encrypted: params.Encrypted, Channel: simulation.Channel}).CountSegments()
+	simulation.Encoding = message.Encoding
+	simulation.SegmentCount = message.SegmentCount
+
+	if params.SendAt != nil && params.SendAt.After(simulation.EstimatedDispatchAt) {
+		simulation.Status = entities.MessageStatusDeferred
+		simulation.EstimatedDispatchAt = *params.SendAt
+	}
+
+	category := entities.MessageCategorySanitized(params.Category)
+	if preference := service.contactPreference(ctx, params.UserID, params.Contact); preference != nil && category != entities.MessageCategoryOTP {
+		if sendAt := preference.NextSendTime(simulation.EstimatedDispatchAt); sendAt.After(simulation.EstimatedDispatchAt) {
+			simulation.Status = entities.MessageStatusDeferred
+			simulation.EstimatedDispatchAt = sendAt
+		}
+	}
+
+	sendAt, err := service.rateLimiter.Peek(ctx, phone.ID, phone.MessagesPerHour, simulation.EstimatedDispatchAt)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot peek at the hourly limit of phone [%s] for user [%s]", phone.ID, params.UserID)))
+	}
+
+	if category != entities.MessageCategoryOTP && sendAt.After(simulation.EstimatedDispatchAt) {
+		simulation.Status = entities.MessageStatusDeferred
+		simulation.IsThrottled = true
+		simulation.EstimatedDispatchAt = sendAt
+	}
+
+	// the notifications of a phone are spaced out by its messages per minute so the queued messages are pushed first
+	if messagesPerMinute := category.MessagesPerMinute(phone.MessagesPerMinute); messagesPerMinute > 0 && simulation.QueueDepth > 0 {
+		queuedAt := time.Now().UTC().Add(time.Duration(simulation.QueueDepth) * (time.Minute / time.Duration(messagesPerMinute)))
+		if queuedAt.After(simulation.EstimatedDispatchAt) {
+			simulation.IsThrottled = true
+			simulation.EstimatedDispatchAt = queuedAt
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("simulated message from [%s] to [%s] for user [%s] with estimated dispatch at [%s]", owner, params.Contact, params.UserID, simulation.EstimatedDispatchAt))
+	return simulation, nil
+}
+
 // MissedCallParams parameters for sending a new message
 type MissedCallParams struct {
 	Owner     *phonenumbers.PhoneNumber
@@ -531,22 +1041,8 @@ func (service *MessageService) RegisterMissedCall(ctx context.Context, params *M
 	return message, err
 }
 
-func (service *MessageService) getSendDelay(ctxLogger telemetry.Logger, eventPayload events.MessageAPISentPayload, sendAt *time.Time) time.Duration {
-	if sendAt == nil {
-		return time.Duration(0)
-	}
-
-	delay := sendAt.Sub(time.Now().UTC())
-	if delay < 0 {
-		ctxLogger.Info(fmt.Sprintf("message [%s] has send time [%s] in the past. sending immediately", eventPayload.MessageID, sendAt.String()))
-		return time.Duration(0)
-	}
-
-	return delay
-}
-
 // StoreReceivedMessage a new message
-func (service *MessageService) storeReceivedMessage(ctx context.Context, params events.MessagePhoneReceivedPayload) (*entities.Message, error) {
+func (service *MessageService) storeReceivedMessage(ctx context.Context, params events.MessagePhoneReceivedPayload, duplicateOfID *uuid.UUID) (*entities.Message, error) {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
 
@@ -559,6 +1055,7 @@ func (service *MessageService) storeReceivedMessage(ctx context.Context, params
 		Contact:           params.Contact,
 		Content:           params.Content,
 		SIM:               params.SIM,
+		Channel:           entities.MessageChannelSMS,
 		Encrypted:         params.Encrypted,
 		Type:              entities.MessageTypeMobileOriginated,
 		Status:            entities.MessageStatusReceived,
@@ -567,6 +1064,13 @@ func (service *MessageService) storeReceivedMessage(ctx context.Context, params
 		UpdatedAt:         time.Now().UTC(),
 		OrderTimestamp:    params.Timestamp,
 		ReceivedAt:        &params.Timestamp,
+		Language:          params.Language,
+		DuplicateOfID:     duplicateOfID,
+	}
+
+	if len(params.MediaURLs) > 0 {
+		message.Channel = entities.MessageChannelMMS
+		message.MediaURLs = params.MediaURLs
 	}
 
 	if err := service.repository.Store(ctx, message); err != nil {
@@ -578,6 +1082,79 @@ func (service *MessageService) storeReceivedMessage(ctx context.Context, params
 	return message, nil
 }
 
+// findDuplicate returns the message which was received from the same contact within the duplicate window of the phone and matches the received message or nil when there is none
+func (service *MessageService) findDuplicate(ctx context.Context, params events.MessagePhoneReceivedPayload) *entities.Message {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneService.Load(ctx, params.UserID, params.Owner)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone with owner [%s] for user [%s] to check for duplicate messages", params.Owner, params.UserID)))
+		return nil
+	}
+
+	if phone.DuplicateWindowSeconds == 0 {
+		return nil
+	}
+
+	messages, err := service.repository.FetchReceivedSince(ctx, params.UserID, params.Owner, params.Contact, params.Timestamp.Add(-phone.DuplicateWindowDuration()), 100)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch messages received by [%s] from [%s] to check for duplicate of message with ID [%s]", params.Owner, params.Contact, params.MessageID)))
+		return nil
+	}
+
+	for _, message := range messages {
+		if message.Encrypted == params.Encrypted && phone.DuplicateStrategy.Matches(params.Content, message.Content) {
+			return message
+		}
+	}
+	return nil
+}
+
+// detectLanguage returns the language of a received message or nil when there is no translator or the language cannot be detected
+func (service *MessageService) detectLanguage(ctx context.Context, messageID uuid.UUID, content string) *string {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.translator == nil || strings.TrimSpace(content) == "" {
+		return nil
+	}
+
+	language, err := service.translator.Detect(ctx, content)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot detect the language of message with ID [%s]", messageID)))
+		return nil
+	}
+
+	if language == "" {
+		return nil
+	}
+
+	ctxLogger.Info(fmt.Sprintf("detected language [%s] for message with ID [%s]", language, messageID))
+	return &language
+}
+
+// isThreadMuted checks if the entities.MessageThread between the owner and the contact is muted
+func (service *MessageService) isThreadMuted(ctx context.Context, userID entities.UserID, owner string, contact string) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.threadRepository.LoadByOwnerContact(ctx, userID, owner, contact)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return false
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load thread with owner [%s] and contact [%s] for user [%s]", owner, contact, userID)))
+		return false
+	}
+
+	if thread.IsMuted {
+		ctxLogger.Info(fmt.Sprintf("thread with ID [%s] is muted for user [%s]", thread.ID, userID))
+	}
+	return thread.IsMuted
+}
+
 // HandleMessageParams are parameters for handling a message event
 type HandleMessageParams struct {
 	ID        uuid.UUID
@@ -680,6 +1257,65 @@ func (service *MessageService) HandleMessageFailed(ctx context.Context, params H
 	return nil
 }
 
+// HandleMessageFailedTransientParams are parameters for handling a message which failed because of a temporary error
+type HandleMessageFailedTransientParams struct {
+	ID           uuid.UUID
+	Source       string
+	UserID       entities.UserID
+	ErrorMessage string
+	Timestamp    time.Time
+	RetryAt      time.Time
+}
+
+// HandleMessageFailedTransient moves a message which could not be sent because of a temporary error back to the pending status and retries it after a backoff
+func (service *MessageService) HandleMessageFailedTransient(ctx context.Context, params HandleMessageFailedTransientParams) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := service.tracer.CtxLogger(service.logger, span)
+
+	message, err := service.repository.Load(ctx, params.UserID, params.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", params.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if message.IsDelivered() {
+		msg := fmt.Sprintf("message has already been delivered with status [%s]", message.Status)
+		return service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err = service.repository.Update(ctx, message.FailedTransiently(params.Timestamp, params.ErrorMessage)); err != nil {
+		msg := fmt.Sprintf("cannot update message with id [%s] as failed transiently", message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event, err := service.createMessageSendRetryEvent(params.Source, &events.MessageSendRetryPayload{
+		MessageID: message.ID,
+		Timestamp: params.RetryAt,
+		Contact:   message.Contact,
+		Owner:     message.Owner,
+		Encrypted: message.Encrypted,
+		UserID:    message.UserID,
+		Content:   message.Content,
+		SIM:       message.SIM,
+		Category:  message.Category,
+		Priority:  message.Priority,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for failed message with ID [%s]", events.EventTypeMessageSendRetry, message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, time.Until(params.RetryAt)); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for message with ID [%s]", event.Type(), message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message with ID [%s] failed with [%s] on attempt [%d/%d] and will be retried at [%s]", message.ID, params.ErrorMessage, message.SendAttemptCount, message.MaxSendAttempts, params.RetryAt))
+	return nil
+}
+
 // HandleMessageDelivered handles when a message is has been delivered by a mobile phone
 func (service *MessageService) HandleMessageDelivered(ctx context.Context, params HandleMessageParams) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -708,6 +1344,34 @@ func (service *MessageService) HandleMessageDelivered(ctx context.Context, param
 	return nil
 }
 
+// HandleMessageDeliveryFailed handles when the carrier could not deliver a message which was sent by a mobile phone
+func (service *MessageService) HandleMessageDeliveryFailed(ctx context.Context, params HandleMessageFailedParams) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := service.tracer.CtxLogger(service.logger, span)
+
+	message, err := service.repository.Load(ctx, params.UserID, params.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find message with id [%s]", params.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if !message.IsSent() && !message.IsSending() {
+		msg := fmt.Sprintf("message has wrong status [%s]. expected [%s, %s]", message.Status, entities.MessageStatusSent, entities.MessageStatusSending)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return nil
+	}
+
+	if err = service.repository.Update(ctx, message.Failed(params.Timestamp, params.ErrorMessage)); err != nil {
+		msg := fmt.Sprintf("cannot update message with id [%s] as failed delivery", message.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message with id [%s] has been updated to status [%s]", message.ID, message.Status))
+	return nil
+}
+
 // HandleMessageNotificationScheduled handles the event when the notification of a message has been scheduled
 func (service *MessageService) HandleMessageNotificationScheduled(ctx context.Context, params HandleMessageParams) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -769,8 +1433,14 @@ func (service *MessageService) HandleMessageExpired(ctx context.Context, params
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	if !message.IsSending() && !message.IsScheduled() && !message.IsPending() {
-		msg := fmt.Sprintf("message has wrong status [%s]. expected [%s, %s, %s]", message.Status, entities.MessageStatusSending, entities.MessageStatusScheduled, entities.MessageStatusPending)
+	// messages which are past their expiry time are moved to the expired status by the sweeper before the event is dispatched
+	if message.IsExpired() && message.IsPastExpiry(params.Timestamp) {
+		ctxLogger.Info(fmt.Sprintf("message with id [%s] has already expired at [%s]", message.ID, message.ExpiresAt))
+		return nil
+	}
+
+	if !message.IsSending() && !message.IsScheduled() && !message.IsPending() && !message.IsDeferred() {
+		msg := fmt.Sprintf("message has wrong status [%s]. expected [%s, %s, %s, %s]", message.Status, entities.MessageStatusSending, entities.MessageStatusScheduled, entities.MessageStatusPending, entities.MessageStatusDeferred)
 		return service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
 	}
 
@@ -794,6 +1464,8 @@ func (service *MessageService) HandleMessageExpired(ctx context.Context, params
 		UserID:    message.UserID,
 		Content:   message.Content,
 		SIM:       message.SIM,
+		Category:  message.Category,
+		Priority:  message.Priority,
 	})
 	if err != nil {
 		msg := fmt.Sprintf("cannot create [%s] event for expired message with ID [%s]", events.EventTypeMessageSendRetry, message.ID)
@@ -875,7 +1547,7 @@ func (service *MessageService) CheckExpired(ctx context.Context, params MessageC
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	if !message.IsPending() && !message.IsSending() && !message.IsScheduled() {
+	if !message.IsPending() && !message.IsSending() && !message.IsScheduled() && !message.IsDeferred() {
 		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has status [%s] and is not expired", message.ID, message.Status))
 		return nil
 	}
@@ -907,13 +1579,205 @@ func (service *MessageService) CheckExpired(ctx context.Context, params MessageC
 	return nil
 }
 
+// SweepExpired expires the messages which are past their expiry time at every interval until the context is cancelled
+func (service *MessageService) SweepExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.ExpireDue(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot expire messages which are past their expiry time"))
+			}
+		}
+	}
+}
+
+// ExpireDue moves the outgoing entities.Message which have not been sent before the timestamp to the expired status
+func (service *MessageService) ExpireDue(ctx context.Context, timestamp time.Time) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	count := 0
+	for batch := 0; batch < messageSweeperMaxBatch; batch++ {
+		messages, err := service.repository.FetchPastExpiry(ctx, timestamp, messageSweeperBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch messages which are past their expiry time at [%s]", timestamp)
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		for _, message := range messages {
+			if service.expireMessage(ctx, message, timestamp) {
+				count++
+			}
+		}
+
+		if len(messages) < messageSweeperBatchSize {
+			break
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("expired [%d] messages which are past their expiry time at [%s]", count, timestamp))
+	}
+	return count, nil
+}
+
+func (service *MessageService) expireMessage(ctx context.Context, outstanding *entities.Message, timestamp time.Time) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// The message has been sent by the phone or expired by another instance of the sweeper when it cannot be expired
+	message, err := service.repository.Expire(ctx, outstanding.UserID, outstanding.ID, timestamp)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message [%s] for user [%s] is no longer waiting to be sent", outstanding.ID, outstanding.UserID))
+		return false
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot expire message [%s] for user [%s]", outstanding.ID, outstanding.UserID)))
+		return false
+	}
+
+	event, err := service.createMessageSendExpiredEvent(messageSweeperSource, events.MessageSendExpiredPayload{
+		MessageID:        message.ID,
+		Owner:            message.Owner,
+		Contact:          message.Contact,
+		Encrypted:        message.Encrypted,
+		RequestID:        message.RequestID,
+		IsFinal:          true,
+		SendAttemptCount: message.SendAttemptCount,
+		UserID:           message.UserID,
+		Timestamp:        timestamp,
+		Content:          message.Content,
+		SIM:              message.SIM,
+	})
+	if err == nil {
+		err = service.eventDispatcher.Dispatch(ctx, event)
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch [%s] event for message [%s]", events.EventTypeMessageSendExpired, message.ID)))
+		return false
+	}
+
+	ctxLogger.Info(fmt.Sprintf("[%s] event with ID [%s] dispatched for message [%s] with expiry time [%s]", event.Type(), event.ID(), message.ID, message.ExpiresAt))
+	return true
+}
+
+// MessageFailoverParams are parameters for moving the pending messages of an offline phone to its backup phone
+type MessageFailoverParams struct {
+	Source    string
+	UserID    entities.UserID
+	Owner     string
+	Timestamp time.Time
+}
+
+// Failover moves the pending entities.Message of a phone which is offline to the backup phone of the user so they are not
+// stuck until the phone comes back online. The messages which have already been picked up by the phone are not moved.
+func (service *MessageService) Failover(ctx context.Context, params MessageFailoverParams) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneService.Load(ctx, params.UserID, params.Owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with owner [%s] for user [%s]", params.Owner, params.UserID)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if phone.BackupPhoneNumber == nil {
+		ctxLogger.Info(fmt.Sprintf("phone [%s] of user [%s] does not have a backup phone", phone.ID, phone.UserID))
+		return 0, nil
+	}
+
+	backup, err := service.phoneService.Load(ctx, params.UserID, *phone.BackupPhoneNumber)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("backup phone [%s] of phone [%s] does not exist for user [%s]", *phone.BackupPhoneNumber, phone.ID, phone.UserID)))
+		return 0, nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load backup phone [%s] of phone [%s] for user [%s]", *phone.BackupPhoneNumber, phone.ID, phone.UserID)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if backup.IsDeactivated() {
+		ctxLogger.Info(fmt.Sprintf("backup phone [%s] of phone [%s] is deactivated for user [%s]", backup.ID, phone.ID, phone.UserID))
+		return 0, nil
+	}
+
+	count := 0
+	for batch := 0; batch < messageSweeperMaxBatch; batch++ {
+		messages, err := service.repository.Queue(ctx, params.UserID, phone.PhoneNumber, messageSweeperBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch the queued messages of phone [%s] for user [%s]", phone.ID, phone.UserID)
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		moved := 0
+		for _, message := range messages {
+			if message.IsPending() && service.failoverMessage(ctx, params.Source, message, backup, params.Timestamp) {
+				moved++
+			}
+		}
+
+		count += moved
+		if moved == 0 || len(messages) < messageSweeperBatchSize {
+			break
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("moved [%d] pending messages of offline phone [%s] to backup phone [%s] for user [%s]", count, phone.ID, backup.ID, phone.UserID))
+	return count, nil
+}
+
+func (service *MessageService) failoverMessage(ctx context.Context, source string, pending *entities.Message, backup *entities.Phone, timestamp time.Time) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// The message has been picked up by the phone or cancelled at the same time when it cannot be moved
+	message, err := service.repository.FailOver(ctx, pending.UserID, pending.ID, pending.Owner, backup, timestamp)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message [%s] for user [%s] is no longer pending on phone [%s]", pending.ID, pending.UserID, pending.Owner))
+		return false
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot move message [%s] for user [%s] to backup phone [%s]", pending.ID, pending.UserID, backup.ID)))
+		return false
+	}
+
+	event, err := service.createMessageSendRetryEvent(source, &events.MessageSendRetryPayload{
+		MessageID: message.ID,
+		Timestamp: timestamp,
+		Contact:   message.Contact,
+		Owner:     message.Owner,
+		Encrypted: message.Encrypted,
+		UserID:    message.UserID,
+		Content:   message.Content,
+		SIM:       message.SIM,
+		Category:  message.Category,
+		Priority:  message.Priority,
+	})
+	if err == nil {
+		err = service.eventDispatcher.Dispatch(ctx, event)
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch [%s] event for message [%s]", events.EventTypeMessageSendRetry, message.ID)))
+		return false
+	}
+	return true
+}
+
 // MessageSearchParams are parameters for searching messages
 type MessageSearchParams struct {
 	repositories.IndexParams
-	UserID   entities.UserID
-	Owners   []string
-	Types    []entities.MessageType
-	Statuses []entities.MessageStatus
+	UserID      entities.UserID
+	Owners      []string
+	Types       []entities.MessageType
+	Statuses    []entities.MessageStatus
+	SenderNames []string
 }
 
 // SearchMessages fetches all the messages for a user
@@ -923,7 +1787,7 @@ func (service *MessageService) SearchMessages(ctx context.Context, params *Messa
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	messages, err := service.repository.Search(ctx, params.UserID, params.Owners, params.Types, params.Statuses, params.IndexParams)
+	messages, err := service.repository.Search(ctx, params.UserID, params.Owners, params.Types, params.Statuses, params.SenderNames, params.IndexParams)
 	if err != nil {
 		msg := fmt.Sprintf("could not search messages with parms [%+#v]", params)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -933,7 +1797,7 @@ func (service *MessageService) SearchMessages(ctx context.Context, params *Messa
 	return messages, nil
 }
 
-func (service *MessageService) phoneSettings(ctx context.Context, userID entities.UserID, owner string) (uint, entities.SIM) {
+func (service *MessageService) phoneSettings(ctx context.Context, userID entities.UserID, owner string) (uint, entities.SIM, *entities.Phone) {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
 
@@ -943,10 +1807,91 @@ func (service *MessageService) phoneSettings(ctx context.Context, userID entitie
 	if err != nil {
 		msg := fmt.Sprintf("cannot load phone for userID [%s] and owner [%s]. using default max send attempt of 2", userID, owner)
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
-		return 2, entities.SIM1
+		return 2, entities.SIM1, nil
+	}
+
+	return phone.MaxSendAttemptsSanitized(), phone.SIM, phone
+}
+
+// rateLimit returns the send time of a message which is delayed until the phone has a token for it when the phone has
+// already sent its hourly limit of messages. OTP messages take a token but they are never delayed.
+func (service *MessageService) rateLimit(ctx context.Context, ctxLogger telemetry.Logger, phone *entities.Phone, params MessageSendParams) *time.Time {
+	if phone == nil || phone.MessagesPerHour == 0 {
+		return params.SendAt
 	}
 
-	return phone.MaxSendAttemptsSanitized(), phone.SIM
+	timestamp := time.Now().UTC()
+	if params.SendAt != nil && params.SendAt.After(timestamp) {
+		timestamp = *params.SendAt
+	}
+
+	sendAt, err := service.rateLimiter.Reserve(ctx, phone.ID, phone.MessagesPerHour, timestamp)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot reserve a token of phone [%s] for user [%s], the message is not delayed", phone.ID, phone.UserID)))
+		return params.SendAt
+	}
+
+	if !sendAt.After(timestamp) || entities.MessageCategorySanitized(params.Category) == entities.MessageCategoryOTP {
+		return params.SendAt
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone [%s] of user [%s] reached its limit of [%d] messages per hour, the message is delayed until [%s]", phone.PhoneNumber, phone.UserID, phone.MessagesPerHour, sendAt))
+	return &sendAt
+}
+
+// contactPreference loads the entities.ContactPreference of a contact or nil when the contact has no preferences
+func (service *MessageService) contactPreference(ctx context.Context, userID entities.UserID, contact string) *entities.ContactPreference {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	preference, err := service.preferences.Load(ctx, userID, contact)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return nil
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load preferences of contact [%s] for user [%s]", contact, userID)))
+		return nil
+	}
+	return preference
+}
+
+// applyContactPreference moves the send time of a message into the send window of the contact and translates the
+// content into the language of the contact. It returns the language of the content when it was translated.
+func (service *MessageService) applyContactPreference(ctx context.Context, params *MessageSendParams) *string {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	preference := service.contactPreference(ctx, params.UserID, params.Contact)
+	if preference == nil || entities.MessageCategorySanitized(params.Category) == entities.MessageCategoryOTP {
+		return nil
+	}
+
+	timestamp := time.Now().UTC()
+	if params.SendAt != nil && params.SendAt.After(timestamp) {
+		timestamp = *params.SendAt
+	}
+
+	if sendAt := preference.NextSendTime(timestamp); sendAt.After(timestamp) {
+		ctxLogger.Info(fmt.Sprintf("message to [%s] for user [%s] is outside the send window of the contact, it is delayed until [%s]", params.Contact, params.UserID, sendAt))
+		params.SendAt = &sendAt
+	}
+
+	if preference.Language == nil || service.translator == nil || params.Encrypted || strings.TrimSpace(params.Content) == "" {
+		return nil
+	}
+
+	translation, err := service.translator.Translate(ctx, params.Content, *preference.Language)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot translate message to [%s] into [%s] for user [%s]", params.Contact, *preference.Language, params.UserID)))
+		return nil
+	}
+
+	if !isSameLanguage(translation.SourceLanguage, *preference.Language) {
+		ctxLogger.Info(fmt.Sprintf("message to [%s] for user [%s] translated from [%s] to [%s]", params.Contact, params.UserID, translation.SourceLanguage, *preference.Language))
+		params.Content = translation.Text
+	}
+	return preference.Language
 }
 
 // storeSentMessage a new message
@@ -961,6 +1906,12 @@ func (service *MessageService) storeSentMessage(ctx context.Context, payload eve
 		timestamp = *payload.ScheduledSendTime
 	}
 
+	// messages with a send time in the future are held in the deferred status until the MessageSchedulerService dispatches them
+	status := entities.MessageStatus(entities.MessageStatusPending)
+	if payload.ScheduledSendTime != nil && payload.ScheduledSendTime.After(time.Now().UTC()) {
+		status = entities.MessageStatusDeferred
+	}
+
 	message := &entities.Message{
 		ID:                payload.MessageID,
 		Owner:             payload.Owner,
@@ -969,10 +1920,18 @@ func (service *MessageService) storeSentMessage(ctx context.Context, payload eve
 		Content:           payload.Content,
 		RequestID:         payload.RequestID,
 		SIM:               payload.SIM,
+		Channel:           entities.MessageChannelSanitized(payload.Channel),
+		MediaURLs:         payload.MediaURLs,
+		Category:          entities.MessageCategorySanitized(payload.Category),
+		Priority:          entities.MessagePrioritySanitized(payload.Priority),
+		SenderName:        payload.SenderName,
+		PhonePoolID:       payload.PhonePoolID,
+		Language:          payload.Language,
 		Encrypted:         payload.Encrypted,
 		ScheduledSendTime: payload.ScheduledSendTime,
+		ExpiresAt:         payload.ExpiresAt,
 		Type:              entities.MessageTypeMobileTerminated,
-		Status:            entities.MessageStatusPending,
+		Status:            status,
 		RequestReceivedAt: payload.RequestReceivedAt,
 		CreatedAt:         time.Now().UTC(),
 		UpdatedAt:         time.Now().UTC(),
@@ -980,7 +1939,7 @@ func (service *MessageService) storeSentMessage(ctx context.Context, payload eve
 		OrderTimestamp:    timestamp,
 	}
 
-	if err := service.repository.Store(ctx, message); err != nil {
+	if err := service.repository.Store(ctx, message.CountSegments()); err != nil {
 		msg := fmt.Sprintf("cannot save message with id [%s]", payload.MessageID)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -1002,6 +1961,7 @@ func (service *MessageService) storeMissedCallMessage(ctx context.Context, paylo
 		Contact:           payload.Contact,
 		UserID:            payload.UserID,
 		SIM:               payload.SIM,
+		Channel:           entities.MessageChannelSMS,
 		Type:              entities.MessageTypeCallMissed,
 		Status:            entities.MessageStatusReceived,
 		RequestReceivedAt: payload.Timestamp,
@@ -1047,6 +2007,10 @@ func (service *MessageService) createMessageSendFailedEvent(source string, paylo
 	return service.createEvent(events.EventTypeMessageSendFailed, source, payload)
 }
 
+func (service *MessageService) createMessageSendFailedTransientEvent(source string, payload events.MessageSendFailedTransientPayload) (cloudevents.Event, error) {
+	return service.createEvent(events.EventTypeMessageSendFailedTransient, source, payload)
+}
+
 func (service *MessageService) createMessagePhoneDeliveredEvent(source string, payload events.MessagePhoneDeliveredPayload) (cloudevents.Event, error) {
 	return service.createEvent(events.EventTypeMessagePhoneDelivered, source, payload)
 }
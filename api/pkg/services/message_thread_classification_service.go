@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	messageThreadClassificationMessageLimit = 20
+	messageThreadClassificationInterval     = 10 * time.Minute
+)
+
+// MessageThreadClassificationService sets the topic and language of message threads with a MessageThreadClassifier
+type MessageThreadClassificationService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	classifier        MessageThreadClassifier
+	repository        repositories.MessageThreadRepository
+	messageRepository repositories.MessageRepository
+}
+
+// NewMessageThreadClassificationService creates a new MessageThreadClassificationService
+func NewMessageThreadClassificationService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	classifier MessageThreadClassifier,
+	repository repositories.MessageThreadRepository,
+	messageRepository repositories.MessageRepository,
+) (s *MessageThreadClassificationService) {
+	return &MessageThreadClassificationService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		classifier:        classifier,
+		repository:        repository,
+		messageRepository: messageRepository,
+	}
+}
+
+// Classify sets the topic and language of the thread between the owner and the contact from its recent messages.
+// A thread is classified at most once every messageThreadClassificationInterval so that a burst of messages does not
+// call the classifier for every message.
+func (service *MessageThreadClassificationService) Classify(ctx context.Context, userID entities.UserID, owner string, contact string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.LoadByOwnerContact(ctx, userID, owner, contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load thread between owner [%s] and contact [%s] for user [%s]", owner, contact, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if thread.ClassifiedAt != nil && time.Since(*thread.ClassifiedAt) < messageThreadClassificationInterval {
+		ctxLogger.Info(fmt.Sprintf("thread [%s] was classified at [%s] which is less than [%s] ago", thread.ID, thread.ClassifiedAt, messageThreadClassificationInterval))
+		return nil
+	}
+
+	messages, err := service.messageRepository.Index(ctx, userID, owner, contact, nil, nil, repositories.IndexParams{Limit: messageThreadClassificationMessageLimit})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch messages of thread [%s] for user [%s]", thread.ID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// end-to-end encrypted messages cannot be read by the classifier
+	plaintext := make([]entities.Message, 0, len(*messages))
+	for _, message := range *messages {
+		if !message.Encrypted && message.Type != entities.MessageTypeCallMissed && strings.TrimSpace(message.Content) != "" {
+			plaintext = append(plaintext, message)
+		}
+	}
+
+	if len(plaintext) == 0 {
+		ctxLogger.Info(fmt.Sprintf("thread [%s] has no messages which can be classified", thread.ID))
+		return nil
+	}
+
+	classification, err := service.classifier.Classify(ctx, plaintext)
+	if err != nil {
+		msg := fmt.Sprintf("cannot classify [%d] messages of thread [%s]", len(plaintext), thread.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.repository.UpdateClassification(ctx, userID, thread.ID, string(classification.Topic), classification.Language, time.Now().UTC()); err != nil {
+		msg := fmt.Sprintf("cannot store topic [%s] of thread [%s]", classification.Topic, thread.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("classified thread [%s] with topic [%s] from [%d] messages", thread.ID, classification.Topic, len(plaintext)))
+	return nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// MessageThreadClassification is the topic and language of the conversation in an entities.MessageThread
+type MessageThreadClassification struct {
+	Topic    entities.MessageThreadTopic
+	Language *string
+}
+
+// MessageThreadClassifier detects the topic and language of a conversation
+type MessageThreadClassifier interface {
+	// Classify the messages of a thread which are ordered with the most recent message first. The messages are not end-to-end encrypted.
+	Classify(ctx context.Context, messages []entities.Message) (*MessageThreadClassification, error)
+}
+
+// lastMessageLanguage returns the language of the most recent message whose language was detected
+func lastMessageLanguage(messages []entities.Message) *string {
+	for _, message := range messages {
+		if message.Language != nil && *message.Language != "" {
+			return message.Language
+		}
+	}
+	return nil
+}
@@ -2,8 +2,13 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
@@ -18,10 +23,12 @@ import (
 // MessageThreadService is handles message requests
 type MessageThreadService struct {
 	service
-	logger          telemetry.Logger
-	tracer          telemetry.Tracer
-	repository      repositories.MessageThreadRepository
-	eventDispatcher *EventDispatcher
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.MessageThreadRepository
+	messageRepository repositories.MessageRepository
+	webhookRepository repositories.WebhookRepository
+	eventDispatcher   *EventDispatcher
 }
 
 // NewMessageThreadService creates a new MessageThreadService
@@ -29,13 +36,17 @@ func NewMessageThreadService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	repository repositories.MessageThreadRepository,
+	messageRepository repositories.MessageRepository,
+	webhookRepository repositories.WebhookRepository,
 	eventDispatcher *EventDispatcher,
 ) (s *MessageThreadService) {
 	return &MessageThreadService{
-		logger:          logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:          tracer,
-		eventDispatcher: eventDispatcher,
-		repository:      repository,
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		eventDispatcher:   eventDispatcher,
+		repository:        repository,
+		messageRepository: messageRepository,
+		webhookRepository: webhookRepository,
 	}
 }
 
@@ -47,6 +58,7 @@ type MessageThreadUpdateParams struct {
 	Content   string
 	UserID    entities.UserID
 	MessageID uuid.UUID
+	Type      entities.MessageType
 	Timestamp time.Time
 }
 
@@ -92,7 +104,7 @@ func (service *MessageThreadService) UpdateThread(ctx context.Context, params Me
 		return nil
 	}
 
-	if err = service.repository.Update(ctx, thread.Update(params.Timestamp, params.MessageID, params.Content, params.Status)); err != nil {
+	if err = service.repository.Update(ctx, thread.Update(params.Timestamp, params.MessageID, params.Type, params.Content, params.Status)); err != nil {
 		msg := fmt.Sprintf("cannot update message thread with id [%s] after adding message [%s]", thread.ID, params.MessageID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -118,7 +130,7 @@ func (service *MessageThreadService) UpdateStatus(ctx context.Context, params Me
 	thread, err := service.repository.Load(ctx, params.UserID, params.MessageThreadID)
 	if err != nil {
 		msg := fmt.Sprintf("cannot find thread with id [%s]", params.MessageThreadID)
-		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
 	}
 
 	if err = service.repository.Update(ctx, thread.UpdateArchive(params.IsArchived)); err != nil {
@@ -130,6 +142,134 @@ func (service *MessageThreadService) UpdateStatus(ctx context.Context, params Me
 	return thread, nil
 }
 
+// UpdateMuted mutes or unmutes a thread so that received messages are not forwarded to webhooks and push notifications
+func (service *MessageThreadService) UpdateMuted(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, isMuted bool) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.UpdateMuted(isMuted)); err != nil {
+		msg := fmt.Sprintf("cannot update message thread with id [%s] with muted status [%t]", thread.ID, isMuted)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with muted status [%t]", thread.ID, thread.IsMuted))
+	return thread, nil
+}
+
+// UpdateUnread marks a thread as read or unread
+func (service *MessageThreadService) UpdateUnread(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, isUnread bool) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.UpdateUnread(isUnread)); err != nil {
+		msg := fmt.Sprintf("cannot update message thread with id [%s] with unread status [%t]", thread.ID, isUnread)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with unread status [%t]", thread.ID, thread.IsUnread))
+	return thread, nil
+}
+
+// UpdatePinned pins or unpins a thread so that it is listed before the other threads of the owner
+func (service *MessageThreadService) UpdatePinned(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, isPinned bool) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.UpdatePinned(isPinned)); err != nil {
+		msg := fmt.Sprintf("cannot update message thread with id [%s] with pinned status [%t]", thread.ID, isPinned)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with pinned status [%t]", thread.ID, thread.IsPinned))
+	return thread, nil
+}
+
+// AddLabels adds labels to an entities.MessageThread
+func (service *MessageThreadService) AddLabels(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, labels []string) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.AddLabels(labels)); err != nil {
+		msg := fmt.Sprintf("cannot add labels [%s] to message thread with id [%s]", strings.Join(labels, ","), thread.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with labels [%s]", thread.ID, strings.Join(thread.Labels, ",")))
+	return thread, nil
+}
+
+// RemoveLabel removes a label from an entities.MessageThread
+func (service *MessageThreadService) RemoveLabel(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, label string) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Update(ctx, thread.RemoveLabel(label)); err != nil {
+		msg := fmt.Sprintf("cannot remove label [%s] from message thread with id [%s]", label, thread.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with labels [%s]", thread.ID, strings.Join(thread.Labels, ",")))
+	return thread, nil
+}
+
+// UpdateWebhook sets the entities.Webhook which receives the events of an entities.MessageThread instead of the webhooks of the account.
+// The webhook of the thread is removed when webhookID is nil.
+func (service *MessageThreadService) UpdateWebhook(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID, webhookID *uuid.UUID) (*entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find thread with id [%s]", messageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if webhookID != nil {
+		if _, err = service.webhookRepository.Load(ctx, userID, *webhookID); err != nil {
+			msg := fmt.Sprintf("cannot find webhook with id [%s] for user [%s]", *webhookID, userID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+		}
+	}
+
+	if err = service.repository.Update(ctx, thread.UpdateWebhook(webhookID)); err != nil {
+		msg := fmt.Sprintf("cannot update the webhook of message thread with id [%s]", thread.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("thread with id [%s] updated with webhook [%v]", thread.ID, thread.WebhookID))
+	return thread, nil
+}
+
 // UpdateAfterDeletedMessage updates a thread after the last message has been deleted
 func (service *MessageThreadService) UpdateAfterDeletedMessage(ctx context.Context, payload *events.MessageAPIDeletedPayload) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
@@ -188,6 +328,9 @@ func (service *MessageThreadService) createThread(ctx context.Context, params Me
 		LastMessageContent: &params.Content,
 		Status:             params.Status,
 		LastMessageID:      &params.MessageID,
+		LastMessageType:    &params.Type,
+		LastMessageAt:      &params.Timestamp,
+		IsUnread:           params.Type == entities.MessageTypeMobileOriginated,
 		CreatedAt:          time.Now().UTC(),
 		UpdatedAt:          time.Now().UTC(),
 		OrderTimestamp:     params.Timestamp,
@@ -239,6 +382,9 @@ type MessageThreadGetParams struct {
 	IsArchived bool
 	UserID     entities.UserID
 	Owner      string
+	Label      string
+	Topic      string
+	Language   string
 }
 
 // GetThreads fetches threads for an owner
@@ -248,7 +394,7 @@ func (service *MessageThreadService) GetThreads(ctx context.Context, params Mess
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	threads, err := service.repository.Index(ctx, params.UserID, params.Owner, params.IsArchived, params.IndexParams)
+	threads, err := service.repository.Index(ctx, params.UserID, params.Owner, params.IsArchived, params.Label, params.Topic, params.Language, params.IndexParams)
 	if err != nil {
 		msg := fmt.Sprintf("could not fetch messages threads for params [%+#v]", params)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -258,6 +404,29 @@ func (service *MessageThreadService) GetThreads(ctx context.Context, params Mess
 	return threads, nil
 }
 
+// MessageThreadSearchParams parameters for searching threads
+type MessageThreadSearchParams struct {
+	repositories.IndexParams
+	IsArchived *bool
+	UserID     entities.UserID
+	Owners     []string
+}
+
+// SearchThreads fetches the threads of a user with a contact or last message content matching the query
+func (service *MessageThreadService) SearchThreads(ctx context.Context, params MessageThreadSearchParams) (*[]entities.MessageThread, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	threads, err := service.repository.Search(ctx, params.UserID, params.Owners, params.IsArchived, params.IndexParams)
+	if err != nil {
+		msg := fmt.Sprintf("could not search messages threads for params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("found [%d] threads with params [%+#v]", len(*threads), params))
+	return threads, nil
+}
+
 // GetThread fetches an entities.MessageThread  message thread by the ID
 func (service *MessageThreadService) GetThread(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID) (*entities.MessageThread, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -272,6 +441,212 @@ func (service *MessageThreadService) GetThread(ctx context.Context, userID entit
 	return thread, nil
 }
 
+// MessageThreadContextParams are parameters for fetching the context of a thread
+type MessageThreadContextParams struct {
+	UserID          entities.UserID
+	MessageThreadID uuid.UUID
+	Limit           int
+}
+
+// GetContext fetches the recent messages in a thread in a compact format which can be used by bots and LLM integrations
+func (service *MessageThreadService) GetContext(ctx context.Context, params MessageThreadContextParams) (*entities.MessageThreadContext, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, params.UserID, params.MessageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch thread with ID [%s] for user [%s]", params.MessageThreadID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	messages, err := service.messageRepository.Index(ctx, thread.UserID, thread.Owner, thread.Contact, nil, nil, repositories.IndexParams{Limit: params.Limit})
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch messages for thread with ID [%s] for user [%s]", thread.ID, thread.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched context with [%d] messages for thread [%s]", len(*messages), thread.ID))
+	return entities.NewMessageThreadContext(thread, *messages), nil
+}
+
+// messageThreadExportBatchSize is the number of messages which are fetched from the database at once while exporting a thread
+const messageThreadExportBatchSize = 500
+
+// messageThreadExportColumns are the columns of a thread which is exported as a CSV file
+var messageThreadExportColumns = []string{"id", "timestamp", "type", "from", "to", "content", "encrypted", "status", "sim"}
+
+// Export writes all the messages in a thread ordered from the oldest to the newest in the format so the conversation can be archived
+func (service *MessageThreadService) Export(ctx context.Context, thread *entities.MessageThread, format entities.MessageThreadExportFormat, writer io.Writer) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	var count int
+	var err error
+	if format == entities.MessageThreadExportFormatJSON {
+		count, err = service.exportJSON(ctx, thread, writer)
+	} else {
+		count, err = service.exportCSV(ctx, thread, writer)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot export thread with ID [%s] as [%s] after [%d] messages", thread.ID, format, count)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("exported [%d] messages in thread [%s] as [%s] for user [%s]", count, thread.ID, format, thread.UserID))
+	return nil
+}
+
+func (service *MessageThreadService) exportCSV(ctx context.Context, thread *entities.MessageThread, writer io.Writer) (int, error) {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(messageThreadExportColumns); err != nil {
+		return 0, stacktrace.Propagate(err, "cannot write the CSV header")
+	}
+
+	count, err := service.forEachMessage(ctx, thread, func(_ int, message *entities.Message) error {
+		from, to := message.Owner, message.Contact
+		if message.Type != entities.MessageTypeMobileTerminated {
+			from, to = message.Contact, message.Owner
+		}
+
+		return csvWriter.Write([]string{
+			message.ID.String(),
+			message.OrderTimestamp.Format(time.RFC3339Nano),
+			string(message.Type),
+			from,
+			to,
+			message.Content,
+			strconv.FormatBool(message.Encrypted),
+			string(message.Status),
+			string(message.SIM),
+		})
+	})
+	if err != nil {
+		return count, err
+	}
+
+	csvWriter.Flush()
+	return count, csvWriter.Error()
+}
+
+func (service *MessageThreadService) exportJSON(ctx context.Context, thread *entities.MessageThread, writer io.Writer) (int, error) {
+	header, err := json.Marshal(map[string]any{
+		"message_thread_id": thread.ID,
+		"owner":             thread.Owner,
+		"contact":           thread.Contact,
+		"exported_at":       time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, stacktrace.Propagate(err, fmt.Sprintf("cannot marshal the header of thread [%s]", thread.ID))
+	}
+
+	// The messages are appended to the header object so the thread is never loaded in memory at once
+	if _, err = writer.Write(append(header[:len(header)-1], `,"messages":[`...)); err != nil {
+		return 0, stacktrace.Propagate(err, "cannot write the JSON header")
+	}
+
+	count, err := service.forEachMessage(ctx, thread, func(index int, message *entities.Message) error {
+		content, err := json.Marshal(message)
+		if err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal message with ID [%s]", message.ID))
+		}
+
+		if index > 0 {
+			content = append([]byte(","), content...)
+		}
+
+		_, err = writer.Write(content)
+		return err
+	})
+	if err != nil {
+		return count, err
+	}
+
+	_, err = writer.Write([]byte("]}"))
+	return count, err
+}
+
+// forEachMessage calls the callback with every message in a thread from the oldest to the newest and returns the number of messages
+func (service *MessageThreadService) forEachMessage(ctx context.Context, thread *entities.MessageThread, callback func(index int, message *entities.Message) error) (int, error) {
+	count := 0
+	var cursor *repositories.Cursor
+	for {
+		messages, err := service.messageRepository.History(ctx, thread.UserID, thread.Owner, thread.Contact, repositories.IndexParams{Cursor: cursor, Limit: messageThreadExportBatchSize})
+		if err != nil {
+			return count, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch messages in thread [%s] after [%d] messages", thread.ID, count))
+		}
+
+		for index := range *messages {
+			if err = callback(count, &(*messages)[index]); err != nil {
+				return count, stacktrace.Propagate(err, fmt.Sprintf("cannot export message with ID [%s]", (*messages)[index].ID))
+			}
+			count++
+		}
+
+		if len(*messages) < messageThreadExportBatchSize {
+			return count, nil
+		}
+
+		last := (*messages)[len(*messages)-1]
+		cursor = repositories.NewCursor(last.OrderTimestamp, last.ID)
+	}
+}
+
+// messageThreadBulkDeleteLimit is the maximum number of message threads which are deleted by a single request
+const messageThreadBulkDeleteLimit = 1000
+
+// MessageThreadBulkDeleteParams are parameters for deleting many entities.MessageThread
+type MessageThreadBulkDeleteParams struct {
+	UserID           entities.UserID
+	Source           string
+	MessageThreadIDs []uuid.UUID
+	OlderThan        *time.Time
+}
+
+// BulkDelete deletes the message threads with the IDs or which have had no messages since OlderThan together with the
+// messages in the threads. At most 1000 threads are deleted in a request so the request can be repeated until no thread is deleted.
+func (service *MessageThreadService) BulkDelete(ctx context.Context, params MessageThreadBulkDeleteParams) (*entities.MessageThreadBulkDelete, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	threads, err := service.repository.LoadMany(ctx, params.UserID, params.MessageThreadIDs, params.OlderThan, messageThreadBulkDeleteLimit)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message threads to delete with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	result := &entities.MessageThreadBulkDelete{MessageThreadIDs: make([]uuid.UUID, 0, len(*threads))}
+	for index := range *threads {
+		thread := &(*threads)[index]
+		if err = service.DeleteThread(ctx, params.Source, thread); err != nil {
+			msg := fmt.Sprintf("cannot delete message thread with ID [%s] after deleting [%d] threads for user [%s]", thread.ID, result.Count, params.UserID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		result.MessageThreadIDs = append(result.MessageThreadIDs, thread.ID)
+		result.Count++
+	}
+
+	event, err := service.createEvent(events.MessageThreadAPIBulkDeleted, params.Source, &events.MessageThreadAPIBulkDeletedPayload{
+		UserID:           params.UserID,
+		MessageThreadIDs: result.MessageThreadIDs,
+		OlderThan:        params.OlderThan,
+		Count:            result.Count,
+		Timestamp:        time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for user [%s]", events.MessageThreadAPIBulkDeleted, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] with id [%s] for user [%s]", event.Type(), event.ID(), params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted [%d] message threads for user [%s]", result.Count, params.UserID))
+	return result, nil
+}
+
 // DeleteThread deletes an entities.MessageThread from the database
 func (service *MessageThreadService) DeleteThread(ctx context.Context, source string, thread *entities.MessageThread) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
@@ -306,3 +681,17 @@ func (service *MessageThreadService) DeleteThread(ctx context.Context, source st
 	ctxLogger.Info(fmt.Sprintf("dispatched [%s] event with id [%s] for message thread [%s]", event.Type(), event.ID(), thread.ID))
 	return nil
 }
+
+// GetTopicCounts counts the classified threads of an owner by topic and language
+func (service *MessageThreadService) GetTopicCounts(ctx context.Context, userID entities.UserID, owner string) ([]*entities.MessageThreadTopicCount, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	counts, err := service.repository.CountTopics(ctx, userID, owner)
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the topics of threads for owner [%s] and user [%s]", owner, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	messageThreadShareIssuer       = "api.httpsms.com"
+	messageThreadShareMessageLimit = 200
+)
+
+// MessageThreadShareService creates and resolves public read-only links to an entities.MessageThread
+type MessageThreadShareService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.MessageThreadRepository
+	messageRepository repositories.MessageRepository
+	domainService     *CustomDomainService
+	signingKey        []byte
+}
+
+// NewMessageThreadShareService creates a new MessageThreadShareService
+func NewMessageThreadShareService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageThreadRepository,
+	messageRepository repositories.MessageRepository,
+	domainService *CustomDomainService,
+	signingKey string,
+) (s *MessageThreadShareService) {
+	return &MessageThreadShareService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		messageRepository: messageRepository,
+		domainService:     domainService,
+		signingKey:        []byte(signingKey),
+	}
+}
+
+// MessageThreadShareParams are parameters for sharing an entities.MessageThread
+type MessageThreadShareParams struct {
+	UserID          entities.UserID
+	MessageThreadID uuid.UUID
+	ExpiresIn       time.Duration
+	BaseURL         string
+}
+
+// Share generates a signed link which expires after MessageThreadShareParams.ExpiresIn. The link is created on the
+// verified entities.CustomDomain of the user instead of MessageThreadShareParams.BaseURL when the user has one.
+func (service *MessageThreadShareService) Share(ctx context.Context, params MessageThreadShareParams) (*entities.MessageThreadShare, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if len(service.signingKey) == 0 {
+		msg := fmt.Sprintf("cannot share thread with ID [%s] because the signing key is not configured", params.MessageThreadID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	thread, err := service.repository.Load(ctx, params.UserID, params.MessageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch thread with ID [%s] for user [%s]", params.MessageThreadID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(params.ExpiresIn)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Audience:  string(thread.UserID),
+		ExpiresAt: expiresAt.Unix(),
+		IssuedAt:  now.Unix(),
+		Issuer:    messageThreadShareIssuer,
+		Subject:   thread.ID.String(),
+	}).SignedString(service.signingKey)
+	if err != nil {
+		msg := fmt.Sprintf("cannot sign share token for thread with ID [%s]", thread.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created share link for thread [%s] which expires at [%s]", thread.ID, expiresAt))
+	return &entities.MessageThreadShare{
+		MessageThreadID: thread.ID,
+		URL:             fmt.Sprintf("%s/shared/message-threads/%s", strings.TrimRight(service.domainService.BaseURL(ctx, thread.UserID, params.BaseURL), "/"), token),
+		ExpiresAt:       expiresAt,
+	}, nil
+}
+
+// LoadShared verifies a share token and fetches the transcript of the shared entities.MessageThread
+func (service *MessageThreadShareService) LoadShared(ctx context.Context, token string) (*entities.MessageThreadContext, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	claims, err := service.parseToken(token)
+	if err != nil {
+		msg := "cannot verify message thread share token"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, repositories.ErrCodeNotFound, msg))
+	}
+
+	threadID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot parse thread ID [%s] in message thread share token", claims.Subject)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, repositories.ErrCodeNotFound, msg))
+	}
+
+	thread, err := service.repository.Load(ctx, entities.UserID(claims.Audience), threadID)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch shared thread with ID [%s] for user [%s]", threadID, claims.Audience)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	messages, err := service.messageRepository.Index(ctx, thread.UserID, thread.Owner, thread.Contact, nil, nil, repositories.IndexParams{Limit: messageThreadShareMessageLimit})
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch messages for shared thread with ID [%s] for user [%s]", thread.ID, thread.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] messages for shared thread [%s]", len(*messages), thread.ID))
+	return entities.NewMessageThreadContext(thread, *messages), nil
+}
+
+func (service *MessageThreadShareService) parseToken(token string) (*jwt.StandardClaims, error) {
+	if len(service.signingKey) == 0 {
+		return nil, stacktrace.NewError("the message thread share signing key is not configured")
+	}
+
+	claims := new(jwt.StandardClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, stacktrace.NewError(fmt.Sprintf("unexpected signing method [%s]", token.Header["alg"]))
+		}
+		return service.signingKey, nil
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot parse message thread share token")
+	}
+
+	if !claims.VerifyIssuer(messageThreadShareIssuer, true) {
+		return nil, stacktrace.NewError(fmt.Sprintf("invalid issuer [%s] in message thread share token", claims.Issuer))
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	messageThreadSummaryMessageLimit = 100
+	messageThreadSummaryTTL          = 24 * time.Hour
+	messageThreadSummaryInstructions = "You summarize SMS conversations between the owner of a phone and a contact. " +
+		"Write a short neutral summary of at most 5 sentences with the main topics, requests and decisions. " +
+		"Reply only with the summary in the language of the conversation."
+)
+
+// MessageThreadSummaryService generates summaries of message threads with an LLMProvider
+type MessageThreadSummaryService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	provider          LLMProvider
+	repository        repositories.MessageThreadRepository
+	messageRepository repositories.MessageRepository
+	cache             cache.Cache
+}
+
+// NewMessageThreadSummaryService creates a new MessageThreadSummaryService
+func NewMessageThreadSummaryService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	provider LLMProvider,
+	repository repositories.MessageThreadRepository,
+	messageRepository repositories.MessageRepository,
+	cache cache.Cache,
+) (s *MessageThreadSummaryService) {
+	return &MessageThreadSummaryService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		provider:          provider,
+		repository:        repository,
+		messageRepository: messageRepository,
+		cache:             cache,
+	}
+}
+
+// Summarize returns the summary of the recent messages in an entities.MessageThread.
+// Summaries are cached by the last message of the thread so a new summary is generated when a new message arrives.
+func (service *MessageThreadSummaryService) Summarize(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID) (*entities.MessageThreadSummary, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	thread, err := service.repository.Load(ctx, userID, messageThreadID)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch thread with ID [%s] for user [%s]", messageThreadID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	cacheKey := service.cacheKey(thread)
+	if summary := service.cachedSummary(ctx, cacheKey); summary != nil {
+		ctxLogger.Info(fmt.Sprintf("fetched cached summary of thread [%s] with last message [%s]", thread.ID, summary.LastMessageID))
+		return summary, nil
+	}
+
+	messages, err := service.messageRepository.Index(ctx, thread.UserID, thread.Owner, thread.Contact, nil, nil, repositories.IndexParams{Limit: messageThreadSummaryMessageLimit})
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch messages for thread with ID [%s] for user [%s]", thread.ID, thread.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	transcript, count := service.transcript(entities.NewMessageThreadContext(thread, *messages))
+	summary := &entities.MessageThreadSummary{
+		MessageThreadID: thread.ID,
+		Owner:           thread.Owner,
+		Contact:         thread.Contact,
+		MessageCount:    count,
+		LastMessageID:   thread.LastMessageID,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	if count > 0 {
+		summary.Summary, err = service.provider.Complete(ctx, messageThreadSummaryInstructions, []entities.MessageThreadContextMessage{
+			{Role: entities.MessageThreadContextRoleUser, Content: transcript, Timestamp: summary.CreatedAt},
+		})
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot summarize [%d] messages in thread [%s]", count, thread.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	service.cacheSummary(ctx, cacheKey, summary)
+
+	ctxLogger.Info(fmt.Sprintf("summarized [%d] messages in thread [%s] for user [%s]", count, thread.ID, thread.UserID))
+	return summary, nil
+}
+
+// transcript renders the messages which are not end-to-end encrypted as one line per message
+func (service *MessageThreadSummaryService) transcript(threadContext *entities.MessageThreadContext) (string, int) {
+	var builder strings.Builder
+	count := 0
+	for _, message := range threadContext.Messages {
+		if message.Encrypted || strings.TrimSpace(message.Content) == "" {
+			continue
+		}
+
+		author := "Owner"
+		switch message.Role {
+		case entities.MessageThreadContextRoleUser:
+			author = "Contact"
+		case entities.MessageThreadContextRoleSystem:
+			author = "Event"
+		}
+
+		builder.WriteString(fmt.Sprintf("[%s] %s: %s\n", message.Timestamp.Format(time.RFC3339), author, message.Content))
+		count++
+	}
+	return builder.String(), count
+}
+
+func (service *MessageThreadSummaryService) cacheKey(thread *entities.MessageThread) string {
+	lastMessageID := "none"
+	if thread.LastMessageID != nil {
+		lastMessageID = thread.LastMessageID.String()
+	}
+	return fmt.Sprintf("message-thread-summary.%s.%s", thread.ID, lastMessageID)
+}
+
+func (service *MessageThreadSummaryService) cachedSummary(ctx context.Context, key string) *entities.MessageThreadSummary {
+	value, err := service.cache.Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+
+	summary := new(entities.MessageThreadSummary)
+	if err = json.Unmarshal([]byte(value), summary); err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal cached summary with key [%s]", key)))
+		return nil
+	}
+	return summary
+}
+
+func (service *MessageThreadSummaryService) cacheSummary(ctx context.Context, key string, summary *entities.MessageThreadSummary) {
+	value, err := json.Marshal(summary)
+	if err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot marshal summary of thread [%s]", summary.MessageThreadID)))
+		return
+	}
+
+	if err = service.cache.Set(ctx, key, string(value), messageThreadSummaryTTL); err != nil {
+		service.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot set item in cache with key [%s]", key)))
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// metricsCacheDuration prevents every scrape from running the aggregate queries against the database
+	metricsCacheDuration = 15 * time.Second
+
+	// metricsHeartbeatWindow is how far back we look for the last heartbeat of a phone
+	metricsHeartbeatWindow = 7 * 24 * time.Hour
+
+	// metricsDeadLetterWindow is how far back we look for phone notifications which could not be delivered
+	metricsDeadLetterWindow = 24 * time.Hour
+
+	// metricsCrashWindow is how far back we look for the crashes of the app on a phone
+	metricsCrashWindow = 24 * time.Hour
+)
+
+// BusinessMetrics are the business level numbers which are exported for alerting
+type BusinessMetrics struct {
+	QueueDepths       []*entities.PhoneQueueDepth
+	Heartbeats        []*entities.Heartbeat
+	WebhooksDelivered uint64
+	WebhooksFailed    uint64
+	MessagesPruned    map[entities.MessageCategory]uint64
+	DeadLetters       int64
+	PhoneCrashes      []*entities.PhoneCrashCount
+	CollectedAt       time.Time
+}
+
+// WebhookFailureRatio is the ratio of webhook requests which failed
+func (metrics *BusinessMetrics) WebhookFailureRatio() float64 {
+	total := metrics.WebhooksDelivered + metrics.WebhooksFailed
+	if total == 0 {
+		return 0
+	}
+	return float64(metrics.WebhooksFailed) / float64(total)
+}
+
+// MetricsService collects the BusinessMetrics
+type MetricsService struct {
+	service
+	logger                 telemetry.Logger
+	tracer                 telemetry.Tracer
+	messageRepository      repositories.MessageRepository
+	heartbeatRepository    repositories.HeartbeatRepository
+	notificationRepository repositories.PhoneNotificationRepository
+	crashRepository        repositories.PhoneCrashRepository
+	usageRepository        repositories.BillingUsageRepository
+	webhookCounter         *WebhookDeliveryCounter
+	pruneCounter           *MessagePruneCounter
+	deprecationCounter     *DeprecationCounter
+
+	mutex   sync.Mutex
+	metrics *BusinessMetrics
+}
+
+// NewMetricsService creates a new MetricsService
+func NewMetricsService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	messageRepository repositories.MessageRepository,
+	heartbeatRepository repositories.HeartbeatRepository,
+	notificationRepository repositories.PhoneNotificationRepository,
+	crashRepository repositories.PhoneCrashRepository,
+	usageRepository repositories.BillingUsageRepository,
+	webhookCounter *WebhookDeliveryCounter,
+	pruneCounter *MessagePruneCounter,
+	deprecationCounter *DeprecationCounter,
+) (s *MetricsService) {
+	return &MetricsService{
+		logger:                 logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:                 tracer,
+		messageRepository:      messageRepository,
+		heartbeatRepository:    heartbeatRepository,
+		notificationRepository: notificationRepository,
+		crashRepository:        crashRepository,
+		usageRepository:        usageRepository,
+		webhookCounter:         webhookCounter,
+		pruneCounter:           pruneCounter,
+		deprecationCounter:     deprecationCounter,
+	}
+}
+
+// DeprecatedRouteUsages returns the API keys which called the deprecated routes
+func (service *MetricsService) DeprecatedRouteUsages(ctx context.Context) ([]*entities.DeprecatedRouteUsage, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	usages, err := service.deprecationCounter.Usages(ctx)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot load the usage of the deprecated routes"))
+	}
+	return usages, nil
+}
+
+// Collect returns the BusinessMetrics, the database is queried at most once every metricsCacheDuration
+func (service *MetricsService) Collect(ctx context.Context) (*BusinessMetrics, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.metrics != nil && time.Since(service.metrics.CollectedAt) < metricsCacheDuration {
+		return service.withCounterTotals(ctx, ctxLogger, service.metrics), nil
+	}
+
+	depths, err := service.messageRepository.CountOutstandingByOwner(ctx)
+	if err != nil {
+		msg := "cannot count the outstanding messages of every phone"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	heartbeats, err := service.heartbeatRepository.LastForEveryOwner(ctx, time.Now().Add(-metricsHeartbeatWindow))
+	if err != nil {
+		msg := "cannot fetch the last heartbeat of every phone"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deadLetters, err := service.notificationRepository.CountFailed(ctx, time.Now().Add(-metricsDeadLetterWindow))
+	if err != nil {
+		msg := "cannot count the phone notifications which could not be delivered"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	crashes, err := service.crashRepository.CountByOwner(ctx, time.Now().Add(-metricsCrashWindow))
+	if err != nil {
+		msg := "cannot count the crashes of every phone"
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	service.metrics = &BusinessMetrics{
+		QueueDepths:  depths,
+		Heartbeats:   heartbeats,
+		DeadLetters:  deadLetters,
+		PhoneCrashes: crashes,
+		CollectedAt:  time.Now().UTC(),
+	}
+
+	ctxLogger.Info(fmt.Sprintf("collected business metrics for [%d] phones with outstanding messages and [%d] phones with heartbeats", len(depths), len(heartbeats)))
+	return service.withCounterTotals(ctx, ctxLogger, service.metrics), nil
+}
+
+// CollectUsage returns the entities.BillingUsage of every account for the billing period which contains the timestamp.
+// The usage is not cached because it is exported once a day to the billing pipelines.
+func (service *MetricsService) CollectUsage(ctx context.Context, timestamp time.Time) ([]*entities.BillingUsage, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	usages, err := service.usageRepository.FetchForPeriod(ctx, timestamp)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the billing usage of every account for the period of [%s]", timestamp)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("collected the billing usage of [%d] accounts for the period of [%s]", len(usages), timestamp))
+	return usages, nil
+}
+
+// withCounterTotals copies the metrics with the current webhook and pruned message totals which are not cached
+func (service *MetricsService) withCounterTotals(ctx context.Context, ctxLogger telemetry.Logger, metrics *BusinessMetrics) *BusinessMetrics {
+	result := *metrics
+
+	delivered, failed, err := service.webhookCounter.Totals(ctx)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, "cannot load the webhook delivery totals"))
+	}
+	result.WebhooksDelivered, result.WebhooksFailed = delivered, failed
+	result.MessagesPruned = service.pruneCounter.Totals()
+	return &result
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	mqttQualityOfService = byte(1)
+	mqttPublishTimeout   = 10 * time.Second
+)
+
+// MQTTAccess is the access of an MQTT client to a topic which is checked by the broker
+type MQTTAccess int
+
+const (
+	// MQTTAccessRead is the access to receive the messages which are published to a topic
+	MQTTAccessRead = MQTTAccess(1)
+
+	// MQTTAccessWrite is the access to publish to a topic
+	MQTTAccessWrite = MQTTAccess(2)
+
+	// MQTTAccessSubscribe is the access to subscribe to a topic filter
+	MQTTAccessSubscribe = MQTTAccess(4)
+)
+
+// MQTTSendResult is published after a send command has been processed
+type MQTTSendResult struct {
+	RequestID string                     `json:"request_id,omitempty"`
+	Status    string                     `json:"status"`
+	Message   *entities.Message          `json:"message,omitempty"`
+	Errors    responses.ValidationErrors `json:"errors,omitempty"`
+}
+
+// MQTTService bridges messages between httpSMS and an MQTT broker
+type MQTTService struct {
+	service
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	client         mqtt.Client
+	userRepository repositories.UserRepository
+	topicPrefix    string
+	sharedGroup    string
+
+	// username and password are the credentials of the API on the broker, the API can publish and subscribe to every topic
+	username string
+	password string
+}
+
+// NewMQTTService creates a new MQTTService
+func NewMQTTService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client mqtt.Client,
+	userRepository repositories.UserRepository,
+	topicPrefix string,
+	sharedGroup string,
+	username string,
+	password string,
+) (s *MQTTService) {
+	return &MQTTService{
+		logger:         logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:         tracer,
+		client:         client,
+		userRepository: userRepository,
+		topicPrefix:    strings.Trim(topicPrefix, "/"),
+		sharedGroup:    sharedGroup,
+		username:       username,
+		password:       password,
+	}
+}
+
+// Authenticate checks the credentials of a client which connects to the broker. The username is the ID of the user and
+// the password is the API key of the account or an additional API key with the send and read scopes.
+func (service *MQTTService) Authenticate(ctx context.Context, username string, password string) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.IsSuperuser(username) {
+		return service.password != "" && subtle.ConstantTimeCompare([]byte(password), []byte(service.password)) == 1
+	}
+
+	if username == "" || password == "" || service.topicLevel(username) != username {
+		return false
+	}
+
+	authUser, err := service.userRepository.LoadAuthUser(ctx, password)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load the user of the MQTT client with username [%s]", username)))
+		return false
+	}
+
+	return authUser.ID == entities.UserID(username) && authUser.HasScope(entities.APIKeyScopeSend) && authUser.HasScope(entities.APIKeyScopeRead)
+}
+
+// IsSuperuser checks if the username is the MQTT_USERNAME which is used by the API to connect to the broker
+func (service *MQTTService) IsSuperuser(username string) bool {
+	return service.username != "" && username == service.username
+}
+
+// Authorize checks if a client can access a topic. A user can only publish to the <prefix>/<user_id>/send and the
+// <prefix>/heartbeat topics and receive the messages of the topics which start with <prefix>/<user_id>/.
+func (service *MQTTService) Authorize(username string, topic string, access MQTTAccess) bool {
+	if service.IsSuperuser(username) {
+		return true
+	}
+
+	userTopic := fmt.Sprintf("%s/%s/", service.topicPrefix, username)
+	switch access {
+	case MQTTAccessRead, MQTTAccessSubscribe:
+		return strings.HasPrefix(topic, userTopic)
+	case MQTTAccessWrite:
+		return topic == userTopic+"send" || topic == service.topicPrefix+"/heartbeat"
+	default:
+		return false
+	}
+}
+
+// PublishReceived publishes a message which was received by a phone to the <prefix>/<user_id>/<owner>/received topic
+func (service *MQTTService) PublishReceived(ctx context.Context, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	topic := fmt.Sprintf("%s/%s/%s/received", service.topicPrefix, payload.UserID, service.topicLevel(payload.Owner))
+	if err := service.publish(topic, payload); err != nil {
+		msg := fmt.Sprintf("cannot publish message with ID [%s] to MQTT topic [%s]", payload.MessageID, topic)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("published message with ID [%s] to MQTT topic [%s]", payload.MessageID, topic))
+	return nil
+}
+
+// PublishSendResult publishes the result of a send command to the <prefix>/<user_id>/send/result topic
+func (service *MQTTService) PublishSendResult(ctx context.Context, userID entities.UserID, result *MQTTSendResult) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	topic := fmt.Sprintf("%s/%s/send/result", service.topicPrefix, userID)
+	if err := service.publish(topic, result); err != nil {
+		msg := fmt.Sprintf("cannot publish send result with status [%s] to MQTT topic [%s]", result.Status, topic)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("published send result with status [%s] to MQTT topic [%s]", result.Status, topic))
+	return nil
+}
+
+// SubscribeToSendCommands calls the handler for every command which is published to the <prefix>/<user_id>/send topic
+// of a user. Only the user can publish to the topic because the broker authorizes the clients with Authorize.
+func (service *MQTTService) SubscribeToSendCommands(ctx context.Context, handler func(ctx context.Context, userID entities.UserID, payload []byte)) error {
+	return service.subscribe(ctx, service.topicPrefix+"/+/send", func(ctx context.Context, topic string, payload []byte) {
+		userID := strings.TrimSuffix(strings.TrimPrefix(topic, service.topicPrefix+"/"), "/send")
+		handler(ctx, entities.UserID(userID), payload)
+	})
+}
+
+// SubscribeToHeartbeats calls the handler for every signed heartbeat packet which is published to the <prefix>/heartbeat topic
+func (service *MQTTService) SubscribeToHeartbeats(ctx context.Context, handler func(ctx context.Context, payload []byte)) error {
+	return service.subscribe(ctx, service.topicPrefix+"/heartbeat", func(ctx context.Context, _ string, payload []byte) {
+		handler(ctx, payload)
+	})
+}
+
+func (service *MQTTService) subscribe(ctx context.Context, topic string, handler func(ctx context.Context, topic string, payload []byte)) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.sharedGroup != "" {
+		// only one instance of the API in the group receives each command when the API is scaled horizontally
+		topic = fmt.Sprintf("$share/%s/%s", service.sharedGroup, topic)
+	}
+
+	token := service.client.Subscribe(topic, mqttQualityOfService, func(_ mqtt.Client, message mqtt.Message) {
+		go handler(context.Background(), message.Topic(), message.Payload())
+	})
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		msg := fmt.Sprintf("timeout while subscribing to MQTT topic [%s]", topic)
+		return service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+	if err := token.Error(); err != nil {
+		msg := fmt.Sprintf("cannot subscribe to MQTT topic [%s]", topic)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("subscribed to MQTT topic [%s]", topic))
+	return nil
+}
+
+func (service *MQTTService) publish(topic string, payload any) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%T] into JSON", payload))
+	}
+
+	token := service.client.Publish(topic, mqttQualityOfService, false, content)
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		return stacktrace.NewError(fmt.Sprintf("timeout after [%s] while publishing to MQTT topic [%s]", mqttPublishTimeout, topic))
+	}
+	return token.Error()
+}
+
+// topicLevel removes the characters which are not allowed in an MQTT topic name e.g. the "+" in a phone number
+func (service *MQTTService) topicLevel(value string) string {
+	return strings.NewReplacer("+", "", "#", "", "/", "").Replace(value)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// NotificationChannelAlertSeverity is how urgent a NotificationChannelAlert is
+type NotificationChannelAlertSeverity string
+
+const (
+	// NotificationChannelAlertSeverityWarning is used for alerts which need attention e.g. a failed message
+	NotificationChannelAlertSeverityWarning = NotificationChannelAlertSeverity("warning")
+
+	// NotificationChannelAlertSeverityCritical is used for alerts which stop messages from being sent e.g. an offline phone
+	NotificationChannelAlertSeverityCritical = NotificationChannelAlertSeverity("critical")
+)
+
+// NotificationChannelFact is a labelled value which is displayed in a NotificationChannelAlert
+type NotificationChannelFact struct {
+	Name  string
+	Value string
+}
+
+// NotificationChannelAlert is a provider agnostic alert which is sent to an entities.NotificationChannel
+type NotificationChannelAlert struct {
+	Event       string
+	Title       string
+	Summary     string
+	Severity    NotificationChannelAlertSeverity
+	Facts       []NotificationChannelFact
+	ActionTitle string
+	ActionURL   string
+}
+
+// NotificationChannelSender formats a NotificationChannelAlert for a chat platform and delivers it to an entities.NotificationChannel
+type NotificationChannelSender interface {
+	// Provider is the entities.NotificationChannelProvider which is supported by the sender
+	Provider() entities.NotificationChannelProvider
+
+	// Send delivers the alert to the webhook of the entities.NotificationChannel
+	Send(ctx context.Context, channel *entities.NotificationChannel, alert *NotificationChannelAlert) error
+}
+
+// postNotificationChannelPayload sends the JSON payload to the webhook URL of a chat platform
+func postNotificationChannelPayload(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%T] into JSON", payload))
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create request to [%s]", url))
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot send request to [%s]", url))
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode >= 400 {
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return stacktrace.NewError(fmt.Sprintf("request to [%s] failed with status code [%d] and body [%s]", url, response.StatusCode, content))
+	}
+
+	return nil
+}
+
+// createNotificationChannelAttachment formats the alert as a message attachment which is supported by Mattermost and Rocket.Chat
+func createNotificationChannelAttachment(alert *NotificationChannelAlert) fiber.Map {
+	fields := make([]fiber.Map, 0, len(alert.Facts))
+	for _, fact := range alert.Facts {
+		fields = append(fields, fiber.Map{"title": fact.Name, "value": fact.Value, "short": true})
+	}
+
+	color := "#FFA000"
+	if alert.Severity == NotificationChannelAlertSeverityCritical {
+		color = "#D32F2F"
+	}
+
+	attachment := fiber.Map{
+		"fallback": alert.Title + ": " + alert.Summary,
+		"color":    color,
+		"title":    alert.Title,
+		"text":     alert.Summary,
+		"fields":   fields,
+	}
+	if alert.ActionURL != "" {
+		attachment["title_link"] = alert.ActionURL
+	}
+
+	return attachment
+}
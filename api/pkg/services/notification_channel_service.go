@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// NotificationChannelService sends alerts about phones and messages to the chat channels of a user
+type NotificationChannelService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.NotificationChannelRepository
+	cache      cache.Cache
+	appURL     string
+	senders    map[entities.NotificationChannelProvider]NotificationChannelSender
+}
+
+// NewNotificationChannelService creates a new NotificationChannelService
+func NewNotificationChannelService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.NotificationChannelRepository,
+	cache cache.Cache,
+	appURL string,
+	senders []NotificationChannelSender,
+) (s *NotificationChannelService) {
+	providers := make(map[entities.NotificationChannelProvider]NotificationChannelSender, len(senders))
+	for _, sender := range senders {
+		providers[sender.Provider()] = sender
+	}
+
+	return &NotificationChannelService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+		cache:      cache,
+		appURL:     strings.TrimRight(appURL, "/"),
+		senders:    providers,
+	}
+}
+
+// Providers returns the entities.NotificationChannelProvider which can receive alerts
+func (service *NotificationChannelService) Providers() []string {
+	providers := make([]string, 0, len(service.senders))
+	for provider := range service.senders {
+		providers = append(providers, provider.String())
+	}
+	return providers
+}
+
+// DeleteAllForUser deletes all entities.NotificationChannel for an entities.UserID.
+func (service *NotificationChannelService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.NotificationChannel] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.NotificationChannel] for user with ID [%s]", userID))
+	return nil
+}
+
+// Index fetches the entities.NotificationChannel for an entities.UserID
+func (service *NotificationChannelService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.NotificationChannel, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	channels, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch notification channels with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] notification channels with prams [%+#v]", len(channels), params))
+	return channels, nil
+}
+
+// Delete an entities.NotificationChannel
+func (service *NotificationChannelService) Delete(ctx context.Context, userID entities.UserID, channelID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, channelID); err != nil {
+		msg := fmt.Sprintf("cannot load notification channel with userID [%s] and channelID [%s]", userID, channelID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, channelID); err != nil {
+		msg := fmt.Sprintf("cannot delete notification channel with id [%s] and user id [%s]", channelID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted notification channel with id [%s] and user id [%s]", channelID, userID))
+	return nil
+}
+
+// NotificationChannelStoreParams are parameters for creating a new entities.NotificationChannel
+type NotificationChannelStoreParams struct {
+	UserID     entities.UserID
+	Provider   entities.NotificationChannelProvider
+	Name       string
+	WebhookURL string
+	Events     pq.StringArray
+}
+
+// Store a new entities.NotificationChannel
+func (service *NotificationChannelService) Store(ctx context.Context, params *NotificationChannelStoreParams) (*entities.NotificationChannel, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	channel := &entities.NotificationChannel{
+		ID:         uuid.New(),
+		UserID:     params.UserID,
+		Provider:   params.Provider,
+		Name:       params.Name,
+		WebhookURL: params.WebhookURL,
+		Events:     params.Events,
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, channel); err != nil {
+		msg := fmt.Sprintf("cannot save notification channel with id [%s]", channel.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("[%s] notification channel saved with id [%s] for user [%s] in the [%T]", channel.Provider, channel.ID, channel.UserID, service.repository))
+	return channel, nil
+}
+
+// NotificationChannelUpdateParams are parameters for updating an entities.NotificationChannel
+type NotificationChannelUpdateParams struct {
+	UserID     entities.UserID
+	ChannelID  uuid.UUID
+	Provider   entities.NotificationChannelProvider
+	Name       string
+	WebhookURL string
+	Events     pq.StringArray
+}
+
+// Update an entities.NotificationChannel
+func (service *NotificationChannelService) Update(ctx context.Context, params *NotificationChannelUpdateParams) (*entities.NotificationChannel, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	channel, err := service.repository.Load(ctx, params.UserID, params.ChannelID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification channel with userID [%s] and channelID [%s]", params.UserID, params.ChannelID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	channel.Provider = params.Provider
+	channel.Name = params.Name
+	channel.WebhookURL = params.WebhookURL
+	channel.Events = params.Events
+	channel.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, channel); err != nil {
+		msg := fmt.Sprintf("cannot save notification channel with id [%s] after update", channel.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("notification channel updated with id [%s] in the [%T]", channel.ID, service.repository))
+	return channel, nil
+}
+
+// NotifyPhoneOffline sends an alert to the notification channels of a user when a phone is offline
+func (service *NotificationChannelService) NotifyPhoneOffline(ctx context.Context, payload *events.PhoneHeartbeatOfflinePayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	alert := &NotificationChannelAlert{
+		Event:    events.EventTypePhoneHeartbeatOffline,
+		Title:    "📵 Phone is offline",
+		Summary:  fmt.Sprintf("The phone with number %s has not sent a heartbeat since %s. Messages will not be sent until the phone is back online.", service.getFormattedNumber(ctxLogger, payload.Owner), payload.LastHeartbeatTimestamp.UTC().Format(time.RFC1123)),
+		Severity: NotificationChannelAlertSeverityCritical,
+		Facts: []NotificationChannelFact{
+			{Name: "Phone", Value: service.getFormattedNumber(ctxLogger, payload.Owner)},
+			{Name: "Last Heartbeat", Value: payload.LastHeartbeatTimestamp.UTC().Format(time.RFC1123)},
+			{Name: "Checked At", Value: payload.Timestamp.UTC().Format(time.RFC1123)},
+		},
+		ActionTitle: "View Heartbeats",
+		ActionURL:   service.getAppURL("/heartbeats/" + url.PathEscape(payload.Owner)),
+	}
+
+	if err := service.send(ctx, payload.UserID, payload.Owner, alert, 0); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert for phone [%s] and user [%s]", alert.Event, payload.PhoneID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// NotifyPhoneBalanceLow sends an alert to the notification channels of a user when the balance of the SIM of a phone is low
+func (service *NotificationChannelService) NotifyPhoneBalanceLow(ctx context.Context, payload *events.PhoneBalanceLowPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	balance := strings.TrimSpace(fmt.Sprintf("%.2f %s", payload.Amount, payload.Currency))
+	facts := []NotificationChannelFact{
+		{Name: "Phone", Value: service.getFormattedNumber(ctxLogger, payload.Owner)},
+		{Name: "SIM", Value: payload.SIM.String()},
+		{Name: "Balance", Value: balance},
+		{Name: "Threshold", Value: strings.TrimSpace(fmt.Sprintf("%.2f %s", payload.Threshold, payload.Currency))},
+	}
+	if payload.PlanExpiresAt != nil {
+		facts = append(facts, NotificationChannelFact{Name: "Plan Expires", Value: payload.PlanExpiresAt.UTC().Format(time.RFC1123)})
+	}
+	facts = append(facts, NotificationChannelFact{Name: "Reported At", Value: payload.Timestamp.UTC().Format(time.RFC1123)})
+
+	alert := &NotificationChannelAlert{
+		Event:       events.EventTypePhoneBalanceLow,
+		Title:       "💸 Balance is low",
+		Summary:     fmt.Sprintf("The balance of the phone with number %s is %s. Top up the SIM or messages will fail to send when the balance runs out.", service.getFormattedNumber(ctxLogger, payload.Owner), balance),
+		Severity:    NotificationChannelAlertSeverityWarning,
+		Facts:       facts,
+		ActionTitle: "View Settings",
+		ActionURL:   service.getAppURL("/settings"),
+	}
+
+	if err := service.send(ctx, payload.UserID, payload.Owner, alert, 0); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert for phone [%s] and user [%s]", alert.Event, payload.PhoneID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// NotifyMessageFailed sends an alert to the notification channels of a user when a message could not be sent
+func (service *NotificationChannelService) NotifyMessageFailed(ctx context.Context, payload *events.MessageSendFailedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	alert := &NotificationChannelAlert{
+		Event:    events.EventTypeMessageSendFailed,
+		Title:    "⚠ Message failed",
+		Summary:  fmt.Sprintf("The message from %s to %s could not be sent: %s", service.getFormattedNumber(ctxLogger, payload.Owner), service.getFormattedNumber(ctxLogger, payload.Contact), payload.ErrorMessage),
+		Severity: NotificationChannelAlertSeverityWarning,
+		Facts: []NotificationChannelFact{
+			{Name: "From", Value: service.getFormattedNumber(ctxLogger, payload.Owner)},
+			{Name: "To", Value: service.getFormattedNumber(ctxLogger, payload.Contact)},
+			{Name: "Error", Value: payload.ErrorMessage},
+			{Name: "Message ID", Value: payload.ID.String()},
+			{Name: "Failed At", Value: payload.Timestamp.UTC().Format(time.RFC1123)},
+		},
+		ActionTitle: "View Messages",
+		ActionURL:   service.getAppURL("/search-messages"),
+	}
+
+	if err := service.send(ctx, payload.UserID, payload.Owner, alert, fifteenMinuteTimeout); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert for message [%s] and user [%s]", alert.Event, payload.ID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// send delivers the alert to every channel of the user which is subscribed to the event. When throttle is not zero,
+// only one alert is sent to a channel for the same owner within the throttle duration.
+func (service *NotificationChannelService) send(ctx context.Context, userID entities.UserID, owner string, alert *NotificationChannelAlert, throttle time.Duration) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	channels, err := service.repository.LoadByEvent(ctx, userID, alert.Event)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load notification channels for userID [%s] and event [%s]", userID, alert.Event)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if len(channels) == 0 {
+		ctxLogger.Info(fmt.Sprintf("user [%s] has no notification channel subscribed to event [%s]", userID, alert.Event))
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for _, channel := range channels {
+		sender, ok := service.senders[channel.Provider]
+		if !ok {
+			ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("no sender for provider [%s] of notification channel [%s]", channel.Provider, channel.ID)))
+			continue
+		}
+
+		cacheKey := fmt.Sprintf("notification-channel.%s.%s.%s", alert.Event, channel.ID, owner)
+		if throttle > 0 {
+			if _, err = service.cache.Get(ctx, cacheKey); err == nil {
+				ctxLogger.Info(fmt.Sprintf("[%s] alert already sent to notification channel [%s] for owner [%s]", alert.Event, channel.ID, owner))
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(channel *entities.NotificationChannel, sender NotificationChannelSender, cacheKey string) {
+			defer wg.Done()
+
+			requestCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+
+			if err := sender.Send(requestCtx, channel, alert); err != nil {
+				ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send [%s] alert to [%s] notification channel [%s]", alert.Event, channel.Provider, channel.ID)))
+				return
+			}
+
+			if throttle > 0 {
+				if err := service.cache.Set(ctx, cacheKey, "", throttle); err != nil {
+					ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot set item in cache with key [%s]", cacheKey)))
+				}
+			}
+
+			ctxLogger.Info(fmt.Sprintf("sent [%s] alert to [%s] notification channel [%s] for user [%s]", alert.Event, channel.Provider, channel.ID, channel.UserID))
+		}(channel, sender, cacheKey)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (service *NotificationChannelService) getAppURL(path string) string {
+	if service.appURL == "" {
+		return ""
+	}
+	return service.appURL + path
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/carlmjohnson/requests"
+	"github.com/palantir/stacktrace"
+)
+
+type openAILLMProvider struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	client  *http.Client
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+// NewOpenAILLMProvider creates an LLMProvider which uses the OpenAI chat completions API.
+// The baseURL can point to any server with an OpenAI compatible API e.g. a self-hosted model.
+func NewOpenAILLMProvider(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+	apiKey string,
+	model string,
+	baseURL string,
+) LLMProvider {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = "https://api.openai.com"
+	}
+
+	if strings.TrimSpace(model) == "" {
+		model = "gpt-4o-mini"
+	}
+
+	return &openAILLMProvider{
+		logger:  logger.WithService(fmt.Sprintf("%T", &openAILLMProvider{})),
+		tracer:  tracer,
+		client:  client,
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Complete the conversation using the v1 chat completions API
+func (provider *openAILLMProvider) Complete(ctx context.Context, instructions string, messages []entities.MessageThreadContextMessage) (string, error) {
+	ctx, span := provider.tracer.Start(ctx)
+	defer span.End()
+
+	type chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+
+	chat := []chatMessage{{Role: string(entities.MessageThreadContextRoleSystem), Content: instructions}}
+	for _, message := range messages {
+		chat = append(chat, chatMessage{Role: string(message.Role), Content: message.Content})
+	}
+
+	var response struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+
+	err := requests.
+		URL(provider.baseURL + "/v1/chat/completions").
+		Client(provider.client).
+		Bearer(provider.apiKey).
+		BodyJSON(map[string]any{
+			"model":    provider.model,
+			"messages": chat,
+		}).
+		ToJSON(&response).
+		Fetch(ctx)
+	if err != nil {
+		msg := fmt.Sprintf("cannot complete conversation with [%d] messages using openai model [%s]", len(messages), provider.model)
+		return "", provider.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(response.Choices) == 0 {
+		msg := fmt.Sprintf("openai model [%s] returned no choices", provider.model)
+		return "", provider.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
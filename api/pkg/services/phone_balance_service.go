@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneBalanceService manages the credit balance and plan expiry of the SIM of a phone which is reported by the app
+type PhoneBalanceService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	repository      repositories.PhoneBalanceRepository
+	phoneRepository repositories.PhoneRepository
+	dispatcher      *EventDispatcher
+}
+
+// NewPhoneBalanceService creates a new PhoneBalanceService
+func NewPhoneBalanceService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhoneBalanceRepository,
+	phoneRepository repositories.PhoneRepository,
+	dispatcher *EventDispatcher,
+) (s *PhoneBalanceService) {
+	return &PhoneBalanceService{
+		logger:          logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		repository:      repository,
+		phoneRepository: phoneRepository,
+		dispatcher:      dispatcher,
+	}
+}
+
+// PhoneBalanceStoreParams are parameters for storing a new entities.PhoneBalance
+type PhoneBalanceStoreParams struct {
+	Source        string
+	UserID        entities.UserID
+	PhoneID       uuid.UUID
+	Amount        float64
+	Currency      string
+	PlanExpiresAt *time.Time
+	Content       string
+	ReportedAt    time.Time
+}
+
+// Store a balance which is reported by the app on a phone. The events.EventTypePhoneBalanceLow event is dispatched
+// only when the balance drops below the threshold so that the user is not alerted on every report.
+func (service *PhoneBalanceService) Store(ctx context.Context, params *PhoneBalanceStoreParams) (*entities.PhoneBalance, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	previous, err := service.repository.LoadLatest(ctx, phone.UserID, phone.ID)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load the latest balance of phone [%s] for user [%s]", phone.ID, phone.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	balance := &entities.PhoneBalance{
+		ID:            uuid.New(),
+		UserID:        phone.UserID,
+		PhoneID:       phone.ID,
+		Owner:         phone.PhoneNumber,
+		Amount:        params.Amount,
+		Currency:      params.Currency,
+		PlanExpiresAt: params.PlanExpiresAt,
+		Content:       params.Content,
+		ReportedAt:    params.ReportedAt,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, balance); err != nil {
+		msg := fmt.Sprintf("cannot save phone balance with id [%s]", balance.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone balance saved with id [%s] and amount [%.2f %s] for phone [%s]", balance.ID, balance.Amount, balance.Currency, balance.PhoneID))
+
+	if !balance.IsBelow(phone.LowBalanceThreshold) || (previous != nil && previous.IsBelow(phone.LowBalanceThreshold)) {
+		return balance, nil
+	}
+
+	return balance, service.dispatchPhoneBalanceLowEvent(ctx, params.Source, phone, balance)
+}
+
+func (service *PhoneBalanceService) dispatchPhoneBalanceLowEvent(ctx context.Context, source string, phone *entities.Phone, balance *entities.PhoneBalance) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	event, err := service.createEvent(events.EventTypePhoneBalanceLow, source, &events.PhoneBalanceLowPayload{
+		PhoneID:       phone.ID,
+		UserID:        phone.UserID,
+		Owner:         phone.PhoneNumber,
+		SIM:           phone.SIM,
+		BalanceID:     balance.ID,
+		Amount:        balance.Amount,
+		Currency:      balance.Currency,
+		Threshold:     phone.LowBalanceThreshold,
+		PlanExpiresAt: balance.PlanExpiresAt,
+		Timestamp:     balance.ReportedAt,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for balance [%s] of phone [%s]", events.EventTypePhoneBalanceLow, balance.ID, phone.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for phone with id [%s]", event.Type(), phone.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Index fetches the entities.PhoneBalance of a phone
+func (service *PhoneBalanceService) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params repositories.IndexParams) ([]*entities.PhoneBalance, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	balances, err := service.repository.Index(ctx, userID, phoneID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch balances of phone [%s] with params [%+#v]", phoneID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] balances of phone [%s] with params [%+#v]", len(balances), phoneID, params))
+	return balances, nil
+}
+
+// DeleteAllForUser deletes all entities.PhoneBalance for an entities.UserID.
+func (service *PhoneBalanceService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete [entities.PhoneBalance] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhoneBalance] for user with ID [%s]", userID))
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneCrashService manages the crashes and ANRs which are reported by the app on a phone
+type PhoneCrashService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	repository      repositories.PhoneCrashRepository
+	phoneRepository repositories.PhoneRepository
+}
+
+// NewPhoneCrashService creates a new PhoneCrashService
+func NewPhoneCrashService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhoneCrashRepository,
+	phoneRepository repositories.PhoneRepository,
+) (s *PhoneCrashService) {
+	return &PhoneCrashService{
+		logger:          logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		repository:      repository,
+		phoneRepository: phoneRepository,
+	}
+}
+
+// PhoneCrashStoreParams are parameters for storing a new entities.PhoneCrash
+type PhoneCrashStoreParams struct {
+	UserID     entities.UserID
+	PhoneID    uuid.UUID
+	Type       entities.PhoneCrashType
+	Message    string
+	StackTrace string
+	AppVersion string
+	OccurredAt time.Time
+}
+
+// Store a crash or ANR which is reported by the app on a phone
+func (service *PhoneCrashService) Store(ctx context.Context, params *PhoneCrashStoreParams) (*entities.PhoneCrash, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	crash := &entities.PhoneCrash{
+		ID:         uuid.New(),
+		UserID:     phone.UserID,
+		PhoneID:    phone.ID,
+		Owner:      phone.PhoneNumber,
+		Type:       params.Type,
+		Message:    params.Message,
+		StackTrace: params.StackTrace,
+		AppVersion: params.AppVersion,
+		OccurredAt: params.OccurredAt,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, crash); err != nil {
+		msg := fmt.Sprintf("cannot save phone crash with id [%s]", crash.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone crash saved with id [%s] and type [%s] for phone [%s]", crash.ID, crash.Type, crash.PhoneID))
+	return crash, nil
+}
+
+// Index fetches the entities.PhoneCrash of a phone
+func (service *PhoneCrashService) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params repositories.IndexParams) ([]*entities.PhoneCrash, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	crashes, err := service.repository.Index(ctx, userID, phoneID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch crashes of phone [%s] with params [%+#v]", phoneID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] crashes of phone [%s] with params [%+#v]", len(crashes), phoneID, params))
+	return crashes, nil
+}
+
+// DeleteAllForUser deletes all entities.PhoneCrash for an entities.UserID.
+func (service *PhoneCrashService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete [entities.PhoneCrash] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhoneCrash] for user with ID [%s]", userID))
+	return nil
+}
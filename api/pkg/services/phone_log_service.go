@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneLogService manages the logs which are uploaded by the app on a phone
+type PhoneLogService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.PhoneLogRepository
+	phoneRepository   repositories.PhoneRepository
+	attachmentService *AttachmentService
+	dispatcher        *EventDispatcher
+}
+
+// NewPhoneLogService creates a new PhoneLogService
+func NewPhoneLogService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhoneLogRepository,
+	phoneRepository repositories.PhoneRepository,
+	attachmentService *AttachmentService,
+	dispatcher *EventDispatcher,
+) (s *PhoneLogService) {
+	return &PhoneLogService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		phoneRepository:   phoneRepository,
+		attachmentService: attachmentService,
+		dispatcher:        dispatcher,
+	}
+}
+
+// Request asks the app on a phone to upload its recent logs
+func (service *PhoneLogService) Request(ctx context.Context, source string, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneLogRequest, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	timestamp := time.Now().UTC()
+	event, err := service.createEvent(events.EventTypePhoneLogsRequested, source, events.PhoneLogsRequestedPayload{
+		PhoneID:   phone.ID,
+		UserID:    phone.UserID,
+		Timestamp: timestamp,
+		Owner:     phone.PhoneNumber,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create event when logs are requested from phone [%s] for user [%s]", phone.ID, phone.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for phone with id [%s]", event.Type(), phone.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("requested the logs of phone [%s] for user [%s]", phone.ID, userID))
+	return &entities.PhoneLogRequest{
+		PhoneID:     phone.ID,
+		UserID:      phone.UserID,
+		Owner:       phone.PhoneNumber,
+		RequestedAt: timestamp,
+	}, nil
+}
+
+// PhoneLogStoreParams are parameters for uploading a new entities.PhoneLog
+type PhoneLogStoreParams struct {
+	UserID      entities.UserID
+	PhoneID     uuid.UUID
+	Name        string
+	ContentType string
+	Size        int64
+	Reader      io.Reader
+	BaseURL     string
+}
+
+// Store uploads the logs of a phone as an entities.Attachment
+func (service *PhoneLogService) Store(ctx context.Context, params *PhoneLogStoreParams) (*entities.PhoneLog, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	attachment, err := service.attachmentService.Store(ctx, &AttachmentStoreParams{
+		UserID:      phone.UserID,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		Reader:      params.Reader,
+		BaseURL:     params.BaseURL,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot store logs [%s] of phone [%s] as an attachment", params.Name, phone.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	log := &entities.PhoneLog{
+		ID:           uuid.New(),
+		UserID:       phone.UserID,
+		PhoneID:      phone.ID,
+		AttachmentID: attachment.ID,
+		Name:         attachment.Name,
+		Size:         attachment.Size,
+		URL:          attachment.URL,
+		CreatedAt:    attachment.CreatedAt,
+	}
+
+	if err = service.repository.Store(ctx, log); err != nil {
+		msg := fmt.Sprintf("cannot save phone log with id [%s]", log.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone log saved with id [%s] and attachment [%s] for phone [%s]", log.ID, log.AttachmentID, log.PhoneID))
+	return log, nil
+}
+
+// Index fetches the entities.PhoneLog of a phone
+func (service *PhoneLogService) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params repositories.IndexParams) ([]*entities.PhoneLog, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	logs, err := service.repository.Index(ctx, userID, phoneID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch logs of phone [%s] with params [%+#v]", phoneID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] logs of phone [%s] with params [%+#v]", len(logs), phoneID, params))
+	return logs, nil
+}
+
+// DeleteAllForUser deletes all entities.PhoneLog for an entities.UserID.
+func (service *PhoneLogService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete [entities.PhoneLog] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhoneLog] for user with ID [%s]", userID))
+	return nil
+}
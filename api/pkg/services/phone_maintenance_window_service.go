@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhoneMaintenanceWindowService manages the maintenance windows during which a phone is expected to be offline
+type PhoneMaintenanceWindowService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	repository      repositories.PhoneMaintenanceWindowRepository
+	phoneRepository repositories.PhoneRepository
+}
+
+// NewPhoneMaintenanceWindowService creates a new PhoneMaintenanceWindowService
+func NewPhoneMaintenanceWindowService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhoneMaintenanceWindowRepository,
+	phoneRepository repositories.PhoneRepository,
+) (s *PhoneMaintenanceWindowService) {
+	return &PhoneMaintenanceWindowService{
+		logger:          logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		repository:      repository,
+		phoneRepository: phoneRepository,
+	}
+}
+
+// PhoneMaintenanceWindowStoreParams are parameters for storing a new entities.PhoneMaintenanceWindow
+type PhoneMaintenanceWindowStoreParams struct {
+	UserID   entities.UserID
+	PhoneID  uuid.UUID
+	Reason   string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// Store a maintenance window of a phone
+func (service *PhoneMaintenanceWindowService) Store(ctx context.Context, params *PhoneMaintenanceWindowStoreParams) (*entities.PhoneMaintenanceWindow, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	window := &entities.PhoneMaintenanceWindow{
+		ID:        uuid.New(),
+		UserID:    phone.UserID,
+		PhoneID:   phone.ID,
+		Owner:     phone.PhoneNumber,
+		Reason:    params.Reason,
+		StartsAt:  params.StartsAt,
+		EndsAt:    params.EndsAt,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, window); err != nil {
+		msg := fmt.Sprintf("cannot save phone maintenance window with id [%s]", window.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("maintenance window saved with id [%s] from [%s] to [%s] for phone [%s]", window.ID, window.StartsAt, window.EndsAt, window.PhoneID))
+	return window, nil
+}
+
+// Index fetches the entities.PhoneMaintenanceWindow of a phone
+func (service *PhoneMaintenanceWindowService) Index(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, params repositories.IndexParams) ([]*entities.PhoneMaintenanceWindow, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	windows, err := service.repository.Index(ctx, userID, phoneID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch maintenance windows of phone [%s] with params [%+#v]", phoneID, params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] maintenance windows of phone [%s] with params [%+#v]", len(windows), phoneID, params))
+	return windows, nil
+}
+
+// Delete an entities.PhoneMaintenanceWindow of a phone
+func (service *PhoneMaintenanceWindowService) Delete(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, windowID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, phoneID, windowID); err != nil {
+		msg := fmt.Sprintf("cannot load maintenance window with userID [%s] and windowID [%s]", userID, windowID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, windowID); err != nil {
+		msg := fmt.Sprintf("cannot delete maintenance window with id [%s] and user id [%s]", windowID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted maintenance window with id [%s] of phone [%s] and user id [%s]", windowID, phoneID, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.PhoneMaintenanceWindow for an entities.UserID.
+func (service *PhoneMaintenanceWindowService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete [entities.PhoneMaintenanceWindow] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhoneMaintenanceWindow] for user with ID [%s]", userID))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
@@ -25,6 +26,8 @@ type PhoneNotificationService struct {
 	phoneNotificationRepository repositories.PhoneNotificationRepository
 	phoneRepository             repositories.PhoneRepository
 	messagingClient             *messaging.Client
+	unifiedPushClient           *http.Client
+	socketRegistry              *PhoneSocketRegistry
 	eventDispatcher             *EventDispatcher
 }
 
@@ -33,16 +36,20 @@ func NewNotificationService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	messagingClient *messaging.Client,
+	unifiedPushClient *http.Client,
 	phoneRepository repositories.PhoneRepository,
 	phoneNotificationRepository repositories.PhoneNotificationRepository,
+	socketRegistry *PhoneSocketRegistry,
 	dispatcher *EventDispatcher,
 ) (s *PhoneNotificationService) {
 	return &PhoneNotificationService{
 		logger:                      logger.WithService(fmt.Sprintf("%T", s)),
 		tracer:                      tracer,
 		messagingClient:             messagingClient,
+		unifiedPushClient:           unifiedPushClient,
 		phoneNotificationRepository: phoneNotificationRepository,
 		phoneRepository:             phoneRepository,
+		socketRegistry:              socketRegistry,
 		eventDispatcher:             dispatcher,
 	}
 }
@@ -72,6 +79,20 @@ func (service *PhoneNotificationService) SendHeartbeatFCM(ctx context.Context, p
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	if service.sendSocketCommand(ctx, phone, &PhoneSocketCommand{Type: PhoneSocketCommandHeartbeat, Timestamp: time.Now().UTC()}) {
+		ctxLogger.Info(fmt.Sprintf("successfully sent heartbeat over websocket to phone with ID [%s] for user [%s] and monitor [%s]", payload.PhoneID, payload.UserID, payload.MonitorID))
+		return nil
+	}
+
+	if phone.UsesUnifiedPush() {
+		if err = service.sendUnifiedPush(ctx, phone, map[string]string{"KEY_HEARTBEAT_ID": time.Now().UTC().Format(time.RFC3339)}); err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send heartbeat unified push to phone with id [%s] for user [%s]", phone.ID, phone.UserID)))
+			return nil
+		}
+		ctxLogger.Info(fmt.Sprintf("successfully sent heartbeat unified push to phone with ID [%s] for user [%s] and monitor [%s]", payload.PhoneID, payload.UserID, payload.MonitorID))
+		return nil
+	}
+
 	if phone.FcmToken == nil {
 		msg := fmt.Sprintf("phone with id [%s] has no FCM token", phone.ID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -96,6 +117,96 @@ func (service *PhoneNotificationService) SendHeartbeatFCM(ctx context.Context, p
 	return nil
 }
 
+// SendResyncFCM requests the app on a phone to upload the results of the messages which the server has not received
+func (service *PhoneNotificationService) SendResyncFCM(ctx context.Context, payload *events.PhoneResyncRequestedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, payload.UserID, payload.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", payload.UserID, payload.PhoneID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if phone.UsesUnifiedPush() {
+		if err = service.sendUnifiedPush(ctx, phone, map[string]string{"KEY_RESYNC_ID": payload.Timestamp.Format(time.RFC3339)}); err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send resync unified push to phone with id [%s] for user [%s]", phone.ID, phone.UserID)))
+			return nil
+		}
+		ctxLogger.Info(fmt.Sprintf("successfully sent resync unified push to phone with ID [%s] for user [%s]", payload.PhoneID, payload.UserID))
+		return nil
+	}
+
+	if phone.FcmToken == nil {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("phone with id [%s] has no FCM token to send the resync request", phone.ID)))
+		return nil
+	}
+
+	result, err := service.messagingClient.Send(ctx, &messaging.Message{
+		Data: map[string]string{
+			"KEY_RESYNC_ID": payload.Timestamp.Format(time.RFC3339),
+		},
+		Android: &messaging.AndroidConfig{
+			Priority: "high",
+		},
+		Token: *phone.FcmToken,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot send resync FCM to phone with id [%s] for user [%s]", phone.ID, phone.UserID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return nil
+	}
+
+	ctxLogger.Info(fmt.Sprintf("successfully sent resync FCM [%s] to phone with ID [%s] for user [%s]", result, payload.PhoneID, payload.UserID))
+	return nil
+}
+
+// SendLogsRequestFCM requests the app on a phone to upload its recent logs
+func (service *PhoneNotificationService) SendLogsRequestFCM(ctx context.Context, payload *events.PhoneLogsRequestedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.phoneRepository.LoadByID(ctx, payload.UserID, payload.PhoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", payload.UserID, payload.PhoneID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if phone.UsesUnifiedPush() {
+		data := map[string]string{"KEY_LOGS_REQUEST_ID": payload.Timestamp.Format(time.RFC3339), "KEY_PHONE_ID": payload.PhoneID.String()}
+		if err = service.sendUnifiedPush(ctx, phone, data); err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send logs request unified push to phone with id [%s] for user [%s]", phone.ID, phone.UserID)))
+			return nil
+		}
+		ctxLogger.Info(fmt.Sprintf("successfully sent logs request unified push to phone with ID [%s] for user [%s]", payload.PhoneID, payload.UserID))
+		return nil
+	}
+
+	if phone.FcmToken == nil {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("phone with id [%s] has no FCM token to send the logs request", phone.ID)))
+		return nil
+	}
+
+	result, err := service.messagingClient.Send(ctx, &messaging.Message{
+		Data: map[string]string{
+			"KEY_LOGS_REQUEST_ID": payload.Timestamp.Format(time.RFC3339),
+			"KEY_PHONE_ID":        payload.PhoneID.String(),
+		},
+		Android: &messaging.AndroidConfig{
+			Priority: "high",
+		},
+		Token: *phone.FcmToken,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot send logs request FCM to phone with id [%s] for user [%s]", phone.ID, phone.UserID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return nil
+	}
+
+	ctxLogger.Info(fmt.Sprintf("successfully sent logs request FCM [%s] to phone with ID [%s] for user [%s]", result, payload.PhoneID, payload.UserID))
+	return nil
+}
+
 // PhoneNotificationSendParams are parameters for sending a notification
 type PhoneNotificationSendParams struct {
 	UserID              entities.UserID
@@ -111,12 +222,44 @@ func (service *PhoneNotificationService) Send(ctx context.Context, params *Phone
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	// the notifications are sent in slots which respect the send rate of the phone, every slot sends the pending
+	// notification with the highest priority so urgent messages jump ahead of the messages queued on the phone.
+	notification, err := service.phoneNotificationRepository.Next(ctx, params.UserID, params.PhoneID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("notification [%s] was already sent in an earlier slot of phone [%s]", params.PhoneNotificationID, params.PhoneID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the next notification for phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if notification.ID != params.PhoneNotificationID {
+		ctxLogger.Info(fmt.Sprintf("notification [%s] with priority [%s] is sent before notification [%s] on phone [%s]", notification.ID, notification.Priority, params.PhoneNotificationID, params.PhoneID))
+		params.PhoneNotificationID = notification.ID
+		params.MessageID = notification.MessageID
+		params.ScheduledAt = notification.ScheduledAt
+	}
+
 	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
 	if err != nil {
 		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
 		return service.handleNotificationFailed(ctx, errors.New(msg), params)
 	}
 
+	if service.sendSocketCommand(ctx, phone, &PhoneSocketCommand{Type: PhoneSocketCommandMessageSend, MessageID: &params.MessageID, Timestamp: time.Now().UTC()}) {
+		return service.handleNotificationSent(ctx, phone, "websocket", params)
+	}
+
+	if phone.UsesUnifiedPush() {
+		if err = service.sendUnifiedPush(ctx, phone, map[string]string{"KEY_MESSAGE_ID": params.MessageID.String()}); err != nil {
+			ctxLogger.Warn(stacktrace.Propagate(err, "cannot send unified push to phone"))
+			msg := fmt.Sprintf("cannot send notification to the UnifiedPush endpoint of your phone [%s]. Register the httpSMS app with your UnifiedPush distributor again.", phone.PhoneNumber)
+			return service.handleNotificationFailed(ctx, errors.New(msg), params)
+		}
+		return service.handleNotificationSent(ctx, phone, "unified_push", params)
+	}
+
 	if phone.FcmToken == nil {
 		msg := fmt.Sprintf("phone with id [%s] has no FCM token", phone.ID)
 		return service.handleNotificationFailed(ctx, errors.New(msg), params)
@@ -142,6 +285,34 @@ func (service *PhoneNotificationService) Send(ctx context.Context, params *Phone
 	return service.handleNotificationSent(ctx, phone, result, params)
 }
 
+// sendSocketCommand sends a command to a phone which holds a WebSocket connection to this process. It returns false when
+// the command has to be sent with FCM because the phone is not connected or the connection is broken.
+func (service *PhoneNotificationService) sendSocketCommand(ctx context.Context, phone *entities.Phone, command *PhoneSocketCommand) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	ok, err := service.socketRegistry.Send(ctx, phone.ID, command)
+	if err != nil {
+		msg := fmt.Sprintf("cannot send [%s] command over websocket to phone with ID [%s] for user [%s]", command.Type, phone.ID, phone.UserID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return false
+	}
+	return ok
+}
+
+// sendUnifiedPush posts the same data as the FCM data message to the UnifiedPush endpoint of a phone https://unifiedpush.org/developers/spec/server/
+func (service *PhoneNotificationService) sendUnifiedPush(ctx context.Context, phone *entities.Phone, data map[string]string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := postNotificationChannelPayload(ctx, service.unifiedPushClient, *phone.UnifiedPushEndpoint, data); err != nil {
+		msg := fmt.Sprintf("cannot send unified push to phone with id [%s] for user [%s]", phone.ID, phone.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 // PhoneNotificationScheduleParams are parameters for sending a notification
 type PhoneNotificationScheduleParams struct {
 	UserID    entities.UserID
@@ -151,6 +322,8 @@ type PhoneNotificationScheduleParams struct {
 	Contact   string
 	Content   string
 	SIM       entities.SIM
+	Category  entities.MessageCategory
+	Priority  entities.MessagePriority
 	MessageID uuid.UUID
 }
 
@@ -173,12 +346,13 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 		UserID:      params.UserID,
 		PhoneID:     phone.ID,
 		Status:      entities.PhoneNotificationStatusPending,
+		Priority:    entities.MessagePrioritySanitized(params.Priority),
 		ScheduledAt: time.Now().UTC(),
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
 
-	if err = service.phoneNotificationRepository.Schedule(ctx, phone.MessagesPerMinute, notification); err != nil {
+	if err = service.phoneNotificationRepository.Schedule(ctx, params.Category.MessagesPerMinute(phone.MessagesPerMinute), notification); err != nil {
 		msg := fmt.Sprintf("cannot schedule notification for message [%s] to phone [%s]", params.MessageID, phone.ID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// PhonePoolService manages the pools of phones which share the outgoing messages that are addressed from the pool
+type PhonePoolService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.PhonePoolRepository
+}
+
+// NewPhonePoolService creates a new PhonePoolService
+func NewPhonePoolService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhonePoolRepository,
+) (s *PhonePoolService) {
+	return &PhonePoolService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// DeleteAllForUser deletes all entities.PhonePool for an entities.UserID.
+func (service *PhonePoolService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.PhonePool] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhonePool] for user with ID [%s]", userID))
+	return nil
+}
+
+// RemovePhoneNumber removes a phone number which was deleted from all the phone pools of a user
+func (service *PhonePoolService) RemovePhoneNumber(ctx context.Context, userID entities.UserID, phoneNumber string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pools, err := service.repository.LoadAll(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the phone pools of user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, pool := range pools {
+		if !slices.Contains(pool.PhoneNumbers, phoneNumber) {
+			continue
+		}
+
+		pool.PhoneNumbers = slices.DeleteFunc(pool.PhoneNumbers, func(value string) bool { return value == phoneNumber })
+		pool.UpdatedAt = time.Now().UTC()
+		if err = service.repository.Save(ctx, pool); err != nil {
+			msg := fmt.Sprintf("cannot remove phone number [%s] from phone pool with ID [%s]", phoneNumber, pool.ID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		ctxLogger.Info(fmt.Sprintf("removed phone number [%s] from phone pool with ID [%s] for user [%s]", phoneNumber, pool.ID, userID))
+	}
+
+	return nil
+}
+
+// Index fetches the entities.PhonePool for an entities.UserID
+func (service *PhonePoolService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.PhonePool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pools, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch phone pools with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] phone pools with prams [%+#v]", len(pools), params))
+	return pools, nil
+}
+
+// Delete an entities.PhonePool
+func (service *PhonePoolService) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, poolID); err != nil {
+		msg := fmt.Sprintf("cannot load phone pool with userID [%s] and poolID [%s]", userID, poolID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, poolID); err != nil {
+		msg := fmt.Sprintf("cannot delete phone pool with id [%s] and user id [%s]", poolID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted phone pool with id [%s] and user id [%s]", poolID, userID))
+	return nil
+}
+
+// PhonePoolStoreParams are parameters for creating a new entities.PhonePool
+type PhonePoolStoreParams struct {
+	UserID       entities.UserID
+	Name         string
+	Strategy     entities.PhonePoolStrategy
+	PhoneNumbers []string
+}
+
+// Store a new entities.PhonePool
+func (service *PhonePoolService) Store(ctx context.Context, params *PhonePoolStoreParams) (*entities.PhonePool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool := &entities.PhonePool{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Name:         params.Name,
+		Strategy:     params.Strategy,
+		PhoneNumbers: params.PhoneNumbers,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, pool); err != nil {
+		msg := fmt.Sprintf("cannot save phone pool with id [%s]", pool.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone pool saved with id [%s] for user [%s] in the [%T]", pool.ID, pool.UserID, service.repository))
+	return pool, nil
+}
+
+// PhonePoolUpdateParams are parameters for updating an entities.PhonePool
+type PhonePoolUpdateParams struct {
+	UserID       entities.UserID
+	PoolID       uuid.UUID
+	Name         string
+	Strategy     entities.PhonePoolStrategy
+	PhoneNumbers []string
+}
+
+// Update an entities.PhonePool
+func (service *PhonePoolService) Update(ctx context.Context, params *PhonePoolUpdateParams) (*entities.PhonePool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, params.UserID, params.PoolID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone pool with userID [%s] and poolID [%s]", params.UserID, params.PoolID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	pool.Name = params.Name
+	pool.Strategy = params.Strategy
+	pool.PhoneNumbers = params.PhoneNumbers
+	pool.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, pool); err != nil {
+		msg := fmt.Sprintf("cannot save phone pool with id [%s] after update", pool.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone pool updated with id [%s] in the [%T]", pool.ID, service.repository))
+	return pool, nil
+}
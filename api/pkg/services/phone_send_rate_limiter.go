@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+// phoneSendRateLimiterKeyPrefix is the prefix of the redis key which stores the arrival time of the bucket of a phone
+const phoneSendRateLimiterKeyPrefix = "phone-send-rate:"
+
+// phoneSendRateLimiterScript returns the time in milliseconds when the next token of a phone can be used. When ARGV[4]
+// is "1" the token is taken and the arrival time of the bucket is stored until it expires.
+var phoneSendRateLimiterScript = redis.NewScript(`
+local timestamp = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+
+local arrival = tonumber(redis.call("GET", KEYS[1]) or timestamp)
+if arrival < timestamp then
+	arrival = timestamp
+end
+
+local sendAt = arrival - burst
+if sendAt < timestamp then
+	sendAt = timestamp
+end
+
+if ARGV[4] == "1" then
+	redis.call("SET", KEYS[1], arrival + interval)
+	redis.call("PEXPIREAT", KEYS[1], arrival + interval)
+end
+
+return sendAt
+`)
+
+// PhoneSendRateLimiter is a token bucket for each phone which holds as many tokens as the hourly limit of the phone and
+// refills them evenly over the hour. A message which arrives when the bucket is empty is not rejected, it reserves the
+// next token so that the excess messages of a phone are spaced out over the following hour. The buckets are stored in
+// redis so the limit holds across the children of a prefork server, the other instances of the API and restarts.
+type PhoneSendRateLimiter struct {
+	client *redis.Client
+}
+
+// NewPhoneSendRateLimiter creates a new PhoneSendRateLimiter
+func NewPhoneSendRateLimiter(client *redis.Client) *PhoneSendRateLimiter {
+	return &PhoneSendRateLimiter{
+		client: client,
+	}
+}
+
+// Reserve takes a token from the bucket of a phone for a message which should be sent at the timestamp and returns the
+// time when the message can be sent. It is the timestamp when the bucket has a token, otherwise it is the time when the
+// reserved token is refilled.
+func (limiter *PhoneSendRateLimiter) Reserve(ctx context.Context, phoneID uuid.UUID, messagesPerHour uint, timestamp time.Time) (time.Time, error) {
+	return limiter.next(ctx, phoneID, messagesPerHour, timestamp, true)
+}
+
+// Peek returns the time when a message which should be sent at the timestamp can be sent without taking a token from
+// the bucket of the phone
+func (limiter *PhoneSendRateLimiter) Peek(ctx context.Context, phoneID uuid.UUID, messagesPerHour uint, timestamp time.Time) (time.Time, error) {
+	return limiter.next(ctx, phoneID, messagesPerHour, timestamp, false)
+}
+
+// next returns the time when the next token of a phone can be used and takes the token when reserve is true
+func (limiter *PhoneSendRateLimiter) next(ctx context.Context, phoneID uuid.UUID, messagesPerHour uint, timestamp time.Time, reserve bool) (time.Time, error) {
+	if messagesPerHour == 0 {
+		return timestamp, nil
+	}
+
+	interval := time.Hour / time.Duration(messagesPerHour)
+	burst := time.Duration(messagesPerHour-1) * interval
+
+	flag := "0"
+	if reserve {
+		flag = "1"
+	}
+
+	sendAt, err := phoneSendRateLimiterScript.Run(
+		ctx,
+		limiter.client,
+		[]string{phoneSendRateLimiterKeyPrefix + phoneID.String()},
+		timestamp.UnixMilli(),
+		interval.Milliseconds(),
+		burst.Milliseconds(),
+		flag,
+	).Int64()
+	if err != nil {
+		return timestamp, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch the next token of phone [%s] from redis", phoneID))
+	}
+
+	if sendAt <= timestamp.UnixMilli() {
+		return timestamp, nil
+	}
+	return time.UnixMilli(sendAt).UTC(), nil
+}
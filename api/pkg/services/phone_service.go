@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
@@ -21,10 +22,12 @@ import (
 // PhoneService is handles phone requests
 type PhoneService struct {
 	service
-	logger     telemetry.Logger
-	tracer     telemetry.Tracer
-	repository repositories.PhoneRepository
-	dispatcher *EventDispatcher
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.PhoneRepository
+	messageRepository repositories.MessageRepository
+	webhookRepository repositories.WebhookRepository
+	dispatcher        *EventDispatcher
 }
 
 // NewPhoneService creates a new PhoneService
@@ -32,13 +35,17 @@ func NewPhoneService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	repository repositories.PhoneRepository,
+	messageRepository repositories.MessageRepository,
+	webhookRepository repositories.WebhookRepository,
 	dispatcher *EventDispatcher,
 ) (s *PhoneService) {
 	return &PhoneService{
-		logger:     logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:     tracer,
-		dispatcher: dispatcher,
-		repository: repository,
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		dispatcher:        dispatcher,
+		repository:        repository,
+		messageRepository: messageRepository,
+		webhookRepository: webhookRepository,
 	}
 }
 
@@ -73,6 +80,252 @@ func (service *PhoneService) Index(ctx context.Context, authUser entities.AuthUs
 	return phones, nil
 }
 
+// Queue fetches the outgoing messages of a phone which are pending, scheduled or sending in the order they will be sent by the phone
+func (service *PhoneService) Queue(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, limit int) ([]*entities.PhoneQueueEntry, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	messages, err := service.messageRepository.Queue(ctx, userID, phone.PhoneNumber, limit)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the queued messages of phone [%s] for user [%s]", phone.ID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	timestamp := time.Now().UTC()
+	entries := make([]*entities.PhoneQueueEntry, 0, len(messages))
+	for index, message := range messages {
+		entries = append(entries, &entities.PhoneQueueEntry{
+			Position:   index + 1,
+			AgeSeconds: int64(timestamp.Sub(message.RequestReceivedAt).Seconds()),
+			Message:    message,
+		})
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] queued messages of phone [%s] for user [%s]", len(entries), phone.ID, userID))
+	return entries, nil
+}
+
+// phoneQueueStatsPeriod is the period in which the failed and expired messages of a phone are counted in its queue stats
+const phoneQueueStatsPeriod = 24 * time.Hour
+
+// QueueStats counts the outgoing messages of a phone in each state of its queue and the age of the oldest pending message
+func (service *PhoneService) QueueStats(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneQueueStats, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	timestamp := time.Now().UTC()
+	stats, err := service.messageRepository.QueueStats(ctx, userID, phone.PhoneNumber, timestamp.Add(-phoneQueueStatsPeriod))
+	if err != nil {
+		msg := fmt.Sprintf("cannot count the queued messages of phone [%s] for user [%s]", phone.ID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	stats.PhoneID = phone.ID
+	if stats.OldestPendingAt != nil {
+		stats.OldestPendingAgeSeconds = int64(timestamp.Sub(*stats.OldestPendingAt).Seconds())
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone [%s] of user [%s] has [%d] pending messages and the oldest is [%d] seconds old", phone.ID, userID, stats.Pending, stats.OldestPendingAgeSeconds))
+	return stats, nil
+}
+
+const (
+	// phonePollMessageLimit is the maximum number of messages which are returned to a phone which is long polling
+	phonePollMessageLimit = 100
+
+	// phonePollInterval is the duration between the checks for new messages while a phone is long polling
+	phonePollInterval = 2 * time.Second
+)
+
+// Poll waits until the phone has messages to send or the timeout is reached and returns the messages which are ready to be
+// fetched by the phone. It is used by phones which cannot receive push notifications e.g. because they don't have FCM.
+func (service *PhoneService) Poll(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, timeout time.Duration) ([]*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	ticker := time.NewTicker(phonePollInterval)
+	defer ticker.Stop()
+
+	deadline := time.Now().UTC().Add(timeout)
+	for {
+		messages, err := service.pollableMessages(ctx, phone)
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, err)
+		}
+
+		if len(messages) > 0 || !time.Now().UTC().Before(deadline) {
+			ctxLogger.Info(fmt.Sprintf("polled [%d] messages of phone [%s] for user [%s]", len(messages), phone.ID, userID))
+			return messages, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return messages, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollableMessages returns the queued messages of a phone which have not been fetched by the phone and whose notification is due
+func (service *PhoneService) pollableMessages(ctx context.Context, phone *entities.Phone) ([]*entities.Message, error) {
+	messages, err := service.messageRepository.Queue(ctx, phone.UserID, phone.PhoneNumber, phonePollMessageLimit)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch the queued messages of phone [%s] for user [%s]", phone.ID, phone.UserID))
+	}
+
+	timestamp := time.Now().UTC()
+	result := make([]*entities.Message, 0, len(messages))
+	for _, message := range messages {
+		if !message.IsSending() && (message.NotificationScheduledAt == nil || !message.NotificationScheduledAt.After(timestamp)) {
+			result = append(result, message)
+		}
+	}
+	return result, nil
+}
+
+// phoneResyncMessageLimit is the maximum number of queued messages which are pushed to a phone again when it is resynced
+const phoneResyncMessageLimit = 1000
+
+// Settings returns the entities.PhoneSettings which the app of a phone fetches when it starts
+func (service *PhoneService) Settings(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneSettings, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	webhooks, err := service.webhookRepository.Index(ctx, userID, repositories.IndexParams{Limit: 100})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the webhooks of user [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	settings := &entities.PhoneSettings{
+		PhoneID:                  phone.ID,
+		Owner:                    phone.PhoneNumber,
+		HeartbeatIntervalSeconds: phone.HeartbeatIntervalSecondsSanitized(),
+		SIM:                      phone.SIM,
+		MessagesPerMinute:        phone.MessagesPerMinute,
+		MessagesPerHour:          phone.MessagesPerHour,
+		MaxSendAttempts:          phone.MaxSendAttemptsSanitized(),
+		PushProvider:             phone.PushProvider,
+		WebhooksEnabled:          false,
+		UpdatedAt:                phone.UpdatedAt,
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.IsPaused() && slices.Contains(webhook.PhoneNumbers, phone.PhoneNumber) {
+			settings.WebhooksEnabled = true
+			break
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched settings of phone [%s] for user [%s]", phone.ID, userID))
+	return settings, nil
+}
+
+// Resync pushes the queued messages of a phone again and requests the app to upload the results which the server has not
+// received. The messages which were being sent are moved back to pending because the phone may have lost them.
+func (service *PhoneService) Resync(ctx context.Context, source string, userID entities.UserID, phoneID uuid.UUID) (*entities.PhoneResync, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	messages, err := service.messageRepository.Queue(ctx, userID, phone.PhoneNumber, phoneResyncMessageLimit)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch the queued messages of phone [%s] for user [%s]", phone.ID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	timestamp := time.Now().UTC()
+	for _, message := range messages {
+		if err = service.requeue(ctx, source, message, timestamp); err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, err)
+		}
+	}
+
+	event, err := service.createPhoneResyncRequestedEvent(source, events.PhoneResyncRequestedPayload{
+		PhoneID:      phone.ID,
+		UserID:       phone.UserID,
+		Timestamp:    timestamp,
+		Owner:        phone.PhoneNumber,
+		SIM:          phone.SIM,
+		MessageCount: len(messages),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create event when phone [%s] is resynced for user [%s]", phone.ID, phone.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for phone with id [%s]", event.Type(), phone.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("resynced [%d] queued messages of phone [%s] for user [%s]", len(messages), phone.ID, userID))
+	return &entities.PhoneResync{
+		PhoneID:      phone.ID,
+		UserID:       phone.UserID,
+		Owner:        phone.PhoneNumber,
+		MessageCount: len(messages),
+		RequestedAt:  timestamp,
+	}, nil
+}
+
+// requeue moves a queued message back to pending if needed and schedules a new notification so it is pushed to the phone again
+func (service *PhoneService) requeue(ctx context.Context, source string, message *entities.Message, timestamp time.Time) error {
+	if err := service.messageRepository.Update(ctx, message.Requeued(timestamp)); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot update message with id [%s] as requeued", message.ID))
+	}
+
+	event, err := service.createEvent(events.EventTypeMessageSendRetry, source, &events.MessageSendRetryPayload{
+		MessageID: message.ID,
+		Timestamp: timestamp,
+		Contact:   message.Contact,
+		Owner:     message.Owner,
+		Encrypted: message.Encrypted,
+		UserID:    message.UserID,
+		Content:   message.Content,
+		SIM:       message.SIM,
+		Category:  message.Category,
+		Priority:  message.Priority,
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create [%s] event for message with ID [%s]", events.EventTypeMessageSendRetry, message.ID))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch [%s] event for message with ID [%s]", event.Type(), message.ID))
+	}
+	return nil
+}
+
 // Load a phone by userID and owner
 func (service *PhoneService) Load(ctx context.Context, userID entities.UserID, owner string) (*entities.Phone, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -81,15 +334,78 @@ func (service *PhoneService) Load(ctx context.Context, userID entities.UserID, o
 	return service.repository.Load(ctx, userID, owner)
 }
 
+// UpdateSocketConnectedAt records when a phone opened its WebSocket connection or clears it when connectedAt is nil
+func (service *PhoneService) UpdateSocketConnectedAt(ctx context.Context, userID entities.UserID, phoneID uuid.UUID, connectedAt *time.Time) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.UpdateSocketConnectedAt(ctx, userID, phoneID, connectedAt); err != nil {
+		msg := fmt.Sprintf("cannot update the socket connection of phone with ID [%s] for user [%s]", phoneID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("updated the socket connection of phone with ID [%s] for user [%s] to [%v]", phoneID, userID, connectedAt != nil))
+	return nil
+}
+
+// ClearStaleSocketConnections clears the socket connection of the phones which are not connected to any process e.g.
+// because the process which held the connection crashed before the phone disconnected
+func (service *PhoneService) ClearStaleSocketConnections(ctx context.Context, registry *PhoneSocketRegistry) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phones, err := service.repository.FetchSocketConnected(ctx, time.Now().UTC())
+	if err != nil {
+		msg := "cannot fetch the phones with a socket connection"
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, phone := range phones {
+		connected, err := registry.IsConnected(ctx, phone.ID)
+		if err != nil {
+			msg := fmt.Sprintf("cannot check the socket connection of phone with ID [%s] for user [%s]", phone.ID, phone.UserID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		if connected {
+			continue
+		}
+
+		if err = service.UpdateSocketConnectedAt(ctx, phone.UserID, phone.ID, nil); err != nil {
+			msg := fmt.Sprintf("cannot clear the stale socket connection of phone with ID [%s] for user [%s]", phone.ID, phone.UserID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		ctxLogger.Info(fmt.Sprintf("cleared the stale socket connection of phone with ID [%s] for user [%s]", phone.ID, phone.UserID))
+	}
+
+	return nil
+}
+
+// LoadByID fetches an entities.Phone by its ID
+func (service *PhoneService) LoadByID(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	return service.repository.LoadByID(ctx, userID, phoneID)
+}
+
 // PhoneUpsertParams are parameters for creating a new entities.Phone
 type PhoneUpsertParams struct {
 	PhoneNumber               *phonenumbers.PhoneNumber
 	FcmToken                  *string
 	MessagesPerMinute         *uint
+	MessagesPerHour           *uint
 	MaxSendAttempts           *uint
 	WebhookURL                *string
 	MessageExpirationDuration *time.Duration
+	HeartbeatTimeout          *time.Duration
+	HeartbeatInterval         *time.Duration
+	LowBalanceThreshold       *float64
+	DuplicateWindow           *time.Duration
+	DuplicateStrategy         *entities.MessageDuplicateStrategy
+	PushProvider              *entities.PhonePushProvider
+	UnifiedPushEndpoint       *string
 	MissedCallAutoReply       *string
+	BackupPhoneNumber         *string
 	SIM                       entities.SIM
 	Source                    string
 	UserID                    entities.UserID
@@ -121,6 +437,112 @@ func (service *PhoneService) Upsert(ctx context.Context, params *PhoneUpsertPara
 	return phone, service.dispatchPhoneUpdatedEvent(ctx, params.Source, phone)
 }
 
+// PhoneRegisterParams are parameters for registering the app of an entities.Phone
+type PhoneRegisterParams struct {
+	PhoneNumber *phonenumbers.PhoneNumber
+	FcmToken    string
+	AppVersion  string
+	SIM         entities.SIM
+	Source      string
+	UserID      entities.UserID
+}
+
+// Register stores the FCM token and the app version which the app sends when it starts so that the phone is notified of
+// pending messages with push notifications instead of waiting for the next poll. The phone is created when it does not exist.
+func (service *PhoneService) Register(ctx context.Context, params *PhoneRegisterParams) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	owner := phonenumbers.Format(params.PhoneNumber, phonenumbers.E164)
+	provider := entities.PhonePushProviderFCM
+
+	phone, err := service.repository.Load(ctx, params.UserID, owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		phone, err = service.createPhone(ctx, &PhoneUpsertParams{
+			PhoneNumber:  params.PhoneNumber,
+			FcmToken:     &params.FcmToken,
+			PushProvider: &provider,
+			SIM:          params.SIM,
+			Source:       params.Source,
+			UserID:       params.UserID,
+		})
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with number [%s] for user [%s]", owner, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	registeredAt := time.Now().UTC()
+	phone.FcmToken = &params.FcmToken
+	phone.PushProvider = provider
+	phone.RegisteredAt = &registeredAt
+	phone.AppVersion = nil
+	if params.AppVersion != "" {
+		phone.AppVersion = &params.AppVersion
+	}
+
+	if err = service.repository.Save(ctx, phone); err != nil {
+		msg := fmt.Sprintf("cannot register phone with id [%s] and number [%s]", phone.ID, phone.PhoneNumber)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("registered phone with id [%s] and app version [%s] for user [%s]", phone.ID, params.AppVersion, phone.UserID))
+	return phone, service.dispatchPhoneUpdatedEvent(ctx, params.Source, phone)
+}
+
+// Deactivate takes a phone out of service so that it is not assigned messages and its offline alerts are suppressed
+func (service *PhoneService) Deactivate(ctx context.Context, source string, userID entities.UserID, phoneID uuid.UUID) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if phone.IsDeactivated() {
+		ctxLogger.Info(fmt.Sprintf("phone with id [%s] for user [%s] is already deactivated", phone.ID, phone.UserID))
+		return phone, nil
+	}
+
+	deactivatedAt := time.Now().UTC()
+	phone.DeactivatedAt = &deactivatedAt
+	if err = service.repository.Save(ctx, phone); err != nil {
+		msg := fmt.Sprintf("cannot deactivate phone with id [%s] for user [%s]", phone.ID, phone.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deactivated phone with id [%s] and number [%s] for user [%s]", phone.ID, phone.PhoneNumber, phone.UserID))
+	return phone, service.dispatchPhoneUpdatedEvent(ctx, source, phone)
+}
+
+// Reactivate puts a phone which was deactivated back into service
+func (service *PhoneService) Reactivate(ctx context.Context, source string, userID entities.UserID, phoneID uuid.UUID) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	phone, err := service.repository.LoadByID(ctx, userID, phoneID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", userID, phoneID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if !phone.IsDeactivated() {
+		ctxLogger.Info(fmt.Sprintf("phone with id [%s] for user [%s] is already active", phone.ID, phone.UserID))
+		return phone, nil
+	}
+
+	phone.DeactivatedAt = nil
+	if err = service.repository.Save(ctx, phone); err != nil {
+		msg := fmt.Sprintf("cannot reactivate phone with id [%s] for user [%s]", phone.ID, phone.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("reactivated phone with id [%s] and number [%s] for user [%s]", phone.ID, phone.PhoneNumber, phone.UserID))
+	return phone, service.dispatchPhoneUpdatedEvent(ctx, source, phone)
+}
+
 func (service *PhoneService) dispatchPhoneUpdatedEvent(ctx context.Context, source string, phone *entities.Phone) error {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
@@ -198,12 +620,21 @@ func (service *PhoneService) createPhone(ctx context.Context, params *PhoneUpser
 		MessageExpirationSeconds: 10 * 60, // 10 minutes
 		MaxSendAttempts:          2,
 		SIM:                      params.SIM,
+		PushProvider:             entities.PhonePushProviderFCM,
 		MissedCallAutoReply:      nil,
 		PhoneNumber:              phonenumbers.Format(params.PhoneNumber, phonenumbers.E164),
 		CreatedAt:                time.Now().UTC(),
 		UpdatedAt:                time.Now().UTC(),
 	}
 
+	// the app registers the UnifiedPush endpoint with the phone when it does not have Google Play Services
+	if params.PushProvider != nil {
+		phone.PushProvider = *params.PushProvider
+	}
+	if params.UnifiedPushEndpoint != nil && *params.UnifiedPushEndpoint != "" {
+		phone.UnifiedPushEndpoint = params.UnifiedPushEndpoint
+	}
+
 	if err := service.repository.Save(ctx, phone); err != nil {
 		msg := fmt.Sprintf("cannot create phone with id [%s] and number [%s]", phone.ID, phone.PhoneNumber)
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -217,6 +648,10 @@ func (service *PhoneService) createPhoneUpdatedEvent(source string, payload even
 	return service.createEvent(events.EventTypePhoneUpdated, source, payload)
 }
 
+func (service *PhoneService) createPhoneResyncRequestedEvent(source string, payload events.PhoneResyncRequestedPayload) (cloudevents.Event, error) {
+	return service.createEvent(events.EventTypePhoneResyncRequested, source, payload)
+}
+
 func (service *PhoneService) createPhoneDeletedEvent(source string, payload events.PhoneDeletedPayload) (cloudevents.Event, error) {
 	return service.createEvent(events.EventTypePhoneDeleted, source, payload)
 }
@@ -229,6 +664,10 @@ func (service *PhoneService) update(phone *entities.Phone, params *PhoneUpsertPa
 		phone.MessagesPerMinute = *params.MessagesPerMinute
 	}
 
+	if params.MessagesPerHour != nil {
+		phone.MessagesPerHour = *params.MessagesPerHour
+	}
+
 	if params.MaxSendAttempts != nil && *params.MaxSendAttempts > 0 {
 		phone.MaxSendAttempts = *params.MaxSendAttempts
 	}
@@ -237,10 +676,46 @@ func (service *PhoneService) update(phone *entities.Phone, params *PhoneUpsertPa
 		phone.MessageExpirationSeconds = uint(params.MessageExpirationDuration.Seconds())
 	}
 
+	if params.HeartbeatTimeout != nil {
+		phone.HeartbeatTimeoutSeconds = uint(params.HeartbeatTimeout.Seconds())
+	}
+
+	if params.HeartbeatInterval != nil {
+		phone.HeartbeatIntervalSeconds = uint(params.HeartbeatInterval.Seconds())
+	}
+
+	if params.LowBalanceThreshold != nil {
+		phone.LowBalanceThreshold = *params.LowBalanceThreshold
+	}
+
+	if params.DuplicateWindow != nil {
+		phone.DuplicateWindowSeconds = uint(params.DuplicateWindow.Seconds())
+	}
+
+	if params.DuplicateStrategy != nil {
+		phone.DuplicateStrategy = *params.DuplicateStrategy
+	}
+
 	if params.MissedCallAutoReply != nil {
 		phone.MissedCallAutoReply = params.MissedCallAutoReply
 	}
 
+	if params.PushProvider != nil {
+		phone.PushProvider = *params.PushProvider
+	}
+
+	if params.UnifiedPushEndpoint != nil && *params.UnifiedPushEndpoint == "" {
+		phone.UnifiedPushEndpoint = nil
+	} else if params.UnifiedPushEndpoint != nil {
+		phone.UnifiedPushEndpoint = params.UnifiedPushEndpoint
+	}
+
+	if params.BackupPhoneNumber != nil && *params.BackupPhoneNumber == "" {
+		phone.BackupPhoneNumber = nil
+	} else if params.BackupPhoneNumber != nil {
+		phone.BackupPhoneNumber = params.BackupPhoneNumber
+	}
+
 	phone.SIM = params.SIM
 
 	return phone
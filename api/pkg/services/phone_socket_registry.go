@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+// phoneSocketChannelPrefix is the prefix of the redis channel of a phone which is subscribed by the process that holds
+// the WebSocket connection of the phone
+const phoneSocketChannelPrefix = "phone-socket:"
+
+// PhoneSocketCommandType is the type of a command which is sent to a phone over its WebSocket connection
+type PhoneSocketCommandType string
+
+const (
+	// PhoneSocketCommandMessageSend requests the phone to fetch the outstanding message and send it
+	PhoneSocketCommandMessageSend = PhoneSocketCommandType("message.send")
+
+	// PhoneSocketCommandHeartbeat requests the phone to send a heartbeat because its last heartbeat is late
+	PhoneSocketCommandHeartbeat = PhoneSocketCommandType("heartbeat")
+)
+
+// PhoneSocketCommand is a command which is sent to a phone over its WebSocket connection
+type PhoneSocketCommand struct {
+	Type      PhoneSocketCommandType `json:"type"`
+	MessageID *uuid.UUID             `json:"message_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// PhoneSocket is a WebSocket connection which a phone holds open to receive commands without push notifications
+type PhoneSocket interface {
+	Send(command *PhoneSocketCommand) error
+}
+
+// PhoneSocketRegistry tracks the WebSocket connections of the phones which are connected to this process. The process
+// subscribes to the redis channel of every connected phone so that the commands which are sent by the other processes
+// e.g. the children of a prefork server or the other instances of the API are delivered to the phone.
+type PhoneSocketRegistry struct {
+	logger  telemetry.Logger
+	client  *redis.Client
+	mutex   sync.RWMutex
+	sockets map[uuid.UUID]PhoneSocket
+	pubsub  *redis.PubSub
+}
+
+// NewPhoneSocketRegistry creates a new PhoneSocketRegistry
+func NewPhoneSocketRegistry(logger telemetry.Logger, client *redis.Client) (registry *PhoneSocketRegistry) {
+	return &PhoneSocketRegistry{
+		logger:  logger.WithService(fmt.Sprintf("%T", registry)),
+		client:  client,
+		sockets: map[uuid.UUID]PhoneSocket{},
+	}
+}
+
+// Register the socket of a phone. It replaces the previous socket of the phone e.g. when the app reconnected before the
+// previous connection timed out.
+func (registry *PhoneSocketRegistry) Register(ctx context.Context, phoneID uuid.UUID, socket PhoneSocket) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.sockets[phoneID] = socket
+
+	if registry.pubsub == nil {
+		registry.pubsub = registry.client.Subscribe(ctx)
+		go registry.receive(registry.pubsub.Channel())
+	}
+
+	if err := registry.pubsub.Subscribe(ctx, registry.channel(phoneID)); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot subscribe to the commands of phone [%s]", phoneID))
+	}
+	return nil
+}
+
+// Unregister the socket of a phone. It returns false when the socket was already replaced by a newer connection.
+func (registry *PhoneSocketRegistry) Unregister(ctx context.Context, phoneID uuid.UUID, socket PhoneSocket) (bool, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.sockets[phoneID] != socket {
+		return false, nil
+	}
+
+	delete(registry.sockets, phoneID)
+	if err := registry.pubsub.Unsubscribe(ctx, registry.channel(phoneID)); err != nil {
+		return true, stacktrace.Propagate(err, fmt.Sprintf("cannot unsubscribe from the commands of phone [%s]", phoneID))
+	}
+	return true, nil
+}
+
+// Send a command to a phone which is connected to any process. It returns false when the phone is not connected.
+func (registry *PhoneSocketRegistry) Send(ctx context.Context, phoneID uuid.UUID, command *PhoneSocketCommand) (bool, error) {
+	if socket, ok := registry.socket(phoneID); ok {
+		return true, socket.Send(command)
+	}
+
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return false, stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%s] command for phone [%s]", command.Type, phoneID))
+	}
+
+	receivers, err := registry.client.Publish(ctx, registry.channel(phoneID), payload).Result()
+	if err != nil {
+		return false, stacktrace.Propagate(err, fmt.Sprintf("cannot publish [%s] command for phone [%s]", command.Type, phoneID))
+	}
+	return receivers > 0, nil
+}
+
+// IsConnected checks if a phone is connected to any process
+func (registry *PhoneSocketRegistry) IsConnected(ctx context.Context, phoneID uuid.UUID) (bool, error) {
+	if _, ok := registry.socket(phoneID); ok {
+		return true, nil
+	}
+
+	subscribers, err := registry.client.PubSubNumSub(ctx, registry.channel(phoneID)).Result()
+	if err != nil {
+		return false, stacktrace.Propagate(err, fmt.Sprintf("cannot count the subscribers of phone [%s]", phoneID))
+	}
+	return subscribers[registry.channel(phoneID)] > 0, nil
+}
+
+// Count returns the number of phones which are connected to this process
+func (registry *PhoneSocketRegistry) Count() int {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return len(registry.sockets)
+}
+
+func (registry *PhoneSocketRegistry) socket(phoneID uuid.UUID) (PhoneSocket, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	socket, ok := registry.sockets[phoneID]
+	return socket, ok
+}
+
+func (registry *PhoneSocketRegistry) channel(phoneID uuid.UUID) string {
+	return phoneSocketChannelPrefix + phoneID.String()
+}
+
+// receive delivers the commands which are published by the other processes to the phones which are connected to this process
+func (registry *PhoneSocketRegistry) receive(messages <-chan *redis.Message) {
+	for message := range messages {
+		phoneID, err := uuid.Parse(strings.TrimPrefix(message.Channel, phoneSocketChannelPrefix))
+		if err != nil {
+			registry.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot parse phone ID of redis channel [%s]", message.Channel)))
+			continue
+		}
+
+		command := new(PhoneSocketCommand)
+		if err = json.Unmarshal([]byte(message.Payload), command); err != nil {
+			registry.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal command [%s] for phone [%s]", message.Payload, phoneID)))
+			continue
+		}
+
+		socket, ok := registry.socket(phoneID)
+		if !ok {
+			continue
+		}
+
+		if err = socket.Send(command); err != nil {
+			registry.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send [%s] command to phone [%s]", command.Type, phoneID)))
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/emails"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	reportSource            = "/v1/reports/scheduler"
+	reportDownloadIssuer    = "api.httpsms.com/reports"
+	reportDownloadExpiresIn = 7 * 24 * time.Hour
+	reportSchedulerBatch    = 100
+	reportContentType       = "text/csv"
+)
+
+// reportUsageColumn is a column of the entities.ReportTypeDailyUsage report
+type reportUsageColumn struct {
+	name     string
+	types    []entities.MessageType
+	statuses []entities.MessageStatus
+}
+
+var reportUsageColumns = []reportUsageColumn{
+	{name: "sent", types: []entities.MessageType{entities.MessageTypeMobileTerminated}},
+	{name: "delivered", types: []entities.MessageType{entities.MessageTypeMobileTerminated}, statuses: []entities.MessageStatus{entities.MessageStatusDelivered}},
+	{name: "failed", types: []entities.MessageType{entities.MessageTypeMobileTerminated}, statuses: []entities.MessageStatus{entities.MessageStatusFailed}},
+	{name: "expired", types: []entities.MessageType{entities.MessageTypeMobileTerminated}, statuses: []entities.MessageStatus{entities.MessageStatusExpired}},
+	{name: "received", types: []entities.MessageType{entities.MessageTypeMobileOriginated}},
+}
+
+// ReportService generates the scheduled reports of a user and delivers them by email or webhook
+type ReportService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.ReportRepository
+	messageRepository repositories.MessageRepository
+	userRepository    repositories.UserRepository
+	attachmentService *AttachmentService
+	emailFactory      emails.NotificationEmailFactory
+	mailer            emails.Mailer
+	dispatcher        *EventDispatcher
+	baseURL           string
+	signingKey        []byte
+}
+
+// NewReportService creates a new ReportService
+func NewReportService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ReportRepository,
+	messageRepository repositories.MessageRepository,
+	userRepository repositories.UserRepository,
+	attachmentService *AttachmentService,
+	emailFactory emails.NotificationEmailFactory,
+	mailer emails.Mailer,
+	dispatcher *EventDispatcher,
+	baseURL string,
+	signingKey string,
+) (s *ReportService) {
+	return &ReportService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		messageRepository: messageRepository,
+		userRepository:    userRepository,
+		attachmentService: attachmentService,
+		emailFactory:      emailFactory,
+		mailer:            mailer,
+		dispatcher:        dispatcher,
+		baseURL:           strings.TrimRight(baseURL, "/"),
+		signingKey:        []byte(signingKey),
+	}
+}
+
+// Index fetches the entities.Report for an entities.UserID
+func (service *ReportService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.Report, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	reports, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch reports with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] reports with prams [%+#v]", len(reports), params))
+	return reports, nil
+}
+
+// ReportStoreParams are parameters for creating a new entities.Report
+type ReportStoreParams struct {
+	UserID   entities.UserID
+	Type     entities.ReportType
+	Delivery entities.ReportDelivery
+}
+
+// Store a new entities.Report which is generated at the end of the current period
+func (service *ReportService) Store(ctx context.Context, params *ReportStoreParams) (*entities.Report, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	report := &entities.Report{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Type:      params.Type,
+		Delivery:  params.Delivery,
+		NextRunAt: params.Type.NextRunAt(time.Now().UTC()),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, report); err != nil {
+		msg := fmt.Sprintf("cannot save report with id [%s]", report.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("report saved with id [%s] for user [%s] and next run at [%s]", report.ID, report.UserID, report.NextRunAt))
+	return report, nil
+}
+
+// Delete an entities.Report
+func (service *ReportService) Delete(ctx context.Context, userID entities.UserID, reportID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, reportID); err != nil {
+		msg := fmt.Sprintf("cannot load report with userID [%s] and reportID [%s]", userID, reportID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, reportID); err != nil {
+		msg := fmt.Sprintf("cannot delete report with id [%s] and user id [%s]", reportID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted report with id [%s] and user id [%s]", reportID, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Report for an entities.UserID.
+func (service *ReportService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.Report] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Report] for user with ID [%s]", userID))
+	return nil
+}
+
+// Schedule generates the reports which are due at every interval until the context is cancelled
+func (service *ReportService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.RunDue(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot generate scheduled reports"))
+			}
+		}
+	}
+}
+
+// RunDue generates and delivers the entities.Report of every user with a run time before the timestamp
+func (service *ReportService) RunDue(ctx context.Context, timestamp time.Time) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	reports, err := service.repository.FetchDue(ctx, timestamp, reportSchedulerBatch)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch reports which are due before [%s]", timestamp)
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	count := 0
+	for _, report := range reports {
+		if service.run(ctx, report, timestamp) {
+			count++
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("generated [%d] reports which are due before [%s]", count, timestamp))
+	}
+	return count, nil
+}
+
+// LoadDownload verifies a signed download token and returns the report file with a reader for its content which must be closed by the caller
+func (service *ReportService) LoadDownload(ctx context.Context, token string) (*entities.Attachment, io.ReadCloser, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	claims, err := service.parseToken(token)
+	if err != nil {
+		msg := "cannot verify report download token"
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, repositories.ErrCodeNotFound, msg))
+	}
+
+	attachmentID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot parse attachment ID [%s] in report download token", claims.Subject)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, repositories.ErrCodeNotFound, msg))
+	}
+
+	attachment, reader, err := service.attachmentService.Download(ctx, entities.UserID(claims.Audience), attachmentID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot download report file [%s] for user [%s]", attachmentID, claims.Audience)
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return attachment, reader, nil
+}
+
+func (service *ReportService) run(ctx context.Context, report *entities.Report, timestamp time.Time) bool {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// periods which were missed while the scheduler was not running are skipped so that only the last period is reported
+	to := report.NextRunAt
+	from := to.Add(-report.Type.Period())
+	nextRunAt := report.Type.NextRunAt(timestamp)
+
+	// Another instance of the scheduler is already generating the report when it cannot be rescheduled
+	err := service.repository.Reschedule(ctx, report, nextRunAt)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("report [%s] for user [%s] has already been generated", report.ID, report.UserID))
+		return false
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot reschedule report [%s] for user [%s]", report.ID, report.UserID)))
+		return false
+	}
+
+	if err = service.deliver(ctx, report, from, to); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot deliver report [%s] for user [%s] between [%s] and [%s]", report.ID, report.UserID, from, to)))
+		return false
+	}
+
+	sentAt := time.Now().UTC()
+	report.NextRunAt = nextRunAt
+	report.LastSentAt = &sentAt
+	report.UpdatedAt = sentAt
+	if err = service.repository.Save(ctx, report); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot save report [%s] after it was sent", report.ID)))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("delivered [%s] report [%s] by [%s] for user [%s] between [%s] and [%s]", report.Type, report.ID, report.Delivery, report.UserID, from, to))
+	return true
+}
+
+func (service *ReportService) deliver(ctx context.Context, report *entities.Report, from time.Time, to time.Time) error {
+	content, err := service.generate(ctx, report, from, to)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot generate [%s] report", report.Type))
+	}
+
+	payload := &events.ReportGeneratedPayload{
+		ReportID:  report.ID,
+		UserID:    report.UserID,
+		Type:      report.Type,
+		Delivery:  report.Delivery,
+		From:      from,
+		To:        to,
+		FileName:  fmt.Sprintf("httpsms-%s-%s.csv", report.Type, from.Format("2006-01-02")),
+		Timestamp: time.Now().UTC(),
+	}
+
+	if report.Delivery == entities.ReportDeliveryEmail {
+		return service.sendEmail(ctx, payload, content)
+	}
+	return service.sendWebhook(ctx, payload, content)
+}
+
+func (service *ReportService) sendEmail(ctx context.Context, payload *events.ReportGeneratedPayload, content []byte) error {
+	user, err := service.userRepository.Load(ctx, payload.UserID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot load user with ID [%s]", payload.UserID))
+	}
+
+	email, err := service.emailFactory.ReportGenerated(user, payload)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create email for report [%s]", payload.ReportID))
+	}
+
+	email.Attachments = append(email.Attachments, emails.EmailAttachment{
+		Name:        payload.FileName,
+		ContentType: reportContentType,
+		Content:     content,
+	})
+
+	if err = service.mailer.Send(ctx, email); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot send email for report [%s] to user [%s]", payload.ReportID, payload.UserID))
+	}
+	return nil
+}
+
+func (service *ReportService) sendWebhook(ctx context.Context, payload *events.ReportGeneratedPayload, content []byte) error {
+	if len(service.signingKey) == 0 {
+		return stacktrace.NewError(fmt.Sprintf("cannot sign the download URL of report [%s] because the signing key is not configured", payload.ReportID))
+	}
+
+	attachment, err := service.attachmentService.Store(ctx, &AttachmentStoreParams{
+		UserID:      payload.UserID,
+		Name:        payload.FileName,
+		ContentType: reportContentType,
+		Size:        int64(len(content)),
+		Reader:      bytes.NewReader(content),
+		BaseURL:     service.baseURL,
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot store file of report [%s]", payload.ReportID))
+	}
+
+	payload.ExpiresAt = payload.Timestamp.Add(reportDownloadExpiresIn)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Audience:  string(attachment.UserID),
+		ExpiresAt: payload.ExpiresAt.Unix(),
+		IssuedAt:  payload.Timestamp.Unix(),
+		Issuer:    reportDownloadIssuer,
+		Subject:   attachment.ID.String(),
+	}).SignedString(service.signingKey)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot sign download token for report [%s]", payload.ReportID))
+	}
+	payload.DownloadURL = fmt.Sprintf("%s/shared/reports/%s", service.baseURL, token)
+
+	event, err := service.createEvent(events.EventTypeReportGenerated, reportSource, payload)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create [%s] event for report [%s]", events.EventTypeReportGenerated, payload.ReportID))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot dispatch [%s] event for report [%s]", event.Type(), payload.ReportID))
+	}
+	return nil
+}
+
+// generate creates the CSV content of a report between from and to
+func (service *ReportService) generate(ctx context.Context, report *entities.Report, from time.Time, to time.Time) ([]byte, error) {
+	var rows [][]string
+	var err error
+
+	switch report.Type {
+	case entities.ReportTypeDailyUsage:
+		rows, err = service.usageRows(ctx, report.UserID, from, to)
+	case entities.ReportTypeWeeklyFailures:
+		rows, err = service.failureRows(ctx, report.UserID, from, to)
+	default:
+		return nil, stacktrace.NewError(fmt.Sprintf("report type [%s] is not supported", report.Type))
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch the rows of the [%s] report for user [%s]", report.Type, report.UserID))
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err = writer.WriteAll(rows); err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot write [%d] rows as CSV", len(rows)))
+	}
+	return buffer.Bytes(), nil
+}
+
+// usageRows counts the messages of every phone for each of the reportUsageColumns
+func (service *ReportService) usageRows(ctx context.Context, userID entities.UserID, from time.Time, to time.Time) ([][]string, error) {
+	params := repositories.TimeseriesParams{From: from, To: to, Interval: to.Sub(from)}
+
+	counts := map[string][]int64{}
+	for index, column := range reportUsageColumns {
+		buckets, err := service.messageRepository.Timeseries(ctx, userID, column.types, column.statuses, params)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count [%s] messages for user [%s]", column.name, userID))
+		}
+
+		for _, bucket := range buckets {
+			if _, ok := counts[bucket.Owner]; !ok {
+				counts[bucket.Owner] = make([]int64, len(reportUsageColumns))
+			}
+			counts[bucket.Owner][index] += bucket.Count
+		}
+	}
+
+	header := []string{"owner"}
+	for _, column := range reportUsageColumns {
+		header = append(header, column.name)
+	}
+
+	owners := make([]string, 0, len(counts))
+	for owner := range counts {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	rows := [][]string{header}
+	for _, owner := range owners {
+		row := []string{owner}
+		for _, count := range counts[owner] {
+			row = append(row, strconv.FormatInt(count, 10))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// failureRows counts the failed messages of every phone grouped by failure reason
+func (service *ReportService) failureRows(ctx context.Context, userID entities.UserID, from time.Time, to time.Time) ([][]string, error) {
+	failures, err := service.messageRepository.CountFailures(ctx, userID, from, to)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count failed messages for user [%s]", userID))
+	}
+
+	rows := [][]string{{"owner", "failure_reason", "count"}}
+	for _, failure := range failures {
+		rows = append(rows, []string{failure.Owner, failure.FailureReason, strconv.FormatInt(failure.Count, 10)})
+	}
+	return rows, nil
+}
+
+func (service *ReportService) parseToken(token string) (*jwt.StandardClaims, error) {
+	if len(service.signingKey) == 0 {
+		return nil, stacktrace.NewError("the report download signing key is not configured")
+	}
+
+	claims := new(jwt.StandardClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, stacktrace.NewError(fmt.Sprintf("unexpected signing method [%s]", token.Header["alg"]))
+		}
+		return service.signingKey, nil
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot parse report download token")
+	}
+
+	if !claims.VerifyIssuer(reportDownloadIssuer, true) {
+		return nil, stacktrace.NewError(fmt.Sprintf("invalid issuer [%s] in report download token", claims.Issuer))
+	}
+
+	return claims, nil
+}
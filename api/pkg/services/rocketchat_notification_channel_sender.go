@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// RocketChatNotificationChannelSender sends alerts as message attachments to Rocket.Chat incoming webhooks
+type RocketChatNotificationChannelSender struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *http.Client
+}
+
+// NewRocketChatNotificationChannelSender creates a new RocketChatNotificationChannelSender
+func NewRocketChatNotificationChannelSender(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+) (s *RocketChatNotificationChannelSender) {
+	return &RocketChatNotificationChannelSender{
+		logger: logger.WithService(fmt.Sprintf("%T", s)),
+		tracer: tracer,
+		client: client,
+	}
+}
+
+// Provider returns entities.NotificationChannelProviderRocketChat
+func (sender *RocketChatNotificationChannelSender) Provider() entities.NotificationChannelProvider {
+	return entities.NotificationChannelProviderRocketChat
+}
+
+// Send posts the alert to the Rocket.Chat webhook https://docs.rocket.chat/use-rocket.chat/workspace-administration/integrations
+func (sender *RocketChatNotificationChannelSender) Send(ctx context.Context, channel *entities.NotificationChannel, alert *NotificationChannelAlert) error {
+	ctx, span := sender.tracer.Start(ctx)
+	defer span.End()
+
+	payload := fiber.Map{
+		"alias":       "httpsms.com",
+		"avatar":      "https://httpsms.com/avatar.png",
+		"text":        alert.Title,
+		"attachments": []fiber.Map{createNotificationChannelAttachment(alert)},
+	}
+
+	if err := postNotificationChannelPayload(ctx, sender.client, channel.WebhookURL, payload); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert to rocket.chat channel [%s] for user [%s]", alert.Event, channel.ID, channel.UserID)
+		return sender.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
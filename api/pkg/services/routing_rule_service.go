@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// RoutingRuleService manages the rules which choose the phone that sends an outgoing message
+type RoutingRuleService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.RoutingRuleRepository
+}
+
+// NewRoutingRuleService creates a new RoutingRuleService
+func NewRoutingRuleService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.RoutingRuleRepository,
+) (s *RoutingRuleService) {
+	return &RoutingRuleService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// DeleteAllForUser deletes all entities.RoutingRule for an entities.UserID.
+func (service *RoutingRuleService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.RoutingRule] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.RoutingRule] for user with ID [%s]", userID))
+	return nil
+}
+
+// Index fetches the entities.RoutingRule for an entities.UserID
+func (service *RoutingRuleService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.RoutingRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("could not fetch routing rules with params [%+#v]", params)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] routing rules with prams [%+#v]", len(rules), params))
+	return rules, nil
+}
+
+// Delete an entities.RoutingRule
+func (service *RoutingRuleService) Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot load routing rule with userID [%s] and ruleID [%s]", userID, ruleID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.Delete(ctx, userID, ruleID); err != nil {
+		msg := fmt.Sprintf("cannot delete routing rule with id [%s] and user id [%s]", ruleID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted routing rule with id [%s] and user id [%s]", ruleID, userID))
+	return nil
+}
+
+// RoutingRuleStoreParams are parameters for creating a new entities.RoutingRule
+type RoutingRuleStoreParams struct {
+	UserID      entities.UserID
+	Name        string
+	Position    uint
+	Prefix      string
+	Country     string
+	Tag         string
+	PhoneNumber string
+}
+
+// Store a new entities.RoutingRule
+func (service *RoutingRuleService) Store(ctx context.Context, params *RoutingRuleStoreParams) (*entities.RoutingRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule := &entities.RoutingRule{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		Name:        params.Name,
+		Position:    params.Position,
+		Prefix:      params.Prefix,
+		Country:     params.Country,
+		Tag:         params.Tag,
+		PhoneNumber: params.PhoneNumber,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save routing rule with id [%s]", rule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("routing rule saved with id [%s] for user [%s] in the [%T]", rule.ID, rule.UserID, service.repository))
+	return rule, nil
+}
+
+// RoutingRuleUpdateParams are parameters for updating an entities.RoutingRule
+type RoutingRuleUpdateParams struct {
+	UserID      entities.UserID
+	RuleID      uuid.UUID
+	Name        string
+	Position    uint
+	Prefix      string
+	Country     string
+	Tag         string
+	PhoneNumber string
+}
+
+// Update an entities.RoutingRule
+func (service *RoutingRuleService) Update(ctx context.Context, params *RoutingRuleUpdateParams) (*entities.RoutingRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule, err := service.repository.Load(ctx, params.UserID, params.RuleID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load routing rule with userID [%s] and ruleID [%s]", params.UserID, params.RuleID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	rule.Name = params.Name
+	rule.Position = params.Position
+	rule.Prefix = params.Prefix
+	rule.Country = params.Country
+	rule.Tag = params.Tag
+	rule.PhoneNumber = params.PhoneNumber
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Save(ctx, rule); err != nil {
+		msg := fmt.Sprintf("cannot save routing rule with id [%s] after update", rule.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("routing rule updated with id [%s] in the [%T]", rule.ID, service.repository))
+	return rule, nil
+}
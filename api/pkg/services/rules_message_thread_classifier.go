@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+)
+
+type rulesMessageThreadClassifier struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	rules  []messageThreadTopicRule
+}
+
+type messageThreadTopicRule struct {
+	topic   entities.MessageThreadTopic
+	pattern *regexp.Regexp
+}
+
+// NewRulesMessageThreadClassifier creates a MessageThreadClassifier which picks the topic with the most keywords in the
+// messages of the contact. The language is the language which was detected in the most recent received message.
+func NewRulesMessageThreadClassifier(logger telemetry.Logger, tracer telemetry.Tracer) MessageThreadClassifier {
+	return &rulesMessageThreadClassifier{
+		logger: logger.WithService(fmt.Sprintf("%T", &rulesMessageThreadClassifier{})),
+		tracer: tracer,
+		// the rules are ordered by priority so that spam wins a tie with the other topics
+		rules: []messageThreadTopicRule{
+			{
+				topic:   entities.MessageThreadTopicSpam,
+				pattern: regexp.MustCompile(`(?i)\b(winner|won|prize|lottery|congratulations|claim|free gift|click here|act now|limited offer|crypto|casino|loan approved)\b|bit\.ly/`),
+			},
+			{
+				topic:   entities.MessageThreadTopicBilling,
+				pattern: regexp.MustCompile(`(?i)\b(invoice|payment|pay|paid|refund|charged?|bill|billing|receipt|subscription|price|balance|credit card|overdue)\b`),
+			},
+			{
+				topic:   entities.MessageThreadTopicSupport,
+				pattern: regexp.MustCompile(`(?i)\b(help|issue|problem|error|broken|not working|support|fix|can't|cannot|doesn't work|question)\b`),
+			},
+		},
+	}
+}
+
+// Classify counts the keywords of each topic in the messages which were received from the contact
+func (classifier *rulesMessageThreadClassifier) Classify(ctx context.Context, messages []entities.Message) (*MessageThreadClassification, error) {
+	_, span := classifier.tracer.Start(ctx)
+	defer span.End()
+
+	topic, score := entities.MessageThreadTopicOther, 0
+	for _, rule := range classifier.rules {
+		count := 0
+		for _, message := range messages {
+			if message.Type == entities.MessageTypeMobileOriginated {
+				count += len(rule.pattern.FindAllStringIndex(message.Content, -1))
+			}
+		}
+
+		if count > score {
+			topic, score = rule.topic, count
+		}
+	}
+
+	return &MessageThreadClassification{
+		Topic:    topic,
+		Language: lastMessageLanguage(messages),
+	}, nil
+}
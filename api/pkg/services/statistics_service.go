@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// StatisticsTargetMessagesSent is the number of outgoing messages
+	StatisticsTargetMessagesSent = "messages.sent"
+	// StatisticsTargetMessagesReceived is the number of incoming messages
+	StatisticsTargetMessagesReceived = "messages.received"
+	// StatisticsTargetMessagesDelivered is the number of outgoing messages which were delivered
+	StatisticsTargetMessagesDelivered = "messages.delivered"
+	// StatisticsTargetMessagesFailed is the number of outgoing messages which failed
+	StatisticsTargetMessagesFailed = "messages.failed"
+	// StatisticsTargetMessagesExpired is the number of outgoing messages which expired
+	StatisticsTargetMessagesExpired = "messages.expired"
+	// StatisticsTargetHeartbeats is the number of heartbeats
+	StatisticsTargetHeartbeats = "heartbeats"
+	// StatisticsTargetUptime is the percentage of time a phone was online
+	StatisticsTargetUptime = "uptime"
+
+	// statisticsUptimeSlot is the expected interval between 2 heartbeats, a phone is online in a slot when it sent a heartbeat
+	statisticsUptimeSlot = 15 * time.Minute
+)
+
+// StatisticsTargets are the targets which can be queried in StatisticsService.Timeseries
+var StatisticsTargets = []string{
+	StatisticsTargetMessagesSent,
+	StatisticsTargetMessagesReceived,
+	StatisticsTargetMessagesDelivered,
+	StatisticsTargetMessagesFailed,
+	StatisticsTargetMessagesExpired,
+	StatisticsTargetHeartbeats,
+	StatisticsTargetUptime,
+}
+
+// StatisticsService aggregates messages and heartbeats into time series
+type StatisticsService struct {
+	service
+	logger              telemetry.Logger
+	tracer              telemetry.Tracer
+	messageRepository   repositories.MessageRepository
+	heartbeatRepository repositories.HeartbeatRepository
+	windowRepository    repositories.PhoneMaintenanceWindowRepository
+}
+
+// NewStatisticsService creates a new StatisticsService
+func NewStatisticsService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	messageRepository repositories.MessageRepository,
+	heartbeatRepository repositories.HeartbeatRepository,
+	windowRepository repositories.PhoneMaintenanceWindowRepository,
+) (s *StatisticsService) {
+	return &StatisticsService{
+		logger:              logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:              tracer,
+		messageRepository:   messageRepository,
+		heartbeatRepository: heartbeatRepository,
+		windowRepository:    windowRepository,
+	}
+}
+
+// StatisticsTimeseriesTarget is a series which is requested in StatisticsTimeseriesParams
+type StatisticsTimeseriesTarget struct {
+	Name       string
+	Owner      string
+	SenderName string
+	Category   entities.MessageCategory
+}
+
+// StatisticsTimeseriesParams are parameters for fetching time series
+type StatisticsTimeseriesParams struct {
+	UserID   entities.UserID
+	From     time.Time
+	To       time.Time
+	Interval time.Duration
+	Targets  []StatisticsTimeseriesTarget
+}
+
+// Timeseries returns a time series for every target
+func (service *StatisticsService) Timeseries(ctx context.Context, params *StatisticsTimeseriesParams) ([]*entities.Timeseries, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	var result []*entities.Timeseries
+	for _, target := range params.Targets {
+		series, err := service.target(ctx, params, target)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch time series for target [%s] and user [%s]", target.Name, params.UserID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		result = append(result, series...)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] time series for user [%s] between [%s] and [%s]", len(result), params.UserID, params.From, params.To))
+	return result, nil
+}
+
+func (service *StatisticsService) target(ctx context.Context, params *StatisticsTimeseriesParams, target StatisticsTimeseriesTarget) ([]*entities.Timeseries, error) {
+	timeseriesParams := repositories.TimeseriesParams{
+		From:     params.From,
+		To:       params.To,
+		Interval: params.Interval,
+	}
+	if target.Owner != "" {
+		timeseriesParams.Owners = []string{target.Owner}
+	}
+	if target.SenderName != "" {
+		timeseriesParams.SenderNames = []string{target.SenderName}
+	}
+	if target.Category != "" {
+		timeseriesParams.Categories = []entities.MessageCategory{target.Category}
+	}
+
+	var buckets []*entities.TimeseriesBucket
+	var err error
+
+	switch target.Name {
+	case StatisticsTargetMessagesSent:
+		buckets, err = service.messageRepository.Timeseries(ctx, params.UserID, []entities.MessageType{entities.MessageTypeMobileTerminated}, nil, timeseriesParams)
+	case StatisticsTargetMessagesReceived:
+		buckets, err = service.messageRepository.Timeseries(ctx, params.UserID, []entities.MessageType{entities.MessageTypeMobileOriginated}, nil, timeseriesParams)
+	case StatisticsTargetMessagesDelivered:
+		buckets, err = service.messageRepository.Timeseries(ctx, params.UserID, []entities.MessageType{entities.MessageTypeMobileTerminated}, []entities.MessageStatus{entities.MessageStatusDelivered}, timeseriesParams)
+	case StatisticsTargetMessagesFailed:
+		buckets, err = service.messageRepository.Timeseries(ctx, params.UserID, []entities.MessageType{entities.MessageTypeMobileTerminated}, []entities.MessageStatus{entities.MessageStatusFailed}, timeseriesParams)
+	case StatisticsTargetMessagesExpired:
+		buckets, err = service.messageRepository.Timeseries(ctx, params.UserID, []entities.MessageType{entities.MessageTypeMobileTerminated}, []entities.MessageStatus{entities.MessageStatusExpired}, timeseriesParams)
+	case StatisticsTargetHeartbeats:
+		buckets, err = service.heartbeatRepository.Timeseries(ctx, params.UserID, timeseriesParams)
+	case StatisticsTargetUptime:
+		return service.uptime(ctx, params, target, timeseriesParams)
+	default:
+		return nil, stacktrace.NewError(fmt.Sprintf("target [%s] is not supported", target.Name))
+	}
+
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count [%s] in time buckets", target.Name))
+	}
+
+	counts := map[int64]float64{}
+	for _, bucket := range buckets {
+		counts[bucket.Timestamp.Unix()] += float64(bucket.Count)
+	}
+
+	return []*entities.Timeseries{service.series(service.name(target.Name, target.Owner, target.SenderName, target.Category.String()), params.From, params.To, params.Interval, counts)}, nil
+}
+
+// uptime returns the percentage of statisticsUptimeSlot in every bucket in which a phone sent a heartbeat.
+// The slots which are in a maintenance window of the phone are not counted.
+func (service *StatisticsService) uptime(ctx context.Context, params *StatisticsTimeseriesParams, target StatisticsTimeseriesTarget, timeseriesParams repositories.TimeseriesParams) ([]*entities.Timeseries, error) {
+	interval := params.Interval
+	if interval < statisticsUptimeSlot {
+		interval = statisticsUptimeSlot
+	}
+
+	timeseriesParams.Interval = statisticsUptimeSlot
+	slots, err := service.heartbeatRepository.Timeseries(ctx, params.UserID, timeseriesParams)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot count heartbeats in slots of [%s]", statisticsUptimeSlot))
+	}
+
+	windows, err := service.windowRepository.LoadBetween(ctx, params.UserID, timeseriesParams.Owners, params.From, params.To)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot load maintenance windows between [%s] and [%s]", params.From, params.To))
+	}
+
+	maintenance := map[string][]*entities.PhoneMaintenanceWindow{}
+	for _, window := range windows {
+		maintenance[window.Owner] = append(maintenance[window.Owner], window)
+	}
+
+	owners := map[string]map[int64]float64{}
+	if target.Owner != "" {
+		owners[target.Owner] = map[int64]float64{}
+	}
+
+	for _, slot := range slots {
+		if _, ok := owners[slot.Owner]; !ok {
+			owners[slot.Owner] = map[int64]float64{}
+		}
+		if service.inMaintenance(maintenance[slot.Owner], slot.Timestamp) {
+			continue
+		}
+		owners[slot.Owner][service.bucket(slot.Timestamp, interval).Unix()]++
+	}
+
+	result := make([]*entities.Timeseries, 0, len(owners))
+	for owner, onlineSlots := range owners {
+		values := map[int64]float64{}
+		for bucket := service.bucket(params.From, interval); bucket.Before(params.To); bucket = bucket.Add(interval) {
+			slotsPerBucket := float64(interval) / float64(statisticsUptimeSlot)
+			for slot := bucket; slot.Before(bucket.Add(interval)) && len(maintenance[owner]) > 0; slot = slot.Add(statisticsUptimeSlot) {
+				if service.inMaintenance(maintenance[owner], slot) {
+					slotsPerBucket--
+				}
+			}
+
+			if slotsPerBucket <= 0 {
+				values[bucket.Unix()] = 100
+				continue
+			}
+			values[bucket.Unix()] = min(100, onlineSlots[bucket.Unix()]*100/slotsPerBucket)
+		}
+		result = append(result, service.series(service.name(target.Name, owner, "", ""), params.From, params.To, interval, values))
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Target < result[j].Target })
+	return result, nil
+}
+
+// inMaintenance checks if a timestamp is in one of the maintenance windows
+func (service *StatisticsService) inMaintenance(windows []*entities.PhoneMaintenanceWindow, timestamp time.Time) bool {
+	for _, window := range windows {
+		if window.Contains(timestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// series creates an entities.Timeseries with a data point for every bucket between from and to so there are no gaps in the graph
+func (service *StatisticsService) series(name string, from time.Time, to time.Time, interval time.Duration, values map[int64]float64) *entities.Timeseries {
+	series := &entities.Timeseries{Target: name, Datapoints: [][2]float64{}}
+	for timestamp := service.bucket(from, interval); timestamp.Before(to); timestamp = timestamp.Add(interval) {
+		series.Datapoints = append(series.Datapoints, [2]float64{values[timestamp.Unix()], float64(timestamp.UnixMilli())})
+	}
+	return series
+}
+
+// bucket returns the start of the bucket of a timestamp which is aligned in the same way as the database query
+func (service *StatisticsService) bucket(timestamp time.Time, interval time.Duration) time.Time {
+	seconds := int64(interval / time.Second)
+	return time.Unix(timestamp.Unix()/seconds*seconds, 0).UTC()
+}
+
+func (service *StatisticsService) name(target string, owner string, senderName string, category string) string {
+	for _, dimension := range []string{owner, senderName, category} {
+		if dimension != "" {
+			target += " " + dimension
+		}
+	}
+	return target
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// SuppressionService manages the contacts who opted out of marketing messages
+type SuppressionService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.SuppressionRepository
+}
+
+// NewSuppressionService creates a new SuppressionService
+func NewSuppressionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.SuppressionRepository,
+) (s *SuppressionService) {
+	return &SuppressionService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// IsSuppressed checks if a contact opted out of marketing messages from the owner
+func (service *SuppressionService) IsSuppressed(ctx context.Context, userID entities.UserID, owner string, contact string) (bool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	exists, err := service.repository.Exists(ctx, userID, owner, contact)
+	if err != nil {
+		msg := fmt.Sprintf("cannot check if contact [%s] is suppressed for owner [%s]", contact, owner)
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return exists, nil
+}
+
+// HandleMessageReceived suppresses a contact who replied with an opt-out keyword e.g. STOP and removes the suppression when they reply with START
+func (service *SuppressionService) HandleMessageReceived(ctx context.Context, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if payload.Encrypted {
+		return nil
+	}
+
+	if entities.IsOptInKeyword(payload.Content) {
+		if err := service.repository.Delete(ctx, payload.UserID, payload.Owner, payload.Contact); err != nil {
+			msg := fmt.Sprintf("cannot delete suppression for contact [%s] and owner [%s]", payload.Contact, payload.Owner)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		ctxLogger.Info(fmt.Sprintf("contact [%s] opted in to marketing messages from [%s] with message [%s]", payload.Contact, payload.Owner, payload.MessageID))
+		return nil
+	}
+
+	if !entities.IsOptOutKeyword(payload.Content) {
+		return nil
+	}
+
+	suppression := &entities.Suppression{
+		ID:        uuid.New(),
+		UserID:    payload.UserID,
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		MessageID: payload.MessageID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, suppression); err != nil {
+		msg := fmt.Sprintf("cannot store suppression for contact [%s] and owner [%s]", payload.Contact, payload.Owner)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact [%s] opted out of marketing messages from [%s] with message [%s]", payload.Contact, payload.Owner, payload.MessageID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Suppression for an entities.UserID.
+func (service *SuppressionService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.Suppression] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Suppression] for user with ID [%s]", userID))
+	return nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// TeamsNotificationChannelSender sends alerts as adaptive cards to Microsoft Teams incoming webhooks
+type TeamsNotificationChannelSender struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *http.Client
+}
+
+// NewTeamsNotificationChannelSender creates a new TeamsNotificationChannelSender
+func NewTeamsNotificationChannelSender(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *http.Client,
+) (s *TeamsNotificationChannelSender) {
+	return &TeamsNotificationChannelSender{
+		logger: logger.WithService(fmt.Sprintf("%T", s)),
+		tracer: tracer,
+		client: client,
+	}
+}
+
+// Provider returns entities.NotificationChannelProviderTeams
+func (sender *TeamsNotificationChannelSender) Provider() entities.NotificationChannelProvider {
+	return entities.NotificationChannelProviderTeams
+}
+
+// Send posts the alert as an adaptive card to the Microsoft Teams webhook
+func (sender *TeamsNotificationChannelSender) Send(ctx context.Context, channel *entities.NotificationChannel, alert *NotificationChannelAlert) error {
+	ctx, span := sender.tracer.Start(ctx)
+	defer span.End()
+
+	if err := postNotificationChannelPayload(ctx, sender.client, channel.WebhookURL, sender.createMessage(alert)); err != nil {
+		msg := fmt.Sprintf("cannot send [%s] alert to teams channel [%s] for user [%s]", alert.Event, channel.ID, channel.UserID)
+		return sender.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// createMessage formats the alert as an adaptive card https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using
+func (sender *TeamsNotificationChannelSender) createMessage(alert *NotificationChannelAlert) fiber.Map {
+	facts := make([]fiber.Map, 0, len(alert.Facts))
+	for _, fact := range alert.Facts {
+		facts = append(facts, fiber.Map{"title": fact.Name, "value": fact.Value})
+	}
+
+	card := fiber.Map{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"msteams": fiber.Map{"width": "Full"},
+		"body": []fiber.Map{
+			{
+				"type":   "TextBlock",
+				"text":   alert.Title,
+				"size":   "Medium",
+				"weight": "Bolder",
+				"color":  sender.getColor(alert.Severity),
+				"wrap":   true,
+			},
+			{
+				"type": "TextBlock",
+				"text": alert.Summary,
+				"wrap": true,
+			},
+			{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+
+	if alert.ActionURL != "" {
+		card["actions"] = []fiber.Map{
+			{
+				"type":  "Action.OpenUrl",
+				"title": alert.ActionTitle,
+				"url":   alert.ActionURL,
+			},
+		}
+	}
+
+	return fiber.Map{
+		"type": "message",
+		"attachments": []fiber.Map{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"contentUrl":  nil,
+				"content":     card,
+			},
+		},
+	}
+}
+
+func (sender *TeamsNotificationChannelSender) getColor(severity NotificationChannelAlertSeverity) string {
+	if severity == NotificationChannelAlertSeverityCritical {
+		return "Attention"
+	}
+	return "Warning"
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// TranslationService translates received messages into the preferred language of the user
+type TranslationService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	translator        Translator
+	userRepository    repositories.UserRepository
+	messageRepository repositories.MessageRepository
+}
+
+// NewTranslationService creates a new TranslationService
+func NewTranslationService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	translator Translator,
+	userRepository repositories.UserRepository,
+	messageRepository repositories.MessageRepository,
+) (s *TranslationService) {
+	return &TranslationService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		translator:        translator,
+		userRepository:    userRepository,
+		messageRepository: messageRepository,
+	}
+}
+
+// HandleMessageReceived stores a translated copy of a received message when the user has a preferred language
+func (service *TranslationService) HandleMessageReceived(ctx context.Context, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if payload.Encrypted || strings.TrimSpace(payload.Content) == "" {
+		return nil
+	}
+
+	user, err := service.userRepository.Load(ctx, payload.UserID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if user.PreferredLanguage == nil {
+		return nil
+	}
+
+	if payload.Language != nil && isSameLanguage(*payload.Language, *user.PreferredLanguage) {
+		ctxLogger.Info(fmt.Sprintf("message [%s] is already in the preferred language [%s] of user [%s]", payload.MessageID, *user.PreferredLanguage, user.ID))
+		return nil
+	}
+
+	message, err := service.messageRepository.Load(ctx, payload.UserID, payload.MessageID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load message with ID [%s] for user [%s]", payload.MessageID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if message.TranslatedContent != nil {
+		ctxLogger.Info(fmt.Sprintf("message [%s] for user [%s] has already been translated", message.ID, message.UserID))
+		return nil
+	}
+
+	translation, err := service.translator.Translate(ctx, message.Content, *user.PreferredLanguage)
+	if err != nil {
+		msg := fmt.Sprintf("cannot translate message [%s] into [%s]", message.ID, *user.PreferredLanguage)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if isSameLanguage(translation.SourceLanguage, *user.PreferredLanguage) {
+		ctxLogger.Info(fmt.Sprintf("message [%s] is already in the preferred language [%s] of user [%s]", message.ID, *user.PreferredLanguage, user.ID))
+		return nil
+	}
+
+	message.TranslatedContent = &translation.Text
+	message.TranslatedFrom = &translation.SourceLanguage
+	if err = service.messageRepository.Update(ctx, message); err != nil {
+		msg := fmt.Sprintf("cannot save translation of message [%s] for user [%s]", message.ID, message.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message [%s] translated from [%s] to [%s] for user [%s]", message.ID, translation.SourceLanguage, *user.PreferredLanguage, user.ID))
+	return nil
+}
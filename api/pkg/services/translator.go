@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"strings"
+)
+
+// Translation is the result of translating a text with a Translator
+type Translation struct {
+	Text           string
+	SourceLanguage string
+}
+
+// Translator translates text into another language
+type Translator interface {
+	// Translate detects the language of the text and translates it into the target language e.g. "en"
+	Translate(ctx context.Context, text string, targetLanguage string) (*Translation, error)
+
+	// Detect returns the language of the text e.g. "fr"
+	Detect(ctx context.Context, text string) (string, error)
+}
+
+// isSameLanguage compares the base language of the codes so that "en" and "en-gb" are the same language
+func isSameLanguage(source string, target string) bool {
+	base := func(language string) string {
+		return strings.SplitN(strings.ToLower(language), "-", 2)[0]
+	}
+	return source != "" && base(source) == base(target)
+}
@@ -93,8 +93,12 @@ func (service *UserService) GetByID(ctx context.Context, userID entities.UserID)
 
 // UserUpdateParams are parameters for updating an entities.User
 type UserUpdateParams struct {
-	Timezone      *time.Location
-	ActivePhoneID *uuid.UUID
+	Timezone                  *time.Location
+	ActivePhoneID             *uuid.UUID
+	OptOutFooter              *string
+	PreferredLanguage         *string
+	HeartbeatOfflineThreshold *uint
+	HeartbeatAlertCooldown    *time.Duration
 }
 
 // Update an entities.User
@@ -116,6 +120,24 @@ func (service *UserService) Update(ctx context.Context, authUser entities.AuthUs
 
 	user.Timezone = params.Timezone.String()
 	user.ActivePhoneID = params.ActivePhoneID
+	if params.OptOutFooter != nil {
+		user.OptOutFooter = params.OptOutFooter
+		if *params.OptOutFooter == "" {
+			user.OptOutFooter = nil
+		}
+	}
+	if params.PreferredLanguage != nil {
+		user.PreferredLanguage = params.PreferredLanguage
+		if *params.PreferredLanguage == "" {
+			user.PreferredLanguage = nil
+		}
+	}
+	if params.HeartbeatOfflineThreshold != nil {
+		user.HeartbeatOfflineThreshold = *params.HeartbeatOfflineThreshold
+	}
+	if params.HeartbeatAlertCooldown != nil {
+		user.HeartbeatAlertCooldownSeconds = uint(params.HeartbeatAlertCooldown.Seconds())
+	}
 
 	if err = service.repository.Update(ctx, user); err != nil {
 		msg := fmt.Sprintf("cannot save user with id [%s]", user.ID)
@@ -126,6 +148,33 @@ func (service *UserService) Update(ctx context.Context, authUser entities.AuthUs
 	return user, nil
 }
 
+// OptOutFooter returns the opt-out footer which must be appended to a message or nil when no footer is needed.
+// The footer is appended when it is explicitly enabled on the message or when it is a marketing message and the user has configured a footer.
+func (service *UserService) OptOutFooter(ctx context.Context, userID entities.UserID, enabled *bool, marketing bool) (*string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if (enabled != nil && !*enabled) || (enabled == nil && !marketing) {
+		return nil, nil
+	}
+
+	user, err := service.repository.Load(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("could not load user with ID [%s] to get the opt-out footer", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if enabled == nil && user.OptOutFooter == nil {
+		return nil, nil
+	}
+
+	footer := entities.DefaultOptOutFooter
+	if user.OptOutFooter != nil {
+		footer = *user.OptOutFooter
+	}
+	return &footer, nil
+}
+
 // UserNotificationUpdateParams are parameters for updating the notifications of a user
 type UserNotificationUpdateParams struct {
 	MessageStatusEnabled bool
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	webhookDeliveryCounterDeliveredKey = "webhook-deliveries:delivered"
+	webhookDeliveryCounterFailedKey    = "webhook-deliveries:failed"
+)
+
+// WebhookDeliveryCounter counts the webhook requests which were delivered or failed. The totals are stored in redis so
+// they are shared by the children of a prefork server and the other instances of the API and they survive restarts.
+type WebhookDeliveryCounter struct {
+	logger telemetry.Logger
+	client *redis.Client
+}
+
+// NewWebhookDeliveryCounter creates a new WebhookDeliveryCounter
+func NewWebhookDeliveryCounter(logger telemetry.Logger, client *redis.Client) (counter *WebhookDeliveryCounter) {
+	return &WebhookDeliveryCounter{
+		logger: logger.WithService(fmt.Sprintf("%T", counter)),
+		client: client,
+	}
+}
+
+// RecordDelivered increments the number of delivered webhook requests
+func (counter *WebhookDeliveryCounter) RecordDelivered(ctx context.Context) {
+	counter.increment(ctx, webhookDeliveryCounterDeliveredKey)
+}
+
+// RecordFailed increments the number of failed webhook requests
+func (counter *WebhookDeliveryCounter) RecordFailed(ctx context.Context) {
+	counter.increment(ctx, webhookDeliveryCounterFailedKey)
+}
+
+// Totals returns the number of delivered and failed webhook requests
+func (counter *WebhookDeliveryCounter) Totals(ctx context.Context) (delivered uint64, failed uint64, err error) {
+	values, err := counter.client.MGet(ctx, webhookDeliveryCounterDeliveredKey, webhookDeliveryCounterFailedKey).Result()
+	if err != nil {
+		return 0, 0, stacktrace.Propagate(err, "cannot fetch the webhook delivery totals from redis")
+	}
+	return redisUint64(values[0]), redisUint64(values[1]), nil
+}
+
+func (counter *WebhookDeliveryCounter) increment(ctx context.Context, key string) {
+	if err := counter.client.Incr(context.WithoutCancel(ctx), key).Err(); err != nil {
+		counter.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot increment the webhook delivery counter [%s] in redis", key)))
+	}
+}
+
+// redisUint64 parses a counter which was read from redis, a missing counter is 0
+func redisUint64(value any) uint64 {
+	text, ok := value.(string)
+	if !ok {
+		return 0
+	}
+
+	result, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return result
+}
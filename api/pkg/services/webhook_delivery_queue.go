@@ -0,0 +1,102 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// WebhookDeliveryQueue runs the webhook deliveries with a queue for each destination host. A destination runs at most
+// perDestination deliveries at the same time and all the destinations together run at most maxConcurrency deliveries,
+// so a receiver which stalls only holds the slots of its own destination and the deliveries to other receivers continue.
+// A destination holds at most maxPending deliveries which are waiting for a worker so a receiver which stalls cannot grow
+// the queue without bounds.
+type WebhookDeliveryQueue struct {
+	slots          chan struct{}
+	perDestination int
+	maxPending     int
+
+	lock         sync.Mutex
+	destinations map[string]*webhookDestination
+}
+
+type webhookDestination struct {
+	jobs    []func()
+	workers int
+}
+
+// NewWebhookDeliveryQueue creates a new WebhookDeliveryQueue
+func NewWebhookDeliveryQueue(maxConcurrency uint, perDestination uint, maxPending uint) *WebhookDeliveryQueue {
+	return &WebhookDeliveryQueue{
+		slots:          make(chan struct{}, max(maxConcurrency, 1)),
+		perDestination: int(max(perDestination, 1)),
+		maxPending:     int(max(maxPending, 1)),
+		destinations:   map[string]*webhookDestination{},
+	}
+}
+
+// Enqueue adds a delivery to the queue of the host of the webhook URL. It returns false and drops the delivery when the
+// queue of the host is full.
+func (queue *WebhookDeliveryQueue) Enqueue(webhookURL string, job func()) bool {
+	key := queue.destination(webhookURL)
+
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	destination, ok := queue.destinations[key]
+	if !ok {
+		destination = &webhookDestination{}
+		queue.destinations[key] = destination
+	}
+
+	if len(destination.jobs) >= queue.maxPending {
+		return false
+	}
+
+	destination.jobs = append(destination.jobs, job)
+	if destination.workers < queue.perDestination {
+		destination.workers++
+		go queue.work(key, destination)
+	}
+	return true
+}
+
+func (queue *WebhookDeliveryQueue) work(key string, destination *webhookDestination) {
+	for {
+		job := queue.next(key, destination)
+		if job == nil {
+			return
+		}
+
+		queue.slots <- struct{}{}
+		job()
+		<-queue.slots
+	}
+}
+
+// next removes the first delivery of a destination and stops the worker when the destination has no deliveries
+func (queue *WebhookDeliveryQueue) next(key string, destination *webhookDestination) func() {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if len(destination.jobs) == 0 {
+		destination.workers--
+		if destination.workers == 0 {
+			delete(queue.destinations, key)
+		}
+		return nil
+	}
+
+	job := destination.jobs[0]
+	destination.jobs[0] = nil
+	destination.jobs = destination.jobs[1:]
+	return job
+}
+
+func (queue *WebhookDeliveryQueue) destination(webhookURL string) string {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Host == "" {
+		return webhookURL
+	}
+	return strings.ToLower(parsed.Host)
+}
@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -30,27 +29,56 @@ import (
 // WebhookService is responsible for handling webhooks
 type WebhookService struct {
 	service
-	logger     telemetry.Logger
-	tracer     telemetry.Tracer
-	client     *http.Client
-	repository repositories.WebhookRepository
-	dispatcher *EventDispatcher
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	client      *http.Client
+	repository  repositories.WebhookRepository
+	threads     repositories.MessageThreadRepository
+	events      repositories.WebhookEventRepository
+	dispatcher  *EventDispatcher
+	counter     *WebhookDeliveryCounter
+	queue       *WebhookDeliveryQueue
+	faults      *FaultInjector
+	maxFailures uint
+	retention   time.Duration
 }
 
+const (
+	// webhookReplayLimit is the maximum number of events which are replayed to a webhook in one request
+	webhookReplayLimit = 10_000
+
+	webhookEventPruneBatchSize = 1000
+	webhookEventPruneMaxBatch  = 20
+)
+
 // NewWebhookService creates a new WebhookService
 func NewWebhookService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	client *http.Client,
 	repository repositories.WebhookRepository,
+	threads repositories.MessageThreadRepository,
+	webhookEvents repositories.WebhookEventRepository,
 	dispatcher *EventDispatcher,
+	counter *WebhookDeliveryCounter,
+	queue *WebhookDeliveryQueue,
+	faults *FaultInjector,
+	maxFailures uint,
+	retention time.Duration,
 ) (s *WebhookService) {
 	return &WebhookService{
-		logger:     logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:     tracer,
-		client:     client,
-		dispatcher: dispatcher,
-		repository: repository,
+		logger:      logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:      tracer,
+		client:      client,
+		dispatcher:  dispatcher,
+		repository:  repository,
+		threads:     threads,
+		events:      webhookEvents,
+		counter:     counter,
+		queue:       queue,
+		faults:      faults,
+		maxFailures: maxFailures,
+		retention:   retention,
 	}
 }
 
@@ -64,6 +92,11 @@ func (service *WebhookService) DeleteAllForUser(ctx context.Context, userID enti
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	if err := service.events.DeleteAllForUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("could not delete all [entities.WebhookEvent] for user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
 	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Webhook] for user with ID [%s]", userID))
 	return nil
 }
@@ -108,11 +141,14 @@ func (service *WebhookService) Delete(ctx context.Context, userID entities.UserI
 
 // WebhookStoreParams are parameters for creating a new entities.Webhook
 type WebhookStoreParams struct {
-	UserID       entities.UserID
-	SigningKey   string
-	URL          string
-	PhoneNumbers pq.StringArray
-	Events       pq.StringArray
+	UserID          entities.UserID
+	SigningKey      string
+	URL             string
+	PhoneNumbers    pq.StringArray
+	Events          pq.StringArray
+	PayloadTemplate string
+	Languages       pq.StringArray
+	TimeoutSeconds  uint
 }
 
 // Store a new entities.Webhook
@@ -123,14 +159,17 @@ func (service *WebhookService) Store(ctx context.Context, params *WebhookStorePa
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
 	webhook := &entities.Webhook{
-		ID:           uuid.New(),
-		UserID:       params.UserID,
-		URL:          params.URL,
-		PhoneNumbers: params.PhoneNumbers,
-		SigningKey:   params.SigningKey,
-		Events:       params.Events,
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		ID:              uuid.New(),
+		UserID:          params.UserID,
+		URL:             params.URL,
+		PhoneNumbers:    params.PhoneNumbers,
+		SigningKey:      params.SigningKey,
+		Events:          params.Events,
+		PayloadTemplate: params.PayloadTemplate,
+		Languages:       params.Languages,
+		TimeoutSeconds:  params.TimeoutSeconds,
+		CreatedAt:       time.Now().UTC(),
+		UpdatedAt:       time.Now().UTC(),
 	}
 
 	if err := service.repository.Save(ctx, webhook); err != nil {
@@ -144,12 +183,15 @@ func (service *WebhookService) Store(ctx context.Context, params *WebhookStorePa
 
 // WebhookUpdateParams are parameters for updating an entities.Webhook
 type WebhookUpdateParams struct {
-	UserID       entities.UserID
-	SigningKey   string
-	URL          string
-	Events       pq.StringArray
-	PhoneNumbers pq.StringArray
-	WebhookID    uuid.UUID
+	UserID          entities.UserID
+	SigningKey      string
+	URL             string
+	Events          pq.StringArray
+	PhoneNumbers    pq.StringArray
+	PayloadTemplate string
+	Languages       pq.StringArray
+	TimeoutSeconds  uint
+	WebhookID       uuid.UUID
 }
 
 // Update an entities.Webhook
@@ -167,6 +209,9 @@ func (service *WebhookService) Update(ctx context.Context, params *WebhookUpdate
 	webhook.SigningKey = params.SigningKey
 	webhook.Events = params.Events
 	webhook.PhoneNumbers = params.PhoneNumbers
+	webhook.PayloadTemplate = params.PayloadTemplate
+	webhook.Languages = params.Languages
+	webhook.TimeoutSeconds = params.TimeoutSeconds
 
 	if err = service.repository.Save(ctx, webhook); err != nil {
 		msg := fmt.Sprintf("cannot save webhook with id [%s] after update", webhook.ID)
@@ -177,12 +222,57 @@ func (service *WebhookService) Update(ctx context.Context, params *WebhookUpdate
 	return webhook, nil
 }
 
+// Pause stops sending events to an entities.Webhook until it is resumed
+func (service *WebhookService) Pause(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (*entities.Webhook, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	webhook, err := service.repository.Load(ctx, userID, webhookID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with userID [%s] and webhookID [%s]", userID, webhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if webhook.IsPaused() {
+		ctxLogger.Info(fmt.Sprintf("webhook with id [%s] is already paused since [%s]", webhook.ID, webhook.PausedAt))
+		return webhook, nil
+	}
+
+	if err = service.repository.Save(ctx, webhook.Pause(time.Now().UTC(), entities.WebhookPausedReasonUser)); err != nil {
+		msg := fmt.Sprintf("cannot save webhook with id [%s] after pausing it", webhook.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("webhook with id [%s] paused for user [%s]", webhook.ID, webhook.UserID))
+	return webhook, nil
+}
+
+// Resume sends events to a paused entities.Webhook again
+func (service *WebhookService) Resume(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) (*entities.Webhook, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	webhook, err := service.repository.Load(ctx, userID, webhookID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with userID [%s] and webhookID [%s]", userID, webhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err = service.repository.Save(ctx, webhook.Resume()); err != nil {
+		msg := fmt.Sprintf("cannot save webhook with id [%s] after resuming it", webhook.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("webhook with id [%s] resumed for user [%s]", webhook.ID, webhook.UserID))
+	return webhook, nil
+}
+
 // Send an event to a subscribed webhook
 func (service *WebhookService) Send(ctx context.Context, userID entities.UserID, event cloudevents.Event, phoneNumber string) error {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	webhooks, err := service.repository.LoadByEvent(ctx, userID, event.Type(), phoneNumber)
+	webhooks, err := service.loadWebhooks(ctx, userID, event, phoneNumber)
 	if err != nil {
 		msg := fmt.Sprintf("cannot load webhooks for userID [%s] and event [%s]", userID, event.Type())
 		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
@@ -193,24 +283,312 @@ func (service *WebhookService) Send(ctx context.Context, userID entities.UserID,
 		return nil
 	}
 
-	var wg sync.WaitGroup
+	// the event is only stored when a webhook is subscribed to it because it can only be replayed to a webhook
+	service.storeEvent(ctx, ctxLogger, userID, event, phoneNumber)
+
+	language := service.getLanguage(ctxLogger, event)
+
+	// the deliveries run in the background and must not be cancelled when the event listener returns
+	ctx = context.WithoutCancel(ctx)
 	for _, webhook := range webhooks {
-		wg.Add(1)
-		go func(webhook *entities.Webhook) {
-			defer wg.Done()
-			service.sendNotification(ctx, event, phoneNumber, webhook)
-		}(webhook)
+		if !service.matchesLanguage(webhook, event, language) {
+			ctxLogger.Info(fmt.Sprintf("skipping [%s] event with ID [%s] for webhook [%s] because the language [%s] is not in %v", event.Type(), event.ID(), webhook.ID, language, webhook.Languages))
+			continue
+		}
+
+		if !service.queue.Enqueue(webhook.URL, func() { service.sendNotification(ctx, event, phoneNumber, webhook) }) {
+			service.counter.RecordFailed(ctx)
+			ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("dropped [%s] event with ID [%s] for webhook [%s] of user [%s] because the delivery queue of [%s] is full", event.Type(), event.ID(), webhook.ID, userID, webhook.URL)))
+		}
+	}
+
+	return nil
+}
+
+// WebhookReplayParams are parameters for replaying the stored events to an entities.Webhook
+type WebhookReplayParams struct {
+	UserID    entities.UserID
+	WebhookID uuid.UUID
+	From      time.Time
+	To        time.Time
+	Events    []string
+}
+
+// Replay sends the stored events which happened between WebhookReplayParams.From and WebhookReplayParams.To again to a webhook.
+// The events are queued in the order in which they happened and they are sent in the background after this method returns.
+func (service *WebhookService) Replay(ctx context.Context, params *WebhookReplayParams) (*entities.WebhookReplay, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	webhook, err := service.repository.Load(ctx, params.UserID, params.WebhookID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with userID [%s] and webhookID [%s]", params.UserID, params.WebhookID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	replay := &entities.WebhookReplay{
+		WebhookID: webhook.ID,
+		From:      params.From,
+		To:        params.To,
+		Events:    service.replayEvents(webhook, params.Events),
+	}
+
+	if len(replay.Events) == 0 {
+		ctxLogger.Info(fmt.Sprintf("webhook [%s] is not subscribed to any of the events %v to replay", webhook.ID, params.Events))
+		return replay, nil
+	}
+
+	webhookEvents, err := service.events.LoadByTimestamp(ctx, params.UserID, params.From, params.To, replay.Events, webhook.PhoneNumbers, webhookReplayLimit)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the events of user [%s] to replay to webhook [%s]", params.UserID, webhook.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// the deliveries must not be cancelled when the request which started the replay is completed
+	ctx = context.WithoutCancel(ctx)
+	for _, webhookEvent := range webhookEvents {
+		event := cloudevents.NewEvent()
+		if err = json.Unmarshal(webhookEvent.Event, &event); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot decode stored event [%s] with ID [%s] into [%T]", webhookEvent.Type, webhookEvent.EventID, event)))
+			continue
+		}
+
+		if !service.matchesLanguage(webhook, event, service.getLanguage(ctxLogger, event)) {
+			continue
+		}
+
+		owner := webhookEvent.Owner
+		if !service.queue.Enqueue(webhook.URL, func() { service.sendNotification(ctx, event, owner, webhook) }) {
+			ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("stopped replay to webhook [%s] of user [%s] at event [%s] because the delivery queue of [%s] is full", webhook.ID, params.UserID, event.ID(), webhook.URL)))
+			replay.NextFrom = &webhookEvent.Timestamp
+			break
+		}
+		replay.Count++
+	}
+
+	if replay.NextFrom == nil && len(webhookEvents) == webhookReplayLimit {
+		replay.NextFrom = &webhookEvents[len(webhookEvents)-1].Timestamp
+	}
+
+	ctxLogger.Info(fmt.Sprintf("queued [%d] events between [%s] and [%s] to replay to webhook [%s] of user [%s]", replay.Count, params.From, params.To, webhook.ID, params.UserID))
+	return replay, nil
+}
+
+// replayEvents returns the event types to replay which the webhook is subscribed to, all the events of the webhook are replayed when types is empty
+func (service *WebhookService) replayEvents(webhook *entities.Webhook, types []string) []string {
+	if len(types) == 0 {
+		return append([]string{}, webhook.Events...)
+	}
+
+	result := make([]string, 0, len(types))
+	for _, eventType := range types {
+		if webhook.IsSubscribed(eventType) {
+			result = append(result, eventType)
+		}
+	}
+	return result
+}
+
+// storeEvent stores an event in the event store so that it can be replayed to a webhook, the event is still sent when it cannot be stored
+func (service *WebhookService) storeEvent(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, event cloudevents.Event, owner string) {
+	if service.retention <= 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot encode [%s] event with ID [%s] to store it", event.Type(), event.ID())))
+		return
+	}
+
+	webhookEvent := &entities.WebhookEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		EventID:   event.ID(),
+		Type:      event.Type(),
+		Owner:     owner,
+		Event:     payload,
+		Timestamp: event.Time().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if webhookEvent.Timestamp.IsZero() {
+		webhookEvent.Timestamp = webhookEvent.CreatedAt
+	}
+
+	if err = service.events.Store(ctx, webhookEvent); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store [%s] event with ID [%s] for user [%s]", event.Type(), event.ID(), userID)))
+	}
+}
+
+// Schedule prunes the stored events at every interval until the context is cancelled
+func (service *WebhookService) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := service.Prune(ctx, time.Now().UTC()); err != nil {
+				service.logger.Error(stacktrace.Propagate(err, "cannot prune webhook events"))
+			}
+		}
+	}
+}
+
+// Prune deletes the entities.WebhookEvent which are older than the retention period at the timestamp
+func (service *WebhookService) Prune(ctx context.Context, timestamp time.Time) (int64, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if service.retention <= 0 {
+		return 0, nil
+	}
+
+	before := timestamp.Add(-service.retention).UTC()
+
+	var total int64
+	for batch := 0; batch < webhookEventPruneMaxBatch; batch++ {
+		count, err := service.events.DeleteBefore(ctx, before, webhookEventPruneBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot delete webhook events which happened before [%s]", before)
+			return total, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		total += count
+		if count < webhookEventPruneBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		ctxLogger.Info(fmt.Sprintf("deleted [%d] webhook events which happened before [%s]", total, before))
+	}
+	return total, nil
+}
+
+// SendCustomEvent forwards an entities.CustomEvent to the webhooks which are subscribed to its type
+func (service *WebhookService) SendCustomEvent(ctx context.Context, payload *events.CustomEventPublishedPayload) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	event := cloudevents.NewEvent()
+	event.SetSource(payload.Source)
+	event.SetType(payload.Type)
+	event.SetTime(payload.Timestamp)
+	event.SetID(payload.ID.String())
+
+	if err := event.SetData(cloudevents.ApplicationJSON, []byte(payload.Data)); err != nil {
+		msg := fmt.Sprintf("cannot encode data of custom event [%s] as JSON", payload.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := service.Send(ctx, payload.UserID, event, ""); err != nil {
+		msg := fmt.Sprintf("cannot send custom event [%s] with type [%s] for user [%s]", payload.ID, payload.Type, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
-	wg.Wait()
 
 	return nil
 }
 
+// loadWebhooks loads the webhooks which are subscribed to an event.
+// The webhook of the message thread takes precedence over the webhooks of the account when it is subscribed to the event.
+func (service *WebhookService) loadWebhooks(ctx context.Context, userID entities.UserID, event cloudevents.Event, phoneNumber string) ([]*entities.Webhook, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if webhook := service.loadThreadWebhook(ctx, ctxLogger, userID, event, phoneNumber); webhook != nil {
+		ctxLogger.Info(fmt.Sprintf("sending [%s] event with ID [%s] to the webhook [%s] of the message thread", event.Type(), event.ID(), webhook.ID))
+		return []*entities.Webhook{webhook}, nil
+	}
+
+	webhooks, err := service.repository.LoadByEvent(ctx, userID, event.Type(), phoneNumber)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhooks for userID [%s] and event [%s]", userID, event.Type())
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+	return webhooks, nil
+}
+
+// loadThreadWebhook returns the webhook of the message thread of an event, it returns nil when the thread does not
+// have a webhook which is subscribed to the event.
+func (service *WebhookService) loadThreadWebhook(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, event cloudevents.Event, owner string) *entities.Webhook {
+	payload := new(struct {
+		Contact string `json:"contact"`
+	})
+	if owner == "" || !strings.HasPrefix(event.Type(), "message.") || event.DataAs(payload) != nil || payload.Contact == "" {
+		return nil
+	}
+
+	thread, err := service.threads.LoadByOwnerContact(ctx, userID, owner, payload.Contact)
+	if err != nil {
+		if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load thread for owner [%s] and contact [%s] of [%s] event with ID [%s]", owner, payload.Contact, event.Type(), event.ID())))
+		}
+		return nil
+	}
+
+	if thread.WebhookID == nil {
+		return nil
+	}
+
+	webhook, err := service.repository.Load(ctx, userID, *thread.WebhookID)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot load webhook [%s] of thread [%s], the event is sent to the webhooks of the account", *thread.WebhookID, thread.ID)))
+		return nil
+	}
+
+	if webhook.IsPaused() || !webhook.IsSubscribed(event.Type()) {
+		return nil
+	}
+	return webhook
+}
+
+// getLanguage returns the language which was detected in the content of a received message
+func (service *WebhookService) getLanguage(ctxLogger telemetry.Logger, event cloudevents.Event) string {
+	if event.Type() != events.EventTypeMessagePhoneReceived {
+		return ""
+	}
+
+	payload := new(events.MessagePhoneReceivedPayload)
+	if err := event.DataAs(payload); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot decode [%s] event with ID [%s] into [%T]", event.Type(), event.ID(), payload)))
+		return ""
+	}
+
+	if payload.Language == nil {
+		return ""
+	}
+	return *payload.Language
+}
+
+// matchesLanguage checks if a received message must be sent to a webhook which is limited to some languages
+func (service *WebhookService) matchesLanguage(webhook *entities.Webhook, event cloudevents.Event, language string) bool {
+	if len(webhook.Languages) == 0 || event.Type() != events.EventTypeMessagePhoneReceived {
+		return true
+	}
+
+	for _, item := range webhook.Languages {
+		if item == language || strings.HasPrefix(language, item+"-") {
+			return true
+		}
+	}
+	return false
+}
+
 func (service *WebhookService) sendNotification(ctx context.Context, event cloudevents.Event, owner string, webhook *entities.Webhook) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	requestCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	if err := service.faults.Inject(ctx, FaultTargetWebhooks, webhook.URL); err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot send [%s] event to webhook [%s] for user [%s]", event.Type(), webhook.URL, webhook.UserID)))
+		service.handleWebhookSendFailed(ctx, event, webhook, owner, err, nil)
+		return
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, webhook.Timeout())
 	defer cancel()
 
 	request, err := service.createRequest(requestCtx, event, webhook)
@@ -240,6 +618,12 @@ func (service *WebhookService) sendNotification(ctx context.Context, event cloud
 		return
 	}
 
+	service.counter.RecordDelivered(ctx)
+	if webhook.ConsecutiveFailures > 0 {
+		if err = service.repository.ResetFailures(ctx, webhook.UserID, webhook.ID); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot reset the consecutive failures of webhook [%s]", webhook.ID)))
+		}
+	}
 	ctxLogger.Info(fmt.Sprintf("sent webhook to url [%s] for event [%s] with ID [%s] and response code [%d]", webhook.URL, event.Type(), event.ID(), response.StatusCode))
 }
 
@@ -247,7 +631,7 @@ func (service *WebhookService) createRequest(ctx context.Context, event cloudeve
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	payload, err := json.Marshal(service.getPayload(ctxLogger, event, webhook))
+	payload, err := service.getRequestBody(ctxLogger, event, webhook)
 	if err != nil {
 		msg := fmt.Sprintf("cannot marshal payload for user [%s] and webhook [%s] for event [%s]", webhook.UserID, webhook.ID, event.ID())
 		return nil, stacktrace.Propagate(err, msg)
@@ -274,6 +658,20 @@ func (service *WebhookService) createRequest(ctx context.Context, event cloudeve
 	return request, nil
 }
 
+func (service *WebhookService) getRequestBody(ctxLogger telemetry.Logger, event cloudevents.Event, webhook *entities.Webhook) ([]byte, error) {
+	if webhook.PayloadTemplate == "" {
+		return json.Marshal(service.getPayload(ctxLogger, event, webhook))
+	}
+
+	body, err := RenderWebhookTemplate(webhook.PayloadTemplate, event)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot render payload template of webhook [%s] for event [%s] with ID [%s], sending the default payload", webhook.ID, event.Type(), event.ID())))
+		return json.Marshal(service.getPayload(ctxLogger, event, webhook))
+	}
+
+	return body, nil
+}
+
 func (service *WebhookService) getPayload(ctxLogger telemetry.Logger, event cloudevents.Event, webhook *entities.Webhook) any {
 	if event.Type() != events.EventTypeMessagePhoneReceived {
 		return event
@@ -338,6 +736,8 @@ func (service *WebhookService) handleWebhookSendFailed(ctx context.Context, even
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	service.counter.RecordFailed(ctx)
+
 	payload := &events.WebhookSendFailedPayload{
 		WebhookID:              webhook.ID,
 		WebhookURL:             webhook.URL,
@@ -378,4 +778,55 @@ func (service *WebhookService) handleWebhookSendFailed(ctx context.Context, even
 	}
 
 	ctxLogger.Info(fmt.Sprintf("dispatched [%s] event with ID [%s] for user with id [%s]", event.Type(), event.ID(), payload.UserID))
+	service.recordFailure(ctx, webhook, event.Source())
+}
+
+// recordFailure pauses the webhook when it has failed more than the maximum number of consecutive times
+func (service *WebhookService) recordFailure(ctx context.Context, webhook *entities.Webhook, source string) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	failures, err := service.repository.RecordFailure(ctx, webhook.UserID, webhook.ID)
+	if err != nil {
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot record failure for webhook [%s]", webhook.ID))))
+		return
+	}
+
+	if service.maxFailures == 0 || failures < service.maxFailures {
+		return
+	}
+
+	payload := &events.WebhookPausedPayload{
+		WebhookID:           webhook.ID,
+		WebhookURL:          webhook.URL,
+		UserID:              webhook.UserID,
+		ConsecutiveFailures: failures,
+		PausedReason:        fmt.Sprintf("paused after [%d] consecutive failures", failures),
+		Timestamp:           time.Now().UTC(),
+	}
+
+	// another event may have already paused the webhook when it failed concurrently
+	err = service.repository.Pause(ctx, webhook.UserID, webhook.ID, payload.Timestamp, payload.PausedReason)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return
+	}
+	if err != nil {
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot pause webhook [%s] after [%d] failures", webhook.ID, failures))))
+		return
+	}
+
+	event, err := service.createEvent(events.EventTypeWebhookPaused, source, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create event [%s] for webhook with id [%s]", events.EventTypeWebhookPaused, webhook.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch event [%s] for webhook with id [%s]", event.Type(), webhook.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	ctxLogger.Info(fmt.Sprintf("webhook [%s] of user [%s] paused after [%d] consecutive failures", webhook.ID, webhook.UserID, failures))
 }
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// webhookTemplateMaxOutput is the maximum size in bytes of a rendered webhook payload template
+	webhookTemplateMaxOutput = 64 * 1024
+
+	// webhookTemplateTimeout is the maximum time which a webhook payload template can take to render
+	webhookTemplateTimeout = 200 * time.Millisecond
+)
+
+// webhookTemplateData is the value which a webhook payload template is executed against
+type webhookTemplateData struct {
+	ID     string
+	Type   string
+	Source string
+	Time   time.Time
+	Data   map[string]any
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		result, err := json.Marshal(value)
+		return string(result), err
+	},
+	"default": func(fallback any, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	"formatTime": func(layout string, value any) (string, error) {
+		switch timestamp := value.(type) {
+		case time.Time:
+			return timestamp.Format(layout), nil
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+			if err != nil {
+				return "", err
+			}
+			return parsed.Format(layout), nil
+		default:
+			return "", fmt.Errorf("cannot format [%v] as a time", value)
+		}
+	},
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+	"replace":  strings.ReplaceAll,
+	"contains": strings.Contains,
+}
+
+// webhookTemplateWriter is a bytes.Buffer which stops the execution of a template when the output is larger than
+// webhookTemplateMaxOutput or when the deadline has passed
+type webhookTemplateWriter struct {
+	buffer   bytes.Buffer
+	deadline time.Time
+}
+
+func (writer *webhookTemplateWriter) Write(data []byte) (int, error) {
+	if writer.buffer.Len()+len(data) > webhookTemplateMaxOutput {
+		return 0, fmt.Errorf("the rendered template is larger than %d bytes", webhookTemplateMaxOutput)
+	}
+	if time.Now().After(writer.deadline) {
+		return 0, fmt.Errorf("the template took longer than %s to render", webhookTemplateTimeout)
+	}
+	return writer.buffer.Write(data)
+}
+
+// ParseWebhookTemplate parses the payload template of an entities.Webhook
+func ParseWebhookTemplate(source string) (*template.Template, error) {
+	tmpl, err := template.New("payload").Funcs(webhookTemplateFuncs).Parse(source)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot parse webhook payload template")
+	}
+
+	for _, definition := range tmpl.Templates() {
+		if definition.Tree == nil {
+			continue
+		}
+		if err = checkWebhookTemplateNode(definition.Tree.Root, map[string]bool{}); err != nil {
+			return nil, stacktrace.Propagate(err, "cannot use webhook payload template")
+		}
+	}
+	return tmpl, nil
+}
+
+// checkWebhookTemplateNode rejects a range over a number and a call to another template because they can loop or recurse
+// for a long time without writing any output. The variables which are declared with a number are collected in numbers.
+func checkWebhookTemplateNode(node parse.Node, numbers map[string]bool) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := checkWebhookTemplateNode(child, numbers); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		checkWebhookTemplateNumbers(node.Pipe, numbers)
+	case *parse.IfNode:
+		return checkWebhookTemplateBranch(&node.BranchNode, numbers)
+	case *parse.WithNode:
+		return checkWebhookTemplateBranch(&node.BranchNode, numbers)
+	case *parse.RangeNode:
+		if isWebhookTemplateNumber(node.Pipe, numbers) {
+			return fmt.Errorf("cannot range over a number in [%s]", node.Pipe.String())
+		}
+		return checkWebhookTemplateBranch(&node.BranchNode, numbers)
+	case *parse.TemplateNode:
+		return fmt.Errorf("cannot execute the template [%s] in a payload template", node.Name)
+	}
+	return nil
+}
+
+func checkWebhookTemplateBranch(node *parse.BranchNode, numbers map[string]bool) error {
+	checkWebhookTemplateNumbers(node.Pipe, numbers)
+	if err := checkWebhookTemplateNode(node.List, numbers); err != nil {
+		return err
+	}
+	return checkWebhookTemplateNode(node.ElseList, numbers)
+}
+
+func checkWebhookTemplateNumbers(pipe *parse.PipeNode, numbers map[string]bool) {
+	if pipe == nil || len(pipe.Decl) == 0 || !isWebhookTemplateNumber(pipe, numbers) {
+		return
+	}
+	for _, variable := range pipe.Decl {
+		numbers[variable.Ident[0]] = true
+	}
+}
+
+// isWebhookTemplateNumber checks if a pipeline is a number or a variable which was declared with a number
+func isWebhookTemplateNumber(pipe *parse.PipeNode, numbers map[string]bool) bool {
+	if pipe == nil || len(pipe.Cmds) == 0 {
+		return false
+	}
+
+	for _, argument := range pipe.Cmds[len(pipe.Cmds)-1].Args {
+		switch argument := argument.(type) {
+		case *parse.NumberNode:
+			return true
+		case *parse.IdentifierNode:
+			return argument.Ident == "len"
+		case *parse.VariableNode:
+			return len(argument.Ident) == 1 && numbers[argument.Ident[0]]
+		case *parse.PipeNode:
+			return isWebhookTemplateNumber(argument, numbers)
+		}
+	}
+	return false
+}
+
+// RenderWebhookTemplate executes the payload template of an entities.Webhook over a cloudevents.Event
+func RenderWebhookTemplate(source string, event cloudevents.Event) ([]byte, error) {
+	tmpl, err := ParseWebhookTemplate(source)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse template for event [%s] with ID [%s]", event.Type(), event.ID()))
+	}
+
+	data := webhookTemplateData{
+		ID:     event.ID(),
+		Type:   event.Type(),
+		Source: event.Source(),
+		Time:   event.Time(),
+		Data:   map[string]any{},
+	}
+	if err = event.DataAs(&data.Data); err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode data of event [%s] with ID [%s]", event.Type(), event.ID()))
+	}
+
+	writer := &webhookTemplateWriter{deadline: time.Now().Add(webhookTemplateTimeout)}
+	result := make(chan error, 1)
+	go func() {
+		result <- tmpl.Execute(writer, data)
+	}()
+
+	timer := time.NewTimer(webhookTemplateTimeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-result:
+	case <-timer.C:
+		err = fmt.Errorf("the template took longer than %s to render", webhookTemplateTimeout)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute template for event [%s] with ID [%s]", event.Type(), event.ID()))
+	}
+
+	if !json.Valid(writer.buffer.Bytes()) {
+		return nil, stacktrace.NewError(fmt.Sprintf("the rendered template for event [%s] with ID [%s] is not valid JSON", event.Type(), event.ID()))
+	}
+
+	return writer.buffer.Bytes(), nil
+}
+
+// ValidateWebhookTemplate renders the payload template over a sample event to make sure it produces valid JSON
+func ValidateWebhookTemplate(source string) error {
+	event := cloudevents.NewEvent()
+	event.SetSource("/v1/messages/receive")
+	event.SetType(events.EventTypeMessagePhoneReceived)
+	event.SetTime(time.Now().UTC())
+	event.SetID(uuid.New().String())
+
+	payload := events.MessagePhoneReceivedPayload{
+		MessageID: uuid.New(),
+		UserID:    "WB7DRDWrJZRGbYrv2CKGkqbzvqdC",
+		Owner:     "+18005550199",
+		Contact:   "+18005550100",
+		Timestamp: time.Now().UTC(),
+		Content:   "This is a sample \"message\"",
+		SIM:       entities.SIM1,
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot encode %T [%#+v] as JSON", payload, payload))
+	}
+
+	_, err := RenderWebhookTemplate(source, event)
+	return err
+}
@@ -0,0 +1,89 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// APIKeyHandlerValidator validates models used in handlers.APIKeyHandler
+type APIKeyHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewAPIKeyHandlerValidator creates a new handlers.APIKeyHandler validator
+func NewAPIKeyHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *APIKeyHandlerValidator) {
+	return &APIKeyHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateStore validates the requests.APIKeyStore request
+func (validator *APIKeyHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.APIKeyStore) responses.ValidationErrors {
+	rules := govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:100",
+		},
+	}
+
+	if len(request.Scopes) > 0 {
+		rules["scopes"] = []string{
+			multipleInRule + ":" + strings.Join([]string{
+				entities.APIKeyScopeSend.String(),
+				entities.APIKeyScopeRead.String(),
+				entities.APIKeyScopeAdmin.String(),
+			}, ","),
+		}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := validator.validate(v)
+	if len(request.Scopes) == 0 {
+		result.Add("scopes", "required", "the scopes field must contain at least one of [send, read, admin]")
+	}
+
+	if request.DefaultPhoneID == "" {
+		return result
+	}
+
+	phoneID, err := uuid.Parse(request.DefaultPhoneID)
+	if err != nil {
+		result.Add("default_phone_id", "uuid", "the default_phone_id field must contain a valid UUID")
+		return result
+	}
+
+	_, err = validator.phoneService.LoadByID(ctx, userID, phoneID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("default_phone_id", "exists", fmt.Sprintf("no phone found with ID [%s]", request.DefaultPhoneID))
+	} else if err != nil {
+		validator.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone [%s] for user [%s]", request.DefaultPhoneID, userID)))
+		result.Add("default_phone_id", "unavailable", fmt.Sprintf("could not validate the default_phone_id [%s], please try again later", request.DefaultPhoneID))
+	}
+
+	return result
+}
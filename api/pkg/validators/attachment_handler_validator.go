@@ -0,0 +1,77 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+)
+
+// attachmentMaxSize fits in the default 4 MB body limit of fiber with the overhead of the multipart form
+const attachmentMaxSize = 3 * 1024 * 1024
+
+// AttachmentHandlerValidator validates models used in handlers.AttachmentHandler
+type AttachmentHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewAttachmentHandlerValidator creates a new handlers.AttachmentHandler validator
+func NewAttachmentHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *AttachmentHandlerValidator) {
+	return &AttachmentHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ContentType returns the media type of an uploaded file, using the file extension when the client does not send one
+func (validator *AttachmentHandlerValidator) ContentType(header *multipart.FileHeader) string {
+	contentType, _, err := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if err != nil || contentType == "" || contentType == "application/octet-stream" {
+		contentType, _, _ = mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(header.Filename))))
+	}
+	return contentType
+}
+
+// ValidateStore validates a file which is uploaded as an attachment of an MMS message
+func (validator *AttachmentHandlerValidator) ValidateStore(_ context.Context, header *multipart.FileHeader) responses.ValidationErrors {
+	result := responses.ValidationErrors{}
+
+	if header.Size == 0 {
+		result.Add("file", "required", "The uploaded file is empty.")
+	}
+
+	if header.Size > attachmentMaxSize {
+		result.AddWithParam("file", "size", strconv.Itoa(attachmentMaxSize), fmt.Sprintf("The uploaded file must be smaller than %d MB.", attachmentMaxSize/1024/1024))
+	}
+
+	if len(header.Filename) > 255 {
+		result.AddWithParam("file", "filename_max", "255", "The name of the uploaded file must be maximum 255 characters.")
+	}
+
+	contentType := validator.ContentType(header)
+	if !validator.isSupportedContentType(contentType) {
+		result.AddWithParam("file", "mime", contentType, fmt.Sprintf("The content type [%s] is not supported, upload an image, video, audio, vCard or PDF file.", contentType))
+	}
+
+	return result
+}
+
+func (validator *AttachmentHandlerValidator) isSupportedContentType(contentType string) bool {
+	for _, prefix := range []string{"image/", "video/", "audio/"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return contentType == "text/vcard" || contentType == "text/x-vcard" || contentType == "application/pdf"
+}
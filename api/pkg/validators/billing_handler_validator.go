@@ -3,10 +3,10 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/thedevsaddam/govalidator"
 )
@@ -30,7 +30,7 @@ func NewBillingHandlerValidator(
 }
 
 // ValidateHistory validates the requests.BillingUsageHistory request
-func (validator *BillingHandlerValidator) ValidateHistory(_ context.Context, request requests.BillingUsageHistory) url.Values {
+func (validator *BillingHandlerValidator) ValidateHistory(_ context.Context, request requests.BillingUsageHistory) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -47,5 +47,5 @@ func (validator *BillingHandlerValidator) ValidateHistory(_ context.Context, req
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
@@ -6,15 +6,16 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/xuri/excelize/v2"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/dustin/go-humanize"
@@ -26,10 +27,11 @@ import (
 // BulkMessageHandlerValidator validates models used in handlers.BillingHandler
 type BulkMessageHandlerValidator struct {
 	validator
-	phoneService *services.PhoneService
-	userService  *services.UserService
-	logger       telemetry.Logger
-	tracer       telemetry.Tracer
+	phoneService      *services.PhoneService
+	userService       *services.UserService
+	preferenceService *services.ContactPreferenceService
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
 }
 
 // NewBulkMessageHandlerValidator creates a new handlers.BulkMessageHandlerValidator validator
@@ -38,24 +40,26 @@ func NewBulkMessageHandlerValidator(
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
 	userService *services.UserService,
+	preferenceService *services.ContactPreferenceService,
 ) (v *BulkMessageHandlerValidator) {
 	return &BulkMessageHandlerValidator{
-		logger:       logger.WithService(fmt.Sprintf("%T", v)),
-		tracer:       tracer,
-		userService:  userService,
-		phoneService: phoneService,
+		logger:            logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:            tracer,
+		userService:       userService,
+		phoneService:      phoneService,
+		preferenceService: preferenceService,
 	}
 }
 
 // ValidateStore validates the requests.BillingUsageHistory request
-func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) ([]*requests.BulkMessage, url.Values) {
+func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) ([]*requests.BulkMessage, responses.ValidationErrors) {
 	ctx, span, ctxLogger := v.tracer.StartWithLogger(ctx, v.logger)
 	defer span.End()
 
 	user, err := v.userService.GetByID(ctx, userID)
 	if err != nil {
-		result := url.Values{}
-		result.Add("document", "Cannot load your account. Please try again later or contact support.")
+		result := responses.ValidationErrors{}
+		result.Add("document", "unavailable", "Cannot load your account. Please try again later or contact support.")
 		ctxLogger.Error(v.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load user [%s]", userID))))
 		return nil, result
 	}
@@ -66,12 +70,12 @@ func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID
 	}
 
 	if len(messages) == 0 {
-		result.Add("document", "The uploaded file doesn't contain any valid records. Make sure you are using the official httpSMS template.")
+		result.AddWithParam("document", "min_records", "1", "The uploaded file doesn't contain any valid records. Make sure you are using the official httpSMS template.")
 		return messages, result
 	}
 
 	if len(messages) > 1000 {
-		result.Add("document", "The uploaded file must contain less than 1000 records.")
+		result.AddWithParam("document", "max_records", "1000", "The uploaded file must contain less than 1000 records.")
 		return messages, result
 	}
 
@@ -89,10 +93,15 @@ func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID
 		return messages, result
 	}
 
+	result = v.validateContacts(ctx, userID, messages)
+	if len(result) != 0 {
+		return messages, result
+	}
+
 	return messages, result
 }
 
-func (v *BulkMessageHandlerValidator) parseFile(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, url.Values) {
+func (v *BulkMessageHandlerValidator) parseFile(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, responses.ValidationErrors) {
 	if header.Header.Get("Content-Type") == "text/csv" || strings.HasSuffix(header.Filename, ".csv") {
 		return v.parseCSV(ctxLogger, user, header)
 	}
@@ -102,12 +111,12 @@ func (v *BulkMessageHandlerValidator) parseFile(ctxLogger telemetry.Logger, user
 
 	ctxLogger.Error(stacktrace.NewError(fmt.Sprintf("cannot parse file [%s] for user [%s] with content type [%s]", header.Filename, user.ID, header.Header.Get("Content-Type"))))
 
-	result := url.Values{}
-	result.Add("document", fmt.Sprintf("The file [%s] is not a valid CSV or Excel file.", header.Filename))
+	result := responses.ValidationErrors{}
+	result.Add("document", "mime", fmt.Sprintf("The file [%s] is not a valid CSV or Excel file.", header.Filename))
 	return nil, result
 }
 
-func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, url.Values) {
+func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, responses.ValidationErrors) {
 	content, result := v.parseBytes(ctxLogger, user.ID, header)
 	if len(result) != 0 {
 		return nil, result
@@ -116,14 +125,14 @@ func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user
 	excel, err := excelize.OpenReader(bytes.NewReader(content))
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot generate excel file from [%s] for user [%s]", header.Filename, user.ID)))
-		result.Add("document", fmt.Sprintf("Cannot parse the uploaded excel file with name [%s].", header.Filename))
+		result.Add("document", "file", fmt.Sprintf("Cannot parse the uploaded excel file with name [%s].", header.Filename))
 		return nil, result
 	}
 
 	rows, err := excel.GetRows(excel.GetSheetName(0))
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot get rows from excel file [%s] for user [%s]", header.Filename, user.ID)))
-		result.Add("document", fmt.Sprintf("Cannot parse the uploaded excel file with name [%s].", header.Filename))
+		result.Add("document", "file", fmt.Sprintf("Cannot parse the uploaded excel file with name [%s].", header.Filename))
 		return nil, result
 	}
 
@@ -138,7 +147,7 @@ func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user
 			ctxLogger.Info(fmt.Sprintf("excel time = [%s]", row[3]))
 			sendAt, err = v.convertExcelTime(user, row[3])
 			if err != nil {
-				result.Add("document", fmt.Sprintf("Row [%d]: The SendTime [%s] is not in the correct format e.g [2006-01-02T15:04:05] where 2006 is the year, 01 is January, 02 is the second day of the month and the time is 15:04:05", index+1, row[3]))
+				result.AddWithParam(fmt.Sprintf("document[%d].SendTime", index+1), "date", "2006-01-02T15:04:05", fmt.Sprintf("Row [%d]: The SendTime [%s] is not in the correct format e.g [2006-01-02T15:04:05] where 2006 is the year, 01 is January, 02 is the second day of the month and the time is 15:04:05", index+1, row[3]))
 				return nil, result
 			}
 		}
@@ -151,7 +160,7 @@ func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user
 		})
 	}
 
-	return messages, url.Values{}
+	return messages, responses.ValidationErrors{}
 }
 
 func (v *BulkMessageHandlerValidator) convertExcelTime(user *entities.User, value string) (*time.Time, error) {
@@ -163,18 +172,18 @@ func (v *BulkMessageHandlerValidator) convertExcelTime(user *entities.User, valu
 	return &t, nil
 }
 
-func (v *BulkMessageHandlerValidator) parseBytes(ctxLogger telemetry.Logger, userID entities.UserID, header *multipart.FileHeader) ([]byte, url.Values) {
-	result := url.Values{}
+func (v *BulkMessageHandlerValidator) parseBytes(ctxLogger telemetry.Logger, userID entities.UserID, header *multipart.FileHeader) ([]byte, responses.ValidationErrors) {
+	result := responses.ValidationErrors{}
 
 	if header.Size >= 5000000 {
-		result.Add("document", fmt.Sprintf("The CSV file must be less than 500 KB the file you uploaded is [%s].", humanize.Bytes(uint64(header.Size))))
+		result.AddWithParam("document", "size", "5000000", fmt.Sprintf("The CSV file must be less than 500 KB the file you uploaded is [%s].", humanize.Bytes(uint64(header.Size))))
 		return nil, result
 	}
 
 	file, err := header.Open()
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot open file [%s] for reading for user [%s]", header.Filename, userID)))
-		result.Add("document", fmt.Sprintf("Cannot open the uploaded file with name [%s].", header.Filename))
+		result.Add("document", "file", fmt.Sprintf("Cannot open the uploaded file with name [%s].", header.Filename))
 		return nil, result
 	}
 	defer func() {
@@ -186,14 +195,14 @@ func (v *BulkMessageHandlerValidator) parseBytes(ctxLogger telemetry.Logger, use
 	b := new(bytes.Buffer)
 	if _, err = io.Copy(b, file); err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot copy file [%s] to buffer for user [%s]", header.Filename, userID)))
-		result.Add("document", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
+		result.Add("document", "file", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
 		return nil, result
 	}
 
 	return b.Bytes(), result
 }
 
-func (v *BulkMessageHandlerValidator) parseCSV(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, url.Values) {
+func (v *BulkMessageHandlerValidator) parseCSV(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, responses.ValidationErrors) {
 	content, result := v.parseBytes(ctxLogger, user.ID, header)
 	if len(result) != 0 {
 		return nil, result
@@ -202,46 +211,76 @@ func (v *BulkMessageHandlerValidator) parseCSV(ctxLogger telemetry.Logger, user
 	var messages []*requests.BulkMessage
 	if err := csvutil.Unmarshal(content, &messages); err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshall contents [%s] into type [%T] for file [%s] and user [%s]", content, messages, header.Filename, user.ID)))
-		result.Add("document", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
+		result.Add("document", "file", fmt.Sprintf("Cannot read the conents of the uploaded file [%s].", header.Filename))
 		return nil, result
 	}
 
-	return messages, url.Values{}
+	return messages, responses.ValidationErrors{}
 }
 
-func (v *BulkMessageHandlerValidator) validateMessages(messages []*requests.BulkMessage) url.Values {
-	result := url.Values{}
+func (v *BulkMessageHandlerValidator) validateMessages(messages []*requests.BulkMessage) responses.ValidationErrors {
+	result := responses.ValidationErrors{}
 	for index, message := range messages {
 		if _, err := phonenumbers.Parse(message.FromPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
-			result.Add("document", fmt.Sprintf("Row [%d]: The FromPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.FromPhoneNumber))
+			result.Add(fmt.Sprintf("document[%d].FromPhoneNumber", index+2), phoneNumberRule, fmt.Sprintf("Row [%d]: The FromPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.FromPhoneNumber))
 		}
 
 		if _, err := phonenumbers.Parse(message.ToPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
-			result.Add("document", fmt.Sprintf("Row [%d]: The ToPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.ToPhoneNumber))
+			result.Add(fmt.Sprintf("document[%d].ToPhoneNumber", index+2), phoneNumberRule, fmt.Sprintf("Row [%d]: The ToPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.ToPhoneNumber))
+		}
+
+		if !utf8.ValidString(message.Content) {
+			result.Add(fmt.Sprintf("document[%d].Content", index+2), "utf8", fmt.Sprintf("Row [%d]: The message content must be a valid UTF-8 string.", index+2))
 		}
 
 		if len(message.Content) > 1024 {
-			result.Add("document", fmt.Sprintf("Row [%d]: The message content must be less than 1024 characters.", index+2))
+			result.AddWithParam(fmt.Sprintf("document[%d].Content", index+2), "max", "1024", fmt.Sprintf("Row [%d]: The message content must be less than 1024 characters.", index+2))
 		}
 
 		if message.SendTime != nil && message.SendTime.After(time.Now().Add(24*time.Hour)) {
-			result.Add("document", fmt.Sprintf("Row [%d]: The SendTime [%s] cannot be more than 24 hours in the future.", index+2, message.SendTime.Format(time.RFC3339)))
+			result.AddWithParam(fmt.Sprintf("document[%d].SendTime", index+2), "before", "24h", fmt.Sprintf("Row [%d]: The SendTime [%s] cannot be more than 24 hours in the future.", index+2, message.SendTime.Format(time.RFC3339)))
 		}
 	}
 	return result
 }
 
-func (v *BulkMessageHandlerValidator) validateOwners(ctx context.Context, userID entities.UserID, messages []*requests.BulkMessage) url.Values {
+func (v *BulkMessageHandlerValidator) validateOwners(ctx context.Context, userID entities.UserID, messages []*requests.BulkMessage) responses.ValidationErrors {
 	numbers := map[string][]int{}
 	for index, message := range messages {
 		numbers[message.FromPhoneNumber] = append(numbers[message.FromPhoneNumber], index+2)
 	}
 
-	result := url.Values{}
+	result := responses.ValidationErrors{}
 	for number, rows := range numbers {
 		_, err := v.phoneService.Load(ctx, userID, strings.TrimSpace(number))
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-			result.Add("document", fmt.Sprintf("Rows [%s]: The FromPhoneNumber [%s] is not registered on your account", v.toString(rows), number))
+			result.Add("document", "exists", fmt.Sprintf("Rows [%s]: The FromPhoneNumber [%s] is not registered on your account", v.toString(rows), number))
+		}
+	}
+	return result
+}
+
+// validateContacts rejects the rows which are sent to contacts who do not want to receive transactional messages
+func (v *BulkMessageHandlerValidator) validateContacts(ctx context.Context, userID entities.UserID, messages []*requests.BulkMessage) responses.ValidationErrors {
+	ctx, span, ctxLogger := v.tracer.StartWithLogger(ctx, v.logger)
+	defer span.End()
+
+	contacts := map[string][]int{}
+	for index, message := range messages {
+		contacts[message.ToPhoneNumber] = append(contacts[message.ToPhoneNumber], index+2)
+	}
+
+	result := responses.ValidationErrors{}
+	for contact, rows := range contacts {
+		blocked, err := v.preferenceService.IsDoNotContact(ctx, userID, contact, entities.MessageCategoryTransactional)
+		if err != nil {
+			ctxLogger.Error(v.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot check the preferences of contact [%s] for user [%s]", contact, userID))))
+			result.Add("document", "unavailable", fmt.Sprintf("Rows [%s]: Cannot validate the preferences of the contact [%s]. Please try again later.", v.toString(rows), contact))
+			continue
+		}
+
+		if blocked {
+			result.Add("document", "do_not_contact", fmt.Sprintf("Rows [%s]: The contact [%s] does not want to receive [%s] messages", v.toString(rows), contact, entities.MessageCategoryTransactional))
 		}
 	}
 	return result
@@ -0,0 +1,137 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// ComplianceRuleHandlerValidator validates models used in handlers.ComplianceRuleHandler
+type ComplianceRuleHandlerValidator struct {
+	validator
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ComplianceService
+}
+
+// NewComplianceRuleHandlerValidator creates a new handlers.ComplianceRuleHandler validator
+func NewComplianceRuleHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ComplianceService,
+) (v *ComplianceRuleHandlerValidator) {
+	return &ComplianceRuleHandlerValidator{
+		logger:  logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// ValidateStore validates the requests.ComplianceRuleStore request
+func (validator *ComplianceRuleHandlerValidator) ValidateStore(ctx context.Context, request requests.ComplianceRuleStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return validator.validateRule(ctx, validator.validate(v), "", request)
+}
+
+// ValidateUpdate validates the requests.ComplianceRuleUpdate request
+func (validator *ComplianceRuleHandlerValidator) ValidateUpdate(ctx context.Context, request requests.ComplianceRuleUpdate) responses.ValidationErrors {
+	rules := validator.storeRules()
+	rules["ruleID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return validator.validateRule(ctx, validator.validate(v), request.RuleID, request.ComplianceRuleStore)
+}
+
+// validateRule checks the fields which cannot be validated with govalidator and that the country has no other rule
+func (validator *ComplianceRuleHandlerValidator) validateRule(ctx context.Context, result responses.ValidationErrors, ruleID string, request requests.ComplianceRuleStore) responses.ValidationErrors {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	if request.CountryCode != "" && !phonenumbers.GetSupportedRegions()[request.CountryCode] {
+		result.Add("country_code", "in", fmt.Sprintf("The country_code [%s] is not a supported ISO 3166-1 alpha-2 country code", request.CountryCode))
+	}
+
+	if _, err := time.LoadLocation(request.Timezone); err != nil {
+		result.Add("timezone", "timezone", fmt.Sprintf("The timezone [%s] is not a valid IANA time zone e.g. America/New_York", request.Timezone))
+	}
+
+	if (request.ForbiddenHoursStart == nil) != (request.ForbiddenHoursEnd == nil) {
+		result.Add("forbidden_hours_start", "required_with", "The forbidden_hours_start and forbidden_hours_end fields must be set together")
+	}
+	for field, hour := range map[string]*uint{"forbidden_hours_start": request.ForbiddenHoursStart, "forbidden_hours_end": request.ForbiddenHoursEnd} {
+		if hour != nil && *hour > 23 {
+			result.AddWithParam(field, "max", "23", fmt.Sprintf("The %s field must be an hour of the day between 0 and 23", field))
+		}
+	}
+
+	if request.OptOutFooterRequired && len(request.OptOutKeywords) == 0 {
+		result.Add("opt_out_keywords", "required_if", "The opt_out_keywords field must contain a keyword e.g. STOP when the opt-out footer is required")
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	rule, err := validator.service.LoadByCountry(ctx, request.CountryCode)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load compliance rule for country [%s]", request.CountryCode))))
+		result.Add("country_code", "unavailable", fmt.Sprintf("could not validate 'country_code' [%s], please try again later", request.CountryCode))
+		return result
+	}
+
+	if rule.ID.String() != ruleID {
+		result.Add("country_code", "unique", fmt.Sprintf("The country [%s] already has the compliance rule with ID [%s]", request.CountryCode, rule.ID))
+	}
+
+	return result
+}
+
+func (validator *ComplianceRuleHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"country_code": []string{
+			"required",
+			"len:2",
+		},
+		"opt_out_keywords": []string{
+			"max:20",
+		},
+		"timezone": []string{
+			"required",
+			"max:50",
+		},
+		"forbidden_content": []string{
+			"max:100",
+		},
+		"forbidden_categories": []string{
+			multipleInRule + ":" + strings.Join([]string{
+				entities.MessageCategoryTransactional.String(),
+				entities.MessageCategoryMarketing.String(),
+				entities.MessageCategoryOTP.String(),
+			}, ","),
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// ContactPreferenceHandlerValidator validates models used in handlers.ContactPreferenceHandler
+type ContactPreferenceHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewContactPreferenceHandlerValidator creates a new handlers.ContactPreferenceHandler validator
+func NewContactPreferenceHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *ContactPreferenceHandlerValidator) {
+	return &ContactPreferenceHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateShow validates the requests.ContactPreference request
+func (validator *ContactPreferenceHandlerValidator) ValidateShow(_ context.Context, request requests.ContactPreference) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"contact": []string{
+				"required",
+				contactPhoneNumberRule,
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateUpdate validates the requests.ContactPreferenceUpdate request
+func (validator *ContactPreferenceHandlerValidator) ValidateUpdate(_ context.Context, request requests.ContactPreferenceUpdate) responses.ValidationErrors {
+	rules := govalidator.MapData{
+		"contact": []string{
+			"required",
+			contactPhoneNumberRule,
+		},
+		"timezone": []string{
+			"required",
+			"max:64",
+		},
+	}
+
+	if len(request.DoNotContactCategories) > 0 {
+		rules["do_not_contact_categories"] = []string{
+			multipleInRule + ":" + strings.Join([]string{
+				entities.MessageCategoryTransactional.String(),
+				entities.MessageCategoryMarketing.String(),
+				entities.MessageCategoryOTP.String(),
+			}, ","),
+		}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := validator.validate(v)
+	if len(result) > 0 {
+		return result
+	}
+
+	if request.Language != "" && !languageRegex.MatchString(request.Language) {
+		result.Add("language", "language", fmt.Sprintf("the language [%s] must be a language code e.g. fr", request.Language))
+	}
+
+	if _, err := time.LoadLocation(request.Timezone); err != nil {
+		result.Add("timezone", "timezone", fmt.Sprintf("the timezone [%s] is not a valid IANA timezone e.g. Europe/Paris", request.Timezone))
+	}
+
+	if (request.SendWindowStart == nil) != (request.SendWindowEnd == nil) {
+		result.Add("send_window_end", "required_with", "the send_window_start and send_window_end must be set together")
+	}
+
+	if request.SendWindowStart != nil && *request.SendWindowStart > 23 {
+		result.AddWithParam("send_window_start", "max", "23", "the send_window_start must be an hour between 0 and 23")
+	}
+
+	if request.SendWindowEnd != nil && *request.SendWindowEnd > 23 {
+		result.AddWithParam("send_window_end", "max", "23", "the send_window_end must be an hour between 0 and 23")
+	}
+
+	return result
+}
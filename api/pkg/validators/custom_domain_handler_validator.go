@@ -0,0 +1,74 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+var customDomainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// CustomDomainHandlerValidator validates models used in handlers.CustomDomainHandler
+type CustomDomainHandlerValidator struct {
+	validator
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.CustomDomainService
+}
+
+// NewCustomDomainHandlerValidator creates a new handlers.CustomDomainHandler validator
+func NewCustomDomainHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.CustomDomainService,
+) (v *CustomDomainHandlerValidator) {
+	return &CustomDomainHandlerValidator{
+		logger:  logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// ValidateStore validates the requests.CustomDomainStore request
+func (validator *CustomDomainHandlerValidator) ValidateStore(ctx context.Context, request requests.CustomDomainStore, userID entities.UserID) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"domain": []string{
+				"required",
+				"max:253",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if len(result) != 0 {
+		return result
+	}
+
+	if !customDomainRegex.MatchString(request.Domain) {
+		result.Add("domain", "domain", fmt.Sprintf("the domain [%s] must be a domain name e.g. sms.example.com", request.Domain))
+		return result
+	}
+
+	taken, err := validator.service.IsTaken(ctx, userID, request.Domain)
+	if err != nil {
+		validator.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot check if domain [%s] is taken", request.Domain)))
+		result.Add("domain", "unavailable", fmt.Sprintf("cannot check if the domain [%s] is available, please try again later", request.Domain))
+		return result
+	}
+
+	if taken {
+		result.Add("domain", "unique", fmt.Sprintf("the domain [%s] is already used by another account", request.Domain))
+	}
+
+	return result
+}
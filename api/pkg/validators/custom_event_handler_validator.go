@@ -0,0 +1,82 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// CustomEventHandlerValidator validates models used in handlers.CustomEventHandler
+type CustomEventHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewCustomEventHandlerValidator creates a new handlers.CustomEventHandler validator
+func NewCustomEventHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *CustomEventHandlerValidator) {
+	return &CustomEventHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.CustomEventIndex request
+func (validator *CustomEventHandlerValidator) ValidateIndex(_ context.Context, request requests.CustomEventIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidatePublish validates the requests.CustomEventPublish request
+func (validator *CustomEventHandlerValidator) ValidatePublish(_ context.Context, request requests.CustomEventPublish) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"type": []string{
+				"required",
+				"max:100",
+			},
+			"source": []string{
+				"max:255",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if request.Type != "" && !entities.CustomEventTypeRegex.MatchString(request.Type) {
+		result.AddWithParam("type", "regex", entities.CustomEventTypeRegex.String(), "the type must contain only lowercase letters, numbers, dashes and underscores separated by dots e.g. order.shipped")
+	}
+
+	if len(request.Data) == 0 || !json.Valid(request.Data) {
+		result.Add("data", "json", "The data field is required and must be a valid JSON value")
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// DebugRequestHandlerValidator validates models used in handlers.DebugRequestHandler
+type DebugRequestHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewDebugRequestHandlerValidator creates a new handlers.DebugRequestHandler validator
+func NewDebugRequestHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *DebugRequestHandlerValidator) {
+	return &DebugRequestHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.DebugRequestIndex request
+func (validator *DebugRequestHandlerValidator) ValidateIndex(_ context.Context, request requests.DebugRequestIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateEnable validates the requests.DebugModeEnable request
+func (validator *DebugRequestHandlerValidator) ValidateEnable(_ context.Context, request requests.DebugModeEnable) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"duration_minutes": []string{
+				"min:1",
+				"max:1440",
+			},
+		},
+	})
+	return validator.validate(v)
+}
@@ -3,13 +3,13 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 
 	"github.com/NdoleStudio/httpsms/pkg/discord"
 	"github.com/palantir/stacktrace"
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/thedevsaddam/govalidator"
 )
@@ -36,7 +36,7 @@ func NewDiscordHandlerValidator(
 }
 
 // ValidateIndex validates the requests.DiscordIndex request
-func (validator *DiscordHandlerValidator) ValidateIndex(_ context.Context, request requests.DiscordIndex) url.Values {
+func (validator *DiscordHandlerValidator) ValidateIndex(_ context.Context, request requests.DiscordIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -56,11 +56,11 @@ func (validator *DiscordHandlerValidator) ValidateIndex(_ context.Context, reque
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
 
 // ValidateStore validates the requests.DiscordStore request
-func (validator *DiscordHandlerValidator) ValidateStore(ctx context.Context, request requests.DiscordStore) url.Values {
+func (validator *DiscordHandlerValidator) ValidateStore(ctx context.Context, request requests.DiscordStore) responses.ValidationErrors {
 	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
 	defer span.End()
 
@@ -85,7 +85,7 @@ func (validator *DiscordHandlerValidator) ValidateStore(ctx context.Context, req
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validate(v)
 	if len(result) > 0 {
 		return result
 	}
@@ -93,20 +93,20 @@ func (validator *DiscordHandlerValidator) ValidateStore(ctx context.Context, req
 	if _, _, err := validator.client.Channel.Get(ctx, request.IncomingChannelID); err != nil {
 		msg := fmt.Sprintf("cannot fetch discord channel with ID [%s]", request.IncomingChannelID)
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
-		result.Add("incoming_channel_id", fmt.Sprintf("cannot fetch discord channel with ID [%s] make sure the bot has access to the channel", request.IncomingChannelID))
+		result.Add("incoming_channel_id", "exists", fmt.Sprintf("cannot fetch discord channel with ID [%s] make sure the bot has access to the channel", request.IncomingChannelID))
 	}
 
 	if _, _, err := validator.client.Guild.Get(ctx, request.ServerID); err != nil {
 		msg := fmt.Sprintf("cannot fetch discord channel with ID [%s]", request.IncomingChannelID)
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
-		result.Add("server_id", fmt.Sprintf("cannot fetch discord server with ID [%s] make sure the bot has access to the channel", request.ServerID))
+		result.Add("server_id", "exists", fmt.Sprintf("cannot fetch discord server with ID [%s] make sure the bot has access to the channel", request.ServerID))
 	}
 
 	return result
 }
 
 // ValidateUpdate validates the requests.DiscordUpdate request
-func (validator *DiscordHandlerValidator) ValidateUpdate(ctx context.Context, request requests.DiscordUpdate) url.Values {
+func (validator *DiscordHandlerValidator) ValidateUpdate(ctx context.Context, request requests.DiscordUpdate) responses.ValidationErrors {
 	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
 	defer span.End()
 
@@ -135,7 +135,7 @@ func (validator *DiscordHandlerValidator) ValidateUpdate(ctx context.Context, re
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validate(v)
 	if len(result) > 0 {
 		return result
 	}
@@ -143,13 +143,13 @@ func (validator *DiscordHandlerValidator) ValidateUpdate(ctx context.Context, re
 	if _, _, err := validator.client.Channel.Get(ctx, request.IncomingChannelID); err != nil {
 		msg := fmt.Sprintf("cannot fetch discord channel with ID [%s]", request.IncomingChannelID)
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
-		result.Add("incoming_channel_id", fmt.Sprintf("cannot fetch discord channel with ID [%s] make sure the bot has access to the channel", request.IncomingChannelID))
+		result.Add("incoming_channel_id", "exists", fmt.Sprintf("cannot fetch discord channel with ID [%s] make sure the bot has access to the channel", request.IncomingChannelID))
 	}
 
 	if _, _, err := validator.client.Guild.Get(ctx, request.ServerID); err != nil {
 		msg := fmt.Sprintf("cannot fetch discord channel with ID [%s]", request.IncomingChannelID)
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
-		result.Add("server_id", fmt.Sprintf("cannot fetch discord server with ID [%s] make sure the bot has access to the channel", request.ServerID))
+		result.Add("server_id", "exists", fmt.Sprintf("cannot fetch discord server with ID [%s] make sure the bot has access to the channel", request.ServerID))
 	}
 
 	return result
@@ -3,14 +3,26 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/nyaruka/phonenumbers"
 	"github.com/thedevsaddam/govalidator"
 )
 
+// heartbeatSignalMaxRange is the maximum time range of the signal of heartbeats which can be fetched at once
+const heartbeatSignalMaxRange = 31 * 24 * time.Hour
+
+// heartbeatICCIDRegex matches the serial number of a SIM card which can end with the F padding character
+var heartbeatICCIDRegex = regexp.MustCompile(`^[0-9]{18,21}F?$`)
+
 // HeartbeatHandlerValidator validates models used in handlers.HeartbeatHandler
 type HeartbeatHandlerValidator struct {
 	validator
@@ -30,7 +42,7 @@ func NewHeartbeatHandlerValidator(
 }
 
 // ValidateIndex validates the requests.HeartbeatIndex request
-func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, request requests.HeartbeatIndex) url.Values {
+func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, request requests.HeartbeatIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -54,11 +66,11 @@ func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, req
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
 
 // ValidateStore validates the requests.HeartbeatStore request
-func (validator *HeartbeatHandlerValidator) ValidateStore(_ context.Context, request requests.HeartbeatStore) url.Values {
+func (validator *HeartbeatHandlerValidator) ValidateStore(_ context.Context, request requests.HeartbeatStore) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -70,5 +82,133 @@ func (validator *HeartbeatHandlerValidator) ValidateStore(_ context.Context, req
 			},
 		},
 	})
-	return v.ValidateStruct()
+
+	result := validator.validate(v)
+	if request.SignalStrength != nil && (*request.SignalStrength < -150 || *request.SignalStrength > 0) {
+		result.Add("signal_strength", "numeric_between", "the signal_strength must be in dBm between -150 and 0")
+	}
+
+	if request.SignalBars != nil && *request.SignalBars > 4 {
+		result.AddWithParam("signal_bars", "max", "4", "the signal_bars must be between 0 and 4")
+	}
+
+	if request.NetworkType != nil && !slices.Contains(entities.HeartbeatNetworkTypes, entities.HeartbeatNetworkType(*request.NetworkType)) {
+		result.Add("network_type", "in", fmt.Sprintf("the network_type [%s] must be one of %v", *request.NetworkType, entities.HeartbeatNetworkTypes))
+	}
+
+	if len(request.SIMs) > 2 {
+		result.AddWithParam("sims", "max", "2", "the sims field cannot have more than 2 SIM cards")
+	}
+
+	for index, sim := range request.SIMs {
+		if sim.PhoneNumber != "" {
+			if _, err := phonenumbers.Parse(sim.PhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
+				result.Add(fmt.Sprintf("sims[%d].phone_number", index), phoneNumberRule, fmt.Sprintf("the phone_number [%s] of the SIM card in [%s] is not a valid E.164 phone number", sim.PhoneNumber, sim.SIM))
+			}
+		}
+
+		if len(sim.Operator) > 100 {
+			result.AddWithParam(fmt.Sprintf("sims[%d].operator", index), "max", "100", "the operator of the SIM card cannot be more than 100 characters")
+		}
+
+		if sim.ICCID != "" && !heartbeatICCIDRegex.MatchString(sim.ICCID) {
+			result.Add(fmt.Sprintf("sims[%d].iccid", index), "regex", fmt.Sprintf("the iccid [%s] of the SIM card in [%s] must have between 18 and 22 digits", sim.ICCID, sim.SIM))
+		}
+	}
+
+	return result
+}
+
+// ValidateSignal validates the requests.HeartbeatSignal request
+func (validator *HeartbeatHandlerValidator) ValidateSignal(_ context.Context, request requests.HeartbeatSignal) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"interval": []string{
+				"required",
+				"numeric",
+				"numeric_between:60,86400",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	for field, value := range map[string]string{"from": request.From, "to": request.To} {
+		if _, err := time.Parse(time.RFC3339, value); value != "" && err != nil {
+			result.Add(field, "date", fmt.Sprintf("the %s field must be a timestamp in the RFC3339 format e.g. 2022-06-05T14:26:09Z", field))
+		}
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	if !request.FromTime().Before(request.ToTime()) {
+		result.Add("from", "before", "the from timestamp must be before the to timestamp")
+	}
+
+	if request.ToTime().Sub(request.FromTime()) > heartbeatSignalMaxRange {
+		result.AddWithParam("to", "max", heartbeatSignalMaxRange.String(), "the time range between from and to cannot be more than 31 days")
+	}
+
+	return result
+}
+
+// ValidateRollups validates the requests.HeartbeatRollupIndex request
+func (validator *HeartbeatHandlerValidator) ValidateRollups(_ context.Context, request requests.HeartbeatRollupIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"resolution": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.HeartbeatRollupResolutionHour.String(),
+					entities.HeartbeatRollupResolutionDay.String(),
+				}, ","),
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateReport validates requests.HeartbeatReport
+func (validator *HeartbeatHandlerValidator) ValidateReport(_ context.Context, request requests.HeartbeatReport) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phone": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"period": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.HeartbeatReportPeriodDay.String(),
+					entities.HeartbeatReportPeriodWeek.String(),
+					entities.HeartbeatReportPeriodMonth.String(),
+				}, ","),
+			},
+		},
+	})
+
+	return validator.validate(v)
 }
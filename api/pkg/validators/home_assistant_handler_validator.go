@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// HomeAssistantHandlerValidator validates models used in handlers.HomeAssistantHandler
+type HomeAssistantHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewHomeAssistantHandlerValidator creates a new HomeAssistantHandlerValidator
+func NewHomeAssistantHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *HomeAssistantHandlerValidator) {
+	return &HomeAssistantHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateNotify validates the requests.HomeAssistantNotify request
+func (validator *HomeAssistantHandlerValidator) ValidateNotify(_ context.Context, request requests.HomeAssistantNotify) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"message": []string{
+				"required",
+				"min:1",
+				"max:2048",
+			},
+			"target": []string{
+				"required",
+				"min:1",
+				"max:10",
+				multiplePhoneNumberRule,
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateSensor validates the requests.HomeAssistantSensor request
+func (validator *HomeAssistantHandlerValidator) ValidateSensor(_ context.Context, request requests.HomeAssistantSensor) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+		},
+	})
+	return validator.validate(v)
+}
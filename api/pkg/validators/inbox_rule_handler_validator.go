@@ -0,0 +1,110 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// InboxRuleHandlerValidator validates models used in handlers.InboxRuleHandler
+type InboxRuleHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewInboxRuleHandlerValidator creates a new handlers.InboxRuleHandler validator
+func NewInboxRuleHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *InboxRuleHandlerValidator) {
+	return &InboxRuleHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.InboxRuleIndex request
+func (validator *InboxRuleHandlerValidator) ValidateIndex(_ context.Context, request requests.InboxRuleIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates the requests.InboxRuleStore request
+func (validator *InboxRuleHandlerValidator) ValidateStore(_ context.Context, request requests.InboxRuleStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return validator.validateRule(validator.validate(v), request)
+}
+
+// ValidateUpdate validates the requests.InboxRuleUpdate request
+func (validator *InboxRuleHandlerValidator) ValidateUpdate(_ context.Context, request requests.InboxRuleUpdate) responses.ValidationErrors {
+	rules := validator.storeRules()
+	rules["ruleID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return validator.validateRule(validator.validate(v), request.InboxRuleStore)
+}
+
+func (validator *InboxRuleHandlerValidator) validateRule(result responses.ValidationErrors, request requests.InboxRuleStore) responses.ValidationErrors {
+	if request.Sender == "" && request.Content == "" {
+		result.Add("sender", "required", "The sender field is required when the content field is empty")
+	}
+
+	if request.Label == "" && !request.Archive && !request.MarkRead {
+		result.Add("label", "required", "The rule must add a label, archive or mark the thread as read")
+	}
+
+	if request.Label != "" && !entities.MessageThreadLabelRegex.MatchString(request.Label) {
+		result.AddWithParam("label", "regex", entities.MessageThreadLabelRegex.String(), messageThreadLabelRegexMessage)
+	}
+
+	return result
+}
+
+func (validator *InboxRuleHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:50",
+		},
+		"sender": []string{
+			"max:50",
+		},
+		"content": []string{
+			"max:255",
+		},
+	}
+}
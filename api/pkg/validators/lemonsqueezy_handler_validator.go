@@ -3,8 +3,8 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	lemonsqueezy "github.com/NdoleStudio/lemonsqueezy-go"
 )
@@ -30,17 +30,15 @@ func NewLemonsqueezyHandlerValidator(
 }
 
 // ValidateEvent checks that an event is coming from lemonsqueezy
-func (validator *LemonsqueezyHandlerValidator) ValidateEvent(ctx context.Context, signature string, request []byte) url.Values {
+func (validator *LemonsqueezyHandlerValidator) ValidateEvent(ctx context.Context, signature string, request []byte) responses.ValidationErrors {
 	_, span := validator.tracer.Start(ctx)
 	defer span.End()
 
 	isValid := validator.client.Webhooks.Verify(ctx, signature, request)
 	if !isValid {
-		return url.Values{
-			"body": []string{
-				"The signature is not valid",
-			},
-		}
+		result := responses.ValidationErrors{}
+		result.Add("body", "signature", "The signature is not valid")
+		return result
 	}
-	return url.Values{}
+	return responses.ValidationErrors{}
 }
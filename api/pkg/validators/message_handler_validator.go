@@ -4,26 +4,42 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/thedevsaddam/govalidator"
 )
 
+// messageMaxValidityPeriod is the maximum validity period of a message in seconds which is 7 days
+const messageMaxValidityPeriod = 7 * 24 * 60 * 60
+
+// deliveryReportPDURegex matches the raw PDU of a delivery report which is encoded in hexadecimal
+var deliveryReportPDURegex = regexp.MustCompile(`^([0-9A-F]{2})*$`)
+
 // MessageHandlerValidator validates models used in handlers.MessageHandler
 type MessageHandlerValidator struct {
 	validator
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	phoneService   *services.PhoneService
-	tokenValidator *TurnstileTokenValidator
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	phoneService       *services.PhoneService
+	complianceService  *services.ComplianceService
+	suppressionService *services.SuppressionService
+	preferenceService  *services.ContactPreferenceService
+	tokenValidator     *TurnstileTokenValidator
+	maxSegments        uint
 }
 
 // NewMessageHandlerValidator creates a new handlers.MessageHandler validator
@@ -31,18 +47,26 @@ func NewMessageHandlerValidator(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
+	complianceService *services.ComplianceService,
+	suppressionService *services.SuppressionService,
+	preferenceService *services.ContactPreferenceService,
 	tokenValidator *TurnstileTokenValidator,
+	maxSegments uint,
 ) (v *MessageHandlerValidator) {
 	return &MessageHandlerValidator{
-		logger:         logger.WithService(fmt.Sprintf("%T", v)),
-		tracer:         tracer,
-		phoneService:   phoneService,
-		tokenValidator: tokenValidator,
+		logger:             logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:             tracer,
+		phoneService:       phoneService,
+		complianceService:  complianceService,
+		suppressionService: suppressionService,
+		preferenceService:  preferenceService,
+		tokenValidator:     tokenValidator,
+		maxSegments:        maxSegments,
 	}
 }
 
 // ValidateMessageReceive validates the requests.MessageReceive request
-func (validator MessageHandlerValidator) ValidateMessageReceive(_ context.Context, request requests.MessageReceive) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageReceive(_ context.Context, request requests.MessageReceive) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -54,8 +78,6 @@ func (validator MessageHandlerValidator) ValidateMessageReceive(_ context.Contex
 				"required",
 			},
 			"content": []string{
-				"required",
-				"min:1",
 				"max:2048",
 			},
 			"sim": []string{
@@ -68,11 +90,16 @@ func (validator MessageHandlerValidator) ValidateMessageReceive(_ context.Contex
 		},
 	})
 
-	return v.ValidateStruct()
+	result := validator.validateContent(validator.validate(v), request.Content)
+	if request.Content == "" && len(request.MediaURLs) == 0 {
+		result.Add("content", "required", "The content field is required")
+	}
+
+	return validator.validateMediaURLs(result, request.MediaURLs)
 }
 
 // ValidateMessageSend validates the requests.MessageSend request
-func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context, userID entities.UserID, request requests.MessageSend) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context, userID entities.UserID, request requests.MessageSend) responses.ValidationErrors {
 	ctx, span := validator.tracer.Start(ctx)
 	defer span.End()
 
@@ -88,6 +115,40 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 			"request_id": []string{
 				"max:255",
 			},
+			"category": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.MessageCategoryTransactional.String(),
+					entities.MessageCategoryMarketing.String(),
+					entities.MessageCategoryOTP.String(),
+				}, ","),
+			},
+			"priority": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.MessagePriorityLow.String(),
+					entities.MessagePriorityNormal.String(),
+					entities.MessagePriorityHigh.String(),
+				}, ","),
+			},
+			"sender_name": []string{
+				"alpha_dash",
+				"max:50",
+			},
+			"routing_tag": []string{
+				"alpha_dash",
+				"max:50",
+			},
+			"sim": []string{
+				"in:" + strings.Join([]string{
+					entities.SIM1.String(),
+					entities.SIM2.String(),
+					entities.SIMDefault.String(),
+				}, ","),
+			},
+			"phone_pool_id": []string{
+				"uuid",
+			},
 			"from": []string{
 				"required",
 				phoneNumberRule,
@@ -100,26 +161,197 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validateContent(validator.validate(v), request.Content)
+	result = validator.validateChannel(result, request)
+	result = validator.validateSegments(result, request.Content, request.Encrypted, entities.MessageChannel(request.Channel))
+	result = validator.validateExpiry(result, request.SendAt, request.ExpiresAt, request.ValidityPeriod)
+	if _, err := uuid.Parse(request.PhonePoolID); err == nil && request.From == "" {
+		result.Add("phone_pool_id", "exists", fmt.Sprintf("no phone pool with phones found with ID [%s]", request.PhonePoolID))
+	}
+	if request.VCard != nil && request.VCard.Name == "" {
+		result.Add("vcard.name", "required", "the vcard must have a name")
+	}
+	if request.VCard != nil && len(request.VCard.PhoneNumbers) == 0 {
+		result.AddWithParam("vcard.phone_numbers", "min", "1", "the vcard must have at least 1 phone number")
+	}
 	if len(result) != 0 {
 		return result
 	}
 
-	_, err := validator.phoneService.Load(ctx, userID, request.From)
+	phone, err := validator.phoneService.Load(ctx, userID, request.From)
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-		result.Add("from", fmt.Sprintf("no phone found with with 'from' number [%s]. install the android app on your phone to start sending messages", request.From))
+		result.Add("from", "exists", fmt.Sprintf("no phone found with with 'from' number [%s]. install the android app on your phone to start sending messages", request.From))
 	}
 
 	if err != nil {
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
-		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+		result.Add("from", "unavailable", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+	}
+
+	if err == nil && phone.IsDeactivated() {
+		result.Add("from", "active", fmt.Sprintf("the phone with 'from' number [%s] is deactivated. reactivate it to start sending messages", request.From))
+	}
+
+	if err == nil && request.SIM != "" && request.SIM != entities.SIMDefault.String() && !phone.HasSIMSlot(entities.SIM(request.SIM)) {
+		result.Add("sim", "exists", fmt.Sprintf("the phone [%s] has no SIM card in [%s]. it reported SIM cards in [%s]", request.From, request.SIM, strings.Join(phone.SIMSlots, ", ")))
+	}
+
+	result = validator.validateSuppression(ctx, result, userID, request.From, request.To, entities.MessageCategory(request.Category))
+	result = validator.validateContactPreference(ctx, result, userID, request.To, entities.MessageCategory(request.Category))
+	return validator.validateCompliance(ctx, result, services.ComplianceCheckParams{
+		Contact:   request.To,
+		Content:   request.Content,
+		Encrypted: request.Encrypted,
+		Category:  entities.MessageCategory(request.Category),
+		SendAt:    request.SendAt,
+	})
+}
+
+// validateContent rejects content which is not valid UTF-8 since it cannot be sent by the android phone or delivered to webhooks
+func (validator MessageHandlerValidator) validateContent(result responses.ValidationErrors, content string) responses.ValidationErrors {
+	if !utf8.ValidString(content) {
+		result.Add("content", "utf8", "the content must be a valid UTF-8 string")
+	}
+	return result
+}
+
+// validateSegments rejects SMS content which needs more segments than the limit configured with MESSAGE_MAX_SEGMENTS
+func (validator MessageHandlerValidator) validateSegments(result responses.ValidationErrors, content string, encrypted bool, channel entities.MessageChannel) responses.ValidationErrors {
+	// the segments of encrypted content are only known after it is decrypted by the phone
+	if validator.maxSegments == 0 || encrypted || entities.MessageChannelSanitized(channel) != entities.MessageChannelSMS {
+		return result
+	}
+
+	if segments := entities.MessageSegmentCount(content); segments > validator.maxSegments {
+		result.AddWithParam("content", "max_segments", strconv.FormatUint(uint64(validator.maxSegments), 10), fmt.Sprintf("the content needs [%d] SMS segments with the [%s] encoding which is more than the limit of [%d] segments", segments, entities.MessageContentEncoding(content), validator.maxSegments))
+	}
+	return result
+}
+
+func (validator MessageHandlerValidator) validateExpiry(result responses.ValidationErrors, sendAt *time.Time, expiresAt *time.Time, validityPeriod uint) responses.ValidationErrors {
+	if validityPeriod > messageMaxValidityPeriod {
+		result.AddWithParam("validity_period", "max", strconv.Itoa(messageMaxValidityPeriod), fmt.Sprintf("the validity_period cannot be more than [%d] seconds", messageMaxValidityPeriod))
+	}
+
+	if expiresAt == nil {
+		return result
+	}
+
+	if validityPeriod > 0 {
+		result.AddWithParam("expires_at", "prohibited_with", "validity_period", "the expires_at and validity_period fields cannot be set at the same time")
+	}
+
+	if !expiresAt.After(time.Now().UTC()) {
+		result.AddWithParam("expires_at", "after", "now", "the expires_at time must be in the future")
+	}
+
+	if sendAt != nil && !expiresAt.After(*sendAt) {
+		result.AddWithParam("expires_at", "after", "send_at", "the expires_at time must be after the send_at time")
+	}
+
+	return result
+}
+
+func (validator MessageHandlerValidator) validateSuppression(ctx context.Context, result responses.ValidationErrors, userID entities.UserID, owner string, contact string, category entities.MessageCategory) responses.ValidationErrors {
+	if !category.IsMarketing() {
+		return result
+	}
+
+	ctx, span := validator.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := validator.tracer.CtxLogger(validator.logger, span)
+
+	suppressed, err := validator.suppressionService.IsSuppressed(ctx, userID, owner, contact)
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not check if contact [%s] is suppressed for owner [%s]", contact, owner))))
+		result.Add("to", "unavailable", fmt.Sprintf("could not validate the suppression list for [%s], please try again later", contact))
+		return result
+	}
+
+	if suppressed {
+		result.Add("to", "opted_out", fmt.Sprintf("the contact [%s] has opted out of marketing messages from [%s]", contact, owner))
+	}
+
+	return result
+}
+
+// validateContactPreference rejects messages in a category which the contact does not want to receive
+func (validator MessageHandlerValidator) validateContactPreference(ctx context.Context, result responses.ValidationErrors, userID entities.UserID, contact string, category entities.MessageCategory) responses.ValidationErrors {
+	ctx, span := validator.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := validator.tracer.CtxLogger(validator.logger, span)
+
+	category = entities.MessageCategorySanitized(category)
+	blocked, err := validator.preferenceService.IsDoNotContact(ctx, userID, contact, category)
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not check the preferences of contact [%s] for user [%s]", contact, userID))))
+		result.Add("to", "unavailable", fmt.Sprintf("could not validate the preferences of [%s], please try again later", contact))
+		return result
+	}
+
+	if blocked {
+		result.Add("to", "do_not_contact", fmt.Sprintf("the contact [%s] does not want to receive [%s] messages", contact, category))
+	}
+
+	return result
+}
+
+func (validator MessageHandlerValidator) validateCompliance(ctx context.Context, result responses.ValidationErrors, params services.ComplianceCheckParams) responses.ValidationErrors {
+	ctx, span := validator.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := validator.tracer.CtxLogger(validator.logger, span)
+
+	violations, err := validator.complianceService.Check(ctx, params)
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not check compliance rules for contact [%s]", params.Contact))))
+		result.Add("to", "unavailable", fmt.Sprintf("could not validate the compliance rules for [%s], please try again later", params.Contact))
+		return result
+	}
+
+	for _, violation := range violations {
+		result.Add(violation.Field, violation.Rule, violation.Message)
+	}
+
+	return result
+}
+
+func (validator MessageHandlerValidator) validateChannel(result responses.ValidationErrors, request requests.MessageSend) responses.ValidationErrors {
+	channel := entities.MessageChannel(request.Channel)
+	if channel != entities.MessageChannelSMS && channel != entities.MessageChannelWhatsApp && channel != entities.MessageChannelMMS {
+		result.AddWithParam("channel", "in", strings.Join([]string{entities.MessageChannelSMS.String(), entities.MessageChannelMMS.String(), entities.MessageChannelWhatsApp.String()}, ","), fmt.Sprintf("the channel [%s] is not supported, use [%s], [%s] or [%s]", request.Channel, entities.MessageChannelSMS, entities.MessageChannelMMS, entities.MessageChannelWhatsApp))
+		return result
+	}
+
+	if len(request.MediaURLs) > 0 && !channel.SupportsMedia() {
+		result.Add("media_urls", "prohibited", fmt.Sprintf("media attachments are not supported on the [%s] channel, use the [%s] channel to send picture messages", channel, entities.MessageChannelMMS))
+	}
+
+	if channel == entities.MessageChannelMMS && len(request.MediaURLs) == 0 {
+		result.Add("media_urls", "required", fmt.Sprintf("you must attach at least 1 media file to a message on the [%s] channel", channel))
+	}
+
+	return validator.validateMediaURLs(result, request.MediaURLs)
+}
+
+func (validator MessageHandlerValidator) validateMediaURLs(result responses.ValidationErrors, mediaURLs []string) responses.ValidationErrors {
+	if len(mediaURLs) > 10 {
+		result.AddWithParam("media_urls", "max", "10", "you can attach a maximum of 10 media files to a message")
+	}
+
+	for index, mediaURL := range mediaURLs {
+		if _, err := url.ParseRequestURI(mediaURL); err != nil || len(mediaURL) > 1000 {
+			result.Add(fmt.Sprintf("media_urls[%d]", index), "url", fmt.Sprintf("the media URL [%s] is not a valid URL", mediaURL))
+		}
 	}
 
 	return result
 }
 
 // ValidateMessageBulkSend validates the requests.MessageBulkSend request
-func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Context, userID entities.UserID, request requests.MessageBulkSend) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Context, userID entities.UserID, request requests.MessageBulkSend) responses.ValidationErrors {
 	ctx, span := validator.tracer.Start(ctx)
 	defer span.End()
 
@@ -134,6 +366,26 @@ func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Con
 				"min:1",
 				multipleContactPhoneNumberRule,
 			},
+			"category": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.MessageCategoryTransactional.String(),
+					entities.MessageCategoryMarketing.String(),
+					entities.MessageCategoryOTP.String(),
+				}, ","),
+			},
+			"priority": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					entities.MessagePriorityLow.String(),
+					entities.MessagePriorityNormal.String(),
+					entities.MessagePriorityHigh.String(),
+				}, ","),
+			},
+			"sender_name": []string{
+				"alpha_dash",
+				"max:50",
+			},
 			"from": []string{
 				"required",
 				phoneNumberRule,
@@ -146,26 +398,43 @@ func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Con
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validateExpiry(validator.validate(v), nil, request.ExpiresAt, request.ValidityPeriod)
+	result = validator.validateContent(result, request.Content)
+	result = validator.validateSegments(result, request.Content, request.Encrypted, entities.MessageChannelSMS)
 	if len(result) != 0 {
 		return result
 	}
 
-	_, err := validator.phoneService.Load(ctx, userID, request.From)
+	phone, err := validator.phoneService.Load(ctx, userID, request.From)
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-		result.Add("from", fmt.Sprintf("no phone found with with 'from' number [%s]. Install the android app on your phone to start sending messages", request.From))
+		result.Add("from", "exists", fmt.Sprintf("no phone found with with 'from' number [%s]. Install the android app on your phone to start sending messages", request.From))
 	}
 
 	if err != nil {
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.From))))
-		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+		result.Add("from", "unavailable", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
+	}
+
+	if err == nil && phone.IsDeactivated() {
+		result.Add("from", "active", fmt.Sprintf("the phone with 'from' number [%s] is deactivated. reactivate it to start sending messages", request.From))
+	}
+
+	for _, to := range request.To {
+		result = validator.validateSuppression(ctx, result, userID, request.From, to, entities.MessageCategory(request.Category))
+		result = validator.validateContactPreference(ctx, result, userID, to, entities.MessageCategory(request.Category))
+		result = validator.validateCompliance(ctx, result, services.ComplianceCheckParams{
+			Contact:   to,
+			Content:   request.Content,
+			Encrypted: request.Encrypted,
+			Category:  entities.MessageCategory(request.Category),
+		})
 	}
 
 	return result
 }
 
 // ValidateMessageOutstanding validates the requests.MessageOutstanding request
-func (validator MessageHandlerValidator) ValidateMessageOutstanding(_ context.Context, request requests.MessageOutstanding) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageOutstanding(_ context.Context, request requests.MessageOutstanding) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -175,11 +444,11 @@ func (validator MessageHandlerValidator) ValidateMessageOutstanding(_ context.Co
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
 
 // ValidateMessageIndex validates the requests.MessageIndex request
-func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context, request requests.MessageIndex) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context, request requests.MessageIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -205,13 +474,70 @@ func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context,
 				"required",
 				phoneNumberRule,
 			},
+			"is_starred": []string{
+				"in:true,false",
+			},
+			"needs_follow_up": []string{
+				"in:true,false",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	validator.validateCursor(&result, request.Cursor)
+	return result
+}
+
+// ValidateContactTimeline validates the requests.ContactTimeline request
+func (validator MessageHandlerValidator) ValidateContactTimeline(_ context.Context, request requests.ContactTimeline) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:20",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"contact": []string{
+				"required",
+				"min:1",
+				"max:50",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	validator.validateCursor(&result, request.Cursor)
+	return result
+}
+
+// ValidateMessageUpdate validates the requests.MessageUpdate request
+func (validator MessageHandlerValidator) ValidateMessageUpdate(_ context.Context, request requests.MessageUpdate) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageID": []string{
+				"required",
+				"uuid",
+			},
 		},
 	})
-	return v.ValidateStruct()
+
+	result := validator.validate(v)
+	if request.IsStarred == nil && request.NeedsFollowUp == nil {
+		result.Add("is_starred", "required", "The is_starred field is required when the needs_follow_up field is not set")
+	}
+	return result
 }
 
 // ValidateMessageSearch validates the requests.MessageSearch request
-func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Context, request requests.MessageSearch) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Context, request requests.MessageSearch) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -232,6 +558,7 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 					entities.MessageStatusDelivered,
 					entities.MessageStatusFailed,
 					entities.MessageStatusExpired,
+					entities.MessageStatusCancelled,
 					entities.MessageStatusReceived,
 				}, ","),
 			},
@@ -264,20 +591,20 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 		},
 	})
 
-	errors := v.ValidateStruct()
+	errors := validator.validate(v)
 	if len(errors) > 0 {
 		return errors
 	}
 
 	if !validator.tokenValidator.ValidateToken(ctx, request.IPAddress, request.Token) {
-		errors.Add("token", "The captcha token from turnstile is invalid")
+		errors.Add("token", "captcha", "The captcha token from turnstile is invalid")
 	}
 
 	return errors
 }
 
 // ValidateMessageEvent validates the requests.MessageEvent request
-func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context, request requests.MessageEvent) url.Values {
+func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context, request requests.MessageEvent) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -287,19 +614,55 @@ func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context,
 					string(entities.MessageEventNameSent),
 					string(entities.MessageEventNameFailed),
 					string(entities.MessageEventNameDelivered),
+					string(entities.MessageEventNameRead),
+				}, ","),
+			},
+			"messageID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateDeliveryReport validates the requests.MessageDeliveryReport request
+func (validator MessageHandlerValidator) ValidateDeliveryReport(_ context.Context, request requests.MessageDeliveryReport) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"status": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					string(entities.MessageDeliveryStatusDelivered),
+					string(entities.MessageDeliveryStatusFailed),
+					string(entities.MessageDeliveryStatusPending),
 				}, ","),
 			},
+			"error_code": []string{
+				"max:100",
+			},
 			"messageID": []string{
 				"required",
 				"uuid",
 			},
 		},
 	})
-	return v.ValidateStruct()
+
+	result := validator.validate(v)
+	if request.StatusCode != nil && *request.StatusCode > 255 {
+		result.AddWithParam("status_code", "max", "255", "the status_code must be a TP-Status code between 0 and 255")
+	}
+
+	if request.PDU != nil && (len(*request.PDU) > 1024 || !deliveryReportPDURegex.MatchString(*request.PDU)) {
+		result.Add("pdu", "regex", "the pdu must be an SMS-STATUS-REPORT encoded in hexadecimal with at most 1024 characters")
+	}
+
+	return result
 }
 
 // ValidateCallMissed validates the requests.MessageCallMissed request
-func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, request requests.MessageCallMissed) url.Values {
+func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, request requests.MessageCallMissed) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -320,5 +683,5 @@ func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, r
 		},
 	})
 
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
@@ -3,13 +3,25 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
 	"github.com/thedevsaddam/govalidator"
 )
 
+const (
+	messageThreadBulkDeleteMaxIDs  = 1000
+	messageThreadBulkDeleteMaxDays = 3650
+	messageThreadMaxLabels         = 20
+	messageThreadLabelRegexMessage = "The label must contain 1 to 32 lowercase letters, numbers, dashes or underscores e.g. support"
+)
+
 // MessageThreadHandlerValidator validates models used in handlers.MessageThreadHandler
 type MessageThreadHandlerValidator struct {
 	validator
@@ -29,7 +41,7 @@ func NewMessageThreadHandlerValidator(
 }
 
 // ValidateMessageThreadIndex validates the requests.MessageThreadIndex request
-func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ context.Context, request requests.MessageThreadIndex) url.Values {
+func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ context.Context, request requests.MessageThreadIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -57,11 +69,168 @@ func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ con
 			},
 		},
 	})
-	return v.ValidateStruct()
+
+	result := validator.validate(v)
+	if request.Label != "" && !entities.MessageThreadLabelRegex.MatchString(request.Label) {
+		result.AddWithParam("label", "regex", entities.MessageThreadLabelRegex.String(), messageThreadLabelRegexMessage)
+	}
+	if request.Topic != "" && !slices.Contains(entities.MessageThreadTopics(), entities.MessageThreadTopic(request.Topic)) {
+		result.Add("topic", "in", fmt.Sprintf("The topic field must be one of %v", entities.MessageThreadTopics()))
+	}
+	if request.Language != "" && !languageRegex.MatchString(request.Language) {
+		result.Add("language", "language", fmt.Sprintf("the language [%s] must be a language code e.g. fr", request.Language))
+	}
+	validator.validateCursor(&result, request.Cursor)
+	return result
+}
+
+// ValidateTopicIndex validates the requests.MessageThreadTopicIndex request
+func (validator *MessageThreadHandlerValidator) ValidateTopicIndex(_ context.Context, request requests.MessageThreadTopicIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateBulkDelete validates requests.MessageThreadBulkDelete
+func (validator *MessageThreadHandlerValidator) ValidateBulkDelete(_ context.Context, request requests.MessageThreadBulkDelete) responses.ValidationErrors {
+	result := responses.ValidationErrors{}
+	if len(request.IDs) == 0 && request.OlderThanDays == 0 {
+		result.AddWithParam("ids", "required_with", "older_than_days", "The ids field is required when the older_than_days field is not set")
+		return result
+	}
+
+	if len(request.IDs) > 0 && request.OlderThanDays > 0 {
+		result.AddWithParam("ids", "prohibited_with", "older_than_days", "The ids and older_than_days fields cannot be set at the same time")
+	}
+
+	if len(request.IDs) > messageThreadBulkDeleteMaxIDs {
+		result.AddWithParam("ids", "max", strconv.Itoa(messageThreadBulkDeleteMaxIDs), fmt.Sprintf("The ids field must contain at most %d message thread IDs", messageThreadBulkDeleteMaxIDs))
+	}
+
+	for index, id := range request.IDs {
+		if _, err := uuid.Parse(id); err != nil {
+			result.Add(fmt.Sprintf("ids[%d]", index), "uuid", fmt.Sprintf("The ids[%d] field must contain a valid UUID", index))
+		}
+	}
+
+	if request.OlderThanDays > messageThreadBulkDeleteMaxDays {
+		result.AddWithParam("older_than_days", "max", strconv.Itoa(messageThreadBulkDeleteMaxDays), fmt.Sprintf("The older_than_days field must be at most %d", messageThreadBulkDeleteMaxDays))
+	}
+
+	return result
+}
+
+// ValidateLabelsStore validates requests.MessageThreadLabelsStore
+func (validator *MessageThreadHandlerValidator) ValidateLabelsStore(_ context.Context, request requests.MessageThreadLabelsStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if len(request.Labels) == 0 {
+		result.Add("labels", "required", "The labels field is required")
+	}
+
+	if len(request.Labels) > messageThreadMaxLabels {
+		result.AddWithParam("labels", "max", strconv.Itoa(messageThreadMaxLabels), fmt.Sprintf("The labels field must contain at most %d labels", messageThreadMaxLabels))
+	}
+
+	for index, label := range request.Labels {
+		if !entities.MessageThreadLabelRegex.MatchString(label) {
+			result.AddWithParam(fmt.Sprintf("labels[%d]", index), "regex", entities.MessageThreadLabelRegex.String(), messageThreadLabelRegexMessage)
+		}
+	}
+
+	return result
+}
+
+// ValidateLabelDestroy validates requests.MessageThreadLabelDestroy
+func (validator *MessageThreadHandlerValidator) ValidateLabelDestroy(_ context.Context, request requests.MessageThreadLabelDestroy) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+			"label": []string{
+				"required",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if request.Label != "" && !entities.MessageThreadLabelRegex.MatchString(request.Label) {
+		result.AddWithParam("label", "regex", entities.MessageThreadLabelRegex.String(), messageThreadLabelRegexMessage)
+	}
+	return result
+}
+
+// ValidateWebhookUpdate validates requests.MessageThreadWebhookUpdate
+func (validator *MessageThreadHandlerValidator) ValidateWebhookUpdate(_ context.Context, request requests.MessageThreadWebhookUpdate) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+			"webhook_id": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateMessageThreadSearch validates the requests.MessageThreadSearch request
+func (validator *MessageThreadHandlerValidator) ValidateMessageThreadSearch(_ context.Context, request requests.MessageThreadSearch) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"q": []string{
+				"required",
+				"min:2",
+				"max:100",
+			},
+			"owners": []string{
+				multipleContactPhoneNumberRule,
+			},
+			"is_archived": []string{
+				"in:true,false",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+		},
+	})
+	return validator.validate(v)
 }
 
 // ValidateUpdate validates requests.UserUpdate
-func (validator *MessageThreadHandlerValidator) ValidateUpdate(_ context.Context, request requests.MessageThreadUpdate) url.Values {
+func (validator *MessageThreadHandlerValidator) ValidateUpdate(_ context.Context, request requests.MessageThreadUpdate) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -72,5 +241,67 @@ func (validator *MessageThreadHandlerValidator) ValidateUpdate(_ context.Context
 		},
 	})
 
-	return v.ValidateStruct()
+	return validator.validate(v)
+}
+
+// ValidateContext validates requests.MessageThreadContext
+func (validator *MessageThreadHandlerValidator) ValidateContext(_ context.Context, request requests.MessageThreadContext) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateExport validates requests.MessageThreadExport
+func (validator *MessageThreadHandlerValidator) ValidateExport(_ context.Context, request requests.MessageThreadExport) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+			"format": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					string(entities.MessageThreadExportFormatCSV),
+					string(entities.MessageThreadExportFormatJSON),
+				}, ","),
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateShare validates requests.MessageThreadShare
+func (validator *MessageThreadHandlerValidator) ValidateShare(_ context.Context, request requests.MessageThreadShare) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageThreadID": []string{
+				"required",
+				"uuid",
+			},
+			"expires_in": []string{
+				"required",
+				"numeric_between:60,604800",
+			},
+		},
+	})
+
+	return validator.validate(v)
 }
@@ -0,0 +1,122 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// NotificationChannelHandlerValidator validates models used in handlers.NotificationChannelHandler
+type NotificationChannelHandlerValidator struct {
+	validator
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	service     *services.NotificationChannelService
+	egressGuard *services.EgressGuard
+}
+
+// NewNotificationChannelHandlerValidator creates a new handlers.NotificationChannelHandler validator
+func NewNotificationChannelHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.NotificationChannelService,
+	egressGuard *services.EgressGuard,
+) (v *NotificationChannelHandlerValidator) {
+	return &NotificationChannelHandlerValidator{
+		logger:      logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:      tracer,
+		service:     service,
+		egressGuard: egressGuard,
+	}
+}
+
+// ValidateIndex validates the requests.NotificationChannelIndex request
+func (validator *NotificationChannelHandlerValidator) ValidateIndex(_ context.Context, request requests.NotificationChannelIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates the requests.NotificationChannelStore request
+func (validator *NotificationChannelHandlerValidator) ValidateStore(ctx context.Context, request requests.NotificationChannelStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return validator.validateWebhookURL(ctx, validator.validate(v), request.WebhookURL)
+}
+
+// ValidateUpdate validates the requests.NotificationChannelUpdate request
+func (validator *NotificationChannelHandlerValidator) ValidateUpdate(ctx context.Context, request requests.NotificationChannelUpdate) responses.ValidationErrors {
+	rules := validator.storeRules()
+	rules["channelID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return validator.validateWebhookURL(ctx, validator.validate(v), request.WebhookURL)
+}
+
+func (validator *NotificationChannelHandlerValidator) validateWebhookURL(ctx context.Context, result responses.ValidationErrors, webhookURL string) responses.ValidationErrors {
+	if len(result) > 0 {
+		return result
+	}
+
+	if err := validator.egressGuard.ValidateURL(ctx, webhookURL); err != nil {
+		validator.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("the notification channel url [%s] is not allowed", webhookURL)))
+		result.Add("webhook_url", "public", "The webhook_url must be an http or https address on the public internet")
+	}
+	return result
+}
+
+func (validator *NotificationChannelHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"provider": []string{
+			"required",
+			"in:" + strings.Join(validator.service.Providers(), ","),
+		},
+		"name": []string{
+			"required",
+			"min:1",
+			"max:50",
+		},
+		"webhook_url": []string{
+			"required",
+			"url",
+			"max:1000",
+		},
+		"events": []string{
+			"required",
+			multipleInRule + ":" + strings.Join([]string{events.EventTypePhoneHeartbeatOffline, events.EventTypeMessageSendFailed, events.EventTypePhoneBalanceLow}, ","),
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// PhoneBalanceHandlerValidator validates models used in handlers.PhoneBalanceHandler
+type PhoneBalanceHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewPhoneBalanceHandlerValidator creates a new handlers.PhoneBalanceHandler validator
+func NewPhoneBalanceHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *PhoneBalanceHandlerValidator) {
+	return &PhoneBalanceHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the requests.PhoneBalanceStore request
+func (validator *PhoneBalanceHandlerValidator) ValidateStore(_ context.Context, request requests.PhoneBalanceStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"content": []string{
+				"max:1024",
+			},
+			"currency": []string{
+				"max:3",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if len(result) > 0 {
+		return result
+	}
+
+	if request.Amount == nil && request.Content == "" {
+		result.Add("amount", "required_without", "the amount is required when the content is empty")
+		return result
+	}
+
+	if request.Amount == nil && entities.ParsePhoneBalance(request.Content) == nil {
+		result.Add("content", "balance", fmt.Sprintf("cannot find the balance in the content [%s], set the amount instead", request.Content))
+	}
+
+	if request.Amount != nil && *request.Amount < 0 {
+		result.AddWithParam("amount", "min", "0", "the amount cannot be negative")
+	}
+
+	return result
+}
+
+// ValidateIndex validates the requests.PhoneBalanceIndex request
+func (validator *PhoneBalanceHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneBalanceIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
@@ -0,0 +1,90 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// PhoneCrashHandlerValidator validates models used in handlers.PhoneCrashHandler
+type PhoneCrashHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewPhoneCrashHandlerValidator creates a new handlers.PhoneCrashHandler validator
+func NewPhoneCrashHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *PhoneCrashHandlerValidator) {
+	return &PhoneCrashHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the requests.PhoneCrashStore request
+func (validator *PhoneCrashHandlerValidator) ValidateStore(_ context.Context, request requests.PhoneCrashStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"type": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					string(entities.PhoneCrashTypeCrash),
+					string(entities.PhoneCrashTypeANR),
+				}, ","),
+			},
+			"message": []string{
+				"required",
+				"max:1024",
+			},
+			"stack_trace": []string{
+				"max:65536",
+			},
+			"app_version": []string{
+				"max:50",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateIndex validates the requests.PhoneCrashIndex request
+func (validator *PhoneCrashHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneCrashIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
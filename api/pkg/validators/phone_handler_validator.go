@@ -3,36 +3,46 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"strings"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
 	"github.com/thedevsaddam/govalidator"
 )
 
 // PhoneHandlerValidator validates models used in handlers.PhoneHandler
 type PhoneHandlerValidator struct {
 	validator
-	logger telemetry.Logger
-	tracer telemetry.Tracer
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	service     *services.PhoneService
+	egressGuard *services.EgressGuard
 }
 
 // NewPhoneHandlerValidator creates a new handlers.PhoneHandler validator
 func NewPhoneHandlerValidator(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
+	service *services.PhoneService,
+	egressGuard *services.EgressGuard,
 ) (v *PhoneHandlerValidator) {
 	return &PhoneHandlerValidator{
-		logger: logger.WithService(fmt.Sprintf("%T", v)),
-		tracer: tracer,
+		logger:      logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:      tracer,
+		service:     service,
+		egressGuard: egressGuard,
 	}
 }
 
 // ValidateIndex validates the requests.HeartbeatIndex request
-func (validator *PhoneHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneIndex) url.Values {
+func (validator *PhoneHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -52,11 +62,37 @@ func (validator *PhoneHandlerValidator) ValidateIndex(_ context.Context, request
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
+}
+
+// ValidateRegister validates requests.PhoneRegister
+func (validator *PhoneHandlerValidator) ValidateRegister(_ context.Context, request requests.PhoneRegister) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phone_number": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"fcm_token": []string{
+				"required",
+				"min:1",
+				"max:1000",
+			},
+			"app_version": []string{
+				"max:50",
+			},
+			"sim": []string{
+				"required",
+				"in:" + strings.Join([]string{entities.SIM1.String(), entities.SIM2.String()}, ","),
+			},
+		},
+	})
+	return validator.validate(v)
 }
 
 // ValidateUpsert validates requests.PhoneUpsert
-func (validator *PhoneHandlerValidator) ValidateUpsert(_ context.Context, request requests.PhoneUpsert) url.Values {
+func (validator *PhoneHandlerValidator) ValidateUpsert(ctx context.Context, request requests.PhoneUpsert, userID entities.UserID) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -84,23 +120,239 @@ func (validator *PhoneHandlerValidator) ValidateUpsert(_ context.Context, reques
 				"min:60",
 				"max:3600",
 			},
+			"heartbeat_timeout_seconds": []string{
+				"min:1800",
+				"max:86400",
+			},
+			"heartbeat_interval_seconds": []string{
+				"min:900",
+				"max:3600",
+			},
+			"duplicate_strategy": []string{
+				"in:" + strings.Join([]string{
+					entities.MessageDuplicateStrategyExact.String(),
+					entities.MessageDuplicateStrategyNormalized.String(),
+				}, ","),
+			},
+			"push_provider": []string{
+				"in:" + strings.Join([]string{
+					entities.PhonePushProviderFCM.String(),
+					entities.PhonePushProviderUnifiedPush.String(),
+				}, ","),
+			},
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validate(v)
 	if len(result) > 0 {
 		return result
 	}
 
 	if request.MaxSendAttempts > 0 && request.MessageExpirationSeconds == 0 {
-		result.Add("message_expiration_seconds", "message_expiration_seconds cannot be 0 when max_send_attempts is greater than 0")
+		result.AddWithParam("message_expiration_seconds", "required_with", "max_send_attempts", "message_expiration_seconds cannot be 0 when max_send_attempts is greater than 0")
+	}
+
+	if request.HeartbeatIntervalSeconds > 0 || request.HeartbeatTimeoutSeconds > 0 {
+		interval, timeout, err := validator.heartbeatSettings(ctx, request, userID)
+		if err != nil {
+			validator.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot load phone [%s] for user [%s]", request.PhoneNumber, userID)))
+			result.Add("heartbeat_interval_seconds", "unavailable", "cannot validate the heartbeat settings of the phone, please try again later")
+		} else if interval >= timeout {
+			result.AddWithParam("heartbeat_interval_seconds", "lt", "heartbeat_timeout_seconds", fmt.Sprintf("heartbeat_interval_seconds [%d] must be less than heartbeat_timeout_seconds [%d]", interval, timeout))
+		}
+	}
+
+	if request.LowBalanceThreshold != nil && *request.LowBalanceThreshold < 0 {
+		result.AddWithParam("low_balance_threshold", "min", "0", "low_balance_threshold cannot be negative")
+	}
+
+	if request.MessagesPerHour != nil && *request.MessagesPerHour > 10000 {
+		result.AddWithParam("messages_per_hour", "max", "10000", "messages_per_hour cannot be more than 10000")
+	}
+
+	if request.DuplicateWindowSeconds != nil && *request.DuplicateWindowSeconds > 86400 {
+		result.AddWithParam("duplicate_window_seconds", "max", "86400", "duplicate_window_seconds cannot be more than 86400")
+	}
+
+	if request.BackupPhoneNumber != nil && *request.BackupPhoneNumber != "" {
+		if _, err := phonenumbers.Parse(*request.BackupPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
+			result.Add("backup_phone_number", phoneNumberRule, "backup_phone_number must be a valid phone number")
+		} else if *request.BackupPhoneNumber == request.PhoneNumber {
+			result.AddWithParam("backup_phone_number", "different", "phone_number", "backup_phone_number must be different from phone_number")
+		}
+	}
+
+	if request.UnifiedPushEndpoint != nil && *request.UnifiedPushEndpoint != "" {
+		if len(*request.UnifiedPushEndpoint) > 1000 {
+			result.Add("unified_push_endpoint", "url", "unified_push_endpoint must be a valid URL")
+		} else if err := validator.egressGuard.ValidateURL(ctx, *request.UnifiedPushEndpoint); err != nil {
+			validator.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("the unified push endpoint [%s] of user [%s] is not allowed", *request.UnifiedPushEndpoint, userID)))
+			result.Add("unified_push_endpoint", "public", "unified_push_endpoint must be an http or https address on the public internet")
+		}
+	}
+
+	if request.PushProvider == entities.PhonePushProviderUnifiedPush.String() && (request.UnifiedPushEndpoint == nil || *request.UnifiedPushEndpoint == "") {
+		result.AddWithParam("unified_push_endpoint", "required_with", "push_provider", "unified_push_endpoint is required when push_provider is unified_push")
 	}
 
 	return result
 }
 
+// ValidateQueue validates requests.PhoneQueue
+func (validator *PhoneHandlerValidator) ValidateQueue(_ context.Context, request requests.PhoneQueue) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"numeric_between:1,100",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateQueueStats validates requests.PhoneQueueStats
+func (validator *PhoneHandlerValidator) ValidateQueueStats(_ context.Context, request requests.PhoneQueueStats) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidatePoll validates requests.PhonePoll
+func (validator *PhoneHandlerValidator) ValidatePoll(_ context.Context, request requests.PhonePoll) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"timeout_seconds": []string{
+				"required",
+				"numeric",
+				"numeric_between:0,60",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateShow validates requests.PhoneShow
+func (validator *PhoneHandlerValidator) ValidateShow(_ context.Context, request requests.PhoneShow) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateResync validates requests.PhoneResync
+func (validator *PhoneHandlerValidator) ValidateResync(_ context.Context, request requests.PhoneResync) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateDeactivate validates requests.PhoneDeactivate
+func (validator *PhoneHandlerValidator) ValidateDeactivate(_ context.Context, request requests.PhoneDeactivate) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateReactivate validates requests.PhoneReactivate
+func (validator *PhoneHandlerValidator) ValidateReactivate(_ context.Context, request requests.PhoneReactivate) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// ValidateSettings validates requests.PhoneSettings
+func (validator *PhoneHandlerValidator) ValidateSettings(_ context.Context, request requests.PhoneSettings) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	return validator.validate(v)
+}
+
+// heartbeatSettings returns the heartbeat interval and timeout of the phone after the request is applied
+func (validator *PhoneHandlerValidator) heartbeatSettings(ctx context.Context, request requests.PhoneUpsert, userID entities.UserID) (uint, uint, error) {
+	phone := new(entities.Phone)
+	if request.HeartbeatIntervalSeconds == 0 || request.HeartbeatTimeoutSeconds == 0 {
+		stored, err := validator.service.Load(ctx, userID, request.PhoneNumber)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			return 0, 0, stacktrace.Propagate(err, fmt.Sprintf("cannot load phone [%s]", request.PhoneNumber))
+		}
+		if err == nil {
+			phone = stored
+		}
+	}
+
+	if request.HeartbeatIntervalSeconds > 0 {
+		phone.HeartbeatIntervalSeconds = request.HeartbeatIntervalSeconds
+	}
+	if request.HeartbeatTimeoutSeconds > 0 {
+		phone.HeartbeatTimeoutSeconds = request.HeartbeatTimeoutSeconds
+	}
+
+	return phone.HeartbeatIntervalSecondsSanitized(), phone.HeartbeatTimeoutSecondsSanitized(), nil
+}
+
 // ValidateDelete ValidateUpsert validates requests.PhoneDelete
-func (validator *PhoneHandlerValidator) ValidateDelete(_ context.Context, request requests.PhoneDelete) url.Values {
+func (validator *PhoneHandlerValidator) ValidateDelete(_ context.Context, request requests.PhoneDelete) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -111,5 +363,5 @@ func (validator *PhoneHandlerValidator) ValidateDelete(_ context.Context, reques
 		},
 	})
 
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
@@ -0,0 +1,96 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// phoneLogContentTypes are the media types of the log files which the app on a phone can upload
+var phoneLogContentTypes = map[string]bool{
+	"text/plain":         true,
+	"application/gzip":   true,
+	"application/x-gzip": true,
+	"application/zip":    true,
+}
+
+// PhoneLogHandlerValidator validates models used in handlers.PhoneLogHandler
+type PhoneLogHandlerValidator struct {
+	validator
+	logger              telemetry.Logger
+	tracer              telemetry.Tracer
+	attachmentValidator *AttachmentHandlerValidator
+}
+
+// NewPhoneLogHandlerValidator creates a new handlers.PhoneLogHandler validator
+func NewPhoneLogHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	attachmentValidator *AttachmentHandlerValidator,
+) (v *PhoneLogHandlerValidator) {
+	return &PhoneLogHandlerValidator{
+		logger:              logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:              tracer,
+		attachmentValidator: attachmentValidator,
+	}
+}
+
+// ContentType returns the media type of an uploaded log file
+func (validator *PhoneLogHandlerValidator) ContentType(header *multipart.FileHeader) string {
+	return validator.attachmentValidator.ContentType(header)
+}
+
+// ValidateIndex validates the requests.PhoneLogIndex request
+func (validator *PhoneLogHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneLogIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates a log file which is uploaded by the app on a phone
+func (validator *PhoneLogHandlerValidator) ValidateStore(ctx context.Context, phoneID string, header *multipart.FileHeader) responses.ValidationErrors {
+	result := validator.ValidateUUID(ctx, phoneID, "phoneID")
+
+	if header.Size == 0 {
+		result.Add("file", "required", "The uploaded file is empty.")
+	}
+
+	if header.Size > attachmentMaxSize {
+		result.AddWithParam("file", "size", strconv.Itoa(attachmentMaxSize), fmt.Sprintf("The uploaded file must be smaller than %d MB.", attachmentMaxSize/1024/1024))
+	}
+
+	if len(header.Filename) > 255 {
+		result.AddWithParam("file", "filename_max", "255", "The name of the uploaded file must be maximum 255 characters.")
+	}
+
+	contentType := validator.ContentType(header)
+	if !phoneLogContentTypes[contentType] {
+		result.AddWithParam("file", "mime", contentType, fmt.Sprintf("The content type [%s] is not supported, upload a text, gzip or zip file.", contentType))
+	}
+
+	return result
+}
@@ -0,0 +1,90 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+const phoneMaintenanceWindowMaxDuration = 30 * 24 * time.Hour
+
+// PhoneMaintenanceWindowHandlerValidator validates models used in handlers.PhoneMaintenanceWindowHandler
+type PhoneMaintenanceWindowHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewPhoneMaintenanceWindowHandlerValidator creates a new handlers.PhoneMaintenanceWindowHandler validator
+func NewPhoneMaintenanceWindowHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *PhoneMaintenanceWindowHandlerValidator) {
+	return &PhoneMaintenanceWindowHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the requests.PhoneMaintenanceWindowStore request
+func (validator *PhoneMaintenanceWindowHandlerValidator) ValidateStore(_ context.Context, request requests.PhoneMaintenanceWindowStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"reason": []string{
+				"max:255",
+			},
+		},
+	})
+
+	result := validator.validate(v)
+	if request.StartsAt.IsZero() || request.EndsAt.IsZero() {
+		result.Add("starts_at", "required", "the maintenance window must have a [starts_at] and an [ends_at] timestamp")
+	} else if !request.StartsAt.Before(request.EndsAt) {
+		result.AddWithParam("starts_at", "before", "ends_at", "the [starts_at] timestamp must be before the [ends_at] timestamp")
+	} else if request.EndsAt.Sub(request.StartsAt) > phoneMaintenanceWindowMaxDuration {
+		result.AddWithParam("ends_at", "max_days", strconv.Itoa(int(phoneMaintenanceWindowMaxDuration.Hours()/24)), fmt.Sprintf("the maintenance window cannot be longer than [%d] days", int(phoneMaintenanceWindowMaxDuration.Hours()/24)))
+	} else if request.EndsAt.Before(time.Now().UTC()) {
+		result.Add("ends_at", "after", "the [ends_at] timestamp must be in the future")
+	}
+
+	return result
+}
+
+// ValidateIndex validates the requests.PhoneMaintenanceWindowIndex request
+func (validator *PhoneMaintenanceWindowHandlerValidator) ValidateIndex(_ context.Context, request requests.PhoneMaintenanceWindowIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phoneID": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
@@ -0,0 +1,139 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// phonePoolMaxPhones is the maximum number of phones in a phone pool
+const phonePoolMaxPhones = 20
+
+// PhonePoolHandlerValidator validates models used in handlers.PhonePoolHandler
+type PhonePoolHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewPhonePoolHandlerValidator creates a new handlers.PhonePoolHandler validator
+func NewPhonePoolHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *PhonePoolHandlerValidator) {
+	return &PhonePoolHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.PhonePoolIndex request
+func (validator *PhonePoolHandlerValidator) ValidateIndex(_ context.Context, request requests.PhonePoolIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates the requests.PhonePoolStore request
+func (validator *PhonePoolHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.PhonePoolStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return validator.validatePhoneNumbers(ctx, validator.validate(v), userID, request)
+}
+
+// ValidateUpdate validates the requests.PhonePoolUpdate request
+func (validator *PhonePoolHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.PhonePoolUpdate) responses.ValidationErrors {
+	rules := validator.storeRules()
+	rules["poolID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return validator.validatePhoneNumbers(ctx, validator.validate(v), userID, request.PhonePoolStore)
+}
+
+func (validator *PhonePoolHandlerValidator) validatePhoneNumbers(ctx context.Context, result responses.ValidationErrors, userID entities.UserID, request requests.PhonePoolStore) responses.ValidationErrors {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	if len(request.PhoneNumbers) > phonePoolMaxPhones {
+		result.AddWithParam("phone_numbers", "max", fmt.Sprint(phonePoolMaxPhones), fmt.Sprintf("a phone pool cannot have more than [%d] phones", phonePoolMaxPhones))
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	for index, phoneNumber := range request.PhoneNumbers {
+		_, err := validator.phoneService.Load(ctx, userID, phoneNumber)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			result.Add(fmt.Sprintf("phone_numbers[%d]", index), "exists", fmt.Sprintf("no phone found with phone number [%s]. install the android app on your phone to add it to the phone pool", phoneNumber))
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, phoneNumber))))
+			result.Add(fmt.Sprintf("phone_numbers[%d]", index), "unavailable", fmt.Sprintf("could not validate phone number [%s], please try again later", phoneNumber))
+		}
+	}
+
+	return result
+}
+
+func (validator *PhonePoolHandlerValidator) storeRules() govalidator.MapData {
+	strategies := make([]string, 0, len(entities.PhonePoolStrategies))
+	for _, strategy := range entities.PhonePoolStrategies {
+		strategies = append(strategies, strategy.String())
+	}
+
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:50",
+		},
+		"strategy": []string{
+			"required",
+			"in:" + strings.Join(strategies, ","),
+		},
+		"phone_numbers": []string{
+			"required",
+			multipleContactPhoneNumberRule,
+		},
+	}
+}
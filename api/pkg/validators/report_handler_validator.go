@@ -0,0 +1,70 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// ReportHandlerValidator validates models used in handlers.ReportHandler
+type ReportHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewReportHandlerValidator creates a new handlers.ReportHandler validator
+func NewReportHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *ReportHandlerValidator) {
+	return &ReportHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.ReportIndex request
+func (validator *ReportHandlerValidator) ValidateIndex(_ context.Context, request requests.ReportIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates the requests.ReportStore request
+func (validator *ReportHandlerValidator) ValidateStore(_ context.Context, request requests.ReportStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"type": []string{
+				"required",
+				"in:" + strings.Join([]string{entities.ReportTypeDailyUsage.String(), entities.ReportTypeWeeklyFailures.String()}, ","),
+			},
+			"delivery": []string{
+				"required",
+				"in:" + strings.Join([]string{entities.ReportDeliveryEmail.String(), entities.ReportDeliveryWebhook.String()}, ","),
+			},
+		},
+	})
+	return validator.validate(v)
+}
@@ -0,0 +1,141 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// routingRulePrefixRegex matches the destination prefix of a routing rule which is the start of an E.164 phone number
+var routingRulePrefixRegex = regexp.MustCompile(`^\+[0-9]{1,14}$`)
+
+// RoutingRuleHandlerValidator validates models used in handlers.RoutingRuleHandler
+type RoutingRuleHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewRoutingRuleHandlerValidator creates a new handlers.RoutingRuleHandler validator
+func NewRoutingRuleHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *RoutingRuleHandlerValidator) {
+	return &RoutingRuleHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.RoutingRuleIndex request
+func (validator *RoutingRuleHandlerValidator) ValidateIndex(_ context.Context, request requests.RoutingRuleIndex) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return validator.validate(v)
+}
+
+// ValidateStore validates the requests.RoutingRuleStore request
+func (validator *RoutingRuleHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.RoutingRuleStore) responses.ValidationErrors {
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: validator.storeRules(),
+	})
+	return validator.validateRule(ctx, validator.validate(v), userID, request)
+}
+
+// ValidateUpdate validates the requests.RoutingRuleUpdate request
+func (validator *RoutingRuleHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.RoutingRuleUpdate) responses.ValidationErrors {
+	rules := validator.storeRules()
+	rules["ruleID"] = []string{
+		"required",
+		"uuid",
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return validator.validateRule(ctx, validator.validate(v), userID, request.RoutingRuleStore)
+}
+
+func (validator *RoutingRuleHandlerValidator) validateRule(ctx context.Context, result responses.ValidationErrors, userID entities.UserID, request requests.RoutingRuleStore) responses.ValidationErrors {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	if request.Prefix != "" && !routingRulePrefixRegex.MatchString(request.Prefix) {
+		result.AddWithParam("prefix", "regex", routingRulePrefixRegex.String(), "The prefix must start with + followed by the digits of the country code and the number e.g. +1800")
+	}
+
+	if request.Country != "" && !phonenumbers.GetSupportedRegions()[request.Country] {
+		result.Add("country", "in", fmt.Sprintf("The country [%s] is not a supported ISO 3166-1 alpha-2 country code", request.Country))
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, request.PhoneNumber)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("phone_number", "exists", fmt.Sprintf("no phone found with 'phone_number' [%s]. install the android app on your phone to start sending messages", request.PhoneNumber))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("could not load phone for user [%s] and phone [%s]", userID, request.PhoneNumber))))
+		result.Add("phone_number", "unavailable", fmt.Sprintf("could not validate 'phone_number' [%s], please try again later", request.PhoneNumber))
+	}
+
+	return result
+}
+
+func (validator *RoutingRuleHandlerValidator) storeRules() govalidator.MapData {
+	return govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:50",
+		},
+		"position": []string{
+			"min:0",
+			"max:1000",
+		},
+		"tag": []string{
+			"alpha_dash",
+			"max:50",
+		},
+		"phone_number": []string{
+			"required",
+			phoneNumberRule,
+		},
+	}
+}
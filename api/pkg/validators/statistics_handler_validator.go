@@ -0,0 +1,78 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/nyaruka/phonenumbers"
+)
+
+const statisticsMaxRange = 366 * 24 * time.Hour
+
+var statisticsCategories = []string{
+	entities.MessageCategoryTransactional.String(),
+	entities.MessageCategoryMarketing.String(),
+	entities.MessageCategoryOTP.String(),
+}
+
+// StatisticsHandlerValidator validates models used in handlers.StatisticsHandler
+type StatisticsHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewStatisticsHandlerValidator creates a new StatisticsHandlerValidator
+func NewStatisticsHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *StatisticsHandlerValidator) {
+	return &StatisticsHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateTimeseries validates the requests.StatisticsTimeseries request
+func (validator *StatisticsHandlerValidator) ValidateTimeseries(_ context.Context, request requests.StatisticsTimeseries) responses.ValidationErrors {
+	result := responses.ValidationErrors{}
+
+	if request.Range.From.IsZero() || request.Range.To.IsZero() {
+		result.Add("range", "required", "the range must have a [from] and a [to] timestamp")
+	} else if !request.Range.From.Before(request.Range.To) {
+		result.AddWithParam("range.from", "before", "range.to", "the [from] timestamp of the range must be before the [to] timestamp")
+	} else if request.Range.To.Sub(request.Range.From) > statisticsMaxRange {
+		result.AddWithParam("range", "max_days", strconv.Itoa(int(statisticsMaxRange.Hours()/24)), fmt.Sprintf("the range cannot be longer than [%d] days", int(statisticsMaxRange.Hours()/24)))
+	}
+
+	if len(request.Targets) > 20 {
+		result.AddWithParam("targets", "max", "20", "you can query a maximum of 20 targets at once")
+	}
+
+	for index, target := range request.Targets {
+		if !slices.Contains(services.StatisticsTargets, target.Target) {
+			result.AddWithParam(fmt.Sprintf("targets[%d].target", index), "in", strings.Join(services.StatisticsTargets, ","), fmt.Sprintf("the target [%s] in index [%d] is not supported, use one of [%s]", target.Target, index, strings.Join(services.StatisticsTargets, ", ")))
+		}
+
+		if owner := target.Owner(); owner != "" {
+			if _, err := phonenumbers.Parse(owner, phonenumbers.UNKNOWN_REGION); err != nil {
+				result.Add(fmt.Sprintf("targets[%d].payload.owner", index), phoneNumberRule, fmt.Sprintf("the owner [%s] in index [%d] must be a valid E.164 phone number", owner, index))
+			}
+		}
+
+		if category := target.Category(); category != "" && !slices.Contains(statisticsCategories, category) {
+			result.AddWithParam(fmt.Sprintf("targets[%d].payload.category", index), "in", strings.Join(statisticsCategories, ","), fmt.Sprintf("the category [%s] in index [%d] is not supported, use one of [%s]", category, index, strings.Join(statisticsCategories, ", ")))
+		}
+	}
+
+	return result
+}
@@ -3,9 +3,9 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/thedevsaddam/govalidator"
 )
@@ -29,7 +29,7 @@ func NewUserHandlerValidator(
 }
 
 // ValidateUpdate validates requests.UserUpdate
-func (validator *UserHandlerValidator) ValidateUpdate(_ context.Context, request requests.UserUpdate) url.Values {
+func (validator *UserHandlerValidator) ValidateUpdate(_ context.Context, request requests.UserUpdate) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -39,5 +39,18 @@ func (validator *UserHandlerValidator) ValidateUpdate(_ context.Context, request
 		},
 	})
 
-	return v.ValidateStruct()
+	result := validator.validate(v)
+	if request.OptOutFooter != nil && len(*request.OptOutFooter) > 100 {
+		result.AddWithParam("opt_out_footer", "max", "100", "The opt_out_footer field must be maximum 100 char")
+	}
+	if request.PreferredLanguage != nil && *request.PreferredLanguage != "" && !languageRegex.MatchString(*request.PreferredLanguage) {
+		result.Add("preferred_language", "language", "The preferred_language field must be a language code e.g. en or pt-br")
+	}
+	if request.HeartbeatOfflineThreshold != nil && *request.HeartbeatOfflineThreshold > 10 {
+		result.AddWithParam("heartbeat_offline_threshold", "max", "10", "The heartbeat_offline_threshold field cannot be more than 10")
+	}
+	if request.HeartbeatAlertCooldownSeconds != nil && *request.HeartbeatAlertCooldownSeconds > 86400 {
+		result.AddWithParam("heartbeat_alert_cooldown_seconds", "max", "86400", "The heartbeat_alert_cooldown_seconds field cannot be more than 86400")
+	}
+	return result
 }
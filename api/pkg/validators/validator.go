@@ -3,11 +3,14 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 
 	"github.com/nyaruka/phonenumbers"
 	"github.com/thedevsaddam/govalidator"
@@ -15,6 +18,8 @@ import (
 
 type validator struct{}
 
+var languageRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,4})?$`)
+
 const (
 	phoneNumberRule                = "phoneNumber"
 	multiplePhoneNumberRule        = "multiplePhoneNumber"
@@ -22,6 +27,7 @@ const (
 	multipleContactPhoneNumberRule = "multipleContactPhoneNumber"
 	multipleInRule                 = "multipleIn"
 	webhookEventsRule              = "webhookEvents"
+	multipleLanguageRule           = "multipleLanguage"
 )
 
 func init() {
@@ -112,6 +118,21 @@ func init() {
 		return nil
 	})
 
+	govalidator.AddCustomRule(multipleLanguageRule, func(field string, rule string, message string, value interface{}) error {
+		languages, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("The %s field must be a string array", field)
+		}
+
+		for index, language := range languages {
+			if !languageRegex.MatchString(language) {
+				return fmt.Errorf("The %s field in index [%d] must be a language code e.g. en or pt-br", field, index)
+			}
+		}
+
+		return nil
+	})
+
 	govalidator.AddCustomRule(webhookEventsRule, func(field string, rule string, message string, value interface{}) error {
 		input, ok := value.([]string)
 		if !ok {
@@ -123,18 +144,25 @@ func init() {
 		}
 
 		validEvents := map[string]bool{
-			events.EventTypeMessagePhoneReceived:  true,
-			events.EventTypeMessagePhoneSent:      true,
-			events.EventTypeMessagePhoneDelivered: true,
-			events.EventTypeMessageSendFailed:     true,
-			events.EventTypeMessageSendExpired:    true,
-			events.EventTypePhoneHeartbeatOnline:  true,
-			events.EventTypePhoneHeartbeatOffline: true,
-			events.MessageCallMissed:              true,
+			events.EventTypeMessagePhoneReceived:       true,
+			events.EventTypeMessagePhoneSent:           true,
+			events.EventTypeMessagePhoneDelivered:      true,
+			events.EventTypeMessageDelivered:           true,
+			events.EventTypeMessageDeliveryFailed:      true,
+			events.EventTypeMessageSendFailed:          true,
+			events.EventTypeMessageSendFailedTransient: true,
+			events.EventTypeMessageSendExpired:         true,
+			events.EventTypeMessageSendCancelled:       true,
+			events.EventTypePhoneHeartbeatOnline:       true,
+			events.EventTypePhoneHeartbeatOffline:      true,
+			events.EventTypePhoneBalanceLow:            true,
+			events.EventTypePhoneSIMSwapped:            true,
+			events.MessageCallMissed:                   true,
+			events.EventTypeReportGenerated:            true,
 		}
 
 		for _, event := range input {
-			if _, ok := validEvents[event]; !ok {
+			if _, ok := validEvents[event]; !ok && !entities.CustomEventTypeRegex.MatchString(event) {
 				return fmt.Errorf("The %s field has an invalid event with name [%s]", field, event)
 			}
 		}
@@ -143,8 +171,57 @@ func init() {
 	})
 }
 
+// validate runs the rules of the govalidator.Validator and returns a responses.ValidationError for every rule which failed.
+// The fields are validated rule by rule so that each error can be attributed to the rule and parameter which caused it.
+func (validator *validator) validate(v *govalidator.Validator) responses.ValidationErrors {
+	result := responses.ValidationErrors{}
+
+	failed := v.ValidateStruct()
+	if len(failed) == 0 {
+		return result
+	}
+
+	fields := make([]string, 0, len(failed))
+	for field := range v.Opts.Rules {
+		if _, ok := failed[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, rule := range v.Opts.Rules[field] {
+			options := v.Opts
+			options.Rules = govalidator.MapData{field: []string{rule}}
+
+			name, param, _ := strings.Cut(rule, ":")
+			messages := govalidator.New(options).ValidateStruct()[field]
+			for _, message := range messages {
+				result.AddWithParam(field, name, param, message)
+			}
+
+			// the other rules of a required field which is empty will also fail with the same cause
+			if name == "required" && len(messages) > 0 {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// validateCursor adds an error to the result when the cursor is set but it was not returned by the API
+func (validator *validator) validateCursor(result *responses.ValidationErrors, cursor string) {
+	if cursor == "" {
+		return
+	}
+	if _, err := repositories.DecodeCursor(cursor); err != nil {
+		result.Add("cursor", "cursor", "The cursor field must be the next_cursor which was returned in the previous response")
+	}
+}
+
 // ValidateUUID that the payload is a UUID
-func (validator *validator) ValidateUUID(_ context.Context, ID string, name string) url.Values {
+func (validator *validator) ValidateUUID(_ context.Context, ID string, name string) responses.ValidationErrors {
 	request := map[string]string{
 		name: ID,
 	}
@@ -159,5 +236,5 @@ func (validator *validator) ValidateUUID(_ context.Context, ID string, name stri
 		},
 	})
 
-	return v.ValidateStruct()
+	return validator.validate(v)
 }
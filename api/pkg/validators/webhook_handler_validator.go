@@ -3,7 +3,7 @@ package validators
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
@@ -12,6 +12,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 
+	"github.com/NdoleStudio/httpsms/pkg/responses"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/thedevsaddam/govalidator"
 )
@@ -22,6 +23,8 @@ type WebhookHandlerValidator struct {
 	logger       telemetry.Logger
 	tracer       telemetry.Tracer
 	phoneService *services.PhoneService
+	egressGuard  *services.EgressGuard
+	retention    time.Duration
 }
 
 // NewWebhookHandlerValidator creates a new handlers.WebhookHandler validator
@@ -29,16 +32,20 @@ func NewWebhookHandlerValidator(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
+	egressGuard *services.EgressGuard,
+	retention time.Duration,
 ) (v *WebhookHandlerValidator) {
 	return &WebhookHandlerValidator{
 		logger:       logger.WithService(fmt.Sprintf("%T", v)),
 		tracer:       tracer,
 		phoneService: phoneService,
+		egressGuard:  egressGuard,
+		retention:    retention,
 	}
 }
 
 // ValidateIndex validates the requests.HeartbeatIndex request
-func (validator *WebhookHandlerValidator) ValidateIndex(_ context.Context, request requests.WebhookIndex) url.Values {
+func (validator *WebhookHandlerValidator) ValidateIndex(_ context.Context, request requests.WebhookIndex) responses.ValidationErrors {
 	v := govalidator.New(govalidator.Options{
 		Data: &request,
 		Rules: govalidator.MapData{
@@ -58,11 +65,54 @@ func (validator *WebhookHandlerValidator) ValidateIndex(_ context.Context, reque
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validate(v)
+}
+
+// ValidateReplay validates the requests.WebhookReplay request
+func (validator *WebhookHandlerValidator) ValidateReplay(_ context.Context, request requests.WebhookReplay) responses.ValidationErrors {
+	rules := govalidator.MapData{
+		"webhookID": []string{
+			"required",
+			"uuid",
+		},
+		"from": []string{
+			"required",
+		},
+		"to": []string{
+			"required",
+		},
+	}
+
+	if len(request.Events) > 0 {
+		rules["events"] = []string{webhookEventsRule}
+	}
+
+	result := validator.validate(govalidator.New(govalidator.Options{Data: &request, Rules: rules}))
+	for field, value := range map[string]string{"from": request.From, "to": request.To} {
+		if _, err := time.Parse(time.RFC3339, value); value != "" && err != nil {
+			result.Add(field, "date", fmt.Sprintf("the %s field must be a timestamp in the RFC3339 format e.g. 2022-06-05T14:26:09Z", field))
+		}
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	if !request.FromTime().Before(request.ToTime()) {
+		result.Add("from", "before", "the from timestamp must be before the to timestamp")
+	}
+
+	if validator.retention <= 0 {
+		result.Add("from", "retention", "the events which are sent to webhooks are not stored so they cannot be replayed")
+	} else if oldest := time.Now().UTC().Add(-validator.retention); request.ToTime().Before(oldest) {
+		result.Add("to", "retention", fmt.Sprintf("the to timestamp must be after [%s] because the events are only stored for [%s]", oldest.Format(time.RFC3339), validator.retention))
+	}
+
+	return result
 }
 
 // ValidateStore validates the requests.WebhookStore request
-func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.WebhookStore) url.Values {
+func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.WebhookStore) responses.ValidationErrors {
 	ctx, span := validator.tracer.Start(ctx)
 	defer span.End()
 
@@ -86,25 +136,48 @@ func (validator *WebhookHandlerValidator) ValidateStore(ctx context.Context, use
 				"required",
 				multipleContactPhoneNumberRule,
 			},
+			"payload_template": []string{
+				"max:10000",
+			},
+			"languages": []string{
+				multipleLanguageRule,
+			},
+			"timeout_seconds": []string{
+				"min:1",
+				"max:30",
+			},
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validate(v)
 	if len(result) > 0 {
 		return result
 	}
 
-	for _, address := range request.PhoneNumbers {
+	if err := validator.egressGuard.ValidateURL(ctx, request.URL); err != nil {
+		validator.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("the webhook url [%s] of user [%s] is not allowed", request.URL, userID)))
+		result.Add("url", "public", "The url must be an http or https address on the public internet")
+		return result
+	}
+
+	if request.PayloadTemplate != "" {
+		if err := services.ValidateWebhookTemplate(request.PayloadTemplate); err != nil {
+			result.Add("payload_template", "template", fmt.Sprintf("The payload template is invalid: %s", stacktrace.RootCause(err).Error()))
+			return result
+		}
+	}
+
+	for index, address := range request.PhoneNumbers {
 		_, err := validator.phoneService.Load(ctx, userID, address)
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-			result.Add("from", fmt.Sprintf("The phone number [%s] is not available in your account. Install the android app on your phone to store a webhook with this phone number", address))
+			result.Add(fmt.Sprintf("phone_numbers[%d]", index), "exists", fmt.Sprintf("The phone number [%s] is not available in your account. Install the android app on your phone to store a webhook with this phone number", address))
 		}
 	}
 	return result
 }
 
 // ValidateUpdate validates the requests.WebhookUpdate request
-func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.WebhookUpdate) url.Values {
+func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, userID entities.UserID, request requests.WebhookUpdate) responses.ValidationErrors {
 	ctx, span := validator.tracer.Start(ctx)
 	defer span.End()
 
@@ -132,18 +205,41 @@ func (validator *WebhookHandlerValidator) ValidateUpdate(ctx context.Context, us
 				"required",
 				multipleContactPhoneNumberRule,
 			},
+			"payload_template": []string{
+				"max:10000",
+			},
+			"languages": []string{
+				multipleLanguageRule,
+			},
+			"timeout_seconds": []string{
+				"min:1",
+				"max:30",
+			},
 		},
 	})
 
-	result := v.ValidateStruct()
+	result := validator.validate(v)
 	if len(result) > 0 {
 		return result
 	}
 
-	for _, address := range request.PhoneNumbers {
+	if err := validator.egressGuard.ValidateURL(ctx, request.URL); err != nil {
+		validator.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("the webhook url [%s] of user [%s] is not allowed", request.URL, userID)))
+		result.Add("url", "public", "The url must be an http or https address on the public internet")
+		return result
+	}
+
+	if request.PayloadTemplate != "" {
+		if err := services.ValidateWebhookTemplate(request.PayloadTemplate); err != nil {
+			result.Add("payload_template", "template", fmt.Sprintf("The payload template is invalid: %s", stacktrace.RootCause(err).Error()))
+			return result
+		}
+	}
+
+	for index, address := range request.PhoneNumbers {
 		_, err := validator.phoneService.Load(ctx, userID, address)
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-			result.Add("from", fmt.Sprintf("The phone number [%s] is not available in your account. Install the android app on your phone to store a webhook with this phone number", address))
+			result.Add(fmt.Sprintf("phone_numbers[%d]", index), "exists", fmt.Sprintf("The phone number [%s] is not available in your account. Install the android app on your phone to store a webhook with this phone number", address))
 		}
 	}
 	return result
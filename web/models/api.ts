@@ -50,10 +50,22 @@ export interface EntitiesDiscord {
 export interface EntitiesHeartbeat {
   /** @example true */
   charging: boolean
+  /** @example "doze-mode" */
+  gap_cause?: string
+  /** @example "2022-06-05T13:02:11.520828+03:00" */
+  gap_started_at?: string
   /** @example "32343a19-da5e-4b1b-a767-3298a73703cb" */
   id: string
+  /** @example 900 */
+  interval_seconds: number
+  /** @example "4g" */
+  network_type?: string
   /** @example "+18005550199" */
   owner: string
+  /** @example 3 */
+  signal_bars?: number
+  /** @example -85 */
+  signal_strength?: number
   /** @example "2022-06-05T14:26:01.520828+03:00" */
   timestamp: string
   /** @example "WB7DRDWrJZRGbYrv2CKGkqbzvqdC" */
@@ -73,24 +85,59 @@ export interface EntitiesMessage {
   created_at: string
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   delivered_at: string
+  /**
+   * DeliveryReportPDU is the raw PDU of the last delivery report which the carrier sent for the message encoded in hexadecimal
+   * @example "07914151551512F2060A0A8101234567892260503141650022605031416500"
+   */
+  delivery_report_pdu: string
+  /**
+   * DeliveryStatusCode is the raw TP-Status code of the last delivery report which the carrier sent for the message
+   * @example 0
+   */
+  delivery_status_code: number
+  /**
+   * DuplicateOfID is the ID of the received message which this message duplicates. Duplicate messages are stored but hidden from the message lists.
+   * @example "32343a19-da5e-4b1b-a767-3298a73703cb"
+   */
+  duplicate_of_id: string
   /** @example false */
   encrypted: boolean
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   expired_at: string
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   failed_at: string
+  /**
+   * FailedOverFrom is the phone number of the phone which owned the message before it went offline and the message was moved to its backup phone
+   * @example "+18005550100"
+   */
+  failed_over_from?: string
   /** @example "UNKNOWN" */
   failure_reason: string
   /** @example "32343a19-da5e-4b1b-a767-3298a73703cb" */
   id: string
+  /**
+   * IsStarred is set by the user to mark an important message when triaging a thread
+   * @example false
+   */
+  is_starred: boolean
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   last_attempted_at: string
   /** @example 1 */
   max_send_attempts: number
+  /**
+   * NeedsFollowUp is set by the user to mark a message which still has to be followed up
+   * @example false
+   */
+  needs_follow_up: boolean
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   order_timestamp: string
   /** @example "+18005550199" */
   owner: string
+  /**
+   * PhonePoolID is the ID of the phone pool which chose the phone that sends the message
+   * @example "32343a19-da5e-4b1b-a767-3298a73703cb"
+   */
+  phone_pool_id?: string
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   received_at: string
   /** @example "153554b5-ae44-44a0-8f4f-7bbac5657ad4" */
@@ -139,10 +186,16 @@ export interface EntitiesMessageThread {
   id: string
   /** @example false */
   is_archived: boolean
+  /** @example false */
+  is_unread: boolean
   /** @example "This is a sample message content" */
   last_message_content: string
   /** @example "32343a19-da5e-4b1b-a767-3298a73703ca" */
   last_message_id: string
+  /** @example "mobile-terminated" */
+  last_message_type: string
+  /** @example "2022-06-05T14:26:09.527976+03:00" */
+  last_message_at: string
   /** @example "2022-06-05T14:26:09.527976+03:00" */
   order_timestamp: string
   /** @example "+18005550199" */
@@ -153,15 +206,57 @@ export interface EntitiesMessageThread {
   updated_at: string
   /** @example "WB7DRDWrJZRGbYrv2CKGkqbzvqdC" */
   user_id: string
+  /** @example "32343a19-da5e-4b1b-a767-3298a73703cb" */
+  webhook_id?: string
 }
 
 export interface EntitiesPhone {
+  /**
+   * AppVersion is the version of the app which was installed on the phone when it was last registered
+   * @example "v1.0.0"
+   */
+  app_version?: string
+  /**
+   * BackupPhoneNumber is the phone number of another phone of the user which sends the pending messages of this phone when it goes offline
+   * @example "+18005550100"
+   */
+  backup_phone_number?: string
   /** @example "2022-06-05T14:26:02.302718+03:00" */
   created_at: string
+  /**
+   * DeactivatedAt is the time when the phone was taken out of service. A deactivated phone is not assigned messages and its offline alerts are suppressed.
+   * @example "2022-06-05T14:26:09.527976+03:00"
+   */
+  deactivated_at?: string
+  /**
+   * DuplicateStrategy determines how a received message is matched with the messages which were previously received from the same contact
+   * @example "exact"
+   */
+  duplicate_strategy: string
+  /**
+   * DuplicateWindowSeconds is the duration in seconds after receiving a message during which a duplicate message from the same contact is suppressed. It is disabled when it is 0.
+   * @example 300
+   */
+  duplicate_window_seconds: number
   /** @example "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzd....." */
   fcm_token: string
+  /**
+   * HeartbeatIntervalSeconds is the expected duration in seconds between 2 heartbeats which is sent to the app so that it schedules its heartbeats.
+   * @example 900
+   */
+  heartbeat_interval_seconds: number
+  /**
+   * HeartbeatTimeoutSeconds is the duration in seconds without a heartbeat after which the phone is considered to be offline.
+   * @example 3840
+   */
+  heartbeat_timeout_seconds: number
   /** @example "32343a19-da5e-4b1b-a767-3298a73703cb" */
   id: string
+  /**
+   * LowBalanceThreshold is the credit balance of the SIM below which an alert is sent. The alert is disabled when it is 0.
+   * @example 5
+   */
+  low_balance_threshold: number
   /**
    * MaxSendAttempts determines how many times to retry sending an SMS message
    * @example 2
@@ -169,13 +264,59 @@ export interface EntitiesPhone {
   max_send_attempts: number
   /** MessageExpirationSeconds is the duration in seconds after sending a message when it is considered to be expired. */
   message_expiration_seconds: number
+  /**
+   * MessagesPerHour is the number of messages which the phone sends in an hour before the next messages are delayed to avoid carrier spam blocks. It is not limited when it is 0.
+   * @example 100
+   */
+  messages_per_hour: number
   /** @example 1 */
   messages_per_minute: number
   /** @example "This phone cannot receive calls. Please send an SMS instead." */
   missed_call_auto_reply: string
   /** @example "+18005550199" */
   phone_number: string
+  /**
+   * PushProvider is the transport which is used to notify the phone of pending messages
+   * @example "fcm"
+   */
+  push_provider: string
+  /**
+   * RegisteredAt is the time when the app last registered the phone with its FCM token
+   * @example "2022-06-05T14:26:09.527976+03:00"
+   */
+  registered_at?: string
   sim: EntitiesSIM
+  /**
+   * SIMICCID is the serial number of the SIM card which was last reported in a heartbeat
+   * @example "8901260222780116412"
+   */
+  sim_iccid?: string
+  /**
+   * SIMOperator is the name of the carrier of the SIM card which was last reported in a heartbeat
+   * @example "T-Mobile"
+   */
+  sim_operator?: string
+  /**
+   * SIMSlots are the SIM slots which had a SIM card in the last heartbeat which reported the SIM cards of the phone
+   * @example ["SIM1","SIM2"]
+   */
+  sim_slots?: string[]
+  /**
+   * SIMSwappedAt is the time when a heartbeat reported a different SIM card in the SIM slot of the phone
+   * @example "2022-06-05T14:26:09.527976+03:00"
+   */
+  sim_swapped_at?: string
+  /**
+   * SocketConnectedAt is the time when the app opened the WebSocket connection which it currently holds open to receive
+   * commands without push notifications. It is null when the phone is not connected.
+   * @example "2022-06-05T14:26:09.527976+03:00"
+   */
+  socket_connected_at?: string
+  /**
+   * UnifiedPushEndpoint is the URL of the UnifiedPush distributor e.g. an ntfy topic which notifies the phone when the push provider is unified_push
+   * @example "https://ntfy.sh/upYzMtZGZiNDYx?up=1"
+   */
+  unified_push_endpoint?: string
   /** @example "2022-06-05T14:26:10.303278+03:00" */
   updated_at: string
   /** @example "WB7DRDWrJZRGbYrv2CKGkqbzvqdC" */
@@ -185,6 +326,7 @@ export interface EntitiesPhone {
 export enum EntitiesSIM {
   SIM1 = 'SIM1',
   SIM2 = 'SIM2',
+  SIMDefault = 'DEFAULT',
 }
 
 export enum EntitiesSubscriptionName {
@@ -211,6 +353,16 @@ export interface EntitiesUser {
   created_at: string
   /** @example "name@email.com" */
   email: string
+  /**
+   * HeartbeatAlertCooldownSeconds is the duration in seconds after a phone goes offline during which it is not reported as offline again. It is disabled when it is 0.
+   * @example 3600
+   */
+  heartbeat_alert_cooldown_seconds: number
+  /**
+   * HeartbeatOfflineThreshold is the number of consecutive missed heartbeats after which a phone is offline. The heartbeat timeout of the phone is used when it is 0.
+   * @example 3
+   */
+  heartbeat_offline_threshold: number
   /** @example "WB7DRDWrJZRGbYrv2CKGkqbzvqdC" */
   id: string
   /** @example true */
@@ -246,6 +398,11 @@ export interface EntitiesWebhook {
   phone_numbers: string[]
   /** @example "DGW8NwQp7mxKaSZ72Xq9v67SLqSbWQvckzzmK8D6rvd7NywSEkdMJtuxKyEkYnCY" */
   signing_key: string
+  /**
+   * TimeoutSeconds is the duration in seconds to wait for the response of the webhook before the delivery fails
+   * @example 10
+   */
+  timeout_seconds: number
   /** @example "2022-06-05T14:26:10.303278+03:00" */
   updated_at: string
   /** @example "https://example.com" */
@@ -373,8 +530,38 @@ export interface RequestsMessageThreadUpdate {
 }
 
 export interface RequestsPhoneUpsert {
+  /**
+   * BackupPhoneNumber is the phone number of another phone which sends the pending messages when this phone goes offline. Set it to an empty string to remove the backup phone.
+   * @example "+18005550100"
+   */
+  backup_phone_number?: string
+  /**
+   * DuplicateStrategy determines how a received message is matched with the previous messages from the same contact e.g. exact or normalized
+   * @example "exact"
+   */
+  duplicate_strategy: string
+  /**
+   * DuplicateWindowSeconds is the duration in seconds after receiving a message during which a duplicate message from the same contact is suppressed. Set it to 0 to disable the suppression.
+   * @example 300
+   */
+  duplicate_window_seconds: number
   /** @example "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzd....." */
   fcm_token: string
+  /**
+   * HeartbeatIntervalSeconds is the expected duration in seconds between 2 heartbeats which the app uses to schedule its heartbeats.
+   * @example 900
+   */
+  heartbeat_interval_seconds: number
+  /**
+   * HeartbeatTimeoutSeconds is the duration in seconds without a heartbeat after which the phone is considered to be offline.
+   * @example 3840
+   */
+  heartbeat_timeout_seconds: number
+  /**
+   * LowBalanceThreshold is the credit balance of the SIM below which an alert is sent. Set it to 0 to disable the alert.
+   * @example 5
+   */
+  low_balance_threshold: number
   /**
    * MaxSendAttempts is the number of attempts when sending an SMS message to handle the case where the phone is offline.
    * @example 2
@@ -385,17 +572,32 @@ export interface RequestsPhoneUpsert {
    * @example 12345
    */
   message_expiration_seconds: number
+  /**
+   * MessagesPerHour is the number of messages which the phone sends in an hour before the next messages are delayed. Set it to 0 to remove the limit.
+   * @example 100
+   */
+  messages_per_hour?: number
   /** @example 1 */
   messages_per_minute: number
   /** @example "e.g. This phone cannot receive calls. Please send an SMS instead." */
   missed_call_auto_reply: string
   /** @example "+18005550199" */
   phone_number: string
+  /**
+   * PushProvider is the transport which is used to notify the phone of pending messages which can be "fcm" or "unified_push"
+   * @example "fcm"
+   */
+  push_provider?: string
   /**
    * SIM is the SIM slot of the phone in case the phone has more than 1 SIM slot
    * @example "SIM1"
    */
   sim: string
+  /**
+   * UnifiedPushEndpoint is the URL of the UnifiedPush distributor e.g. an ntfy topic which is required when the push provider is "unified_push". Set it to an empty string to remove the endpoint.
+   * @example "https://ntfy.sh/upYzMtZGZiNDYx?up=1"
+   */
+  unified_push_endpoint?: string
 }
 
 export interface RequestsUserNotificationUpdate {
@@ -410,6 +612,16 @@ export interface RequestsUserNotificationUpdate {
 export interface RequestsUserUpdate {
   /** @example "32343a19-da5e-4b1b-a767-3298a73703cb" */
   active_phone_id: string
+  /**
+   * HeartbeatAlertCooldownSeconds is the duration in seconds after a phone goes offline during which it is not reported as offline again. Set it to 0 to disable the cool-down.
+   * @example 3600
+   */
+  heartbeat_alert_cooldown_seconds?: number
+  /**
+   * HeartbeatOfflineThreshold is the number of consecutive missed heartbeats after which a phone is offline. Set it to 0 to use the heartbeat timeout of the phone.
+   * @example 3
+   */
+  heartbeat_offline_threshold?: number
   /** @example "Europe/Helsinki" */
   timezone: string
 }
@@ -419,6 +631,11 @@ export interface RequestsWebhookStore {
   /** @example ["+18005550100","+18005550100"] */
   phone_numbers: string[]
   signing_key: string
+  /**
+   * TimeoutSeconds is the duration in seconds to wait for the response of the webhook which defaults to 10 seconds
+   * @example 10
+   */
+  timeout_seconds?: number
   url: string
 }
 
@@ -427,6 +644,11 @@ export interface RequestsWebhookUpdate {
   /** @example ["+18005550100","+18005550100"] */
   phone_numbers: string[]
   signing_key: string
+  /**
+   * TimeoutSeconds is the duration in seconds to wait for the response of the webhook which defaults to 10 seconds
+   * @example 10
+   */
+  timeout_seconds?: number
   url: string
 }
 
@@ -506,6 +728,8 @@ export interface ResponsesMessageThreadsResponse {
   data: EntitiesMessageThread[]
   /** @example "item created successfully" */
   message: string
+  /** @example "eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NhIn0" */
+  next_cursor?: string
   /** @example "success" */
   status: string
 }
@@ -514,6 +738,8 @@ export interface ResponsesMessagesResponse {
   data: EntitiesMessage[]
   /** @example "item created successfully" */
   message: string
+  /** @example "eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NhIn0" */
+  next_cursor?: string
   /** @example "success" */
   status: string
 }
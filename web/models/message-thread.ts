@@ -5,7 +5,10 @@ export interface MessageThread {
   id: string
   last_message_content: string
   last_message_id: string
+  last_message_type: string | null
+  last_message_at: string | null
   is_archived: boolean
+  is_unread: boolean
   order_timestamp: string
   owner: string
   updated_at: string
@@ -4,7 +4,9 @@ export interface Message {
   created_at: string
   failure_reason: string
   id: string
+  is_starred: boolean
   last_attempted_at: string | null
+  needs_follow_up: boolean
   order_timestamp: string
   owner: string
   received_at: string | null
@@ -4,6 +4,13 @@ import capitalize from '@/plugins/capitalize'
 
 export class ErrorMessages extends Bag<string> {}
 
+interface ValidationError {
+  field: string
+  rule: string
+  message: string
+  param?: string
+}
+
 const sanitize = (key: string, values: Array<string>): Array<string> => {
   return values.map((value: string) => {
     return capitalize(
@@ -26,15 +33,17 @@ export const getErrorMessages = (error: AxiosError): ErrorMessages => {
   const errors = new ErrorMessages()
   if (
     error === null ||
-    typeof (error.response?.data as any)?.data !== 'object' ||
-    (error.response?.data as any)?.data === null ||
+    !Array.isArray((error.response?.data as any)?.data) ||
     error.response?.status !== 422
   ) {
     return errors
   }
 
-  Object.keys((error.response?.data as any).data).forEach((key: string) => {
-    errors.addMany(key, sanitize(key, (error.response?.data as any).data[key]))
+  // the errors are grouped by the root of the field path e.g. "media_urls[1]" is shown on the "media_urls" input
+  const items: Array<ValidationError> = (error.response?.data as any).data
+  items.forEach((item: ValidationError) => {
+    const key = item.field.split(/[.[]/)[0]
+    errors.addMany(key, sanitize(key, [item.message]))
   })
 
   return errors
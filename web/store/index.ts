@@ -19,6 +19,7 @@ import {
   RequestsWebhookUpdate,
   ResponsesDiscordResponse,
   ResponsesDiscordsResponse,
+  ResponsesMessageResponse,
   ResponsesMessagesResponse,
   ResponsesNoContent,
   ResponsesOkString,
@@ -286,6 +287,12 @@ export type SendMessageRequest = {
   sim: SIM
 }
 
+export type UpdateMessageRequest = {
+  messageId: string
+  is_starred?: boolean
+  needs_follow_up?: boolean
+}
+
 export const actions = {
   async loadThreads(context: ActionContext<State, State>) {
     if (
@@ -369,6 +376,20 @@ export const actions = {
           phone.message_expiration_seconds.toString(),
         ),
         missed_call_auto_reply: phone.missed_call_auto_reply,
+        backup_phone_number: phone.backup_phone_number ?? '',
+        heartbeat_timeout_seconds: parseInt(
+          phone.heartbeat_timeout_seconds.toString(),
+        ),
+        heartbeat_interval_seconds: parseInt(
+          phone.heartbeat_interval_seconds.toString(),
+        ),
+        low_balance_threshold: parseFloat(
+          phone.low_balance_threshold.toString(),
+        ),
+        duplicate_window_seconds: parseInt(
+          phone.duplicate_window_seconds.toString(),
+        ),
+        duplicate_strategy: phone.duplicate_strategy,
         max_send_attempts: parseInt(phone.max_send_attempts.toString()),
         messages_per_minute: parseInt(phone.messages_per_minute.toString()),
       })
@@ -514,6 +535,34 @@ export const actions = {
     })
   },
 
+  updateMessage(
+    context: ActionContext<State, State>,
+    payload: UpdateMessageRequest,
+  ) {
+    return new Promise<EntitiesMessage>((resolve, reject) => {
+      axios
+        .patch<ResponsesMessageResponse>(
+          `/v1/messages/${payload.messageId}`,
+          {
+            is_starred: payload.is_starred,
+            needs_follow_up: payload.needs_follow_up,
+          },
+        )
+        .then((response: AxiosResponse<ResponsesMessageResponse>) => {
+          resolve(response.data.data)
+        })
+        .catch(async (error: AxiosError) => {
+          await context.dispatch('addNotification', {
+            message:
+              (error.response?.data as any)?.message ??
+              'Error while updating message',
+            type: 'error',
+          })
+          reject(getErrorMessages(error))
+        })
+    })
+  },
+
   searchMessages(
     _: ActionContext<State, State>,
     payload: SearchMessagesRequest,